@@ -0,0 +1,66 @@
+// Package main is the entry point for the GophKeeper command-line client.
+//
+// The client loads its configuration from a YAML file (-f), environment variables and global flags,
+// connects to the GophKeeper gRPC server over TLS and executes a single command per invocation.
+// The JWT obtained by login or signup is persisted in a session file and reused by later commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/apetsko/gophkeeper/internal/client"
+	"github.com/apetsko/gophkeeper/pkg/version"
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+)
+
+// requestTimeout bounds the duration of a single command.
+const requestTimeout = 5 * time.Minute
+
+// main runs the client and maps errors to a non-zero exit code.
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		if errors.Is(err, client.ErrUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// run parses the configuration, connects to the server and executes the requested command.
+func run() error {
+	cfg, args, err := client.NewConfig(os.Args[1:])
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 && args[0] == "version" {
+		version.PrintVersion()
+		return nil
+	}
+
+	conn, err := client.Dial(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	defer stop()
+
+	// The agent serves requests until it is interrupted
+	if len(args) == 0 || args[0] != "agent" {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
+	app := client.NewApp(cfg, pb.NewGophKeeperClient(conn), os.Stdin, os.Stdout)
+	return app.Run(ctx, args)
+}
@@ -13,6 +13,8 @@ import (
 
 	"github.com/apetsko/gophkeeper/config"
 	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/internal/jobs"
+	"github.com/apetsko/gophkeeper/internal/kms"
 	grpcsrv "github.com/apetsko/gophkeeper/internal/server/grpc"
 	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
 	httpsrv "github.com/apetsko/gophkeeper/internal/server/http"
@@ -51,9 +53,34 @@ func main() {
 	}
 
 	envelope := crypto.NewEnvelope(dbClient)
-	keyManager := crypto.NewKeyManager(dbClient, cfg.ServerEK)
+	keyWrapper, err := kms.New(cfg)
+	if err != nil {
+		log.Errorf("kms init err %v", err)
+		return
+	}
+	keyManager := crypto.NewKeyManager(dbClient, keyWrapper)
+
+	keyRotation := jobs.NewKeyRotation(ctx, dbClient, keyManager, jobs.DefaultKeyRotationBatch)
+	if err := keyRotation.Resume(ctx); err != nil {
+		log.Errorf("key rotation resume err %v", err)
+		return
+	}
+
+	trashPurger := jobs.NewTrashPurger(ctx, dbClient, s3Client, cfg.TrashRetention, cfg.TrashPurgeInterval)
+	trashPurger.Start()
+
+	reconciler := jobs.NewReconciler(ctx, dbClient, s3Client, cfg.ReconcileInterval, cfg.ReconcileGrace, cfg.ReconcileRepair)
+	reconciler.Start()
+
+	uploadCleaner := jobs.NewUploadCleaner(ctx, dbClient, s3Client, cfg.UploadCleanupInterval)
+	uploadCleaner.Start()
 
 	sa := handlers.NewServerAdmin(dbClient, s3Client, cfg.JWT, envelope, keyManager)
+	sa.KeyRotation = keyRotation
+	sa.Reconciler = reconciler
+	sa.VersionRetention = *cfg.VersionRetention
+	sa.TrashRetention = cfg.TrashRetention
+	sa.UploadTTL = cfg.UploadTTL
 
 	// Start gRPC server
 	if _, err := grpcsrv.RunGRPC(cfg, sa, log); err != nil {
@@ -73,4 +100,9 @@ func main() {
 
 	<-ctx.Done()
 	log.Info("Shutting down servers...")
+	cancel()
+	keyRotation.Wait()
+	trashPurger.Wait()
+	reconciler.Wait()
+	uploadCleaner.Wait()
 }
@@ -0,0 +1,36 @@
+// Package main runs a local stand-in for the HashiCorp Vault Transit secrets engine.
+//
+// It serves the encrypt, decrypt and key rotate endpoints used by the vault KMS backend, with
+// keys kept in memory, so the server can be run against KMS_BACKEND=vault without a real Vault.
+// It is meant for development and tests only: all keys are lost when it stops.
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/apetsko/gophkeeper/internal/kms/kmstest"
+)
+
+// main parses the flags and serves the stand-in until the process is stopped.
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8200", "listen address")
+	token := flag.String("token", "root", "token expected in X-Vault-Token")
+	mount := flag.String("mount", "transit", "transit mount path")
+	flag.Parse()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           kmstest.NewVaultServer(*mount, *token),
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+
+	slog.Info("Vault transit stand-in listening", "addr", *addr, "mount", *mount)
+	if err := srv.ListenAndServe(); err != nil {
+		slog.Error("stand-in stopped", "error", err)
+		os.Exit(1)
+	}
+}
@@ -6,12 +6,14 @@
 package config
 
 import (
+	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/utils"
@@ -29,22 +31,103 @@ type Config struct {
 	GRPCAddress string `env:"GRPC_ADDRESS" yaml:"GRPC_ADDRESS" validate:"required"`
 	// HTTPAddress is the address for the HTTP server to listen on.
 	HTTPAddress string `env:"HTTP_ADDRESS" yaml:"HTTP_ADDRESS" validate:"required"`
-	// StrServerEK is the hex-encoded server encryption key.
-	StrServerEK string `env:"SERVER_ENCRYPTION_KEY" yaml:"SERVER_ENCRYPTION_KEY" validate:"required"`
-	// ServerEK is the decoded server encryption key as a byte slice.
+	// StrServerEK is the hex-encoded server encryption key. It joins the keyring under the
+	// constants.DefaultServerKeyID ID, which is also the ID of master keys wrapped before key IDs existed.
+	StrServerEK string `env:"SERVER_ENCRYPTION_KEY" yaml:"SERVER_ENCRYPTION_KEY"`
+	// StrServerKeys is the keyring of hex-encoded server encryption keys by key ID.
+	StrServerKeys map[string]string `env:"SERVER_ENCRYPTION_KEYS" yaml:"SERVER_ENCRYPTION_KEYS"`
+	// ServerKeyID is the ID of the active key that wraps new master keys.
+	// It may be omitted when the keyring holds a single key.
+	ServerKeyID string `env:"SERVER_ENCRYPTION_KEY_ID" yaml:"SERVER_ENCRYPTION_KEY_ID"`
+	// ServerKeys is the decoded keyring. With a remote KMS backend it is optional and only opens
+	// master keys wrapped before the switch.
+	ServerKeys map[string][]byte
+	// ServerEK is the decoded active server encryption key as a byte slice.
 	ServerEK []byte
+	// AdminToken authorizes administrative RPCs such as server key rotation; empty disables them.
+	AdminToken string `env:"ADMIN_TOKEN" yaml:"ADMIN_TOKEN"`
+	// GatewayToken authenticates the HTTP gateway to the gRPC server, which trusts the client
+	// address in x-forwarded-for only on requests carrying it. A random token is generated when
+	// empty, since the gateway and the gRPC server run in one process.
+	GatewayToken string `env:"GATEWAY_TOKEN" yaml:"GATEWAY_TOKEN"`
+	// VersionRetention is the number of prior versions kept per record for users that have not
+	// chosen their own; DefaultVersionRetention when unset. 0 keeps no prior versions.
+	VersionRetention *int `env:"VERSION_RETENTION" yaml:"VERSION_RETENTION"`
+	// TrashRetention is how long deleted records stay in the trash; DefaultTrashRetention when zero.
+	TrashRetention time.Duration `env:"TRASH_RETENTION" yaml:"TRASH_RETENTION"`
+	// TrashPurgeInterval is how often expired records are purged from the trash;
+	// DefaultTrashPurgeInterval when zero.
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" yaml:"TRASH_PURGE_INTERVAL"`
+	// ReconcileInterval is how often the bucket is compared with the stored records;
+	// DefaultReconcileInterval when zero.
+	ReconcileInterval time.Duration `env:"RECONCILE_INTERVAL" yaml:"RECONCILE_INTERVAL"`
+	// ReconcileGrace is how old an unreferenced object must be to count as an orphan;
+	// DefaultReconcileGrace when zero.
+	ReconcileGrace time.Duration `env:"RECONCILE_GRACE" yaml:"RECONCILE_GRACE"`
+	// ReconcileRepair makes scheduled reconciliations delete orphaned objects instead of only
+	// reporting them.
+	ReconcileRepair bool `env:"RECONCILE_REPAIR" yaml:"RECONCILE_REPAIR"`
+	// UploadTTL is how long a resumable upload waits for its next chunk before it is abandoned;
+	// DefaultUploadTTL when zero.
+	UploadTTL time.Duration `env:"UPLOAD_TTL" yaml:"UPLOAD_TTL"`
+	// UploadCleanupInterval is how often abandoned uploads are removed;
+	// DefaultUploadCleanupInterval when zero.
+	UploadCleanupInterval time.Duration `env:"UPLOAD_CLEANUP_INTERVAL" yaml:"UPLOAD_CLEANUP_INTERVAL"`
 	// JWT holds JWT-related configuration.
 	JWT JWTConfig `yaml:"JWT"`
+	// KMS selects the backend that wraps master keys.
+	KMS KMSConfig `yaml:"KMS"`
 	// S3Config holds S3/MinIO-related configuration.
 	S3Config S3Config `yaml:"S3"`
 	// TLSConfig holds TLS/HTTPS-related configuration.
 	TLSConfig TLSConfig `yaml:"TLS"`
 }
 
+// Default token lifetimes.
+const (
+	// DefaultAccessTokenTTL is the lifetime of access tokens when JWT_ACCESS_TTL is not set.
+	DefaultAccessTokenTTL = 15 * time.Minute
+	// DefaultRefreshTokenTTL is the lifetime of refresh tokens when JWT_REFRESH_TTL is not set.
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Record version and trash retention limits.
+const (
+	// DefaultVersionRetention is the number of prior versions kept per record when VERSION_RETENTION is not set.
+	DefaultVersionRetention = 10
+	// MaxVersionRetention is the largest number of prior versions kept per record.
+	MaxVersionRetention = 100
+	// DefaultTrashRetention is how long deleted records stay in the trash when TRASH_RETENTION is not set.
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	// DefaultTrashPurgeInterval is how often the trash is purged when TRASH_PURGE_INTERVAL is not set.
+	DefaultTrashPurgeInterval = time.Hour
+)
+
+// Object reconciliation defaults.
+const (
+	// DefaultReconcileInterval is how often the bucket is reconciled when RECONCILE_INTERVAL is not set.
+	DefaultReconcileInterval = 24 * time.Hour
+	// DefaultReconcileGrace is the minimum age of an orphaned object when RECONCILE_GRACE is not set.
+	DefaultReconcileGrace = time.Hour
+)
+
+// Resumable upload defaults.
+const (
+	// DefaultUploadTTL is how long a resumable upload waits for its next chunk when UPLOAD_TTL is not set.
+	DefaultUploadTTL = 24 * time.Hour
+	// DefaultUploadCleanupInterval is how often abandoned uploads are removed when
+	// UPLOAD_CLEANUP_INTERVAL is not set.
+	DefaultUploadCleanupInterval = time.Hour
+)
+
 // JWTConfig contains settings for JWT authentication.
 type JWTConfig struct {
 	// Secret is the secret key for signing JWT tokens.
 	Secret string `env:"JWT_SECRET" yaml:"JWT_SECRET" validate:"required"`
+	// AccessTTL is the lifetime of access tokens, DefaultAccessTokenTTL when zero.
+	AccessTTL time.Duration `env:"JWT_ACCESS_TTL" yaml:"JWT_ACCESS_TTL"`
+	// RefreshTTL is how long an unused refresh token stays valid, DefaultRefreshTokenTTL when zero.
+	RefreshTTL time.Duration `env:"JWT_REFRESH_TTL" yaml:"JWT_REFRESH_TTL"`
 }
 
 // S3Config contains settings for S3/MinIO object storage.
@@ -59,6 +142,56 @@ type S3Config struct {
 	Endpoint string `env:"S3_ENDPOINT" yaml:"S3_ENDPOINT" validate:"required"`
 }
 
+// KMS backends.
+const (
+	// KMSBackendLocal wraps master keys with the in-process server key keyring.
+	KMSBackendLocal = "local"
+	// KMSBackendVault wraps master keys with a HashiCorp Vault Transit-compatible service.
+	KMSBackendVault = "vault"
+	// KMSBackendKMIP wraps master keys with an HSM key through a KMIP-style HTTPS endpoint.
+	KMSBackendKMIP = "kmip"
+)
+
+// KMSConfig contains settings for the backend that wraps master keys.
+type KMSConfig struct {
+	// Backend is KMSBackendLocal (the default), KMSBackendVault or KMSBackendKMIP.
+	Backend string `env:"KMS_BACKEND" yaml:"KMS_BACKEND"`
+	// Vault holds the Vault Transit settings.
+	Vault VaultConfig `yaml:"VAULT"`
+	// KMIP holds the KMIP-style HSM settings.
+	KMIP KMIPConfig `yaml:"KMIP"`
+}
+
+// VaultConfig contains settings for a HashiCorp Vault Transit-compatible service.
+type VaultConfig struct {
+	// Address is the Vault base URL, e.g. https://vault:8200.
+	Address string `env:"VAULT_ADDR" yaml:"VAULT_ADDR"`
+	// Token is the Vault token used for the transit requests.
+	Token string `env:"VAULT_TOKEN" yaml:"VAULT_TOKEN"`
+	// Namespace is the optional Vault Enterprise namespace.
+	Namespace string `env:"VAULT_NAMESPACE" yaml:"VAULT_NAMESPACE"`
+	// Mount is the path of the transit secrets engine; "transit" when empty.
+	Mount string `env:"VAULT_TRANSIT_MOUNT" yaml:"VAULT_TRANSIT_MOUNT"`
+	// Key is the name of the transit key.
+	Key string `env:"VAULT_TRANSIT_KEY" yaml:"VAULT_TRANSIT_KEY"`
+	// CAPath is the optional CA certificate used to verify the Vault server.
+	CAPath string `env:"VAULT_CACERT" yaml:"VAULT_CACERT"`
+}
+
+// KMIPConfig contains settings for an HSM reached through a KMIP-style HTTPS endpoint.
+type KMIPConfig struct {
+	// Endpoint is the URL of the KMIP operations endpoint.
+	Endpoint string `env:"KMIP_ENDPOINT" yaml:"KMIP_ENDPOINT"`
+	// KeyID is the unique identifier of the AES key on the HSM.
+	KeyID string `env:"KMIP_KEY_ID" yaml:"KMIP_KEY_ID"`
+	// CertPath is the client certificate for mutual TLS.
+	CertPath string `env:"KMIP_CERT_PATH" yaml:"KMIP_CERT_PATH"`
+	// KeyPath is the client private key for mutual TLS.
+	KeyPath string `env:"KMIP_KEY_PATH" yaml:"KMIP_KEY_PATH"`
+	// CAPath is the optional CA certificate used to verify the endpoint.
+	CAPath string `env:"KMIP_CA_PATH" yaml:"KMIP_CA_PATH"`
+}
+
 // TLSConfig contains settings for enabling HTTPS/TLS.
 type TLSConfig struct {
 	// EnableHTTPS enables HTTPS if set to true.
@@ -95,20 +228,193 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
-	serverKey, errDecode := hex.DecodeString(cfg.StrServerEK)
-	if errDecode != nil {
-		return nil, errDecode
+	if err := cfg.validateKMS(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.JWT.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.setRetentionDefaults(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.setReconcileDefaults(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.setUploadDefaults(); err != nil {
+		return nil, err
 	}
 
-	if len(serverKey) != constants.KeyLength {
-		return nil, errors.New("server encryption key must be 32 bytes")
+	if err := cfg.setGatewayToken(); err != nil {
+		return nil, err
 	}
 
-	cfg.ServerEK = serverKey
+	if err := cfg.loadServerKeys(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
 
+// loadServerKeys decodes the server keyring and selects the active key.
+func (cfg *Config) loadServerKeys() error {
+	keys := make(map[string]string, len(cfg.StrServerKeys)+1)
+	for id, key := range cfg.StrServerKeys {
+		keys[id] = key
+	}
+	if cfg.StrServerEK != "" {
+		if other, ok := keys[constants.DefaultServerKeyID]; ok && other != cfg.StrServerEK {
+			return fmt.Errorf("server encryption key %q is defined twice", constants.DefaultServerKeyID)
+		}
+		keys[constants.DefaultServerKeyID] = cfg.StrServerEK
+	}
+
+	if len(keys) == 0 {
+		if cfg.KMS.Backend != KMSBackendLocal {
+			return nil
+		}
+		return errors.New("server encryption key is required")
+	}
+
+	cfg.ServerKeys = make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		if id == "" {
+			return errors.New("server encryption key ID must not be empty")
+		}
+
+		serverKey, errDecode := hex.DecodeString(key)
+		if errDecode != nil {
+			return fmt.Errorf("server encryption key %q: %w", id, errDecode)
+		}
+
+		if len(serverKey) != constants.KeyLength {
+			return errors.New("server encryption key must be 32 bytes")
+		}
+
+		cfg.ServerKeys[id] = serverKey
+	}
+
+	if cfg.ServerKeyID == "" {
+		if len(cfg.ServerKeys) > 1 {
+			return errors.New("SERVER_ENCRYPTION_KEY_ID is required when several server keys are configured")
+		}
+		for id := range cfg.ServerKeys {
+			cfg.ServerKeyID = id
+		}
+	}
+
+	active, ok := cfg.ServerKeys[cfg.ServerKeyID]
+	if !ok {
+		return fmt.Errorf("active server encryption key %q is not in the keyring", cfg.ServerKeyID)
+	}
+
+	cfg.ServerEK = active
+
+	return nil
+}
+
+// validateKMS checks that the selected KMS backend is known and fully configured.
+func (cfg *Config) validateKMS() error {
+	switch cfg.KMS.Backend {
+	case "":
+		cfg.KMS.Backend = KMSBackendLocal
+	case KMSBackendLocal:
+	case KMSBackendVault:
+		if cfg.KMS.Vault.Address == "" || cfg.KMS.Vault.Token == "" || cfg.KMS.Vault.Key == "" {
+			return errors.New("VAULT_ADDR, VAULT_TOKEN and VAULT_TRANSIT_KEY are required for the vault KMS backend")
+		}
+	case KMSBackendKMIP:
+		if cfg.KMS.KMIP.Endpoint == "" || cfg.KMS.KMIP.KeyID == "" {
+			return errors.New("KMIP_ENDPOINT and KMIP_KEY_ID are required for the kmip KMS backend")
+		}
+	default:
+		return fmt.Errorf("unknown KMS backend %q", cfg.KMS.Backend)
+	}
+
+	return nil
+}
+
+// setDefaults fills in the default token lifetimes and rejects negative ones.
+func (c *JWTConfig) setDefaults() error {
+	if c.AccessTTL < 0 || c.RefreshTTL < 0 {
+		return errors.New("JWT_ACCESS_TTL and JWT_REFRESH_TTL must not be negative")
+	}
+	if c.AccessTTL == 0 {
+		c.AccessTTL = DefaultAccessTokenTTL
+	}
+	if c.RefreshTTL == 0 {
+		c.RefreshTTL = DefaultRefreshTokenTTL
+	}
+	return nil
+}
+
+// setRetentionDefaults fills in the default version and trash retention and checks their bounds.
+func (cfg *Config) setRetentionDefaults() error {
+	if cfg.VersionRetention != nil && (*cfg.VersionRetention < 0 || *cfg.VersionRetention > MaxVersionRetention) {
+		return fmt.Errorf("VERSION_RETENTION must be between 0 and %d", MaxVersionRetention)
+	}
+	if cfg.TrashRetention < 0 || cfg.TrashPurgeInterval < 0 {
+		return errors.New("TRASH_RETENTION and TRASH_PURGE_INTERVAL must not be negative")
+	}
+	if cfg.VersionRetention == nil {
+		retention := DefaultVersionRetention
+		cfg.VersionRetention = &retention
+	}
+	if cfg.TrashRetention == 0 {
+		cfg.TrashRetention = DefaultTrashRetention
+	}
+	if cfg.TrashPurgeInterval == 0 {
+		cfg.TrashPurgeInterval = DefaultTrashPurgeInterval
+	}
+	return nil
+}
+
+// setReconcileDefaults fills in the default reconciliation interval and grace period and
+// rejects negative ones.
+func (cfg *Config) setReconcileDefaults() error {
+	if cfg.ReconcileInterval < 0 || cfg.ReconcileGrace < 0 {
+		return errors.New("RECONCILE_INTERVAL and RECONCILE_GRACE must not be negative")
+	}
+	if cfg.ReconcileInterval == 0 {
+		cfg.ReconcileInterval = DefaultReconcileInterval
+	}
+	if cfg.ReconcileGrace == 0 {
+		cfg.ReconcileGrace = DefaultReconcileGrace
+	}
+	return nil
+}
+
+// setUploadDefaults fills in the default lifetime and cleanup interval of resumable uploads and
+// rejects negative ones.
+func (cfg *Config) setUploadDefaults() error {
+	if cfg.UploadTTL < 0 || cfg.UploadCleanupInterval < 0 {
+		return errors.New("UPLOAD_TTL and UPLOAD_CLEANUP_INTERVAL must not be negative")
+	}
+	if cfg.UploadTTL == 0 {
+		cfg.UploadTTL = DefaultUploadTTL
+	}
+	if cfg.UploadCleanupInterval == 0 {
+		cfg.UploadCleanupInterval = DefaultUploadCleanupInterval
+	}
+	return nil
+}
+
+// setGatewayToken generates a random gateway token when none is configured.
+func (cfg *Config) setGatewayToken() error {
+	if cfg.GatewayToken != "" {
+		return nil
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate gateway token: %w", err)
+	}
+	cfg.GatewayToken = hex.EncodeToString(token)
+	return nil
+}
+
 // readConfigFile loads configuration from the specified YAML file into the Config struct.
 func (cfg *Config) readConfigFile() error {
 	b, err := os.ReadFile(cfg.ConfigFile)
@@ -8,19 +8,52 @@ export const fetchWrapper = {
 };
 
 function request(method) {
-  return (url, body) => {
-    const requestOptions = {
-      method,
-      headers: authHeader(url)
-    };
-    if (body) {
-      requestOptions.headers['Content-Type'] = 'application/json';
-      requestOptions.body = JSON.stringify(body);
+  return async (url, body) => {
+    let response = await fetch(url, requestOptions(method, url, body));
+    if (response.status === 401 && await refreshToken(url)) {
+      response = await fetch(url, requestOptions(method, url, body));
     }
-    return fetch(url, requestOptions).then(handleResponse);
+    return handleResponse(response);
   }
 }
 
+function requestOptions(method, url, body) {
+  const options = {
+    method,
+    headers: authHeader(url)
+  };
+  if (body instanceof FormData) {
+    // The browser sets the multipart Content-Type with its boundary
+    options.body = body;
+  } else if (body) {
+    options.headers['Content-Type'] = 'application/json';
+    options.body = JSON.stringify(body);
+  }
+  return options;
+}
+
+// refreshToken exchanges the refresh token for a new access token once the old one has expired.
+async function refreshToken(url) {
+  const authStore = useAuthStore();
+  const refreshUrl = `${import.meta.env.VITE_API_URL}/v1/token/refresh`;
+  if (!authStore.user?.refreshToken || url === refreshUrl) {
+    return false;
+  }
+
+  const response = await fetch(refreshUrl, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refreshToken: authStore.user.refreshToken })
+  });
+  if (!response.ok) {
+    return false;
+  }
+
+  const tokens = await response.json();
+  authStore.setTokens(tokens.token, tokens.refreshToken);
+  return true;
+}
+
 function authHeader(url) {
   const { user } = useAuthStore();
   const isLoggedIn = !!user?.token;
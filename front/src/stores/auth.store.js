@@ -33,7 +33,16 @@ export const useAuthStore = defineStore('auth', {
         throw new Error(error.response?.data?.message || 'Registration failed');
       }
     },
+    setTokens(token, refreshToken) {
+      this.user = { ...this.user, token, refreshToken };
+
+      localStorage.setItem('user', JSON.stringify(this.user));
+    },
     logout() {
+      if (this.user?.token) {
+        fetchWrapper.post(`${baseUrl}/logout`, {}).catch(() => {});
+      }
+
       this.user = null;
       localStorage.removeItem('user');
       router.push('/login');
@@ -0,0 +1,471 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/pkg/password"
+	"github.com/apetsko/gophkeeper/pkg/sshkey"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// defaultLockAfter is how long the agent stays unlocked without signing anything.
+const defaultLockAfter = 15 * time.Minute
+
+// confirmTag is the tag of SSH key records that the agent asks about before every use,
+// whatever the -confirm flag says.
+const confirmTag = "confirm"
+
+var (
+	// errAgentLocked is returned for signing requests while the agent is locked.
+	errAgentLocked = errors.New("agent is locked: unlock it with ssh-add -X")
+	// errAgentReadOnly is returned for requests that change the keys of the agent.
+	errAgentReadOnly = errors.New("keys are managed in GophKeeper")
+)
+
+// agentKey is an SSH key record served by the agent.
+type agentKey struct {
+	id      int32
+	pub     ssh.PublicKey
+	comment string
+	confirm bool
+	// version is the version of the record the public key was read from.
+	version int32
+}
+
+// vaultAgent serves the SSH key records of the vault over the ssh-agent protocol.
+//
+// Only public keys are kept between requests: a record is viewed to read its public key when it
+// is new or has changed since, and its private key is fetched from the server, and decrypted
+// for zero-knowledge accounts, for the signature it is needed for. After lockAfter
+// without a signature the agent locks itself and forgets the master key of a zero-knowledge
+// account; ssh-add -X unlocks it with the master password, or for accounts encrypted on the
+// server with the passphrase given when the agent started.
+type vaultAgent struct {
+	app *App
+	ctx context.Context
+	// zk marks a zero-knowledge session, whose records are decrypted with mk.
+	zk bool
+	// confirm asks on the terminal of the agent before every signature.
+	confirm bool
+	// lockAfter is the idle time after which the agent locks itself; 0 disables it.
+	lockAfter time.Duration
+	// now returns the current time; tests move it forward.
+	now func() time.Time
+
+	mu sync.Mutex
+	// mk is the master key of a zero-knowledge account while the agent is unlocked.
+	mk []byte
+	// keys caches the public keys of the SSH key records; they are kept while the agent is
+	// locked, so an unlock does not view every record again.
+	keys []agentKey
+	// passphraseHash is the hash of the unlock passphrase of an account encrypted on the server.
+	passphraseHash string
+	locked         bool
+	lastUse        time.Time
+}
+
+// newVaultAgent creates an unlocked agent for sess, asking for the master password of a
+// zero-knowledge account or for the unlock passphrase otherwise.
+func newVaultAgent(ctx context.Context, a *App, sess *Session, confirm bool, lockAfter time.Duration) (*vaultAgent, error) {
+	ag := &vaultAgent{
+		app:       a,
+		ctx:       ctx,
+		zk:        sess.ZeroKnowledge,
+		confirm:   confirm,
+		lockAfter: lockAfter,
+		now:       time.Now,
+	}
+
+	if ag.zk {
+		mk, err := a.masterKey(sess)
+		if err != nil {
+			return nil, err
+		}
+		ag.mk = mk
+	} else {
+		passphrase, err := a.password("", "Unlock passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if ag.passphraseHash, err = password.HashPassword(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	ag.lastUse = ag.now()
+	return ag, nil
+}
+
+// List returns the public keys of the SSH key records, or none while the agent is locked.
+func (ag *vaultAgent) List() ([]*agent.Key, error) {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+
+	if ag.lockIfIdleLocked() {
+		return nil, nil
+	}
+	if err := ag.loadKeys(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*agent.Key, 0, len(ag.keys))
+	for _, k := range ag.keys {
+		keys = append(keys, &agent.Key{Format: k.pub.Type(), Blob: k.pub.Marshal(), Comment: k.comment})
+	}
+	return keys, nil
+}
+
+// Sign signs data with the private key of key.
+func (ag *vaultAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return ag.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags signs data with the private key of key, using the SHA-2 signature algorithms
+// of RSA keys when flags ask for them.
+func (ag *vaultAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+
+	if ag.lockIfIdleLocked() {
+		return nil, errAgentLocked
+	}
+
+	k, err := ag.findKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if (ag.confirm || k.confirm) && !ag.confirmUse(k) {
+		return nil, fmt.Errorf("use of key %s denied", ssh.FingerprintSHA256(k.pub))
+	}
+
+	resp, err := ag.view(k.id)
+	if err != nil {
+		return nil, err
+	}
+	record := resp.GetSshKey()
+	signer, err := sshkey.Signer(record.GetPrivateKey(), record.GetPassphrase())
+	if err != nil {
+		return nil, err
+	}
+
+	var sig *ssh.Signature
+	algorithmSigner, ok := signer.(ssh.AlgorithmSigner)
+	switch {
+	case ok && flags&agent.SignatureFlagRsaSha512 != 0:
+		sig, err = algorithmSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	case ok && flags&agent.SignatureFlagRsaSha256 != 0:
+		sig, err = algorithmSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA256)
+	default:
+		sig, err = signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ag.lastUse = ag.now()
+	return sig, nil
+}
+
+// Lock locks the agent and forgets the master key. The passphrase is not kept: the agent is
+// always unlocked with the master password or the unlock passphrase.
+func (ag *vaultAgent) Lock([]byte) error {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+	ag.lockLocked()
+	return nil
+}
+
+// Unlock unlocks the agent with the master password of a zero-knowledge account or the unlock
+// passphrase given when the agent started.
+func (ag *vaultAgent) Unlock(passphrase []byte) error {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+
+	if !ag.locked {
+		return errors.New("agent is not locked")
+	}
+
+	if ag.zk {
+		sess, err := ag.app.session(ag.ctx)
+		if err != nil {
+			return err
+		}
+		keys, err := crypto.DeriveClientKeys(string(passphrase), *sess.KDF)
+		if err != nil {
+			return err
+		}
+		mk, err := crypto.UnwrapKey(keys.EncryptionKey, sess.WrappedMasterKey)
+		if err != nil {
+			return errors.New("wrong master password")
+		}
+		ag.mk = mk
+	} else if !password.CheckPasswordHash(string(passphrase), ag.passphraseHash) {
+		return errors.New("wrong passphrase")
+	}
+
+	ag.locked = false
+	ag.lastUse = ag.now()
+	return nil
+}
+
+// Add is refused: keys are added by saving SSH key records.
+func (ag *vaultAgent) Add(agent.AddedKey) error {
+	return errAgentReadOnly
+}
+
+// Remove is refused: keys are removed by deleting SSH key records.
+func (ag *vaultAgent) Remove(ssh.PublicKey) error {
+	return errAgentReadOnly
+}
+
+// RemoveAll is refused: keys are removed by deleting SSH key records.
+func (ag *vaultAgent) RemoveAll() error {
+	return errAgentReadOnly
+}
+
+// Signers is refused, since it would hand out every private key at once.
+func (ag *vaultAgent) Signers() ([]ssh.Signer, error) {
+	return nil, errors.New("private keys are not exported")
+}
+
+// Extension reports that no extensions are supported.
+func (ag *vaultAgent) Extension(string, []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
+
+// lockIfIdle locks the agent once it has not signed anything for lockAfter.
+func (ag *vaultAgent) lockIfIdle() {
+	ag.mu.Lock()
+	defer ag.mu.Unlock()
+	ag.lockIfIdleLocked()
+}
+
+// lockIfIdleLocked is lockIfIdle for callers holding mu; it reports whether the agent is locked.
+func (ag *vaultAgent) lockIfIdleLocked() bool {
+	if !ag.locked && ag.lockAfter > 0 && ag.now().Sub(ag.lastUse) >= ag.lockAfter {
+		ag.lockLocked()
+	}
+	return ag.locked
+}
+
+// lockLocked locks the agent; the caller holds mu.
+func (ag *vaultAgent) lockLocked() {
+	ag.locked = true
+	clear(ag.mk)
+	ag.mk = nil
+}
+
+// loadKeys lists the SSH key records, reusing the cached public keys of the records whose version
+// has not changed and viewing the others; the caller holds mu.
+func (ag *vaultAgent) loadKeys() error {
+	sess, err := ag.app.session(ag.ctx)
+	if err != nil {
+		return err
+	}
+	ctx := withToken(ag.ctx, sess.Token)
+
+	cached := make(map[int32]agentKey, len(ag.keys))
+	for _, k := range ag.keys {
+		cached[k.id] = k
+	}
+
+	var keys []agentKey
+	req := &pbrpc.DataListRequest{Types: []pbc.DataType{pbc.DataType_DATA_TYPE_SSH_KEY}, Limit: 1000}
+	for {
+		resp, errList := ag.app.api.DataList(ctx, req)
+		if errList != nil {
+			return errList
+		}
+
+		for _, record := range resp.GetRecords() {
+			k, ok := cached[record.GetId()]
+			if !ok || k.version != record.GetVersion() {
+				var errKey error
+				if k, errKey = ag.publicKey(record); errKey != nil {
+					return errKey
+				}
+			}
+			k.confirm = slices.Contains(record.GetMeta().GetTags(), confirmTag)
+			keys = append(keys, k)
+		}
+
+		if resp.GetNextCursor() == "" {
+			break
+		}
+		req.Cursor = resp.GetNextCursor()
+	}
+
+	ag.keys = keys
+	return nil
+}
+
+// publicKey views the SSH key record of a DataList entry and keeps only its public key.
+func (ag *vaultAgent) publicKey(record *pbmodels.Record) (agentKey, error) {
+	view, err := ag.view(record.GetId())
+	if err != nil {
+		return agentKey{}, err
+	}
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(view.GetSshKey().GetPublicKey()))
+	if err != nil {
+		return agentKey{}, fmt.Errorf("record %d: invalid public key: %w", record.GetId(), err)
+	}
+	if c := view.GetSshKey().GetComment(); c != "" {
+		comment = c
+	} else if comment == "" {
+		comment = record.GetMeta().GetTitle()
+	}
+	return agentKey{id: record.GetId(), pub: pub, comment: comment, version: record.GetVersion()}, nil
+}
+
+// findKey returns the record of key, reloading the records when it is not known yet; the
+// caller holds mu.
+func (ag *vaultAgent) findKey(key ssh.PublicKey) (agentKey, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		for _, k := range ag.keys {
+			if bytes.Equal(k.pub.Marshal(), key.Marshal()) {
+				return k, nil
+			}
+		}
+		if attempt == 0 {
+			if err := ag.loadKeys(); err != nil {
+				return agentKey{}, err
+			}
+		}
+	}
+	return agentKey{}, errors.New("key not found in GophKeeper")
+}
+
+// view fetches the SSH key record id and decrypts it for a zero-knowledge account.
+func (ag *vaultAgent) view(id int32) (*pbrpc.DataViewResponse, error) {
+	sess, err := ag.app.session(ag.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ag.app.api.DataView(withToken(ag.ctx, sess.Token), &pbrpc.DataViewRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetEncrypted() != nil {
+		if err = openResponse(ag.ctx, ag.mk, resp); err != nil {
+			return nil, fmt.Errorf("failed to decrypt record %d: %w", id, err)
+		}
+	}
+	if resp.GetSshKey() == nil {
+		return nil, fmt.Errorf("record %d is not an SSH key", id)
+	}
+	return resp, nil
+}
+
+// confirmUse asks on the terminal of the agent whether k may be used.
+func (ag *vaultAgent) confirmUse(k agentKey) bool {
+	_, _ = fmt.Fprintf(os.Stderr, "Allow use of SSH key %q (%s)? [y/N]: ", k.comment, ssh.FingerprintSHA256(k.pub))
+	line, err := ag.app.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+// defaultAgentSocket returns the path of the agent socket in the runtime directory of the user.
+func defaultAgentSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("gophkeeper-agent-%d.sock", os.Getuid()))
+}
+
+// agent serves the SSH key records over the ssh-agent protocol on a Unix socket until it is
+// interrupted.
+func (a *App) agent(ctx context.Context, args []string) error {
+	fs := newFlagSet("agent")
+	socket := fs.String("socket", "", "path of the agent socket, in the runtime directory by default")
+	confirm := fs.Bool("confirm", false, "ask before every use of a key; keys tagged confirm are always asked about")
+	lockAfter := fs.Duration("lock-after", defaultLockAfter, "lock the agent after this long without use, 0 to never lock")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *lockAfter < 0 {
+		return fmt.Errorf("%w: agent: -lock-after must not be negative", ErrUsage)
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+	ag, err := newVaultAgent(ctx, a, sess, *confirm, *lockAfter)
+	if err != nil {
+		return err
+	}
+
+	path := *socket
+	if path == "" {
+		path = defaultAgentSocket()
+	}
+	// A socket left behind by an agent that was killed would make Listen fail
+	if info, errStat := os.Lstat(path); errStat == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path)
+	}
+
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(path) }()
+	defer func() { _ = ln.Close() }()
+	if err = os.Chmod(path, 0o600); err != nil {
+		return err
+	}
+
+	if err = a.out.message(fmt.Sprintf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;", path)); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = ln.Close()
+				return
+			case <-ticker.C:
+				ag.lockIfIdle()
+			}
+		}
+	}()
+
+	for {
+		conn, errAccept := ln.Accept()
+		if errAccept != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errAccept
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			_ = agent.ServeAgent(ag, conn)
+		}()
+	}
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+)
+
+// writeSSHKey writes a new ed25519 key pair to dir and returns the paths of its private and
+// public keys and the public key.
+func writeSSHKey(t *testing.T, dir, comment string) (string, string, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	privatePath, publicPath := filepath.Join(dir, "id_ed25519"), filepath.Join(dir, "id_ed25519.pub")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(block), 0o600))
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " " + comment + "\n"
+	require.NoError(t, os.WriteFile(publicPath, []byte(authorized), 0o600))
+	return privatePath, publicPath, sshPub
+}
+
+// serveAgent serves ag over an in-memory connection and returns a client for it.
+func serveAgent(t *testing.T, ag *vaultAgent) agent.ExtendedAgent {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { _ = clientConn.Close() })
+	go func() { _ = agent.ServeAgent(ag, serverConn) }()
+	return agent.NewClient(clientConn)
+}
+
+func TestApp_SSHKey(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	dir := t.TempDir()
+	privatePath, publicPath, pub := writeSSHKey(t, dir, "deploy@ci")
+	require.NoError(t, app.Run(ctx, []string{"save", "ssh", "-key", privatePath, "-public", publicPath, "-title", "Deploy"}))
+	key := fake.saved[0].GetSshKey()
+	require.Equal(t, pbc.DataType_DATA_TYPE_SSH_KEY, fake.saved[0].GetType())
+	require.Equal(t, ssh.FingerprintSHA256(pub), key.GetFingerprint())
+	require.Equal(t, "deploy@ci", key.GetComment())
+
+	out.Reset()
+	saved := filepath.Join(dir, "restored")
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "3", "-out", saved}))
+	require.Contains(t, out.String(), ssh.FingerprintSHA256(pub))
+	restored, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	require.Equal(t, key.GetPrivateKey(), string(restored))
+
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "ssh"}), ErrUsage)
+	require.Error(t, app.Run(ctx, []string{"save", "ssh", "-key", publicPath}))
+}
+
+func TestVaultAgent(t *testing.T) {
+	t.Setenv(passwordEnv, "unlock me")
+	app, fake, _ := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	privatePath, _, pub := writeSSHKey(t, t.TempDir(), "deploy@ci")
+	require.NoError(t, app.Run(ctx, []string{"save", "ssh", "-key", privatePath, "-tag", confirmTag}))
+
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	ag, err := newVaultAgent(ctx, app, sess, false, time.Minute)
+	require.NoError(t, err)
+	now := time.Now()
+	ag.now = func() time.Time { return now }
+	client := serveAgent(t, ag)
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, pub.Marshal(), keys[0].Blob)
+	require.Equal(t, "deploy@ci", keys[0].Comment)
+
+	// The public key of an unchanged record is not viewed again
+	_, err = client.List()
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.viewed)
+
+	// The key is tagged confirm, so every use is asked about on the terminal of the agent
+	app.in = bufio.NewReader(strings.NewReader("n\ny\n"))
+	_, err = client.Sign(pub, []byte("challenge"))
+	require.Error(t, err)
+	sig, err := client.Sign(pub, []byte("challenge"))
+	require.NoError(t, err)
+	require.NoError(t, pub.Verify([]byte("challenge"), sig))
+
+	require.Error(t, client.Add(agent.AddedKey{}))
+
+	require.NoError(t, client.Lock([]byte("anything")))
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	require.Error(t, client.Unlock([]byte("wrong")))
+	require.NoError(t, client.Unlock([]byte("unlock me")))
+
+	// Idle for longer than lockAfter locks the agent
+	now = now.Add(2 * time.Minute)
+	_, err = client.Sign(pub, []byte("challenge"))
+	require.Error(t, err)
+}
+
+func TestVaultAgent_ReplacedKey(t *testing.T) {
+	t.Setenv(passwordEnv, "unlock me")
+	app, _, _ := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	oldPath, _, oldPub := writeSSHKey(t, t.TempDir(), "old@ci")
+	require.NoError(t, app.Run(ctx, []string{"save", "ssh", "-key", oldPath}))
+
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	ag, err := newVaultAgent(ctx, app, sess, false, 0)
+	require.NoError(t, err)
+	client := serveAgent(t, ag)
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, oldPub.Marshal(), keys[0].Blob)
+
+	// The fake server replaces the key of record 3 with the same updated_at, as an update within
+	// the same minute would; the new version still makes the agent read the new public key
+	newPath, _, newPub := writeSSHKey(t, t.TempDir(), "new@ci")
+	require.NoError(t, app.Run(ctx, []string{"save", "ssh", "-key", newPath}))
+
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, newPub.Marshal(), keys[0].Blob)
+	sig, err := client.Sign(newPub, []byte("challenge"))
+	require.NoError(t, err)
+	require.NoError(t, newPub.Verify([]byte("challenge"), sig))
+}
+
+func TestVaultAgent_ZeroKnowledge(t *testing.T) {
+	t.Setenv(passwordEnv, "correct horse")
+	app, fake, _ := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"signup", "-u", "bob", "-zero-knowledge"}))
+
+	privatePath, _, pub := writeSSHKey(t, t.TempDir(), "bob@laptop")
+	require.NoError(t, app.Run(ctx, []string{"save", "ssh", "-key", privatePath}))
+	require.Nil(t, fake.saved[0].GetSshKey(), "plaintext must not be sent")
+
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	ag, err := newVaultAgent(ctx, app, sess, false, 0)
+	require.NoError(t, err)
+	client := serveAgent(t, ag)
+
+	sig, err := client.Sign(pub, []byte("challenge"))
+	require.NoError(t, err)
+	require.NoError(t, pub.Verify([]byte("challenge"), sig))
+
+	require.NoError(t, client.Lock(nil))
+	require.Nil(t, ag.mk, "locking must forget the master key")
+	require.Error(t, client.Unlock([]byte("wrong")))
+	require.NoError(t, client.Unlock([]byte("correct horse")))
+	_, err = client.Sign(pub, []byte("challenge"))
+	require.NoError(t, err)
+}
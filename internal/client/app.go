@@ -0,0 +1,192 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// passwordEnv is the environment variable consulted before prompting for a password.
+const passwordEnv = "GOPHKEEPER_PASSWORD"
+
+// refreshMargin is how long before its expiry the access token is refreshed.
+const refreshMargin = 30 * time.Second
+
+// command describes a single client sub-command.
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+// App executes client commands against a GophKeeper server.
+type App struct {
+	cfg      *Config
+	api      pb.GophKeeperClient
+	sessions *SessionStore
+	in       *bufio.Reader
+	w        io.Writer
+	out      *printer
+	commands []command
+}
+
+// NewApp creates an App that talks to the server through api, reads prompts from in
+// and writes results to out.
+func NewApp(cfg *Config, api pb.GophKeeperClient, in io.Reader, out io.Writer) *App {
+	a := &App{
+		cfg:      cfg,
+		api:      api,
+		sessions: NewSessionStore(cfg.SessionFile),
+		in:       bufio.NewReader(in),
+		w:        out,
+		out:      newPrinter(out, cfg.Output),
+	}
+
+	a.commands = []command{
+		{name: "ping", usage: "check that the server is reachable", run: a.ping},
+		{name: "signup", usage: "-u <username> [-p <password>] [-zero-knowledge]  create an account and log in", run: a.signup},
+		{name: "login", usage: "-u <username> [-p <password>] [-code <code>]  log in and store the session", run: a.login},
+		{name: "logout", usage: "forget the stored session", run: a.logout},
+		{name: "passwd", usage: "[-p <password>] [-new <password>]  change the account password", run: a.passwd},
+		{name: "totp", usage: "enroll|confirm|disable [-code <code>] [-p <password>]  manage two-factor authentication", run: a.totp},
+		{name: "sessions", usage: "[list]|revoke -id <id>|revoke-others  show or end logins on other devices", run: a.sessionsCmd},
+		{name: "list", usage: "[-type <type>] [-tag <tag>] [-favorites] [-folder <id> [-subfolders]] [-sort <order>] [-limit <n>] [-cursor <cursor>]  list stored records", run: a.list},
+		{name: "search", usage: "[-type <type>] [-limit <n>] <query>  find records by their metadata", run: a.search},
+		{name: "view", usage: "-id <id> [-out <path>]  show a record, saving files and private keys to -out", run: a.view},
+		{name: "save", usage: "card|creds|note|otp|ssh|file [flags]  store a new record", run: a.save},
+		{name: "otp", usage: "-id <id>  show the current one-time code of an OTP record", run: a.otp},
+		{name: "agent", usage: "[-socket <path>] [-confirm] [-lock-after <duration>]  serve SSH keys to ssh over the ssh-agent protocol", run: a.agent},
+		{name: "upload", usage: "-path <file> [meta flags]  stream a large file to a new record", run: a.upload},
+		{name: "download", usage: "-id <id> -out <path>  stream a stored file to -out", run: a.download},
+		{name: "update", usage: "-id <id> card|creds|note|otp|ssh|file [flags]  replace the contents of a record", run: a.update},
+		{name: "versions", usage: "[list]|view|restore -id <id> [-v <version>] [-out <path>]|retention -n <count>  browse prior versions", run: a.versions},
+		{name: "delete", usage: "-id <id>  move a record to the trash", run: a.delete},
+		{name: "folders", usage: "[list]|create -name <name> [-parent <id>]|rename -id <id> -name <name>|move -id <id> -parent <id>|delete -id <id>  organise records in folders", run: a.folders},
+		{name: "move", usage: "-folder <id> <record id>...  move records into a folder, or out of folders with -folder 0", run: a.move},
+		{name: "trash", usage: "[list]|restore -id <id>|empty  show, restore or permanently delete deleted records", run: a.trash},
+	}
+
+	return a
+}
+
+// Run executes the command named by args[0] with the remaining arguments.
+func (a *App) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage()
+		return nil
+	}
+
+	for _, c := range a.commands {
+		if c.name == args[0] {
+			return c.run(ctx, args[1:])
+		}
+	}
+
+	a.usage()
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+}
+
+// usage prints the list of available commands.
+func (a *App) usage() {
+	var b strings.Builder
+	b.WriteString("Usage: gophkeeper [-f config.yaml] [-addr host:port] [-json] [-insecure] [-ca cert] <command> [flags]\n\n")
+	b.WriteString("Commands:\n")
+	for _, c := range a.commands {
+		fmt.Fprintf(&b, "  %-8s %s\n", c.name, c.usage)
+	}
+	b.WriteString("  version  print the client version\n")
+	_, _ = io.WriteString(a.w, b.String())
+}
+
+// authContext loads the stored session and returns ctx carrying its JWT.
+func (a *App) authContext(ctx context.Context) (context.Context, error) {
+	sess, err := a.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return withToken(ctx, sess.Token), nil
+}
+
+// session loads the stored session, refreshing its access token shortly before it expires.
+//
+// A refresh token the server no longer accepts ends the session: it is removed, and
+// ErrNotLoggedIn is returned.
+func (a *App) session(ctx context.Context) (*Session, error) {
+	sess, err := a.sessions.Load()
+	if err != nil {
+		return nil, err
+	}
+	if sess.RefreshToken == "" || time.Until(sess.ExpiresAt) > refreshMargin {
+		return sess, nil
+	}
+
+	resp, err := a.api.Refresh(ctx, &pbrpcu.RefreshRequest{RefreshToken: sess.RefreshToken})
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			_ = a.sessions.Remove()
+			return nil, fmt.Errorf("%w (%s)", ErrNotLoggedIn, status.Convert(err).Message())
+		}
+		return nil, err
+	}
+
+	sess.setTokens(resp.GetToken(), resp.GetRefreshToken(), resp.GetExpiresIn())
+	if err := a.sessions.Save(sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// password returns the password from the flag value, the GOPHKEEPER_PASSWORD variable
+// or, failing both, a line read from the input.
+func (a *App) password(fromFlag, prompt string) (string, error) {
+	if fromFlag != "" {
+		return fromFlag, nil
+	}
+	if p := os.Getenv(passwordEnv); p != "" {
+		return p, nil
+	}
+
+	return a.prompt(prompt)
+}
+
+// prompt prints prompt to stderr and reads a non-empty line, such as a password, from the input.
+func (a *App) prompt(prompt string) (string, error) {
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	line, err := a.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	p := strings.TrimRight(line, "\r\n")
+	if p == "" {
+		return "", fmt.Errorf("%s is required", strings.TrimSuffix(prompt, ": "))
+	}
+	return p, nil
+}
+
+// newFlagSet creates a flag set for a sub-command that reports errors instead of exiting.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags parses args into fs, wrapping failures in ErrUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrUsage, fs.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,1040 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/pkg/totp"
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+const (
+	testToken        = "test-token"
+	testRefreshToken = "test-refresh-token"
+	testTOTPCode     = "123456"
+)
+
+// fakeServer is an in-memory GophKeeper server that checks the JWT on data calls.
+type fakeServer struct {
+	pb.UnimplementedGophKeeperServer
+	saved   []*pbrpc.DataSaveRequest
+	updated []*pbrpc.DataUpdateRequest
+	// listed is the last DataList request; a limit of 1 pages through the records.
+	listed *pbrpc.DataListRequest
+	// searched is the last Search request.
+	searched *pbrpc.SearchRequest
+	// restored lists the versions passed to RestoreRecordVersion; retention is the last one set.
+	restored  []int32
+	retention int32
+	// trashed lists the IDs of deleted records that are still in the trash.
+	trashed []int32
+	// folders holds the folders created through CreateFolder; moved is the last MoveRecords request.
+	folders []*pbrpc.Folder
+	moved   *pbrpc.MoveRecordsRequest
+	// uploaded holds the file received by UploadFile; DownloadFile returns it.
+	uploaded     []byte
+	uploadedName string
+	// zk is the zero-knowledge account registered through Signup, if any.
+	zk *pbrpcu.SignupRequest
+	// totpEnabled makes Login ask for the second factor.
+	totpEnabled bool
+	// refreshToken is the refresh token accepted by Refresh; refreshed counts the exchanges.
+	refreshToken string
+	refreshed    int
+	loggedOut    bool
+	// viewed counts the DataView requests.
+	viewed int
+}
+
+func (f *fakeServer) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("jwt"); len(v) == 0 || v[0] != testToken {
+		return status.Error(codes.Unauthenticated, "invalid jwt")
+	}
+	return nil
+}
+
+func (f *fakeServer) Ping(context.Context, *pbrpc.PingRequest) (*pbrpc.PingResponse, error) {
+	return &pbrpc.PingResponse{Message: "pong"}, nil
+}
+
+func (f *fakeServer) Signup(_ context.Context, in *pbrpcu.SignupRequest) (*pbrpcu.SignupResponse, error) {
+	if in.GetZeroKnowledge() {
+		f.zk = in
+	}
+	return &pbrpcu.SignupResponse{Id: 7, Username: in.GetUsername(), Token: testToken, ZeroKnowledge: in.GetZeroKnowledge()}, nil
+}
+
+func (f *fakeServer) PreLogin(_ context.Context, in *pbrpcu.PreLoginRequest) (*pbrpcu.PreLoginResponse, error) {
+	if f.zk == nil || f.zk.GetUsername() != in.GetUsername() {
+		return &pbrpcu.PreLoginResponse{}, nil
+	}
+	return &pbrpcu.PreLoginResponse{ZeroKnowledge: true, Kdf: f.zk.GetKdf()}, nil
+}
+
+func (f *fakeServer) Login(_ context.Context, in *pbrpcu.LoginRequest) (*pbrpcu.LoginResponse, error) {
+	if f.zk != nil && f.zk.GetUsername() == in.GetUsername() {
+		if in.GetPassword() != f.zk.GetPassword() {
+			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+		}
+		return &pbrpcu.LoginResponse{
+			Id: 7, Username: in.GetUsername(), Token: testToken,
+			ZeroKnowledge: true, Kdf: f.zk.GetKdf(), WrappedMasterKey: f.zk.GetWrappedMasterKey(),
+		}, nil
+	}
+	if in.GetPassword() != "password123" {
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+	if f.totpEnabled {
+		return &pbrpcu.LoginResponse{Id: 7, Username: in.GetUsername(), TwoFactorRequired: true, TwoFactorToken: "pending"}, nil
+	}
+	f.refreshToken = testRefreshToken
+	return &pbrpcu.LoginResponse{
+		Id: 7, Username: in.GetUsername(), Token: testToken, RefreshToken: f.refreshToken, ExpiresIn: 900,
+	}, nil
+}
+
+func (f *fakeServer) LoginTOTP(_ context.Context, in *pbrpcu.LoginTOTPRequest) (*pbrpcu.LoginResponse, error) {
+	if in.GetTwoFactorToken() != "pending" || in.GetCode() != testTOTPCode {
+		return nil, status.Error(codes.Unauthenticated, "invalid code")
+	}
+	return &pbrpcu.LoginResponse{Id: 7, Username: "alice", Token: testToken}, nil
+}
+
+func (f *fakeServer) Refresh(_ context.Context, in *pbrpcu.RefreshRequest) (*pbrpcu.RefreshResponse, error) {
+	if f.refreshToken == "" || in.GetRefreshToken() != f.refreshToken {
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+	f.refreshed++
+	f.refreshToken = fmt.Sprintf("%s-%d", testRefreshToken, f.refreshed)
+	return &pbrpcu.RefreshResponse{Token: testToken, RefreshToken: f.refreshToken, ExpiresIn: 900}, nil
+}
+
+func (f *fakeServer) Logout(ctx context.Context, _ *pbrpcu.LogoutRequest) (*pbrpcu.LogoutResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.refreshToken, f.loggedOut = "", true
+	return &pbrpcu.LogoutResponse{Message: "logged out"}, nil
+}
+
+func (f *fakeServer) ListSessions(ctx context.Context, _ *pbrpcu.ListSessionsRequest) (*pbrpcu.ListSessionsResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &pbrpcu.ListSessionsResponse{Sessions: []*pbrpcu.Session{
+		{Id: 1, DeviceName: md.Get("device-name")[0], ClientVersion: md.Get("client-version")[0], Current: true},
+		{Id: 2, DeviceName: "phone", Ip: "10.0.0.2", LastSeenAt: "01.01.2025 10:00"},
+	}}, nil
+}
+
+func (f *fakeServer) RevokeSession(ctx context.Context, in *pbrpcu.RevokeSessionRequest) (*pbrpcu.RevokeSessionResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if in.GetSessionId() != 2 {
+		return nil, status.Error(codes.NotFound, "session not found")
+	}
+	return &pbrpcu.RevokeSessionResponse{Message: "session revoked"}, nil
+}
+
+func (f *fakeServer) RevokeAllOtherSessions(
+	ctx context.Context,
+	_ *pbrpcu.RevokeAllOtherSessionsRequest,
+) (*pbrpcu.RevokeAllOtherSessionsResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &pbrpcu.RevokeAllOtherSessionsResponse{Revoked: 1, Message: "revoked 1 session"}, nil
+}
+
+func (f *fakeServer) EnrollTOTP(ctx context.Context, _ *pbrpcu.EnrollTOTPRequest) (*pbrpcu.EnrollTOTPResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &pbrpcu.EnrollTOTPResponse{OtpauthUri: "otpauth://totp/GophKeeper:alice?secret=ABC", Secret: "ABC"}, nil
+}
+
+func (f *fakeServer) ConfirmTOTP(ctx context.Context, in *pbrpcu.ConfirmTOTPRequest) (*pbrpcu.ConfirmTOTPResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if in.GetCode() != testTOTPCode {
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	}
+	f.totpEnabled = true
+	return &pbrpcu.ConfirmTOTPResponse{RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"}}, nil
+}
+
+func (f *fakeServer) DisableTOTP(ctx context.Context, in *pbrpcu.DisableTOTPRequest) (*pbrpcu.DisableTOTPResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if in.GetPassword() != "password123" || in.GetCode() != testTOTPCode {
+		return nil, status.Error(codes.Unauthenticated, "invalid password or code")
+	}
+	f.totpEnabled = false
+	return &pbrpcu.DisableTOTPResponse{Message: "disabled"}, nil
+}
+
+func (f *fakeServer) ChangePassword(ctx context.Context, in *pbrpcu.ChangePasswordRequest) (*pbrpcu.ChangePasswordResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if f.zk == nil || in.GetOldPassword() != f.zk.GetPassword() {
+		return nil, status.Error(codes.Unauthenticated, "invalid password")
+	}
+	f.zk.Password, f.zk.Kdf, f.zk.WrappedMasterKey = in.GetNewPassword(), in.GetKdf(), in.GetWrappedMasterKey()
+	return &pbrpcu.ChangePasswordResponse{Message: "password changed"}, nil
+}
+
+func (f *fakeServer) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (*pbrpc.DataSaveResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.saved = append(f.saved, in)
+	return &pbrpc.DataSaveResponse{Message: "saved"}, nil
+}
+
+func (f *fakeServer) DataUpdate(ctx context.Context, in *pbrpc.DataUpdateRequest) (*pbrpc.DataUpdateResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.updated = append(f.updated, in)
+	return &pbrpc.DataUpdateResponse{Message: "updated"}, nil
+}
+
+func (f *fakeServer) DataList(ctx context.Context, in *pbrpc.DataListRequest) (*pbrpc.DataListResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.listed = in
+	if slices.Contains(in.GetTypes(), pbc.DataType_DATA_TYPE_SSH_KEY) && len(f.saved) > 0 {
+		last := f.saved[len(f.saved)-1]
+		return &pbrpc.DataListResponse{Records: []*pbmodels.Record{{
+			Id: 3, Type: "ssh_key", Meta: last.GetMeta(), UpdatedAt: "03.01.2025 10:00", Version: int32(len(f.saved)),
+		}}, Count: 1}, nil
+	}
+	records := []*pbmodels.Record{
+		{Id: 1, Type: "credentials", Meta: &pbmodels.Meta{Content: "vpn"}, CreatedAt: "01.01.2025 10:00"},
+		{Id: 2, Type: "bank_card", Meta: &pbmodels.Meta{Content: "visa"}, CreatedAt: "02.01.2025 10:00"},
+	}
+	if in.GetLimit() == 1 {
+		if in.GetCursor() == "" {
+			return &pbrpc.DataListResponse{Records: records[:1], Count: 2, NextCursor: "c1"}, nil
+		}
+		return &pbrpc.DataListResponse{Records: records[1:], Count: 2}, nil
+	}
+	return &pbrpc.DataListResponse{Records: records, Count: 2}, nil
+}
+
+func (f *fakeServer) Search(ctx context.Context, in *pbrpc.SearchRequest) (*pbrpc.SearchResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.searched = in
+	return &pbrpc.SearchResponse{Results: []*pbrpc.SearchResult{{
+		Record: &pbmodels.Record{Id: 1, Type: "credentials", Meta: &pbmodels.Meta{Content: "рабочий vpn"}},
+		Score:  0.8,
+		Highlights: []*pbrpc.SearchHighlight{{
+			Field:  "content",
+			Value:  "рабочий vpn",
+			Ranges: []*pbrpc.SearchRange{{Start: 0, End: 7}, {Start: 8, End: 11}},
+		}},
+	}}}, nil
+}
+
+func (f *fakeServer) UploadFile(stream grpc.ClientStreamingServer[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse]) error {
+	if err := f.authorize(stream.Context()); err != nil {
+		return err
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	f.uploadedName = first.GetHeader().GetName()
+	f.uploaded = nil
+	for {
+		msg, errRecv := stream.Recv()
+		if errors.Is(errRecv, io.EOF) {
+			break
+		}
+		if errRecv != nil {
+			return errRecv
+		}
+		f.uploaded = append(f.uploaded, msg.GetChunk()...)
+	}
+	return stream.SendAndClose(&pbrpc.UploadFileResponse{Id: 9, Size: int64(len(f.uploaded)), Message: "uploaded"})
+}
+
+func (f *fakeServer) DownloadFile(in *pbrpc.DownloadFileRequest, stream grpc.ServerStreamingServer[pbrpc.DownloadFileResponse]) error {
+	if err := f.authorize(stream.Context()); err != nil {
+		return err
+	}
+	if in.GetId() != 9 {
+		return status.Error(codes.NotFound, "not found")
+	}
+	if err := stream.Send(&pbrpc.DownloadFileResponse{Payload: &pbrpc.DownloadFileResponse_Header{
+		Header: &pbrpc.DownloadFileHeader{Name: f.uploadedName, Size: int64(len(f.uploaded))},
+	}}); err != nil {
+		return err
+	}
+	for data := f.uploaded; len(data) > 0; {
+		n := min(len(data), 1000)
+		if err := stream.Send(&pbrpc.DownloadFileResponse{Payload: &pbrpc.DownloadFileResponse_Chunk{Chunk: data[:n]}}); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func (f *fakeServer) DataView(ctx context.Context, in *pbrpc.DataViewRequest) (*pbrpc.DataViewResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.viewed++
+	if in.GetId() == 3 && len(f.saved) > 0 {
+		last := f.saved[len(f.saved)-1]
+		if last.GetSshKey() != nil {
+			return &pbrpc.DataViewResponse{
+				Type: last.GetType(),
+				Meta: last.GetMeta(),
+				Data: &pbrpc.DataViewResponse_SshKey{SshKey: last.GetSshKey()},
+			}, nil
+		}
+		return &pbrpc.DataViewResponse{
+			Type: last.GetType(),
+			Meta: last.GetMeta(),
+			Data: &pbrpc.DataViewResponse_Encrypted{Encrypted: last.GetEncrypted()},
+		}, nil
+	}
+	if in.GetId() == 2 {
+		return &pbrpc.DataViewResponse{
+			Type: pbc.DataType_DATA_TYPE_BINARY_DATA,
+			Meta: &pbmodels.Meta{Content: "doc"},
+			Data: &pbrpc.DataViewResponse_BinaryData{BinaryData: &pbmodels.File{Name: "a.txt", Type: "text/plain", Size: 5, Data: []byte("hello")}},
+		}, nil
+	}
+	return &pbrpc.DataViewResponse{
+		Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+		Meta: &pbmodels.Meta{Content: "vpn"},
+		Data: &pbrpc.DataViewResponse_Credentials{Credentials: &pbmodels.Credentials{Login: "alice", Password: "s3cret"}},
+	}, nil
+}
+
+func (f *fakeServer) GenerateOTP(ctx context.Context, in *pbrpc.GenerateOTPRequest) (*pbrpc.GenerateOTPResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &pbrpc.GenerateOTPResponse{Code: "123456", NextCode: "654321", ExpiresIn: 12, Period: 30}, nil
+}
+
+func (f *fakeServer) ListRecordVersions(
+	ctx context.Context,
+	in *pbrpc.ListRecordVersionsRequest,
+) (*pbrpc.ListRecordVersionsResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &pbrpc.ListRecordVersionsResponse{
+		CurrentVersion: 3,
+		Retention:      f.retention,
+		Versions: []*pbrpc.RecordVersion{
+			{Version: 2, Meta: &pbmodels.Meta{Content: "vpn"}, CreatedAt: "01.01.2025 10:00", ArchivedAt: "02.01.2025 10:00"},
+		},
+	}, nil
+}
+
+func (f *fakeServer) ViewRecordVersion(ctx context.Context, in *pbrpc.ViewRecordVersionRequest) (*pbrpc.DataViewResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if in.GetVersion() != 2 {
+		return nil, status.Error(codes.NotFound, "version not found")
+	}
+	return &pbrpc.DataViewResponse{
+		Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+		Meta: &pbmodels.Meta{Content: "vpn"},
+		Data: &pbrpc.DataViewResponse_Credentials{Credentials: &pbmodels.Credentials{Login: "alice", Password: "old-secret"}},
+	}, nil
+}
+
+func (f *fakeServer) RestoreRecordVersion(
+	ctx context.Context,
+	in *pbrpc.RestoreRecordVersionRequest,
+) (*pbrpc.RestoreRecordVersionResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.restored = append(f.restored, in.GetVersion())
+	return &pbrpc.RestoreRecordVersionResponse{Message: "restored", CurrentVersion: 4}, nil
+}
+
+func (f *fakeServer) SetVersionRetention(
+	ctx context.Context,
+	in *pbrpc.SetVersionRetentionRequest,
+) (*pbrpc.SetVersionRetentionResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.retention = in.GetRetention()
+	return &pbrpc.SetVersionRetentionResponse{Message: "retention set", Retention: in.GetRetention()}, nil
+}
+
+func (f *fakeServer) DataDelete(ctx context.Context, in *pbrpc.DataDeleteRequest) (*pbrpc.DataDeleteResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.trashed = append(f.trashed, in.GetId())
+	return &pbrpc.DataDeleteResponse{Message: "ok"}, nil
+}
+
+func (f *fakeServer) ListTrash(ctx context.Context, _ *pbrpc.ListTrashRequest) (*pbrpc.ListTrashResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	resp := &pbrpc.ListTrashResponse{}
+	for _, id := range f.trashed {
+		resp.Records = append(resp.Records, &pbrpc.TrashedRecord{
+			Record:    &pbmodels.Record{Id: id, Type: "credentials", Meta: &pbmodels.Meta{Content: "vpn"}},
+			DeletedAt: "01.06.2025 10:00",
+			PurgeAt:   "01.07.2025 10:00",
+		})
+	}
+	resp.Count = int32(len(resp.Records))
+	return resp, nil
+}
+
+func (f *fakeServer) RestoreFromTrash(
+	ctx context.Context,
+	in *pbrpc.RestoreFromTrashRequest,
+) (*pbrpc.RestoreFromTrashResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	for i, id := range f.trashed {
+		if id == in.GetId() {
+			f.trashed = append(f.trashed[:i], f.trashed[i+1:]...)
+			return &pbrpc.RestoreFromTrashResponse{Message: "restored"}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "not in trash")
+}
+
+func (f *fakeServer) EmptyTrash(ctx context.Context, _ *pbrpc.EmptyTrashRequest) (*pbrpc.EmptyTrashResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	purged := len(f.trashed)
+	f.trashed = nil
+	return &pbrpc.EmptyTrashResponse{Message: fmt.Sprintf("purged %d", purged), Purged: int32(purged)}, nil
+}
+
+func (f *fakeServer) CreateFolder(ctx context.Context, in *pbrpc.CreateFolderRequest) (*pbrpc.CreateFolderResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	folder := &pbrpc.Folder{
+		Id:        int32(len(f.folders) + 1),
+		ParentId:  in.GetParentId(),
+		Name:      in.GetName(),
+		Encrypted: in.GetEncrypted(),
+		UpdatedAt: "01.06.2025 10:00",
+	}
+	f.folders = append(f.folders, folder)
+	return &pbrpc.CreateFolderResponse{Folder: folder}, nil
+}
+
+func (f *fakeServer) ListFolders(ctx context.Context, _ *pbrpc.ListFoldersRequest) (*pbrpc.ListFoldersResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	resp := &pbrpc.ListFoldersResponse{}
+	for _, folder := range f.folders {
+		resp.Folders = append(resp.Folders, proto.Clone(folder).(*pbrpc.Folder))
+	}
+	return resp, nil
+}
+
+func (f *fakeServer) MoveFolder(ctx context.Context, in *pbrpc.MoveFolderRequest) (*pbrpc.MoveFolderResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if in.GetId() == in.GetParentId() {
+		return nil, status.Error(codes.InvalidArgument, "cycle")
+	}
+	f.folders[in.GetId()-1].ParentId = in.GetParentId()
+	return &pbrpc.MoveFolderResponse{Message: "moved"}, nil
+}
+
+func (f *fakeServer) MoveRecords(ctx context.Context, in *pbrpc.MoveRecordsRequest) (*pbrpc.MoveRecordsResponse, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	f.moved = in
+	return &pbrpc.MoveRecordsResponse{Message: fmt.Sprintf("moved %d", len(in.GetIds()))}, nil
+}
+
+func newTestApp(t *testing.T, output string) (*App, *fakeServer, *bytes.Buffer) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	fake := &fakeServer{}
+	pb.RegisterGophKeeperServer(srv, fake)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(clientInfoInterceptor("test-host", "1.2.3")),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	cfg := &Config{
+		GRPCAddress: "bufnet",
+		SessionFile: filepath.Join(t.TempDir(), "session.json"),
+		Output:      output,
+	}
+	out := &bytes.Buffer{}
+	return NewApp(cfg, pb.NewGophKeeperClient(conn), strings.NewReader(""), out), fake, out
+}
+
+func TestApp_LoginAndList(t *testing.T) {
+	app, _, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+
+	err := app.Run(ctx, []string{"list"})
+	require.ErrorIs(t, err, ErrNotLoggedIn)
+
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+	require.Contains(t, out.String(), "logged in as alice")
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"list"}))
+	require.Contains(t, out.String(), "credentials")
+	require.Contains(t, out.String(), "vpn")
+
+	require.NoError(t, app.Run(ctx, []string{"logout"}))
+	require.ErrorIs(t, app.Run(ctx, []string{"list"}), ErrNotLoggedIn)
+}
+
+func TestApp_ListPages(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"list", "-limit", "1", "-type", "creds", "-sort", "updated-asc"}))
+	require.Contains(t, out.String(), "vpn")
+	require.NotContains(t, out.String(), "visa")
+	require.Contains(t, out.String(), "1 of 2 records; next page: -cursor c1")
+	require.Equal(t, []pbc.DataType{pbc.DataType_DATA_TYPE_CREDENTIALS}, fake.listed.GetTypes())
+	require.Equal(t, pbrpc.DataListSort_DATA_LIST_SORT_UPDATED_ASC, fake.listed.GetSort())
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"list", "-limit", "1", "-cursor", "c1"}))
+	require.Contains(t, out.String(), "visa")
+	require.NotContains(t, out.String(), "next page")
+
+	require.ErrorIs(t, app.Run(ctx, []string{"list", "-sort", "name"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"list", "-type", "boat"}), ErrUsage)
+}
+
+func TestApp_Search(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"search", "-type", "creds", "рабочий", "vpn"}))
+	require.Contains(t, out.String(), "content: [рабочий] [vpn]")
+	require.Equal(t, "рабочий vpn", fake.searched.GetQuery())
+	require.Equal(t, []pbc.DataType{pbc.DataType_DATA_TYPE_CREDENTIALS}, fake.searched.GetTypes())
+
+	require.ErrorIs(t, app.Run(ctx, []string{"search"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"search", "-type", "boat", "vpn"}), ErrUsage)
+}
+
+func TestApp_LoginPasswordFromEnv(t *testing.T) {
+	t.Setenv(passwordEnv, "password123")
+	app, _, _ := newTestApp(t, OutputText)
+
+	require.NoError(t, app.Run(context.Background(), []string{"login", "-u", "alice"}))
+
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	require.Equal(t, testToken, sess.Token)
+	require.Equal(t, int32(7), sess.UserID)
+}
+
+func TestApp_SaveAndView(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	require.NoError(t, app.Run(ctx, []string{"save", "card", "-number", "4111111111111111", "-cvv", "123", "-meta", "visa"}))
+	require.Len(t, fake.saved, 1)
+	require.Equal(t, "4111111111111111", fake.saved[0].GetBankCard().GetCardNumber())
+	require.Equal(t, "visa", fake.saved[0].GetMeta().GetContent())
+
+	path := filepath.Join(t.TempDir(), "note.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+	require.NoError(t, app.Run(ctx, []string{"save", "file", "-path", path}))
+	require.Equal(t, "note.txt", fake.saved[1].GetBinaryData().GetName())
+	require.Equal(t, []byte("hello"), fake.saved[1].GetBinaryData().GetData())
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "1"}))
+	require.Contains(t, out.String(), "s3cret")
+
+	saved := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "2", "-out", saved}))
+	b, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(b))
+}
+
+func TestApp_SaveMeta(t *testing.T) {
+	app, fake, _ := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	require.NoError(t, app.Run(ctx, []string{"save", "creds", "-login", "root", "-title", "Router",
+		"-tag", "home, wifi", "-tag", "lan", "-favorite", "-url", "https://192.168.0.1",
+		"-field", "Port:number=22", "-secret", "PIN=1234"}))
+	require.Len(t, fake.saved, 1)
+	meta := fake.saved[0].GetMeta()
+	require.Equal(t, "Router", meta.GetTitle())
+	require.Equal(t, []string{"home", "wifi", "lan"}, meta.GetTags())
+	require.True(t, meta.GetFavorite())
+	require.Equal(t, []string{"https://192.168.0.1"}, meta.GetUrls())
+	require.Len(t, meta.GetFields(), 2)
+	require.Equal(t, pbc.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER, meta.GetFields()[0].GetType())
+	require.Equal(t, "Port", meta.GetFields()[0].GetName())
+	require.False(t, meta.GetFields()[0].GetSensitive())
+	require.True(t, meta.GetFields()[1].GetSensitive())
+	require.Equal(t, "1234", meta.GetFields()[1].GetValue())
+
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "creds", "-login", "root", "-field", "Port"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "creds", "-login", "root", "-field", "Port:color=22"}), ErrUsage)
+
+	require.NoError(t, app.Run(ctx, []string{"list", "-tag", "Home,wifi", "-favorites"}))
+	require.Equal(t, []string{"Home", "wifi"}, fake.listed.GetTags())
+	require.True(t, fake.listed.GetFavorite())
+}
+
+func TestApp_SecureNote(t *testing.T) {
+	t.Setenv(passwordEnv, "correct horse")
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"signup", "-u", "bob", "-zero-knowledge"}))
+
+	path := filepath.Join(t.TempDir(), "runbook.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Restore\n\n1. stop the service\n"), 0o600))
+	require.NoError(t, app.Run(ctx, []string{"save", "note", "-path", path, "-title", "Runbook"}))
+	require.Len(t, fake.saved, 1)
+	require.Equal(t, pbc.DataType_DATA_TYPE_TEXT, fake.saved[0].GetType())
+	require.Nil(t, fake.saved[0].GetSecureNote(), "plaintext must not be sent")
+	require.NotContains(t, string(fake.saved[0].GetEncrypted().GetEncryptedData()), "stop the service")
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "3"}))
+	require.Contains(t, out.String(), "note")
+	require.Contains(t, out.String(), "# Restore\n\n1. stop the service\n")
+
+	require.NoError(t, app.Run(ctx, []string{"update", "-id", "3", "note", "-text", "seed words"}))
+	require.NotNil(t, fake.updated[0].GetEncrypted())
+
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "note"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "note", "-text", "a", "-path", path}), ErrUsage)
+}
+
+func TestApp_OTP(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	require.NoError(t, app.Run(ctx, []string{"save", "otp", "-uri",
+		"otpauth://totp/GitHub:alice?secret=jbswy3dpehpk3pxp&digits=8&algorithm=SHA256"}))
+	otp := fake.saved[0].GetOtp()
+	require.Equal(t, "JBSWY3DPEHPK3PXP", otp.GetSecret())
+	require.Equal(t, "GitHub", otp.GetIssuer())
+	require.Equal(t, pbc.OtpAlgorithm_OTP_ALGORITHM_SHA256, otp.GetAlgorithm())
+	require.Equal(t, int32(8), otp.GetDigits())
+	require.Equal(t, int32(30), otp.GetPeriod())
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"otp", "-id", "1"}))
+	require.Equal(t, "123456  (valid for 12s, next 654321)\n", out.String())
+
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "otp"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "otp", "-seed", "not base32!"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"otp"}), ErrUsage)
+}
+
+func TestApp_OTPZeroKnowledge(t *testing.T) {
+	t.Setenv(passwordEnv, "correct horse")
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"signup", "-u", "bob", "-zero-knowledge"}))
+
+	const secret = "JBSWY3DPEHPK3PXP"
+	require.NoError(t, app.Run(ctx, []string{"save", "otp", "-seed", secret, "-issuer", "GitHub"}))
+	require.Nil(t, fake.saved[0].GetOtp(), "plaintext must not be sent")
+	require.NotContains(t, string(fake.saved[0].GetEncrypted().GetEncryptedData()), secret)
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "3"}))
+	require.Contains(t, out.String(), "one-time password")
+	require.Contains(t, out.String(), secret)
+
+	// The codes are generated locally, the server never sees the seed
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"otp", "-id", "3"}))
+	code, _, _ := strings.Cut(out.String(), " ")
+	_, valid := totp.Validate(code, secret, time.Now(), 1, totp.Opts{})
+	require.True(t, valid)
+}
+
+func TestApp_JSONOutput(t *testing.T) {
+	app, _, out := newTestApp(t, OutputJSON)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "1"}))
+	require.Contains(t, out.String(), `"login": "alice"`)
+	require.Contains(t, out.String(), `"DATA_TYPE_CREDENTIALS"`)
+}
+
+func TestApp_Usage(t *testing.T) {
+	app, _, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+
+	require.NoError(t, app.Run(ctx, nil))
+	require.Contains(t, out.String(), "Commands:")
+
+	require.ErrorIs(t, app.Run(ctx, []string{"bogus"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"view"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"save", "boat"}), ErrUsage)
+}
+
+func TestApp_ZeroKnowledge(t *testing.T) {
+	t.Setenv(passwordEnv, "correct horse")
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+
+	require.NoError(t, app.Run(ctx, []string{"signup", "-u", "bob", "-zero-knowledge"}))
+	require.NotNil(t, fake.zk)
+	require.NotEqual(t, "correct horse", fake.zk.GetPassword(), "the password must not reach the server")
+	require.NotEmpty(t, fake.zk.GetWrappedMasterKey())
+
+	// Log in again from scratch to exercise PreLogin and key derivation.
+	require.NoError(t, app.Run(ctx, []string{"logout"}))
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "bob"}))
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	require.True(t, sess.ZeroKnowledge)
+
+	require.NoError(t, app.Run(ctx, []string{"save", "creds", "-login", "bob", "-password", "hunter2", "-meta", "mail"}))
+	require.Len(t, fake.saved, 1)
+	require.Nil(t, fake.saved[0].GetCredentials(), "plaintext must not be sent")
+	require.NotEmpty(t, fake.saved[0].GetEncrypted().GetEncryptedData())
+	require.NotContains(t, string(fake.saved[0].GetEncrypted().GetEncryptedData()), "hunter2")
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "3"}))
+	require.Contains(t, out.String(), "hunter2")
+
+	t.Setenv(passwordEnv, "wrong horse")
+	require.ErrorContains(t, app.Run(ctx, []string{"view", "-id", "3"}), "wrong master password")
+	require.Error(t, app.Run(ctx, []string{"login", "-u", "bob"}))
+}
+
+func TestApp_ChangePasswordZeroKnowledge(t *testing.T) {
+	t.Setenv(passwordEnv, "correct horse")
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+
+	require.NoError(t, app.Run(ctx, []string{"signup", "-u", "bob", "-zero-knowledge"}))
+	require.NoError(t, app.Run(ctx, []string{"save", "creds", "-login", "bob", "-password", "hunter2"}))
+
+	require.Error(t, app.Run(ctx, []string{"passwd", "-p", "wrong horse", "-new", "battery staple"}))
+	require.NoError(t, app.Run(ctx, []string{"passwd", "-new", "battery staple"}))
+	require.Contains(t, out.String(), "password changed")
+
+	t.Setenv(passwordEnv, "battery staple")
+	require.NoError(t, app.Run(ctx, []string{"logout"}))
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "bob"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"view", "-id", "3"}))
+	require.Contains(t, out.String(), "hunter2", "records saved before the change stay readable")
+	require.Len(t, fake.saved, 1)
+}
+
+func TestApp_TwoFactor(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+	require.NoError(t, app.Run(ctx, []string{"totp", "enroll"}))
+	require.Contains(t, out.String(), "otpauth://totp/GophKeeper:alice")
+
+	require.Error(t, app.Run(ctx, []string{"totp", "confirm", "-code", "000000"}))
+	require.NoError(t, app.Run(ctx, []string{"totp", "confirm", "-code", testTOTPCode}))
+	require.Contains(t, out.String(), "AAAA-BBBB-CCCC-DDDD")
+	require.True(t, fake.totpEnabled)
+
+	require.NoError(t, app.Run(ctx, []string{"logout"}))
+	require.Error(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123", "-code", "000000"}))
+	require.ErrorIs(t, app.Run(ctx, []string{"list"}), ErrNotLoggedIn)
+
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123", "-code", testTOTPCode}))
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	require.Equal(t, testToken, sess.Token)
+
+	require.NoError(t, app.Run(ctx, []string{"totp", "disable", "-p", "password123", "-code", testTOTPCode}))
+	require.False(t, fake.totpEnabled)
+	require.ErrorIs(t, app.Run(ctx, []string{"totp"}), ErrUsage)
+}
+
+func TestApp_RefreshAndLogout(t *testing.T) {
+	app, fake, _ := newTestApp(t, OutputText)
+	ctx := context.Background()
+
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+	sess, err := app.sessions.Load()
+	require.NoError(t, err)
+	require.Equal(t, testRefreshToken, sess.RefreshToken)
+	require.WithinDuration(t, time.Now().Add(900*time.Second), sess.ExpiresAt, time.Minute)
+
+	// A fresh access token is used as is.
+	require.NoError(t, app.Run(ctx, []string{"list"}))
+	require.Zero(t, fake.refreshed)
+
+	// An expired one is refreshed first, and the rotated refresh token is stored.
+	sess.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, app.sessions.Save(sess))
+	require.NoError(t, app.Run(ctx, []string{"list"}))
+	require.Equal(t, 1, fake.refreshed)
+	sess, err = app.sessions.Load()
+	require.NoError(t, err)
+	require.Equal(t, fake.refreshToken, sess.RefreshToken)
+
+	require.NoError(t, app.Run(ctx, []string{"logout"}))
+	require.True(t, fake.loggedOut)
+	require.ErrorIs(t, app.Run(ctx, []string{"list"}), ErrNotLoggedIn)
+
+	// A refresh token the server no longer accepts ends the local session.
+	require.NoError(t, app.sessions.Save(&Session{Token: testToken, RefreshToken: "revoked", ExpiresAt: time.Now()}))
+	require.ErrorIs(t, app.Run(ctx, []string{"list"}), ErrNotLoggedIn)
+	_, err = app.sessions.Load()
+	require.ErrorIs(t, err, ErrNotLoggedIn)
+}
+
+func TestApp_Sessions(t *testing.T) {
+	app, _, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"sessions"}))
+	require.Contains(t, out.String(), "test-host")
+	require.Contains(t, out.String(), "gophkeeper-cli/1.2.3")
+	require.Contains(t, out.String(), "(current)")
+	require.Contains(t, out.String(), "phone")
+
+	require.NoError(t, app.Run(ctx, []string{"sessions", "revoke", "-id", "2"}))
+	require.Error(t, app.Run(ctx, []string{"sessions", "revoke", "-id", "3"}))
+	require.ErrorIs(t, app.Run(ctx, []string{"sessions", "revoke"}), ErrUsage)
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"sessions", "revoke-others"}))
+	require.Contains(t, out.String(), "revoked 1 session")
+	require.ErrorIs(t, app.Run(ctx, []string{"sessions", "bogus"}), ErrUsage)
+}
+
+func TestApp_Update(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	require.NoError(t, app.Run(ctx, []string{"update", "-id", "1", "creds", "-login", "alice", "-password", "n3w"}))
+	require.Contains(t, out.String(), "updated")
+	require.Len(t, fake.updated, 1)
+	require.Equal(t, int32(1), fake.updated[0].GetId())
+	require.Equal(t, "n3w", fake.updated[0].GetCredentials().GetPassword())
+	require.Nil(t, fake.updated[0].GetMeta(), "the description is kept without -meta")
+
+	require.NoError(t, app.Run(ctx, []string{"update", "-id", "1", "card", "-number", "4111", "-meta", "visa"}))
+	require.Equal(t, "visa", fake.updated[1].GetMeta().GetContent())
+
+	require.ErrorIs(t, app.Run(ctx, []string{"update", "creds", "-login", "alice"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"update", "-id", "1", "boat"}), ErrUsage)
+}
+
+func TestApp_Versions(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	require.NoError(t, app.Run(ctx, []string{"versions", "retention", "-n", "5"}))
+	require.Equal(t, int32(5), fake.retention)
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"versions", "-id", "1"}))
+	require.Contains(t, out.String(), "current version 3, keeping 5 prior versions")
+	require.Contains(t, out.String(), "02.01.2025 10:00")
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"versions", "view", "-id", "1", "-v", "2"}))
+	require.Contains(t, out.String(), "old-secret")
+	require.Error(t, app.Run(ctx, []string{"versions", "view", "-id", "1", "-v", "1"}))
+
+	require.NoError(t, app.Run(ctx, []string{"versions", "restore", "-id", "1", "-v", "2"}))
+	require.Equal(t, []int32{2}, fake.restored)
+
+	require.ErrorIs(t, app.Run(ctx, []string{"versions"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"versions", "restore", "-id", "1"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"versions", "bogus", "-id", "1"}), ErrUsage)
+}
+
+func TestApp_Trash(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"trash"}))
+	require.Contains(t, out.String(), "trash is empty")
+
+	require.NoError(t, app.Run(ctx, []string{"delete", "-id", "1"}))
+	require.NoError(t, app.Run(ctx, []string{"delete", "-id", "2"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"trash", "list"}))
+	require.Contains(t, out.String(), "01.07.2025 10:00")
+
+	require.NoError(t, app.Run(ctx, []string{"trash", "restore", "-id", "1"}))
+	require.Equal(t, []int32{2}, fake.trashed)
+	require.Error(t, app.Run(ctx, []string{"trash", "restore", "-id", "1"}))
+	require.ErrorIs(t, app.Run(ctx, []string{"trash", "restore"}), ErrUsage)
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"trash", "empty"}))
+	require.Contains(t, out.String(), "purged 1")
+	require.Empty(t, fake.trashed)
+	require.ErrorIs(t, app.Run(ctx, []string{"trash", "bogus"}), ErrUsage)
+}
+
+func TestApp_Folders(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"folders"}))
+	require.Contains(t, out.String(), "no folders")
+
+	require.NoError(t, app.Run(ctx, []string{"folders", "create", "-name", "Work"}))
+	require.NoError(t, app.Run(ctx, []string{"folders", "create", "-name", "Projects"}))
+	require.NoError(t, app.Run(ctx, []string{"folders", "move", "-id", "2", "-parent", "1"}))
+	require.Error(t, app.Run(ctx, []string{"folders", "move", "-id", "2", "-parent", "2"}))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"folders", "list"}))
+	require.Regexp(t, `(?m)^1\s+Work\s`, out.String())
+	require.Regexp(t, `(?m)^2\s+  Projects\s`, out.String(), "subfolders are indented under their parent")
+
+	require.ErrorIs(t, app.Run(ctx, []string{"folders", "create"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"folders", "rename", "-name", "x"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"folders", "bogus"}), ErrUsage)
+
+	require.NoError(t, app.Run(ctx, []string{"move", "-folder", "2", "1", "3"}))
+	require.Equal(t, []int32{1, 3}, fake.moved.GetIds())
+	require.Equal(t, int32(2), fake.moved.GetFolderId())
+	require.NoError(t, app.Run(ctx, []string{"move", "-folder", "0", "1"}))
+	require.Zero(t, fake.moved.GetFolderId())
+	require.ErrorIs(t, app.Run(ctx, []string{"move", "1"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"move", "-folder", "2", "one"}), ErrUsage)
+
+	require.NoError(t, app.Run(ctx, []string{"list", "-folder", "1", "-subfolders"}))
+	require.Equal(t, int32(1), fake.listed.GetFolderId())
+	require.True(t, fake.listed.GetSubfolders())
+}
+
+func TestApp_FoldersZeroKnowledge(t *testing.T) {
+	t.Setenv(passwordEnv, "correct horse")
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"signup", "-u", "bob", "-zero-knowledge"}))
+
+	require.NoError(t, app.Run(ctx, []string{"folders", "create", "-name", "Taxes"}))
+	require.Len(t, fake.folders, 1)
+	require.Empty(t, fake.folders[0].GetName(), "the name must not reach the server in plaintext")
+	require.NotContains(t, string(fake.folders[0].GetEncrypted().GetEncryptedData()), "Taxes")
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"folders"}))
+	require.Contains(t, out.String(), "Taxes")
+}
+
+func TestApp_UploadDownload(t *testing.T) {
+	app, fake, out := newTestApp(t, OutputText)
+	ctx := context.Background()
+	require.NoError(t, app.Run(ctx, []string{"login", "-u", "alice", "-p", "password123"}))
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "big.bin")
+	data := bytes.Repeat([]byte("0123456789"), transferChunkSize/4)
+	require.NoError(t, os.WriteFile(src, data, 0o600))
+
+	out.Reset()
+	require.NoError(t, app.Run(ctx, []string{"upload", "-path", src, "-meta", "backup"}))
+	require.Contains(t, out.String(), "id 9")
+	require.Equal(t, "big.bin", fake.uploadedName)
+	require.Equal(t, data, fake.uploaded)
+
+	dst := filepath.Join(dir, "restored.bin")
+	require.NoError(t, app.Run(ctx, []string{"download", "-id", "9", "-out", dst}))
+	got, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	missing := filepath.Join(dir, "missing.bin")
+	require.Error(t, app.Run(ctx, []string{"download", "-id", "1", "-out", missing}))
+	require.NoFileExists(t, missing)
+
+	require.ErrorIs(t, app.Run(ctx, []string{"upload"}), ErrUsage)
+	require.ErrorIs(t, app.Run(ctx, []string{"download", "-id", "9"}), ErrUsage)
+}
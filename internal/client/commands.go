@@ -0,0 +1,1507 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/sshkey"
+	"github.com/apetsko/gophkeeper/pkg/totp"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// transferChunkSize is the size of the file chunks sent by upload.
+const transferChunkSize = 256 * 1024
+
+// ping checks that the server is reachable.
+func (a *App) ping(ctx context.Context, _ []string) error {
+	resp, err := a.api.Ping(ctx, &pbrpc.PingRequest{})
+	if err != nil {
+		return err
+	}
+	return a.out.message(resp.GetMessage())
+}
+
+// signup registers a new account and stores the returned session.
+func (a *App) signup(ctx context.Context, args []string) error {
+	fs := newFlagSet("signup")
+	username := fs.String("u", "", "username")
+	pass := fs.String("p", "", "password (prefer GOPHKEEPER_PASSWORD or the prompt)")
+	zk := fs.Bool("zero-knowledge", false, "encrypt all data on this side; the server never sees the keys")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("%w: signup: -u is required", ErrUsage)
+	}
+
+	p, err := a.password(*pass, "Password: ")
+	if err != nil {
+		return err
+	}
+
+	req := &pbrpcu.SignupRequest{Username: *username, Password: p}
+	sess := &Session{}
+	if *zk {
+		keys, params, wrapped, errKeys := zeroKnowledgeSignup(p)
+		if errKeys != nil {
+			return errKeys
+		}
+		req.Password = keys.AuthKey
+		req.ZeroKnowledge = true
+		req.Kdf = kdfToProto(params)
+		req.WrappedMasterKey = wrapped
+		sess.ZeroKnowledge, sess.KDF, sess.WrappedMasterKey = true, &params, wrapped
+	}
+
+	resp, err := a.api.Signup(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	sess.UserID, sess.Username = resp.GetId(), resp.GetUsername()
+	sess.setTokens(resp.GetToken(), resp.GetRefreshToken(), resp.GetExpiresIn())
+	if err := a.storeSession(sess); err != nil {
+		return err
+	}
+	return a.out.user(resp.GetId(), resp.GetUsername())
+}
+
+// login authenticates and stores the returned session.
+func (a *App) login(ctx context.Context, args []string) error {
+	fs := newFlagSet("login")
+	username := fs.String("u", "", "username")
+	pass := fs.String("p", "", "password (prefer GOPHKEEPER_PASSWORD or the prompt)")
+	code := fs.String("code", "", "TOTP or recovery code, if two-factor authentication is enabled")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("%w: login: -u is required", ErrUsage)
+	}
+
+	p, err := a.password(*pass, "Password: ")
+	if err != nil {
+		return err
+	}
+
+	pre, err := a.api.PreLogin(ctx, &pbrpcu.PreLoginRequest{Username: *username})
+	if err != nil {
+		return err
+	}
+
+	var keys *crypto.ClientKeys
+	if pre.GetZeroKnowledge() {
+		keys, err = crypto.DeriveClientKeys(p, kdfFromProto(pre.GetKdf()))
+		if err != nil {
+			return err
+		}
+		p = keys.AuthKey
+	}
+
+	resp, err := a.api.Login(ctx, &pbrpcu.LoginRequest{Username: *username, Password: p})
+	if err != nil {
+		return err
+	}
+
+	if resp.GetTwoFactorRequired() {
+		c, errCode := a.code(*code)
+		if errCode != nil {
+			return errCode
+		}
+		resp, err = a.api.LoginTOTP(ctx, &pbrpcu.LoginTOTPRequest{TwoFactorToken: resp.GetTwoFactorToken(), Code: c})
+		if err != nil {
+			return err
+		}
+	}
+
+	sess := &Session{UserID: resp.GetId(), Username: resp.GetUsername()}
+	sess.setTokens(resp.GetToken(), resp.GetRefreshToken(), resp.GetExpiresIn())
+	if resp.GetZeroKnowledge() {
+		if keys == nil {
+			return errors.New("server switched to zero-knowledge mode during login: try again")
+		}
+		if _, err := crypto.UnwrapKey(keys.EncryptionKey, resp.GetWrappedMasterKey()); err != nil {
+			return fmt.Errorf("failed to open master key: %w", err)
+		}
+		params := kdfFromProto(resp.GetKdf())
+		sess.ZeroKnowledge, sess.KDF, sess.WrappedMasterKey = true, &params, resp.GetWrappedMasterKey()
+	}
+
+	if err := a.storeSession(sess); err != nil {
+		return err
+	}
+	return a.out.user(resp.GetId(), resp.GetUsername())
+}
+
+// logout ends the session on the server and removes it locally.
+//
+// The local session is removed even if the server cannot be reached.
+func (a *App) logout(ctx context.Context, _ []string) error {
+	if sess, err := a.session(ctx); err == nil {
+		if _, err := a.api.Logout(withToken(ctx, sess.Token), &pbrpcu.LogoutRequest{}); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: server logout failed: %v\n", err)
+		}
+	}
+
+	if err := a.sessions.Remove(); err != nil {
+		return err
+	}
+	return a.out.message("logged out")
+}
+
+// passwd changes the account password.
+//
+// For zero-knowledge accounts the master key is re-wrapped locally with keys derived from the
+// new password; the server only receives the new authentication key and the wrapped key.
+func (a *App) passwd(ctx context.Context, args []string) error {
+	fs := newFlagSet("passwd")
+	pass := fs.String("p", "", "current password (prefer GOPHKEEPER_PASSWORD or the prompt)")
+	newPass := fs.String("new", "", "new password (prefer the prompt)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+
+	oldP, err := a.password(*pass, "Current password: ")
+	if err != nil {
+		return err
+	}
+
+	newP := *newPass
+	if newP == "" {
+		if newP, err = a.prompt("New password: "); err != nil {
+			return err
+		}
+	}
+
+	req := &pbrpcu.ChangePasswordRequest{OldPassword: oldP, NewPassword: newP}
+	if sess.ZeroKnowledge {
+		if err := rewrapForPassword(sess, req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := a.api.ChangePassword(withToken(ctx, sess.Token), req)
+	if err != nil {
+		return err
+	}
+
+	if sess.ZeroKnowledge {
+		params := kdfFromProto(req.GetKdf())
+		sess.KDF, sess.WrappedMasterKey = &params, req.GetWrappedMasterKey()
+		if err := a.sessions.Save(sess); err != nil {
+			return err
+		}
+	}
+
+	return a.out.message(resp.GetMessage())
+}
+
+// totp manages two-factor authentication: enroll starts it, confirm enables it with the first
+// code from the authenticator app and disable turns it off.
+func (a *App) totp(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: totp: expected enroll, confirm or disable", ErrUsage)
+	}
+
+	fs := newFlagSet("totp " + args[0])
+	code := fs.String("code", "", "TOTP code, or a recovery code for disable")
+	pass := fs.String("p", "", "password for disable (prefer GOPHKEEPER_PASSWORD or the prompt)")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = withToken(ctx, sess.Token)
+
+	switch args[0] {
+	case "enroll":
+		resp, errEnroll := a.api.EnrollTOTP(ctx, &pbrpcu.EnrollTOTPRequest{})
+		if errEnroll != nil {
+			return errEnroll
+		}
+		return a.out.totpEnrollment(resp)
+	case "confirm":
+		c, errCode := a.code(*code)
+		if errCode != nil {
+			return errCode
+		}
+		resp, errConfirm := a.api.ConfirmTOTP(ctx, &pbrpcu.ConfirmTOTPRequest{Code: c})
+		if errConfirm != nil {
+			return errConfirm
+		}
+		return a.out.recoveryCodes(resp.GetRecoveryCodes())
+	case "disable":
+		p, errPass := a.password(*pass, "Password: ")
+		if errPass != nil {
+			return errPass
+		}
+		if sess.ZeroKnowledge {
+			keys, errKeys := crypto.DeriveClientKeys(p, *sess.KDF)
+			if errKeys != nil {
+				return errKeys
+			}
+			p = keys.AuthKey
+		}
+		c, errCode := a.code(*code)
+		if errCode != nil {
+			return errCode
+		}
+		resp, errDisable := a.api.DisableTOTP(ctx, &pbrpcu.DisableTOTPRequest{Password: p, Code: c})
+		if errDisable != nil {
+			return errDisable
+		}
+		return a.out.message(resp.GetMessage())
+	default:
+		return fmt.Errorf("%w: totp: unknown action %q", ErrUsage, args[0])
+	}
+}
+
+// sessionsCmd lists the active sessions of the user or ends them: revoke ends one session
+// and revoke-others every session except the current one.
+func (a *App) sessionsCmd(ctx context.Context, args []string) error {
+	action := "list"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	fs := newFlagSet("sessions " + action)
+	id := fs.Int("id", 0, "session ID for revoke")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx, err := a.authContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		resp, errList := a.api.ListSessions(ctx, &pbrpcu.ListSessionsRequest{})
+		if errList != nil {
+			return errList
+		}
+		return a.out.sessions(resp)
+	case "revoke":
+		if *id <= 0 {
+			return fmt.Errorf("%w: sessions revoke: -id is required", ErrUsage)
+		}
+		resp, errRevoke := a.api.RevokeSession(ctx, &pbrpcu.RevokeSessionRequest{SessionId: int32(*id)})
+		if errRevoke != nil {
+			return errRevoke
+		}
+		return a.out.message(resp.GetMessage())
+	case "revoke-others":
+		resp, errRevoke := a.api.RevokeAllOtherSessions(ctx, &pbrpcu.RevokeAllOtherSessionsRequest{})
+		if errRevoke != nil {
+			return errRevoke
+		}
+		return a.out.message(resp.GetMessage())
+	default:
+		return fmt.Errorf("%w: sessions: unknown action %q", ErrUsage, action)
+	}
+}
+
+// code returns the code from the flag value or prompts for it.
+func (a *App) code(fromFlag string) (string, error) {
+	if fromFlag != "" {
+		return fromFlag, nil
+	}
+	return a.prompt("TOTP code: ")
+}
+
+// listSorts maps the values of the list -sort flag to the orders of DataList.
+var listSorts = map[string]pbrpc.DataListSort{
+	"created":     pbrpc.DataListSort_DATA_LIST_SORT_CREATED_DESC,
+	"created-asc": pbrpc.DataListSort_DATA_LIST_SORT_CREATED_ASC,
+	"updated":     pbrpc.DataListSort_DATA_LIST_SORT_UPDATED_DESC,
+	"updated-asc": pbrpc.DataListSort_DATA_LIST_SORT_UPDATED_ASC,
+}
+
+// listTypes maps the values of the list -type flag to data types, named as in save.
+var listTypes = map[string]pbc.DataType{
+	"card":  pbc.DataType_DATA_TYPE_BANK_CARD,
+	"creds": pbc.DataType_DATA_TYPE_CREDENTIALS,
+	"file":  pbc.DataType_DATA_TYPE_BINARY_DATA,
+	"note":  pbc.DataType_DATA_TYPE_TEXT,
+	"otp":   pbc.DataType_DATA_TYPE_OTP,
+	"ssh":   pbc.DataType_DATA_TYPE_SSH_KEY,
+}
+
+// list prints a page of the user's records; -cursor continues from the previous page.
+func (a *App) list(ctx context.Context, args []string) error {
+	fs := newFlagSet("list")
+	dataType := fs.String("type", "", "show only records of this type: card, creds, note, otp, ssh or file")
+	sort := fs.String("sort", "created", "order: created, created-asc, updated or updated-asc")
+	limit := fs.Int("limit", 0, "number of records per page, the server default when 0")
+	cursor := fs.String("cursor", "", "cursor printed with the previous page")
+	favorite := fs.Bool("favorites", false, "show only favorite records")
+	folder := fs.Int("folder", 0, "show only records in this folder")
+	subfolders := fs.Bool("subfolders", false, "with -folder, also show records in its subfolders")
+	var tags []string
+	fs.Func("tag", "show only records with this tag; repeat the flag or separate tags with commas", func(v string) error {
+		tags = append(tags, splitList(v)...)
+		return nil
+	})
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *folder < 0 {
+		return fmt.Errorf("%w: list: -folder must not be negative", ErrUsage)
+	}
+
+	req := &pbrpc.DataListRequest{
+		Limit:      int32(*limit),
+		Cursor:     *cursor,
+		Tags:       tags,
+		Favorite:   *favorite,
+		FolderId:   int32(*folder),
+		Subfolders: *subfolders,
+	}
+	var ok bool
+	if req.Sort, ok = listSorts[*sort]; !ok {
+		return fmt.Errorf("%w: list: unknown sort %q", ErrUsage, *sort)
+	}
+	if *dataType != "" {
+		t, found := listTypes[*dataType]
+		if !found {
+			return fmt.Errorf("%w: list: unknown record type %q", ErrUsage, *dataType)
+		}
+		req.Types = []pbc.DataType{t}
+	}
+
+	ctx, err := a.authContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.api.DataList(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.out.list(resp)
+}
+
+// search prints the user's records whose metadata matches the query given after the flags.
+func (a *App) search(ctx context.Context, args []string) error {
+	fs := newFlagSet("search")
+	dataType := fs.String("type", "", "search only records of this type: card, creds, note, otp, ssh or file")
+	limit := fs.Int("limit", 0, "number of results, the server default when 0")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	query := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("%w: search: a query is required", ErrUsage)
+	}
+
+	req := &pbrpc.SearchRequest{Query: query, Limit: int32(*limit)}
+	if *dataType != "" {
+		t, found := listTypes[*dataType]
+		if !found {
+			return fmt.Errorf("%w: search: unknown record type %q", ErrUsage, *dataType)
+		}
+		req.Types = []pbc.DataType{t}
+	}
+
+	ctx, err := a.authContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.api.Search(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.out.search(resp)
+}
+
+// view prints a single record, writing binary data to the -out path when given.
+func (a *App) view(ctx context.Context, args []string) error {
+	fs := newFlagSet("view")
+	id := fs.Int("id", 0, "record ID")
+	out := fs.String("out", "", "path to save file contents to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return fmt.Errorf("%w: view: -id is required", ErrUsage)
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.api.DataView(withToken(ctx, sess.Token), &pbrpc.DataViewRequest{Id: int32(*id)})
+	if err != nil {
+		return err
+	}
+
+	return a.showRecord(ctx, sess, int32(*id), resp, *out)
+}
+
+// showRecord decrypts a record of a zero-knowledge account if needed and prints it, saving
+// file contents to out when it is set.
+func (a *App) showRecord(ctx context.Context, sess *Session, id int32, resp *pbrpc.DataViewResponse, out string) error {
+	if resp.GetEncrypted() != nil {
+		mk, errKey := a.masterKey(sess)
+		if errKey != nil {
+			return errKey
+		}
+		if err := openResponse(ctx, mk, resp); err != nil {
+			return fmt.Errorf("failed to decrypt record: %w", err)
+		}
+	}
+
+	savedTo := ""
+	if file := resp.GetBinaryData(); file != nil && out != "" {
+		if err := os.WriteFile(out, file.GetData(), 0o600); err != nil {
+			return fmt.Errorf("failed to save file: %w", err)
+		}
+		savedTo = out
+		file.Data = nil
+	}
+	if key := resp.GetSshKey(); key != nil && out != "" {
+		if err := os.WriteFile(out, []byte(key.GetPrivateKey()), 0o600); err != nil {
+			return fmt.Errorf("failed to save private key: %w", err)
+		}
+		savedTo = out
+		key.PrivateKey = ""
+	}
+
+	return a.out.view(id, resp, savedTo)
+}
+
+// versions lists, shows and restores prior versions of a record and sets how many are kept.
+func (a *App) versions(ctx context.Context, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := newFlagSet("versions " + action)
+	id := fs.Int("id", 0, "record ID")
+	version := fs.Int("v", 0, "version number for view and restore")
+	out := fs.String("out", "", "path to save file contents to")
+	count := fs.Int("n", 0, "number of prior versions to keep per record, 0 for the server default")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if action != "retention" && *id <= 0 {
+		return fmt.Errorf("%w: versions %s: -id is required", ErrUsage, action)
+	}
+	if (action == "view" || action == "restore") && *version <= 0 {
+		return fmt.Errorf("%w: versions %s: -v is required", ErrUsage, action)
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+	authCtx := withToken(ctx, sess.Token)
+
+	switch action {
+	case "list":
+		resp, errList := a.api.ListRecordVersions(authCtx, &pbrpc.ListRecordVersionsRequest{Id: int32(*id)})
+		if errList != nil {
+			return errList
+		}
+		return a.out.versions(resp)
+	case "view":
+		resp, errView := a.api.ViewRecordVersion(authCtx, &pbrpc.ViewRecordVersionRequest{
+			Id:      int32(*id),
+			Version: int32(*version),
+		})
+		if errView != nil {
+			return errView
+		}
+		return a.showRecord(ctx, sess, int32(*id), resp, *out)
+	case "restore":
+		resp, errRestore := a.api.RestoreRecordVersion(authCtx, &pbrpc.RestoreRecordVersionRequest{
+			Id:      int32(*id),
+			Version: int32(*version),
+		})
+		if errRestore != nil {
+			return errRestore
+		}
+		return a.out.message(resp.GetMessage())
+	case "retention":
+		resp, errSet := a.api.SetVersionRetention(authCtx, &pbrpc.SetVersionRetentionRequest{Retention: int32(*count)})
+		if errSet != nil {
+			return errSet
+		}
+		return a.out.message(resp.GetMessage())
+	default:
+		return fmt.Errorf("%w: versions: unknown action %q", ErrUsage, action)
+	}
+}
+
+// save stores a new bank card, credentials or file record.
+func (a *App) save(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: save: expected card, creds, note, otp, ssh or file", ErrUsage)
+	}
+
+	var (
+		req *pbrpc.DataSaveRequest
+		err error
+	)
+
+	switch args[0] {
+	case "card":
+		req, err = cardRequest(args[1:])
+	case "creds":
+		req, err = credentialsRequest(args[1:])
+	case "note":
+		req, err = noteRequest(args[1:])
+	case "otp":
+		req, err = otpRequest(args[1:])
+	case "ssh":
+		req, err = sshKeyRequest(args[1:])
+	case "file":
+		req, err = fileRequest(args[1:])
+	default:
+		return fmt.Errorf("%w: save: unknown record type %q", ErrUsage, args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sess.ZeroKnowledge {
+		mk, errKey := a.masterKey(sess)
+		if errKey != nil {
+			return errKey
+		}
+		if err := sealRequest(ctx, mk, req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := a.api.DataSave(withToken(ctx, sess.Token), req)
+	if err != nil {
+		return err
+	}
+	return a.out.message(resp.GetMessage())
+}
+
+// update replaces the contents of an existing record, keeping its ID. The record type must
+// match the stored one; without meta flags the current meta is kept.
+func (a *App) update(ctx context.Context, args []string) error {
+	fs := newFlagSet("update")
+	id := fs.Int("id", 0, "record ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 || fs.NArg() == 0 {
+		return fmt.Errorf("%w: update: expected -id <id> and card, creds, note, otp, ssh or file", ErrUsage)
+	}
+
+	var (
+		req *pbrpc.DataSaveRequest
+		err error
+	)
+
+	switch fs.Arg(0) {
+	case "card":
+		req, err = cardRequest(fs.Args()[1:])
+	case "creds":
+		req, err = credentialsRequest(fs.Args()[1:])
+	case "note":
+		req, err = noteRequest(fs.Args()[1:])
+	case "otp":
+		req, err = otpRequest(fs.Args()[1:])
+	case "ssh":
+		req, err = sshKeyRequest(fs.Args()[1:])
+	case "file":
+		req, err = fileRequest(fs.Args()[1:])
+	default:
+		return fmt.Errorf("%w: update: unknown record type %q", ErrUsage, fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sess.ZeroKnowledge {
+		mk, errKey := a.masterKey(sess)
+		if errKey != nil {
+			return errKey
+		}
+		if err := sealRequest(ctx, mk, req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := a.api.DataUpdate(withToken(ctx, sess.Token), updateRequest(int32(*id), req))
+	if err != nil {
+		return err
+	}
+	return a.out.message(resp.GetMessage())
+}
+
+// otp prints the current and next codes of an OTP record. The server generates them without
+// sending the seed; the seeds of zero-knowledge accounts are decrypted and used locally.
+func (a *App) otp(ctx context.Context, args []string) error {
+	fs := newFlagSet("otp")
+	id := fs.Int("id", 0, "record ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return fmt.Errorf("%w: otp: -id is required", ErrUsage)
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+	authCtx := withToken(ctx, sess.Token)
+
+	if !sess.ZeroKnowledge {
+		resp, errGenerate := a.api.GenerateOTP(authCtx, &pbrpc.GenerateOTPRequest{Id: int32(*id)})
+		if errGenerate != nil {
+			return errGenerate
+		}
+		return a.out.otp(resp)
+	}
+
+	view, err := a.api.DataView(authCtx, &pbrpc.DataViewRequest{Id: int32(*id)})
+	if err != nil {
+		return err
+	}
+	mk, err := a.masterKey(sess)
+	if err != nil {
+		return err
+	}
+	if err = openResponse(ctx, mk, view); err != nil {
+		return fmt.Errorf("failed to decrypt record: %w", err)
+	}
+	seed := view.GetOtp()
+	if seed == nil {
+		return fmt.Errorf("record %d is not an OTP", *id)
+	}
+
+	opts := otpOpts(seed)
+	now := time.Now()
+	code, err := totp.Code(seed.GetSecret(), now, opts)
+	if err != nil {
+		return err
+	}
+	next, err := totp.Code(seed.GetSecret(), now.Add(time.Duration(seed.GetPeriod())*time.Second), opts)
+	if err != nil {
+		return err
+	}
+	return a.out.otp(&pbrpc.GenerateOTPResponse{
+		Code:      code,
+		NextCode:  next,
+		ExpiresIn: int32((totp.Remaining(now, opts) + time.Second - 1) / time.Second),
+		Period:    seed.GetPeriod(),
+	})
+}
+
+// delete removes a record.
+func (a *App) delete(ctx context.Context, args []string) error {
+	fs := newFlagSet("delete")
+	id := fs.Int("id", 0, "record ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return fmt.Errorf("%w: delete: -id is required", ErrUsage)
+	}
+
+	ctx, err := a.authContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.api.DataDelete(ctx, &pbrpc.DataDeleteRequest{Id: int32(*id)})
+	if err != nil {
+		return err
+	}
+	return a.out.message(resp.GetMessage())
+}
+
+// trash lists, restores and permanently deletes records in the trash.
+func (a *App) trash(ctx context.Context, args []string) error {
+	action := "list"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	fs := newFlagSet("trash " + action)
+	id := fs.Int("id", 0, "record ID for restore")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx, err := a.authContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		resp, errList := a.api.ListTrash(ctx, &pbrpc.ListTrashRequest{})
+		if errList != nil {
+			return errList
+		}
+		return a.out.trash(resp)
+	case "restore":
+		if *id <= 0 {
+			return fmt.Errorf("%w: trash restore: -id is required", ErrUsage)
+		}
+		resp, errRestore := a.api.RestoreFromTrash(ctx, &pbrpc.RestoreFromTrashRequest{Id: int32(*id)})
+		if errRestore != nil {
+			return errRestore
+		}
+		return a.out.message(resp.GetMessage())
+	case "empty":
+		resp, errEmpty := a.api.EmptyTrash(ctx, &pbrpc.EmptyTrashRequest{})
+		if errEmpty != nil {
+			return errEmpty
+		}
+		return a.out.message(resp.GetMessage())
+	default:
+		return fmt.Errorf("%w: trash: unknown action %q", ErrUsage, action)
+	}
+}
+
+// folders lists, creates, renames, moves and deletes folders.
+//
+// Folder names of zero-knowledge accounts are encrypted and decrypted here with the master key.
+func (a *App) folders(ctx context.Context, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := newFlagSet("folders " + action)
+	id := fs.Int("id", 0, "folder ID for rename, move and delete")
+	name := fs.String("name", "", "folder name for create and rename")
+	parent := fs.Int("parent", 0, "parent folder ID for create and move; 0 is the root")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *parent < 0 {
+		return fmt.Errorf("%w: folders %s: -parent must not be negative", ErrUsage, action)
+	}
+	switch action {
+	case "create", "rename":
+		if strings.TrimSpace(*name) == "" {
+			return fmt.Errorf("%w: folders %s: -name is required", ErrUsage, action)
+		}
+	}
+	switch action {
+	case "rename", "move", "delete":
+		if *id <= 0 {
+			return fmt.Errorf("%w: folders %s: -id is required", ErrUsage, action)
+		}
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+	authCtx := withToken(ctx, sess.Token)
+
+	// sealName encrypts the name of a folder of a zero-knowledge account
+	sealName := func() (*pbmodels.EncryptedPayload, error) {
+		mk, errKey := a.masterKey(sess)
+		if errKey != nil {
+			return nil, errKey
+		}
+		return sealFolderName(ctx, mk, strings.TrimSpace(*name))
+	}
+
+	switch action {
+	case "list":
+		resp, errList := a.api.ListFolders(authCtx, &pbrpc.ListFoldersRequest{})
+		if errList != nil {
+			return errList
+		}
+		if sess.ZeroKnowledge && len(resp.GetFolders()) > 0 {
+			mk, errKey := a.masterKey(sess)
+			if errKey != nil {
+				return errKey
+			}
+			for _, f := range resp.GetFolders() {
+				if err = openFolderName(ctx, mk, f); err != nil {
+					return fmt.Errorf("failed to decrypt folder %d: %w", f.GetId(), err)
+				}
+			}
+		}
+		return a.out.folders(resp)
+	case "create":
+		req := &pbrpc.CreateFolderRequest{ParentId: int32(*parent), NameData: &pbrpc.CreateFolderRequest_Name{Name: *name}}
+		if sess.ZeroKnowledge {
+			payload, errSeal := sealName()
+			if errSeal != nil {
+				return errSeal
+			}
+			req.NameData = &pbrpc.CreateFolderRequest_Encrypted{Encrypted: payload}
+		}
+		resp, errCreate := a.api.CreateFolder(authCtx, req)
+		if errCreate != nil {
+			return errCreate
+		}
+		return a.out.message(fmt.Sprintf("folder %d created", resp.GetFolder().GetId()))
+	case "rename":
+		req := &pbrpc.RenameFolderRequest{Id: int32(*id), NameData: &pbrpc.RenameFolderRequest_Name{Name: *name}}
+		if sess.ZeroKnowledge {
+			payload, errSeal := sealName()
+			if errSeal != nil {
+				return errSeal
+			}
+			req.NameData = &pbrpc.RenameFolderRequest_Encrypted{Encrypted: payload}
+		}
+		resp, errRename := a.api.RenameFolder(authCtx, req)
+		if errRename != nil {
+			return errRename
+		}
+		return a.out.message(resp.GetMessage())
+	case "move":
+		resp, errMove := a.api.MoveFolder(authCtx, &pbrpc.MoveFolderRequest{Id: int32(*id), ParentId: int32(*parent)})
+		if errMove != nil {
+			return errMove
+		}
+		return a.out.message(resp.GetMessage())
+	case "delete":
+		resp, errDelete := a.api.DeleteFolder(authCtx, &pbrpc.DeleteFolderRequest{Id: int32(*id)})
+		if errDelete != nil {
+			return errDelete
+		}
+		return a.out.message(resp.GetMessage())
+	default:
+		return fmt.Errorf("%w: folders: unknown action %q", ErrUsage, action)
+	}
+}
+
+// move moves the records given after the flags into a folder.
+func (a *App) move(ctx context.Context, args []string) error {
+	fs := newFlagSet("move")
+	folder := fs.Int("folder", -1, "target folder ID; 0 moves the records out of any folder")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *folder < 0 {
+		return fmt.Errorf("%w: move: -folder is required", ErrUsage)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: move: record IDs are required", ErrUsage)
+	}
+
+	req := &pbrpc.MoveRecordsRequest{FolderId: int32(*folder)}
+	for _, arg := range fs.Args() {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("%w: move: invalid record ID %q", ErrUsage, arg)
+		}
+		req.Ids = append(req.Ids, int32(id))
+	}
+
+	ctx, err := a.authContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.api.MoveRecords(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.out.message(resp.GetMessage())
+}
+
+// upload streams a local file of any size to the server as a new file record.
+func (a *App) upload(ctx context.Context, args []string) error {
+	fs := newFlagSet("upload")
+	path := fs.String("path", "", "path to the file")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return fmt.Errorf("%w: upload: -path is required", ErrUsage)
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+	if sess.ZeroKnowledge {
+		return errors.New("zero-knowledge accounts encrypt files on the client; use save file instead")
+	}
+
+	f, err := os.Open(filepath.Clean(*path))
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	stream, err := a.api.UploadFile(withToken(ctx, sess.Token))
+	if err != nil {
+		return err
+	}
+	if err = stream.Send(&pbrpc.UploadFileRequest{Payload: &pbrpc.UploadFileRequest_Header{Header: &pbrpc.UploadFileHeader{
+		Meta: meta,
+		Name: filepath.Base(*path),
+		Type: contentType(*path, head[:n]),
+	}}}); err != nil {
+		return uploadError(stream, err)
+	}
+
+	buf := make([]byte, transferChunkSize)
+	copy(buf, head[:n])
+	for filled := n; ; filled = 0 {
+		m, errRead := io.ReadFull(f, buf[filled:])
+		filled += m
+		if filled > 0 {
+			if err = stream.Send(&pbrpc.UploadFileRequest{
+				Payload: &pbrpc.UploadFileRequest_Chunk{Chunk: buf[:filled]},
+			}); err != nil {
+				return uploadError(stream, err)
+			}
+		}
+		if errors.Is(errRead, io.EOF) || errors.Is(errRead, io.ErrUnexpectedEOF) {
+			break
+		}
+		if errRead != nil {
+			return fmt.Errorf("failed to read file: %w", errRead)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return a.out.message(fmt.Sprintf("%s (id %d, %d bytes)", resp.GetMessage(), resp.GetId(), resp.GetSize()))
+}
+
+// uploadError returns the status the server ended an upload with when sending a chunk fails.
+func uploadError(stream grpc.ClientStreamingClient[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse], err error) error {
+	if errors.Is(err, io.EOF) {
+		_, err = stream.CloseAndRecv()
+	}
+	return err
+}
+
+// download streams the contents of a file record to a local path.
+func (a *App) download(ctx context.Context, args []string) error {
+	fs := newFlagSet("download")
+	id := fs.Int("id", 0, "record ID")
+	out := fs.String("out", "", "path to save the file to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 || *out == "" {
+		return fmt.Errorf("%w: download: -id and -out are required", ErrUsage)
+	}
+
+	sess, err := a.session(ctx)
+	if err != nil {
+		return err
+	}
+
+	stream, err := a.api.DownloadFile(withToken(ctx, sess.Token), &pbrpc.DownloadFileRequest{Id: int32(*id)})
+	if err != nil {
+		return err
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return errors.New("download: the server did not send the file header")
+	}
+
+	f, err := os.OpenFile(filepath.Clean(*out), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	size, err := a.receiveFile(ctx, sess, stream, header, f)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(*out)
+		return err
+	}
+
+	return a.out.message(fmt.Sprintf("saved %s (%d bytes) to %s", header.GetName(), size, *out))
+}
+
+// receiveFile writes the chunks of a download to w and returns the number of bytes written.
+// Files of zero-knowledge accounts arrive as ciphertext and are decrypted once received.
+func (a *App) receiveFile(
+	ctx context.Context,
+	sess *Session,
+	stream grpc.ServerStreamingClient[pbrpc.DownloadFileResponse],
+	header *pbrpc.DownloadFileHeader,
+	w io.Writer,
+) (int64, error) {
+	var ciphertext bytes.Buffer
+	dst := w
+	if header.GetEncrypted() != nil {
+		dst = &ciphertext
+	}
+
+	var size int64
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return size, err
+		}
+		n, err := dst.Write(msg.GetChunk())
+		size += int64(n)
+		if err != nil {
+			return size, fmt.Errorf("failed to save file: %w", err)
+		}
+	}
+
+	if header.GetEncrypted() == nil {
+		return size, nil
+	}
+
+	mk, err := a.masterKey(sess)
+	if err != nil {
+		return 0, err
+	}
+	payload := proto.Clone(header.GetEncrypted()).(*pbmodels.EncryptedPayload)
+	payload.EncryptedData = ciphertext.Bytes()
+	resp := &pbrpc.DataViewResponse{
+		Type: pbc.DataType_DATA_TYPE_BINARY_DATA,
+		Data: &pbrpc.DataViewResponse_Encrypted{Encrypted: payload},
+	}
+	if err = openResponse(ctx, mk, resp); err != nil {
+		return 0, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	data := resp.GetBinaryData().GetData()
+	if _, err = w.Write(data); err != nil {
+		return 0, fmt.Errorf("failed to save file: %w", err)
+	}
+	return int64(len(data)), nil
+}
+
+// storeSession persists the session returned by Login or Signup.
+func (a *App) storeSession(sess *Session) error {
+	if sess.Token == "" {
+		return errors.New("server returned an empty token")
+	}
+	sess.Server = a.cfg.GRPCAddress
+	return a.sessions.Save(sess)
+}
+
+// kdfToProto converts KDF parameters to their protobuf representation.
+func kdfToProto(p models.KDFParams) *pbmodels.KdfParams {
+	return &pbmodels.KdfParams{
+		Algorithm: p.Algorithm,
+		Salt:      p.Salt,
+		Time:      p.Time,
+		Memory:    p.Memory,
+		Threads:   uint32(p.Threads),
+	}
+}
+
+// kdfFromProto converts protobuf KDF parameters to the model type.
+func kdfFromProto(p *pbmodels.KdfParams) models.KDFParams {
+	return models.KDFParams{
+		Algorithm: p.GetAlgorithm(),
+		Salt:      p.GetSalt(),
+		Time:      p.GetTime(),
+		Memory:    p.GetMemory(),
+		Threads:   uint8(min(p.GetThreads(), 255)),
+	}
+}
+
+// cardRequest builds a DataSaveRequest for a bank card from the save card flags.
+func cardRequest(args []string) (*pbrpc.DataSaveRequest, error) {
+	fs := newFlagSet("save card")
+	number := fs.String("number", "", "card number")
+	expiry := fs.String("expiry", "", "expiry date (MM/YY)")
+	cvv := fs.String("cvv", "", "CVV code")
+	holder := fs.String("holder", "", "cardholder name")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if *number == "" {
+		return nil, fmt.Errorf("%w: save card: -number is required", ErrUsage)
+	}
+
+	return &pbrpc.DataSaveRequest{
+		Type: pbc.DataType_DATA_TYPE_BANK_CARD,
+		Meta: meta,
+		Data: &pbrpc.DataSaveRequest_BankCard{BankCard: &pbmodels.BankCard{
+			CardNumber: *number,
+			ExpiryDate: *expiry,
+			Cvv:        *cvv,
+			Cardholder: *holder,
+		}},
+	}, nil
+}
+
+// credentialsRequest builds a DataSaveRequest for a login/password pair from the save creds flags.
+func credentialsRequest(args []string) (*pbrpc.DataSaveRequest, error) {
+	fs := newFlagSet("save creds")
+	login := fs.String("login", "", "login")
+	pass := fs.String("password", "", "password")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if *login == "" {
+		return nil, fmt.Errorf("%w: save creds: -login is required", ErrUsage)
+	}
+
+	return &pbrpc.DataSaveRequest{
+		Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+		Meta: meta,
+		Data: &pbrpc.DataSaveRequest_Credentials{Credentials: &pbmodels.Credentials{
+			Login:    *login,
+			Password: *pass,
+		}},
+	}, nil
+}
+
+// noteRequest builds a DataSaveRequest for a secure note from the save note flags. The text is
+// given with -text or read from the file at -path, such as a Markdown runbook.
+func noteRequest(args []string) (*pbrpc.DataSaveRequest, error) {
+	fs := newFlagSet("save note")
+	text := fs.String("text", "", "text of the note")
+	path := fs.String("path", "", "path to a file with the text of the note")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if (*text == "") == (*path == "") {
+		return nil, fmt.Errorf("%w: save note: either -text or -path is required", ErrUsage)
+	}
+
+	if *path != "" {
+		data, err := os.ReadFile(filepath.Clean(*path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note: %w", err)
+		}
+		*text = string(data)
+	}
+
+	return &pbrpc.DataSaveRequest{
+		Type: pbc.DataType_DATA_TYPE_TEXT,
+		Meta: meta,
+		Data: &pbrpc.DataSaveRequest_SecureNote{SecureNote: &pbmodels.SecureNote{Text: *text}},
+	}, nil
+}
+
+// otpAlgorithms maps the values of the save otp -algorithm flag to OTP algorithms.
+var otpAlgorithms = map[string]pbc.OtpAlgorithm{
+	totp.AlgorithmSHA1:   pbc.OtpAlgorithm_OTP_ALGORITHM_SHA1,
+	totp.AlgorithmSHA256: pbc.OtpAlgorithm_OTP_ALGORITHM_SHA256,
+	totp.AlgorithmSHA512: pbc.OtpAlgorithm_OTP_ALGORITHM_SHA512,
+}
+
+// otpRequest builds a DataSaveRequest for a one-time password seed from the save otp flags.
+// The seed is given with -uri, as read from an enrollment QR code, or with -seed and the
+// code parameters. It is checked here, since the server cannot check the seeds of
+// zero-knowledge accounts.
+func otpRequest(args []string) (*pbrpc.DataSaveRequest, error) {
+	fs := newFlagSet("save otp")
+	uri := fs.String("uri", "", "otpauth://totp/ URI")
+	secret := fs.String("seed", "", "base32-encoded secret")
+	algorithm := fs.String("algorithm", totp.AlgorithmSHA1, "hash algorithm: SHA1, SHA256 or SHA512")
+	digits := fs.Int("digits", 6, "number of digits of a code")
+	period := fs.Int("period", 30, "seconds a code is valid")
+	issuer := fs.String("issuer", "", "service the seed belongs to")
+	account := fs.String("account", "", "account at the service")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if (*uri == "") == (*secret == "") {
+		return nil, fmt.Errorf("%w: save otp: either -uri or -seed is required", ErrUsage)
+	}
+
+	key := &totp.Key{
+		Issuer:  *issuer,
+		Account: *account,
+		Secret:  strings.ToUpper(strings.ReplaceAll(*secret, " ", "")),
+		Opts: totp.Opts{
+			Algorithm: strings.ToUpper(*algorithm),
+			Digits:    *digits,
+			Period:    time.Duration(*period) * time.Second,
+		},
+	}
+	if *uri != "" {
+		var err error
+		if key, err = totp.ParseURI(*uri); err != nil {
+			return nil, err
+		}
+	}
+	if err := totp.Check(key.Secret, key.Opts); err != nil {
+		return nil, fmt.Errorf("%w: save otp: invalid seed or parameters: %v", ErrUsage, err)
+	}
+
+	return &pbrpc.DataSaveRequest{
+		Type: pbc.DataType_DATA_TYPE_OTP,
+		Meta: meta,
+		Data: &pbrpc.DataSaveRequest_Otp{Otp: &pbmodels.Otp{
+			Secret:    key.Secret,
+			Algorithm: otpAlgorithms[key.Opts.Algorithm],
+			Digits:    int32(key.Opts.Digits),
+			Period:    int32(key.Opts.Period / time.Second),
+			Issuer:    key.Issuer,
+			Account:   key.Account,
+		}},
+	}, nil
+}
+
+// sshKeyRequest builds a DataSaveRequest for an SSH key pair from the save ssh flags. The public
+// key and fingerprint are derived here, since the server cannot read the keys of zero-knowledge
+// accounts; a public key, given with -public or found next to the private key, must match it.
+func sshKeyRequest(args []string) (*pbrpc.DataSaveRequest, error) {
+	fs := newFlagSet("save ssh")
+	keyPath := fs.String("key", "", "path to the private key, such as ~/.ssh/id_ed25519")
+	publicPath := fs.String("public", "", "path to the public key, the -key path with .pub appended by default")
+	comment := fs.String("comment", "", "comment of the key, the one of the public key by default")
+	passphrase := fs.String("passphrase", "", "passphrase of an encrypted private key")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if *keyPath == "" {
+		return nil, fmt.Errorf("%w: save ssh: -key is required", ErrUsage)
+	}
+
+	private, err := os.ReadFile(filepath.Clean(*keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	var public []byte
+	if *publicPath != "" {
+		if public, err = os.ReadFile(filepath.Clean(*publicPath)); err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+	} else if data, errRead := os.ReadFile(filepath.Clean(*keyPath) + ".pub"); errRead == nil {
+		// Like ssh-add, pick up the public key that ssh-keygen writes next to the private one
+		public = data
+	}
+
+	info, err := sshkey.Inspect(string(private), string(public), *passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if *comment == "" {
+		*comment = info.Comment
+	}
+
+	return &pbrpc.DataSaveRequest{
+		Type: pbc.DataType_DATA_TYPE_SSH_KEY,
+		Meta: meta,
+		Data: &pbrpc.DataSaveRequest_SshKey{SshKey: &pbmodels.SshKey{
+			PrivateKey:  string(private),
+			PublicKey:   info.PublicKey,
+			Comment:     *comment,
+			Passphrase:  *passphrase,
+			Fingerprint: info.Fingerprint,
+		}},
+	}, nil
+}
+
+// otpOpts returns the code parameters of otp.
+func otpOpts(otp *pbmodels.Otp) totp.Opts {
+	opts := totp.Opts{
+		Digits: int(otp.GetDigits()),
+		Period: time.Duration(otp.GetPeriod()) * time.Second,
+	}
+	for name, algorithm := range otpAlgorithms {
+		if algorithm == otp.GetAlgorithm() {
+			opts.Algorithm = name
+		}
+	}
+	return opts
+}
+
+// fileRequest builds a DataSaveRequest for a local file from the save file flags.
+func fileRequest(args []string) (*pbrpc.DataSaveRequest, error) {
+	fs := newFlagSet("save file")
+	path := fs.String("path", "", "path to the file")
+	meta := metaFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if *path == "" {
+		return nil, fmt.Errorf("%w: save file: -path is required", ErrUsage)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(*path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return &pbrpc.DataSaveRequest{
+		Type: pbc.DataType_DATA_TYPE_BINARY_DATA,
+		Meta: meta,
+		Data: &pbrpc.DataSaveRequest_BinaryData{BinaryData: &pbmodels.File{
+			Name: filepath.Base(*path),
+			Type: contentType(*path, data),
+			Size: int32(len(data)),
+			Data: data,
+		}},
+	}, nil
+}
+
+// updateRequest turns a DataSaveRequest built from the save flags into a DataUpdateRequest for
+// record id. Empty meta is left out, so the server keeps the current one; otherwise the meta
+// flags replace the whole meta.
+func updateRequest(id int32, req *pbrpc.DataSaveRequest) *pbrpc.DataUpdateRequest {
+	update := &pbrpc.DataUpdateRequest{Id: id}
+	if !proto.Equal(req.GetMeta(), &pbmodels.Meta{}) {
+		update.Meta = req.GetMeta()
+	}
+
+	switch d := req.GetData().(type) {
+	case *pbrpc.DataSaveRequest_BankCard:
+		update.Data = &pbrpc.DataUpdateRequest_BankCard{BankCard: d.BankCard}
+	case *pbrpc.DataSaveRequest_Credentials:
+		update.Data = &pbrpc.DataUpdateRequest_Credentials{Credentials: d.Credentials}
+	case *pbrpc.DataSaveRequest_BinaryData:
+		update.Data = &pbrpc.DataUpdateRequest_BinaryData{BinaryData: d.BinaryData}
+	case *pbrpc.DataSaveRequest_SecureNote:
+		update.Data = &pbrpc.DataUpdateRequest_SecureNote{SecureNote: d.SecureNote}
+	case *pbrpc.DataSaveRequest_Otp:
+		update.Data = &pbrpc.DataUpdateRequest_Otp{Otp: d.Otp}
+	case *pbrpc.DataSaveRequest_SshKey:
+		update.Data = &pbrpc.DataUpdateRequest_SshKey{SshKey: d.SshKey}
+	case *pbrpc.DataSaveRequest_Encrypted:
+		update.Data = &pbrpc.DataUpdateRequest_Encrypted{Encrypted: d.Encrypted}
+	}
+
+	return update
+}
+
+// fieldTypes maps the types accepted by the -field and -secret flags to custom field types.
+var fieldTypes = map[string]pbc.CustomFieldType{
+	"text":    pbc.CustomFieldType_CUSTOM_FIELD_TYPE_TEXT,
+	"number":  pbc.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER,
+	"boolean": pbc.CustomFieldType_CUSTOM_FIELD_TYPE_BOOLEAN,
+	"date":    pbc.CustomFieldType_CUSTOM_FIELD_TYPE_DATE,
+	"url":     pbc.CustomFieldType_CUSTOM_FIELD_TYPE_URL,
+	"email":   pbc.CustomFieldType_CUSTOM_FIELD_TYPE_EMAIL,
+}
+
+// metaFlags registers the flags describing a record on fs and returns the meta they fill in
+// while fs is parsed.
+func metaFlags(fs *flag.FlagSet) *pbmodels.Meta {
+	meta := &pbmodels.Meta{}
+	fs.StringVar(&meta.Content, "meta", "", "free-form description")
+	fs.StringVar(&meta.Title, "title", "", "title of the record")
+	fs.BoolVar(&meta.Favorite, "favorite", false, "mark the record as favorite")
+	fs.Func("tag", "tag of the record; repeat the flag or separate tags with commas", func(v string) error {
+		meta.Tags = append(meta.Tags, splitList(v)...)
+		return nil
+	})
+	fs.Func("url", "URL associated with the record; repeatable", func(v string) error {
+		meta.Urls = append(meta.Urls, v)
+		return nil
+	})
+	addField := func(sensitive bool) func(string) error {
+		return func(v string) error {
+			field, err := parseField(v)
+			if err != nil {
+				return err
+			}
+			field.Sensitive = sensitive
+			meta.Fields = append(meta.Fields, field)
+			return nil
+		}
+	}
+	fs.Func("field", "custom field as name[:type]=value, type being text, number, boolean, date, url or email; repeatable",
+		addField(false))
+	fs.Func("secret", "sensitive custom field as name[:type]=value, stored encrypted; repeatable", addField(true))
+	return meta
+}
+
+// parseField parses a custom field given as name[:type]=value.
+func parseField(v string) (*pbmodels.CustomField, error) {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok {
+		return nil, errors.New("expected name[:type]=value")
+	}
+	field := &pbmodels.CustomField{Name: key, Value: value}
+	if name, typeName, typed := strings.Cut(key, ":"); typed {
+		t, found := fieldTypes[typeName]
+		if !found {
+			return nil, fmt.Errorf("unknown field type %q", typeName)
+		}
+		field.Name, field.Type = name, t
+	}
+	return field, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// contentType guesses the MIME type of a file from its extension, falling back to content sniffing.
+func contentType(path string, data []byte) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,135 @@
+// Package client implements the GophKeeper command-line client.
+//
+// The client talks to the GophKeeper gRPC service through the generated protogen stubs,
+// keeps the JWT between invocations in a local session file and renders records either
+// as human-readable text or as JSON.
+package client
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/caarlos0/env/v11"
+	"gopkg.in/yaml.v3"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/utils"
+)
+
+// Output formats supported by the client.
+const (
+	// OutputText renders records as human-readable tables.
+	OutputText = "text"
+	// OutputJSON renders records as protojson documents.
+	OutputJSON = "json"
+)
+
+// ErrUsage is returned when the command line cannot be parsed.
+var ErrUsage = errors.New("invalid usage")
+
+// Config holds the client settings, loaded from a YAML file or environment variables
+// and overridden by global command-line flags.
+type Config struct {
+	// ConfigFile is the path to the YAML configuration file.
+	ConfigFile string `env:"CONFIG_FILE" yaml:"CONFIG_FILE"`
+	// GRPCAddress is the address of the GophKeeper gRPC server.
+	GRPCAddress string `env:"GRPC_ADDRESS" yaml:"GRPC_ADDRESS" envDefault:"localhost:13007" validate:"required"`
+	// SessionFile is the path where the JWT is persisted between invocations.
+	SessionFile string `env:"SESSION_FILE" yaml:"SESSION_FILE"`
+	// Output is the output format: text or json.
+	Output string `env:"OUTPUT" yaml:"OUTPUT" envDefault:"text" validate:"oneof=text json"`
+	// TLSConfig holds TLS settings; CertPath is the certificate used to verify the server.
+	TLSConfig config.TLSConfig `yaml:"TLS"`
+}
+
+// NewConfig loads the client configuration and parses the global flags in args.
+//
+// Global flags must precede the command name. The remaining arguments (command and its flags)
+// are returned alongside the configuration.
+func NewConfig(args []string) (*Config, []string, error) {
+	var (
+		cfg        Config
+		configFile string
+		addr       string
+		session    string
+		jsonOut    bool
+		insecure   bool
+		caCert     string
+	)
+
+	fs := flag.NewFlagSet("gophkeeper", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&configFile, "f", "", "config.yaml or config.yml")
+	fs.StringVar(&addr, "addr", "", "gRPC server address")
+	fs.StringVar(&session, "session", "", "path to the session file")
+	fs.BoolVar(&jsonOut, "json", false, "print output as JSON")
+	fs.BoolVar(&insecure, "insecure", false, "disable TLS")
+	fs.StringVar(&caCert, "ca", "", "certificate used to verify the server")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+
+	if configFile != "" {
+		if err := readConfigFile(configFile, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("failed to load config from file: %w", err)
+		}
+	} else {
+		// TLS is enabled by default, the same way the server is deployed.
+		cfg.TLSConfig.EnableHTTPS = true
+		if err := env.Parse(&cfg); err != nil {
+			return nil, nil, fmt.Errorf("failed to load environment: %w", err)
+		}
+	}
+	cfg.ConfigFile = configFile
+
+	if addr != "" {
+		cfg.GRPCAddress = addr
+	}
+	if session != "" {
+		cfg.SessionFile = session
+	}
+	if jsonOut {
+		cfg.Output = OutputJSON
+	}
+	if insecure {
+		cfg.TLSConfig.EnableHTTPS = false
+	}
+	if caCert != "" {
+		cfg.TLSConfig.CertPath = caCert
+	}
+	if cfg.Output == "" {
+		cfg.Output = OutputText
+	}
+	if cfg.SessionFile == "" {
+		cfg.SessionFile = defaultSessionFile()
+	}
+
+	if err := utils.ValidateStruct(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, fs.Args(), nil
+}
+
+// readConfigFile loads configuration from the YAML file at path into cfg.
+func readConfigFile(path string, cfg *Config) error {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, cfg)
+}
+
+// defaultSessionFile returns the session file location inside the user's config directory.
+func defaultSessionFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gophkeeper", "session.json")
+}
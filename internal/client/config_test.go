@@ -0,0 +1,55 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig_FlagsOverrideEnv(t *testing.T) {
+	t.Setenv("GRPC_ADDRESS", "example.com:443")
+
+	cfg, rest, err := NewConfig([]string{"-addr", "localhost:13007", "-json", "-insecure", "list"})
+	require.NoError(t, err)
+	require.Equal(t, "localhost:13007", cfg.GRPCAddress)
+	require.Equal(t, OutputJSON, cfg.Output)
+	require.False(t, cfg.TLSConfig.EnableHTTPS)
+	require.NotEmpty(t, cfg.SessionFile)
+	require.Equal(t, []string{"list"}, rest)
+}
+
+func TestNewConfig_Defaults(t *testing.T) {
+	cfg, _, err := NewConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, "localhost:13007", cfg.GRPCAddress)
+	require.Equal(t, OutputText, cfg.Output)
+	require.True(t, cfg.TLSConfig.EnableHTTPS)
+}
+
+func TestNewConfig_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client.yaml")
+	content := `
+GRPC_ADDRESS: "vault.example.com:13007"
+SESSION_FILE: "/tmp/gk-session.json"
+TLS:
+  TLS_ENABLE_HTTPS: true
+  TLS_CERT_PATH: "certs/server.crt"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, _, err := NewConfig([]string{"-f", path})
+	require.NoError(t, err)
+	require.Equal(t, "vault.example.com:13007", cfg.GRPCAddress)
+	require.Equal(t, "/tmp/gk-session.json", cfg.SessionFile)
+	require.Equal(t, "certs/server.crt", cfg.TLSConfig.CertPath)
+}
+
+func TestNewConfig_Errors(t *testing.T) {
+	_, _, err := NewConfig([]string{"-unknown"})
+	require.ErrorIs(t, err, ErrUsage)
+
+	_, _, err = NewConfig([]string{"-f", "/nonexistent/client.yaml"})
+	require.ErrorContains(t, err, "failed to load config from file")
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/pkg/version"
+)
+
+// Dial opens a gRPC connection to the server described by cfg.
+//
+// With TLS enabled the server certificate is verified against TLS_CERT_PATH when it is set
+// (the same certificate the server and its HTTP gateway use), or against the system roots otherwise.
+func Dial(cfg *Config) (*grpc.ClientConn, error) {
+	creds, err := transportCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(
+		cfg.GRPCAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(clientInfoInterceptor(deviceName(), version.Version())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.GRPCAddress, err)
+	}
+
+	return conn, nil
+}
+
+// transportCredentials builds the gRPC transport credentials for cfg.
+func transportCredentials(cfg *Config) (credentials.TransportCredentials, error) {
+	if !cfg.TLSConfig.EnableHTTPS {
+		return insecure.NewCredentials(), nil
+	}
+
+	if cfg.TLSConfig.CertPath == "" {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+	}
+
+	caCert, err := os.ReadFile(filepath.Clean(cfg.TLSConfig.CertPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA cert: %w", err)
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("failed to append CA cert")
+	}
+
+	return credentials.NewClientTLSFromCert(certPool, ""), nil
+}
+
+// clientInfoInterceptor attaches the device name and client version to every request, so the
+// server can show them in the list of sessions.
+func clientInfoInterceptor(device, clientVersion string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			string(constants.DeviceName), device,
+			string(constants.ClientVersion), "gophkeeper-cli/"+clientVersion,
+		)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// deviceName returns the host name of this machine, or "unknown" if it cannot be read.
+func deviceName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "unknown"
+	}
+	return host
+}
+
+// withToken attaches the JWT to the outgoing request metadata.
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, string(constants.JWT), token)
+}
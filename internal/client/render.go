@@ -0,0 +1,402 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// printer renders command results in the configured output format.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// newPrinter creates a printer writing to w in the given format (OutputText or OutputJSON).
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, json: format == OutputJSON}
+}
+
+// message prints a plain status message.
+func (p *printer) message(msg string) error {
+	if p.json {
+		return p.writeJSON(map[string]string{"message": msg})
+	}
+	_, err := fmt.Fprintln(p.w, msg)
+	return err
+}
+
+// user prints the authenticated user returned by Login or Signup.
+func (p *printer) user(id int32, username string) error {
+	if p.json {
+		return p.writeJSON(map[string]any{"id": id, "username": username})
+	}
+	_, err := fmt.Fprintf(p.w, "logged in as %s (id %d)\n", username, id)
+	return err
+}
+
+// totpEnrollment prints the secret returned by EnrollTOTP for adding to an authenticator app.
+func (p *printer) totpEnrollment(resp *pbrpcu.EnrollTOTPResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+	_, err := fmt.Fprintf(p.w, "add this account to your authenticator app:\n  %s\nsecret: %s\n"+
+		"then run: gophkeeper totp confirm -code <code>\n", resp.GetOtpauthUri(), resp.GetSecret())
+	return err
+}
+
+// recoveryCodes prints the recovery codes returned by ConfirmTOTP.
+func (p *printer) recoveryCodes(codes []string) error {
+	if p.json {
+		return p.writeJSON(map[string][]string{"recovery_codes": codes})
+	}
+
+	var b strings.Builder
+	b.WriteString("two-factor authentication enabled; store these one-time recovery codes safely:\n")
+	for _, c := range codes {
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	_, err := io.WriteString(p.w, b.String())
+	return err
+}
+
+// otp prints the codes of an OTP record.
+func (p *printer) otp(resp *pbrpc.GenerateOTPResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+	_, err := fmt.Fprintf(p.w, "%s  (valid for %ds, next %s)\n", resp.GetCode(), resp.GetExpiresIn(), resp.GetNextCode())
+	return err
+}
+
+// sessions prints the sessions returned by ListSessions, marking the current one.
+func (p *printer) sessions(resp *pbrpcu.ListSessionsResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tDEVICE\tCLIENT\tIP\tLAST SEEN\t")
+	for _, s := range resp.GetSessions() {
+		current := ""
+		if s.GetCurrent() {
+			current = "(current)"
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			s.GetId(), s.GetDeviceName(), s.GetClientVersion(), s.GetIp(), s.GetLastSeenAt(), current)
+	}
+	return tw.Flush()
+}
+
+// list prints the records returned by DataList.
+func (p *printer) list(resp *pbrpc.DataListResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	if len(resp.GetRecords()) == 0 {
+		_, err := fmt.Fprintln(p.w, "no records")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tTYPE\tCREATED\tFOLDER\tMETA")
+	for _, r := range resp.GetRecords() {
+		folder := "-"
+		if r.GetFolderId() != 0 {
+			folder = strconv.Itoa(int(r.GetFolderId()))
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", r.GetId(), r.GetType(), r.GetCreatedAt(), folder, metaSummary(r.GetMeta()))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if resp.GetNextCursor() == "" {
+		return nil
+	}
+	_, err := fmt.Fprintf(p.w, "%d of %d records; next page: -cursor %s\n",
+		len(resp.GetRecords()), resp.GetCount(), resp.GetNextCursor())
+	return err
+}
+
+// search prints the results returned by Search, with the matched words in brackets.
+func (p *printer) search(resp *pbrpc.SearchResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	if len(resp.GetResults()) == 0 {
+		_, err := fmt.Fprintln(p.w, "nothing found")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tTYPE\tCREATED\tMATCH")
+	for _, r := range resp.GetResults() {
+		matches := make([]string, 0, len(r.GetHighlights()))
+		for _, h := range r.GetHighlights() {
+			matches = append(matches, h.GetField()+": "+markRanges(h.GetValue(), h.GetRanges()))
+		}
+		if len(matches) == 0 {
+			matches = append(matches, metaSummary(r.GetRecord().GetMeta()))
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n",
+			r.GetRecord().GetId(), r.GetRecord().GetType(), r.GetRecord().GetCreatedAt(), strings.Join(matches, "; "))
+	}
+	return tw.Flush()
+}
+
+// markRanges wraps the ranges of value, given in code points, in brackets.
+func markRanges(value string, ranges []*pbrpc.SearchRange) string {
+	runes := []rune(value)
+	var b strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		start, end := int(r.GetStart()), int(r.GetEnd())
+		if start < pos || end > len(runes) || start >= end {
+			continue
+		}
+		b.WriteString(string(runes[pos:start]))
+		b.WriteString("[" + string(runes[start:end]) + "]")
+		pos = end
+	}
+	b.WriteString(string(runes[pos:]))
+	return b.String()
+}
+
+// trash prints the records returned by ListTrash.
+func (p *printer) trash(resp *pbrpc.ListTrashResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	if len(resp.GetRecords()) == 0 {
+		_, err := fmt.Fprintln(p.w, "trash is empty")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tTYPE\tDELETED\tPURGED AFTER\tMETA")
+	for _, r := range resp.GetRecords() {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n",
+			r.GetRecord().GetId(), r.GetRecord().GetType(), r.GetDeletedAt(), r.GetPurgeAt(), metaSummary(r.GetRecord().GetMeta()))
+	}
+	return tw.Flush()
+}
+
+// folders prints the folders returned by ListFolders as a tree, subfolders indented under
+// their parents.
+func (p *printer) folders(resp *pbrpc.ListFoldersResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	if len(resp.GetFolders()) == 0 {
+		_, err := fmt.Fprintln(p.w, "no folders")
+		return err
+	}
+
+	known := make(map[int32]bool, len(resp.GetFolders()))
+	for _, f := range resp.GetFolders() {
+		known[f.GetId()] = true
+	}
+	children := make(map[int32][]*pbrpc.Folder)
+	for _, f := range resp.GetFolders() {
+		parent := f.GetParentId()
+		if !known[parent] {
+			parent = 0
+		}
+		children[parent] = append(children[parent], f)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tNAME\tUPDATED")
+	var walk func(parent int32, depth int)
+	walk = func(parent int32, depth int) {
+		for _, f := range children[parent] {
+			_, _ = fmt.Fprintf(tw, "%d\t%s%s\t%s\n", f.GetId(), strings.Repeat("  ", depth), f.GetName(), f.GetUpdatedAt())
+			walk(f.GetId(), depth+1)
+		}
+	}
+	walk(0, 0)
+	return tw.Flush()
+}
+
+// versions prints the prior versions returned by ListRecordVersions.
+func (p *printer) versions(resp *pbrpc.ListRecordVersionsResponse) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	_, _ = fmt.Fprintf(p.w, "current version %d, keeping %d prior versions\n", resp.GetCurrentVersion(), resp.GetRetention())
+	if len(resp.GetVersions()) == 0 {
+		_, err := fmt.Fprintln(p.w, "no prior versions")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "VERSION\tWRITTEN\tREPLACED\tMETA")
+	for _, v := range resp.GetVersions() {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", v.GetVersion(), v.GetCreatedAt(), v.GetArchivedAt(), metaSummary(v.GetMeta()))
+	}
+	return tw.Flush()
+}
+
+// view prints a single record returned by DataView.
+//
+// savedTo is the path the file contents were written to, if any; in that case the
+// raw bytes are not printed.
+func (p *printer) view(id int32, resp *pbrpc.DataViewResponse, savedTo string) error {
+	if p.json {
+		return p.writeProto(resp)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "ID:\t%d\n", id)
+	_, _ = fmt.Fprintf(tw, "Type:\t%s\n", typeLabel(resp.GetType()))
+	printMeta(tw, resp.GetMeta())
+
+	switch data := resp.GetData().(type) {
+	case *pbrpc.DataViewResponse_BankCard:
+		_, _ = fmt.Fprintf(tw, "Card number:\t%s\n", data.BankCard.GetCardNumber())
+		_, _ = fmt.Fprintf(tw, "Expiry date:\t%s\n", data.BankCard.GetExpiryDate())
+		_, _ = fmt.Fprintf(tw, "CVV:\t%s\n", data.BankCard.GetCvv())
+		_, _ = fmt.Fprintf(tw, "Cardholder:\t%s\n", data.BankCard.GetCardholder())
+	case *pbrpc.DataViewResponse_Credentials:
+		_, _ = fmt.Fprintf(tw, "Login:\t%s\n", data.Credentials.GetLogin())
+		_, _ = fmt.Fprintf(tw, "Password:\t%s\n", data.Credentials.GetPassword())
+	case *pbrpc.DataViewResponse_BinaryData:
+		_, _ = fmt.Fprintf(tw, "File name:\t%s\n", data.BinaryData.GetName())
+		_, _ = fmt.Fprintf(tw, "Content type:\t%s\n", data.BinaryData.GetType())
+		_, _ = fmt.Fprintf(tw, "Size:\t%d bytes\n", data.BinaryData.GetSize())
+		if savedTo != "" {
+			_, _ = fmt.Fprintf(tw, "Saved to:\t%s\n", savedTo)
+		} else {
+			_, _ = fmt.Fprintln(tw, "Contents:\tuse -out to save the file")
+		}
+	case *pbrpc.DataViewResponse_Otp:
+		_, _ = fmt.Fprintf(tw, "Issuer:\t%s\n", data.Otp.GetIssuer())
+		_, _ = fmt.Fprintf(tw, "Account:\t%s\n", data.Otp.GetAccount())
+		_, _ = fmt.Fprintf(tw, "Secret:\t%s\n", data.Otp.GetSecret())
+		_, _ = fmt.Fprintf(tw, "Algorithm:\t%s\n", strings.TrimPrefix(data.Otp.GetAlgorithm().String(), "OTP_ALGORITHM_"))
+		_, _ = fmt.Fprintf(tw, "Digits:\t%d\n", data.Otp.GetDigits())
+		_, _ = fmt.Fprintf(tw, "Period:\t%ds\n", data.Otp.GetPeriod())
+	case *pbrpc.DataViewResponse_SshKey:
+		_, _ = fmt.Fprintf(tw, "Public key:\t%s\n", data.SshKey.GetPublicKey())
+		_, _ = fmt.Fprintf(tw, "Comment:\t%s\n", data.SshKey.GetComment())
+		_, _ = fmt.Fprintf(tw, "Fingerprint:\t%s\n", data.SshKey.GetFingerprint())
+		if data.SshKey.GetPassphrase() != "" {
+			_, _ = fmt.Fprintf(tw, "Passphrase:\t%s\n", data.SshKey.GetPassphrase())
+		}
+		if savedTo != "" {
+			_, _ = fmt.Fprintf(tw, "Saved to:\t%s\n", savedTo)
+		} else {
+			_, _ = fmt.Fprintln(tw, "Private key:\tuse -out to save it")
+		}
+	case *pbrpc.DataViewResponse_SecureNote:
+		// Notes span lines, so the text follows the table as is
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(p.w, "\n%s\n", strings.TrimRight(data.SecureNote.GetText(), "\n"))
+		return err
+	}
+
+	return tw.Flush()
+}
+
+// metaSummary describes a record in one line: its title, or the first line of its description
+// without one, followed by its tags; favorites are marked with a star.
+func metaSummary(meta *pbmodels.Meta) string {
+	summary := meta.GetTitle()
+	if summary == "" {
+		summary, _, _ = strings.Cut(meta.GetContent(), "\n")
+	}
+	if meta.GetFavorite() {
+		summary = "* " + summary
+	}
+	for _, tag := range meta.GetTags() {
+		summary += " #" + tag
+	}
+	return strings.TrimSpace(summary)
+}
+
+// printMeta prints the non-empty parts of meta as rows of tw.
+func printMeta(tw io.Writer, meta *pbmodels.Meta) {
+	if meta.GetTitle() != "" {
+		_, _ = fmt.Fprintf(tw, "Title:\t%s\n", meta.GetTitle())
+	}
+	_, _ = fmt.Fprintf(tw, "Meta:\t%s\n", meta.GetContent())
+	if meta.GetFavorite() {
+		_, _ = fmt.Fprintln(tw, "Favorite:\tyes")
+	}
+	if len(meta.GetTags()) > 0 {
+		_, _ = fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(meta.GetTags(), ", "))
+	}
+	for _, u := range meta.GetUrls() {
+		_, _ = fmt.Fprintf(tw, "URL:\t%s\n", u)
+	}
+	for _, f := range meta.GetFields() {
+		_, _ = fmt.Fprintf(tw, "%s:\t%s\n", f.GetName(), f.GetValue())
+	}
+}
+
+// writeProto prints a protobuf message as indented JSON.
+//
+// protojson deliberately randomizes its whitespace, so the output is re-indented
+// with encoding/json to keep it stable for scripts.
+func (p *printer) writeProto(m proto.Message) error {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		return fmt.Errorf("failed to format response: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	_, err = buf.WriteTo(p.w)
+	return err
+}
+
+// writeJSON prints v as indented JSON.
+func (p *printer) writeJSON(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// typeLabel returns a human-readable name for a data type.
+func typeLabel(dt pbc.DataType) string {
+	switch dt {
+	case pbc.DataType_DATA_TYPE_BANK_CARD:
+		return "bank card"
+	case pbc.DataType_DATA_TYPE_CREDENTIALS:
+		return "credentials"
+	case pbc.DataType_DATA_TYPE_BINARY_DATA:
+		return "file"
+	case pbc.DataType_DATA_TYPE_TEXT:
+		return "note"
+	case pbc.DataType_DATA_TYPE_OTP:
+		return "one-time password"
+	case pbc.DataType_DATA_TYPE_SSH_KEY:
+		return "ssh key"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// ErrNotLoggedIn is returned when a command requires a session but none is stored.
+var ErrNotLoggedIn = errors.New("not logged in: run `gophkeeper login` first")
+
+// Session is the authenticated state persisted between client invocations.
+type Session struct {
+	// Server is the gRPC address the token was issued by.
+	Server string `json:"server"`
+	// UserID is the authenticated user's ID.
+	UserID int32 `json:"user_id"`
+	// Username is the authenticated user's login name.
+	Username string `json:"username"`
+	// Token is the JWT sent with every protected request.
+	Token string `json:"token"`
+	// RefreshToken is exchanged for a new Token once it expires.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// ExpiresAt is when Token expires.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// ZeroKnowledge marks accounts whose data is encrypted by the client.
+	ZeroKnowledge bool `json:"zero_knowledge,omitempty"`
+	// KDF holds the parameters used to derive the client keys from the password.
+	KDF *models.KDFParams `json:"kdf,omitempty"`
+	// WrappedMasterKey is the master key sealed with the client encryption key.
+	WrappedMasterKey []byte `json:"wrapped_master_key,omitempty"`
+}
+
+// setTokens stores freshly issued tokens in the session.
+func (s *Session) setTokens(token, refreshToken string, expiresIn int64) {
+	s.Token, s.RefreshToken = token, refreshToken
+	s.ExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
+
+// SessionStore reads and writes the session file.
+type SessionStore struct {
+	path string
+}
+
+// NewSessionStore creates a SessionStore backed by the file at path.
+func NewSessionStore(path string) *SessionStore {
+	return &SessionStore{path: path}
+}
+
+// Load reads the stored session. It returns ErrNotLoggedIn if no session file exists.
+func (s *SessionStore) Load() (*Session, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotLoggedIn
+		}
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var sess Session
+	if err := json.Unmarshal(b, &sess); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	if sess.Token == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	return &sess, nil
+}
+
+// Save writes the session to disk, readable by the current user only.
+func (s *SessionStore) Save(sess *Session) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	b, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the stored session. Removing a missing session is not an error.
+func (s *SessionStore) Remove() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/models"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// zeroKnowledgeSignup prepares the key material for a new zero-knowledge account.
+//
+// It returns the client keys derived from password and the request fields carrying the
+// KDF parameters and the freshly generated master key wrapped with the encryption key.
+func zeroKnowledgeSignup(password string) (*crypto.ClientKeys, models.KDFParams, []byte, error) {
+	params, err := crypto.NewKDFParams()
+	if err != nil {
+		return nil, models.KDFParams{}, nil, err
+	}
+
+	keys, err := crypto.DeriveClientKeys(password, params)
+	if err != nil {
+		return nil, models.KDFParams{}, nil, err
+	}
+
+	mk, err := crypto.NewMasterKey()
+	if err != nil {
+		return nil, models.KDFParams{}, nil, err
+	}
+
+	wrapped, err := crypto.WrapKey(keys.EncryptionKey, mk)
+	if err != nil {
+		return nil, models.KDFParams{}, nil, err
+	}
+
+	return keys, params, wrapped, nil
+}
+
+// masterKey asks for the password and unwraps the master key of a zero-knowledge session.
+func (a *App) masterKey(sess *Session) ([]byte, error) {
+	if sess.KDF == nil || len(sess.WrappedMasterKey) == 0 {
+		return nil, fmt.Errorf("session has no key material: log in again")
+	}
+
+	password, err := a.password("", "Master password: ")
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := crypto.DeriveClientKeys(password, *sess.KDF)
+	if err != nil {
+		return nil, err
+	}
+
+	mk, err := crypto.UnwrapKey(keys.EncryptionKey, sess.WrappedMasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrong master password")
+	}
+
+	return mk, nil
+}
+
+// rewrapForPassword rewrites a ChangePasswordRequest of a zero-knowledge session.
+//
+// It opens the master key with the current password, wraps it with keys derived from the new
+// password and fresh KDF parameters, and replaces both passwords with their authentication keys.
+func rewrapForPassword(sess *Session, req *pbrpcu.ChangePasswordRequest) error {
+	if sess.KDF == nil || len(sess.WrappedMasterKey) == 0 {
+		return fmt.Errorf("session has no key material: log in again")
+	}
+
+	oldKeys, err := crypto.DeriveClientKeys(req.GetOldPassword(), *sess.KDF)
+	if err != nil {
+		return err
+	}
+
+	mk, err := crypto.UnwrapKey(oldKeys.EncryptionKey, sess.WrappedMasterKey)
+	if err != nil {
+		return fmt.Errorf("wrong current password")
+	}
+
+	params, err := crypto.NewKDFParams()
+	if err != nil {
+		return err
+	}
+
+	newKeys, err := crypto.DeriveClientKeys(req.GetNewPassword(), params)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := crypto.WrapKey(newKeys.EncryptionKey, mk)
+	if err != nil {
+		return err
+	}
+
+	req.OldPassword = oldKeys.AuthKey
+	req.NewPassword = newKeys.AuthKey
+	req.Kdf = kdfToProto(params)
+	req.WrappedMasterKey = wrapped
+
+	return nil
+}
+
+// sealRequest replaces the plaintext data of req with a payload encrypted under mk.
+func sealRequest(ctx context.Context, mk []byte, req *pbrpc.DataSaveRequest) error {
+	var data proto.Message
+	switch d := req.GetData().(type) {
+	case *pbrpc.DataSaveRequest_BankCard:
+		data = d.BankCard
+	case *pbrpc.DataSaveRequest_Credentials:
+		data = d.Credentials
+	case *pbrpc.DataSaveRequest_BinaryData:
+		data = d.BinaryData
+	case *pbrpc.DataSaveRequest_SecureNote:
+		data = d.SecureNote
+	case *pbrpc.DataSaveRequest_Otp:
+		data = d.Otp
+	case *pbrpc.DataSaveRequest_SshKey:
+		data = d.SshKey
+	default:
+		return fmt.Errorf("unsupported data for type %s", req.GetType())
+	}
+
+	serialized, err := proto.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("serialize error: %w", err)
+	}
+
+	encrypted, err := crypto.NewEnvelope(nil).EncryptUserData(ctx, mk, serialized)
+	if err != nil {
+		return fmt.Errorf("encrypt error: %w", err)
+	}
+
+	req.Data = &pbrpc.DataSaveRequest_Encrypted{Encrypted: &pbmodels.EncryptedPayload{
+		EncryptedData: encrypted.EncryptedData,
+		DataNonce:     encrypted.DataNonce,
+		EncryptedDek:  encrypted.EncryptedDek,
+		DekNonce:      encrypted.DekNonce,
+	}}
+
+	return nil
+}
+
+// openResponse decrypts an EncryptedPayload in resp under mk and replaces it with the typed data.
+func openResponse(ctx context.Context, mk []byte, resp *pbrpc.DataViewResponse) error {
+	payload := resp.GetEncrypted()
+	if payload == nil {
+		return nil
+	}
+
+	plain, err := crypto.NewEnvelope(nil).DecryptUserData(ctx, models.DBUserData{
+		EncryptedData: payload.GetEncryptedData(),
+		DataNonce:     payload.GetDataNonce(),
+		EncryptedDek:  payload.GetEncryptedDek(),
+		DekNonce:      payload.GetDekNonce(),
+	}, mk)
+	if err != nil {
+		return err
+	}
+
+	switch resp.GetType() {
+	case pbc.DataType_DATA_TYPE_BANK_CARD:
+		var card pbmodels.BankCard
+		if err := proto.Unmarshal(plain, &card); err != nil {
+			return fmt.Errorf("failed to parse bank card: %w", err)
+		}
+		resp.Data = &pbrpc.DataViewResponse_BankCard{BankCard: &card}
+	case pbc.DataType_DATA_TYPE_CREDENTIALS:
+		var creds pbmodels.Credentials
+		if err := proto.Unmarshal(plain, &creds); err != nil {
+			return fmt.Errorf("failed to parse credentials: %w", err)
+		}
+		resp.Data = &pbrpc.DataViewResponse_Credentials{Credentials: &creds}
+	case pbc.DataType_DATA_TYPE_BINARY_DATA:
+		var file pbmodels.File
+		if err := proto.Unmarshal(plain, &file); err != nil {
+			return fmt.Errorf("failed to parse file: %w", err)
+		}
+		resp.Data = &pbrpc.DataViewResponse_BinaryData{BinaryData: &file}
+	case pbc.DataType_DATA_TYPE_TEXT:
+		var note pbmodels.SecureNote
+		if err := proto.Unmarshal(plain, &note); err != nil {
+			return fmt.Errorf("failed to parse note: %w", err)
+		}
+		resp.Data = &pbrpc.DataViewResponse_SecureNote{SecureNote: &note}
+	case pbc.DataType_DATA_TYPE_OTP:
+		var otp pbmodels.Otp
+		if err := proto.Unmarshal(plain, &otp); err != nil {
+			return fmt.Errorf("failed to parse otp: %w", err)
+		}
+		resp.Data = &pbrpc.DataViewResponse_Otp{Otp: &otp}
+	case pbc.DataType_DATA_TYPE_SSH_KEY:
+		var key pbmodels.SshKey
+		if err := proto.Unmarshal(plain, &key); err != nil {
+			return fmt.Errorf("failed to parse ssh key: %w", err)
+		}
+		resp.Data = &pbrpc.DataViewResponse_SshKey{SshKey: &key}
+	default:
+		return fmt.Errorf("unsupported data type %s", resp.GetType())
+	}
+
+	return nil
+}
+
+// sealFolderName encrypts a folder name under mk.
+func sealFolderName(ctx context.Context, mk []byte, name string) (*pbmodels.EncryptedPayload, error) {
+	encrypted, err := crypto.NewEnvelope(nil).EncryptUserData(ctx, mk, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("encrypt error: %w", err)
+	}
+
+	return &pbmodels.EncryptedPayload{
+		EncryptedData: encrypted.EncryptedData,
+		DataNonce:     encrypted.DataNonce,
+		EncryptedDek:  encrypted.EncryptedDek,
+		DekNonce:      encrypted.DekNonce,
+	}, nil
+}
+
+// openFolderName decrypts the encrypted name of folder under mk and replaces it with the plain name.
+func openFolderName(ctx context.Context, mk []byte, folder *pbrpc.Folder) error {
+	payload := folder.GetEncrypted()
+	if payload == nil {
+		return nil
+	}
+
+	name, err := crypto.NewEnvelope(nil).DecryptUserData(ctx, models.DBUserData{
+		EncryptedData: payload.GetEncryptedData(),
+		DataNonce:     payload.GetDataNonce(),
+		EncryptedDek:  payload.GetEncryptedDek(),
+		DekNonce:      payload.GetDekNonce(),
+	}, mk)
+	if err != nil {
+		return err
+	}
+
+	folder.Name = string(name)
+	folder.Encrypted = nil
+	return nil
+}
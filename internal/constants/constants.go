@@ -12,8 +12,20 @@ type contextKey string
 const (
 	// JWT is the context key for storing JWT tokens in metadata.
 	JWT mdKey = "jwt"
+	// AdminToken is the metadata key carrying the token for administrative RPCs.
+	AdminToken mdKey = "admin-token"
+	// GatewayToken is the metadata key carrying the token of the HTTP gateway.
+	GatewayToken mdKey = "gateway-token"
+	// DeviceName is the metadata key carrying the name of the client device.
+	DeviceName mdKey = "device-name"
+	// ClientVersion is the metadata key carrying the version of the client application.
+	ClientVersion mdKey = "client-version"
 	// UserID is the context key for storing user ID in context.
 	UserID contextKey = "userID"
+	// SessionID is the context key for the session of the access token in context.
+	SessionID contextKey = "sessionID"
+	// Forwarded is the context key marking requests forwarded by the HTTP gateway.
+	Forwarded contextKey = "forwarded"
 )
 
 const (
@@ -23,6 +35,12 @@ const (
 	Credentials string = "credentials"
 	// BinaryData represents the data type for binary data.
 	BinaryData string = "binary_data"
+	// Text represents the data type for secure notes.
+	Text string = "text"
+	// OTP represents the data type for one-time password seeds.
+	OTP string = "otp"
+	// SSHKey represents the data type for SSH key pairs.
+	SSHKey string = "ssh_key"
 )
 
 const (
@@ -32,6 +50,14 @@ const (
 	Mem uint32 = 64 * 1024
 	// Threads is the number of threads for cryptographic operations.
 	Threads uint8 = 4
+	// Time is the number of passes for cryptographic operations (e.g., Argon2).
+	Time uint32 = 3
+	// SaltLength is the length (in bytes) of random KDF salts.
+	SaltLength int = 16
+	// KDFArgon2id is the name of the Argon2id key derivation function.
+	KDFArgon2id string = "argon2id"
+	// DefaultServerKeyID is the ID of SERVER_ENCRYPTION_KEY and of master keys wrapped before key IDs existed.
+	DefaultServerKeyID string = "default"
 )
 
 // MapDataTypeToString maps a protobuf DataType to its string representation.
@@ -46,6 +72,12 @@ func MapDataTypeToString(dt pbc.DataType) string {
 		return Credentials
 	case pbc.DataType_DATA_TYPE_BINARY_DATA:
 		return BinaryData
+	case pbc.DataType_DATA_TYPE_TEXT:
+		return Text
+	case pbc.DataType_DATA_TYPE_OTP:
+		return OTP
+	case pbc.DataType_DATA_TYPE_SSH_KEY:
+		return SSHKey
 	default:
 		return "unknown"
 	}
@@ -16,6 +16,9 @@ func TestMapDataTypeToString(t *testing.T) {
 		{"bank card", pbc.DataType_DATA_TYPE_BANK_CARD, BankCard},
 		{"credentials", pbc.DataType_DATA_TYPE_CREDENTIALS, Credentials},
 		{"binary data", pbc.DataType_DATA_TYPE_BINARY_DATA, BinaryData},
+		{"text", pbc.DataType_DATA_TYPE_TEXT, Text},
+		{"otp", pbc.DataType_DATA_TYPE_OTP, OTP},
+		{"ssh key", pbc.DataType_DATA_TYPE_SSH_KEY, SSHKey},
 		{"unknown", pbc.DataType(999), "unknown"},
 	}
 
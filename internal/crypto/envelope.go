@@ -2,11 +2,13 @@
 package crypto
 
 import (
+	"bytes"
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"fmt"
+	"io"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/models"
@@ -19,7 +21,43 @@ type IEnvelope interface {
 	// EncryptUserData encrypts the given data with a randomly generated DEK, which is itself encrypted with the master key.
 	EncryptUserData(ctx context.Context, masterKey []byte, data []byte) (*models.EncryptedData, error)
 	// DecryptUserData decrypts the user data using the provided master key.
+	// Data without a DataNonce is read as a segmented stream.
 	DecryptUserData(ctx context.Context, userData models.DBUserData, masterKey []byte) ([]byte, error)
+	// EncryptFile encrypts file contents in the segmented stream format with a randomly generated
+	// DEK, which is itself encrypted with the master key.
+	EncryptFile(ctx context.Context, masterKey []byte, data []byte) (*models.EncryptedData, error)
+	// EncryptStream returns a writer encrypting into dst in the segmented stream format with a
+	// randomly generated DEK, which is itself encrypted with the master key.
+	EncryptStream(ctx context.Context, masterKey []byte, dst io.Writer) (io.WriteCloser, *models.EncryptedData, error)
+	// NewStreamSealer returns a sealer for a segmented stream uploaded in parts, with a randomly
+	// generated DEK, which is itself encrypted with the master key.
+	NewStreamSealer(ctx context.Context, masterKey []byte) (*StreamSealer, *models.EncryptedData, error)
+	// OpenStreamSealer returns a sealer continuing the stream with the given header, whose DEK
+	// is the encrypted DEK of userData.
+	OpenStreamSealer(ctx context.Context, userData models.DBUserData, masterKey, header []byte) (*StreamSealer, error)
+	// DecryptReader returns a reader of the plaintext of the ciphertext src of userData, whose
+	// size is size, and the plaintext size. Both the segmented and the single-shot format are read.
+	DecryptReader(
+		ctx context.Context,
+		userData models.DBUserData,
+		masterKey []byte,
+		src io.Reader,
+		size int64,
+	) (io.Reader, int64, error)
+	// DecryptReaderAt returns a random-access reader of the plaintext of the ciphertext src of
+	// userData, whose size is size, and the plaintext size. Both formats are read.
+	DecryptReaderAt(
+		ctx context.Context,
+		userData models.DBUserData,
+		masterKey []byte,
+		src io.ReaderAt,
+		size int64,
+	) (io.ReaderAt, int64, error)
+	// SealWithDEK encrypts data with the DEK of userData, for values stored beside the record
+	// such as its sensitive meta fields, and returns the ciphertext and its nonce.
+	SealWithDEK(ctx context.Context, userData models.DBUserData, masterKey, data []byte) ([]byte, []byte, error)
+	// OpenWithDEK decrypts a ciphertext sealed by SealWithDEK with the DEK of userData.
+	OpenWithDEK(ctx context.Context, userData models.DBUserData, masterKey, ciphertext, nonce []byte) ([]byte, error)
 }
 
 // EnvelopStorage defines the interface for persisting user data.
@@ -110,6 +148,19 @@ func (e *Envelope) DecryptUserData(
 		return nil, fmt.Errorf("failed to decrypt DEK: %w", err)
 	}
 
+	// Данные без DataNonce записаны в потоковом формате
+	if len(userData.DataNonce) == 0 {
+		stream, errStream := NewStreamReader(bytes.NewReader(userData.EncryptedData), dek)
+		if errStream != nil {
+			return nil, fmt.Errorf("failed to decrypt data: %w", errStream)
+		}
+		decryptData, errRead := io.ReadAll(stream)
+		if errRead != nil {
+			return nil, fmt.Errorf("failed to decrypt data: %w", errRead)
+		}
+		return decryptData, nil
+	}
+
 	// 2. Расшифровываем данные с помощью DEK
 	block, err := aes.NewCipher(dek)
 	if err != nil {
@@ -128,3 +179,280 @@ func (e *Envelope) DecryptUserData(
 
 	return decryptData, nil
 }
+
+// EncryptStream returns a writer that encrypts everything written to it into dst in the
+// segmented stream format, using a randomly generated DEK encrypted with the master key.
+//
+// The returned EncryptedData holds the encrypted DEK and its nonce; DataNonce is empty, since
+// the stream carries its own nonces. The writer must be closed to write the final chunk.
+func (e *Envelope) EncryptStream(
+	ctx context.Context,
+	masterKey []byte,
+	dst io.Writer,
+) (io.WriteCloser, *models.EncryptedData, error) {
+	dek := make([]byte, constants.KeyLength)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate DEK: %w", err)
+	}
+
+	stream, err := NewStreamWriter(dst, dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedDEK, dekNonce, err := sealDEK(masterKey, dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stream, &models.EncryptedData{
+		EncryptedDek: encryptedDEK,
+		DekNonce:     dekNonce,
+	}, nil
+}
+
+// EncryptFile encrypts data in the segmented stream format using a randomly generated DEK
+// encrypted with the master key. Unlike EncryptUserData, the result can be decrypted in parts.
+//
+// The returned DataNonce is empty, which marks the data as a segmented stream.
+func (e *Envelope) EncryptFile(
+	ctx context.Context,
+	masterKey []byte,
+	data []byte,
+) (*models.EncryptedData, error) {
+	var buf bytes.Buffer
+	stream, encryptedData, err := e.EncryptStream(ctx, masterKey, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stream.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+	if err = stream.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	encryptedData.EncryptedData = buf.Bytes()
+	return encryptedData, nil
+}
+
+// NewStreamSealer returns a StreamSealer for a new segmented stream, using a randomly generated
+// DEK encrypted with the master key. The returned DataNonce is empty.
+func (e *Envelope) NewStreamSealer(ctx context.Context, masterKey []byte) (*StreamSealer, *models.EncryptedData, error) {
+	dek := make([]byte, constants.KeyLength)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate DEK: %w", err)
+	}
+
+	sealer, err := NewStreamSealer(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedDEK, dekNonce, err := sealDEK(masterKey, dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sealer, &models.EncryptedData{EncryptedDek: encryptedDEK, DekNonce: dekNonce}, nil
+}
+
+// OpenStreamSealer decrypts the DEK of userData with the master key and returns a
+// StreamSealer continuing the stream with the given header.
+func (e *Envelope) OpenStreamSealer(
+	ctx context.Context,
+	userData models.DBUserData,
+	masterKey, header []byte,
+) (*StreamSealer, error) {
+	dek, err := openDEK(masterKey, userData)
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenStreamSealer(dek, header)
+}
+
+// DecryptReader decrypts the DEK of userData with the master key and returns a reader of the
+// plaintext of src along with its size.
+//
+// Data without a DataNonce is a segmented stream and is decrypted as it is read. Data in the
+// single-shot format has to be authenticated as a whole, so it is read and decrypted up front.
+func (e *Envelope) DecryptReader(
+	ctx context.Context,
+	userData models.DBUserData,
+	masterKey []byte,
+	src io.Reader,
+	size int64,
+) (io.Reader, int64, error) {
+	if len(userData.DataNonce) != 0 {
+		plaintext, err := e.decryptAll(ctx, userData, masterKey, src)
+		if err != nil {
+			return nil, 0, err
+		}
+		return bytes.NewReader(plaintext), int64(len(plaintext)), nil
+	}
+
+	dek, err := openDEK(masterKey, userData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stream, err := NewStreamReader(src, dek)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return stream, stream.PlaintextSize(size), nil
+}
+
+// DecryptReaderAt decrypts the DEK of userData with the master key and returns a random-access
+// reader of the plaintext of src along with its size.
+//
+// Only the chunks of a segmented stream that cover a read are fetched from src and decrypted.
+// Data in the single-shot format is read and decrypted up front.
+func (e *Envelope) DecryptReaderAt(
+	ctx context.Context,
+	userData models.DBUserData,
+	masterKey []byte,
+	src io.ReaderAt,
+	size int64,
+) (io.ReaderAt, int64, error) {
+	if len(userData.DataNonce) != 0 {
+		plaintext, err := e.decryptAll(ctx, userData, masterKey, io.NewSectionReader(src, 0, size))
+		if err != nil {
+			return nil, 0, err
+		}
+		return bytes.NewReader(plaintext), int64(len(plaintext)), nil
+	}
+
+	dek, err := openDEK(masterKey, userData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stream, err := NewStreamReaderAt(src, size, dek)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return stream, stream.Size(), nil
+}
+
+// decryptAll reads the single-shot ciphertext of userData from src and decrypts it.
+func (e *Envelope) decryptAll(ctx context.Context, userData models.DBUserData, masterKey []byte, src io.Reader) ([]byte, error) {
+	encrypted, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	userData.EncryptedData = encrypted
+
+	return e.DecryptUserData(ctx, userData, masterKey)
+}
+
+// sideDataAAD binds values sealed by SealWithDEK to their purpose, so they cannot be passed off
+// as the record payload encrypted with the same DEK.
+var sideDataAAD = []byte("gophkeeper side data")
+
+// SealWithDEK encrypts data with the DEK of userData and returns the ciphertext and its nonce.
+// The DEK stays wrapped by the master key, so the sealed data follows the record through key
+// rotation without being re-encrypted.
+func (e *Envelope) SealWithDEK(
+	ctx context.Context,
+	userData models.DBUserData,
+	masterKey, data []byte,
+) ([]byte, []byte, error) {
+	dek, err := openDEK(masterKey, userData)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("error generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nil, nonce, data, sideDataAAD), nonce, nil
+}
+
+// OpenWithDEK decrypts a ciphertext sealed by SealWithDEK with the DEK of userData.
+func (e *Envelope) OpenWithDEK(
+	ctx context.Context,
+	userData models.DBUserData,
+	masterKey, ciphertext, nonce []byte,
+) ([]byte, error) {
+	dek, err := openDEK(masterKey, userData)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := gcm.Open(nil, nonce, ciphertext, sideDataAAD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt side data: %w", err)
+	}
+
+	return data, nil
+}
+
+// sealDEK encrypts a DEK with the master key and returns it with its nonce.
+func sealDEK(masterKey, dek []byte) ([]byte, []byte, error) {
+	mkGCM, err := newGCM(masterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dekNonce := make([]byte, mkGCM.NonceSize())
+	if _, err := rand.Read(dekNonce); err != nil {
+		return nil, nil, fmt.Errorf("error generate dekNonce: %w", err)
+	}
+
+	return mkGCM.Seal(nil, dekNonce, dek, nil), dekNonce, nil
+}
+
+// openDEK decrypts the DEK of userData with the master key.
+func openDEK(masterKey []byte, userData models.DBUserData) ([]byte, error) {
+	mkGCM, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dek, err := mkGCM.Open(nil, userData.DekNonce, userData.EncryptedDek, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt DEK: %w", err)
+	}
+
+	return dek, nil
+}
+
+// RewrapDEK decrypts an encrypted DEK with oldMasterKey and encrypts it with newMasterKey.
+// Returns the new encrypted DEK and its nonce; the data encrypted with the DEK is left untouched.
+func RewrapDEK(oldMasterKey, newMasterKey, encryptedDEK, dekNonce []byte) ([]byte, []byte, error) {
+	oldAEAD, err := newGCM(oldMasterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dek, err := oldAEAD.Open(nil, dekNonce, encryptedDEK, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt DEK: %w", err)
+	}
+
+	newAEAD, err := newGCM(newMasterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, newAEAD.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("error generate dekNonce: %w", err)
+	}
+
+	return newAEAD.Seal(nil, nonce, dek, nil), nonce, nil
+}
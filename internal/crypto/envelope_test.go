@@ -90,3 +90,46 @@ func TestDecryptUserData_CorruptedData(t *testing.T) {
 	_, err = e.DecryptUserData(ctx, dbUserData, masterKey)
 	require.Error(t, err)
 }
+
+func TestSealWithDEK(t *testing.T) {
+	ctx := context.Background()
+	masterKey := []byte("01234567890123456789012345678901") // 32 bytes master key
+
+	e := NewEnvelope(&mockStorage{})
+
+	encryptedData, err := e.EncryptUserData(ctx, masterKey, []byte("record payload"))
+	require.NoError(t, err)
+	userData := models.DBUserData{
+		EncryptedData: encryptedData.EncryptedData,
+		DataNonce:     encryptedData.DataNonce,
+		EncryptedDek:  encryptedData.EncryptedDek,
+		DekNonce:      encryptedData.DekNonce,
+	}
+
+	sealed, nonce, err := e.SealWithDEK(ctx, userData, masterKey, []byte("pin 1234"))
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), "1234")
+
+	opened, err := e.OpenWithDEK(ctx, userData, masterKey, sealed, nonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("pin 1234"), opened)
+
+	// The DEK survives rewrapping with a new master key
+	newMasterKey := []byte("abcdefghijabcdefghijabcdefghijab")
+	userData.EncryptedDek, userData.DekNonce, err = RewrapDEK(masterKey, newMasterKey, userData.EncryptedDek, userData.DekNonce)
+	require.NoError(t, err)
+	opened, err = e.OpenWithDEK(ctx, userData, newMasterKey, sealed, nonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("pin 1234"), opened)
+
+	// Side data is not accepted as the record payload and the other way round
+	_, err = e.DecryptUserData(ctx, models.DBUserData{
+		EncryptedData: sealed,
+		DataNonce:     nonce,
+		EncryptedDek:  userData.EncryptedDek,
+		DekNonce:      userData.DekNonce,
+	}, newMasterKey)
+	require.Error(t, err)
+	_, err = e.OpenWithDEK(ctx, userData, newMasterKey, encryptedData.EncryptedData, encryptedData.DataNonce)
+	require.Error(t, err)
+}
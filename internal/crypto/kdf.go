@@ -0,0 +1,73 @@
+// Package crypto provides cryptographic utilities for data encryption and decryption using envelope encryption.
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// CurrentKDFVersion is the version of the Argon2id parameters used for new and upgraded keys.
+//
+// To strengthen the parameters, add a new entry to kdfVersions and raise CurrentKDFVersion:
+// users are moved to the new version on their next successful login.
+const CurrentKDFVersion = 1
+
+// kdfVersions maps a parameter version to its Argon2id cost settings.
+//
+// Version 0 is reserved for legacy master keys derived from the password with no salt.
+var kdfVersions = map[int]models.KDFParams{
+	1: {Algorithm: constants.KDFArgon2id, Time: constants.Time, Memory: constants.Mem, Threads: constants.Threads},
+}
+
+// Lower bounds accepted for KDF parameters chosen by clients.
+const (
+	minKDFTime   uint32 = 1
+	minKDFMemory uint32 = 19 * 1024
+)
+
+// ErrInvalidKDFParams is returned when KDF parameters are unsupported or too weak.
+var ErrInvalidKDFParams = errors.New("invalid kdf parameters")
+
+// NewKDFParams returns the current Argon2id parameters with a fresh random salt.
+func NewKDFParams() (models.KDFParams, error) {
+	salt := make([]byte, constants.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return models.KDFParams{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	p := kdfVersions[CurrentKDFVersion]
+	p.Salt = salt
+	return p, nil
+}
+
+// ValidateKDFParams checks that p names a supported algorithm and is not weaker than the accepted minimum.
+func ValidateKDFParams(p models.KDFParams) error {
+	switch {
+	case p.Algorithm != constants.KDFArgon2id:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidKDFParams, p.Algorithm)
+	case len(p.Salt) < constants.SaltLength:
+		return fmt.Errorf("%w: salt must be at least %d bytes", ErrInvalidKDFParams, constants.SaltLength)
+	case p.Time < minKDFTime || p.Memory < minKDFMemory || p.Threads == 0:
+		return fmt.Errorf("%w: parameters are too weak", ErrInvalidKDFParams)
+	}
+	return nil
+}
+
+// DeriveKey stretches the password into a KeyLength key using the KDF described by p.
+func DeriveKey(password string, p models.KDFParams) ([]byte, error) {
+	if p.Algorithm != constants.KDFArgon2id {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidKDFParams, p.Algorithm)
+	}
+	return argon2.IDKey([]byte(password), p.Salt, p.Time, p.Memory, p.Threads, uint32(constants.KeyLength)), nil
+}
+
+// deriveLegacyMasterKey derives a version 0 master key, which was the password stretched with no salt.
+func deriveLegacyMasterKey(password string) []byte {
+	return argon2.IDKey([]byte(password), nil, constants.Time, constants.Mem, constants.Threads, uint32(constants.KeyLength))
+}
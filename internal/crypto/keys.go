@@ -3,23 +3,31 @@ package crypto
 
 import (
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
 	"crypto/subtle"
 	"errors"
+	"log/slog"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/kms"
 	"github.com/apetsko/gophkeeper/models"
-	"golang.org/x/crypto/argon2"
 )
 
+// ErrInvalidPassword is returned when the password does not open the user's master key.
+var ErrInvalidPassword = errors.New("invalid password")
+
 // KeyManagerInterface defines methods for managing user master keys.
 //
 //go:generate mockery --dir ./internal/crypto --name=KeyManagerInterface --output=../mocks/ --case=underscore
 type KeyManagerInterface interface {
 	GetMasterKey(ctx context.Context, userID int) ([]byte, error)
-	GetOrCreateMasterKey(ctx context.Context, userID int, userPassword string, userSalt []byte) ([]byte, error)
+	GetOrCreateMasterKey(ctx context.Context, userID int, userPassword string) ([]byte, error)
+	RewrapMasterKey(
+		ctx context.Context,
+		key *models.EncryptedMK,
+		oldPassword, newPassword string,
+	) (func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error)
+	SealSecret(ctx context.Context, plaintext []byte) (*models.WrappedSecret, error)
+	OpenSecret(ctx context.Context, secret *models.WrappedSecret) ([]byte, error)
 }
 
 // Убедимся, что KeyManager реализует интерфейс
@@ -29,101 +37,309 @@ var _ KeyManagerInterface = (*KeyManager)(nil)
 type KeyStorage interface {
 	// GetMasterKey fetches the encrypted master key for the user.
 	GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error)
-	// SaveMasterKey stores the encrypted master key and its nonce for the user.
-	SaveMasterKey(ctx context.Context, userID int, encryptedMK, nonce []byte) (int, error)
+	// SaveMasterKey stores the encrypted master key and its KDF parameters.
+	SaveMasterKey(ctx context.Context, key *models.EncryptedMK) (int, error)
+	// UpdateMasterKey replaces the stored master key, optionally re-wrapping the user's DEKs.
+	UpdateMasterKey(
+		ctx context.Context,
+		key *models.EncryptedMK,
+		rewrapDEK func(encryptedDEK, nonce []byte) ([]byte, []byte, error),
+	) error
 }
 
 // KeyManager implements KeyManagerInterface and handles master key encryption and decryption.
+//
+// Each user has a random master key. It is stored sealed with the active server key of the
+// KeyWrapper, together with that key's ID, and, separately, wrapped with a key derived from the
+// user's password using a per-user random salt and the Argon2id parameters of CurrentKDFVersion.
 type KeyManager struct {
-	storage             KeyStorage
-	serverEncryptionKey []byte
+	storage KeyStorage
+	wrapper kms.KeyWrapper
 }
 
-// NewKeyManager creates a new KeyManager with the given storage and server encryption key.
+// NewKeyManager creates a new KeyManager with the given storage and server key wrapper.
 func NewKeyManager(
 	storage KeyStorage,
-	serverEncryptionKey []byte,
+	wrapper kms.KeyWrapper,
 ) *KeyManager {
 	return &KeyManager{
-		storage:             storage,
-		serverEncryptionKey: serverEncryptionKey,
+		storage: storage,
+		wrapper: wrapper,
 	}
 }
 
-// GetMasterKey retrieves and decrypts the user's master key using the server encryption key.
-func (m *KeyManager) GetMasterKey(ctx context.Context, userID int) ([]byte, error) {
-	encryptedMK, err := m.storage.GetMasterKey(ctx, userID)
+// ActiveKeyID returns the ID of the server key that wraps new master keys.
+func (m *KeyManager) ActiveKeyID() string {
+	return m.wrapper.ActiveKeyID()
+}
+
+// RewrapServerKey re-encrypts the server-wrapped copy of key with the active server key.
+// The password-wrapped copy and the master key itself do not change.
+func (m *KeyManager) RewrapServerKey(ctx context.Context, key *models.EncryptedMK) error {
+	mk, err := m.openMasterKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	keyID, encrypted, nonce, err := m.wrapper.Wrap(ctx, mk)
+	if err != nil {
+		return err
+	}
+
+	key.ServerKeyID, key.EncryptedMK, key.Nonce = keyID, encrypted, nonce
+	return nil
+}
+
+// SealSecret encrypts a small server-side secret, such as a TOTP secret, with the active server key.
+func (m *KeyManager) SealSecret(ctx context.Context, plaintext []byte) (*models.WrappedSecret, error) {
+	keyID, ciphertext, nonce, err := m.wrapper.Wrap(ctx, plaintext)
 	if err != nil {
 		return nil, err
 	}
 
-	block, _ := aes.NewCipher(m.serverEncryptionKey)
-	gcm, _ := cipher.NewGCM(block)
+	return &models.WrappedSecret{KeyID: keyID, Ciphertext: ciphertext, Nonce: nonce}, nil
+}
+
+// OpenSecret decrypts a secret sealed by SealSecret.
+func (m *KeyManager) OpenSecret(ctx context.Context, secret *models.WrappedSecret) ([]byte, error) {
+	return m.wrapper.Unwrap(ctx, secret.KeyID, secret.Ciphertext, secret.Nonce)
+}
+
+// RewrapSecret re-encrypts a secret sealed by SealSecret with the active server key.
+func (m *KeyManager) RewrapSecret(ctx context.Context, secret *models.WrappedSecret) error {
+	plaintext, err := m.OpenSecret(ctx, secret)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := m.SealSecret(ctx, plaintext)
+	if err != nil {
+		return err
+	}
+
+	*secret = *sealed
+	return nil
+}
 
-	mk, err := gcm.Open(nil, encryptedMK.Nonce, encryptedMK.EncryptedMK, nil)
+// GetMasterKey retrieves and decrypts the user's master key using the server encryption key.
+func (m *KeyManager) GetMasterKey(ctx context.Context, userID int) ([]byte, error) {
+	encryptedMK, err := m.storage.GetMasterKey(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return mk, nil
+	return m.openMasterKey(ctx, encryptedMK)
 }
 
 // GetOrCreateMasterKey retrieves the user's master key if it exists and validates the password,
 // or generates and stores a new master key if not found.
+//
+// Keys created with older KDF parameters are upgraded to CurrentKDFVersion once the password
+// has been verified. A failed upgrade is logged and does not fail the call.
 func (m *KeyManager) GetOrCreateMasterKey(
 	ctx context.Context,
 	userID int,
 	userPassword string,
-	userSalt []byte,
 ) ([]byte, error) {
 	encryptedMK, err := m.storage.GetMasterKey(ctx, userID)
 	if err != nil {
 		if errors.Is(err, models.ErrMasterKeyNotFound) {
-			return m.generateAndStoreMasterKey(ctx, userID, userPassword, userSalt)
+			return m.generateAndStoreMasterKey(ctx, userID, userPassword)
 		}
 		return nil, err
 	}
 
-	block, _ := aes.NewCipher(m.serverEncryptionKey)
-	gcm, _ := cipher.NewGCM(block)
-
-	mk, err := gcm.Open(nil, encryptedMK.Nonce, encryptedMK.EncryptedMK, nil)
+	mk, err := m.openMasterKey(ctx, encryptedMK)
 	if err != nil {
 		return nil, err
 	}
 
-	computedMK := argon2.IDKey([]byte(userPassword), userSalt, 3, constants.Mem, constants.Threads, uint32(constants.KeyLength))
-	if subtle.ConstantTimeCompare(mk, computedMK) != 1 {
-		return nil, errors.New("invalid password")
+	if err := verifyPassword(encryptedMK, mk, userPassword); err != nil {
+		return nil, err
+	}
+
+	if encryptedMK.KDFVersion < CurrentKDFVersion {
+		upgraded, errUpgrade := m.upgradeMasterKey(ctx, userID, encryptedMK.KDFVersion, mk, userPassword)
+		if errUpgrade != nil {
+			slog.Warn("failed to upgrade master key", "user_id", userID, "error", errUpgrade.Error())
+			return mk, nil
+		}
+		return upgraded, nil
 	}
 
 	return mk, nil
 }
 
-// generateAndStoreMasterKey generates a new master key from the user's password and salt,
-// encrypts it with the server key, stores it, and returns the plaintext master key.
+// RewrapMasterKey verifies oldPassword against the master key key and replaces key in place with
+// the master key wrapped for newPassword with a fresh salt and the current KDF parameters.
+//
+// The result is not stored: the caller reads key and saves it together with the new password
+// hash in one transaction. The master key itself is kept, so all DEKs stay valid, except for a
+// legacy (version 0) key derived from the old password: like on login, it is replaced with a
+// random key, and the returned function re-wraps the DEKs for it. Otherwise the function is nil.
+func (m *KeyManager) RewrapMasterKey(
+	ctx context.Context,
+	key *models.EncryptedMK,
+	oldPassword, newPassword string,
+) (func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error) {
+	mk, err := m.openMasterKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyPassword(key, mk, oldPassword); err != nil {
+		return nil, err
+	}
+
+	newMK, rewrapDEK, err := replaceLegacyMasterKey(key.KDFVersion, mk)
+	if err != nil {
+		return nil, err
+	}
+
+	rewrapped, err := m.sealMasterKey(ctx, key.UserID, newMK, newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	*key = *rewrapped
+	return rewrapDEK, nil
+}
+
+// generateAndStoreMasterKey generates a random master key, seals it for the user's password
+// and the server, stores it, and returns the plaintext master key.
 func (m *KeyManager) generateAndStoreMasterKey(
 	ctx context.Context,
 	userID int,
 	userPassword string,
-	userSalt []byte,
 ) ([]byte, error) {
-	mk := argon2.IDKey([]byte(userPassword), userSalt, 3, constants.Mem, constants.Threads, uint32(constants.KeyLength))
+	mk, err := NewMasterKey()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedMK, err := m.sealMasterKey(ctx, userID, mk, userPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = m.storage.SaveMasterKey(ctx, encryptedMK)
+
+	return mk, err
+}
+
+// upgradeMasterKey re-wraps mk with a fresh salt and the current KDF parameters.
+//
+// Legacy (version 0) master keys were derived from the password itself, so users with equal
+// passwords shared a key. They are replaced with a random key, and the user's DEKs are
+// re-wrapped with it in the same transaction.
+func (m *KeyManager) upgradeMasterKey(
+	ctx context.Context,
+	userID int,
+	fromVersion int,
+	mk []byte,
+	userPassword string,
+) ([]byte, error) {
+	newMK, rewrapDEK, err := replaceLegacyMasterKey(fromVersion, mk)
+	if err != nil {
+		return nil, err
+	}
 
-	block, errBlock := aes.NewCipher(m.serverEncryptionKey)
-	if errBlock != nil {
-		return nil, errBlock
+	encryptedMK, err := m.sealMasterKey(ctx, userID, newMK, userPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.storage.UpdateMasterKey(ctx, encryptedMK, rewrapDEK); err != nil {
+		return nil, err
 	}
 
-	gcm, _ := cipher.NewGCM(block)
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := rand.Read(nonce); err != nil {
-		return nil, errors.New("error generate nonce")
+	return newMK, nil
+}
+
+// replaceLegacyMasterKey returns a random master key replacing mk, if mk is a legacy (version 0)
+// key, and the function re-wrapping the DEKs from mk to it. Newer keys are returned unchanged
+// with a nil function.
+func replaceLegacyMasterKey(
+	fromVersion int,
+	mk []byte,
+) ([]byte, func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error) {
+	if fromVersion != 0 {
+		return mk, nil, nil
 	}
 
-	encryptedMK := gcm.Seal(nil, nonce, mk, nil)
+	newMK, err := NewMasterKey()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	_, err := m.storage.SaveMasterKey(ctx, userID, encryptedMK, nonce)
+	return newMK, func(encryptedDEK, nonce []byte) ([]byte, []byte, error) {
+		return RewrapDEK(mk, newMK, encryptedDEK, nonce)
+	}, nil
+}
 
-	return mk, err
+// sealMasterKey encrypts mk with the server key and wraps it with a key derived from the
+// password using a new random salt and the current KDF parameters.
+func (m *KeyManager) sealMasterKey(ctx context.Context, userID int, mk []byte, userPassword string) (*models.EncryptedMK, error) {
+	params, err := NewKDFParams()
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := DeriveKey(userPassword, params)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := WrapKey(kek, mk)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, encrypted, nonce, err := m.wrapper.Wrap(ctx, mk)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EncryptedMK{
+		UserID:            userID,
+		ServerKeyID:       keyID,
+		EncryptedMK:       encrypted,
+		Nonce:             nonce,
+		KDFVersion:        CurrentKDFVersion,
+		KDF:               &params,
+		PasswordWrappedMK: wrapped,
+	}, nil
+}
+
+// openMasterKey decrypts a master key sealed with the server key it records.
+// Keys without an ID predate key IDs and were sealed with constants.DefaultServerKeyID.
+func (m *KeyManager) openMasterKey(ctx context.Context, encryptedMK *models.EncryptedMK) ([]byte, error) {
+	keyID := encryptedMK.ServerKeyID
+	if keyID == "" {
+		keyID = constants.DefaultServerKeyID
+	}
+
+	return m.wrapper.Unwrap(ctx, keyID, encryptedMK.EncryptedMK, encryptedMK.Nonce)
+}
+
+// verifyPassword checks that userPassword belongs to the master key mk.
+func verifyPassword(encryptedMK *models.EncryptedMK, mk []byte, userPassword string) error {
+	if encryptedMK.KDFVersion == 0 || encryptedMK.KDF == nil {
+		if subtle.ConstantTimeCompare(mk, deriveLegacyMasterKey(userPassword)) != 1 {
+			return ErrInvalidPassword
+		}
+		return nil
+	}
+
+	kek, err := DeriveKey(userPassword, *encryptedMK.KDF)
+	if err != nil {
+		return err
+	}
+
+	unwrapped, err := UnwrapKey(kek, encryptedMK.PasswordWrappedMK)
+	if err != nil || subtle.ConstantTimeCompare(mk, unwrapped) != 1 {
+		return ErrInvalidPassword
+	}
+
+	return nil
 }
@@ -8,16 +8,21 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/kms"
 	"github.com/apetsko/gophkeeper/models"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/argon2"
 )
 
 type mockKeyStorage struct {
-	storedMK       *models.EncryptedMK
-	saveShouldFail bool
-	getShouldFail  bool
-	getErr         error
+	storedMK         *models.EncryptedMK
+	saveShouldFail   bool
+	updateShouldFail bool
+	getShouldFail    bool
+	getErr           error
+	// deks simulates the user's stored data keys for UpdateMasterKey.
+	deks []*models.EncryptedData
 }
 
 func (m *mockKeyStorage) GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error) {
@@ -30,17 +35,35 @@ func (m *mockKeyStorage) GetMasterKey(ctx context.Context, userID int) (*models.
 	return m.storedMK, nil
 }
 
-func (m *mockKeyStorage) SaveMasterKey(ctx context.Context, userID int, encryptedMK, nonce []byte) (int, error) {
+func (m *mockKeyStorage) SaveMasterKey(ctx context.Context, key *models.EncryptedMK) (int, error) {
 	if m.saveShouldFail {
 		return 0, errors.New("save failed")
 	}
-	m.storedMK = &models.EncryptedMK{
-		EncryptedMK: encryptedMK,
-		Nonce:       nonce,
-	}
+	m.storedMK = key
 	return 1, nil
 }
 
+func (m *mockKeyStorage) UpdateMasterKey(
+	ctx context.Context,
+	key *models.EncryptedMK,
+	rewrapDEK func(encryptedDEK, nonce []byte) ([]byte, []byte, error),
+) error {
+	if m.updateShouldFail {
+		return errors.New("update failed")
+	}
+	if rewrapDEK != nil {
+		for _, d := range m.deks {
+			dek, nonce, err := rewrapDEK(d.EncryptedDek, d.DekNonce)
+			if err != nil {
+				return err
+			}
+			d.EncryptedDek, d.DekNonce = dek, nonce
+		}
+	}
+	m.storedMK = key
+	return nil
+}
+
 func generateEncryptedMK(serverKey, mk []byte) (*models.EncryptedMK, error) {
 	block, err := aes.NewCipher(serverKey)
 	if err != nil {
@@ -74,7 +97,7 @@ func TestGetMasterKey_Success(t *testing.T) {
 		storedMK: encryptedMK,
 	}
 
-	km := NewKeyManager(storage, serverKey)
+	km := NewKeyManager(storage, testKeyring(t, serverKey))
 
 	gotMK, err := km.GetMasterKey(ctx, userID)
 	require.NoError(t, err)
@@ -88,7 +111,7 @@ func TestGetMasterKey_NotFound(t *testing.T) {
 
 	storage := &mockKeyStorage{}
 
-	km := NewKeyManager(storage, serverKey)
+	km := NewKeyManager(storage, testKeyring(t, serverKey))
 
 	_, err := km.GetMasterKey(ctx, userID)
 	require.ErrorIs(t, err, models.ErrMasterKeyNotFound)
@@ -99,44 +122,187 @@ func TestGetOrCreateMasterKey_CreateNew_Success(t *testing.T) {
 	serverKey := []byte("01234567890123456789012345678901")
 	userID := 123
 	userPassword := "strongpassword"
-	userSalt := []byte("saltysalt")
 
 	storage := &mockKeyStorage{}
 
-	km := NewKeyManager(storage, serverKey)
+	km := NewKeyManager(storage, testKeyring(t, serverKey))
 
-	mk, err := km.GetOrCreateMasterKey(ctx, userID, userPassword, userSalt)
+	mk, err := km.GetOrCreateMasterKey(ctx, userID, userPassword)
 	require.NoError(t, err)
 	require.NotNil(t, mk)
 	require.Len(t, mk, 32)
 
-	// Check that key was saved in storage
+	// Check that key was saved in storage with its KDF parameters
 	require.NotNil(t, storage.storedMK)
+	require.Equal(t, userID, storage.storedMK.UserID)
+	require.Equal(t, CurrentKDFVersion, storage.storedMK.KDFVersion)
+	require.NotNil(t, storage.storedMK.KDF)
+	require.Len(t, storage.storedMK.KDF.Salt, 16)
+	require.NotEmpty(t, storage.storedMK.PasswordWrappedMK)
+
+	// The same password opens the stored key
+	again, err := km.GetOrCreateMasterKey(ctx, userID, userPassword)
+	require.NoError(t, err)
+	require.Equal(t, mk, again)
+}
+
+func TestGetOrCreateMasterKey_SamePasswordDifferentKeys(t *testing.T) {
+	ctx := context.Background()
+	serverKey := []byte("01234567890123456789012345678901")
+
+	first, second := &mockKeyStorage{}, &mockKeyStorage{}
+
+	mk1, err := NewKeyManager(first, testKeyring(t, serverKey)).GetOrCreateMasterKey(ctx, 1, "samepassword")
+	require.NoError(t, err)
+	mk2, err := NewKeyManager(second, testKeyring(t, serverKey)).GetOrCreateMasterKey(ctx, 2, "samepassword")
+	require.NoError(t, err)
+
+	require.NotEqual(t, mk1, mk2)
+	require.NotEqual(t, first.storedMK.KDF.Salt, second.storedMK.KDF.Salt)
 }
 
 func TestGetOrCreateMasterKey_InvalidPassword(t *testing.T) {
 	ctx := context.Background()
 	serverKey := []byte("01234567890123456789012345678901")
 	userID := 123
-	userPassword := "correctpassword"
-	userSalt := []byte("saltysalt")
 
-	// Создаём ключ из пароля, но в тесте будем использовать неправильный
-	km := NewKeyManager(nil, serverKey)
+	storage := &mockKeyStorage{}
+	km := NewKeyManager(storage, testKeyring(t, serverKey))
 
-	// Создаём зашифрованный MK для правильного пароля
-	mk := km.generateMasterKeyForTest(userPassword, userSalt, serverKey, t)
+	_, err := km.GetOrCreateMasterKey(ctx, userID, "correctpassword")
+	require.NoError(t, err)
 
-	storage := &mockKeyStorage{
-		storedMK: mk,
-	}
+	// Пытаемся получить с неправильным паролем
+	_, err = km.GetOrCreateMasterKey(ctx, userID, "wrongpassword")
+	require.ErrorIs(t, err, ErrInvalidPassword)
+}
 
+func TestGetOrCreateMasterKey_UpgradesLegacyKey(t *testing.T) {
+	ctx := context.Background()
+	serverKey := []byte("01234567890123456789012345678901")
+	userID := 123
+	userPassword := "correctpassword"
+
+	km := NewKeyManager(nil, testKeyring(t, serverKey))
+	legacy, legacyMK := km.generateLegacyMasterKeyForTest(userPassword, serverKey, t)
+
+	e := NewEnvelope(nil)
+	record, err := e.EncryptUserData(ctx, legacyMK, []byte("secret"))
+	require.NoError(t, err)
+
+	storage := &mockKeyStorage{storedMK: legacy, deks: []*models.EncryptedData{record}}
 	km.storage = storage
 
-	// Пытаемся получить с неправильным паролем
-	_, err := km.GetOrCreateMasterKey(ctx, userID, "wrongpassword", userSalt)
-	require.Error(t, err)
-	require.Equal(t, "invalid password", err.Error())
+	_, err = km.GetOrCreateMasterKey(ctx, userID, "wrongpassword")
+	require.ErrorIs(t, err, ErrInvalidPassword)
+	require.Zero(t, storage.storedMK.KDFVersion)
+
+	mk, err := km.GetOrCreateMasterKey(ctx, userID, userPassword)
+	require.NoError(t, err)
+	require.NotEqual(t, legacyMK, mk, "legacy key must be replaced with a random one")
+	require.Equal(t, CurrentKDFVersion, storage.storedMK.KDFVersion)
+	require.NotNil(t, storage.storedMK.KDF)
+
+	// Existing records stay readable with the new key
+	plain, err := e.DecryptUserData(ctx, models.DBUserData{
+		EncryptedData: record.EncryptedData,
+		DataNonce:     record.DataNonce,
+		EncryptedDek:  record.EncryptedDek,
+		DekNonce:      record.DekNonce,
+	}, mk)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plain)
+
+	// The server copy matches
+	stored, err := km.GetMasterKey(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, mk, stored)
+}
+
+func TestGetOrCreateMasterKey_UpgradeFailureKeepsLegacyKey(t *testing.T) {
+	ctx := context.Background()
+	serverKey := []byte("01234567890123456789012345678901")
+	userPassword := "correctpassword"
+
+	km := NewKeyManager(nil, testKeyring(t, serverKey))
+	legacy, legacyMK := km.generateLegacyMasterKeyForTest(userPassword, serverKey, t)
+	km.storage = &mockKeyStorage{storedMK: legacy, updateShouldFail: true}
+
+	mk, err := km.GetOrCreateMasterKey(ctx, 123, userPassword)
+	require.NoError(t, err)
+	require.Equal(t, legacyMK, mk)
+}
+
+func TestRewrapMasterKey(t *testing.T) {
+	ctx := context.Background()
+	serverKey := []byte("01234567890123456789012345678901")
+	userID := 123
+
+	storage := &mockKeyStorage{}
+	km := NewKeyManager(storage, testKeyring(t, serverKey))
+
+	mk, err := km.GetOrCreateMasterKey(ctx, userID, "oldpassword")
+	require.NoError(t, err)
+	oldSalt := storage.storedMK.KDF.Salt
+
+	stored := *storage.storedMK
+	_, err = km.RewrapMasterKey(ctx, &stored, "wrongpassword", "newpassword")
+	require.ErrorIs(t, err, ErrInvalidPassword)
+
+	key := &stored
+	rewrapDEK, err := km.RewrapMasterKey(ctx, key, "oldpassword", "newpassword")
+	require.NoError(t, err)
+	require.Nil(t, rewrapDEK, "the DEKs of a random master key stay valid")
+	require.Equal(t, userID, key.UserID)
+	require.NotEqual(t, oldSalt, key.KDF.Salt)
+
+	// RewrapMasterKey does not store the key itself
+	_, err = km.GetOrCreateMasterKey(ctx, userID, "oldpassword")
+	require.NoError(t, err)
+
+	storage.storedMK = key
+	_, err = km.GetOrCreateMasterKey(ctx, userID, "oldpassword")
+	require.ErrorIs(t, err, ErrInvalidPassword)
+
+	got, err := km.GetOrCreateMasterKey(ctx, userID, "newpassword")
+	require.NoError(t, err)
+	require.Equal(t, mk, got, "the master key must survive a password change")
+}
+
+func TestRewrapMasterKey_ReplacesLegacyKey(t *testing.T) {
+	ctx := context.Background()
+	serverKey := []byte("01234567890123456789012345678901")
+	userPassword := "oldpassword"
+
+	km := NewKeyManager(nil, testKeyring(t, serverKey))
+	legacy, legacyMK := km.generateLegacyMasterKeyForTest(userPassword, serverKey, t)
+
+	e := NewEnvelope(nil)
+	record, err := e.EncryptUserData(ctx, legacyMK, []byte("secret"))
+	require.NoError(t, err)
+
+	key := *legacy
+	rewrapDEK, err := km.RewrapMasterKey(ctx, &key, userPassword, "newpassword")
+	require.NoError(t, err)
+	require.NotNil(t, rewrapDEK)
+	require.Equal(t, CurrentKDFVersion, key.KDFVersion)
+
+	mk, err := km.openMasterKey(ctx, &key)
+	require.NoError(t, err)
+	require.NotEqual(t, legacyMK, mk, "legacy key must be replaced with a random one")
+	require.NoError(t, verifyPassword(&key, mk, "newpassword"))
+
+	// Existing records stay readable once their DEKs are re-wrapped
+	dek, nonce, err := rewrapDEK(record.EncryptedDek, record.DekNonce)
+	require.NoError(t, err)
+	plain, err := e.DecryptUserData(ctx, models.DBUserData{
+		EncryptedData: record.EncryptedData,
+		DataNonce:     record.DataNonce,
+		EncryptedDek:  dek,
+		DekNonce:      nonce,
+	}, mk)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plain)
 }
 
 func TestGenerateAndStoreMasterKey_SaveFail(t *testing.T) {
@@ -144,30 +310,58 @@ func TestGenerateAndStoreMasterKey_SaveFail(t *testing.T) {
 	serverKey := []byte("01234567890123456789012345678901")
 	userID := 123
 	userPassword := "password"
-	userSalt := []byte("saltysalt")
 
 	storage := &mockKeyStorage{saveShouldFail: true}
 
-	km := NewKeyManager(storage, serverKey)
+	km := NewKeyManager(storage, testKeyring(t, serverKey))
 
-	_, err := km.GetOrCreateMasterKey(ctx, userID, userPassword, userSalt)
+	_, err := km.GetOrCreateMasterKey(ctx, userID, userPassword)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "save failed")
 }
 
-// Вспомогательная функция, чтобы сгенерировать валидный EncryptedMK для теста invalid password
-func (m *KeyManager) generateMasterKeyForTest(userPassword string, userSalt, serverKey []byte, t *testing.T) *models.EncryptedMK {
-	mk := argon2.IDKey([]byte(userPassword), userSalt, 3, 64*1024, 4, 32)
-	block, err := aes.NewCipher(serverKey)
+// Вспомогательная функция: master key в старом формате (пароль без соли, версия 0)
+func (m *KeyManager) generateLegacyMasterKeyForTest(userPassword string, serverKey []byte, t *testing.T) (*models.EncryptedMK, []byte) {
+	mk := argon2.IDKey([]byte(userPassword), nil, 3, 64*1024, 4, 32)
+	encryptedMK, err := generateEncryptedMK(serverKey, mk)
 	require.NoError(t, err)
-	gcm, err := cipher.NewGCM(block)
+	return encryptedMK, mk
+}
+
+// testKeyring returns a keyring holding serverKey as the default key.
+func testKeyring(t *testing.T, serverKey []byte) *kms.Keyring {
+	t.Helper()
+
+	keyring, err := kms.NewKeyring(map[string][]byte{constants.DefaultServerKeyID: serverKey}, constants.DefaultServerKeyID)
 	require.NoError(t, err)
-	nonce := make([]byte, gcm.NonceSize())
-	_, err = rand.Read(nonce)
+	return keyring
+}
+
+func TestRewrapServerKey(t *testing.T) {
+	ctx := context.Background()
+	oldKey := []byte("01234567890123456789012345678901")
+	newKey := []byte("abcdefghijabcdefghijabcdefghijab")
+
+	storage := &mockKeyStorage{}
+	mk, err := NewKeyManager(storage, testKeyring(t, oldKey)).GetOrCreateMasterKey(ctx, 1, "password")
 	require.NoError(t, err)
-	encryptedMK := gcm.Seal(nil, nonce, mk, nil)
-	return &models.EncryptedMK{
-		EncryptedMK: encryptedMK,
-		Nonce:       nonce,
-	}
+
+	key := storage.storedMK
+	require.Equal(t, constants.DefaultServerKeyID, key.ServerKeyID)
+
+	keyring, err := kms.NewKeyring(map[string][]byte{constants.DefaultServerKeyID: oldKey, "k2": newKey}, "k2")
+	require.NoError(t, err)
+	km := NewKeyManager(storage, keyring)
+	require.Equal(t, "k2", km.ActiveKeyID())
+
+	require.NoError(t, km.RewrapServerKey(ctx, key))
+	require.Equal(t, "k2", key.ServerKeyID)
+
+	got, err := km.GetMasterKey(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, mk, got)
+
+	// The old key alone can no longer open the re-wrapped master key.
+	_, err = NewKeyManager(storage, testKeyring(t, oldKey)).GetMasterKey(ctx, 1)
+	require.ErrorIs(t, err, kms.ErrUnknownKeyID)
 }
@@ -0,0 +1,468 @@
+// Package crypto provides cryptographic utilities for data encryption and decryption using envelope encryption.
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Segmented stream format.
+//
+// A stream starts with a header: the magic "GKSE", the format version, the plaintext chunk size
+// as a big-endian uint32 and a random 12-byte base nonce. It is followed by chunks of at most
+// chunk size plaintext bytes, each sealed with AES-GCM under the header as additional data. The
+// nonce of chunk i is the base nonce with i XORed into bytes 7-10 and, for the last chunk, 1
+// XORed into byte 11, so chunks can be neither reordered nor dropped from the end.
+const (
+	// StreamChunkSize is the plaintext size of the chunks written by NewStreamWriter.
+	StreamChunkSize = 64 * 1024
+	// MaxStreamChunkSize is the largest chunk size NewStreamReader accepts.
+	MaxStreamChunkSize = 16 * 1024 * 1024
+
+	streamVersion   = 1
+	streamNonceSize = 12
+	streamHeaderLen = len(streamMagic) + 1 + 4 + streamNonceSize
+)
+
+var streamMagic = [4]byte{'G', 'K', 'S', 'E'}
+
+var (
+	// ErrStreamFormat is returned for data that is not a supported segmented stream.
+	ErrStreamFormat = errors.New("unsupported encrypted stream format")
+	// ErrStreamCorrupted is returned when a chunk fails authentication, including when chunks
+	// were reordered or the stream was truncated.
+	ErrStreamCorrupted = errors.New("encrypted stream is corrupted or truncated")
+)
+
+// StreamWriter encrypts data written to it into the segmented stream format.
+//
+// A chunk is sealed only once the next byte arrives, so the last chunk can be marked as final
+// by Close; Close must be called for the stream to be readable.
+type StreamWriter struct {
+	dst     io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	buf     []byte
+	out     []byte
+	counter uint32
+	started bool
+	closed  bool
+	err     error
+}
+
+// NewStreamWriter returns a StreamWriter that encrypts into dst with the 32-byte key.
+func NewStreamWriter(dst io.Writer, key []byte) (*StreamWriter, error) {
+	return newStreamWriter(dst, key, StreamChunkSize)
+}
+
+func newStreamWriter(dst io.Writer, key []byte, chunkSize int) (*StreamWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, streamHeaderLen)
+	copy(header, streamMagic[:])
+	header[len(streamMagic)] = streamVersion
+	binary.BigEndian.PutUint32(header[len(streamMagic)+1:], uint32(chunkSize))
+	if _, err := rand.Read(header[streamHeaderLen-streamNonceSize:]); err != nil {
+		return nil, fmt.Errorf("error generate stream nonce: %w", err)
+	}
+
+	return &StreamWriter{
+		dst:    dst,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, 0, chunkSize),
+		out:    make([]byte, 0, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+// Write encrypts p, writing every completed chunk to the destination.
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to closed stream")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		if len(w.buf) == cap(w.buf) {
+			if err := w.seal(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close seals the buffered data as the final chunk. It does not close the destination.
+func (w *StreamWriter) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.seal(true)
+}
+
+// seal encrypts the buffered data as the next chunk and writes it, preceded by the header for
+// the first chunk.
+func (w *StreamWriter) seal(final bool) error {
+	if !w.started {
+		if _, err := w.dst.Write(w.header); err != nil {
+			w.err = err
+			return err
+		}
+		w.started = true
+	}
+
+	if w.counter == math.MaxUint32 {
+		w.err = errors.New("encrypted stream is too long")
+		return w.err
+	}
+
+	w.out = w.aead.Seal(w.out[:0], chunkNonce(w.header, w.counter, final), w.buf, w.header)
+	w.counter++
+	w.buf = w.buf[:0]
+
+	if _, err := w.dst.Write(w.out); err != nil {
+		w.err = err
+		return err
+	}
+
+	return nil
+}
+
+// StreamReader decrypts a segmented stream, authenticating every chunk before returning it.
+type StreamReader struct {
+	src       *bufio.Reader
+	aead      cipher.AEAD
+	header    []byte
+	chunkSize int
+	in        []byte
+	plain     []byte
+	pos       int
+	counter   uint32
+	done      bool
+	err       error
+}
+
+// NewStreamReader reads the stream header from src and returns a StreamReader decrypting the
+// chunks that follow it with the 32-byte key.
+func NewStreamReader(src io.Reader, key []byte) (*StreamReader, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, streamHeaderLen)
+	if _, err := io.ReadFull(src, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrStreamFormat
+		}
+		return nil, err
+	}
+	chunkSize, err := parseStreamHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamReader{
+		src:       bufio.NewReader(src),
+		aead:      aead,
+		header:    header,
+		chunkSize: chunkSize,
+		in:        make([]byte, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+// Read returns decrypted data. It returns ErrStreamCorrupted if a chunk was modified, reordered
+// or the stream ends without its final chunk.
+func (r *StreamReader) Read(p []byte) (int, error) {
+	for r.pos == len(r.plain) {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			r.err = err
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plain[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// PlaintextSize returns the size of the plaintext of a stream whose total size, header
+// included, is ciphertextSize.
+func (r *StreamReader) PlaintextSize(ciphertextSize int64) int64 {
+	_, size := streamLayout(ciphertextSize, r.chunkSize, r.aead.Overhead())
+	return max(size, 0)
+}
+
+// open reads and decrypts the next chunk. A chunk is final when nothing follows it.
+func (r *StreamReader) open() error {
+	n, err := io.ReadFull(r.src, r.in)
+	final := false
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		final = true
+	case err != nil:
+		return err
+	default:
+		if _, errPeek := r.src.Peek(1); errors.Is(errPeek, io.EOF) {
+			final = true
+		} else if errPeek != nil {
+			return errPeek
+		}
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], chunkNonce(r.header, r.counter, final), r.in[:n], r.header)
+	if err != nil {
+		return ErrStreamCorrupted
+	}
+
+	r.plain = plain
+	r.pos = 0
+	r.counter++
+	r.done = final
+	return nil
+}
+
+// StreamReaderAt decrypts arbitrary ranges of a segmented stream stored in an io.ReaderAt,
+// reading and authenticating only the chunks that overlap the requested range.
+//
+// Every ReadAt call decrypts the chunks it touches anew, so reads should use buffers of at
+// least the chunk size. It is safe for concurrent use if src is.
+type StreamReaderAt struct {
+	src       io.ReaderAt
+	aead      cipher.AEAD
+	header    []byte
+	chunkSize int
+	chunks    int64
+	size      int64
+	end       int64
+}
+
+// NewStreamReaderAt reads the stream header from src, whose total size is size, and returns a
+// StreamReaderAt decrypting it with the 32-byte key.
+func NewStreamReaderAt(src io.ReaderAt, size int64, key []byte) (*StreamReaderAt, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if size < int64(streamHeaderLen) {
+		return nil, ErrStreamFormat
+	}
+	header := make([]byte, streamHeaderLen)
+	if _, err := src.ReadAt(header, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	chunkSize, err := parseStreamHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, plaintextSize := streamLayout(size, chunkSize, aead.Overhead())
+	if plaintextSize < 0 || chunks > math.MaxUint32 {
+		return nil, ErrStreamCorrupted
+	}
+
+	return &StreamReaderAt{
+		src:       src,
+		aead:      aead,
+		header:    header,
+		chunkSize: chunkSize,
+		chunks:    chunks,
+		size:      plaintextSize,
+		end:       size,
+	}, nil
+}
+
+// Size returns the size of the plaintext.
+func (r *StreamReaderAt) Size() int64 {
+	return r.size
+}
+
+// ReadAt reads len(p) bytes of plaintext starting at offset off. It returns ErrStreamCorrupted
+// if a chunk in the range fails authentication.
+func (r *StreamReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	sealed := int64(r.chunkSize + r.aead.Overhead())
+	in := make([]byte, sealed)
+	var plain []byte
+
+	n := 0
+	for n < len(p) && off < r.size {
+		index := off / int64(r.chunkSize)
+		start := int64(streamHeaderLen) + index*sealed
+		chunk := in[:min(sealed, r.end-start)]
+		if _, err := r.src.ReadAt(chunk, start); err != nil && !errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		var err error
+		plain, err = r.aead.Open(plain[:0], chunkNonce(r.header, uint32(index), index == r.chunks-1), chunk, r.header)
+		if err != nil {
+			return n, ErrStreamCorrupted
+		}
+
+		copied := copy(p[n:], plain[off-index*int64(r.chunkSize):])
+		n += copied
+		off += int64(copied)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// StreamSealer encrypts parts of a segmented stream independently of each other, for uploads
+// whose data arrives in separate requests that may be retried.
+//
+// Every part starts at a chunk boundary and holds whole chunks, except the last one, and is
+// sealed the same way each time: a retried part must therefore carry the same plaintext, or
+// the chunk nonces would be reused with different data.
+type StreamSealer struct {
+	aead      cipher.AEAD
+	header    []byte
+	chunkSize int
+}
+
+// NewStreamSealer returns a StreamSealer for a new stream encrypted with the 32-byte key.
+func NewStreamSealer(key []byte) (*StreamSealer, error) {
+	w, err := NewStreamWriter(io.Discard, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamSealer{aead: w.aead, header: w.header, chunkSize: StreamChunkSize}, nil
+}
+
+// OpenStreamSealer returns a StreamSealer continuing the stream with the given header.
+func OpenStreamSealer(key, header []byte) (*StreamSealer, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(header) != streamHeaderLen {
+		return nil, ErrStreamFormat
+	}
+	chunkSize, err := parseStreamHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamSealer{aead: aead, header: bytes.Clone(header), chunkSize: chunkSize}, nil
+}
+
+// Header returns the stream header, which OpenStreamSealer needs to continue the stream.
+func (s *StreamSealer) Header() []byte {
+	return bytes.Clone(s.header)
+}
+
+// ChunkSize returns the plaintext chunk size; part offsets must be multiples of it.
+func (s *StreamSealer) ChunkSize() int {
+	return s.chunkSize
+}
+
+// SealPart encrypts the plaintext starting at offset off of the stream. The part at offset 0
+// is preceded by the stream header; last marks the part ending the stream.
+func (s *StreamSealer) SealPart(plaintext []byte, off int64, last bool) ([]byte, error) {
+	if off < 0 || off%int64(s.chunkSize) != 0 {
+		return nil, fmt.Errorf("part offset %d is not a multiple of the chunk size", off)
+	}
+	if !last && (len(plaintext) == 0 || len(plaintext)%s.chunkSize != 0) {
+		return nil, errors.New("only the last part may hold a partial chunk")
+	}
+
+	chunks := max((len(plaintext)+s.chunkSize-1)/s.chunkSize, 1)
+	counter := off / int64(s.chunkSize)
+	if counter+int64(chunks) > math.MaxUint32 {
+		return nil, errors.New("encrypted stream is too long")
+	}
+
+	out := make([]byte, 0, len(s.header)+len(plaintext)+chunks*s.aead.Overhead())
+	if off == 0 {
+		out = append(out, s.header...)
+	}
+	for i := range chunks {
+		chunk := plaintext[i*s.chunkSize : min((i+1)*s.chunkSize, len(plaintext))]
+		nonce := chunkNonce(s.header, uint32(counter)+uint32(i), last && i == chunks-1)
+		out = s.aead.Seal(out, nonce, chunk, s.header)
+	}
+
+	return out, nil
+}
+
+// parseStreamHeader checks the magic and version of a stream header and returns its chunk size.
+func parseStreamHeader(header []byte) (int, error) {
+	if !bytes.Equal(header[:len(streamMagic)], streamMagic[:]) || header[len(streamMagic)] != streamVersion {
+		return 0, ErrStreamFormat
+	}
+
+	chunkSize := int(binary.BigEndian.Uint32(header[len(streamMagic)+1:]))
+	if chunkSize <= 0 || chunkSize > MaxStreamChunkSize {
+		return 0, fmt.Errorf("%w: chunk size %d", ErrStreamFormat, chunkSize)
+	}
+
+	return chunkSize, nil
+}
+
+// streamLayout returns the number of chunks and the plaintext size of a stream whose total size
+// is ciphertextSize. The plaintext size is negative if no valid stream has that size.
+func streamLayout(ciphertextSize int64, chunkSize, overhead int) (int64, int64) {
+	body := ciphertextSize - int64(streamHeaderLen)
+	sealed := int64(chunkSize + overhead)
+	chunks := max((body+sealed-1)/sealed, 1)
+	size := body - chunks*int64(overhead)
+	if last := body - (chunks-1)*sealed; last < int64(overhead) {
+		size = -1
+	}
+	return chunks, size
+}
+
+// chunkNonce derives the nonce of chunk counter from the base nonce in header.
+func chunkNonce(header []byte, counter uint32, final bool) []byte {
+	nonce := make([]byte, streamNonceSize)
+	copy(nonce, header[streamHeaderLen-streamNonceSize:])
+
+	var c [4]byte
+	binary.BigEndian.PutUint32(c[:], counter)
+	for i := range c {
+		nonce[7+i] ^= c[i]
+	}
+	if final {
+		nonce[11] ^= 1
+	}
+
+	return nonce
+}
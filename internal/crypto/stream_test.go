@@ -0,0 +1,315 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+func encryptStream(t *testing.T, key, plaintext []byte, chunkSize int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := newStreamWriter(&buf, key, chunkSize)
+	require.NoError(t, err)
+
+	// Пишем кусками, не совпадающими с размером чанка
+	for p := plaintext; len(p) > 0; {
+		n := min(len(p), 7)
+		_, err = w.Write(p[:n])
+		require.NoError(t, err)
+		p = p[n:]
+	}
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	for _, size := range []int{0, 1, 15, 16, 17, 48, 100} {
+		plaintext := make([]byte, size)
+		_, err := rand.Read(plaintext)
+		require.NoError(t, err)
+
+		ciphertext := encryptStream(t, key, plaintext, 16)
+
+		r, err := NewStreamReader(bytes.NewReader(ciphertext), key)
+		require.NoError(t, err)
+		require.Equal(t, int64(size), r.PlaintextSize(int64(len(ciphertext))), "size %d", size)
+
+		got, err := io.ReadAll(r)
+		require.NoError(t, err, "size %d", size)
+		require.Equal(t, plaintext, got, "size %d", size)
+	}
+}
+
+func TestStream_Tampering(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	plaintext := bytes.Repeat([]byte("0123456789"), 10)
+	ciphertext := encryptStream(t, key, plaintext, 16)
+	sealed := 16 + 16
+
+	read := func(data []byte) error {
+		r, err := NewStreamReader(bytes.NewReader(data), key)
+		if err != nil {
+			return err
+		}
+		_, err = io.ReadAll(r)
+		return err
+	}
+
+	t.Run("truncated at a chunk boundary", func(t *testing.T) {
+		require.ErrorIs(t, read(ciphertext[:streamHeaderLen+2*sealed]), ErrStreamCorrupted)
+	})
+
+	t.Run("truncated inside a chunk", func(t *testing.T) {
+		require.ErrorIs(t, read(ciphertext[:len(ciphertext)-3]), ErrStreamCorrupted)
+	})
+
+	t.Run("chunks swapped", func(t *testing.T) {
+		swapped := bytes.Clone(ciphertext)
+		first := swapped[streamHeaderLen : streamHeaderLen+sealed]
+		second := bytes.Clone(swapped[streamHeaderLen+sealed : streamHeaderLen+2*sealed])
+		copy(swapped[streamHeaderLen+sealed:], first)
+		copy(swapped[streamHeaderLen:], second)
+		require.ErrorIs(t, read(swapped), ErrStreamCorrupted)
+	})
+
+	t.Run("header modified", func(t *testing.T) {
+		modified := bytes.Clone(ciphertext)
+		modified[streamHeaderLen-1] ^= 1
+		require.ErrorIs(t, read(modified), ErrStreamCorrupted)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		r, err := NewStreamReader(bytes.NewReader(ciphertext), bytes.Repeat([]byte{2}, 32))
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrStreamCorrupted)
+	})
+
+	t.Run("not a stream", func(t *testing.T) {
+		require.ErrorIs(t, read([]byte("legacy single-shot ciphertext")), ErrStreamFormat)
+		require.ErrorIs(t, read(nil), ErrStreamFormat)
+	})
+}
+
+func TestStreamReaderAt(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	for _, size := range []int{0, 1, 16, 17, 100} {
+		plaintext := make([]byte, size)
+		_, err := rand.Read(plaintext)
+		require.NoError(t, err)
+		ciphertext := encryptStream(t, key, plaintext, 16)
+
+		r, err := NewStreamReaderAt(bytes.NewReader(ciphertext), int64(len(ciphertext)), key)
+		require.NoError(t, err, "size %d", size)
+		require.Equal(t, int64(size), r.Size())
+
+		got, err := io.ReadAll(io.NewSectionReader(r, 0, r.Size()))
+		require.NoError(t, err, "size %d", size)
+		require.Equal(t, plaintext, got, "size %d", size)
+
+		// Every range, including ones crossing chunk boundaries and running past the end
+		for off := 0; off <= size; off += 5 {
+			buf := make([]byte, 21)
+			n, errRead := r.ReadAt(buf, int64(off))
+			want := plaintext[off:min(off+len(buf), size)]
+			require.Equal(t, want, buf[:n], "size %d, offset %d", size, off)
+			if n < len(buf) {
+				require.ErrorIs(t, errRead, io.EOF)
+			} else {
+				require.NoError(t, errRead)
+			}
+		}
+	}
+}
+
+func TestStreamReaderAt_Tampering(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	plaintext := bytes.Repeat([]byte("0123456789"), 10)
+	ciphertext := encryptStream(t, key, plaintext, 16)
+	sealed := 16 + 16
+
+	readAt := func(data []byte, off int64) error {
+		r, err := NewStreamReaderAt(bytes.NewReader(data), int64(len(data)), key)
+		if err != nil {
+			return err
+		}
+		_, err = r.ReadAt(make([]byte, 8), off)
+		return err
+	}
+
+	modified := bytes.Clone(ciphertext)
+	modified[streamHeaderLen+sealed+3] ^= 1
+	require.NoError(t, readAt(modified, 0), "untouched chunks stay readable")
+	require.ErrorIs(t, readAt(modified, 20), ErrStreamCorrupted)
+
+	truncated := ciphertext[:streamHeaderLen+2*sealed]
+	require.ErrorIs(t, readAt(truncated, 20), ErrStreamCorrupted, "the new last chunk is not final")
+
+	require.ErrorIs(t, readAt(ciphertext[:streamHeaderLen+3], 0), ErrStreamCorrupted)
+	require.ErrorIs(t, readAt([]byte("legacy single-shot ciphertext"), 0), ErrStreamFormat)
+}
+
+func TestEnvelope_EncryptDecryptStream(t *testing.T) {
+	ctx := context.Background()
+	masterKey := []byte("01234567890123456789012345678901")
+	e := NewEnvelope(&mockStorage{})
+	plaintext := bytes.Repeat([]byte("large file "), StreamChunkSize/5)
+
+	var buf bytes.Buffer
+	w, enc, err := e.EncryptStream(ctx, masterKey, &buf)
+	require.NoError(t, err)
+	require.Empty(t, enc.DataNonce)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	userData := models.DBUserData{EncryptedDek: enc.EncryptedDek, DekNonce: enc.DekNonce}
+	r, size, err := e.DecryptReader(ctx, userData, masterKey, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(plaintext)), size)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+
+	// DecryptUserData reads data without a DataNonce as a stream
+	userData.EncryptedData = buf.Bytes()
+	got, err = e.DecryptUserData(ctx, userData, masterKey)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+
+	_, _, err = e.DecryptReader(ctx, userData, bytes.Repeat([]byte{9}, 32), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.ErrorContains(t, err, "failed to decrypt DEK")
+}
+
+func TestEnvelope_DecryptReaderAt(t *testing.T) {
+	ctx := context.Background()
+	masterKey := []byte("01234567890123456789012345678901")
+	e := NewEnvelope(&mockStorage{})
+	plaintext := bytes.Repeat([]byte("0123456789"), StreamChunkSize/4)
+
+	for name, encrypt := range map[string]func(context.Context, []byte, []byte) (*models.EncryptedData, error){
+		"segmented":   e.EncryptFile,
+		"single-shot": e.EncryptUserData,
+	} {
+		t.Run(name, func(t *testing.T) {
+			enc, err := encrypt(ctx, masterKey, plaintext)
+			require.NoError(t, err)
+			userData := models.DBUserData{DataNonce: enc.DataNonce, EncryptedDek: enc.EncryptedDek, DekNonce: enc.DekNonce}
+
+			src := bytes.NewReader(enc.EncryptedData)
+			r, size, err := e.DecryptReaderAt(ctx, userData, masterKey, src, src.Size())
+			require.NoError(t, err)
+			require.Equal(t, int64(len(plaintext)), size)
+
+			off := int64(StreamChunkSize - 5)
+			got, err := io.ReadAll(io.NewSectionReader(r, off, 100))
+			require.NoError(t, err)
+			require.Equal(t, plaintext[off:off+100], got)
+		})
+	}
+}
+
+func TestEnvelope_EncryptFile(t *testing.T) {
+	ctx := context.Background()
+	masterKey := []byte("01234567890123456789012345678901")
+	e := NewEnvelope(&mockStorage{})
+	plaintext := []byte("scanned passport")
+
+	enc, err := e.EncryptFile(ctx, masterKey, plaintext)
+	require.NoError(t, err)
+	require.Empty(t, enc.DataNonce)
+
+	userData := models.DBUserData{EncryptedData: enc.EncryptedData, EncryptedDek: enc.EncryptedDek, DekNonce: enc.DekNonce}
+	got, err := e.DecryptUserData(ctx, userData, masterKey)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+
+	// Files encrypted before the segmented format are still read
+	legacy, err := e.EncryptUserData(ctx, masterKey, plaintext)
+	require.NoError(t, err)
+	userData = models.DBUserData{DataNonce: legacy.DataNonce, EncryptedDek: legacy.EncryptedDek, DekNonce: legacy.DekNonce}
+	r, size, err := e.DecryptReader(ctx, userData, masterKey, bytes.NewReader(legacy.EncryptedData), int64(len(legacy.EncryptedData)))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(plaintext)), size)
+	got, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+}
+
+func TestStreamSealer(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	plaintext := make([]byte, 2*StreamChunkSize+100)
+	_, err := rand.Read(plaintext)
+	require.NoError(t, err)
+
+	sealer, err := NewStreamSealer(key)
+	require.NoError(t, err)
+
+	// Parts sealed out of order by different sealers form one stream
+	resumed, err := OpenStreamSealer(key, sealer.Header())
+	require.NoError(t, err)
+	second, err := resumed.SealPart(plaintext[StreamChunkSize:], StreamChunkSize, true)
+	require.NoError(t, err)
+	first, err := sealer.SealPart(plaintext[:StreamChunkSize], 0, false)
+	require.NoError(t, err)
+
+	again, err := sealer.SealPart(plaintext[:StreamChunkSize], 0, false)
+	require.NoError(t, err)
+	require.Equal(t, first, again, "retried parts are identical")
+
+	r, err := NewStreamReader(bytes.NewReader(append(first, second...)), key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+
+	// Without the last part the stream is truncated
+	r, err = NewStreamReader(bytes.NewReader(first), key)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, ErrStreamCorrupted)
+
+	_, err = sealer.SealPart(plaintext[:10], 0, false)
+	require.Error(t, err, "only the last part may be partial")
+	_, err = sealer.SealPart(plaintext, 10, true)
+	require.Error(t, err, "parts start at chunk boundaries")
+	_, err = OpenStreamSealer(key, []byte("not a header"))
+	require.ErrorIs(t, err, ErrStreamFormat)
+}
+
+func TestEnvelope_StreamSealer(t *testing.T) {
+	ctx := context.Background()
+	masterKey := []byte("01234567890123456789012345678901")
+	e := NewEnvelope(&mockStorage{})
+	plaintext := []byte("scanned in the field")
+
+	sealer, enc, err := e.NewStreamSealer(ctx, masterKey)
+	require.NoError(t, err)
+	require.Empty(t, enc.DataNonce)
+
+	userData := models.DBUserData{EncryptedDek: enc.EncryptedDek, DekNonce: enc.DekNonce}
+	resumed, err := e.OpenStreamSealer(ctx, userData, masterKey, sealer.Header())
+	require.NoError(t, err)
+
+	part, err := resumed.SealPart(plaintext, 0, true)
+	require.NoError(t, err)
+	userData.EncryptedData = part
+	got, err := e.DecryptUserData(ctx, userData, masterKey)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+
+	_, err = e.OpenStreamSealer(ctx, userData, bytes.Repeat([]byte{9}, 32), sealer.Header())
+	require.ErrorContains(t, err, "failed to decrypt DEK")
+}
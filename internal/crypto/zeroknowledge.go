@@ -0,0 +1,111 @@
+// Package crypto provides cryptographic utilities for data encryption and decryption using envelope encryption.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// HKDF labels separating the keys derived from the stretched password.
+const (
+	encryptionKeyInfo = "gophkeeper/zk/encryption"
+	authKeyInfo       = "gophkeeper/zk/auth"
+)
+
+// ClientKeys are the keys a zero-knowledge client derives from the user's password.
+type ClientKeys struct {
+	// EncryptionKey wraps the master key. It never leaves the client.
+	EncryptionKey []byte
+	// AuthKey is sent to the server in place of the password, so the server can authenticate
+	// the user without learning anything that decrypts the vault.
+	AuthKey string
+}
+
+// DeriveClientKeys stretches the password with p and splits the result into an encryption key
+// and an authentication key using HKDF-SHA256.
+func DeriveClientKeys(password string, p models.KDFParams) (*ClientKeys, error) {
+	stretched, err := DeriveKey(password, p)
+	if err != nil {
+		return nil, err
+	}
+
+	encKey, err := hkdf.Expand(sha256.New, stretched, encryptionKeyInfo, constants.KeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+
+	authKey, err := hkdf.Expand(sha256.New, stretched, authKeyInfo, constants.KeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive auth key: %w", err)
+	}
+
+	return &ClientKeys{
+		EncryptionKey: encKey,
+		AuthKey:       hex.EncodeToString(authKey),
+	}, nil
+}
+
+// NewMasterKey generates a random master key.
+func NewMasterKey() ([]byte, error) {
+	mk := make([]byte, constants.KeyLength)
+	if _, err := rand.Read(mk); err != nil {
+		return nil, fmt.Errorf("failed to generate master key: %w", err)
+	}
+	return mk, nil
+}
+
+// WrapKey seals key with AES-GCM under kek and returns nonce || ciphertext.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(key)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, key, nil), nil
+}
+
+// UnwrapKey opens a key produced by WrapKey.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("wrapped key is too short")
+	}
+
+	key, err := gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key: %w", err)
+	}
+
+	return key, nil
+}
+
+// newGCM creates an AES-GCM AEAD for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
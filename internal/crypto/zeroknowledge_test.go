@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveClientKeys(t *testing.T) {
+	params, err := NewKDFParams()
+	require.NoError(t, err)
+	require.NoError(t, ValidateKDFParams(params))
+
+	keys, err := DeriveClientKeys("password123", params)
+	require.NoError(t, err)
+	require.Len(t, keys.EncryptionKey, 32)
+	require.Len(t, keys.AuthKey, 64)
+
+	again, err := DeriveClientKeys("password123", params)
+	require.NoError(t, err)
+	require.Equal(t, keys, again)
+
+	other, err := DeriveClientKeys("password124", params)
+	require.NoError(t, err)
+	require.NotEqual(t, keys.EncryptionKey, other.EncryptionKey)
+	require.NotEqual(t, keys.AuthKey, other.AuthKey)
+}
+
+func TestValidateKDFParams(t *testing.T) {
+	params, err := NewKDFParams()
+	require.NoError(t, err)
+
+	weak := params
+	weak.Memory = 1024
+	require.ErrorIs(t, ValidateKDFParams(weak), ErrInvalidKDFParams)
+
+	noSalt := params
+	noSalt.Salt = nil
+	require.ErrorIs(t, ValidateKDFParams(noSalt), ErrInvalidKDFParams)
+
+	unknown := params
+	unknown.Algorithm = "pbkdf2"
+	require.ErrorIs(t, ValidateKDFParams(unknown), ErrInvalidKDFParams)
+	_, err = DeriveKey("password123", unknown)
+	require.ErrorIs(t, err, ErrInvalidKDFParams)
+}
+
+func TestWrapUnwrapKey(t *testing.T) {
+	kek := []byte("01234567890123456789012345678901")
+	mk, err := NewMasterKey()
+	require.NoError(t, err)
+
+	wrapped, err := WrapKey(kek, mk)
+	require.NoError(t, err)
+
+	unwrapped, err := UnwrapKey(kek, wrapped)
+	require.NoError(t, err)
+	require.Equal(t, mk, unwrapped)
+
+	_, err = UnwrapKey([]byte("99999999999999999999999999999999"), wrapped)
+	require.Error(t, err)
+
+	_, err = UnwrapKey(kek, wrapped[:8])
+	require.Error(t, err)
+}
+
+func TestClientSideEnvelope(t *testing.T) {
+	ctx := context.Background()
+	params, err := NewKDFParams()
+	require.NoError(t, err)
+
+	keys, err := DeriveClientKeys("password123", params)
+	require.NoError(t, err)
+	mk, err := NewMasterKey()
+	require.NoError(t, err)
+	wrapped, err := WrapKey(keys.EncryptionKey, mk)
+	require.NoError(t, err)
+
+	// A fresh login only has the password and the stored wrapped key.
+	relogin, err := DeriveClientKeys("password123", params)
+	require.NoError(t, err)
+	opened, err := UnwrapKey(relogin.EncryptionKey, wrapped)
+	require.NoError(t, err)
+
+	e := NewEnvelope(nil)
+	enc, err := e.EncryptUserData(ctx, mk, []byte("secret"))
+	require.NoError(t, err)
+
+	plain, err := e.DecryptUserData(ctx, models.DBUserData{
+		EncryptedData: enc.EncryptedData,
+		DataNonce:     enc.DataNonce,
+		EncryptedDek:  enc.EncryptedDek,
+		DekNonce:      enc.DekNonce,
+	}, opened)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plain)
+}
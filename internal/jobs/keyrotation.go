@@ -0,0 +1,184 @@
+// Package jobs provides long-running background jobs of the GophKeeper server.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// DefaultKeyRotationBatch is the number of master keys re-wrapped per transaction.
+const DefaultKeyRotationBatch = 100
+
+// ErrActiveKeyChanged is returned when the active server key differs from the target key of a job.
+var ErrActiveKeyChanged = errors.New("active server key differs from the rotation target")
+
+// KeyRotator starts server key rotations and reports their progress.
+//
+//go:generate mockery --name=KeyRotator --output=../mocks/ --case=underscore
+type KeyRotator interface {
+	// Start begins re-wrapping all master keys with the active server key in the background.
+	// Returns models.ErrKeyRotationInProgress if a rotation is already running.
+	Start(ctx context.Context) (*models.KeyRotationJob, error)
+	// Status returns the most recent rotation job.
+	// Returns models.ErrKeyRotationNotFound if no rotation was started.
+	Status(ctx context.Context) (*models.KeyRotationJob, error)
+}
+
+// KeyRotationStorage persists key rotation jobs and the master keys they re-wrap.
+type KeyRotationStorage interface {
+	CreateKeyRotationJob(ctx context.Context, targetKeyID string) (*models.KeyRotationJob, error)
+	GetKeyRotationJob(ctx context.Context) (*models.KeyRotationJob, error)
+	RewrapMasterKeysBatch(ctx context.Context, jobID, limit int, rewrap func(key *models.EncryptedMK) error) (int, error)
+	RewrapTOTPSecretsBatch(ctx context.Context, jobID, limit int, rewrap func(secret *models.WrappedSecret) error) (int, error)
+	FinishKeyRotationJob(ctx context.Context, jobID int, status, reason string) error
+}
+
+// ServerKeys re-wraps master keys and other server-sealed secrets with the active server key.
+type ServerKeys interface {
+	ActiveKeyID() string
+	RewrapServerKey(ctx context.Context, key *models.EncryptedMK) error
+	RewrapSecret(ctx context.Context, secret *models.WrappedSecret) error
+}
+
+// KeyRotation re-wraps the server-sealed copies of all master keys, and then all TOTP secrets,
+// with the active server key.
+//
+// Keys are processed in batches, and the progress is stored with every batch, so a rotation
+// interrupted by a restart is picked up again by Resume.
+type KeyRotation struct {
+	base      context.Context
+	storage   KeyRotationStorage
+	keys      ServerKeys
+	batchSize int
+	wg        sync.WaitGroup
+}
+
+var _ KeyRotator = (*KeyRotation)(nil)
+
+// NewKeyRotation creates a KeyRotation whose jobs run until base is cancelled.
+// A non-positive batchSize selects DefaultKeyRotationBatch.
+func NewKeyRotation(base context.Context, storage KeyRotationStorage, keys ServerKeys, batchSize int) *KeyRotation {
+	if batchSize <= 0 {
+		batchSize = DefaultKeyRotationBatch
+	}
+
+	return &KeyRotation{
+		base:      base,
+		storage:   storage,
+		keys:      keys,
+		batchSize: batchSize,
+	}
+}
+
+// Start creates a rotation job targeting the active server key and runs it in the background.
+func (r *KeyRotation) Start(ctx context.Context) (*models.KeyRotationJob, error) {
+	job, err := r.storage.CreateKeyRotationJob(ctx, r.keys.ActiveKeyID())
+	if err != nil {
+		return nil, err
+	}
+
+	r.run(job)
+
+	return job, nil
+}
+
+// Resume continues the most recent rotation job if it was interrupted while running.
+func (r *KeyRotation) Resume(ctx context.Context) error {
+	job, err := r.storage.GetKeyRotationJob(ctx)
+	if err != nil {
+		if errors.Is(err, models.ErrKeyRotationNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if job.Status == models.JobStatusRunning {
+		slog.Info("resuming key rotation", "job", job.ID, "target", job.TargetKeyID, "last_user_id", job.LastUserID)
+		r.run(job)
+	}
+
+	return nil
+}
+
+// Status returns the most recent rotation job.
+func (r *KeyRotation) Status(ctx context.Context) (*models.KeyRotationJob, error) {
+	return r.storage.GetKeyRotationJob(ctx)
+}
+
+// Wait blocks until all started jobs have stopped.
+func (r *KeyRotation) Wait() {
+	r.wg.Wait()
+}
+
+// run processes job in a background goroutine.
+func (r *KeyRotation) run(job *models.KeyRotationJob) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		err := r.process(r.base, job)
+		switch {
+		case err == nil:
+			slog.Info("key rotation finished", "job", job.ID, "target", job.TargetKeyID)
+			r.finish(job.ID, models.JobStatusDone, "")
+		case r.base.Err() != nil:
+			// Shutdown: the job stays running and is resumed on the next start.
+			slog.Info("key rotation interrupted", "job", job.ID)
+		default:
+			slog.Error("key rotation failed", "job", job.ID, "error", err)
+			r.finish(job.ID, models.JobStatusFailed, err.Error())
+		}
+	}()
+}
+
+// process re-wraps batches of master keys and then of TOTP secrets until none are left.
+func (r *KeyRotation) process(ctx context.Context, job *models.KeyRotationJob) error {
+	if active := r.keys.ActiveKeyID(); active != job.TargetKeyID {
+		return fmt.Errorf("%w: active %q, target %q", ErrActiveKeyChanged, active, job.TargetKeyID)
+	}
+
+	err := r.batches(ctx, job, func() (int, error) {
+		return r.storage.RewrapMasterKeysBatch(ctx, job.ID, r.batchSize, func(key *models.EncryptedMK) error {
+			return r.keys.RewrapServerKey(ctx, key)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.batches(ctx, job, func() (int, error) {
+		return r.storage.RewrapTOTPSecretsBatch(ctx, job.ID, r.batchSize, func(secret *models.WrappedSecret) error {
+			return r.keys.RewrapSecret(ctx, secret)
+		})
+	})
+}
+
+// batches runs batch until it reports that nothing is left.
+func (r *KeyRotation) batches(ctx context.Context, job *models.KeyRotationJob, batch func() (int, error)) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := batch()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		slog.Debug("key rotation batch done", "job", job.ID, "rewrapped", n)
+	}
+}
+
+// finish records the final status of a job.
+func (r *KeyRotation) finish(jobID int, status, reason string) {
+	if err := r.storage.FinishKeyRotationJob(context.WithoutCancel(r.base), jobID, status, reason); err != nil {
+		slog.Error("failed to finish key rotation", "job", jobID, "error", err)
+	}
+}
@@ -0,0 +1,258 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// fakeRotationStorage keeps master keys and rotation jobs in memory.
+type fakeRotationStorage struct {
+	mu      sync.Mutex
+	keys    map[int]*models.EncryptedMK
+	secrets map[int]*models.WrappedSecret
+	jobs    []*models.KeyRotationJob
+	batches int
+	// failAfter makes RewrapMasterKeysBatch fail once this many batches have been committed.
+	failAfter int
+}
+
+func newFakeRotationStorage(keyIDs ...string) *fakeRotationStorage {
+	s := &fakeRotationStorage{
+		keys:      make(map[int]*models.EncryptedMK),
+		secrets:   make(map[int]*models.WrappedSecret),
+		failAfter: -1,
+	}
+	for i, id := range keyIDs {
+		s.keys[i+1] = &models.EncryptedMK{UserID: i + 1, ServerKeyID: id}
+	}
+	return s
+}
+
+func (s *fakeRotationStorage) CreateKeyRotationJob(_ context.Context, targetKeyID string) (*models.KeyRotationJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.Status == models.JobStatusRunning {
+			return nil, models.ErrKeyRotationInProgress
+		}
+	}
+	job := &models.KeyRotationJob{ID: len(s.jobs) + 1, TargetKeyID: targetKeyID, Status: models.JobStatusRunning}
+	s.jobs = append(s.jobs, job)
+
+	c := *job
+	return &c, nil
+}
+
+func (s *fakeRotationStorage) GetKeyRotationJob(_ context.Context) (*models.KeyRotationJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.jobs) == 0 {
+		return nil, models.ErrKeyRotationNotFound
+	}
+	job := *s.jobs[len(s.jobs)-1]
+	for _, k := range s.keys {
+		if k.ServerKeyID != job.TargetKeyID {
+			job.Remaining++
+		}
+	}
+	for _, secret := range s.secrets {
+		if secret.KeyID != job.TargetKeyID {
+			job.Remaining++
+		}
+	}
+	return &job, nil
+}
+
+func (s *fakeRotationStorage) RewrapMasterKeysBatch(
+	_ context.Context,
+	jobID, limit int,
+	rewrap func(key *models.EncryptedMK) error,
+) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failAfter >= 0 && s.batches >= s.failAfter {
+		return 0, errors.New("db error")
+	}
+
+	job := s.jobs[jobID-1]
+	ids := make([]int, 0, len(s.keys))
+	for id, k := range s.keys {
+		if id > job.LastUserID && k.ServerKeyID != job.TargetKeyID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	for _, id := range ids {
+		if err := rewrap(s.keys[id]); err != nil {
+			return 0, err
+		}
+	}
+	if len(ids) > 0 {
+		job.LastUserID = ids[len(ids)-1]
+		job.Rewrapped += len(ids)
+		s.batches++
+	}
+	return len(ids), nil
+}
+
+func (s *fakeRotationStorage) RewrapTOTPSecretsBatch(
+	_ context.Context,
+	jobID, limit int,
+	rewrap func(secret *models.WrappedSecret) error,
+) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job := s.jobs[jobID-1]
+	ids := make([]int, 0, len(s.secrets))
+	for id, secret := range s.secrets {
+		if secret.KeyID != job.TargetKeyID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	for _, id := range ids {
+		if err := rewrap(s.secrets[id]); err != nil {
+			return 0, err
+		}
+	}
+	job.Rewrapped += len(ids)
+	return len(ids), nil
+}
+
+func (s *fakeRotationStorage) FinishKeyRotationJob(_ context.Context, jobID int, status, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[jobID-1].Status = status
+	s.jobs[jobID-1].Error = reason
+	return nil
+}
+
+// fakeServerKeys re-wraps keys by switching their server key ID.
+type fakeServerKeys struct {
+	active string
+}
+
+func (k *fakeServerKeys) ActiveKeyID() string {
+	return k.active
+}
+
+func (k *fakeServerKeys) RewrapServerKey(_ context.Context, key *models.EncryptedMK) error {
+	key.ServerKeyID = k.active
+	return nil
+}
+
+func (k *fakeServerKeys) RewrapSecret(_ context.Context, secret *models.WrappedSecret) error {
+	secret.KeyID = k.active
+	return nil
+}
+
+func TestKeyRotation_Start(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeRotationStorage("default", "default", "k2", "default", "default")
+	storage.secrets[1] = &models.WrappedSecret{KeyID: "default"}
+	storage.secrets[3] = &models.WrappedSecret{KeyID: "k2"}
+	rotation := NewKeyRotation(ctx, storage, &fakeServerKeys{active: "k2"}, 2)
+
+	job, err := rotation.Start(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "k2", job.TargetKeyID)
+	rotation.Wait()
+
+	status, err := rotation.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.JobStatusDone, status.Status)
+	require.Equal(t, 5, status.Rewrapped)
+	require.Equal(t, 0, status.Remaining)
+	require.Equal(t, 2, storage.batches)
+
+	for _, k := range storage.keys {
+		require.Equal(t, "k2", k.ServerKeyID)
+	}
+	require.Equal(t, "k2", storage.secrets[1].KeyID)
+}
+
+func TestKeyRotation_StartWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeRotationStorage()
+	_, err := storage.CreateKeyRotationJob(ctx, "k2")
+	require.NoError(t, err)
+
+	_, err = NewKeyRotation(ctx, storage, &fakeServerKeys{active: "k2"}, 0).Start(ctx)
+	require.ErrorIs(t, err, models.ErrKeyRotationInProgress)
+}
+
+func TestKeyRotation_FailureAndResume(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeRotationStorage("default", "default", "default", "default", "default")
+	storage.failAfter = 1
+	keys := &fakeServerKeys{active: "k2"}
+
+	rotation := NewKeyRotation(ctx, storage, keys, 2)
+	_, err := rotation.Start(ctx)
+	require.NoError(t, err)
+	rotation.Wait()
+
+	status, err := rotation.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.JobStatusFailed, status.Status)
+	require.Equal(t, "db error", status.Error)
+	require.Equal(t, 2, status.Rewrapped)
+	require.Equal(t, 3, status.Remaining)
+
+	// A job left running by a restart continues after the last committed batch.
+	storage.failAfter = -1
+	storage.jobs[0].Status = models.JobStatusRunning
+	rotation = NewKeyRotation(ctx, storage, keys, 2)
+	require.NoError(t, rotation.Resume(ctx))
+	rotation.Wait()
+
+	status, err = rotation.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.JobStatusDone, status.Status)
+	require.Equal(t, 5, status.Rewrapped)
+	require.Equal(t, 0, status.Remaining)
+}
+
+func TestKeyRotation_ResumeActiveKeyChanged(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeRotationStorage("default")
+	_, err := storage.CreateKeyRotationJob(ctx, "k2")
+	require.NoError(t, err)
+
+	rotation := NewKeyRotation(ctx, storage, &fakeServerKeys{active: "k3"}, 0)
+	require.NoError(t, rotation.Resume(ctx))
+	rotation.Wait()
+
+	status, err := rotation.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.JobStatusFailed, status.Status)
+	require.Contains(t, status.Error, ErrActiveKeyChanged.Error())
+}
+
+func TestKeyRotation_ResumeWithoutJobs(t *testing.T) {
+	ctx := context.Background()
+	rotation := NewKeyRotation(ctx, newFakeRotationStorage(), &fakeServerKeys{active: "k2"}, 0)
+
+	require.NoError(t, rotation.Resume(ctx))
+	rotation.Wait()
+}
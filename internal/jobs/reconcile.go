@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// ObjectReconciler compares the objects in the bucket with the records that reference them.
+//
+//go:generate mockery --name=ObjectReconciler --output=../mocks/ --case=underscore
+type ObjectReconciler interface {
+	// Reconcile reports orphaned objects and references to missing objects; with repair set,
+	// the orphans are deleted.
+	Reconcile(ctx context.Context, repair bool) (*models.ReconcileReport, error)
+}
+
+// ReferenceStorage lists the objects referenced by records and their versions.
+type ReferenceStorage interface {
+	ListObjectReferences(ctx context.Context) ([]models.ObjectReference, error)
+}
+
+// ObjectStore lists and removes objects in the object storage.
+type ObjectStore interface {
+	ObjectDeleter
+	List(ctx context.Context) ([]models.S3Object, error)
+}
+
+// Reconciler periodically looks for objects that no record references, e.g. left behind by a
+// failed save or a pruned version, and for records whose object is missing.
+//
+// Orphans younger than the grace period are skipped, since their record may not be committed
+// yet. A repairing run deletes the orphans; records with a missing object are only reported,
+// because their contents cannot be recovered.
+type Reconciler struct {
+	base     context.Context
+	storage  ReferenceStorage
+	objects  ObjectStore
+	interval time.Duration
+	grace    time.Duration
+	repair   bool
+	now      func() time.Time
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+}
+
+var _ ObjectReconciler = (*Reconciler)(nil)
+
+// NewReconciler creates a Reconciler whose scheduled runs stop when base is cancelled.
+// Scheduled runs delete orphans only when repair is set.
+func NewReconciler(
+	base context.Context,
+	storage ReferenceStorage,
+	objects ObjectStore,
+	interval, grace time.Duration,
+	repair bool,
+) *Reconciler {
+	return &Reconciler{
+		base:     base,
+		storage:  storage,
+		objects:  objects,
+		interval: interval,
+		grace:    grace,
+		repair:   repair,
+		now:      time.Now,
+	}
+}
+
+// Start reconciles the bucket in the background every interval, starting one interval from now.
+func (r *Reconciler) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.base.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if _, err := r.Reconcile(r.base, r.repair); err != nil && r.base.Err() == nil {
+				slog.Error("object reconciliation failed", "error", err)
+			}
+		}
+	}()
+}
+
+// Wait blocks until the background reconciliation has stopped.
+func (r *Reconciler) Wait() {
+	r.wg.Wait()
+}
+
+// Reconcile compares the bucket with the object references of all records and versions.
+//
+// Runs are serialized, so a manual run never overlaps a scheduled one.
+func (r *Reconciler) Reconcile(ctx context.Context, repair bool) (*models.ReconcileReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Objects are listed before references: an object written after the listing cannot be
+	// reported as an orphan, and a reference older than the listing must find its object.
+	listedAt := r.now()
+	objects, err := r.objects.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := r.storage.ListObjectReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconcileReport{
+		Objects:    len(objects),
+		References: len(refs),
+	}
+
+	existing := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		existing[obj.Name] = true
+	}
+
+	referenced := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		referenced[ref.ObjectName] = true
+		if !existing[ref.ObjectName] && ref.ReferencedAt.Before(listedAt) {
+			report.Missing = append(report.Missing, ref)
+		}
+	}
+
+	for _, obj := range objects {
+		if !referenced[obj.Name] && obj.LastModified.Before(listedAt.Add(-r.grace)) {
+			report.Orphans = append(report.Orphans, obj)
+		}
+	}
+
+	if repair {
+		for _, obj := range report.Orphans {
+			if errDelete := r.objects.Delete(ctx, obj.Name); errDelete != nil {
+				slog.Error("failed to delete orphaned object", "object", obj.Name, "error", errDelete)
+				continue
+			}
+			report.Removed++
+		}
+	}
+
+	for _, ref := range report.Missing {
+		slog.Warn("record references a missing object",
+			"user_data_id", ref.UserDataID, "version", ref.Version, "object", ref.ObjectName)
+	}
+	slog.Info("object reconciliation finished",
+		"objects", report.Objects,
+		"references", report.References,
+		"orphans", len(report.Orphans),
+		"missing", len(report.Missing),
+		"removed", report.Removed,
+	)
+
+	return report, nil
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// fakeReferences returns a fixed list of object references.
+type fakeReferences struct {
+	refs []models.ObjectReference
+	err  error
+}
+
+func (s *fakeReferences) ListObjectReferences(context.Context) ([]models.ObjectReference, error) {
+	return s.refs, s.err
+}
+
+// fakeBucket lists a fixed set of objects and records the removed ones.
+type fakeBucket struct {
+	fakeObjects
+	list []models.S3Object
+}
+
+func (b *fakeBucket) List(context.Context) ([]models.S3Object, error) {
+	return b.list, nil
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * time.Hour)
+
+	bucket := &fakeBucket{
+		fakeObjects: fakeObjects{fail: map[string]bool{"orphan-2": true}},
+		list: []models.S3Object{
+			{Name: "current", LastModified: old},
+			{Name: "version", LastModified: old},
+			{Name: "orphan-1", LastModified: old},
+			{Name: "orphan-2", LastModified: old},
+			{Name: "uploading", LastModified: now.Add(-time.Minute)},
+		},
+	}
+	refs := &fakeReferences{refs: []models.ObjectReference{
+		{UserDataID: 1, ObjectName: "current", ReferencedAt: old},
+		{UserDataID: 1, Version: 2, ObjectName: "version", ReferencedAt: old},
+		{UserDataID: 2, ObjectName: "lost", ReferencedAt: old},
+		{UserDataID: 3, ObjectName: "just-saved", ReferencedAt: now.Add(time.Second)},
+	}}
+
+	r := NewReconciler(ctx, refs, bucket, time.Hour, time.Hour, false)
+	r.now = func() time.Time { return now }
+
+	report, err := r.Reconcile(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, 5, report.Objects)
+	require.Equal(t, 4, report.References)
+	require.Len(t, report.Orphans, 2, "objects within the grace period are not orphans")
+	require.Equal(t, "orphan-1", report.Orphans[0].Name)
+	require.Equal(t, []models.ObjectReference{refs.refs[2]}, report.Missing, "references newer than the listing are skipped")
+	require.Zero(t, report.Removed)
+	require.Empty(t, bucket.deleted)
+
+	report, err = r.Reconcile(ctx, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Removed)
+	require.Equal(t, []string{"orphan-1"}, bucket.deleted)
+
+	refs.err = errors.New("db error")
+	_, err = r.Reconcile(ctx, true)
+	require.ErrorContains(t, err, "db error")
+}
+
+func TestReconciler_StartAndWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bucket := &fakeBucket{list: []models.S3Object{{Name: "orphan", LastModified: time.Now().Add(-time.Hour)}}}
+
+	r := NewReconciler(ctx, &fakeReferences{}, bucket, 10*time.Millisecond, time.Minute, true)
+	r.Start()
+
+	require.Eventually(t, func() bool {
+		bucket.mu.Lock()
+		defer bucket.mu.Unlock()
+		return len(bucket.deleted) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	r.Wait()
+}
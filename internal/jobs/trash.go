@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultTrashPurgeBatch is the number of trashed records deleted per transaction.
+const DefaultTrashPurgeBatch = 100
+
+// TrashStorage permanently deletes records that stayed in the trash for too long.
+type TrashStorage interface {
+	PurgeTrash(ctx context.Context, deletedBefore time.Time, limit int) (int, []string, error)
+}
+
+// ObjectDeleter removes objects from the object storage.
+type ObjectDeleter interface {
+	Delete(ctx context.Context, objectName string) error
+}
+
+// TrashPurger periodically deletes records trashed longer than the retention period, and
+// then the objects they referenced.
+//
+// Rows are deleted before their objects, so a failure never leaves a record pointing at a
+// missing object; objects that could not be removed are only logged.
+type TrashPurger struct {
+	base      context.Context
+	storage   TrashStorage
+	objects   ObjectDeleter
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+	wg        sync.WaitGroup
+}
+
+// NewTrashPurger creates a TrashPurger whose runs stop when base is cancelled.
+func NewTrashPurger(
+	base context.Context,
+	storage TrashStorage,
+	objects ObjectDeleter,
+	retention, interval time.Duration,
+) *TrashPurger {
+	return &TrashPurger{
+		base:      base,
+		storage:   storage,
+		objects:   objects,
+		retention: retention,
+		interval:  interval,
+		batchSize: DefaultTrashPurgeBatch,
+		now:       time.Now,
+	}
+}
+
+// Start purges the trash in the background now and then every interval.
+func (p *TrashPurger) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := p.Purge(p.base); err != nil && p.base.Err() == nil {
+				slog.Error("trash purge failed", "error", err)
+			}
+
+			select {
+			case <-p.base.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the background purging has stopped.
+func (p *TrashPurger) Wait() {
+	p.wg.Wait()
+}
+
+// Purge deletes, batch by batch, all records trashed longer than the retention period ago
+// and returns their number.
+func (p *TrashPurger) Purge(ctx context.Context) (int, error) {
+	deletedBefore := p.now().Add(-p.retention)
+	total := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, objects, err := p.storage.PurgeTrash(ctx, deletedBefore, p.batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		DeleteObjects(ctx, p.objects, objects)
+
+		if n < p.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		slog.Info("trash purged", "records", total)
+	}
+
+	return total, nil
+}
+
+// DeleteObjects removes objects that are no longer referenced, logging the ones that could
+// not be removed.
+func DeleteObjects(ctx context.Context, objects ObjectDeleter, names []string) {
+	for _, name := range names {
+		if err := objects.Delete(ctx, name); err != nil {
+			slog.Error("failed to delete object", "object", name, "error", err)
+		}
+	}
+}
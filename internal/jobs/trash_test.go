@@ -0,0 +1,118 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTrashStorage keeps trashed records as deletion times and object names in memory.
+type fakeTrashStorage struct {
+	mu        sync.Mutex
+	deletedAt map[int]time.Time
+	objects   map[int][]string
+	calls     int
+	err       error
+}
+
+func (s *fakeTrashStorage) PurgeTrash(_ context.Context, deletedBefore time.Time, limit int) (int, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return 0, nil, s.err
+	}
+
+	ids := make([]int, 0, len(s.deletedAt))
+	for id, at := range s.deletedAt {
+		if at.Before(deletedBefore) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	var objects []string
+	for _, id := range ids {
+		objects = append(objects, s.objects[id]...)
+		delete(s.deletedAt, id)
+	}
+	return len(ids), objects, nil
+}
+
+// fakeObjects records removed objects and fails for the names in fail.
+type fakeObjects struct {
+	mu      sync.Mutex
+	deleted []string
+	fail    map[string]bool
+}
+
+func (o *fakeObjects) Delete(_ context.Context, name string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.fail[name] {
+		return errors.New("s3 error")
+	}
+	o.deleted = append(o.deleted, name)
+	return nil
+}
+
+func TestTrashPurger_Purge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	storage := &fakeTrashStorage{
+		deletedAt: map[int]time.Time{
+			1: now.Add(-40 * 24 * time.Hour),
+			2: now.Add(-31 * 24 * time.Hour),
+			3: now.Add(-35 * 24 * time.Hour),
+			4: now.Add(-time.Hour),
+		},
+		objects: map[int][]string{1: {"obj-1", "obj-1-v1"}, 3: {"obj-3"}, 4: {"obj-4"}},
+	}
+	objects := &fakeObjects{fail: map[string]bool{"obj-1-v1": true}}
+
+	purger := NewTrashPurger(ctx, storage, objects, 30*24*time.Hour, time.Hour)
+	purger.batchSize = 2
+	purger.now = func() time.Time { return now }
+
+	purged, err := purger.Purge(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, purged)
+	require.Equal(t, 2, storage.calls, "a short batch ends the run")
+	require.ElementsMatch(t, []string{"obj-1", "obj-3"}, objects.deleted)
+	require.Contains(t, storage.deletedAt, 4)
+
+	storage.err = errors.New("db error")
+	_, err = purger.Purge(ctx)
+	require.ErrorContains(t, err, "db error")
+}
+
+func TestTrashPurger_StartAndWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	storage := &fakeTrashStorage{
+		deletedAt: map[int]time.Time{1: time.Now().Add(-48 * time.Hour)},
+		objects:   map[int][]string{1: {"obj-1"}},
+	}
+	objects := &fakeObjects{}
+
+	purger := NewTrashPurger(ctx, storage, objects, 24*time.Hour, time.Hour)
+	purger.Start()
+
+	require.Eventually(t, func() bool {
+		objects.mu.Lock()
+		defer objects.mu.Unlock()
+		return len(objects.deleted) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	purger.Wait()
+}
@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// DefaultUploadCleanupBatch is the number of expired uploads removed per query.
+const DefaultUploadCleanupBatch = 100
+
+// UploadStorage lists and deletes resumable uploads.
+type UploadStorage interface {
+	ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]*models.Upload, error)
+	DeleteUpload(ctx context.Context, uploadID string) error
+}
+
+// MultipartAborter discards multipart uploads in the object storage.
+type MultipartAborter interface {
+	AbortMultipartUpload(ctx context.Context, objectName, uploadID string) error
+}
+
+// UploadCleaner periodically removes resumable uploads that received no chunk before they
+// expired, together with the parts stored for them.
+//
+// The multipart upload is aborted before the upload is deleted, so a failure never loses
+// track of stored parts; uploads whose abort failed are retried on the next run.
+type UploadCleaner struct {
+	base      context.Context
+	storage   UploadStorage
+	objects   MultipartAborter
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+	wg        sync.WaitGroup
+}
+
+// NewUploadCleaner creates an UploadCleaner whose runs stop when base is cancelled.
+func NewUploadCleaner(
+	base context.Context,
+	storage UploadStorage,
+	objects MultipartAborter,
+	interval time.Duration,
+) *UploadCleaner {
+	return &UploadCleaner{
+		base:      base,
+		storage:   storage,
+		objects:   objects,
+		interval:  interval,
+		batchSize: DefaultUploadCleanupBatch,
+		now:       time.Now,
+	}
+}
+
+// Start removes expired uploads in the background now and then every interval.
+func (c *UploadCleaner) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := c.Clean(c.base); err != nil && c.base.Err() == nil {
+				slog.Error("upload cleanup failed", "error", err)
+			}
+
+			select {
+			case <-c.base.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the background cleanup has stopped.
+func (c *UploadCleaner) Wait() {
+	c.wg.Wait()
+}
+
+// Clean removes, batch by batch, all uploads that have expired and returns their number.
+func (c *UploadCleaner) Clean(ctx context.Context) (int, error) {
+	before := c.now()
+	total := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		uploads, err := c.storage.ListExpiredUploads(ctx, before, c.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		removed := 0
+		for _, upload := range uploads {
+			if err = c.objects.AbortMultipartUpload(ctx, upload.ObjectName, upload.MultipartID); err != nil {
+				slog.Error("failed to abort multipart upload", "upload", upload.ID, "error", err)
+				continue
+			}
+			if err = c.storage.DeleteUpload(ctx, upload.ID); err != nil && !errors.Is(err, models.ErrUploadNotFound) {
+				return total, err
+			}
+			removed++
+		}
+		total += removed
+
+		// Uploads that failed to abort are listed again, so stop once a batch makes no progress
+		if len(uploads) < c.batchSize || removed == 0 {
+			break
+		}
+	}
+
+	if total > 0 {
+		slog.Info("expired uploads removed", "uploads", total)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// fakeUploadStorage keeps uploads in memory.
+type fakeUploadStorage struct {
+	mu      sync.Mutex
+	uploads map[string]*models.Upload
+	calls   int
+	err     error
+}
+
+func (s *fakeUploadStorage) ListExpiredUploads(_ context.Context, before time.Time, limit int) ([]*models.Upload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var expired []*models.Upload
+	for _, u := range s.uploads {
+		if u.ExpiresAt.Before(before) {
+			expired = append(expired, u)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].ID < expired[j].ID })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+func (s *fakeUploadStorage) DeleteUpload(_ context.Context, uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.uploads, uploadID)
+	return nil
+}
+
+// fakeAborter records aborted multipart uploads and fails for the IDs in fail.
+type fakeAborter struct {
+	mu      sync.Mutex
+	aborted []string
+	fail    map[string]bool
+}
+
+func (a *fakeAborter) AbortMultipartUpload(_ context.Context, _, uploadID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.fail[uploadID] {
+		return errors.New("s3 error")
+	}
+	a.aborted = append(a.aborted, uploadID)
+	return nil
+}
+
+func TestUploadCleaner_Clean(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	upload := func(id string, expiresAt time.Time) *models.Upload {
+		return &models.Upload{ID: id, ObjectName: "obj-" + id, MultipartID: "mp-" + id, ExpiresAt: expiresAt}
+	}
+	storage := &fakeUploadStorage{uploads: map[string]*models.Upload{
+		"a": upload("a", now.Add(-2*time.Hour)),
+		"b": upload("b", now.Add(-time.Hour)),
+		"c": upload("c", now.Add(-time.Minute)),
+		"d": upload("d", now.Add(time.Hour)),
+	}}
+	objects := &fakeAborter{fail: map[string]bool{"mp-b": true}}
+
+	cleaner := NewUploadCleaner(ctx, storage, objects, time.Hour)
+	cleaner.batchSize = 2
+	cleaner.now = func() time.Time { return now }
+
+	removed, err := cleaner.Clean(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+	require.ElementsMatch(t, []string{"mp-a", "mp-c"}, objects.aborted)
+	require.Contains(t, storage.uploads, "b", "uploads that failed to abort are kept for the next run")
+	require.Contains(t, storage.uploads, "d")
+	require.Equal(t, 3, storage.calls, "a short batch ends the run")
+
+	storage.err = errors.New("db error")
+	_, err = cleaner.Clean(ctx)
+	require.ErrorContains(t, err, "db error")
+}
+
+func TestUploadCleaner_StartAndWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	storage := &fakeUploadStorage{uploads: map[string]*models.Upload{
+		"a": {ID: "a", MultipartID: "mp-a", ExpiresAt: time.Now().Add(-time.Hour)},
+	}}
+	objects := &fakeAborter{}
+
+	cleaner := NewUploadCleaner(ctx, storage, objects, time.Hour)
+	cleaner.Start()
+
+	require.Eventually(t, func() bool {
+		objects.mu.Lock()
+		defer objects.mu.Unlock()
+		return len(objects.aborted) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	cleaner.Wait()
+}
@@ -0,0 +1,216 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apetsko/gophkeeper/config"
+)
+
+// hsmKeyIDPrefix marks key IDs of master keys wrapped by an HSM key.
+const hsmKeyIDPrefix = "hsm:"
+
+// HSMSession is the part of a PKCS#11 session or KMIP client the HSM wrapper relies on:
+// AES-GCM encryption with a key that never leaves the device, addressed by its identifier.
+// The device generates the IV on Encrypt and returns it with the ciphertext and tag.
+type HSMSession interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) (ciphertext, iv []byte, err error)
+	Decrypt(ctx context.Context, keyID string, ciphertext, iv []byte) ([]byte, error)
+}
+
+// HSM is a KeyWrapper backed by a key held in a hardware security module.
+//
+// It works with any HSMSession; KMIPClient provides one for devices exposing a KMIP-style HTTPS
+// endpoint, and a PKCS#11 binding can provide another for devices loaded as a module.
+type HSM struct {
+	session HSMSession
+	keyID   string
+}
+
+var _ KeyWrapper = (*HSM)(nil)
+
+// NewHSM creates an HSM wrapper using the device key keyID through session.
+func NewHSM(session HSMSession, keyID string) *HSM {
+	return &HSM{session: session, keyID: keyID}
+}
+
+// ActiveKeyID returns the key ID of the device key, "hsm:<key ID>".
+func (h *HSM) ActiveKeyID() string {
+	return hsmKeyIDPrefix + h.keyID
+}
+
+// Wrap encrypts plaintext on the device; the IV chosen by the device is returned as the nonce.
+func (h *HSM) Wrap(ctx context.Context, plaintext []byte) (string, []byte, []byte, error) {
+	ciphertext, iv, err := h.session.Encrypt(ctx, h.keyID, plaintext)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return h.ActiveKeyID(), ciphertext, iv, nil
+}
+
+// Unwrap decrypts ciphertext on the device.
+func (h *HSM) Unwrap(ctx context.Context, keyID string, ciphertext, nonce []byte) ([]byte, error) {
+	if keyID != h.ActiveKeyID() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+
+	return h.session.Decrypt(ctx, h.keyID, ciphertext, nonce)
+}
+
+// KMIPRequest is a KMIP-style Encrypt or Decrypt operation sent to the HSM endpoint.
+type KMIPRequest struct {
+	Operation               string                  `json:"operation"`
+	UniqueIdentifier        string                  `json:"unique_identifier"`
+	CryptographicParameters KMIPCryptographicParams `json:"cryptographic_parameters"`
+	Data                    string                  `json:"data"`
+	IVCounterNonce          string                  `json:"iv_counter_nonce,omitempty"`
+}
+
+// KMIPCryptographicParams selects the algorithm of a KMIP-style operation.
+type KMIPCryptographicParams struct {
+	CryptographicAlgorithm string `json:"cryptographic_algorithm"`
+	BlockCipherMode        string `json:"block_cipher_mode"`
+}
+
+// KMIPResponse is the result of a KMIP-style operation.
+type KMIPResponse struct {
+	ResultStatus   string `json:"result_status"`
+	ResultReason   string `json:"result_reason,omitempty"`
+	ResultMessage  string `json:"result_message,omitempty"`
+	Data           string `json:"data,omitempty"`
+	IVCounterNonce string `json:"iv_counter_nonce,omitempty"`
+}
+
+// KMIP operations and result statuses.
+const (
+	KMIPOperationEncrypt = "Encrypt"
+	KMIPOperationDecrypt = "Decrypt"
+	KMIPResultSuccess    = "Success"
+)
+
+// KMIPClient is an HSMSession that sends KMIP-style operations as JSON over mutually
+// authenticated HTTPS.
+type KMIPClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+var _ HSMSession = (*KMIPClient)(nil)
+
+// NewKMIPClient creates a KMIPClient for cfg.Endpoint.
+//
+// Parameters:
+//   - cfg: The endpoint and the client certificate, key and CA paths.
+//
+// Returns:
+//   - *KMIPClient: The client.
+//   - error: An error if the certificates cannot be loaded.
+func NewKMIPClient(cfg config.KMIPConfig) (*KMIPClient, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CertPath != "" || cfg.KeyPath != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertPath, cfg.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load KMIP client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAPath != "" {
+		pool, err := loadCertPool(cfg.CAPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &KMIPClient{
+		client:   &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		endpoint: cfg.Endpoint,
+	}, nil
+}
+
+// Encrypt encrypts plaintext with AES-GCM under the device key keyID.
+func (c *KMIPClient) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, []byte, error) {
+	resp, err := c.do(ctx, KMIPRequest{
+		Operation:        KMIPOperationEncrypt,
+		UniqueIdentifier: keyID,
+		Data:             base64.StdEncoding.EncodeToString(plaintext),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(resp.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("kmip: invalid data: %w", err)
+	}
+	iv, err := base64.StdEncoding.DecodeString(resp.IVCounterNonce)
+	if err != nil || len(iv) == 0 {
+		return nil, nil, errors.New("kmip: invalid IV")
+	}
+
+	return ciphertext, iv, nil
+}
+
+// Decrypt decrypts ciphertext with AES-GCM under the device key keyID.
+func (c *KMIPClient) Decrypt(ctx context.Context, keyID string, ciphertext, iv []byte) ([]byte, error) {
+	resp, err := c.do(ctx, KMIPRequest{
+		Operation:        KMIPOperationDecrypt,
+		UniqueIdentifier: keyID,
+		Data:             base64.StdEncoding.EncodeToString(ciphertext),
+		IVCounterNonce:   base64.StdEncoding.EncodeToString(iv),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("kmip: invalid data: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// do sends one operation and checks its result status.
+func (c *KMIPClient) do(ctx context.Context, in KMIPRequest) (*KMIPResponse, error) {
+	in.CryptographicParameters = KMIPCryptographicParams{CryptographicAlgorithm: "AES", BlockCipherMode: "GCM"}
+
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kmip %s: %w", in.Operation, err)
+	}
+	defer resp.Body.Close()
+
+	var out KMIPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("kmip %s: %s: invalid response: %w", in.Operation, resp.Status, err)
+	}
+	if out.ResultStatus != KMIPResultSuccess {
+		return nil, fmt.Errorf("kmip %s: %s: %s", in.Operation, out.ResultReason, out.ResultMessage)
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,83 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+)
+
+// Keyring is the local KeyWrapper: it holds the server encryption keys by ID in process memory.
+// One of them is active and wraps new master keys; the others are kept so master keys wrapped
+// before a rotation can still be opened.
+type Keyring struct {
+	keys     map[string][]byte
+	activeID string
+}
+
+var _ KeyWrapper = (*Keyring)(nil)
+
+// NewKeyring creates a Keyring from keys, with activeID as the active key.
+func NewKeyring(keys map[string][]byte, activeID string) (*Keyring, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownKeyID, activeID)
+	}
+
+	k := &Keyring{keys: make(map[string][]byte, len(keys)), activeID: activeID}
+	for id, key := range keys {
+		if len(key) != constants.KeyLength {
+			return nil, fmt.Errorf("server key %q must be %d bytes", id, constants.KeyLength)
+		}
+		k.keys[id] = key
+	}
+
+	return k, nil
+}
+
+// ActiveKeyID returns the ID of the key used for new master keys.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Wrap encrypts plaintext with the active key and returns the key ID, ciphertext and nonce.
+func (k *Keyring) Wrap(_ context.Context, plaintext []byte) (string, []byte, []byte, error) {
+	gcm, err := newGCM(k.keys[k.activeID])
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, nil, errors.New("error generate nonce")
+	}
+
+	return k.activeID, gcm.Seal(nil, nonce, plaintext, nil), nonce, nil
+}
+
+// Unwrap decrypts ciphertext sealed with the key keyID.
+func (k *Keyring) Unwrap(_ context.Context, keyID string, ciphertext, nonce []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// newGCM creates an AES-GCM AEAD for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
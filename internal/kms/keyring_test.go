@@ -0,0 +1,49 @@
+package kms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	ctx := context.Background()
+	k1 := []byte("01234567890123456789012345678901")
+	k2 := []byte("abcdefghijabcdefghijabcdefghijab")
+
+	tests := []struct {
+		name     string
+		keys     map[string][]byte
+		activeID string
+		wantErr  bool
+	}{
+		{name: "single key", keys: map[string][]byte{"k1": k1}, activeID: "k1"},
+		{name: "two keys", keys: map[string][]byte{"k1": k1, "k2": k2}, activeID: "k2"},
+		{name: "unknown active key", keys: map[string][]byte{"k1": k1}, activeID: "k2", wantErr: true},
+		{name: "short key", keys: map[string][]byte{"k1": k1, "k2": []byte("short")}, activeID: "k1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := NewKeyring(tt.keys, tt.activeID)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.activeID, keyring.ActiveKeyID())
+
+			keyID, ciphertext, nonce, err := keyring.Wrap(ctx, []byte("secret"))
+			require.NoError(t, err)
+			require.Equal(t, tt.activeID, keyID)
+
+			plaintext, err := keyring.Unwrap(ctx, keyID, ciphertext, nonce)
+			require.NoError(t, err)
+			require.Equal(t, []byte("secret"), plaintext)
+
+			_, err = keyring.Unwrap(ctx, "missing", ciphertext, nonce)
+			require.ErrorIs(t, err, ErrUnknownKeyID)
+		})
+	}
+}
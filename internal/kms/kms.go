@@ -0,0 +1,117 @@
+// Package kms provides the key wrappers that seal user master keys with a server-side root key.
+//
+// A KeyWrapper hides where the root key lives: in the gophkeeper process (Keyring), in a
+// HashiCorp Vault Transit-compatible service (Vault) or in an HSM reached through a PKCS#11 or
+// KMIP-style session (HSM). Every wrapped key is stored with the ID of the key that wrapped it,
+// so several wrappers can be chained while master keys are migrated between backends.
+package kms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apetsko/gophkeeper/config"
+)
+
+// ErrUnknownKeyID is returned when a master key was wrapped with a key the wrapper does not hold.
+var ErrUnknownKeyID = errors.New("unknown server key ID")
+
+// KeyWrapper seals and opens master keys with a server-side root key.
+type KeyWrapper interface {
+	// ActiveKeyID returns the ID of the key that wraps new master keys.
+	ActiveKeyID() string
+	// Wrap encrypts plaintext with the active key and returns the key ID, ciphertext and nonce.
+	Wrap(ctx context.Context, plaintext []byte) (keyID string, ciphertext, nonce []byte, err error)
+	// Unwrap decrypts ciphertext wrapped with the key keyID.
+	// Returns ErrUnknownKeyID if the wrapper does not hold that key.
+	Unwrap(ctx context.Context, keyID string, ciphertext, nonce []byte) ([]byte, error)
+}
+
+// Chain wraps new keys with one wrapper and opens keys wrapped by any of several wrappers.
+//
+// It is used while moving master keys to another backend: the new backend is active, and the
+// previous one stays available until the rotation has re-wrapped every key.
+type Chain struct {
+	active KeyWrapper
+	all    []KeyWrapper
+}
+
+var _ KeyWrapper = (*Chain)(nil)
+
+// NewChain creates a Chain that wraps with active and unwraps with active or any of fallbacks.
+func NewChain(active KeyWrapper, fallbacks ...KeyWrapper) *Chain {
+	return &Chain{active: active, all: append([]KeyWrapper{active}, fallbacks...)}
+}
+
+// ActiveKeyID returns the active key ID of the active wrapper.
+func (c *Chain) ActiveKeyID() string {
+	return c.active.ActiveKeyID()
+}
+
+// Wrap encrypts plaintext with the active wrapper.
+func (c *Chain) Wrap(ctx context.Context, plaintext []byte) (string, []byte, []byte, error) {
+	return c.active.Wrap(ctx, plaintext)
+}
+
+// Unwrap decrypts ciphertext with the first wrapper that holds the key keyID.
+func (c *Chain) Unwrap(ctx context.Context, keyID string, ciphertext, nonce []byte) ([]byte, error) {
+	for _, w := range c.all {
+		plaintext, err := w.Unwrap(ctx, keyID, ciphertext, nonce)
+		if !errors.Is(err, ErrUnknownKeyID) {
+			return plaintext, err
+		}
+	}
+
+	return nil, ErrUnknownKeyID
+}
+
+// New creates the KeyWrapper selected by cfg.KMS.Backend.
+//
+// The local keyring is built from the configured server keys. With a remote backend it is
+// chained as a fallback, so master keys wrapped before the switch stay readable until a key
+// rotation has moved them to the remote key.
+//
+// Parameters:
+//   - cfg: The application configuration.
+//
+// Returns:
+//   - KeyWrapper: The wrapper for new and existing master keys.
+//   - error: An error if the backend cannot be initialized.
+func New(cfg *config.Config) (KeyWrapper, error) {
+	var keyring *Keyring
+	if len(cfg.ServerKeys) > 0 {
+		var err error
+		if keyring, err = NewKeyring(cfg.ServerKeys, cfg.ServerKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	var remote KeyWrapper
+	switch cfg.KMS.Backend {
+	case config.KMSBackendVault:
+		vault, err := NewVault(cfg.KMS.Vault)
+		if err != nil {
+			return nil, err
+		}
+		remote = vault
+	case config.KMSBackendKMIP:
+		client, err := NewKMIPClient(cfg.KMS.KMIP)
+		if err != nil {
+			return nil, err
+		}
+		remote = NewHSM(client, cfg.KMS.KMIP.KeyID)
+	case "", config.KMSBackendLocal:
+		if keyring == nil {
+			return nil, errors.New("local KMS backend requires a server encryption key")
+		}
+		return keyring, nil
+	default:
+		return nil, fmt.Errorf("unknown KMS backend %q", cfg.KMS.Backend)
+	}
+
+	if keyring == nil {
+		return remote, nil
+	}
+	return NewChain(remote, keyring), nil
+}
@@ -0,0 +1,178 @@
+package kms_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/kms"
+	"github.com/apetsko/gophkeeper/internal/kms/kmstest"
+)
+
+func TestVault(t *testing.T) {
+	ctx := context.Background()
+	standin := kmstest.NewVaultServer("transit", "root-token")
+	srv := httptest.NewServer(standin)
+	defer srv.Close()
+
+	vault, err := kms.NewVault(config.VaultConfig{Address: srv.URL, Token: "root-token", Key: "gophkeeper"})
+	require.NoError(t, err)
+	require.Equal(t, "vault:transit/gophkeeper", vault.ActiveKeyID())
+
+	keyID, ciphertext, nonce, err := vault.Wrap(ctx, []byte("master key"))
+	require.NoError(t, err)
+	require.Equal(t, vault.ActiveKeyID(), keyID)
+	require.Contains(t, string(ciphertext), "vault:v1:")
+	require.NotNil(t, nonce)
+
+	plaintext, err := vault.Unwrap(ctx, keyID, ciphertext, nonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("master key"), plaintext)
+
+	// Keys rotated inside Vault keep opening older ciphertexts.
+	rotate := httptest.NewRequest("POST", "/v1/transit/keys/gophkeeper/rotate", nil)
+	rotate.Header.Set("X-Vault-Token", "root-token")
+	standin.ServeHTTP(httptest.NewRecorder(), rotate)
+
+	_, rotated, _, err := vault.Wrap(ctx, []byte("master key"))
+	require.NoError(t, err)
+	require.Contains(t, string(rotated), "vault:v2:")
+	plaintext, err = vault.Unwrap(ctx, keyID, ciphertext, nonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("master key"), plaintext)
+
+	_, err = vault.Unwrap(ctx, "default", ciphertext, nonce)
+	require.ErrorIs(t, err, kms.ErrUnknownKeyID)
+
+	badToken, err := kms.NewVault(config.VaultConfig{Address: srv.URL, Token: "wrong", Key: "gophkeeper"})
+	require.NoError(t, err)
+	_, _, _, err = badToken.Wrap(ctx, []byte("master key"))
+	require.ErrorContains(t, err, "permission denied")
+}
+
+func TestHSM_KMIP(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewTLSServer(kmstest.NewKMIPServer("hsm-key-1"))
+	defer srv.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caPEM, 0o600))
+
+	client, err := kms.NewKMIPClient(config.KMIPConfig{Endpoint: srv.URL, CAPath: caPath})
+	require.NoError(t, err)
+	hsm := kms.NewHSM(client, "hsm-key-1")
+	require.Equal(t, "hsm:hsm-key-1", hsm.ActiveKeyID())
+
+	keyID, ciphertext, nonce, err := hsm.Wrap(ctx, []byte("master key"))
+	require.NoError(t, err)
+	require.NotEmpty(t, nonce)
+
+	plaintext, err := hsm.Unwrap(ctx, keyID, ciphertext, nonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("master key"), plaintext)
+
+	ciphertext[0] ^= 0xff
+	_, err = hsm.Unwrap(ctx, keyID, ciphertext, nonce)
+	require.ErrorContains(t, err, "Cryptographic Failure")
+
+	_, _, _, err = kms.NewHSM(client, "missing").Wrap(ctx, []byte("master key"))
+	require.ErrorContains(t, err, "Item Not Found")
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(kmstest.NewVaultServer("transit", "root-token"))
+	defer srv.Close()
+
+	local, err := kms.NewKeyring(map[string][]byte{"default": []byte("01234567890123456789012345678901")}, "default")
+	require.NoError(t, err)
+	vault, err := kms.NewVault(config.VaultConfig{Address: srv.URL, Token: "root-token", Key: "gophkeeper"})
+	require.NoError(t, err)
+
+	oldID, oldCiphertext, oldNonce, err := local.Wrap(ctx, []byte("old key"))
+	require.NoError(t, err)
+
+	chain := kms.NewChain(vault, local)
+	require.Equal(t, vault.ActiveKeyID(), chain.ActiveKeyID())
+
+	newID, newCiphertext, newNonce, err := chain.Wrap(ctx, []byte("new key"))
+	require.NoError(t, err)
+	require.Equal(t, vault.ActiveKeyID(), newID)
+
+	plaintext, err := chain.Unwrap(ctx, oldID, oldCiphertext, oldNonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("old key"), plaintext)
+
+	plaintext, err = chain.Unwrap(ctx, newID, newCiphertext, newNonce)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new key"), plaintext)
+
+	_, err = chain.Unwrap(ctx, "hsm:other", newCiphertext, newNonce)
+	require.ErrorIs(t, err, kms.ErrUnknownKeyID)
+}
+
+func TestNew(t *testing.T) {
+	serverKeys := map[string][]byte{"default": []byte("01234567890123456789012345678901")}
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		wantActive string
+		wantErr    bool
+	}{
+		{
+			name:       "local",
+			cfg:        &config.Config{ServerKeys: serverKeys, ServerKeyID: "default"},
+			wantActive: "default",
+		},
+		{
+			name:    "local without keys",
+			cfg:     &config.Config{KMS: config.KMSConfig{Backend: config.KMSBackendLocal}},
+			wantErr: true,
+		},
+		{
+			name: "vault with local fallback",
+			cfg: &config.Config{
+				ServerKeys:  serverKeys,
+				ServerKeyID: "default",
+				KMS: config.KMSConfig{
+					Backend: config.KMSBackendVault,
+					Vault:   config.VaultConfig{Address: "http://127.0.0.1:8200", Token: "t", Key: "gophkeeper"},
+				},
+			},
+			wantActive: "vault:transit/gophkeeper",
+		},
+		{
+			name: "kmip",
+			cfg: &config.Config{KMS: config.KMSConfig{
+				Backend: config.KMSBackendKMIP,
+				KMIP:    config.KMIPConfig{Endpoint: "https://127.0.0.1:5696/kmip", KeyID: "k1"},
+			}},
+			wantActive: "hsm:k1",
+		},
+		{
+			name:    "unknown backend",
+			cfg:     &config.Config{ServerKeys: serverKeys, ServerKeyID: "default", KMS: config.KMSConfig{Backend: "aws"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapper, err := kms.New(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantActive, wrapper.ActiveKeyID())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package kmstest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/apetsko/gophkeeper/internal/kms"
+)
+
+// KMIPServer is a stand-in for an HSM exposing KMIP-style Encrypt and Decrypt operations.
+//
+// It holds AES keys by unique identifier and answers the JSON requests sent by kms.KMIPClient.
+// Serve it with TLS, e.g. via httptest.NewTLSServer, to exercise the client's TLS setup.
+type KMIPServer struct {
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+// NewKMIPServer creates a KMIPServer holding a random key for each of keyIDs.
+func NewKMIPServer(keyIDs ...string) *KMIPServer {
+	s := &KMIPServer{keys: make(map[string][]byte, len(keyIDs))}
+	for _, id := range keyIDs {
+		s.keys[id] = newKey()
+	}
+	return s
+}
+
+// ServeHTTP handles one KMIP-style operation per POST request.
+func (s *KMIPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req kms.KMIPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		kmipResult(w, &kms.KMIPResponse{ResultReason: "Invalid Message", ResultMessage: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	key, ok := s.keys[req.UniqueIdentifier]
+	s.mu.Unlock()
+	if !ok {
+		kmipResult(w, &kms.KMIPResponse{ResultReason: "Item Not Found", ResultMessage: req.UniqueIdentifier})
+		return
+	}
+
+	if req.CryptographicParameters.CryptographicAlgorithm != "AES" || req.CryptographicParameters.BlockCipherMode != "GCM" {
+		kmipResult(w, &kms.KMIPResponse{ResultReason: "Invalid Field", ResultMessage: "only AES-GCM is supported"})
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(req.Data)
+	if err != nil {
+		kmipResult(w, &kms.KMIPResponse{ResultReason: "Invalid Field", ResultMessage: "data is not base64"})
+		return
+	}
+
+	gcm := newGCM(key)
+	switch req.Operation {
+	case kms.KMIPOperationEncrypt:
+		iv := make([]byte, gcm.NonceSize())
+		_, _ = rand.Read(iv)
+		kmipResult(w, &kms.KMIPResponse{
+			ResultStatus:   kms.KMIPResultSuccess,
+			Data:           base64.StdEncoding.EncodeToString(gcm.Seal(nil, iv, data, nil)),
+			IVCounterNonce: base64.StdEncoding.EncodeToString(iv),
+		})
+	case kms.KMIPOperationDecrypt:
+		iv, errIV := base64.StdEncoding.DecodeString(req.IVCounterNonce)
+		if errIV != nil || len(iv) != gcm.NonceSize() {
+			kmipResult(w, &kms.KMIPResponse{ResultReason: "Invalid Field", ResultMessage: "invalid IV"})
+			return
+		}
+		plaintext, errOpen := gcm.Open(nil, iv, data, nil)
+		if errOpen != nil {
+			kmipResult(w, &kms.KMIPResponse{ResultReason: "Cryptographic Failure", ResultMessage: errOpen.Error()})
+			return
+		}
+		kmipResult(w, &kms.KMIPResponse{
+			ResultStatus: kms.KMIPResultSuccess,
+			Data:         base64.StdEncoding.EncodeToString(plaintext),
+		})
+	default:
+		kmipResult(w, &kms.KMIPResponse{ResultReason: "Operation Not Supported", ResultMessage: req.Operation})
+	}
+}
+
+// kmipResult writes a KMIP-style response; failures carry the "Operation Failed" status.
+func kmipResult(w http.ResponseWriter, resp *kms.KMIPResponse) {
+	if resp.ResultStatus == "" {
+		resp.ResultStatus = "Operation Failed"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
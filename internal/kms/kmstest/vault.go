@@ -0,0 +1,183 @@
+// Package kmstest provides in-process stand-ins for the key management services supported by
+// package kms, for tests and local development.
+package kmstest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// VaultServer is a stand-in for the HashiCorp Vault Transit secrets engine.
+//
+// It implements the encrypt, decrypt and key rotate endpoints of a transit mount, with keys
+// created on first use and ciphertexts in Vault's "vault:v<version>:<base64>" format. Requests
+// must carry the configured token in the X-Vault-Token header.
+type VaultServer struct {
+	token string
+	mount string
+
+	mu   sync.Mutex
+	keys map[string][][]byte
+}
+
+// NewVaultServer creates a VaultServer serving the transit mount and accepting token.
+func NewVaultServer(mount, token string) *VaultServer {
+	return &VaultServer{
+		token: token,
+		mount: strings.Trim(mount, "/"),
+		keys:  make(map[string][][]byte),
+	}
+}
+
+// ServeHTTP handles POST /v1/<mount>/{encrypt,decrypt}/<key> and POST /v1/<mount>/keys/<key>/rotate.
+func (s *VaultServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != s.token {
+		vaultError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	if r.Method != http.MethodPost {
+		vaultError(w, http.StatusMethodNotAllowed, "unsupported method")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"+s.mount+"/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "encrypt":
+		s.encrypt(w, r, parts[1])
+	case len(parts) == 2 && parts[0] == "decrypt":
+		s.decrypt(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "keys" && parts[2] == "rotate":
+		s.rotate(w, parts[1])
+	default:
+		vaultError(w, http.StatusNotFound, "unsupported path")
+	}
+}
+
+func (s *VaultServer) encrypt(w http.ResponseWriter, r *http.Request, name string) {
+	var req struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		vaultError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+	if err != nil {
+		vaultError(w, http.StatusBadRequest, "plaintext is not base64")
+		return
+	}
+
+	s.mu.Lock()
+	if len(s.keys[name]) == 0 {
+		s.keys[name] = [][]byte{newKey()}
+	}
+	version := len(s.keys[name])
+	key := s.keys[name][version-1]
+	s.mu.Unlock()
+
+	gcm := newGCM(key)
+	nonce := make([]byte, gcm.NonceSize())
+	_, _ = rand.Read(nonce)
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	vaultData(w, map[string]any{
+		"ciphertext":  fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString(sealed)),
+		"key_version": version,
+	})
+}
+
+func (s *VaultServer) decrypt(w http.ResponseWriter, r *http.Request, name string) {
+	var req struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		vaultError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	fields := strings.SplitN(req.Ciphertext, ":", 3)
+	if len(fields) != 3 || fields[0] != "vault" || !strings.HasPrefix(fields[1], "v") {
+		vaultError(w, http.StatusBadRequest, "invalid ciphertext")
+		return
+	}
+	version, err := strconv.Atoi(fields[1][1:])
+	if err != nil {
+		vaultError(w, http.StatusBadRequest, "invalid key version")
+		return
+	}
+	sealed, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		vaultError(w, http.StatusBadRequest, "invalid ciphertext")
+		return
+	}
+
+	s.mu.Lock()
+	versions := s.keys[name]
+	s.mu.Unlock()
+	if version < 1 || version > len(versions) {
+		vaultError(w, http.StatusBadRequest, "unknown key version")
+		return
+	}
+
+	gcm := newGCM(versions[version-1])
+	if len(sealed) < gcm.NonceSize() {
+		vaultError(w, http.StatusBadRequest, "invalid ciphertext")
+		return
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		vaultError(w, http.StatusBadRequest, "cipher: message authentication failed")
+		return
+	}
+
+	vaultData(w, map[string]any{"plaintext": base64.StdEncoding.EncodeToString(plaintext)})
+}
+
+func (s *VaultServer) rotate(w http.ResponseWriter, name string) {
+	s.mu.Lock()
+	s.keys[name] = append(s.keys[name], newKey())
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// vaultData writes a successful Vault response.
+func vaultData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+// vaultError writes a Vault error response.
+func vaultError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{msg}})
+}
+
+// newKey returns a random AES-256 key.
+func newKey() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// newGCM creates an AES-GCM AEAD for a key created by newKey.
+func newGCM(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return gcm
+}
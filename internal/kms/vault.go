@@ -0,0 +1,173 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apetsko/gophkeeper/config"
+)
+
+// vaultKeyIDPrefix marks key IDs of master keys wrapped by Vault Transit.
+const vaultKeyIDPrefix = "vault:"
+
+// Vault is a KeyWrapper backed by the HashiCorp Vault Transit secrets engine.
+//
+// The root key never leaves Vault: master keys are sent to the encrypt endpoint and the returned
+// "vault:vN:..." ciphertext is stored as is. Key versions rotated inside Vault keep the same
+// key ID, since the ciphertext records the version that decrypts it.
+type Vault struct {
+	client    *http.Client
+	address   string
+	token     string
+	namespace string
+	mount     string
+	key       string
+}
+
+var _ KeyWrapper = (*Vault)(nil)
+
+// NewVault creates a Vault wrapper for the transit key cfg.Key.
+//
+// Parameters:
+//   - cfg: Vault address, token, transit mount and key name.
+//
+// Returns:
+//   - *Vault: The wrapper.
+//   - error: An error if the CA certificate cannot be loaded.
+func NewVault(cfg config.VaultConfig) (*Vault, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CAPath != "" {
+		pool, err := loadCertPool(cfg.CAPath)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	mount := strings.Trim(cfg.Mount, "/")
+	if mount == "" {
+		mount = "transit"
+	}
+
+	return &Vault{
+		client:    &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		address:   strings.TrimRight(cfg.Address, "/"),
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		mount:     mount,
+		key:       cfg.Key,
+	}, nil
+}
+
+// ActiveKeyID returns the key ID of the transit key, "vault:<mount>/<key>".
+func (v *Vault) ActiveKeyID() string {
+	return vaultKeyIDPrefix + v.mount + "/" + v.key
+}
+
+// Wrap encrypts plaintext with the transit key. The nonce is part of the Vault ciphertext, so
+// the returned nonce is empty.
+func (v *Vault) Wrap(ctx context.Context, plaintext []byte) (string, []byte, []byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}
+	if err := v.do(ctx, "encrypt", req, &resp); err != nil {
+		return "", nil, nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return "", nil, nil, errors.New("vault: empty ciphertext")
+	}
+
+	return v.ActiveKeyID(), []byte(resp.Data.Ciphertext), []byte{}, nil
+}
+
+// Unwrap decrypts a Vault ciphertext produced by Wrap.
+func (v *Vault) Unwrap(ctx context.Context, keyID string, ciphertext, _ []byte) ([]byte, error) {
+	if keyID != v.ActiveKeyID() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+
+	if err := v.do(ctx, "decrypt", map[string]string{"ciphertext": string(ciphertext)}, &resp); err != nil {
+		return nil, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("vault: invalid plaintext: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// do sends a transit operation for the configured key and decodes the response into out.
+func (v *Vault) do(ctx context.Context, operation string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", v.address, v.mount, operation, v.key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault %s: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		return fmt.Errorf("vault %s: %s: %s", operation, resp.Status, strings.Join(vaultErr.Errors, "; "))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("vault %s: invalid response: %w", operation, err)
+	}
+
+	return nil
+}
+
+// loadCertPool reads a PEM CA certificate bundle.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in %s", path)
+	}
+
+	return pool, nil
+}
@@ -4,6 +4,9 @@ package mocks
 
 import (
 	context "context"
+	io "io"
+
+	crypto "github.com/apetsko/gophkeeper/internal/crypto"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,6 +18,80 @@ type IEnvelope struct {
 	mock.Mock
 }
 
+// DecryptReader provides a mock function with given fields: ctx, userData, masterKey, src, size
+func (_m *IEnvelope) DecryptReader(ctx context.Context, userData models.DBUserData, masterKey []byte, src io.Reader, size int64) (io.Reader, int64, error) {
+	ret := _m.Called(ctx, userData, masterKey, src, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecryptReader")
+	}
+
+	var r0 io.Reader
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, io.Reader, int64) (io.Reader, int64, error)); ok {
+		return rf(ctx, userData, masterKey, src, size)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, io.Reader, int64) io.Reader); ok {
+		r0 = rf(ctx, userData, masterKey, src, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.Reader)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DBUserData, []byte, io.Reader, int64) int64); ok {
+		r1 = rf(ctx, userData, masterKey, src, size)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.DBUserData, []byte, io.Reader, int64) error); ok {
+		r2 = rf(ctx, userData, masterKey, src, size)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DecryptReaderAt provides a mock function with given fields: ctx, userData, masterKey, src, size
+func (_m *IEnvelope) DecryptReaderAt(ctx context.Context, userData models.DBUserData, masterKey []byte, src io.ReaderAt, size int64) (io.ReaderAt, int64, error) {
+	ret := _m.Called(ctx, userData, masterKey, src, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecryptReaderAt")
+	}
+
+	var r0 io.ReaderAt
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, io.ReaderAt, int64) (io.ReaderAt, int64, error)); ok {
+		return rf(ctx, userData, masterKey, src, size)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, io.ReaderAt, int64) io.ReaderAt); ok {
+		r0 = rf(ctx, userData, masterKey, src, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReaderAt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DBUserData, []byte, io.ReaderAt, int64) int64); ok {
+		r1 = rf(ctx, userData, masterKey, src, size)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.DBUserData, []byte, io.ReaderAt, int64) error); ok {
+		r2 = rf(ctx, userData, masterKey, src, size)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // DecryptUserData provides a mock function with given fields: ctx, userData, masterKey
 func (_m *IEnvelope) DecryptUserData(ctx context.Context, userData models.DBUserData, masterKey []byte) ([]byte, error) {
 	ret := _m.Called(ctx, userData, masterKey)
@@ -45,6 +122,75 @@ func (_m *IEnvelope) DecryptUserData(ctx context.Context, userData models.DBUser
 	return r0, r1
 }
 
+// EncryptFile provides a mock function with given fields: ctx, masterKey, data
+func (_m *IEnvelope) EncryptFile(ctx context.Context, masterKey []byte, data []byte) (*models.EncryptedData, error) {
+	ret := _m.Called(ctx, masterKey, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EncryptFile")
+	}
+
+	var r0 *models.EncryptedData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) (*models.EncryptedData, error)); ok {
+		return rf(ctx, masterKey, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) *models.EncryptedData); ok {
+		r0 = rf(ctx, masterKey, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EncryptedData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []byte) error); ok {
+		r1 = rf(ctx, masterKey, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EncryptStream provides a mock function with given fields: ctx, masterKey, dst
+func (_m *IEnvelope) EncryptStream(ctx context.Context, masterKey []byte, dst io.Writer) (io.WriteCloser, *models.EncryptedData, error) {
+	ret := _m.Called(ctx, masterKey, dst)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EncryptStream")
+	}
+
+	var r0 io.WriteCloser
+	var r1 *models.EncryptedData
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, io.Writer) (io.WriteCloser, *models.EncryptedData, error)); ok {
+		return rf(ctx, masterKey, dst)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, io.Writer) io.WriteCloser); ok {
+		r0 = rf(ctx, masterKey, dst)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.WriteCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, io.Writer) *models.EncryptedData); ok {
+		r1 = rf(ctx, masterKey, dst)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*models.EncryptedData)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []byte, io.Writer) error); ok {
+		r2 = rf(ctx, masterKey, dst)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // EncryptUserData provides a mock function with given fields: ctx, masterKey, data
 func (_m *IEnvelope) EncryptUserData(ctx context.Context, masterKey []byte, data []byte) (*models.EncryptedData, error) {
 	ret := _m.Called(ctx, masterKey, data)
@@ -75,6 +221,144 @@ func (_m *IEnvelope) EncryptUserData(ctx context.Context, masterKey []byte, data
 	return r0, r1
 }
 
+// NewStreamSealer provides a mock function with given fields: ctx, masterKey
+func (_m *IEnvelope) NewStreamSealer(ctx context.Context, masterKey []byte) (*crypto.StreamSealer, *models.EncryptedData, error) {
+	ret := _m.Called(ctx, masterKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewStreamSealer")
+	}
+
+	var r0 *crypto.StreamSealer
+	var r1 *models.EncryptedData
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (*crypto.StreamSealer, *models.EncryptedData, error)); ok {
+		return rf(ctx, masterKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *crypto.StreamSealer); ok {
+		r0 = rf(ctx, masterKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*crypto.StreamSealer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) *models.EncryptedData); ok {
+		r1 = rf(ctx, masterKey)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*models.EncryptedData)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []byte) error); ok {
+		r2 = rf(ctx, masterKey)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// OpenStreamSealer provides a mock function with given fields: ctx, userData, masterKey, header
+func (_m *IEnvelope) OpenStreamSealer(ctx context.Context, userData models.DBUserData, masterKey []byte, header []byte) (*crypto.StreamSealer, error) {
+	ret := _m.Called(ctx, userData, masterKey, header)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenStreamSealer")
+	}
+
+	var r0 *crypto.StreamSealer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, []byte) (*crypto.StreamSealer, error)); ok {
+		return rf(ctx, userData, masterKey, header)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, []byte) *crypto.StreamSealer); ok {
+		r0 = rf(ctx, userData, masterKey, header)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*crypto.StreamSealer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DBUserData, []byte, []byte) error); ok {
+		r1 = rf(ctx, userData, masterKey, header)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenWithDEK provides a mock function with given fields: ctx, userData, masterKey, ciphertext, nonce
+func (_m *IEnvelope) OpenWithDEK(ctx context.Context, userData models.DBUserData, masterKey []byte, ciphertext []byte, nonce []byte) ([]byte, error) {
+	ret := _m.Called(ctx, userData, masterKey, ciphertext, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenWithDEK")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, []byte, []byte) ([]byte, error)); ok {
+		return rf(ctx, userData, masterKey, ciphertext, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, []byte, []byte) []byte); ok {
+		r0 = rf(ctx, userData, masterKey, ciphertext, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DBUserData, []byte, []byte, []byte) error); ok {
+		r1 = rf(ctx, userData, masterKey, ciphertext, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SealWithDEK provides a mock function with given fields: ctx, userData, masterKey, data
+func (_m *IEnvelope) SealWithDEK(ctx context.Context, userData models.DBUserData, masterKey []byte, data []byte) ([]byte, []byte, error) {
+	ret := _m.Called(ctx, userData, masterKey, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SealWithDEK")
+	}
+
+	var r0 []byte
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, []byte) ([]byte, []byte, error)); ok {
+		return rf(ctx, userData, masterKey, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DBUserData, []byte, []byte) []byte); ok {
+		r0 = rf(ctx, userData, masterKey, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DBUserData, []byte, []byte) []byte); ok {
+		r1 = rf(ctx, userData, masterKey, data)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.DBUserData, []byte, []byte) error); ok {
+		r2 = rf(ctx, userData, masterKey, data)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewIEnvelope creates a new instance of IEnvelope. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIEnvelope(t interface {
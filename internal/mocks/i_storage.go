@@ -7,6 +7,8 @@ import (
 
 	models "github.com/apetsko/gophkeeper/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // IStorage is an autogenerated mock type for the IStorage type
@@ -14,6 +16,34 @@ type IStorage struct {
 	mock.Mock
 }
 
+// AddClientUser provides a mock function with given fields: ctx, u, key
+func (_m *IStorage) AddClientUser(ctx context.Context, u *models.UserEntry, key *models.ClientKey) (int, error) {
+	ret := _m.Called(ctx, u, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddClientUser")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserEntry, *models.ClientKey) (int, error)); ok {
+		return rf(ctx, u, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserEntry, *models.ClientKey) int); ok {
+		r0 = rf(ctx, u, key)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.UserEntry, *models.ClientKey) error); ok {
+		r1 = rf(ctx, u, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AddUser provides a mock function with given fields: ctx, u
 func (_m *IStorage) AddUser(ctx context.Context, u *models.UserEntry) (int, error) {
 	ret := _m.Called(ctx, u)
@@ -42,6 +72,24 @@ func (_m *IStorage) AddUser(ctx context.Context, u *models.UserEntry) (int, erro
 	return r0, r1
 }
 
+// ClaimUpload provides a mock function with given fields: ctx, uploadID
+func (_m *IStorage) ClaimUpload(ctx context.Context, uploadID string) error {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Close provides a mock function with no fields
 func (_m *IStorage) Close() error {
 	ret := _m.Called()
@@ -60,17 +108,17 @@ func (_m *IStorage) Close() error {
 	return r0
 }
 
-// DeleteUserData provides a mock function with given fields: ctx, userDataID
-func (_m *IStorage) DeleteUserData(ctx context.Context, userDataID int) error {
-	ret := _m.Called(ctx, userDataID)
+// CommitUploadPart provides a mock function with given fields: ctx, uploadID, number, etag, offset, received, expiresAt
+func (_m *IStorage) CommitUploadPart(ctx context.Context, uploadID string, number int, etag string, offset int64, received int64, expiresAt time.Time) error {
+	ret := _m.Called(ctx, uploadID, number, etag, offset, received, expiresAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUserData")
+		panic("no return value specified for CommitUploadPart")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, userDataID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string, int64, int64, time.Time) error); ok {
+		r0 = rf(ctx, uploadID, number, etag, offset, received, expiresAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -78,29 +126,27 @@ func (_m *IStorage) DeleteUserData(ctx context.Context, userDataID int) error {
 	return r0
 }
 
-// GetMasterKey provides a mock function with given fields: ctx, userID
-func (_m *IStorage) GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error) {
-	ret := _m.Called(ctx, userID)
+// CreateFolder provides a mock function with given fields: ctx, folder
+func (_m *IStorage) CreateFolder(ctx context.Context, folder *models.Folder) (int, error) {
+	ret := _m.Called(ctx, folder)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMasterKey")
+		panic("no return value specified for CreateFolder")
 	}
 
-	var r0 *models.EncryptedMK
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.EncryptedMK, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Folder) (int, error)); ok {
+		return rf(ctx, folder)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) *models.EncryptedMK); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Folder) int); ok {
+		r0 = rf(ctx, folder)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.EncryptedMK)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Folder) error); ok {
+		r1 = rf(ctx, folder)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,29 +154,29 @@ func (_m *IStorage) GetMasterKey(ctx context.Context, userID int) (*models.Encry
 	return r0, r1
 }
 
-// GetUser provides a mock function with given fields: ctx, username
-func (_m *IStorage) GetUser(ctx context.Context, username string) (*models.UserEntry, error) {
-	ret := _m.Called(ctx, username)
+// CreateKeyRotationJob provides a mock function with given fields: ctx, targetKeyID
+func (_m *IStorage) CreateKeyRotationJob(ctx context.Context, targetKeyID string) (*models.KeyRotationJob, error) {
+	ret := _m.Called(ctx, targetKeyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUser")
+		panic("no return value specified for CreateKeyRotationJob")
 	}
 
-	var r0 *models.UserEntry
+	var r0 *models.KeyRotationJob
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.UserEntry, error)); ok {
-		return rf(ctx, username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.KeyRotationJob, error)); ok {
+		return rf(ctx, targetKeyID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UserEntry); ok {
-		r0 = rf(ctx, username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.KeyRotationJob); ok {
+		r0 = rf(ctx, targetKeyID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.UserEntry)
+			r0 = ret.Get(0).(*models.KeyRotationJob)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, username)
+		r1 = rf(ctx, targetKeyID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -138,29 +184,27 @@ func (_m *IStorage) GetUser(ctx context.Context, username string) (*models.UserE
 	return r0, r1
 }
 
-// GetUserData provides a mock function with given fields: ctx, userDataID
-func (_m *IStorage) GetUserData(ctx context.Context, userDataID int) (*models.DBUserData, error) {
-	ret := _m.Called(ctx, userDataID)
+// CreateSession provides a mock function with given fields: ctx, session, tokenHash
+func (_m *IStorage) CreateSession(ctx context.Context, session *models.Session, tokenHash []byte) (int, error) {
+	ret := _m.Called(ctx, session, tokenHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserData")
+		panic("no return value specified for CreateSession")
 	}
 
-	var r0 *models.DBUserData
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.DBUserData, error)); ok {
-		return rf(ctx, userDataID)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Session, []byte) (int, error)); ok {
+		return rf(ctx, session, tokenHash)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) *models.DBUserData); ok {
-		r0 = rf(ctx, userDataID)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Session, []byte) int); ok {
+		r0 = rf(ctx, session, tokenHash)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.DBUserData)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, userDataID)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Session, []byte) error); ok {
+		r1 = rf(ctx, session, tokenHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -168,24 +212,223 @@ func (_m *IStorage) GetUserData(ctx context.Context, userDataID int) (*models.DB
 	return r0, r1
 }
 
-// GetUserDataList provides a mock function with given fields: ctx, userID
-func (_m *IStorage) GetUserDataList(ctx context.Context, userID int) ([]models.UserDataListItem, error) {
+// CreateTwoFactorChallenge provides a mock function with given fields: ctx, userID, jti, expiresAt
+func (_m *IStorage) CreateTwoFactorChallenge(ctx context.Context, userID int, jti string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, userID, jti, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTwoFactorChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) error); ok {
+		r0 = rf(ctx, userID, jti, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUpload provides a mock function with given fields: ctx, upload
+func (_m *IStorage) CreateUpload(ctx context.Context, upload *models.Upload) error {
+	ret := _m.Called(ctx, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Upload) error); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFolder provides a mock function with given fields: ctx, folderID, userID
+func (_m *IStorage) DeleteFolder(ctx context.Context, folderID int, userID int) error {
+	ret := _m.Called(ctx, folderID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFolder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, folderID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUpload provides a mock function with given fields: ctx, uploadID
+func (_m *IStorage) DeleteUpload(ctx context.Context, uploadID string) error {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserData provides a mock function with given fields: ctx, userDataID
+func (_m *IStorage) DeleteUserData(ctx context.Context, userDataID int) error {
+	ret := _m.Called(ctx, userDataID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userDataID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTOTP provides a mock function with given fields: ctx, userID
+func (_m *IStorage) DisableTOTP(ctx context.Context, userID int) error {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserDataList")
+		panic("no return value specified for DisableTOTP")
 	}
 
-	var r0 []models.UserDataListItem
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EmptyTrash provides a mock function with given fields: ctx, userID
+func (_m *IStorage) EmptyTrash(ctx context.Context, userID int) (int, []string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EmptyTrash")
+	}
+
+	var r0 int
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, []string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) []string); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int) error); ok {
+		r2 = rf(ctx, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// EnableTOTP provides a mock function with given fields: ctx, userID, step, recoveryCodeHashes
+func (_m *IStorage) EnableTOTP(ctx context.Context, userID int, step int64, recoveryCodeHashes [][]byte) error {
+	ret := _m.Called(ctx, userID, step, recoveryCodeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, [][]byte) error); ok {
+		r0 = rf(ctx, userID, step, recoveryCodeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishKeyRotationJob provides a mock function with given fields: ctx, jobID, status, reason
+func (_m *IStorage) FinishKeyRotationJob(ctx context.Context, jobID int, status string, reason string) error {
+	ret := _m.Called(ctx, jobID, status, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishKeyRotationJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, jobID, status, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishTwoFactorAttempt provides a mock function with given fields: ctx, userID, jti
+func (_m *IStorage) FinishTwoFactorAttempt(ctx context.Context, userID int, jti string) error {
+	ret := _m.Called(ctx, userID, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishTwoFactorAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, jti)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetClientKey provides a mock function with given fields: ctx, userID
+func (_m *IStorage) GetClientKey(ctx context.Context, userID int) (*models.ClientKey, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClientKey")
+	}
+
+	var r0 *models.ClientKey
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.UserDataListItem, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.ClientKey, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []models.UserDataListItem); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.ClientKey); ok {
 		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.UserDataListItem)
+			r0 = ret.Get(0).(*models.ClientKey)
 		}
 	}
 
@@ -198,27 +441,29 @@ func (_m *IStorage) GetUserDataList(ctx context.Context, userID int) ([]models.U
 	return r0, r1
 }
 
-// SaveMasterKey provides a mock function with given fields: ctx, userID, encryptedMK, nonce
-func (_m *IStorage) SaveMasterKey(ctx context.Context, userID int, encryptedMK []byte, nonce []byte) (int, error) {
-	ret := _m.Called(ctx, userID, encryptedMK, nonce)
+// GetKeyRotationJob provides a mock function with given fields: ctx
+func (_m *IStorage) GetKeyRotationJob(ctx context.Context) (*models.KeyRotationJob, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveMasterKey")
+		panic("no return value specified for GetKeyRotationJob")
 	}
 
-	var r0 int
+	var r0 *models.KeyRotationJob
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, []byte, []byte) (int, error)); ok {
-		return rf(ctx, userID, encryptedMK, nonce)
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.KeyRotationJob, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, []byte, []byte) int); ok {
-		r0 = rf(ctx, userID, encryptedMK, nonce)
+	if rf, ok := ret.Get(0).(func(context.Context) *models.KeyRotationJob); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KeyRotationJob)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, []byte, []byte) error); ok {
-		r1 = rf(ctx, userID, encryptedMK, nonce)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -226,27 +471,29 @@ func (_m *IStorage) SaveMasterKey(ctx context.Context, userID int, encryptedMK [
 	return r0, r1
 }
 
-// SaveUserData provides a mock function with given fields: ctx, userData
-func (_m *IStorage) SaveUserData(ctx context.Context, userData *models.DBUserData) (int, error) {
-	ret := _m.Called(ctx, userData)
+// GetMasterKey provides a mock function with given fields: ctx, userID
+func (_m *IStorage) GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveUserData")
+		panic("no return value specified for GetMasterKey")
 	}
 
-	var r0 int
+	var r0 *models.EncryptedMK
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.DBUserData) (int, error)); ok {
-		return rf(ctx, userData)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.EncryptedMK, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *models.DBUserData) int); ok {
-		r0 = rf(ctx, userData)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.EncryptedMK); ok {
+		r0 = rf(ctx, userID)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EncryptedMK)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *models.DBUserData) error); ok {
-		r1 = rf(ctx, userData)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -254,6 +501,1058 @@ func (_m *IStorage) SaveUserData(ctx context.Context, userData *models.DBUserDat
 	return r0, r1
 }
 
+// GetTOTP provides a mock function with given fields: ctx, userID
+func (_m *IStorage) GetTOTP(ctx context.Context, userID int) (*models.TOTP, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTP")
+	}
+
+	var r0 *models.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.TOTP, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.TOTP); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrashList provides a mock function with given fields: ctx, userID
+func (_m *IStorage) GetTrashList(ctx context.Context, userID int) ([]models.UserDataListItem, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrashList")
+	}
+
+	var r0 []models.UserDataListItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.UserDataListItem, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.UserDataListItem); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserDataListItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUpload provides a mock function with given fields: ctx, uploadID
+func (_m *IStorage) GetUpload(ctx context.Context, uploadID string) (*models.Upload, error) {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUpload")
+	}
+
+	var r0 *models.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Upload, error)); ok {
+		return rf(ctx, uploadID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Upload); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uploadID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: ctx, username
+func (_m *IStorage) GetUser(ctx context.Context, username string) (*models.UserEntry, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 *models.UserEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.UserEntry, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UserEntry); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *IStorage) GetUserByID(ctx context.Context, id int) (*models.UserEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *models.UserEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.UserEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.UserEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserData provides a mock function with given fields: ctx, userDataID
+func (_m *IStorage) GetUserData(ctx context.Context, userDataID int) (*models.DBUserData, error) {
+	ret := _m.Called(ctx, userDataID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserData")
+	}
+
+	var r0 *models.DBUserData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.DBUserData, error)); ok {
+		return rf(ctx, userDataID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.DBUserData); ok {
+		r0 = rf(ctx, userDataID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DBUserData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userDataID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserDataList provides a mock function with given fields: ctx, userID, filter
+func (_m *IStorage) GetUserDataList(ctx context.Context, userID int, filter models.UserDataListFilter) (*models.UserDataPage, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserDataList")
+	}
+
+	var r0 *models.UserDataPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.UserDataListFilter) (*models.UserDataPage, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.UserDataListFilter) *models.UserDataPage); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserDataPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.UserDataListFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserDataVersion provides a mock function with given fields: ctx, userDataID, version
+func (_m *IStorage) GetUserDataVersion(ctx context.Context, userDataID int, version int) (*models.UserDataVersion, error) {
+	ret := _m.Called(ctx, userDataID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserDataVersion")
+	}
+
+	var r0 *models.UserDataVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.UserDataVersion, error)); ok {
+		return rf(ctx, userDataID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.UserDataVersion); ok {
+		r0 = rf(ctx, userDataID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserDataVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userDataID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExpiredUploads provides a mock function with given fields: ctx, before, limit
+func (_m *IStorage) ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]*models.Upload, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListExpiredUploads")
+	}
+
+	var r0 []*models.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*models.Upload, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*models.Upload); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFolders provides a mock function with given fields: ctx, userID
+func (_m *IStorage) ListFolders(ctx context.Context, userID int) ([]models.Folder, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFolders")
+	}
+
+	var r0 []models.Folder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Folder, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Folder); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Folder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListObjectReferences provides a mock function with given fields: ctx
+func (_m *IStorage) ListObjectReferences(ctx context.Context) ([]models.ObjectReference, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListObjectReferences")
+	}
+
+	var r0 []models.ObjectReference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.ObjectReference, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.ObjectReference); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ObjectReference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: ctx, userID
+func (_m *IStorage) ListSessions(ctx context.Context, userID int) ([]*models.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []*models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*models.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*models.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUploadParts provides a mock function with given fields: ctx, uploadID
+func (_m *IStorage) ListUploadParts(ctx context.Context, uploadID string) ([]models.UploadPart, error) {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUploadParts")
+	}
+
+	var r0 []models.UploadPart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.UploadPart, error)); ok {
+		return rf(ctx, uploadID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.UploadPart); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UploadPart)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uploadID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUserDataVersions provides a mock function with given fields: ctx, userDataID
+func (_m *IStorage) ListUserDataVersions(ctx context.Context, userDataID int) ([]models.UserDataVersion, error) {
+	ret := _m.Called(ctx, userDataID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserDataVersions")
+	}
+
+	var r0 []models.UserDataVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.UserDataVersion, error)); ok {
+		return rf(ctx, userDataID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.UserDataVersion); ok {
+		r0 = rf(ctx, userDataID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserDataVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userDataID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveFolder provides a mock function with given fields: ctx, folderID, userID, parentID
+func (_m *IStorage) MoveFolder(ctx context.Context, folderID int, userID int, parentID int) error {
+	ret := _m.Called(ctx, folderID, userID, parentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveFolder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = rf(ctx, folderID, userID, parentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MoveUserData provides a mock function with given fields: ctx, userID, userDataIDs, folderID
+func (_m *IStorage) MoveUserData(ctx context.Context, userID int, userDataIDs []int, folderID int) error {
+	ret := _m.Called(ctx, userID, userDataIDs, folderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []int, int) error); ok {
+		r0 = rf(ctx, userID, userDataIDs, folderID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeTrash provides a mock function with given fields: ctx, deletedBefore, limit
+func (_m *IStorage) PurgeTrash(ctx context.Context, deletedBefore time.Time, limit int) (int, []string, error) {
+	ret := _m.Called(ctx, deletedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTrash")
+	}
+
+	var r0 int
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int, []string, error)); ok {
+		return rf(ctx, deletedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int); ok {
+		r0 = rf(ctx, deletedBefore, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) []string); ok {
+		r1 = rf(ctx, deletedBefore, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time, int) error); ok {
+		r2 = rf(ctx, deletedBefore, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReleaseUpload provides a mock function with given fields: ctx, uploadID
+func (_m *IStorage) ReleaseUpload(ctx context.Context, uploadID string) error {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenameFolder provides a mock function with given fields: ctx, folder
+func (_m *IStorage) RenameFolder(ctx context.Context, folder *models.Folder) error {
+	ret := _m.Called(ctx, folder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameFolder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Folder) error); ok {
+		r0 = rf(ctx, folder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveUploadPart provides a mock function with given fields: ctx, uploadID, number, digest
+func (_m *IStorage) ReserveUploadPart(ctx context.Context, uploadID string, number int, digest []byte) ([]byte, error) {
+	ret := _m.Called(ctx, uploadID, number, digest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveUploadPart")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, []byte) ([]byte, error)); ok {
+		return rf(ctx, uploadID, number, digest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, []byte) []byte); ok {
+		r0 = rf(ctx, uploadID, number, digest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, []byte) error); ok {
+		r1 = rf(ctx, uploadID, number, digest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreUserData provides a mock function with given fields: ctx, userDataID, userID
+func (_m *IStorage) RestoreUserData(ctx context.Context, userDataID int, userID int) error {
+	ret := _m.Called(ctx, userDataID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userDataID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreUserDataVersion provides a mock function with given fields: ctx, userDataID, userID, version, keep
+func (_m *IStorage) RestoreUserDataVersion(ctx context.Context, userDataID int, userID int, version int, keep int) (int, []string, error) {
+	ret := _m.Called(ctx, userDataID, userID, version, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUserDataVersion")
+	}
+
+	var r0 int
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) (int, []string, error)); ok {
+		return rf(ctx, userDataID, userID, version, keep)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, int) int); ok {
+		r0 = rf(ctx, userDataID, userID, version, keep)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, int) []string); ok {
+		r1 = rf(ctx, userDataID, userID, version, keep)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, int, int) error); ok {
+		r2 = rf(ctx, userDataID, userID, version, keep)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RevokeOtherSessions provides a mock function with given fields: ctx, userID, keepSessionID, reason
+func (_m *IStorage) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID int, reason string) (int, error) {
+	ret := _m.Called(ctx, userID, keepSessionID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeOtherSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) (int, error)); ok {
+		return rf(ctx, userID, keepSessionID, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) int); ok {
+		r0 = rf(ctx, userID, keepSessionID, reason)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = rf(ctx, userID, keepSessionID, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID, reason
+func (_m *IStorage) RevokeSession(ctx context.Context, userID int, sessionID int, reason string) error {
+	ret := _m.Called(ctx, userID, sessionID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = rf(ctx, userID, sessionID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewrapMasterKeysBatch provides a mock function with given fields: ctx, jobID, limit, rewrap
+func (_m *IStorage) RewrapMasterKeysBatch(ctx context.Context, jobID int, limit int, rewrap func(*models.EncryptedMK) error) (int, error) {
+	ret := _m.Called(ctx, jobID, limit, rewrap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewrapMasterKeysBatch")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(*models.EncryptedMK) error) (int, error)); ok {
+		return rf(ctx, jobID, limit, rewrap)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(*models.EncryptedMK) error) int); ok {
+		r0 = rf(ctx, jobID, limit, rewrap)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, func(*models.EncryptedMK) error) error); ok {
+		r1 = rf(ctx, jobID, limit, rewrap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewrapTOTPSecretsBatch provides a mock function with given fields: ctx, jobID, limit, rewrap
+func (_m *IStorage) RewrapTOTPSecretsBatch(ctx context.Context, jobID int, limit int, rewrap func(*models.WrappedSecret) error) (int, error) {
+	ret := _m.Called(ctx, jobID, limit, rewrap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewrapTOTPSecretsBatch")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(*models.WrappedSecret) error) (int, error)); ok {
+		return rf(ctx, jobID, limit, rewrap)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(*models.WrappedSecret) error) int); ok {
+		r0 = rf(ctx, jobID, limit, rewrap)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, func(*models.WrappedSecret) error) error); ok {
+		r1 = rf(ctx, jobID, limit, rewrap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, tokenHash, newHash, expiresAt
+func (_m *IStorage) RotateRefreshToken(ctx context.Context, tokenHash []byte, newHash []byte, expiresAt time.Time) (*models.Session, error) {
+	ret := _m.Called(ctx, tokenHash, newHash, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 *models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte, time.Time) (*models.Session, error)); ok {
+		return rf(ctx, tokenHash, newHash, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte, time.Time) *models.Session); ok {
+		r0 = rf(ctx, tokenHash, newHash, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []byte, time.Time) error); ok {
+		r1 = rf(ctx, tokenHash, newHash, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveMasterKey provides a mock function with given fields: ctx, key
+func (_m *IStorage) SaveMasterKey(ctx context.Context, key *models.EncryptedMK) (int, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMasterKey")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EncryptedMK) (int, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EncryptedMK) int); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.EncryptedMK) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTOTPSecret provides a mock function with given fields: ctx, userID, secret
+func (_m *IStorage) SaveTOTPSecret(ctx context.Context, userID int, secret *models.WrappedSecret) error {
+	ret := _m.Called(ctx, userID, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTOTPSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.WrappedSecret) error); ok {
+		r0 = rf(ctx, userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveUserData provides a mock function with given fields: ctx, userData
+func (_m *IStorage) SaveUserData(ctx context.Context, userData *models.DBUserData) (int, error) {
+	ret := _m.Called(ctx, userData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUserData")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DBUserData) (int, error)); ok {
+		return rf(ctx, userData)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DBUserData) int); ok {
+		r0 = rf(ctx, userData)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.DBUserData) error); ok {
+		r1 = rf(ctx, userData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchUserData provides a mock function with given fields: ctx, userID, query
+func (_m *IStorage) SearchUserData(ctx context.Context, userID int, query models.SearchQuery) ([]models.SearchResult, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchUserData")
+	}
+
+	var r0 []models.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.SearchQuery) ([]models.SearchResult, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.SearchQuery) []models.SearchResult); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.SearchQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVersionRetention provides a mock function with given fields: ctx, userID, retention
+func (_m *IStorage) SetVersionRetention(ctx context.Context, userID int, retention int) error {
+	ret := _m.Called(ctx, userID, retention)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetVersionRetention")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, retention)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartTwoFactorAttempt provides a mock function with given fields: ctx, userID, jti, limits
+func (_m *IStorage) StartTwoFactorAttempt(ctx context.Context, userID int, jti string, limits models.TwoFactorLimits) error {
+	ret := _m.Called(ctx, userID, jti, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartTwoFactorAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, models.TwoFactorLimits) error); ok {
+		r0 = rf(ctx, userID, jti, limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: ctx, sessionID, ip
+func (_m *IStorage) TouchSession(ctx context.Context, sessionID int, ip string) (bool, error) {
+	ret := _m.Called(ctx, sessionID, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, sessionID, ip)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, sessionID, ip)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, sessionID, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TrashUserData provides a mock function with given fields: ctx, userDataID, userID
+func (_m *IStorage) TrashUserData(ctx context.Context, userDataID int, userID int) error {
+	ret := _m.Called(ctx, userDataID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TrashUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userDataID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateClientPassword provides a mock function with given fields: ctx, userID, passwordHash, key
+func (_m *IStorage) UpdateClientPassword(ctx context.Context, userID int, passwordHash string, key *models.ClientKey) error {
+	ret := _m.Called(ctx, userID, passwordHash, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateClientPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *models.ClientKey) error); ok {
+		r0 = rf(ctx, userID, passwordHash, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateMasterKey provides a mock function with given fields: ctx, key, rewrapDEK
+func (_m *IStorage) UpdateMasterKey(ctx context.Context, key *models.EncryptedMK, rewrapDEK func([]byte, []byte) ([]byte, []byte, error)) error {
+	ret := _m.Called(ctx, key, rewrapDEK)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMasterKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EncryptedMK, func([]byte, []byte) ([]byte, []byte, error)) error); ok {
+		r0 = rf(ctx, key, rewrapDEK)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, userID, passwordHash, rewrap
+func (_m *IStorage) UpdatePassword(ctx context.Context, userID int, passwordHash string, rewrap func(*models.EncryptedMK) (func([]byte, []byte) ([]byte, []byte, error), error)) error {
+	ret := _m.Called(ctx, userID, passwordHash, rewrap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, func(*models.EncryptedMK) (func([]byte, []byte) ([]byte, []byte, error), error)) error); ok {
+		r0 = rf(ctx, userID, passwordHash, rewrap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *IStorage) UpdateTOTPStep(ctx context.Context, userID int, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserData provides a mock function with given fields: ctx, userDataID, userData, keep
+func (_m *IStorage) UpdateUserData(ctx context.Context, userDataID int, userData *models.DBUserData, keep int) ([]string, error) {
+	ret := _m.Called(ctx, userDataID, userData, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserData")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.DBUserData, int) ([]string, error)); ok {
+		return rf(ctx, userDataID, userData, keep)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.DBUserData, int) []string); ok {
+		r0 = rf(ctx, userDataID, userData, keep)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.DBUserData, int) error); ok {
+		r1 = rf(ctx, userDataID, userData, keep)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *IStorage) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) error {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []byte) error); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIStorage creates a new instance of IStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIStorage(t interface {
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/apetsko/gophkeeper/models"
 )
 
 // KeyManagerInterface is an autogenerated mock type for the KeyManagerInterface type
@@ -43,9 +45,9 @@ func (_m *KeyManagerInterface) GetMasterKey(ctx context.Context, userID int) ([]
 	return r0, r1
 }
 
-// GetOrCreateMasterKey provides a mock function with given fields: ctx, userID, userPassword, userSalt
-func (_m *KeyManagerInterface) GetOrCreateMasterKey(ctx context.Context, userID int, userPassword string, userSalt []byte) ([]byte, error) {
-	ret := _m.Called(ctx, userID, userPassword, userSalt)
+// GetOrCreateMasterKey provides a mock function with given fields: ctx, userID, userPassword
+func (_m *KeyManagerInterface) GetOrCreateMasterKey(ctx context.Context, userID int, userPassword string) ([]byte, error) {
+	ret := _m.Called(ctx, userID, userPassword)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetOrCreateMasterKey")
@@ -53,19 +55,109 @@ func (_m *KeyManagerInterface) GetOrCreateMasterKey(ctx context.Context, userID
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string, []byte) ([]byte, error)); ok {
-		return rf(ctx, userID, userPassword, userSalt)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) ([]byte, error)); ok {
+		return rf(ctx, userID, userPassword)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, string, []byte) []byte); ok {
-		r0 = rf(ctx, userID, userPassword, userSalt)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) []byte); ok {
+		r0 = rf(ctx, userID, userPassword)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, string, []byte) error); ok {
-		r1 = rf(ctx, userID, userPassword, userSalt)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, userPassword)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenSecret provides a mock function with given fields: ctx, secret
+func (_m *KeyManagerInterface) OpenSecret(ctx context.Context, secret *models.WrappedSecret) ([]byte, error) {
+	ret := _m.Called(ctx, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenSecret")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WrappedSecret) ([]byte, error)); ok {
+		return rf(ctx, secret)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WrappedSecret) []byte); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.WrappedSecret) error); ok {
+		r1 = rf(ctx, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewrapMasterKey provides a mock function with given fields: ctx, key, oldPassword, newPassword
+func (_m *KeyManagerInterface) RewrapMasterKey(ctx context.Context, key *models.EncryptedMK, oldPassword string, newPassword string) (func([]byte, []byte) ([]byte, []byte, error), error) {
+	ret := _m.Called(ctx, key, oldPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewrapMasterKey")
+	}
+
+	var r0 func([]byte, []byte) ([]byte, []byte, error)
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EncryptedMK, string, string) (func([]byte, []byte) ([]byte, []byte, error), error)); ok {
+		return rf(ctx, key, oldPassword, newPassword)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EncryptedMK, string, string) func([]byte, []byte) ([]byte, []byte, error)); ok {
+		r0 = rf(ctx, key, oldPassword, newPassword)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func([]byte, []byte) ([]byte, []byte, error))
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.EncryptedMK, string, string) error); ok {
+		r1 = rf(ctx, key, oldPassword, newPassword)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SealSecret provides a mock function with given fields: ctx, plaintext
+func (_m *KeyManagerInterface) SealSecret(ctx context.Context, plaintext []byte) (*models.WrappedSecret, error) {
+	ret := _m.Called(ctx, plaintext)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SealSecret")
+	}
+
+	var r0 *models.WrappedSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (*models.WrappedSecret, error)); ok {
+		return rf(ctx, plaintext)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *models.WrappedSecret); ok {
+		r0 = rf(ctx, plaintext)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WrappedSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, plaintext)
 	} else {
 		r1 = ret.Error(1)
 	}
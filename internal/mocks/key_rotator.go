@@ -0,0 +1,90 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/apetsko/gophkeeper/models"
+)
+
+// KeyRotator is an autogenerated mock type for the KeyRotator type
+type KeyRotator struct {
+	mock.Mock
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *KeyRotator) Start(ctx context.Context) (*models.KeyRotationJob, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 *models.KeyRotationJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.KeyRotationJob, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.KeyRotationJob); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KeyRotationJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Status provides a mock function with given fields: ctx
+func (_m *KeyRotator) Status(ctx context.Context) (*models.KeyRotationJob, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Status")
+	}
+
+	var r0 *models.KeyRotationJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.KeyRotationJob, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.KeyRotationJob); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.KeyRotationJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewKeyRotator creates a new instance of KeyRotator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyRotator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyRotator {
+	mock := &KeyRotator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
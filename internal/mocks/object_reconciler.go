@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/apetsko/gophkeeper/models"
+)
+
+// ObjectReconciler is an autogenerated mock type for the ObjectReconciler type
+type ObjectReconciler struct {
+	mock.Mock
+}
+
+// Reconcile provides a mock function with given fields: ctx, repair
+func (_m *ObjectReconciler) Reconcile(ctx context.Context, repair bool) (*models.ReconcileReport, error) {
+	ret := _m.Called(ctx, repair)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reconcile")
+	}
+
+	var r0 *models.ReconcileReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) (*models.ReconcileReport, error)); ok {
+		return rf(ctx, repair)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) *models.ReconcileReport); ok {
+		r0 = rf(ctx, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReconcileReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewObjectReconciler creates a new instance of ObjectReconciler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewObjectReconciler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ObjectReconciler {
+	mock := &ObjectReconciler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
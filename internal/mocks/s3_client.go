@@ -4,8 +4,10 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	minio "github.com/minio/minio-go/v7"
+
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/apetsko/gophkeeper/models"
@@ -16,6 +18,88 @@ type S3Client struct {
 	mock.Mock
 }
 
+// AbortMultipartUpload provides a mock function with given fields: ctx, objectName, uploadID
+func (_m *S3Client) AbortMultipartUpload(ctx context.Context, objectName string, uploadID string) error {
+	ret := _m.Called(ctx, objectName, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AbortMultipartUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, objectName, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteMultipartUpload provides a mock function with given fields: ctx, objectName, uploadID, parts
+func (_m *S3Client) CompleteMultipartUpload(ctx context.Context, objectName string, uploadID string, parts []models.UploadPart) error {
+	ret := _m.Called(ctx, objectName, uploadID, parts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteMultipartUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []models.UploadPart) error); ok {
+		r0 = rf(ctx, objectName, uploadID, parts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateMultipartUpload provides a mock function with given fields: ctx, s3UploadData
+func (_m *S3Client) CreateMultipartUpload(ctx context.Context, s3UploadData *models.S3UploadData) (string, error) {
+	ret := _m.Called(ctx, s3UploadData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMultipartUpload")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.S3UploadData) (string, error)); ok {
+		return rf(ctx, s3UploadData)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.S3UploadData) string); ok {
+		r0 = rf(ctx, s3UploadData)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.S3UploadData) error); ok {
+		r1 = rf(ctx, s3UploadData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, objectName
+func (_m *S3Client) Delete(ctx context.Context, objectName string) error {
+	ret := _m.Called(ctx, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, objectName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetObject provides a mock function with given fields: ctx, objectName
 func (_m *S3Client) GetObject(ctx context.Context, objectName string) ([]byte, *minio.ObjectInfo, error) {
 	ret := _m.Called(ctx, objectName)
@@ -55,6 +139,75 @@ func (_m *S3Client) GetObject(ctx context.Context, objectName string) ([]byte, *
 	return r0, r1, r2
 }
 
+// GetObjectStream provides a mock function with given fields: ctx, objectName
+func (_m *S3Client) GetObjectStream(ctx context.Context, objectName string) (io.ReadSeekCloser, *minio.ObjectInfo, error) {
+	ret := _m.Called(ctx, objectName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetObjectStream")
+	}
+
+	var r0 io.ReadSeekCloser
+	var r1 *minio.ObjectInfo
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadSeekCloser, *minio.ObjectInfo, error)); ok {
+		return rf(ctx, objectName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadSeekCloser); ok {
+		r0 = rf(ctx, objectName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadSeekCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *minio.ObjectInfo); ok {
+		r1 = rf(ctx, objectName)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*minio.ObjectInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, objectName)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *S3Client) List(ctx context.Context) ([]models.S3Object, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []models.S3Object
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.S3Object, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.S3Object); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.S3Object)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Upload provides a mock function with given fields: ctx, data, s3UploadData
 func (_m *S3Client) Upload(ctx context.Context, data []byte, s3UploadData *models.S3UploadData) (*minio.UploadInfo, error) {
 	ret := _m.Called(ctx, data, s3UploadData)
@@ -85,6 +238,64 @@ func (_m *S3Client) Upload(ctx context.Context, data []byte, s3UploadData *model
 	return r0, r1
 }
 
+// UploadPart provides a mock function with given fields: ctx, objectName, uploadID, number, data
+func (_m *S3Client) UploadPart(ctx context.Context, objectName string, uploadID string, number int, data []byte) (string, error) {
+	ret := _m.Called(ctx, objectName, uploadID, number, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadPart")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, []byte) (string, error)); ok {
+		return rf(ctx, objectName, uploadID, number, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, []byte) string); ok {
+		r0 = rf(ctx, objectName, uploadID, number, data)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, []byte) error); ok {
+		r1 = rf(ctx, objectName, uploadID, number, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadStream provides a mock function with given fields: ctx, r, s3UploadData
+func (_m *S3Client) UploadStream(ctx context.Context, r io.Reader, s3UploadData *models.S3UploadData) (*minio.UploadInfo, error) {
+	ret := _m.Called(ctx, r, s3UploadData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadStream")
+	}
+
+	var r0 *minio.UploadInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, *models.S3UploadData) (*minio.UploadInfo, error)); ok {
+		return rf(ctx, r, s3UploadData)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, *models.S3UploadData) *minio.UploadInfo); ok {
+		r0 = rf(ctx, r, s3UploadData)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*minio.UploadInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, *models.S3UploadData) error); ok {
+		r1 = rf(ctx, r, s3UploadData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewS3Client creates a new instance of S3Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewS3Client(t interface {
@@ -0,0 +1,140 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/password"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ChangePassword handles the gRPC request to change the authenticated user's password.
+//
+// The old password is verified, and the existing master key is re-wrapped for the new one, so
+// all DEKs in user_data stay valid and nothing is re-encrypted. The key is read, re-wrapped and
+// stored with the new password hash in a single transaction. A legacy (version 0) master key,
+// derived from the old password, is replaced with a random one, re-wrapping the DEKs.
+//
+// All other sessions of the user are revoked once the password has changed.
+//
+// For zero-knowledge accounts both passwords are the client-derived authentication keys, and
+// the client sends its new KDF parameters and the master key it re-wrapped itself.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ChangePasswordRequest message with the old and new passwords.
+//
+// Returns:
+//   - *pbrpcu.ChangePasswordResponse: A response indicating success.
+//   - error: A gRPC error if the old password is wrong or the update fails.
+func (s *ServerAdmin) ChangePassword(
+	ctx context.Context,
+	in *pbrpcu.ChangePasswordRequest,
+) (*pbrpcu.ChangePasswordResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	if len(in.GetNewPassword()) < 8 {
+		return nil, status.Errorf(codes.InvalidArgument, "пароль должен быть не короче 8 символов")
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "пользователь не найден")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения пользователя")
+	}
+
+	if !password.CheckPasswordHash(in.GetOldPassword(), user.PasswordHash) {
+		return nil, status.Errorf(codes.Unauthenticated, "неверный пароль")
+	}
+
+	hash, err := password.HashPassword(in.GetNewPassword())
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if user.EncryptionMode == models.EncryptionModeClient {
+		err = s.changeClientPassword(ctx, userID, hash, in)
+	} else {
+		err = s.changeServerPassword(ctx, userID, hash, in)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions opened with the old password end with it; a failure does not undo the change
+	sessionID, _ := ctx.Value(constants.SessionID).(int)
+	if _, err := s.Storage.RevokeOtherSessions(ctx, userID, sessionID, "password changed"); err != nil {
+		slog.Warn("failed to revoke sessions after password change", "user_id", userID, "error", err.Error())
+	}
+
+	return &pbrpcu.ChangePasswordResponse{Message: "пароль успешно изменен"}, nil
+}
+
+// changeServerPassword re-wraps the server-managed master key and stores it with the new hash.
+// The key is re-wrapped inside the storage transaction that holds its row locked.
+func (s *ServerAdmin) changeServerPassword(
+	ctx context.Context,
+	userID int,
+	hash string,
+	in *pbrpcu.ChangePasswordRequest,
+) error {
+	err := s.Storage.UpdatePassword(
+		ctx,
+		userID,
+		hash,
+		func(key *models.EncryptedMK) (func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error) {
+			return s.KeyManager.RewrapMasterKey(ctx, key, in.GetOldPassword(), in.GetNewPassword())
+		},
+	)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidPassword) {
+			return status.Errorf(codes.Unauthenticated, "неверный пароль")
+		}
+		slog.Error("failed to update password: " + err.Error())
+		return status.Errorf(codes.Internal, "ошибка сохранения пароля")
+	}
+
+	return nil
+}
+
+// changeClientPassword stores the key material re-wrapped by a zero-knowledge client with the new hash.
+func (s *ServerAdmin) changeClientPassword(
+	ctx context.Context,
+	userID int,
+	hash string,
+	in *pbrpcu.ChangePasswordRequest,
+) error {
+	kdf := kdfFromProto(in.GetKdf())
+	if err := crypto.ValidateKDFParams(kdf); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if len(in.GetWrappedMasterKey()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "отсутствует зашифрованный мастер-ключ")
+	}
+
+	err := s.Storage.UpdateClientPassword(ctx, userID, hash, &models.ClientKey{
+		UserID:           userID,
+		KDF:              kdf,
+		WrappedMasterKey: in.GetWrappedMasterKey(),
+	})
+	if err != nil {
+		slog.Error("failed to update password: " + err.Error())
+		return status.Errorf(codes.Internal, "ошибка сохранения пароля")
+	}
+
+	return nil
+}
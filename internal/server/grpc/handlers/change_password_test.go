@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+	"github.com/apetsko/gophkeeper/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServerAdmin_ChangePassword(t *testing.T) {
+	const (
+		userID    = 42
+		sessionID = 7
+		oldPass   = "password123"
+		newPass   = "password456"
+	)
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	ctx = context.WithValue(ctx, constants.SessionID, sessionID)
+	hash, _ := utils.HashPassword(oldPass)
+
+	serverUser := &models.UserEntry{ID: userID, Username: "user", PasswordHash: string(hash), EncryptionMode: models.EncryptionModeServer}
+	clientUser := &models.UserEntry{ID: userID, Username: "user", PasswordHash: string(hash), EncryptionMode: models.EncryptionModeClient}
+
+	// rewrapLocked stands in for the storage transaction, handing the locked key to rewrap.
+	rewrapLocked := func(
+		_ context.Context,
+		userID int,
+		_ string,
+		rewrap func(*models.EncryptedMK) (func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error),
+	) error {
+		_, err := rewrap(&models.EncryptedMK{UserID: userID})
+		return err
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        *pbrpcu.ChangePasswordRequest
+		setupMocks func(st *mocks.IStorage, km *mocks.KeyManagerInterface)
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			ctx:  ctx,
+			req:  &pbrpcu.ChangePasswordRequest{OldPassword: oldPass, NewPassword: newPass},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(serverUser, nil)
+				km.On("RewrapMasterKey", mock.Anything, &models.EncryptedMK{UserID: userID}, oldPass, newPass).Return(nil, nil)
+				st.On("UpdatePassword", mock.Anything, userID, mock.AnythingOfType("string"), mock.Anything).Return(rewrapLocked)
+				st.On("RevokeOtherSessions", mock.Anything, userID, sessionID, "password changed").Return(1, nil)
+			},
+		},
+		{
+			name:       "missing user id",
+			ctx:        context.Background(),
+			req:        &pbrpcu.ChangePasswordRequest{OldPassword: oldPass, NewPassword: newPass},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {},
+			wantErr:    true,
+		},
+		{
+			name:       "short new password",
+			ctx:        ctx,
+			req:        &pbrpcu.ChangePasswordRequest{OldPassword: oldPass, NewPassword: "short"},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {},
+			wantErr:    true,
+		},
+		{
+			name: "wrong old password",
+			ctx:  ctx,
+			req:  &pbrpcu.ChangePasswordRequest{OldPassword: "wrongpass", NewPassword: newPass},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(serverUser, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "master key does not match password",
+			ctx:  ctx,
+			req:  &pbrpcu.ChangePasswordRequest{OldPassword: oldPass, NewPassword: newPass},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(serverUser, nil)
+				km.On("RewrapMasterKey", mock.Anything, mock.Anything, oldPass, newPass).Return(nil, crypto.ErrInvalidPassword)
+				st.On("UpdatePassword", mock.Anything, userID, mock.AnythingOfType("string"), mock.Anything).Return(rewrapLocked)
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage error",
+			ctx:  ctx,
+			req:  &pbrpcu.ChangePasswordRequest{OldPassword: oldPass, NewPassword: newPass},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(serverUser, nil)
+				st.On("UpdatePassword", mock.Anything, userID, mock.AnythingOfType("string"), mock.Anything).Return(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero knowledge",
+			ctx:  ctx,
+			req: &pbrpcu.ChangePasswordRequest{
+				OldPassword:      oldPass,
+				NewPassword:      newPass,
+				Kdf:              testKDF(),
+				WrappedMasterKey: []byte("rewrapped"),
+			},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(clientUser, nil)
+				st.On("UpdateClientPassword", mock.Anything, userID, mock.AnythingOfType("string"), mock.MatchedBy(func(k *models.ClientKey) bool {
+					return string(k.WrappedMasterKey) == "rewrapped"
+				})).Return(nil)
+				st.On("RevokeOtherSessions", mock.Anything, userID, sessionID, "password changed").Return(0, nil)
+			},
+		},
+		{
+			name: "zero knowledge without master key",
+			ctx:  ctx,
+			req:  &pbrpcu.ChangePasswordRequest{OldPassword: oldPass, NewPassword: newPass, Kdf: testKDF()},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(clientUser, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, km)
+
+			srv := &ServerAdmin{Storage: st, KeyManager: km}
+
+			resp, err := srv.ChangePassword(tt.ctx, tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, resp.GetMessage())
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+)
+
+// maxClientInfoLength bounds the client-supplied details stored with a session.
+const maxClientInfoLength = 200
+
+// ClientIP returns the address a request came from.
+//
+// Requests proxied by the HTTP gateway carry the browser address in x-forwarded-for; it is
+// trusted only on requests marked with constants.Forwarded, since any gRPC client can send the
+// header. For other calls the peer address is used. The value is informational and is not
+// used for access decisions.
+func ClientIP(ctx context.Context) string {
+	if gateway, _ := ctx.Value(constants.Forwarded).(bool); gateway {
+		if forwarded := firstMetadata(ctx, "x-forwarded-for"); forwarded != "" {
+			ip, _, _ := strings.Cut(forwarded, ",")
+			return truncate(strings.TrimSpace(ip))
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// newClientSession returns a session filled with the client details of a login request.
+//
+// The CLI client sends its device name and version in metadata; for browsers the user agent
+// forwarded by the HTTP gateway is used as the device name.
+func newClientSession(ctx context.Context) *models.Session {
+	device := firstMetadata(ctx, string(constants.DeviceName))
+	if device == "" {
+		device = firstMetadata(ctx, "grpcgateway-user-agent")
+	}
+	if device == "" {
+		device = firstMetadata(ctx, "user-agent")
+	}
+
+	return &models.Session{
+		DeviceName:    truncate(device),
+		ClientVersion: truncate(firstMetadata(ctx, string(constants.ClientVersion))),
+		IP:            ClientIP(ctx),
+	}
+}
+
+// firstMetadata returns the first value of an incoming metadata key.
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// truncate cuts s to maxClientInfoLength bytes without splitting a character.
+func truncate(s string) string {
+	if len(s) <= maxClientInfoLength {
+		return s
+	}
+	s = s[:maxClientInfoLength]
+	return strings.ToValidUTF8(s, "")
+}
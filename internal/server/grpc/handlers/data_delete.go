@@ -15,7 +15,8 @@ import (
 // DataDelete handles the gRPC request to delete a user's data record.
 //
 // This method checks user authorization, verifies ownership of the data record,
-// and deletes the record from storage if permitted.
+// and moves the record to the trash if permitted. Trashed records can be restored with
+// RestoreFromTrash until they are purged.
 //
 // Parameters:
 //   - ctx: The gRPC context.
@@ -39,7 +40,7 @@ func (s *ServerAdmin) DataDelete(ctx context.Context, in *pbrpc.DataDeleteReques
 		return nil, status.Errorf(codes.PermissionDenied, "нельзя удалить запись, она не ваша")
 	}
 
-	errDelete := s.Storage.DeleteUserData(ctx, int(in.GetId()))
+	errDelete := s.Storage.TrashUserData(ctx, int(in.GetId()), userID)
 	if errDelete != nil {
 		return nil, status.Errorf(codes.Internal, "ошибка удаления данных")
 	}
@@ -64,13 +64,13 @@ func TestServerAdmin_DataDelete(t *testing.T) {
 			wantErrMessage: "нельзя удалить запись, она не ваша",
 		},
 		{
-			name: "DeleteUserData returns error",
+			name: "TrashUserData returns error",
 			ctx:  context.WithValue(context.Background(), constants.UserID, userID),
 			req:  &pbrpc.DataDeleteRequest{Id: int32(dataID)},
 			mockSetup: func(m *mocks.IStorage) {
 				m.On("GetUserData", mock.Anything, dataID).
 					Return(&models.DBUserData{UserID: userID}, nil).Once()
-				m.On("DeleteUserData", mock.Anything, dataID).
+				m.On("TrashUserData", mock.Anything, dataID, userID).
 					Return(errors.New("delete error")).Once()
 			},
 			wantErr:        true,
@@ -84,7 +84,7 @@ func TestServerAdmin_DataDelete(t *testing.T) {
 			mockSetup: func(m *mocks.IStorage) {
 				m.On("GetUserData", mock.Anything, dataID).
 					Return(&models.DBUserData{UserID: userID}, nil).Once()
-				m.On("DeleteUserData", mock.Anything, dataID).
+				m.On("TrashUserData", mock.Anything, dataID, userID).
 					Return(nil).Once()
 			},
 			wantErr:     false,
@@ -4,42 +4,62 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
 	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
 )
 
-// DataView handles the gRPC request to retrieve a specific user data record by its ID.
+const (
+	// DefaultDataListLimit is the page size of DataList when the request sets no limit.
+	DefaultDataListLimit = 100
+	// MaxDataListLimit is the largest page size of DataList.
+	MaxDataListLimit = 1000
+)
+
+// DataList handles the gRPC request to list a page of the user's records.
 //
-// This method checks user authorization, fetches and decrypts the data (including files from S3 if needed),
-// parses the data according to its type, and returns it in the response.
+// Records can be filtered by type, tags, the favorite flag and by creation and update time,
+// and sorted by either time.
+// The first page is selected by page and limit; following pages are best requested with the
+// next_cursor of the previous one, which stays fast however far the list is paged. The count
+// of the response is the number of records matching the filters on all pages.
 //
 // Parameters:
 // - ctx: The gRPC context.
-// - in: The DataViewRequest message with the data record ID.
+// - in: The DataListRequest message with the page, filters and sort.
 //
 // Returns:
-// - *pbrpc.DataViewResponse: The requested data record.
-// - error: An error if access is denied or retrieval fails.
+// - *pbrpc.DataListResponse: The records of the page, the total count and the next cursor.
+// - error: An error if the request is invalid or retrieval fails.
 func (s *ServerAdmin) DataList(ctx context.Context, in *pbrpc.DataListRequest) (*pbrpc.DataListResponse, error) {
 	userID, ok := ctx.Value(constants.UserID).(int)
 	if !ok {
 		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
 	}
 
-	userDataList, err := s.Storage.GetUserDataList(ctx, userID)
+	filter, err := dataListFilter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.Storage.GetUserDataList(ctx, userID, filter)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "ошибка получения данных: %v", err)
 	}
 
 	var records []*pbmodels.Record
-	for _, data := range userDataList {
+	for _, data := range page.Items {
 		// Преобразуем строку Meta (в формате JSON) в pbmodels.Meta
 		var meta pbmodels.Meta
 		if errUnmarshal := protojson.Unmarshal([]byte(data.Meta), &meta); errUnmarshal != nil {
@@ -52,12 +72,128 @@ func (s *ServerAdmin) DataList(ctx context.Context, in *pbrpc.DataListRequest) (
 			Type:      data.Type,
 			Meta:      &meta,
 			CreatedAt: data.CreatedAt.Format("02.01.2006 15:04"),
+			UpdatedAt: data.UpdatedAt.Format("02.01.2006 15:04"),
+			FolderId:  int32(data.FolderID),
+			Version:   int32(data.Version),
 		}
 		records = append(records, record)
 	}
 
-	return &pbrpc.DataListResponse{
+	resp := &pbrpc.DataListResponse{
 		Records: records,
-		Count:   int32(len(records)), // Или общее количество в базе, если есть пагинация
-	}, nil
+		Count:   int32(page.Total),
+	}
+	if page.Next != nil {
+		resp.NextCursor = encodeListCursor(filter.Sort, page.Next)
+	}
+	return resp, nil
+}
+
+// dataListFilter converts a DataListRequest into the filter of GetUserDataList.
+func dataListFilter(in *pbrpc.DataListRequest) (models.UserDataListFilter, error) {
+	var filter models.UserDataListFilter
+
+	switch in.GetSort() {
+	case pbrpc.DataListSort_DATA_LIST_SORT_UNSPECIFIED, pbrpc.DataListSort_DATA_LIST_SORT_CREATED_DESC:
+		filter.Sort = models.SortCreatedDesc
+	case pbrpc.DataListSort_DATA_LIST_SORT_CREATED_ASC:
+		filter.Sort = models.SortCreatedAsc
+	case pbrpc.DataListSort_DATA_LIST_SORT_UPDATED_DESC:
+		filter.Sort = models.SortUpdatedDesc
+	case pbrpc.DataListSort_DATA_LIST_SORT_UPDATED_ASC:
+		filter.Sort = models.SortUpdatedAsc
+	default:
+		return filter, status.Errorf(codes.InvalidArgument, "неизвестная сортировка: %v", in.GetSort())
+	}
+
+	for _, t := range in.GetTypes() {
+		name := constants.MapDataTypeToString(t)
+		if name == "unknown" {
+			return filter, status.Errorf(codes.InvalidArgument, "неизвестный тип данных: %v", t)
+		}
+		filter.Types = append(filter.Types, name)
+	}
+
+	tags, err := normalizeTags(in.GetTags())
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
+	filter.FavoritesOnly = in.GetFavorite()
+
+	if in.GetFolderId() < 0 {
+		return filter, status.Errorf(codes.InvalidArgument, "неверный ID папки")
+	}
+	filter.FolderID = int(in.GetFolderId())
+	filter.Subfolders = in.GetSubfolders() && filter.FolderID != 0
+
+	for _, bound := range []struct {
+		field string
+		value string
+		dst   *time.Time
+	}{
+		{"created_from", in.GetCreatedFrom(), &filter.CreatedFrom},
+		{"created_to", in.GetCreatedTo(), &filter.CreatedTo},
+		{"updated_from", in.GetUpdatedFrom(), &filter.UpdatedFrom},
+		{"updated_to", in.GetUpdatedTo(), &filter.UpdatedTo},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return filter, status.Errorf(codes.InvalidArgument, "%s должно быть временем в формате RFC 3339", bound.field)
+		}
+		*bound.dst = t
+	}
+
+	if in.GetLimit() < 0 || in.GetPage() < 0 {
+		return filter, status.Errorf(codes.InvalidArgument, "page и limit не могут быть отрицательными")
+	}
+	filter.Limit = int(in.GetLimit())
+	if filter.Limit == 0 {
+		filter.Limit = DefaultDataListLimit
+	}
+	filter.Limit = min(filter.Limit, MaxDataListLimit)
+
+	if in.GetCursor() != "" {
+		after, err := decodeListCursor(filter.Sort, in.GetCursor())
+		if err != nil {
+			return filter, status.Errorf(codes.InvalidArgument, "неверный курсор: %v", err)
+		}
+		filter.After = after
+	} else if in.GetPage() > 1 {
+		filter.Offset = int(in.GetPage()-1) * filter.Limit
+	}
+
+	return filter, nil
+}
+
+// encodeListCursor returns the opaque next_cursor of DataList. The sort is part of the cursor,
+// since the position it holds is meaningless in another order.
+func encodeListCursor(sort models.UserDataSort, cursor *models.UserDataCursor) string {
+	raw := fmt.Sprintf("%d:%d:%d", sort, cursor.Time.UnixMicro(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeListCursor parses a cursor returned by encodeListCursor for the same sort.
+func decodeListCursor(sort models.UserDataSort, s string) (*models.UserDataCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cursorSort models.UserDataSort
+		micros     int64
+		id         int
+	)
+	if _, err = fmt.Sscanf(string(raw), "%d:%d:%d", &cursorSort, &micros, &id); err != nil {
+		return nil, err
+	}
+	if cursorSort != sort {
+		return nil, errors.New("cursor was issued for another sort")
+	}
+
+	return &models.UserDataCursor{Time: time.UnixMicro(micros), ID: id}, nil
 }
@@ -8,11 +8,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/internal/mocks"
 	"github.com/apetsko/gophkeeper/models"
 
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -43,7 +45,7 @@ func TestServerAdmin_DataList(t *testing.T) {
 			name: "storage returns error",
 			ctx:  context.WithValue(context.Background(), constants.UserID, userID),
 			mockSetup: func(m *mocks.IStorage) {
-				m.On("GetUserDataList", mock.Anything, userID).
+				m.On("GetUserDataList", mock.Anything, userID, mock.Anything).
 					Return(nil, errors.New("db error")).Once()
 			},
 			wantErr:         true,
@@ -55,8 +57,8 @@ func TestServerAdmin_DataList(t *testing.T) {
 			ctx:  context.WithValue(context.Background(), constants.UserID, userID),
 			mockSetup: func(m *mocks.IStorage) {
 				now := time.Now()
-				m.On("GetUserDataList", mock.Anything, userID).
-					Return([]models.UserDataListItem{
+				m.On("GetUserDataList", mock.Anything, userID, mock.Anything).
+					Return(&models.UserDataPage{Total: 2, Items: []models.UserDataListItem{
 						{
 							ID:        1,
 							UserID:    userID,
@@ -71,7 +73,7 @@ func TestServerAdmin_DataList(t *testing.T) {
 							Meta:      `{"content":"another content"}`,
 							CreatedAt: now.Add(time.Minute),
 						},
-					}, nil).Once()
+					}}, nil).Once()
 			},
 			wantErr:        false,
 			wantCount:      2,
@@ -82,8 +84,8 @@ func TestServerAdmin_DataList(t *testing.T) {
 			ctx:  context.WithValue(context.Background(), constants.UserID, userID),
 			mockSetup: func(m *mocks.IStorage) {
 				now := time.Now()
-				m.On("GetUserDataList", mock.Anything, userID).
-					Return([]models.UserDataListItem{
+				m.On("GetUserDataList", mock.Anything, userID, mock.Anything).
+					Return(&models.UserDataPage{Total: 2, Items: []models.UserDataListItem{
 						{
 							ID:        1,
 							UserID:    userID,
@@ -98,10 +100,10 @@ func TestServerAdmin_DataList(t *testing.T) {
 							Meta:      `invalid json`,
 							CreatedAt: now.Add(time.Minute),
 						},
-					}, nil).Once()
+					}}, nil).Once()
 			},
 			wantErr:        false,
-			wantCount:      1,
+			wantCount:      2,
 			wantRecordsLen: 1,
 		},
 	}
@@ -134,3 +136,99 @@ func TestServerAdmin_DataList(t *testing.T) {
 		})
 	}
 }
+
+func TestServerAdmin_DataListFilter(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("filters and sort are passed to storage", func(t *testing.T) {
+		mockStorage := &mocks.IStorage{}
+		mockStorage.On("GetUserDataList", mock.Anything, userID, models.UserDataListFilter{
+			Types:       []string{constants.Credentials, constants.BankCard},
+			CreatedFrom: created,
+			UpdatedTo:   created.Add(time.Hour),
+			Sort:        models.SortUpdatedAsc,
+			Offset:      40,
+			Limit:       20,
+		}).Return(&models.UserDataPage{}, nil).Once()
+
+		_, err := (&ServerAdmin{Storage: mockStorage}).DataList(ctx, &pbrpc.DataListRequest{
+			Page:        3,
+			Limit:       20,
+			Types:       []pbc.DataType{pbc.DataType_DATA_TYPE_CREDENTIALS, pbc.DataType_DATA_TYPE_BANK_CARD},
+			CreatedFrom: "2025-03-01T12:00:00Z",
+			UpdatedTo:   "2025-03-01T13:00:00Z",
+			Sort:        pbrpc.DataListSort_DATA_LIST_SORT_UPDATED_ASC,
+		})
+		require.NoError(t, err)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("next cursor continues the list", func(t *testing.T) {
+		next := &models.UserDataCursor{Time: created.Add(123 * time.Microsecond), ID: 7}
+		mockStorage := &mocks.IStorage{}
+		mockStorage.On("GetUserDataList", mock.Anything, userID, models.UserDataListFilter{Limit: DefaultDataListLimit}).
+			Return(&models.UserDataPage{Total: 150, Next: next, Items: []models.UserDataListItem{
+				{ID: 8, Type: constants.Credentials, Meta: "{}", CreatedAt: created, UpdatedAt: created},
+			}}, nil).Once()
+		srv := &ServerAdmin{Storage: mockStorage}
+
+		resp, err := srv.DataList(ctx, &pbrpc.DataListRequest{})
+		require.NoError(t, err)
+		require.Equal(t, int32(150), resp.GetCount())
+		require.NotEmpty(t, resp.GetNextCursor())
+		require.Equal(t, "01.03.2025 12:00", resp.GetRecords()[0].GetUpdatedAt())
+
+		mockStorage.On("GetUserDataList", mock.Anything, userID, mock.MatchedBy(func(f models.UserDataListFilter) bool {
+			return f.After != nil && f.After.ID == 7 && f.After.Time.Equal(next.Time) && f.Offset == 0
+		})).Return(&models.UserDataPage{Total: 150}, nil).Once()
+
+		resp, err = srv.DataList(ctx, &pbrpc.DataListRequest{Cursor: resp.GetNextCursor(), Page: 5})
+		require.NoError(t, err)
+		require.Empty(t, resp.GetNextCursor())
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("folder with subfolders", func(t *testing.T) {
+		mockStorage := &mocks.IStorage{}
+		mockStorage.On("GetUserDataList", mock.Anything, userID, models.UserDataListFilter{
+			FolderID:   5,
+			Subfolders: true,
+			Limit:      DefaultDataListLimit,
+		}).Return(&models.UserDataPage{Items: []models.UserDataListItem{
+			{ID: 8, Type: constants.Credentials, Meta: "{}", FolderID: 6},
+		}}, nil).Once()
+
+		resp, err := (&ServerAdmin{Storage: mockStorage}).DataList(ctx, &pbrpc.DataListRequest{FolderId: 5, Subfolders: true})
+		require.NoError(t, err)
+		require.Equal(t, int32(6), resp.GetRecords()[0].GetFolderId())
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		mockStorage := &mocks.IStorage{}
+		mockStorage.On("GetUserDataList", mock.Anything, userID, models.UserDataListFilter{Limit: MaxDataListLimit}).
+			Return(&models.UserDataPage{}, nil).Once()
+
+		_, err := (&ServerAdmin{Storage: mockStorage}).DataList(ctx, &pbrpc.DataListRequest{Limit: MaxDataListLimit + 1})
+		require.NoError(t, err)
+		mockStorage.AssertExpectations(t)
+	})
+
+	otherSort := encodeListCursor(models.SortCreatedAsc, &models.UserDataCursor{Time: created, ID: 1})
+	for name, req := range map[string]*pbrpc.DataListRequest{
+		"invalid time":           {CreatedTo: "01.03.2025"},
+		"unknown type":           {Types: []pbc.DataType{pbc.DataType_DATA_TYPE_UNSPECIFIED}},
+		"negative limit":         {Limit: -1},
+		"malformed cursor":       {Cursor: "not a cursor"},
+		"cursor of another sort": {Cursor: otherSort},
+		"unknown sort":           {Sort: pbrpc.DataListSort(99)},
+		"negative folder":        {FolderId: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&ServerAdmin{Storage: &mocks.IStorage{}}).DataList(ctx, req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
@@ -4,25 +4,35 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/sshkey"
 	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
 	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
+// MaxSecureNoteSize is the maximum size of the text of a secure note in bytes. Larger texts
+// belong in a file record.
+const MaxSecureNoteSize = 1 << 20
+
+// MaxSSHKeySize is the maximum size of an SSH private key in bytes, ample for 16384-bit RSA keys.
+const MaxSSHKeySize = 16 << 10
+
 // DataSave handles the gRPC request to save user data.
 //
 // This method validates the request, retrieves the user's master key, encrypts the data,
 // and stores it in the database or S3 depending on the data type.
+// Payloads already encrypted by a zero-knowledge client are stored as-is.
 //
 // Parameters:
 // - ctx: The gRPC context.
@@ -42,9 +52,27 @@ func (s *ServerAdmin) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 		return nil, status.Errorf(codes.InvalidArgument, "тип данных не указан")
 	}
 
+	meta, err := normalizeMeta(in.GetMeta())
+	if err != nil {
+		return nil, err
+	}
+
+	if payload := in.GetEncrypted(); payload != nil {
+		if err := s.saveEncryptedPayload(ctx, userID, in.Type, payload, meta); err != nil {
+			return nil, err
+		}
+
+		return &pbrpc.DataSaveResponse{
+			Message: fmt.Sprintf("данные типа %s успешно сохранены", in.Type.String()),
+		}, nil
+	}
+
 	// TODO: переделать на потокобезопасную in memory мапу
 	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userID)
 	if err != nil {
+		if errors.Is(err, models.ErrMasterKeyNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+		}
 		return nil, fmt.Errorf("error get encryptedMK: %v", err)
 	}
 
@@ -56,7 +84,7 @@ func (s *ServerAdmin) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 			return nil, status.Errorf(codes.InvalidArgument, "отсутствуют данные банковской карты")
 		}
 
-		err := s.saveUserData(ctx, userID, in.Type, encryptedMK, bankCard, in.Meta)
+		err := s.saveUserData(ctx, userID, in.Type, encryptedMK, bankCard, meta)
 		if err != nil {
 			return nil, err
 		}
@@ -66,7 +94,37 @@ func (s *ServerAdmin) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 		if creds == nil {
 			return nil, status.Errorf(codes.InvalidArgument, "отсутствуют учетные данные")
 		}
-		err := s.saveUserData(ctx, userID, in.Type, encryptedMK, creds, in.Meta)
+		err := s.saveUserData(ctx, userID, in.Type, encryptedMK, creds, meta)
+		if err != nil {
+			return nil, err
+		}
+
+	case pbc.DataType_DATA_TYPE_TEXT:
+		note := in.GetSecureNote()
+		if err := validateSecureNote(note); err != nil {
+			return nil, err
+		}
+		err := s.saveUserData(ctx, userID, in.Type, encryptedMK, note, meta)
+		if err != nil {
+			return nil, err
+		}
+
+	case pbc.DataType_DATA_TYPE_OTP:
+		otp, err := normalizeOTP(in.GetOtp())
+		if err != nil {
+			return nil, err
+		}
+		err = s.saveUserData(ctx, userID, in.Type, encryptedMK, otp, meta)
+		if err != nil {
+			return nil, err
+		}
+
+	case pbc.DataType_DATA_TYPE_SSH_KEY:
+		key, err := normalizeSSHKey(in.GetSshKey())
+		if err != nil {
+			return nil, err
+		}
+		err = s.saveUserData(ctx, userID, in.Type, encryptedMK, key, meta)
 		if err != nil {
 			return nil, err
 		}
@@ -77,8 +135,8 @@ func (s *ServerAdmin) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 			return nil, status.Errorf(codes.InvalidArgument, "отсутствуют данные файла")
 		}
 
-		// Шифруем содержимое файла
-		encryptedData, err := s.Envelope.EncryptUserData(ctx, encryptedMK, file.Data)
+		// Шифруем содержимое файла в потоковом формате
+		encryptedData, err := s.Envelope.EncryptFile(ctx, encryptedMK, file.Data)
 		if err != nil {
 			slog.Error("failed to encrypt binary data", "error", err)
 			return nil, fmt.Errorf("failed to encrypt binary data: %v", err)
@@ -90,7 +148,7 @@ func (s *ServerAdmin) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 		// Загружаем в S3
 		s3UploadData := &models.S3UploadData{
 			ObjectName:  objectName,
-			MetaContent: in.Meta.Content,
+			MetaContent: meta.GetContent(),
 			FileName:    file.Name,
 			FileType:    file.Type,
 		}
@@ -107,10 +165,14 @@ func (s *ServerAdmin) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 			DataNonce:     encryptedData.DataNonce,
 			EncryptedDek:  encryptedData.EncryptedDek,
 			DekNonce:      encryptedData.DekNonce,
-			Meta:          protojson.Format(in.Meta),
+		}
+		if err = s.sealMeta(ctx, saveUserData, encryptedMK, meta); err != nil {
+			s.discardObject(ctx, objectName)
+			return nil, err
 		}
 		_, err = s.Storage.SaveUserData(ctx, saveUserData)
 		if err != nil {
+			s.discardObject(ctx, objectName)
 			return nil, err
 		}
 
@@ -152,8 +214,123 @@ func (s *ServerAdmin) saveUserData(
 		DataNonce:     encryptedData.DataNonce,
 		EncryptedDek:  encryptedData.EncryptedDek,
 		DekNonce:      encryptedData.DekNonce,
-		Meta:          protojson.Format(meta),
+	}
+	if err = s.sealMeta(ctx, saveUserData, encryptedMK, meta); err != nil {
+		return err
 	}
 	_, err = s.Storage.SaveUserData(ctx, saveUserData)
 	return err
 }
+
+// saveEncryptedPayload stores a payload encrypted by a zero-knowledge client without decrypting it.
+//
+// The server only checks that the account uses client-side encryption; the ciphertext and the
+// wrapped DEK are written verbatim, with binary data going to S3 like server-encrypted files.
+func (s *ServerAdmin) saveEncryptedPayload(
+	ctx context.Context,
+	userID int,
+	dataType pbc.DataType,
+	payload *pbmodels.EncryptedPayload,
+	meta *pbmodels.Meta,
+) error {
+	if len(payload.GetEncryptedDek()) == 0 || len(payload.GetDekNonce()) == 0 || len(payload.GetDataNonce()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "неполные зашифрованные данные")
+	}
+
+	metaJSON, err := plainMeta(meta)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Storage.GetClientKey(ctx, userID); err != nil {
+		if errors.Is(err, models.ErrClientKeyNotFound) {
+			return status.Errorf(codes.FailedPrecondition, "шифрование на клиенте не включено для этого аккаунта")
+		}
+		return status.Errorf(codes.Internal, "ошибка получения ключа клиента")
+	}
+
+	saveUserData := &models.DBUserData{
+		UserID:          userID,
+		Type:            constants.MapDataTypeToString(dataType),
+		EncryptedData:   payload.GetEncryptedData(),
+		DataNonce:       payload.GetDataNonce(),
+		EncryptedDek:    payload.GetEncryptedDek(),
+		DekNonce:        payload.GetDekNonce(),
+		Meta:            metaJSON,
+		ClientEncrypted: true,
+	}
+
+	if dataType == pbc.DataType_DATA_TYPE_BINARY_DATA {
+		// Имя и тип файла зашифрованы вместе с содержимым, поэтому в S3 кладем только шифротекст
+		objectName := fmt.Sprintf("%d-%d", time.Now().UnixNano(), userID)
+		s3UploadData := &models.S3UploadData{
+			ObjectName:  objectName,
+			MetaContent: meta.GetContent(),
+			FileType:    "application/octet-stream",
+		}
+		if _, err := s.StorageS3.Upload(ctx, payload.GetEncryptedData(), s3UploadData); err != nil {
+			return fmt.Errorf("failed to upload file to MinIO: %v", err)
+		}
+
+		saveUserData.EncryptedData = nil
+		saveUserData.MinioObjectID = objectName
+	}
+
+	if _, err := s.Storage.SaveUserData(ctx, saveUserData); err != nil {
+		s.discardObject(ctx, saveUserData.MinioObjectID)
+		return err
+	}
+
+	return nil
+}
+
+// discardObject removes an uploaded object whose record could not be written, so it is not
+// left orphaned in the bucket; an empty name is ignored. The removal outlives a cancelled request.
+func (s *ServerAdmin) discardObject(ctx context.Context, objectName string) {
+	if objectName == "" {
+		return
+	}
+	if err := s.StorageS3.Delete(context.WithoutCancel(ctx), objectName); err != nil {
+		slog.Error("failed to delete orphaned object", "object", objectName, "error", err)
+	}
+}
+
+// validateSecureNote checks the text of a secure note sent by the client.
+func validateSecureNote(note *pbmodels.SecureNote) error {
+	if note == nil {
+		return status.Errorf(codes.InvalidArgument, "отсутствует текст заметки")
+	}
+	if len(note.GetText()) > MaxSecureNoteSize {
+		return status.Errorf(codes.InvalidArgument, "текст заметки больше %d байт", MaxSecureNoteSize)
+	}
+	return nil
+}
+
+// normalizeSSHKey checks an SSH key sent by the client and returns it with the public key and
+// fingerprint derived from the private key. A public key sent along must match the private key;
+// its comment is kept when the key has none.
+func normalizeSSHKey(key *pbmodels.SshKey) (*pbmodels.SshKey, error) {
+	if key == nil || key.GetPrivateKey() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "отсутствует закрытый SSH-ключ")
+	}
+	if len(key.GetPrivateKey()) > MaxSSHKeySize {
+		return nil, status.Errorf(codes.InvalidArgument, "закрытый SSH-ключ больше %d байт", MaxSSHKeySize)
+	}
+
+	info, err := sshkey.Inspect(key.GetPrivateKey(), key.GetPublicKey(), key.GetPassphrase())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный SSH-ключ: %v", err)
+	}
+
+	comment := strings.TrimSpace(key.GetComment())
+	if comment == "" {
+		comment = info.Comment
+	}
+	return &pbmodels.SshKey{
+		PrivateKey:  key.GetPrivateKey(),
+		PublicKey:   info.PublicKey,
+		Comment:     comment,
+		Passphrase:  key.GetPassphrase(),
+		Fingerprint: info.Fingerprint,
+	}, nil
+}
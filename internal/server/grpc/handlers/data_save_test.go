@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/apetsko/gophkeeper/config"
@@ -14,11 +18,27 @@ import (
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/protobuf/proto"
 )
 
+// newSSHKey returns a new ed25519 private key in the OpenSSH format and its public key.
+func newSSHKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(block)), sshPub
+}
+
 func TestServerAdmin_DataSave(t *testing.T) {
 	const userID = 42
 	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	privateKey, publicKey := newSSHKey(t)
 
 	type testCase struct {
 		name       string
@@ -80,6 +100,88 @@ func TestServerAdmin_DataSave(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success secure note",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_TEXT,
+				Meta: &pbmodels.Meta{Title: "Recovery phrase"},
+				Data: &pbrpc.DataSaveRequest_SecureNote{
+					SecureNote: &pbmodels.SecureNote{Text: "abandon ability able"},
+				},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.Type == constants.Text
+				})).Return(1, nil)
+				env.On("EncryptUserData", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "success otp from uri",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_OTP,
+				Data: &pbrpc.DataSaveRequest_Otp{
+					Otp: &pbmodels.Otp{Uri: "otpauth://totp/GitHub:alice?secret=jbswy3dpehpk3pxp&digits=8"},
+				},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.Type == constants.OTP
+				})).Return(1, nil)
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), mock.MatchedBy(func(data []byte) bool {
+					var otp pbmodels.Otp
+					if err := proto.Unmarshal(data, &otp); err != nil {
+						return false
+					}
+					return otp.GetSecret() == "JBSWY3DPEHPK3PXP" && otp.GetDigits() == 8 && otp.GetPeriod() == 30 &&
+						otp.GetIssuer() == "GitHub" && otp.GetAccount() == "alice" && otp.GetUri() == ""
+				})).Return(&models.EncryptedData{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "success ssh key",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_SSH_KEY,
+				Data: &pbrpc.DataSaveRequest_SshKey{SshKey: &pbmodels.SshKey{
+					PrivateKey: privateKey,
+					PublicKey:  strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))) + " deploy@ci",
+				}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.Type == constants.SSHKey
+				})).Return(1, nil)
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), mock.MatchedBy(func(data []byte) bool {
+					var key pbmodels.SshKey
+					if err := proto.Unmarshal(data, &key); err != nil {
+						return false
+					}
+					return key.GetFingerprint() == ssh.FingerprintSHA256(publicKey) && key.GetComment() == "deploy@ci" &&
+						key.GetPrivateKey() == privateKey
+				})).Return(&models.EncryptedData{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}, nil)
+			},
+			wantErr: false,
+		},
 		{
 			name: "success binary data",
 			req: &pbrpc.DataSaveRequest{
@@ -94,7 +196,7 @@ func TestServerAdmin_DataSave(t *testing.T) {
 				},
 			},
 			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
-				env.On("EncryptUserData", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
+				env.On("EncryptFile", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
 					EncryptedData: []byte("enc"),
 					DataNonce:     []byte("nonce"),
 					EncryptedDek:  []byte("dek"),
@@ -211,6 +313,74 @@ func TestServerAdmin_DataSave_ErrorBranches(t *testing.T) {
 			},
 			wantErr: "отсутствуют учетные данные",
 		},
+		{
+			name: "secure note nil",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_TEXT,
+				Data: &pbrpc.DataSaveRequest_SecureNote{SecureNote: nil},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+			},
+			wantErr: "отсутствует текст заметки",
+		},
+		{
+			name: "secure note too large",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_TEXT,
+				Data: &pbrpc.DataSaveRequest_SecureNote{SecureNote: &pbmodels.SecureNote{
+					Text: strings.Repeat("a", MaxSecureNoteSize+1),
+				}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+			},
+			wantErr: "текст заметки больше",
+		},
+		{
+			name: "otp invalid secret",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_OTP,
+				Data: &pbrpc.DataSaveRequest_Otp{Otp: &pbmodels.Otp{Secret: "not base32!"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+			},
+			wantErr: "неверные данные OTP",
+		},
+		{
+			name: "otp invalid uri",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_OTP,
+				Data: &pbrpc.DataSaveRequest_Otp{Otp: &pbmodels.Otp{Uri: "otpauth://hotp/x?secret=JBSWY3DPEHPK3PXP"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+			},
+			wantErr: "неверная ссылка otpauth",
+		},
+		{
+			name: "ssh key invalid",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_SSH_KEY,
+				Data: &pbrpc.DataSaveRequest_SshKey{SshKey: &pbmodels.SshKey{PrivateKey: "not a key"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+			},
+			wantErr: "неверный SSH-ключ",
+		},
+		{
+			name: "ssh key without private key",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_SSH_KEY,
+				Data: &pbrpc.DataSaveRequest_SshKey{SshKey: &pbmodels.SshKey{PublicKey: "ssh-ed25519 AAAA"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+			},
+			wantErr: "отсутствует закрытый SSH-ключ",
+		},
 		{
 			name: "file nil",
 			req: &pbrpc.DataSaveRequest{
@@ -231,7 +401,7 @@ func TestServerAdmin_DataSave_ErrorBranches(t *testing.T) {
 			},
 			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
 				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
-				env.On("EncryptUserData", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("fail"))
+				env.On("EncryptFile", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("fail"))
 			},
 			wantErr: "failed to encrypt binary data",
 		},
@@ -244,7 +414,7 @@ func TestServerAdmin_DataSave_ErrorBranches(t *testing.T) {
 			},
 			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
 				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
-				env.On("EncryptUserData", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
+				env.On("EncryptFile", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
 					EncryptedData: []byte("enc"),
 					DataNonce:     []byte("nonce"),
 					EncryptedDek:  []byte("dek"),
@@ -263,7 +433,7 @@ func TestServerAdmin_DataSave_ErrorBranches(t *testing.T) {
 			},
 			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
 				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
-				env.On("EncryptUserData", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
+				env.On("EncryptFile", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
 					EncryptedData: []byte("enc"),
 					DataNonce:     []byte("nonce"),
 					EncryptedDek:  []byte("dek"),
@@ -271,6 +441,9 @@ func TestServerAdmin_DataSave_ErrorBranches(t *testing.T) {
 				}, nil)
 				s3.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 				st.On("SaveUserData", mock.Anything, mock.Anything).Return(0, errors.New("fail"))
+				s3.On("Delete", mock.Anything, mock.MatchedBy(func(name string) bool {
+					return strings.HasSuffix(name, "-f")
+				})).Return(nil)
 			},
 			wantErr: "fail",
 		},
@@ -306,6 +479,97 @@ func TestServerAdmin_DataSave_ErrorBranches(t *testing.T) {
 			},
 			wantErr: "fail",
 		},
+		{
+			name: "client encrypted",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+				Meta: &pbmodels.Meta{Content: "meta"},
+				Data: &pbrpc.DataSaveRequest_Encrypted{Encrypted: &pbmodels.EncryptedPayload{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetClientKey", mock.Anything, userID).Return(&models.ClientKey{UserID: userID}, nil)
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.ClientEncrypted && string(d.EncryptedData) == "enc"
+				})).Return(1, nil)
+			},
+		},
+		{
+			name: "client encrypted without client key",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+				Meta: &pbmodels.Meta{},
+				Data: &pbrpc.DataSaveRequest_Encrypted{Encrypted: &pbmodels.EncryptedPayload{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetClientKey", mock.Anything, userID).Return(nil, models.ErrClientKeyNotFound)
+			},
+			wantErr: "шифрование на клиенте не включено",
+		},
+		{
+			name: "client encrypted incomplete payload",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+				Meta: &pbmodels.Meta{},
+				Data: &pbrpc.DataSaveRequest_Encrypted{Encrypted: &pbmodels.EncryptedPayload{EncryptedData: []byte("enc")}},
+			},
+			wantErr: "неполные зашифрованные данные",
+		},
+		{
+			name: "client encrypted sensitive field",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_CREDENTIALS,
+				Meta: &pbmodels.Meta{Fields: []*pbmodels.CustomField{{Name: "PIN", Value: "1234", Sensitive: true}}},
+				Data: &pbrpc.DataSaveRequest_Encrypted{Encrypted: &pbmodels.EncryptedPayload{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}},
+			},
+			wantErr: "значения чувствительных полей нельзя хранить открыто",
+		},
+		{
+			name: "invalid meta",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_BANK_CARD,
+				Data: &pbrpc.DataSaveRequest_BankCard{BankCard: &pbmodels.BankCard{CardNumber: "1234"}},
+				Meta: &pbmodels.Meta{Urls: []string{"ftp://example.com"}},
+			},
+			wantErr: "неверная ссылка",
+		},
+		{
+			name: "sensitive field is sealed",
+			req: &pbrpc.DataSaveRequest{
+				Type: pbc.DataType_DATA_TYPE_BANK_CARD,
+				Data: &pbrpc.DataSaveRequest_BankCard{BankCard: &pbmodels.BankCard{CardNumber: "1234"}},
+				Meta: &pbmodels.Meta{Title: "Card", Fields: []*pbmodels.CustomField{{Name: "PIN", Value: "1234", Sensitive: true}}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptUserData", mock.Anything, mock.Anything, mock.Anything).Return(&models.EncryptedData{
+					EncryptedData: []byte("enc"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek_nonce"),
+				}, nil)
+				env.On("SealWithDEK", mock.Anything, mock.MatchedBy(func(d models.DBUserData) bool {
+					return string(d.EncryptedDek) == "dek"
+				}), []byte("mk"), mock.Anything).Return([]byte("sealed"), []byte("sealed_nonce"), nil)
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return string(d.MetaSecrets) == "sealed" && !strings.Contains(d.Meta, "1234")
+				})).Return(1, nil)
+			},
+		},
 	}
 
 	for _, tt := range cases {
@@ -0,0 +1,265 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/jobs"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// DataUpdate handles the gRPC request to edit an existing user data record in place.
+//
+// The record keeps its ID, type and creation time. The new payload is encrypted with a fresh
+// DEK, and for binary data a new object is uploaded to S3 and the record is pointed at it.
+// Payloads already encrypted by a zero-knowledge client are stored as-is. The replaced
+// contents are kept as a prior version of the record. The new object is removed if the update
+// fails, and objects of versions pruned by the update are removed once it is committed.
+// Without a new meta the record keeps its meta, with the sensitive values sealed again under
+// the fresh DEK.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The DataUpdateRequest message with the record ID and the new data.
+//
+// Returns:
+//   - *pbrpc.DataUpdateResponse: A response indicating success.
+//   - error: A gRPC error if the record belongs to another user, the data does not match
+//     the record type, or the update fails.
+func (s *ServerAdmin) DataUpdate(ctx context.Context, in *pbrpc.DataUpdateRequest) (*pbrpc.DataUpdateResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	current, err := s.Storage.GetUserData(ctx, int(in.GetId()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения данных")
+	}
+
+	// Проверка прав доступа
+	if current.UserID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "нет доступа к запрошенным данным")
+	}
+
+	var meta *pbmodels.Meta
+	if in.GetMeta() != nil {
+		if meta, err = normalizeMeta(in.GetMeta()); err != nil {
+			return nil, err
+		}
+	}
+
+	var updated *models.DBUserData
+	if current.ClientEncrypted {
+		updated, err = s.updatedEncryptedPayload(ctx, current, in.GetEncrypted(), meta)
+	} else {
+		updated, err = s.updatedUserData(ctx, current, in, meta)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keep, err := s.versionRetention(ctx, userID)
+	if err != nil {
+		s.discardObject(ctx, updated.MinioObjectID)
+		return nil, err
+	}
+
+	superseded, err := s.Storage.UpdateUserData(ctx, int(in.GetId()), updated, keep)
+	if err != nil {
+		s.discardObject(ctx, updated.MinioObjectID)
+		if errors.Is(err, models.ErrUserDataNotFound) {
+			return nil, status.Errorf(codes.NotFound, "запись не найдена")
+		}
+		slog.Error("failed to update user data: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка обновления данных")
+	}
+	jobs.DeleteObjects(ctx, s.StorageS3, superseded)
+
+	return &pbrpc.DataUpdateResponse{
+		Message: fmt.Sprintf("данные записи %d успешно обновлены", in.GetId()),
+	}, nil
+}
+
+// updatedUserData encrypts the new contents of a server-encrypted record with a fresh DEK.
+// A nil meta keeps the meta of the current contents.
+func (s *ServerAdmin) updatedUserData(
+	ctx context.Context,
+	current *models.DBUserData,
+	in *pbrpc.DataUpdateRequest,
+	meta *pbmodels.Meta,
+) (*models.DBUserData, error) {
+	if in.GetEncrypted() != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на сервере")
+	}
+
+	var msg proto.Message
+	switch current.Type {
+	case constants.BankCard:
+		if in.GetBankCard() == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "отсутствуют данные банковской карты")
+		}
+		msg = in.GetBankCard()
+	case constants.Credentials:
+		if in.GetCredentials() == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "отсутствуют учетные данные")
+		}
+		msg = in.GetCredentials()
+	case constants.Text:
+		if err := validateSecureNote(in.GetSecureNote()); err != nil {
+			return nil, err
+		}
+		msg = in.GetSecureNote()
+	case constants.OTP:
+		otp, err := normalizeOTP(in.GetOtp())
+		if err != nil {
+			return nil, err
+		}
+		msg = otp
+	case constants.SSHKey:
+		key, err := normalizeSSHKey(in.GetSshKey())
+		if err != nil {
+			return nil, err
+		}
+		msg = key
+	case constants.BinaryData:
+		if in.GetBinaryData() == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "отсутствуют данные файла")
+		}
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "неподдерживаемый тип данных: %s", current.Type)
+	}
+
+	// Содержимое файла шифруется как есть, остальные типы сериализуются в protobuf
+	plaintext := in.GetBinaryData().GetData()
+	if msg != nil {
+		serialized, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("serialize error: %v", err)
+		}
+		plaintext = serialized
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, current.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error get encryptedMK: %v", err)
+	}
+
+	if meta == nil {
+		if meta, err = s.openMeta(ctx, current, encryptedMK); err != nil {
+			return nil, err
+		}
+	}
+
+	encrypt := s.Envelope.EncryptUserData
+	if current.Type == constants.BinaryData {
+		encrypt = s.Envelope.EncryptFile
+	}
+	encryptedData, err := encrypt(ctx, encryptedMK, plaintext)
+	if err != nil {
+		slog.Error("failed to crypt data: " + err.Error())
+		return nil, fmt.Errorf("encrypt error: %v", err)
+	}
+
+	updated := &models.DBUserData{
+		UserID:        current.UserID,
+		Type:          current.Type,
+		EncryptedData: encryptedData.EncryptedData,
+		DataNonce:     encryptedData.DataNonce,
+		EncryptedDek:  encryptedData.EncryptedDek,
+		DekNonce:      encryptedData.DekNonce,
+	}
+	if err = s.sealMeta(ctx, updated, encryptedMK, meta); err != nil {
+		return nil, err
+	}
+
+	if current.Type == constants.BinaryData {
+		file := in.GetBinaryData()
+		objectName := fmt.Sprintf("%d-%s", time.Now().UnixNano(), file.Name)
+		s3UploadData := &models.S3UploadData{
+			ObjectName:  objectName,
+			MetaContent: meta.GetContent(),
+			FileName:    file.Name,
+			FileType:    file.Type,
+		}
+		if _, err = s.StorageS3.Upload(ctx, encryptedData.EncryptedData, s3UploadData); err != nil {
+			return nil, fmt.Errorf("failed to upload file to MinIO: %v", err)
+		}
+
+		updated.EncryptedData = nil
+		updated.MinioObjectID = objectName
+	}
+
+	return updated, nil
+}
+
+// updatedEncryptedPayload returns the new contents of a record of a zero-knowledge account,
+// which are stored without decrypting them. A nil meta keeps the meta of the current contents.
+func (s *ServerAdmin) updatedEncryptedPayload(
+	ctx context.Context,
+	current *models.DBUserData,
+	payload *pbmodels.EncryptedPayload,
+	meta *pbmodels.Meta,
+) (*models.DBUserData, error) {
+	if payload == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+	}
+	if len(payload.GetEncryptedDek()) == 0 || len(payload.GetDekNonce()) == 0 || len(payload.GetDataNonce()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "неполные зашифрованные данные")
+	}
+
+	metaJSON := current.Meta
+	if meta != nil {
+		var err error
+		if metaJSON, err = plainMeta(meta); err != nil {
+			return nil, err
+		}
+	} else {
+		meta = &pbmodels.Meta{}
+		if errUnmarshal := protojson.Unmarshal([]byte(current.Meta), meta); errUnmarshal != nil {
+			return nil, status.Errorf(codes.Internal, "ошибка парсинга Meta JSON: %v", errUnmarshal)
+		}
+	}
+
+	updated := &models.DBUserData{
+		UserID:          current.UserID,
+		Type:            current.Type,
+		EncryptedData:   payload.GetEncryptedData(),
+		DataNonce:       payload.GetDataNonce(),
+		EncryptedDek:    payload.GetEncryptedDek(),
+		DekNonce:        payload.GetDekNonce(),
+		Meta:            metaJSON,
+		ClientEncrypted: true,
+	}
+
+	if current.MinioObjectID != "" {
+		// Имя и тип файла зашифрованы вместе с содержимым, поэтому в S3 кладем только шифротекст
+		objectName := fmt.Sprintf("%d-%d", time.Now().UnixNano(), current.UserID)
+		s3UploadData := &models.S3UploadData{
+			ObjectName:  objectName,
+			MetaContent: meta.GetContent(),
+			FileType:    "application/octet-stream",
+		}
+		if _, err := s.StorageS3.Upload(ctx, payload.GetEncryptedData(), s3UploadData); err != nil {
+			return nil, fmt.Errorf("failed to upload file to MinIO: %v", err)
+		}
+
+		updated.EncryptedData = nil
+		updated.MinioObjectID = objectName
+	}
+
+	return updated, nil
+}
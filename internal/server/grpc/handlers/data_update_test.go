@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+func TestServerAdmin_DataUpdate(t *testing.T) {
+	const (
+		userID   = 42
+		recordID = 5
+	)
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	encrypted := &models.EncryptedData{
+		EncryptedData: []byte("enc"),
+		DataNonce:     []byte("nonce"),
+		EncryptedDek:  []byte("new-dek"),
+		DekNonce:      []byte("dek_nonce"),
+	}
+	payload := &pbmodels.EncryptedPayload{
+		EncryptedData: []byte("client-enc"),
+		DataNonce:     []byte("nonce"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("dek_nonce"),
+	}
+
+	tests := []struct {
+		name       string
+		req        *pbrpc.DataUpdateRequest
+		setupMocks func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+		wantErr    string
+	}{
+		{
+			name: "credentials with new meta",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Meta: &pbmodels.Meta{Content: "new meta"},
+				Data: &pbrpc.DataUpdateRequest_Credentials{Credentials: &pbmodels.Credentials{Login: "l", Password: "p2"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.Credentials, Meta: `{"content":"old"}`}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), mock.Anything).Return(encrypted, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.UserID == userID && string(d.EncryptedDek) == "new-dek" &&
+						d.Type == constants.Credentials && strings.Contains(d.Meta, "new meta")
+				}), 10).Return(nil, nil)
+			},
+		},
+		{
+			name: "secure note",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_SecureNote{SecureNote: &pbmodels.SecureNote{Text: "# Runbook"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.Text, Meta: `{"title":"ops"}`}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				note, _ := proto.Marshal(&pbmodels.SecureNote{Text: "# Runbook"})
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), note).Return(encrypted, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.Type == constants.Text && strings.Contains(d.Meta, "ops")
+				}), 10).Return(nil, nil)
+			},
+		},
+		{
+			name: "binary data keeps meta",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_BinaryData{BinaryData: &pbmodels.File{Name: "f.txt", Data: []byte("d")}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{
+					UserID: userID, Type: constants.BinaryData, MinioObjectID: "old-object", Meta: `{"content":"doc"}`,
+				}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptFile", mock.Anything, []byte("mk"), []byte("d")).Return(encrypted, nil)
+				s3.On("Upload", mock.Anything, []byte("enc"), mock.MatchedBy(func(u *models.S3UploadData) bool {
+					return u.MetaContent == "doc" && u.FileName == "f.txt"
+				})).Return(nil, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.MinioObjectID != "old-object" && d.EncryptedData == nil && strings.Contains(d.Meta, "doc")
+				}), 10).Return([]string{"old-object"}, nil)
+				// The object of the version pruned by the update is removed after it
+				s3.On("Delete", mock.Anything, "old-object").Return(nil)
+			},
+		},
+		{
+			name: "sensitive meta is sealed under the new DEK",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_Credentials{Credentials: &pbmodels.Credentials{Login: "l", Password: "p2"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{
+					UserID: userID, Type: constants.Credentials, EncryptedDek: []byte("old-dek"),
+					Meta:        `{"fields":[{"name":"PIN","sensitive":true}]}`,
+					MetaSecrets: []byte("old-secrets"), MetaSecretsNonce: []byte("old-nonce"),
+				}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				secrets, _ := proto.Marshal(&pbmodels.Meta{Fields: []*pbmodels.CustomField{{Name: "PIN", Value: "1234"}}})
+				env.On("OpenWithDEK", mock.Anything, mock.MatchedBy(func(d models.DBUserData) bool {
+					return string(d.EncryptedDek) == "old-dek"
+				}), []byte("mk"), []byte("old-secrets"), []byte("old-nonce")).Return(secrets, nil)
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), mock.Anything).Return(encrypted, nil)
+				env.On("SealWithDEK", mock.Anything, mock.MatchedBy(func(d models.DBUserData) bool {
+					return string(d.EncryptedDek) == "new-dek"
+				}), []byte("mk"), mock.Anything).Return([]byte("new-secrets"), []byte("new-nonce"), nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return string(d.MetaSecrets) == "new-secrets" && !strings.Contains(d.Meta, "1234")
+				}), 10).Return(nil, nil)
+			},
+		},
+		{
+			name: "client encrypted",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_Encrypted{Encrypted: payload},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{
+					UserID: userID, Type: constants.Credentials, Meta: "{}", ClientEncrypted: true,
+				}, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID, VersionRetention: 3}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.ClientEncrypted && string(d.EncryptedData) == "client-enc"
+				}), 3).Return(nil, nil)
+			},
+		},
+		{
+			name: "other user's record",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_Credentials{Credentials: &pbmodels.Credentials{Login: "l"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{UserID: 7, Type: constants.Credentials}, nil)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "type mismatch",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_BankCard{BankCard: &pbmodels.BankCard{CardNumber: "4111"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.Credentials, Meta: "{}"}, nil)
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "plaintext for client-encrypted record",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_Credentials{Credentials: &pbmodels.Credentials{Login: "l"}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{
+					UserID: userID, Type: constants.Credentials, Meta: "{}", ClientEncrypted: true,
+				}, nil)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "record not found",
+			req:  &pbrpc.DataUpdateRequest{Id: recordID},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(nil, errors.New("no rows"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "s3 upload error",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_BinaryData{BinaryData: &pbmodels.File{Name: "f", Data: []byte("d")}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.BinaryData, Meta: "{}"}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptFile", mock.Anything, mock.Anything, mock.Anything).Return(encrypted, nil)
+				s3.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("fail"))
+			},
+			wantErr: "failed to upload file to MinIO",
+		},
+		{
+			name: "update error",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_Encrypted{Encrypted: payload},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{
+					UserID: userID, Type: constants.Credentials, Meta: "{}", ClientEncrypted: true,
+				}, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.Anything, 10).Return(nil, errors.New("db error"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "update error discards uploaded object",
+			req: &pbrpc.DataUpdateRequest{
+				Id:   recordID,
+				Data: &pbrpc.DataUpdateRequest_BinaryData{BinaryData: &pbmodels.File{Name: "f.txt", Data: []byte("d")}},
+			},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.BinaryData, Meta: "{}"}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptFile", mock.Anything, mock.Anything, mock.Anything).Return(encrypted, nil)
+				s3.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+				st.On("UpdateUserData", mock.Anything, recordID, mock.Anything, 10).Return(nil, models.ErrUserDataNotFound)
+				s3.On("Delete", mock.Anything, mock.MatchedBy(func(name string) bool {
+					return strings.HasSuffix(name, "-f.txt")
+				})).Return(nil)
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			s3 := mocks.NewS3Client(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, s3, env, km)
+			srv := &ServerAdmin{Storage: st, StorageS3: s3, Envelope: env, KeyManager: km, VersionRetention: 10}
+
+			resp, err := srv.DataUpdate(ctx, tt.req)
+			switch {
+			case tt.wantCode != codes.OK:
+				assert.Equal(t, tt.wantCode, status.Code(err))
+			case tt.wantErr != "":
+				assert.ErrorContains(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+				assert.NotEmpty(t, resp.GetMessage())
+			}
+		})
+	}
+
+	_, err := (&ServerAdmin{}).DataUpdate(context.Background(), &pbrpc.DataUpdateRequest{Id: recordID})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
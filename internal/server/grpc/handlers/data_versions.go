@@ -0,0 +1,244 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/jobs"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// ListRecordVersions handles the gRPC request listing the prior versions of a user data record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListRecordVersionsRequest message with the record ID.
+//
+// Returns:
+//   - *pbrpc.ListRecordVersionsResponse: The current version number, the retention in effect
+//     and the prior versions, newest first.
+//   - error: A gRPC error if the record belongs to another user or the query fails.
+func (s *ServerAdmin) ListRecordVersions(
+	ctx context.Context,
+	in *pbrpc.ListRecordVersionsRequest,
+) (*pbrpc.ListRecordVersionsResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	current, err := s.ownedUserData(ctx, userID, int(in.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	retention, err := s.versionRetention(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.Storage.ListUserDataVersions(ctx, int(in.GetId()))
+	if err != nil {
+		slog.Error("failed to list user data versions: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка получения версий записи")
+	}
+
+	response := &pbrpc.ListRecordVersionsResponse{
+		CurrentVersion: int32(current.Version),
+		Retention:      int32(retention),
+	}
+	for _, v := range versions {
+		var meta pbmodels.Meta
+		if errUnmarshal := protojson.Unmarshal([]byte(v.Data.Meta), &meta); errUnmarshal != nil {
+			slog.Error("failed to unmarshal meta: " + errUnmarshal.Error())
+			continue
+		}
+
+		response.Versions = append(response.Versions, &pbrpc.RecordVersion{
+			Version:    int32(v.Version),
+			Meta:       &meta,
+			CreatedAt:  v.CreatedAt.Format("02.01.2006 15:04"),
+			ArchivedAt: v.ArchivedAt.Format("02.01.2006 15:04"),
+		})
+	}
+
+	return response, nil
+}
+
+// ViewRecordVersion handles the gRPC request to retrieve the contents of a prior version of a record.
+//
+// A version is decrypted exactly like the current contents in DataView; records of zero-knowledge
+// accounts are returned as an opaque EncryptedPayload.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ViewRecordVersionRequest message with the record ID and the version number.
+//
+// Returns:
+//   - *pbrpc.DataViewResponse: The contents of the version.
+//   - error: A gRPC error if the record belongs to another user, the version does not exist
+//     or decryption fails.
+func (s *ServerAdmin) ViewRecordVersion(ctx context.Context, in *pbrpc.ViewRecordVersionRequest) (*pbrpc.DataViewResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	current, err := s.ownedUserData(ctx, userID, int(in.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.Storage.GetUserDataVersion(ctx, int(in.GetId()), int(in.GetVersion()))
+	if err != nil {
+		if errors.Is(err, models.ErrVersionNotFound) {
+			return nil, status.Errorf(codes.NotFound, "версия не найдена")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения версии записи")
+	}
+
+	userData := version.Data
+	userData.UserID = current.UserID
+	userData.Type = current.Type
+	userData.ClientEncrypted = current.ClientEncrypted
+	userData.Version = version.Version
+
+	return s.viewUserData(ctx, &userData)
+}
+
+// RestoreRecordVersion handles the gRPC request making a prior version the current contents of a record.
+//
+// The contents being replaced are kept as a new version, so a restore can be undone the same way.
+// Objects of versions pruned by the restore are removed once it is committed.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RestoreRecordVersionRequest message with the record ID and the version number.
+//
+// Returns:
+//   - *pbrpc.RestoreRecordVersionResponse: The version number of the restored contents.
+//   - error: A gRPC error if the record belongs to another user, the version does not exist
+//     or the restore fails.
+func (s *ServerAdmin) RestoreRecordVersion(
+	ctx context.Context,
+	in *pbrpc.RestoreRecordVersionRequest,
+) (*pbrpc.RestoreRecordVersionResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	if _, err := s.ownedUserData(ctx, userID, int(in.GetId())); err != nil {
+		return nil, err
+	}
+
+	keep, err := s.versionRetention(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, pruned, err := s.Storage.RestoreUserDataVersion(ctx, int(in.GetId()), userID, int(in.GetVersion()), keep)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrVersionNotFound):
+			return nil, status.Errorf(codes.NotFound, "версия не найдена")
+		case errors.Is(err, models.ErrUserDataNotFound):
+			return nil, status.Errorf(codes.NotFound, "запись не найдена")
+		}
+		slog.Error("failed to restore user data version: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка восстановления версии")
+	}
+	jobs.DeleteObjects(ctx, s.StorageS3, pruned)
+
+	return &pbrpc.RestoreRecordVersionResponse{
+		Message:        fmt.Sprintf("версия %d записи %d восстановлена", in.GetVersion(), in.GetId()),
+		CurrentVersion: int32(current),
+	}, nil
+}
+
+// SetVersionRetention handles the gRPC request setting how many prior versions are kept for
+// every record of the authenticated user.
+//
+// Versions above a lowered limit are removed on the next update of each record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The SetVersionRetentionRequest message; 0 selects the server default.
+//
+// Returns:
+//   - *pbrpc.SetVersionRetentionResponse: The retention in effect.
+//   - error: A gRPC error if the value is out of range or the update fails.
+func (s *ServerAdmin) SetVersionRetention(
+	ctx context.Context,
+	in *pbrpc.SetVersionRetentionRequest,
+) (*pbrpc.SetVersionRetentionResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	retention := int(in.GetRetention())
+	if retention < 0 || retention > config.MaxVersionRetention {
+		return nil, status.Errorf(codes.InvalidArgument, "количество версий должно быть от 0 до %d", config.MaxVersionRetention)
+	}
+
+	if err := s.Storage.SetVersionRetention(ctx, userID, retention); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "пользователь не найден")
+		}
+		slog.Error("failed to set version retention: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка сохранения настройки")
+	}
+
+	if retention == 0 {
+		retention = s.VersionRetention
+	}
+
+	return &pbrpc.SetVersionRetentionResponse{
+		Message:   fmt.Sprintf("для каждой записи хранится версий: %d", retention),
+		Retention: int32(retention),
+	}, nil
+}
+
+// ownedUserData returns a user data record after checking that it belongs to userID.
+func (s *ServerAdmin) ownedUserData(ctx context.Context, userID, userDataID int) (*models.DBUserData, error) {
+	userData, err := s.Storage.GetUserData(ctx, userDataID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения данных")
+	}
+
+	// Проверка прав доступа
+	if userData.UserID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "нет доступа к запрошенным данным")
+	}
+
+	return userData, nil
+}
+
+// versionRetention returns the number of prior versions kept per record of userID.
+func (s *ServerAdmin) versionRetention(ctx context.Context, userID int) (int, error) {
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return 0, status.Errorf(codes.NotFound, "пользователь не найден")
+		}
+		return 0, status.Errorf(codes.Internal, "ошибка получения пользователя")
+	}
+
+	if user.VersionRetention > 0 {
+		return user.VersionRetention, nil
+	}
+	return s.VersionRetention, nil
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+func TestServerAdmin_ListRecordVersions(t *testing.T) {
+	const (
+		userID   = 42
+		recordID = 5
+	)
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	st := mocks.NewIStorage(t)
+	st.On("GetUserData", mock.Anything, recordID).
+		Return(&models.DBUserData{UserID: userID, Type: constants.Credentials, Version: 4}, nil)
+	st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID, VersionRetention: 3}, nil)
+	st.On("ListUserDataVersions", mock.Anything, recordID).Return([]models.UserDataVersion{
+		{Version: 3, Data: models.DBUserData{Meta: `{"content":"v3"}`}, CreatedAt: time.Now(), ArchivedAt: time.Now()},
+		{Version: 2, Data: models.DBUserData{Meta: `invalid`}},
+	}, nil)
+	srv := &ServerAdmin{Storage: st, VersionRetention: 10}
+
+	resp, err := srv.ListRecordVersions(ctx, &pbrpc.ListRecordVersionsRequest{Id: recordID})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), resp.GetCurrentVersion())
+	assert.Equal(t, int32(3), resp.GetRetention())
+	require.Len(t, resp.GetVersions(), 1)
+	assert.Equal(t, int32(3), resp.GetVersions()[0].GetVersion())
+	assert.Equal(t, "v3", resp.GetVersions()[0].GetMeta().GetContent())
+
+	other := mocks.NewIStorage(t)
+	other.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{UserID: 7}, nil)
+	_, err = (&ServerAdmin{Storage: other}).ListRecordVersions(ctx, &pbrpc.ListRecordVersionsRequest{Id: recordID})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestServerAdmin_ViewRecordVersion(t *testing.T) {
+	const (
+		userID   = 42
+		recordID = 5
+	)
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	tests := []struct {
+		name       string
+		setupMocks func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+	}{
+		{
+			name: "server encrypted",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.Credentials, Version: 3}, nil)
+				st.On("GetUserDataVersion", mock.Anything, recordID, 2).Return(&models.UserDataVersion{
+					Version: 2,
+					Data:    models.DBUserData{EncryptedData: []byte("old"), EncryptedDek: []byte("old-dek"), Meta: "{}"},
+				}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptUserData", mock.Anything, mock.MatchedBy(func(d models.DBUserData) bool {
+					return d.UserID == userID && d.Type == constants.Credentials && string(d.EncryptedDek) == "old-dek"
+				}), []byte("mk")).Return([]byte{10, 1, 'l', 18, 1, 'p'}, nil) // serialized Credentials
+			},
+		},
+		{
+			name: "client encrypted",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.Credentials, ClientEncrypted: true}, nil)
+				st.On("GetUserDataVersion", mock.Anything, recordID, 2).Return(&models.UserDataVersion{
+					Version: 2,
+					Data:    models.DBUserData{EncryptedData: []byte("old"), EncryptedDek: []byte("old-dek"), Meta: "{}"},
+				}, nil)
+			},
+		},
+		{
+			name: "version not found",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).
+					Return(&models.DBUserData{UserID: userID, Type: constants.Credentials}, nil)
+				st.On("GetUserDataVersion", mock.Anything, recordID, 2).Return(nil, models.ErrVersionNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "other user's record",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, recordID).Return(&models.DBUserData{UserID: 7}, nil)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, env, km)
+			srv := &ServerAdmin{Storage: st, Envelope: env, KeyManager: km}
+
+			resp, err := srv.ViewRecordVersion(ctx, &pbrpc.ViewRecordVersionRequest{Id: recordID, Version: 2})
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			if resp.GetEncrypted() != nil {
+				assert.Equal(t, []byte("old-dek"), resp.GetEncrypted().GetEncryptedDek())
+			} else {
+				assert.Equal(t, "p", resp.GetCredentials().GetPassword())
+			}
+		})
+	}
+}
+
+func TestServerAdmin_RestoreRecordVersion(t *testing.T) {
+	const (
+		userID   = 42
+		recordID = 5
+	)
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	tests := []struct {
+		name       string
+		restoreErr error
+		wantCode   codes.Code
+	}{
+		{name: "success"},
+		{name: "version not found", restoreErr: models.ErrVersionNotFound, wantCode: codes.NotFound},
+		{name: "db error", restoreErr: errors.New("db error"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			st.On("GetUserData", mock.Anything, recordID).
+				Return(&models.DBUserData{UserID: userID, Type: constants.Credentials, Version: 3}, nil)
+			st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID}, nil)
+			s3 := mocks.NewS3Client(t)
+			if tt.restoreErr == nil {
+				st.On("RestoreUserDataVersion", mock.Anything, recordID, userID, 2, 10).Return(4, []string{"pruned"}, nil)
+				s3.On("Delete", mock.Anything, "pruned").Return(nil)
+			} else {
+				st.On("RestoreUserDataVersion", mock.Anything, recordID, userID, 2, 10).Return(0, nil, tt.restoreErr)
+			}
+			srv := &ServerAdmin{Storage: st, StorageS3: s3, VersionRetention: 10}
+
+			resp, err := srv.RestoreRecordVersion(ctx, &pbrpc.RestoreRecordVersionRequest{Id: recordID, Version: 2})
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int32(4), resp.GetCurrentVersion())
+		})
+	}
+}
+
+func TestServerAdmin_SetVersionRetention(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	st := mocks.NewIStorage(t)
+	st.On("SetVersionRetention", mock.Anything, userID, 0).Return(nil)
+	st.On("SetVersionRetention", mock.Anything, userID, 25).Return(nil)
+	srv := &ServerAdmin{Storage: st, VersionRetention: 10}
+
+	resp, err := srv.SetVersionRetention(ctx, &pbrpc.SetVersionRetentionRequest{Retention: 25})
+	require.NoError(t, err)
+	assert.Equal(t, int32(25), resp.GetRetention())
+
+	resp, err = srv.SetVersionRetention(ctx, &pbrpc.SetVersionRetentionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), resp.GetRetention())
+
+	_, err = srv.SetVersionRetention(ctx, &pbrpc.SetVersionRetentionRequest{Retention: 1000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = srv.SetVersionRetention(context.Background(), &pbrpc.SetVersionRetentionRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
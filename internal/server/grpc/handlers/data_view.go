@@ -12,6 +12,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/apetsko/gophkeeper/internal/constants"
+	gkmodels "github.com/apetsko/gophkeeper/models"
 	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
 	"github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
@@ -21,13 +22,17 @@ var stringToDataType = map[string]pbc.DataType{
 	"bank_card":   pbc.DataType_DATA_TYPE_BANK_CARD,
 	"credentials": pbc.DataType_DATA_TYPE_CREDENTIALS,
 	"binary_data": pbc.DataType_DATA_TYPE_BINARY_DATA,
+	"text":        pbc.DataType_DATA_TYPE_TEXT,
+	"otp":         pbc.DataType_DATA_TYPE_OTP,
+	"ssh_key":     pbc.DataType_DATA_TYPE_SSH_KEY,
 }
 
 // DataView handles the gRPC request to retrieve a specific user data record by its ID.
 //
 // This method checks user authorization, fetches the encrypted data from the database or S3 (for binary files),
-// decrypts the data using the user's master key, parses it according to its type (bank card, credentials, or binary data),
+// decrypts the data using the user's master key, parses it according to its type (bank card, credentials, secure note or binary data),
 // and returns the result in the response.
+// Records encrypted by a zero-knowledge client are returned as an opaque EncryptedPayload.
 //
 // Parameters:
 //   - ctx: The gRPC context.
@@ -52,7 +57,16 @@ func (s *ServerAdmin) DataView(ctx context.Context, in *pbrpc.DataViewRequest) (
 		return nil, status.Errorf(codes.PermissionDenied, "нет доступа к запрошенным данным")
 	}
 
-	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userID)
+	return s.viewUserData(ctx, userData)
+}
+
+// viewUserData decrypts a record of userData.UserID, or of a prior version of it, into a DataViewResponse.
+func (s *ServerAdmin) viewUserData(ctx context.Context, userData *gkmodels.DBUserData) (*pbrpc.DataViewResponse, error) {
+	if userData.ClientEncrypted {
+		return s.viewEncryptedPayload(ctx, userData)
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userData.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("error get encryptedMK: %v", err)
 	}
@@ -79,13 +93,8 @@ func (s *ServerAdmin) DataView(ctx context.Context, in *pbrpc.DataViewRequest) (
 			return nil, status.Errorf(codes.Internal, "ошибка расшифровки файла: %v", err)
 		}
 
-		originalName := fileInfo.UserMetadata["original-name"]
-		if originalName == "" {
-			originalName = userData.MinioObjectID
-		}
-
 		file = models.File{
-			Name: originalName,
+			Name: originalName(fileInfo, userData.MinioObjectID),
 			Data: decryptData,
 			Size: int32(len(decryptData)),
 			Type: fileInfo.ContentType,
@@ -105,15 +114,15 @@ func (s *ServerAdmin) DataView(ctx context.Context, in *pbrpc.DataViewRequest) (
 		}
 	}
 
-	var meta models.Meta
-	if errUnmarshal := protojson.Unmarshal([]byte(userData.Meta), &meta); errUnmarshal != nil {
-		return nil, status.Errorf(codes.Internal, "ошибка парсинга Meta JSON: %v", errUnmarshal)
+	meta, err := s.openMeta(ctx, userData, encryptedMK)
+	if err != nil {
+		return nil, err
 	}
 
 	// 4. Создаем базовый ответ
 	response := &pbrpc.DataViewResponse{
 		Type: dataType,
-		Meta: &meta,
+		Meta: meta,
 	}
 
 	// 5. Парсим данные в зависимости от типа
@@ -123,6 +132,39 @@ func (s *ServerAdmin) DataView(ctx context.Context, in *pbrpc.DataViewRequest) (
 	return response, nil
 }
 
+// viewEncryptedPayload returns a client-encrypted record without decrypting it.
+func (s *ServerAdmin) viewEncryptedPayload(ctx context.Context, userData *gkmodels.DBUserData) (*pbrpc.DataViewResponse, error) {
+	dataType, ok := stringToDataType[userData.Type]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "неподдерживаемый тип данных: %s", userData.Type)
+	}
+
+	encryptedData := userData.EncryptedData
+	if userData.MinioObjectID != "" {
+		fileData, _, errGetObject := s.StorageS3.GetObject(ctx, userData.MinioObjectID)
+		if errGetObject != nil {
+			return nil, status.Errorf(codes.Internal, "ошибка получения файла из хранилища: %v", errGetObject)
+		}
+		encryptedData = fileData
+	}
+
+	var meta models.Meta
+	if errUnmarshal := protojson.Unmarshal([]byte(userData.Meta), &meta); errUnmarshal != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка парсинга Meta JSON: %v", errUnmarshal)
+	}
+
+	return &pbrpc.DataViewResponse{
+		Type: dataType,
+		Meta: &meta,
+		Data: &pbrpc.DataViewResponse_Encrypted{Encrypted: &models.EncryptedPayload{
+			EncryptedData: encryptedData,
+			DataNonce:     userData.DataNonce,
+			EncryptedDek:  userData.EncryptedDek,
+			DekNonce:      userData.DekNonce,
+		}},
+	}, nil
+}
+
 func parseData(r *pbrpc.DataViewResponse, dataType pbc.DataType, decryptData []byte, file *models.File) error {
 	switch dataType {
 	case pbc.DataType_DATA_TYPE_BANK_CARD:
@@ -142,6 +184,27 @@ func parseData(r *pbrpc.DataViewResponse, dataType pbc.DataType, decryptData []b
 	case pbc.DataType_DATA_TYPE_BINARY_DATA:
 		r.Data = &pbrpc.DataViewResponse_BinaryData{BinaryData: file}
 
+	case pbc.DataType_DATA_TYPE_TEXT:
+		var note models.SecureNote
+		if errUnmarshal := proto.Unmarshal(decryptData, &note); errUnmarshal != nil {
+			return status.Errorf(codes.Internal, "ошибка парсинга заметки")
+		}
+		r.Data = &pbrpc.DataViewResponse_SecureNote{SecureNote: &note}
+
+	case pbc.DataType_DATA_TYPE_OTP:
+		var otp models.Otp
+		if errUnmarshal := proto.Unmarshal(decryptData, &otp); errUnmarshal != nil {
+			return status.Errorf(codes.Internal, "ошибка парсинга OTP")
+		}
+		r.Data = &pbrpc.DataViewResponse_Otp{Otp: &otp}
+
+	case pbc.DataType_DATA_TYPE_SSH_KEY:
+		var key models.SshKey
+		if errUnmarshal := proto.Unmarshal(decryptData, &key); errUnmarshal != nil {
+			return status.Errorf(codes.Internal, "ошибка парсинга SSH-ключа")
+		}
+		r.Data = &pbrpc.DataViewResponse_SshKey{SshKey: &key}
+
 	default:
 		return status.Errorf(codes.InvalidArgument, "неподдерживаемый тип данных")
 	}
@@ -63,6 +63,21 @@ func TestServerAdmin_DataView(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success secure note",
+			req:  &pbrpc.DataViewRequest{Id: 10},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 10).Return(&models.DBUserData{
+					UserID: userID,
+					Type:   "text",
+					Meta:   `{"title":"runbook"}`,
+				}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptUserData", mock.Anything, mock.AnythingOfType("models.DBUserData"), []byte("mk")).
+					Return([]byte{10, 5, '#', ' ', 'o', 'p', 's'}, nil) // serialized SecureNote
+			},
+			wantErr: false,
+		},
 		{
 			name: "success binary data",
 			req:  &pbrpc.DataViewRequest{Id: 3},
@@ -148,6 +163,23 @@ func TestServerAdmin_DataView(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "client encrypted",
+			req:  &pbrpc.DataViewRequest{Id: 9},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 9).Return(&models.DBUserData{
+					UserID:          userID,
+					Type:            "credentials",
+					Meta:            `{"content":"meta"}`,
+					EncryptedData:   []byte("enc"),
+					DataNonce:       []byte("nonce"),
+					EncryptedDek:    []byte("dek"),
+					DekNonce:        []byte("dek_nonce"),
+					ClientEncrypted: true,
+				}, nil)
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,368 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// FileChunkSize is the size of the file chunks sent by DownloadFile.
+const FileChunkSize = 256 * 1024
+
+// UploadFile handles the client-streaming request that stores a file of any size.
+//
+// The first message carries the file name, type and meta, the following ones the contents.
+// The chunks are encrypted with a fresh DEK as they arrive and piped into a multipart upload
+// to MinIO, so memory use does not depend on the file size. The record is written once the
+// upload completes; if that fails, the object is removed.
+//
+// Parameters:
+//   - stream: The gRPC stream of UploadFileRequest messages.
+//
+// Returns:
+//   - error: A gRPC error if the header is missing, the account encrypts on the client or
+//     the upload fails.
+func (s *ServerAdmin) UploadFile(stream grpc.ClientStreamingServer[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse]) error {
+	ctx := stream.Context()
+
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "ошибка получения заголовка файла: %v", err)
+	}
+	header := first.GetHeader()
+	if header == nil || header.GetName() == "" {
+		return status.Errorf(codes.InvalidArgument, "первое сообщение должно содержать имя файла")
+	}
+	meta, err := normalizeMeta(header.GetMeta())
+	if err != nil {
+		return err
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrMasterKeyNotFound) {
+			return status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+		}
+		return fmt.Errorf("error get encryptedMK: %v", err)
+	}
+
+	objectName := fmt.Sprintf("%d-%s", time.Now().UnixNano(), header.GetName())
+	size, encryptedData, err := s.uploadEncrypted(ctx, encryptedMK, stream, &models.S3UploadData{
+		ObjectName:  objectName,
+		MetaContent: meta.GetContent(),
+		FileName:    header.GetName(),
+		FileType:    header.GetType(),
+	})
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return err
+		}
+		slog.Error("failed to upload file", "error", err)
+		return status.Errorf(codes.Internal, "ошибка загрузки файла")
+	}
+
+	record := &models.DBUserData{
+		UserID:        userID,
+		Type:          constants.BinaryData,
+		MinioObjectID: objectName,
+		EncryptedDek:  encryptedData.EncryptedDek,
+		DekNonce:      encryptedData.DekNonce,
+	}
+	if err = s.sealMeta(ctx, record, encryptedMK, meta); err != nil {
+		s.discardObject(ctx, objectName)
+		return err
+	}
+	id, err := s.Storage.SaveUserData(ctx, record)
+	if err != nil {
+		s.discardObject(ctx, objectName)
+		slog.Error("failed to save uploaded file: " + err.Error())
+		return status.Errorf(codes.Internal, "ошибка сохранения данных")
+	}
+
+	return stream.SendAndClose(&pbrpc.UploadFileResponse{
+		Id:      int32(id),
+		Size:    size,
+		Message: fmt.Sprintf("файл %s успешно сохранен", header.GetName()),
+	})
+}
+
+// uploadEncrypted encrypts the chunks received from stream into the object described by
+// s3UploadData and returns the plaintext size and the wrapped DEK.
+func (s *ServerAdmin) uploadEncrypted(
+	ctx context.Context,
+	encryptedMK []byte,
+	stream grpc.ClientStreamingServer[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse],
+	s3UploadData *models.S3UploadData,
+) (int64, *models.EncryptedData, error) {
+	pr, pw := io.Pipe()
+
+	encrypter, encryptedData, err := s.Envelope.EncryptStream(ctx, encryptedMK, pw)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	uploaded := make(chan error, 1)
+	go func() {
+		_, errUpload := s.StorageS3.UploadStream(ctx, pr, s3UploadData)
+		// Разблокируем запись в канал, если загрузка прервалась раньше
+		_ = pr.CloseWithError(errUpload)
+		uploaded <- errUpload
+	}()
+
+	size, err := copyChunks(encrypter, stream)
+	if err == nil {
+		err = encrypter.Close()
+	}
+	if err != nil {
+		_ = pw.CloseWithError(err)
+		<-uploaded
+		return 0, nil, err
+	}
+	_ = pw.Close()
+
+	if err = <-uploaded; err != nil {
+		return 0, nil, err
+	}
+
+	return size, encryptedData, nil
+}
+
+// copyChunks writes the chunks received from stream to w until the client closes the stream.
+func copyChunks(w io.Writer, stream grpc.ClientStreamingServer[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse]) (int64, error) {
+	var size int64
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+		if msg.GetHeader() != nil {
+			return size, status.Errorf(codes.InvalidArgument, "заголовок файла передан повторно")
+		}
+
+		n, err := w.Write(msg.GetChunk())
+		size += int64(n)
+		if err != nil {
+			return size, err
+		}
+	}
+}
+
+// DownloadFile handles the server-streaming request that returns a stored file, or a range of
+// it, in chunks.
+//
+// The first message carries the file name, type, size and meta and the range that follows, the
+// following ones the contents. Files are decrypted while they are read from MinIO; for a range,
+// only the chunks of the segmented format that cover it are fetched. Files stored in the
+// single-shot format are decrypted as a whole. Files of zero-knowledge accounts are sent as
+// ciphertext, with the wrapped DEK in the header, and ranges select ciphertext bytes.
+//
+// Parameters:
+//   - in: The DownloadFileRequest message with the record ID and the optional range.
+//   - stream: The gRPC stream the chunks are sent to.
+//
+// Returns:
+//   - error: A gRPC error if the record is not the user's file, the range lies outside of it or
+//     the file cannot be read.
+func (s *ServerAdmin) DownloadFile(in *pbrpc.DownloadFileRequest, stream grpc.ServerStreamingServer[pbrpc.DownloadFileResponse]) error {
+	ctx := stream.Context()
+
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+	if in.GetLength() < 0 {
+		return status.Errorf(codes.InvalidArgument, "длина диапазона не может быть отрицательной")
+	}
+
+	userData, err := s.Storage.GetUserData(ctx, int(in.GetId()))
+	if err != nil {
+		return status.Errorf(codes.Internal, "ошибка получения данных")
+	}
+	if userData.UserID != userID {
+		return status.Errorf(codes.PermissionDenied, "нет доступа к запрошенным данным")
+	}
+	if userData.Type != constants.BinaryData || userData.MinioObjectID == "" {
+		return status.Errorf(codes.FailedPrecondition, "запись не является файлом")
+	}
+
+	var meta pbmodels.Meta
+	if errUnmarshal := protojson.Unmarshal([]byte(userData.Meta), &meta); errUnmarshal != nil {
+		return status.Errorf(codes.Internal, "ошибка парсинга Meta JSON: %v", errUnmarshal)
+	}
+
+	object, objectInfo, err := s.StorageS3.GetObjectStream(ctx, userData.MinioObjectID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "ошибка получения файла из хранилища: %v", err)
+	}
+	defer object.Close()
+
+	header := &pbrpc.DownloadFileHeader{
+		Meta: &meta,
+		Name: originalName(objectInfo, userData.MinioObjectID),
+		Type: objectInfo.ContentType,
+		Size: objectInfo.Size,
+	}
+
+	contents, err := s.fileContents(ctx, userData, object, header, in)
+	if err != nil {
+		return err
+	}
+
+	if err = stream.Send(&pbrpc.DownloadFileResponse{Payload: &pbrpc.DownloadFileResponse_Header{Header: header}}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, FileChunkSize)
+	for {
+		n, errRead := io.ReadFull(contents, buf)
+		if n > 0 {
+			if errSend := stream.Send(&pbrpc.DownloadFileResponse{
+				Payload: &pbrpc.DownloadFileResponse_Chunk{Chunk: buf[:n]},
+			}); errSend != nil {
+				return errSend
+			}
+		}
+		if errors.Is(errRead, io.EOF) || errors.Is(errRead, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if errRead != nil {
+			slog.Error("failed to read file", "id", in.GetId(), "error", errRead)
+			return status.Errorf(codes.Internal, "ошибка расшифровки файла")
+		}
+	}
+}
+
+// fileContents returns a reader of the plaintext of the file object of userData, or of the range
+// requested by in, and sets the plaintext size and the range, or the wrapped DEK for
+// zero-knowledge accounts, in header.
+func (s *ServerAdmin) fileContents(
+	ctx context.Context,
+	userData *models.DBUserData,
+	object io.ReadSeeker,
+	header *pbrpc.DownloadFileHeader,
+	in *pbrpc.DownloadFileRequest,
+) (io.Reader, error) {
+	ranged := in.GetOffset() != 0 || in.GetLength() != 0
+
+	var contents io.ReaderAt
+	if userData.ClientEncrypted {
+		header.Encrypted = &pbmodels.EncryptedPayload{
+			DataNonce:    userData.DataNonce,
+			EncryptedDek: userData.EncryptedDek,
+			DekNonce:     userData.DekNonce,
+		}
+		if !ranged {
+			header.Length = header.GetSize()
+			return object, nil
+		}
+		contents = &sequentialReaderAt{rs: object}
+	} else {
+		encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userData.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("error get encryptedMK: %v", err)
+		}
+
+		if !ranged {
+			plaintext, size, err := s.Envelope.DecryptReader(ctx, *userData, encryptedMK, object, header.GetSize())
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "ошибка расшифровки файла: %v", err)
+			}
+			header.Size, header.Length = size, size
+			return plaintext, nil
+		}
+
+		contents, header.Size, err = s.Envelope.DecryptReaderAt(ctx, *userData, encryptedMK,
+			&sequentialReaderAt{rs: object}, header.GetSize())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "ошибка расшифровки файла: %v", err)
+		}
+	}
+
+	offset, length, err := fileRange(in.GetOffset(), in.GetLength(), header.GetSize())
+	if err != nil {
+		return nil, err
+	}
+	header.Offset, header.Length = offset, length
+
+	return io.NewSectionReader(contents, offset, length), nil
+}
+
+// fileRange resolves a requested range against the file size and returns its offset and length.
+// A negative offset selects the last -offset bytes and a zero length runs to the end.
+func fileRange(offset, length, size int64) (int64, int64, error) {
+	if offset < 0 {
+		offset = max(size+offset, 0)
+	} else if offset > 0 && offset >= size {
+		return 0, 0, status.Errorf(codes.OutOfRange, "смещение %d за пределами файла размером %d байт", offset, size)
+	}
+
+	rest := size - offset
+	if length == 0 || length > rest {
+		length = rest
+	}
+
+	return offset, length, nil
+}
+
+// sequentialReaderAt reads at offsets of an io.ReadSeeker, seeking only when a read does not
+// continue the previous one. Reading a range of a MinIO object in order therefore costs a
+// single request, where every io.ReaderAt call on the object would make its own.
+//
+// Unlike io.ReaderAt implementations in general, it must not be used concurrently.
+type sequentialReaderAt struct {
+	rs  io.ReadSeeker
+	pos int64
+}
+
+// ReadAt reads len(p) bytes starting at offset off.
+func (r *sequentialReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off != r.pos {
+		if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+			r.pos = -1
+			return 0, err
+		}
+		r.pos = off
+	}
+
+	n, err := io.ReadFull(r.rs, p)
+	r.pos += int64(n)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// originalName returns the file name an object was uploaded with, or fallback if it has none.
+// MinIO returns user metadata keys in canonical header form.
+func originalName(info *minio.ObjectInfo, fallback string) string {
+	for _, key := range []string{"Original-Name", "original-name"} {
+		if name := info.UserMetadata[key]; name != "" {
+			return name
+		}
+	}
+	return fallback
+}
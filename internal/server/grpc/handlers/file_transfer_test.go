@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// fakeUploadStream replays a fixed list of upload messages.
+type fakeUploadStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []*pbrpc.UploadFileRequest
+	resp *pbrpc.UploadFileResponse
+}
+
+func (s *fakeUploadStream) Context() context.Context { return s.ctx }
+
+func (s *fakeUploadStream) Recv() (*pbrpc.UploadFileRequest, error) {
+	if len(s.msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return msg, nil
+}
+
+func (s *fakeUploadStream) SendAndClose(resp *pbrpc.UploadFileResponse) error {
+	s.resp = resp
+	return nil
+}
+
+// fakeDownloadStream collects the sent download messages.
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header *pbrpc.DownloadFileHeader
+	data   bytes.Buffer
+	chunks int
+}
+
+func (s *fakeDownloadStream) Context() context.Context { return s.ctx }
+
+func (s *fakeDownloadStream) Send(resp *pbrpc.DownloadFileResponse) error {
+	if h := resp.GetHeader(); h != nil {
+		s.header = h
+		return nil
+	}
+	s.chunks++
+	s.data.Write(resp.GetChunk())
+	return nil
+}
+
+// seekNopCloser is an object opened from memory.
+type seekNopCloser struct {
+	*bytes.Reader
+}
+
+func (seekNopCloser) Close() error { return nil }
+
+func objectReader(data []byte) io.ReadSeekCloser {
+	return seekNopCloser{bytes.NewReader(data)}
+}
+
+// passthroughWriter stands in for an encrypting writer in tests.
+type passthroughWriter struct {
+	io.Writer
+}
+
+func (passthroughWriter) Close() error { return nil }
+
+func uploadMessages(name string, chunks ...string) []*pbrpc.UploadFileRequest {
+	msgs := []*pbrpc.UploadFileRequest{{
+		Payload: &pbrpc.UploadFileRequest_Header{Header: &pbrpc.UploadFileHeader{
+			Name: name,
+			Type: "text/plain",
+			Meta: &pbmodels.Meta{Content: "meta"},
+		}},
+	}}
+	for _, c := range chunks {
+		msgs = append(msgs, &pbrpc.UploadFileRequest{Payload: &pbrpc.UploadFileRequest_Chunk{Chunk: []byte(c)}})
+	}
+	return msgs
+}
+
+func TestServerAdmin_UploadFile(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	tests := []struct {
+		name       string
+		msgs       []*pbrpc.UploadFileRequest
+		setupMocks func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface, uploaded *bytes.Buffer)
+		wantCode   codes.Code
+	}{
+		{
+			name: "success",
+			msgs: uploadMessages("big.bin", "hello ", "large ", "file"),
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface, uploaded *bytes.Buffer) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptStream", mock.Anything, []byte("mk"), mock.Anything).Return(
+					func(_ context.Context, _ []byte, dst io.Writer) io.WriteCloser { return passthroughWriter{dst} },
+					&models.EncryptedData{EncryptedDek: []byte("dek"), DekNonce: []byte("nonce")},
+					nil,
+				)
+				s3.On("UploadStream", mock.Anything, mock.Anything, mock.MatchedBy(func(d *models.S3UploadData) bool {
+					return d.FileName == "big.bin" && d.FileType == "text/plain" && d.MetaContent == "meta"
+				})).Return(func(_ context.Context, r io.Reader, _ *models.S3UploadData) (*minio.UploadInfo, error) {
+					_, err := io.Copy(uploaded, r)
+					return &minio.UploadInfo{}, err
+				})
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.Type == constants.BinaryData && len(d.DataNonce) == 0 && string(d.EncryptedDek) == "dek"
+				})).Return(7, nil)
+			},
+		},
+		{
+			name:     "missing header",
+			msgs:     uploadMessages("big.bin", "data")[1:],
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "zero-knowledge account",
+			msgs: uploadMessages("big.bin", "data"),
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface, uploaded *bytes.Buffer) {
+				km.On("GetMasterKey", mock.Anything, userID).Return(nil, models.ErrMasterKeyNotFound)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "upload error",
+			msgs: uploadMessages("big.bin", "data"),
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface, uploaded *bytes.Buffer) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptStream", mock.Anything, []byte("mk"), mock.Anything).Return(
+					func(_ context.Context, _ []byte, dst io.Writer) io.WriteCloser { return passthroughWriter{dst} },
+					&models.EncryptedData{},
+					nil,
+				)
+				s3.On("UploadStream", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("s3 down"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "save error discards uploaded object",
+			msgs: uploadMessages("big.bin", "data"),
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface, uploaded *bytes.Buffer) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptStream", mock.Anything, []byte("mk"), mock.Anything).Return(
+					func(_ context.Context, _ []byte, dst io.Writer) io.WriteCloser { return passthroughWriter{dst} },
+					&models.EncryptedData{},
+					nil,
+				)
+				s3.On("UploadStream", mock.Anything, mock.Anything, mock.Anything).
+					Return(func(_ context.Context, r io.Reader, _ *models.S3UploadData) (*minio.UploadInfo, error) {
+						_, err := io.Copy(io.Discard, r)
+						return &minio.UploadInfo{}, err
+					})
+				st.On("SaveUserData", mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+				s3.On("Delete", mock.Anything, mock.MatchedBy(func(name string) bool {
+					return strings.HasSuffix(name, "-big.bin")
+				})).Return(nil)
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			s3 := mocks.NewS3Client(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			var uploaded bytes.Buffer
+			if tt.setupMocks != nil {
+				tt.setupMocks(st, s3, env, km, &uploaded)
+			}
+			s := NewServerAdmin(st, s3, config.JWTConfig{}, env, km)
+
+			stream := &fakeUploadStream{ctx: ctx, msgs: tt.msgs}
+			err := s.UploadFile(stream)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int32(7), stream.resp.GetId())
+			require.Equal(t, int64(len("hello large file")), stream.resp.GetSize())
+			require.Equal(t, "hello large file", uploaded.String())
+		})
+	}
+}
+
+func TestServerAdmin_DownloadFile(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	key := bytes.Repeat([]byte{1}, 32)
+	plaintext := bytes.Repeat([]byte("0123456789"), FileChunkSize/4)
+
+	var encrypted bytes.Buffer
+	w, err := crypto.NewStreamWriter(&encrypted, key)
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	decryptReader := func(_ context.Context, _ models.DBUserData, _ []byte, src io.Reader, size int64) (io.Reader, int64, error) {
+		r, errStream := crypto.NewStreamReader(src, key)
+		if errStream != nil {
+			return nil, 0, errStream
+		}
+		return r, r.PlaintextSize(size), nil
+	}
+	decryptReaderAt := func(_ context.Context, _ models.DBUserData, _ []byte, src io.ReaderAt, size int64) (io.ReaderAt, int64, error) {
+		r, errStream := crypto.NewStreamReaderAt(src, size, key)
+		if errStream != nil {
+			return nil, 0, errStream
+		}
+		return r, r.Size(), nil
+	}
+	objectInfo := func() *minio.ObjectInfo {
+		return &minio.ObjectInfo{
+			UserMetadata: map[string]string{"Original-Name": "big.bin"},
+			ContentType:  "application/octet-stream",
+			Size:         int64(encrypted.Len()),
+		}
+	}
+	file := func(clientEncrypted bool, dataNonce []byte) *models.DBUserData {
+		return &models.DBUserData{
+			UserID:          userID,
+			Type:            constants.BinaryData,
+			MinioObjectID:   "obj",
+			Meta:            `{"content":"meta"}`,
+			DataNonce:       dataNonce,
+			ClientEncrypted: clientEncrypted,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        *pbrpc.DownloadFileRequest
+		setupMocks func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface)
+		want       []byte
+		wantSize   int64
+		wantOffset int64
+		wantCode   codes.Code
+	}{
+		{
+			name: "stream format",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(false, nil), nil)
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader(encrypted.Bytes()), objectInfo(), nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptReader", mock.Anything, mock.Anything, []byte("mk"), mock.Anything, int64(encrypted.Len())).
+					Return(decryptReader)
+			},
+			want:     plaintext,
+			wantSize: int64(len(plaintext)),
+		},
+		{
+			name: "range across chunks",
+			req:  &pbrpc.DownloadFileRequest{Id: 1, Offset: crypto.StreamChunkSize - 3, Length: FileChunkSize + 10},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(false, nil), nil)
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader(encrypted.Bytes()), objectInfo(), nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptReaderAt", mock.Anything, mock.Anything, []byte("mk"), mock.Anything, int64(encrypted.Len())).
+					Return(decryptReaderAt)
+			},
+			want:       plaintext[crypto.StreamChunkSize-3 : crypto.StreamChunkSize-3+FileChunkSize+10],
+			wantSize:   int64(len(plaintext)),
+			wantOffset: crypto.StreamChunkSize - 3,
+		},
+		{
+			name: "suffix range",
+			req:  &pbrpc.DownloadFileRequest{Id: 1, Offset: -5},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(false, nil), nil)
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader(encrypted.Bytes()), objectInfo(), nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptReaderAt", mock.Anything, mock.Anything, []byte("mk"), mock.Anything, int64(encrypted.Len())).
+					Return(decryptReaderAt)
+			},
+			want:       plaintext[len(plaintext)-5:],
+			wantSize:   int64(len(plaintext)),
+			wantOffset: int64(len(plaintext) - 5),
+		},
+		{
+			name: "range past the end",
+			req:  &pbrpc.DownloadFileRequest{Id: 1, Offset: int64(len(plaintext))},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(false, nil), nil)
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader(encrypted.Bytes()), objectInfo(), nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptReaderAt", mock.Anything, mock.Anything, []byte("mk"), mock.Anything, int64(encrypted.Len())).
+					Return(decryptReaderAt)
+			},
+			wantCode: codes.OutOfRange,
+		},
+		{
+			name: "decrypt error",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(false, nil), nil)
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader(encrypted.Bytes()), objectInfo(), nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptReader", mock.Anything, mock.Anything, []byte("mk"), mock.Anything, mock.Anything).
+					Return(nil, int64(0), errors.New("failed to decrypt DEK"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "zero-knowledge account gets ciphertext",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(true, []byte("nonce")), nil)
+				info := objectInfo()
+				info.Size = int64(len("client ciphertext"))
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader([]byte("client ciphertext")), info, nil)
+			},
+			want:     []byte("client ciphertext"),
+			wantSize: int64(len("client ciphertext")),
+		},
+		{
+			name: "zero-knowledge range selects ciphertext",
+			req:  &pbrpc.DownloadFileRequest{Id: 1, Offset: 7, Length: 6},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(true, []byte("nonce")), nil)
+				info := objectInfo()
+				info.Size = int64(len("client ciphertext"))
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader([]byte("client ciphertext")), info, nil)
+			},
+			want:       []byte("cipher"),
+			wantSize:   int64(len("client ciphertext")),
+			wantOffset: 7,
+		},
+		{
+			name: "other user's record",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(&models.DBUserData{UserID: 7}, nil)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "not a file",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(&models.DBUserData{UserID: userID, Type: constants.Credentials}, nil)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "corrupted stream",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(file(false, nil), nil)
+				truncated := encrypted.Bytes()[:encrypted.Len()-1]
+				s3.On("GetObjectStream", mock.Anything, "obj").
+					Return(objectReader(truncated), objectInfo(), nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptReader", mock.Anything, mock.Anything, []byte("mk"), mock.Anything, int64(encrypted.Len())).
+					Return(decryptReader)
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			s3 := mocks.NewS3Client(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, s3, env, km)
+			s := NewServerAdmin(st, s3, config.JWTConfig{}, env, km)
+
+			req := tt.req
+			if req == nil {
+				req = &pbrpc.DownloadFileRequest{Id: 1}
+			}
+			stream := &fakeDownloadStream{ctx: ctx}
+			err := s.DownloadFile(req, stream)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "big.bin", stream.header.GetName())
+			require.Equal(t, "meta", stream.header.GetMeta().GetContent())
+			require.Equal(t, tt.wantSize, stream.header.GetSize())
+			require.Equal(t, tt.wantOffset, stream.header.GetOffset())
+			require.Equal(t, int64(len(tt.want)), stream.header.GetLength())
+			require.Equal(t, tt.want, stream.data.Bytes())
+			require.Equal(t, (len(tt.want)+FileChunkSize-1)/FileChunkSize, stream.chunks)
+		})
+	}
+}
+
+// countingSeeker counts the seeks of an object.
+type countingSeeker struct {
+	io.ReadSeeker
+	seeks int
+}
+
+func (s *countingSeeker) Seek(offset int64, whence int) (int64, error) {
+	s.seeks++
+	return s.ReadSeeker.Seek(offset, whence)
+}
+
+func TestSequentialReaderAt(t *testing.T) {
+	data := []byte("0123456789")
+	object := &countingSeeker{ReadSeeker: bytes.NewReader(data)}
+	r := &sequentialReaderAt{rs: object}
+
+	buf := make([]byte, 3)
+	for off := int64(2); off < 8; off += 3 {
+		n, err := r.ReadAt(buf, off)
+		require.NoError(t, err)
+		require.Equal(t, data[off:off+3], buf[:n])
+	}
+	require.Equal(t, 1, object.seeks, "reads in order continue without seeking")
+
+	n, err := r.ReadAt(buf, 0)
+	require.NoError(t, err)
+	require.Equal(t, data[:3], buf[:n])
+	require.Equal(t, 2, object.seeks)
+
+	n, err = r.ReadAt(buf, 8)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, data[8:], buf[:n])
+}
@@ -0,0 +1,349 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// MaxFolderNameLength is the maximum length of a folder name in characters.
+const MaxFolderNameLength = 200
+
+// CreateFolder handles the gRPC request to create a folder.
+//
+// The server encrypts the name with a DEK of its own, like a record payload; a name encrypted
+// by a zero-knowledge client is stored as-is.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The CreateFolderRequest message with the parent folder and the name.
+//
+// Returns:
+//   - *pbrpc.CreateFolderResponse: The new folder.
+//   - error: A gRPC error if the name is invalid, the parent is not found or storage fails.
+func (s *ServerAdmin) CreateFolder(ctx context.Context, in *pbrpc.CreateFolderRequest) (*pbrpc.CreateFolderResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+	if in.GetParentId() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID родительской папки")
+	}
+
+	folder, name, err := s.sealFolderName(ctx, userID, in.GetName(), in.GetEncrypted())
+	if err != nil {
+		return nil, err
+	}
+	folder.ParentID = int(in.GetParentId())
+
+	id, err := s.Storage.CreateFolder(ctx, folder)
+	if err != nil {
+		if errors.Is(err, models.ErrFolderNotFound) {
+			return nil, status.Errorf(codes.NotFound, "родительская папка не найдена")
+		}
+		slog.Error("failed to create folder: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка создания папки")
+	}
+
+	resp := &pbrpc.Folder{
+		Id:       int32(id),
+		ParentId: in.GetParentId(),
+		Name:     name,
+	}
+	if folder.ClientEncrypted {
+		resp.Encrypted = in.GetEncrypted()
+	}
+	return &pbrpc.CreateFolderResponse{Folder: resp}, nil
+}
+
+// ListFolders handles the gRPC request listing the folders of the authenticated user.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListFoldersRequest message.
+//
+// Returns:
+//   - *pbrpc.ListFoldersResponse: The folders, oldest first, with their names decrypted by the
+//     server or encrypted by the client.
+//   - error: A gRPC error if the query or decryption fails.
+func (s *ServerAdmin) ListFolders(ctx context.Context, _ *pbrpc.ListFoldersRequest) (*pbrpc.ListFoldersResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	folders, err := s.Storage.ListFolders(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения папок: %v", err)
+	}
+
+	// Мастер-ключ нужен только для папок, зашифрованных сервером
+	var encryptedMK []byte
+	resp := &pbrpc.ListFoldersResponse{}
+	for _, f := range folders {
+		folder := &pbrpc.Folder{
+			Id:        int32(f.ID),
+			ParentId:  int32(f.ParentID),
+			CreatedAt: f.CreatedAt.Format("02.01.2006 15:04"),
+			UpdatedAt: f.UpdatedAt.Format("02.01.2006 15:04"),
+		}
+
+		if f.ClientEncrypted {
+			folder.Encrypted = &pbmodels.EncryptedPayload{
+				EncryptedData: f.EncryptedName,
+				DataNonce:     f.NameNonce,
+				EncryptedDek:  f.EncryptedDek,
+				DekNonce:      f.DekNonce,
+			}
+		} else {
+			if encryptedMK == nil {
+				encryptedMK, err = s.KeyManager.GetMasterKey(ctx, userID)
+				if err != nil {
+					return nil, fmt.Errorf("error get encryptedMK: %v", err)
+				}
+			}
+			name, errDecrypt := s.Envelope.DecryptUserData(ctx, models.DBUserData{
+				EncryptedData: f.EncryptedName,
+				DataNonce:     f.NameNonce,
+				EncryptedDek:  f.EncryptedDek,
+				DekNonce:      f.DekNonce,
+			}, encryptedMK)
+			if errDecrypt != nil {
+				slog.Error("failed to decrypt folder name", "folder", f.ID, "error", errDecrypt)
+				return nil, status.Errorf(codes.Internal, "ошибка расшифровки данных")
+			}
+			folder.Name = string(name)
+		}
+
+		resp.Folders = append(resp.Folders, folder)
+	}
+
+	return resp, nil
+}
+
+// RenameFolder handles the gRPC request to rename a folder.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RenameFolderRequest message with the folder ID and the new name.
+//
+// Returns:
+//   - *pbrpc.RenameFolderResponse: A response indicating success.
+//   - error: A gRPC error if the name is invalid, the folder is not found or storage fails.
+func (s *ServerAdmin) RenameFolder(ctx context.Context, in *pbrpc.RenameFolderRequest) (*pbrpc.RenameFolderResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	folder, _, err := s.sealFolderName(ctx, userID, in.GetName(), in.GetEncrypted())
+	if err != nil {
+		return nil, err
+	}
+	folder.ID = int(in.GetId())
+
+	if err = s.Storage.RenameFolder(ctx, folder); err != nil {
+		return nil, folderError("failed to rename folder", err)
+	}
+
+	return &pbrpc.RenameFolderResponse{
+		Message: fmt.Sprintf("папка %d переименована", in.GetId()),
+	}, nil
+}
+
+// MoveFolder handles the gRPC request to move a folder, with its records and subfolders,
+// into another folder or to the root.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The MoveFolderRequest message with the folder ID and the new parent.
+//
+// Returns:
+//   - *pbrpc.MoveFolderResponse: A response indicating success.
+//   - error: A gRPC error if a folder is not found, the move would nest the folder in itself
+//     or storage fails.
+func (s *ServerAdmin) MoveFolder(ctx context.Context, in *pbrpc.MoveFolderRequest) (*pbrpc.MoveFolderResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+	if in.GetParentId() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID родительской папки")
+	}
+
+	if err := s.Storage.MoveFolder(ctx, int(in.GetId()), userID, int(in.GetParentId())); err != nil {
+		return nil, folderError("failed to move folder", err)
+	}
+
+	return &pbrpc.MoveFolderResponse{
+		Message: fmt.Sprintf("папка %d перемещена", in.GetId()),
+	}, nil
+}
+
+// DeleteFolder handles the gRPC request to delete a folder. Its records and subfolders are
+// not deleted but moved to the folder that contained it.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The DeleteFolderRequest message with the folder ID.
+//
+// Returns:
+//   - *pbrpc.DeleteFolderResponse: A response indicating success.
+//   - error: A gRPC error if the folder is not found or storage fails.
+func (s *ServerAdmin) DeleteFolder(ctx context.Context, in *pbrpc.DeleteFolderRequest) (*pbrpc.DeleteFolderResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	if err := s.Storage.DeleteFolder(ctx, int(in.GetId()), userID); err != nil {
+		return nil, folderError("failed to delete folder", err)
+	}
+
+	return &pbrpc.DeleteFolderResponse{
+		Message: fmt.Sprintf("папка %d удалена", in.GetId()),
+	}, nil
+}
+
+// MoveRecords handles the gRPC request to move records into a folder or out of any folder.
+// Either all of the records are moved or none.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The MoveRecordsRequest message with the record IDs and the target folder.
+//
+// Returns:
+//   - *pbrpc.MoveRecordsResponse: A response indicating success.
+//   - error: A gRPC error if the folder or a record is not found or storage fails.
+func (s *ServerAdmin) MoveRecords(ctx context.Context, in *pbrpc.MoveRecordsRequest) (*pbrpc.MoveRecordsResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+	if len(in.GetIds()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "не указаны записи")
+	}
+	if in.GetFolderId() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный ID папки")
+	}
+
+	ids := make([]int, 0, len(in.GetIds()))
+	for _, id := range in.GetIds() {
+		ids = append(ids, int(id))
+	}
+
+	if err := s.Storage.MoveUserData(ctx, userID, ids, int(in.GetFolderId())); err != nil {
+		if errors.Is(err, models.ErrUserDataNotFound) {
+			return nil, status.Errorf(codes.NotFound, "запись не найдена")
+		}
+		return nil, folderError("failed to move user data", err)
+	}
+
+	if in.GetFolderId() == 0 {
+		return &pbrpc.MoveRecordsResponse{
+			Message: fmt.Sprintf("записи вынесены из папок: %d", len(ids)),
+		}, nil
+	}
+	return &pbrpc.MoveRecordsResponse{
+		Message: fmt.Sprintf("записи перемещены в папку %d: %d", in.GetFolderId(), len(ids)),
+	}, nil
+}
+
+// sealFolderName returns a folder of the user with its name encrypted.
+//
+// A name encrypted by a zero-knowledge client is taken as-is, provided the account uses
+// client-side encryption; a plain name is validated and encrypted with the master key of the user.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - userID: The owner of the folder.
+//   - name: The plain name, used when payload is nil.
+//   - payload: The name encrypted by the client.
+//
+// Returns:
+//   - *models.Folder: The folder with UserID, the encrypted name and the wrapped DEK set.
+//   - string: The trimmed plain name, empty for a client-encrypted one.
+//   - error: A gRPC error if the name is invalid or cannot be encrypted.
+func (s *ServerAdmin) sealFolderName(
+	ctx context.Context,
+	userID int,
+	name string,
+	payload *pbmodels.EncryptedPayload,
+) (*models.Folder, string, error) {
+	if payload != nil {
+		if len(payload.GetEncryptedData()) == 0 || len(payload.GetDataNonce()) == 0 ||
+			len(payload.GetEncryptedDek()) == 0 || len(payload.GetDekNonce()) == 0 {
+			return nil, "", status.Errorf(codes.InvalidArgument, "неполные зашифрованные данные")
+		}
+		if _, err := s.Storage.GetClientKey(ctx, userID); err != nil {
+			if errors.Is(err, models.ErrClientKeyNotFound) {
+				return nil, "", status.Errorf(codes.FailedPrecondition, "шифрование на клиенте не включено для этого аккаунта")
+			}
+			return nil, "", status.Errorf(codes.Internal, "ошибка получения ключа клиента")
+		}
+		return &models.Folder{
+			UserID:          userID,
+			EncryptedName:   payload.GetEncryptedData(),
+			NameNonce:       payload.GetDataNonce(),
+			EncryptedDek:    payload.GetEncryptedDek(),
+			DekNonce:        payload.GetDekNonce(),
+			ClientEncrypted: true,
+		}, "", nil
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", status.Errorf(codes.InvalidArgument, "не указано имя папки")
+	}
+	if utf8.RuneCountInString(name) > MaxFolderNameLength {
+		return nil, "", status.Errorf(codes.InvalidArgument, "имя папки длиннее %d символов", MaxFolderNameLength)
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrMasterKeyNotFound) {
+			return nil, "", status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+		}
+		return nil, "", fmt.Errorf("error get encryptedMK: %v", err)
+	}
+
+	encrypted, err := s.Envelope.EncryptUserData(ctx, encryptedMK, []byte(name))
+	if err != nil {
+		slog.Error("failed to encrypt folder name: " + err.Error())
+		return nil, "", status.Errorf(codes.Internal, "ошибка шифрования данных")
+	}
+
+	return &models.Folder{
+		UserID:        userID,
+		EncryptedName: encrypted.EncryptedData,
+		NameNonce:     encrypted.DataNonce,
+		EncryptedDek:  encrypted.EncryptedDek,
+		DekNonce:      encrypted.DekNonce,
+	}, name, nil
+}
+
+// folderError maps a storage error of a folder operation to a gRPC error.
+func folderError(action string, err error) error {
+	switch {
+	case errors.Is(err, models.ErrFolderNotFound):
+		return status.Errorf(codes.NotFound, "папка не найдена")
+	case errors.Is(err, models.ErrFolderCycle):
+		return status.Errorf(codes.InvalidArgument, "папку нельзя переместить в нее саму или в ее подпапку")
+	default:
+		slog.Error(action + ": " + err.Error())
+		return status.Errorf(codes.Internal, "ошибка изменения папок")
+	}
+}
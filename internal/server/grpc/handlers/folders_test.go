@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+func TestServerAdmin_CreateFolder(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	payload := &pbmodels.EncryptedPayload{
+		EncryptedData: []byte("name"),
+		DataNonce:     []byte("nonce"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("dek-nonce"),
+	}
+	sealed := &models.EncryptedData{
+		EncryptedData: []byte("enc"),
+		DataNonce:     []byte("n"),
+		EncryptedDek:  []byte("d"),
+		DekNonce:      []byte("dn"),
+	}
+
+	tests := []struct {
+		name     string
+		req      *pbrpc.CreateFolderRequest
+		setup    func(st *mocks.IStorage, km *mocks.KeyManagerInterface, env *mocks.IEnvelope)
+		wantCode codes.Code
+		wantName string
+	}{
+		{
+			name: "server encrypts the name",
+			req:  &pbrpc.CreateFolderRequest{ParentId: 3, NameData: &pbrpc.CreateFolderRequest_Name{Name: "  Work "}},
+			setup: func(st *mocks.IStorage, km *mocks.KeyManagerInterface, env *mocks.IEnvelope) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), []byte("Work")).Return(sealed, nil)
+				st.On("CreateFolder", mock.Anything, &models.Folder{
+					UserID:        userID,
+					ParentID:      3,
+					EncryptedName: []byte("enc"),
+					NameNonce:     []byte("n"),
+					EncryptedDek:  []byte("d"),
+					DekNonce:      []byte("dn"),
+				}).Return(9, nil)
+			},
+			wantName: "Work",
+		},
+		{
+			name: "client-encrypted name",
+			req:  &pbrpc.CreateFolderRequest{NameData: &pbrpc.CreateFolderRequest_Encrypted{Encrypted: payload}},
+			setup: func(st *mocks.IStorage, _ *mocks.KeyManagerInterface, _ *mocks.IEnvelope) {
+				st.On("GetClientKey", mock.Anything, userID).Return(&models.ClientKey{UserID: userID}, nil)
+				st.On("CreateFolder", mock.Anything, mock.MatchedBy(func(f *models.Folder) bool {
+					return f.ClientEncrypted && string(f.EncryptedName) == "name" && f.ParentID == 0
+				})).Return(9, nil)
+			},
+		},
+		{
+			name: "client encryption not enabled",
+			req:  &pbrpc.CreateFolderRequest{NameData: &pbrpc.CreateFolderRequest_Encrypted{Encrypted: payload}},
+			setup: func(st *mocks.IStorage, _ *mocks.KeyManagerInterface, _ *mocks.IEnvelope) {
+				st.On("GetClientKey", mock.Anything, userID).Return(nil, models.ErrClientKeyNotFound)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "incomplete encrypted name",
+			req: &pbrpc.CreateFolderRequest{NameData: &pbrpc.CreateFolderRequest_Encrypted{
+				Encrypted: &pbmodels.EncryptedPayload{EncryptedData: []byte("name")},
+			}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "plain name for a zero-knowledge account",
+			req:      &pbrpc.CreateFolderRequest{NameData: &pbrpc.CreateFolderRequest_Name{Name: "Work"}},
+			wantCode: codes.FailedPrecondition,
+			setup: func(_ *mocks.IStorage, km *mocks.KeyManagerInterface, _ *mocks.IEnvelope) {
+				km.On("GetMasterKey", mock.Anything, userID).Return(nil, models.ErrMasterKeyNotFound)
+			},
+		},
+		{
+			name:     "empty name",
+			req:      &pbrpc.CreateFolderRequest{NameData: &pbrpc.CreateFolderRequest_Name{Name: " "}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "negative parent",
+			req:      &pbrpc.CreateFolderRequest{ParentId: -1, NameData: &pbrpc.CreateFolderRequest_Name{Name: "Work"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "parent of another user",
+			req:  &pbrpc.CreateFolderRequest{ParentId: 3, NameData: &pbrpc.CreateFolderRequest_Name{Name: "Work"}},
+			setup: func(st *mocks.IStorage, km *mocks.KeyManagerInterface, env *mocks.IEnvelope) {
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("EncryptUserData", mock.Anything, []byte("mk"), []byte("Work")).Return(sealed, nil)
+				st.On("CreateFolder", mock.Anything, mock.Anything).Return(0, models.ErrFolderNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			km := mocks.NewKeyManagerInterface(t)
+			env := mocks.NewIEnvelope(t)
+			if tt.setup != nil {
+				tt.setup(st, km, env)
+			}
+			srv := &ServerAdmin{Storage: st, KeyManager: km, Envelope: env}
+
+			resp, err := srv.CreateFolder(ctx, tt.req)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int32(9), resp.GetFolder().GetId())
+			assert.Equal(t, tt.wantName, resp.GetFolder().GetName())
+			assert.Equal(t, tt.req.GetEncrypted(), resp.GetFolder().GetEncrypted())
+		})
+	}
+}
+
+func TestServerAdmin_ListFolders(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	created := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	st := mocks.NewIStorage(t)
+	st.On("ListFolders", mock.Anything, userID).Return([]models.Folder{
+		{ID: 1, UserID: userID, EncryptedName: []byte("enc"), CreatedAt: created, UpdatedAt: created},
+		{ID: 2, UserID: userID, ParentID: 1, EncryptedName: []byte("zk"), ClientEncrypted: true},
+	}, nil)
+	km := mocks.NewKeyManagerInterface(t)
+	km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil).Once()
+	env := mocks.NewIEnvelope(t)
+	env.On("DecryptUserData", mock.Anything, mock.MatchedBy(func(d models.DBUserData) bool {
+		return string(d.EncryptedData) == "enc"
+	}), []byte("mk")).Return([]byte("Work"), nil)
+	srv := &ServerAdmin{Storage: st, KeyManager: km, Envelope: env}
+
+	resp, err := srv.ListFolders(ctx, &pbrpc.ListFoldersRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetFolders(), 2)
+	assert.Equal(t, "Work", resp.GetFolders()[0].GetName())
+	assert.Equal(t, "01.06.2025 10:00", resp.GetFolders()[0].GetCreatedAt())
+	assert.Nil(t, resp.GetFolders()[0].GetEncrypted())
+	assert.Equal(t, int32(1), resp.GetFolders()[1].GetParentId())
+	assert.Equal(t, []byte("zk"), resp.GetFolders()[1].GetEncrypted().GetEncryptedData())
+
+	failing := mocks.NewIStorage(t)
+	failing.On("ListFolders", mock.Anything, userID).Return(nil, errors.New("db error"))
+	_, err = (&ServerAdmin{Storage: failing}).ListFolders(ctx, &pbrpc.ListFoldersRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestServerAdmin_FolderChanges(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	errorCodes := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "success"},
+		{name: "not found", err: models.ErrFolderNotFound, wantCode: codes.NotFound},
+		{name: "db error", err: errors.New("db error"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range errorCodes {
+		t.Run("rename "+tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			st.On("GetClientKey", mock.Anything, userID).Return(&models.ClientKey{UserID: userID}, nil)
+			st.On("RenameFolder", mock.Anything, mock.MatchedBy(func(f *models.Folder) bool {
+				return f.ID == 4 && f.UserID == userID && f.ClientEncrypted
+			})).Return(tt.err)
+
+			_, err := (&ServerAdmin{Storage: st}).RenameFolder(ctx, &pbrpc.RenameFolderRequest{
+				Id: 4,
+				NameData: &pbrpc.RenameFolderRequest_Encrypted{Encrypted: &pbmodels.EncryptedPayload{
+					EncryptedData: []byte("name"),
+					DataNonce:     []byte("nonce"),
+					EncryptedDek:  []byte("dek"),
+					DekNonce:      []byte("dek-nonce"),
+				}},
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+
+		t.Run("delete "+tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			st.On("DeleteFolder", mock.Anything, 4, userID).Return(tt.err)
+
+			_, err := (&ServerAdmin{Storage: st}).DeleteFolder(ctx, &pbrpc.DeleteFolderRequest{Id: 4})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	for _, tt := range append(errorCodes, struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{name: "cycle", err: models.ErrFolderCycle, wantCode: codes.InvalidArgument}) {
+		t.Run("move "+tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			st.On("MoveFolder", mock.Anything, 4, userID, 5).Return(tt.err)
+
+			_, err := (&ServerAdmin{Storage: st}).MoveFolder(ctx, &pbrpc.MoveFolderRequest{Id: 4, ParentId: 5})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	_, err := (&ServerAdmin{}).MoveFolder(ctx, &pbrpc.MoveFolderRequest{Id: 4, ParentId: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServerAdmin_MoveRecords(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	tests := []struct {
+		name     string
+		req      *pbrpc.MoveRecordsRequest
+		moveErr  error
+		wantCode codes.Code
+	}{
+		{name: "into a folder", req: &pbrpc.MoveRecordsRequest{Ids: []int32{1, 2}, FolderId: 5}},
+		{name: "out of folders", req: &pbrpc.MoveRecordsRequest{Ids: []int32{1, 2}}},
+		{
+			name:     "record of another user",
+			req:      &pbrpc.MoveRecordsRequest{Ids: []int32{1, 2}, FolderId: 5},
+			moveErr:  models.ErrUserDataNotFound,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "folder of another user",
+			req:      &pbrpc.MoveRecordsRequest{Ids: []int32{1, 2}, FolderId: 5},
+			moveErr:  models.ErrFolderNotFound,
+			wantCode: codes.NotFound,
+		},
+		{name: "no records", req: &pbrpc.MoveRecordsRequest{FolderId: 5}, wantCode: codes.InvalidArgument},
+		{name: "negative folder", req: &pbrpc.MoveRecordsRequest{Ids: []int32{1}, FolderId: -1}, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			if tt.wantCode == codes.OK || tt.moveErr != nil {
+				st.On("MoveUserData", mock.Anything, userID, []int{1, 2}, int(tt.req.GetFolderId())).Return(tt.moveErr)
+			}
+
+			resp, err := (&ServerAdmin{Storage: st}).MoveRecords(ctx, tt.req)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, resp.GetMessage())
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/models"
+	pbrpca "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
+)
+
+// StartKeyRotation handles the administrative request to re-wrap all master keys with the
+// active server key.
+//
+// The rotation runs in the background; the response carries the new job, and its progress is
+// reported by GetKeyRotation.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The StartKeyRotationRequest message.
+//
+// Returns:
+//   - *pbrpca.StartKeyRotationResponse: The started job.
+//   - error: A gRPC error if a rotation is already running or the job cannot be created.
+func (s *ServerAdmin) StartKeyRotation(
+	ctx context.Context,
+	_ *pbrpca.StartKeyRotationRequest,
+) (*pbrpca.StartKeyRotationResponse, error) {
+	if s.KeyRotation == nil {
+		return nil, status.Errorf(codes.Unavailable, "ротация ключей недоступна")
+	}
+
+	job, err := s.KeyRotation.Start(ctx)
+	if err != nil {
+		if errors.Is(err, models.ErrKeyRotationInProgress) {
+			return nil, status.Errorf(codes.FailedPrecondition, "ротация ключей уже выполняется")
+		}
+		slog.Error("failed to start key rotation", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка запуска ротации ключей")
+	}
+
+	return &pbrpca.StartKeyRotationResponse{Job: keyRotationJobToProto(job)}, nil
+}
+
+// GetKeyRotation handles the administrative request for the progress of the latest key rotation.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The GetKeyRotationRequest message.
+//
+// Returns:
+//   - *pbrpca.GetKeyRotationResponse: The latest job and the number of keys left to re-wrap.
+//   - error: A gRPC error if no rotation was started or the lookup fails.
+func (s *ServerAdmin) GetKeyRotation(
+	ctx context.Context,
+	_ *pbrpca.GetKeyRotationRequest,
+) (*pbrpca.GetKeyRotationResponse, error) {
+	if s.KeyRotation == nil {
+		return nil, status.Errorf(codes.Unavailable, "ротация ключей недоступна")
+	}
+
+	job, err := s.KeyRotation.Status(ctx)
+	if err != nil {
+		if errors.Is(err, models.ErrKeyRotationNotFound) {
+			return nil, status.Errorf(codes.NotFound, "ротация ключей не запускалась")
+		}
+		slog.Error("failed to get key rotation", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка получения статуса ротации ключей")
+	}
+
+	return &pbrpca.GetKeyRotationResponse{Job: keyRotationJobToProto(job)}, nil
+}
+
+// keyRotationJobToProto converts a key rotation job to its protobuf representation.
+func keyRotationJobToProto(job *models.KeyRotationJob) *pbrpca.KeyRotationJob {
+	return &pbrpca.KeyRotationJob{
+		Id:          int32(job.ID),
+		TargetKeyId: job.TargetKeyID,
+		Status:      job.Status,
+		Rewrapped:   int32(job.Rewrapped),
+		Remaining:   int32(job.Remaining),
+		Error:       job.Error,
+		StartedAt:   job.StartedAt.Format("02.01.2006 15:04"),
+		UpdatedAt:   job.UpdatedAt.Format("02.01.2006 15:04"),
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpca "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
+)
+
+func TestServerAdmin_StartKeyRotation(t *testing.T) {
+	job := &models.KeyRotationJob{ID: 1, TargetKeyID: "k2", Status: models.JobStatusRunning, StartedAt: time.Now()}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(m *mocks.KeyRotator)
+		noRotator   bool
+		wantErrCode codes.Code
+	}{
+		{
+			name: "started",
+			mockSetup: func(m *mocks.KeyRotator) {
+				m.On("Start", mock.Anything).Return(job, nil).Once()
+			},
+		},
+		{
+			name: "already running",
+			mockSetup: func(m *mocks.KeyRotator) {
+				m.On("Start", mock.Anything).Return(nil, models.ErrKeyRotationInProgress).Once()
+			},
+			wantErrCode: codes.FailedPrecondition,
+		},
+		{
+			name: "storage error",
+			mockSetup: func(m *mocks.KeyRotator) {
+				m.On("Start", mock.Anything).Return(nil, errors.New("db error")).Once()
+			},
+			wantErrCode: codes.Internal,
+		},
+		{
+			name:        "rotation not configured",
+			noRotator:   true,
+			wantErrCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServerAdmin{}
+			if !tt.noRotator {
+				rotator := mocks.NewKeyRotator(t)
+				tt.mockSetup(rotator)
+				s.KeyRotation = rotator
+			}
+
+			resp, err := s.StartKeyRotation(context.Background(), &pbrpca.StartKeyRotationRequest{})
+			if tt.wantErrCode != codes.OK {
+				assert.Equal(t, tt.wantErrCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "k2", resp.GetJob().GetTargetKeyId())
+			assert.Equal(t, models.JobStatusRunning, resp.GetJob().GetStatus())
+		})
+	}
+}
+
+func TestServerAdmin_GetKeyRotation(t *testing.T) {
+	job := &models.KeyRotationJob{ID: 3, TargetKeyID: "k2", Status: models.JobStatusRunning, Rewrapped: 10, Remaining: 5}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(m *mocks.KeyRotator)
+		wantErrCode codes.Code
+	}{
+		{
+			name: "progress",
+			mockSetup: func(m *mocks.KeyRotator) {
+				m.On("Status", mock.Anything).Return(job, nil).Once()
+			},
+		},
+		{
+			name: "never started",
+			mockSetup: func(m *mocks.KeyRotator) {
+				m.On("Status", mock.Anything).Return(nil, models.ErrKeyRotationNotFound).Once()
+			},
+			wantErrCode: codes.NotFound,
+		},
+		{
+			name: "storage error",
+			mockSetup: func(m *mocks.KeyRotator) {
+				m.On("Status", mock.Anything).Return(nil, errors.New("db error")).Once()
+			},
+			wantErrCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rotator := mocks.NewKeyRotator(t)
+			tt.mockSetup(rotator)
+			s := &ServerAdmin{KeyRotation: rotator}
+
+			resp, err := s.GetKeyRotation(context.Background(), &pbrpca.GetKeyRotationRequest{})
+			if tt.wantErrCode != codes.OK {
+				assert.Equal(t, tt.wantErrCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int32(10), resp.GetJob().GetRewrapped())
+			assert.Equal(t, int32(5), resp.GetJob().GetRemaining())
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/apetsko/gophkeeper/models"
 	"github.com/apetsko/gophkeeper/pkg/jwt"
@@ -17,7 +18,12 @@ import (
 // Login handles the gRPC request for user authentication.
 //
 // This method validates the username and password, checks credentials against the database,
-// generates a JWT token upon successful authentication, and ensures the user's master key exists.
+// starts a session with a short-lived access token and a refresh token upon successful authentication,
+// and ensures the user's master key exists.
+// Zero-knowledge accounts authenticate with the client-derived key instead of the password and
+// receive their KDF parameters and wrapped master key, which only the client can open.
+// Accounts with two-factor authentication get a short-lived two-factor token instead of the
+// authentication token, to be exchanged in LoginTOTP.
 //
 // Parameters:
 // - ctx: The gRPC context.
@@ -43,28 +49,68 @@ func (s *ServerAdmin) Login(ctx context.Context, in *pbrpcu.LoginRequest) (*pbrp
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	token, err := jwt.GenerateJWT(user.ID, user.Username, s.JWTConfig.Secret)
+	if user.EncryptionMode != models.EncryptionModeClient {
+		// TODO: нужно записать в потокобезопасную мапу в памяти
+		_, errMasterKey := s.KeyManager.GetOrCreateMasterKey(
+			ctx,
+			user.ID,
+			in.Password,
+		)
+
+		if errMasterKey != nil {
+			slog.Error("failed to generate encrypted master key: " + errMasterKey.Error())
+
+			return nil, errors.New("failed to generate encrypted master key")
+		}
+	}
+
+	if user.TOTPEnabled {
+		twoFactorToken, jti, errToken := jwt.GenerateTwoFactorJWT(user.ID, s.JWTConfig.Secret, twoFactorTokenTTL)
+		if errToken != nil {
+			return nil, fmt.Errorf("failed to generate two-factor token: %w", errToken)
+		}
+		expiresAt := time.Now().Add(twoFactorTokenTTL)
+		if errToken = s.Storage.CreateTwoFactorChallenge(ctx, user.ID, jti, expiresAt); errToken != nil {
+			return nil, fmt.Errorf("failed to create two-factor challenge: %w", errToken)
+		}
+
+		return &pbrpcu.LoginResponse{
+			Id:                int32(user.ID),
+			Username:          user.Username,
+			TwoFactorRequired: true,
+			TwoFactorToken:    twoFactorToken,
+		}, nil
+	}
+
+	return s.loginResponse(ctx, user)
+}
+
+// loginResponse starts a session for a user who passed all login checks.
+// Zero-knowledge accounts also receive their KDF parameters and wrapped master key.
+func (s *ServerAdmin) loginResponse(ctx context.Context, user *models.UserEntry) (*pbrpcu.LoginResponse, error) {
+	tokens, err := s.startSession(ctx, user.ID, user.Username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
-	// TODO: нужно записать в потокобезопасную мапу в памяти
-	_, errMasterKey := s.KeyManager.GetOrCreateMasterKey(
-		ctx,
-		user.ID,
-		in.Password,
-		nil,
-	)
+	resp := &pbrpcu.LoginResponse{
+		Id:           int32(user.ID),
+		Username:     user.Username,
+		Token:        tokens.access,
+		RefreshToken: tokens.refresh,
+		ExpiresIn:    tokens.expiresIn,
+	}
 
-	if errMasterKey != nil {
-		slog.Error("failed to generate encrypted master key: " + errMasterKey.Error())
+	if user.EncryptionMode == models.EncryptionModeClient {
+		key, errKey := s.Storage.GetClientKey(ctx, user.ID)
+		if errKey != nil {
+			return nil, fmt.Errorf("failed to get client key: %w", errKey)
+		}
 
-		return nil, errors.New("failed to generate encrypted master key")
+		resp.ZeroKnowledge = true
+		resp.Kdf = kdfToProto(key.KDF)
+		resp.WrappedMasterKey = key.WrappedMasterKey
 	}
 
-	return &pbrpcu.LoginResponse{
-		Id:       int32(user.ID),
-		Username: user.Username,
-		Token:    token,
-	}, nil
+	return resp, nil
 }
@@ -39,7 +39,8 @@ func TestServerAdmin_Login(t *testing.T) {
 					Username:     username,
 					PasswordHash: string(hash),
 				}, nil)
-				km.On("GetOrCreateMasterKey", mock.Anything, userID, password, mock.Anything).Return([]byte("mk"), nil)
+				km.On("GetOrCreateMasterKey", mock.Anything, userID, password).Return([]byte("mk"), nil)
+				st.On("CreateSession", mock.Anything, sessionOf(userID), mock.Anything).Return(1, nil)
 			},
 			wantErr: false,
 		},
@@ -92,7 +93,7 @@ func TestServerAdmin_Login(t *testing.T) {
 					Username:     username,
 					PasswordHash: string(hash),
 				}, nil)
-				km.On("GetOrCreateMasterKey", mock.Anything, userID, password, mock.Anything).Return(nil, errors.New("mk error"))
+				km.On("GetOrCreateMasterKey", mock.Anything, userID, password).Return(nil, errors.New("mk error"))
 			},
 			wantErr: true,
 		},
@@ -119,6 +120,7 @@ func TestServerAdmin_Login(t *testing.T) {
 				assert.NotNil(t, resp)
 				assert.Equal(t, username, resp.Username)
 				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
 			}
 		})
 	}
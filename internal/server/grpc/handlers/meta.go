@@ -0,0 +1,292 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/models"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+)
+
+// Limits of the structured meta of a record. Meta is stored and searched in plaintext, so it
+// is kept small; the record payload is the place for large values.
+const (
+	MaxMetaTitleLength      = 200
+	MaxMetaContentLength    = 16 << 10
+	MaxMetaTags             = 32
+	MaxMetaTagLength        = 64
+	MaxMetaURLs             = 16
+	MaxMetaURLLength        = 2048
+	MaxMetaFields           = 64
+	MaxMetaFieldNameLength  = 128
+	MaxMetaFieldValueLength = 4096
+)
+
+// normalizeMeta validates meta and returns a copy with the title, tags, URLs and field names
+// trimmed, and the tags lower-cased without duplicates. A nil meta is an empty one.
+//
+// Parameters:
+//   - meta: The meta sent by the client.
+//
+// Returns:
+//   - *pbmodels.Meta: The normalized meta.
+//   - error: An InvalidArgument status naming the first invalid value.
+func normalizeMeta(meta *pbmodels.Meta) (*pbmodels.Meta, error) {
+	out := &pbmodels.Meta{}
+	if meta == nil {
+		return out, nil
+	}
+	out.Content = meta.GetContent()
+	out.Title = strings.TrimSpace(meta.GetTitle())
+	out.Favorite = meta.GetFavorite()
+
+	if utf8.RuneCountInString(out.Title) > MaxMetaTitleLength {
+		return nil, status.Errorf(codes.InvalidArgument, "заголовок длиннее %d символов", MaxMetaTitleLength)
+	}
+	if len(out.Content) > MaxMetaContentLength {
+		return nil, status.Errorf(codes.InvalidArgument, "описание больше %d байт", MaxMetaContentLength)
+	}
+
+	tags, err := normalizeTags(meta.GetTags())
+	if err != nil {
+		return nil, err
+	}
+	out.Tags = tags
+
+	if len(meta.GetUrls()) > MaxMetaURLs {
+		return nil, status.Errorf(codes.InvalidArgument, "не больше %d ссылок", MaxMetaURLs)
+	}
+	for _, raw := range meta.GetUrls() {
+		link := strings.TrimSpace(raw)
+		if err := validateURL(link); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверная ссылка %q: %v", raw, err)
+		}
+		out.Urls = append(out.Urls, link)
+	}
+
+	if len(meta.GetFields()) > MaxMetaFields {
+		return nil, status.Errorf(codes.InvalidArgument, "не больше %d полей", MaxMetaFields)
+	}
+	names := make(map[string]struct{}, len(meta.GetFields()))
+	for _, field := range meta.GetFields() {
+		name := strings.TrimSpace(field.GetName())
+		if name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "не указано имя поля")
+		}
+		if utf8.RuneCountInString(name) > MaxMetaFieldNameLength {
+			return nil, status.Errorf(codes.InvalidArgument, "имя поля длиннее %d символов", MaxMetaFieldNameLength)
+		}
+		if _, dup := names[name]; dup {
+			return nil, status.Errorf(codes.InvalidArgument, "поле %q указано дважды", name)
+		}
+		names[name] = struct{}{}
+
+		if len(field.GetValue()) > MaxMetaFieldValueLength {
+			return nil, status.Errorf(codes.InvalidArgument, "значение поля %q больше %d байт", name, MaxMetaFieldValueLength)
+		}
+		// Пустое значение допустимо для любого типа: поле заведено, но еще не заполнено
+		if field.GetValue() != "" {
+			if err := validateFieldValue(field.GetType(), field.GetValue()); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "неверное значение поля %q: %v", name, err)
+			}
+		}
+
+		out.Fields = append(out.Fields, &pbmodels.CustomField{
+			Name:      name,
+			Type:      field.GetType(),
+			Value:     field.GetValue(),
+			Sensitive: field.GetSensitive(),
+		})
+	}
+
+	return out, nil
+}
+
+// normalizeTags trims and lower-cases tags and drops empty and repeated ones, keeping the
+// order of the first occurrences.
+func normalizeTags(tags []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]struct{}, len(tags))
+	for _, raw := range tags {
+		tag := strings.ToLower(strings.TrimSpace(raw))
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxMetaTagLength {
+			return nil, status.Errorf(codes.InvalidArgument, "тег длиннее %d символов", MaxMetaTagLength)
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	if len(out) > MaxMetaTags {
+		return nil, status.Errorf(codes.InvalidArgument, "не больше %d тегов", MaxMetaTags)
+	}
+	return out, nil
+}
+
+// validateURL accepts absolute http and https URLs.
+func validateURL(link string) error {
+	if len(link) > MaxMetaURLLength {
+		return fmt.Errorf("longer than %d bytes", MaxMetaURLLength)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("not an absolute http or https URL")
+	}
+	return nil
+}
+
+// validateFieldValue checks that value is a valid value of a custom field of type t.
+func validateFieldValue(t pbc.CustomFieldType, value string) error {
+	switch t {
+	case pbc.CustomFieldType_CUSTOM_FIELD_TYPE_TEXT:
+		return nil
+	case pbc.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER:
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("not a number")
+		}
+		return nil
+	case pbc.CustomFieldType_CUSTOM_FIELD_TYPE_BOOLEAN:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("must be true or false")
+		}
+		return nil
+	case pbc.CustomFieldType_CUSTOM_FIELD_TYPE_DATE:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fmt.Errorf("must be a date in the YYYY-MM-DD format")
+		}
+		return nil
+	case pbc.CustomFieldType_CUSTOM_FIELD_TYPE_URL:
+		return validateURL(value)
+	case pbc.CustomFieldType_CUSTOM_FIELD_TYPE_EMAIL:
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return fmt.Errorf("not an email address")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown field type %v", t)
+	}
+}
+
+// hasSecretValues reports whether meta has a sensitive field with a value.
+func hasSecretValues(meta *pbmodels.Meta) bool {
+	for _, field := range meta.GetFields() {
+		if field.GetSensitive() && field.GetValue() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// plainMeta returns the JSON of the meta of a record of a zero-knowledge account. The server
+// cannot encrypt for such accounts, so sensitive values belong to the encrypted payload.
+func plainMeta(meta *pbmodels.Meta) (string, error) {
+	if hasSecretValues(meta) {
+		return "", status.Errorf(codes.InvalidArgument,
+			"значения чувствительных полей нельзя хранить открыто: данные этого аккаунта шифруются на клиенте")
+	}
+	return protojson.Format(meta), nil
+}
+
+// sealMeta stores meta in record: the JSON of Meta keeps the sensitive fields without their
+// values, which are encrypted with the DEK of the record into MetaSecrets.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - record: The record, whose EncryptedDek and DekNonce are already set.
+//   - encryptedMK: The master key of the owner of the record.
+//   - meta: The normalized meta.
+//
+// Returns:
+//   - error: An error if the secrets cannot be encrypted.
+func (s *ServerAdmin) sealMeta(ctx context.Context, record *models.DBUserData, encryptedMK []byte, meta *pbmodels.Meta) error {
+	public := proto.Clone(meta).(*pbmodels.Meta)
+	secrets := &pbmodels.Meta{}
+	for _, field := range public.GetFields() {
+		if field.GetSensitive() && field.GetValue() != "" {
+			secrets.Fields = append(secrets.Fields, &pbmodels.CustomField{Name: field.GetName(), Value: field.GetValue()})
+			field.Value = ""
+		}
+	}
+
+	record.Meta = protojson.Format(public)
+	record.MetaSecrets, record.MetaSecretsNonce = nil, nil
+	if len(secrets.GetFields()) == 0 {
+		return nil
+	}
+
+	serialized, err := proto.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("serialize error: %v", err)
+	}
+	record.MetaSecrets, record.MetaSecretsNonce, err = s.Envelope.SealWithDEK(ctx, *record, encryptedMK, serialized)
+	if err != nil {
+		slog.Error("failed to seal meta secrets", "error", err)
+		return status.Errorf(codes.Internal, "ошибка шифрования данных")
+	}
+	return nil
+}
+
+// openMeta returns the meta of record with the values of its sensitive fields decrypted.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - record: A server-encrypted record or a prior version of it.
+//   - encryptedMK: The master key of the owner of the record.
+//
+// Returns:
+//   - *pbmodels.Meta: The full meta.
+//   - error: A gRPC error if the meta cannot be parsed or decrypted.
+func (s *ServerAdmin) openMeta(ctx context.Context, record *models.DBUserData, encryptedMK []byte) (*pbmodels.Meta, error) {
+	meta := &pbmodels.Meta{}
+	if errUnmarshal := protojson.Unmarshal([]byte(record.Meta), meta); errUnmarshal != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка парсинга Meta JSON: %v", errUnmarshal)
+	}
+	if len(record.MetaSecrets) == 0 {
+		return meta, nil
+	}
+
+	serialized, err := s.Envelope.OpenWithDEK(ctx, *record, encryptedMK, record.MetaSecrets, record.MetaSecretsNonce)
+	if err != nil {
+		slog.Error("failed to open meta secrets", "user", record.UserID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка расшифровки данных")
+	}
+	var secrets pbmodels.Meta
+	if err = proto.Unmarshal(serialized, &secrets); err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка парсинга данных")
+	}
+
+	values := make(map[string]string, len(secrets.GetFields()))
+	for _, field := range secrets.GetFields() {
+		values[field.GetName()] = field.GetValue()
+	}
+	for _, field := range meta.GetFields() {
+		if value, ok := values[field.GetName()]; ok && field.GetSensitive() {
+			field.Value = value
+		}
+	}
+	return meta, nil
+}
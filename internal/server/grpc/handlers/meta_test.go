@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+)
+
+func TestNormalizeMeta(t *testing.T) {
+	field := func(t pbc.CustomFieldType, value string) *pbmodels.Meta {
+		return &pbmodels.Meta{Fields: []*pbmodels.CustomField{{Name: "f", Type: t, Value: value}}}
+	}
+
+	t.Run("normalized", func(t *testing.T) {
+		meta, err := normalizeMeta(&pbmodels.Meta{
+			Title:  "  Router  ",
+			Tags:   []string{"Home", " wifi ", "home", ""},
+			Urls:   []string{" https://192.168.0.1/ "},
+			Fields: []*pbmodels.CustomField{{Name: " Model ", Value: "AX55"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Router", meta.GetTitle())
+		assert.Equal(t, []string{"home", "wifi"}, meta.GetTags())
+		assert.Equal(t, []string{"https://192.168.0.1/"}, meta.GetUrls())
+		assert.Equal(t, "Model", meta.GetFields()[0].GetName())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		meta, err := normalizeMeta(nil)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(&pbmodels.Meta{}, meta))
+	})
+
+	valid := []*pbmodels.Meta{
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER, "-1.5"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_BOOLEAN, "true"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_DATE, "2026-02-28"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_URL, "http://example.com"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_EMAIL, "user@example.com"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_DATE, ""),
+	}
+	for _, meta := range valid {
+		_, err := normalizeMeta(meta)
+		assert.NoError(t, err, meta.String())
+	}
+
+	invalid := []*pbmodels.Meta{
+		{Title: strings.Repeat("x", MaxMetaTitleLength+1)},
+		{Tags: []string{strings.Repeat("x", MaxMetaTagLength+1)}},
+		{Urls: []string{"example.com"}},
+		{Urls: []string{"javascript:alert(1)"}},
+		{Fields: []*pbmodels.CustomField{{Name: " "}}},
+		{Fields: []*pbmodels.CustomField{{Name: "a"}, {Name: "a "}}},
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER, "one"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_BOOLEAN, "yes"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_DATE, "28.02.2026"),
+		field(pbc.CustomFieldType_CUSTOM_FIELD_TYPE_EMAIL, "User <user@example.com>"),
+		field(pbc.CustomFieldType(42), "x"),
+	}
+	for _, meta := range invalid {
+		_, err := normalizeMeta(meta)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), meta.String())
+	}
+}
+
+func TestServerAdmin_SealOpenMeta(t *testing.T) {
+	ctx := context.Background()
+	meta := &pbmodels.Meta{
+		Title: "Bank",
+		Fields: []*pbmodels.CustomField{
+			{Name: "Branch", Value: "Central"},
+			{Name: "PIN", Value: "1234", Sensitive: true},
+			{Name: "Recovery", Sensitive: true},
+		},
+	}
+
+	env := mocks.NewIEnvelope(t)
+	var sealed []byte
+	env.On("SealWithDEK", ctx, mock.Anything, []byte("mk"), mock.Anything).
+		Run(func(args mock.Arguments) { sealed = args.Get(3).([]byte) }).
+		Return([]byte("ciphertext"), []byte("nonce"), nil)
+	srv := &ServerAdmin{Envelope: env}
+
+	record := &models.DBUserData{EncryptedDek: []byte("dek"), DekNonce: []byte("dek_nonce")}
+	require.NoError(t, srv.sealMeta(ctx, record, []byte("mk"), meta))
+	assert.NotContains(t, record.Meta, "1234")
+	assert.Contains(t, record.Meta, "Central")
+	assert.Equal(t, []byte("ciphertext"), record.MetaSecrets)
+	assert.Equal(t, []byte("nonce"), record.MetaSecretsNonce)
+	assert.Equal(t, "1234", meta.GetFields()[1].GetValue(), "meta is not modified")
+
+	env.On("OpenWithDEK", ctx, mock.Anything, []byte("mk"), []byte("ciphertext"), []byte("nonce")).Return(sealed, nil)
+	opened, err := srv.openMeta(ctx, record, []byte("mk"))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(meta, opened), opened.String())
+
+	t.Run("no secrets", func(t *testing.T) {
+		record := &models.DBUserData{MetaSecrets: []byte("stale")}
+		require.NoError(t, srv.sealMeta(ctx, record, []byte("mk"), &pbmodels.Meta{Title: "t"}))
+		assert.Nil(t, record.MetaSecrets)
+		assert.Nil(t, record.MetaSecretsNonce)
+	})
+}
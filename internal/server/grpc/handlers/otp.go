@@ -0,0 +1,175 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/totp"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// otpAlgorithms maps the OTP algorithms of the API to the ones of the totp package.
+var otpAlgorithms = map[pbc.OtpAlgorithm]string{
+	pbc.OtpAlgorithm_OTP_ALGORITHM_SHA1:   totp.AlgorithmSHA1,
+	pbc.OtpAlgorithm_OTP_ALGORITHM_SHA256: totp.AlgorithmSHA256,
+	pbc.OtpAlgorithm_OTP_ALGORITHM_SHA512: totp.AlgorithmSHA512,
+}
+
+// GenerateOTP handles the gRPC request for the codes of an OTP record.
+//
+// The seed is decrypted only for the duration of the call and never leaves the server; the
+// response carries the current code, the one that follows and how long the current one is valid.
+// Seeds of zero-knowledge accounts cannot be read by the server, so their clients generate the
+// codes themselves.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The GenerateOTPRequest message with the record ID.
+//
+// Returns:
+//   - *pbrpc.GenerateOTPResponse: The codes.
+//   - error: A gRPC error if access is denied, the record is not an OTP or decryption fails.
+func (s *ServerAdmin) GenerateOTP(ctx context.Context, in *pbrpc.GenerateOTPRequest) (*pbrpc.GenerateOTPResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	userData, err := s.Storage.GetUserData(ctx, int(in.GetId()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения данных")
+	}
+	if userData.UserID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "нет доступа к запрошенным данным")
+	}
+	if userData.Type != constants.OTP {
+		return nil, status.Errorf(codes.InvalidArgument, "запись не является OTP")
+	}
+	if userData.ClientEncrypted {
+		return nil, status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrMasterKeyNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+		}
+		return nil, fmt.Errorf("error get encryptedMK: %v", err)
+	}
+
+	decryptData, err := s.Envelope.DecryptUserData(ctx, *userData, encryptedMK)
+	if err != nil {
+		slog.Error("failed to decrypt otp", "user", userID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка расшифровки данных")
+	}
+	var otp pbmodels.Otp
+	errUnmarshal := proto.Unmarshal(decryptData, &otp)
+	clear(decryptData)
+	if errUnmarshal != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка парсинга OTP")
+	}
+
+	opts := otpOpts(&otp)
+	now := time.Now()
+	code, err := totp.Code(otp.GetSecret(), now, opts)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка генерации кода: %v", err)
+	}
+	next, err := totp.Code(otp.GetSecret(), now.Add(opts.Period), opts)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка генерации кода: %v", err)
+	}
+
+	// Оставшееся время округляется вверх, чтобы код не считался истекшим раньше срока
+	remaining := totp.Remaining(now, opts)
+	return &pbrpc.GenerateOTPResponse{
+		Code:      code,
+		NextCode:  next,
+		ExpiresIn: int32((remaining + time.Second - 1) / time.Second),
+		Period:    int32(opts.Period / time.Second),
+	}, nil
+}
+
+// normalizeOTP validates an OTP seed sent by the client and returns it with the defaults
+// applied. When the URI is set, the seed and its parameters are taken from it; the URI itself
+// is not stored, since it repeats the other fields.
+//
+// Parameters:
+//   - otp: The OTP sent by the client.
+//
+// Returns:
+//   - *pbmodels.Otp: The normalized OTP.
+//   - error: An InvalidArgument status if the seed or its parameters are invalid.
+func normalizeOTP(otp *pbmodels.Otp) (*pbmodels.Otp, error) {
+	if otp == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "отсутствуют данные OTP")
+	}
+
+	if otp.GetUri() != "" {
+		key, err := totp.ParseURI(otp.GetUri())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "неверная ссылка otpauth: %v", err)
+		}
+		out := &pbmodels.Otp{
+			Secret:  key.Secret,
+			Digits:  int32(key.Opts.Digits),
+			Period:  int32(key.Opts.Period / time.Second),
+			Issuer:  key.Issuer,
+			Account: key.Account,
+		}
+		for algorithm, name := range otpAlgorithms {
+			if name == key.Opts.Algorithm {
+				out.Algorithm = algorithm
+			}
+		}
+		return out, nil
+	}
+
+	if _, ok := otpAlgorithms[otp.GetAlgorithm()]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "неверные данные OTP: неизвестный алгоритм %v", otp.GetAlgorithm())
+	}
+	out := &pbmodels.Otp{
+		Secret:    strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(otp.GetSecret()), " ", "")),
+		Algorithm: otp.GetAlgorithm(),
+		Digits:    otp.GetDigits(),
+		Period:    otp.GetPeriod(),
+		Issuer:    strings.TrimSpace(otp.GetIssuer()),
+		Account:   strings.TrimSpace(otp.GetAccount()),
+	}
+	if out.Digits == 0 {
+		out.Digits = 6
+	}
+	if out.Period == 0 {
+		out.Period = 30
+	}
+	if out.Period < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "неверные данные OTP: период должен быть положительным")
+	}
+	if err := totp.Check(out.Secret, otpOpts(out)); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "неверные данные OTP: %v", err)
+	}
+	return out, nil
+}
+
+// otpOpts returns the code parameters of otp.
+func otpOpts(otp *pbmodels.Otp) totp.Opts {
+	return totp.Opts{
+		Algorithm: otpAlgorithms[otp.GetAlgorithm()],
+		Digits:    int(otp.GetDigits()),
+		Period:    time.Duration(otp.GetPeriod()) * time.Second,
+	}
+}
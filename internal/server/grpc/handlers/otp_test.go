@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/totp"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServerAdmin_GenerateOTP(t *testing.T) {
+	const userID = 42
+	const secret = "JBSWY3DPEHPK3PXP"
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	seed, err := proto.Marshal(&pbmodels.Otp{Secret: secret, Digits: 8, Period: 60})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		setupMocks func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+	}{
+		{
+			name: "success",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(&models.DBUserData{UserID: userID, Type: constants.OTP}, nil)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("DecryptUserData", mock.Anything, mock.AnythingOfType("models.DBUserData"), []byte("mk")).
+					Return(append([]byte(nil), seed...), nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not owner",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(&models.DBUserData{UserID: 99, Type: constants.OTP}, nil)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "not an otp",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(&models.DBUserData{UserID: userID, Type: constants.Credentials}, nil)
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "client encrypted",
+			setupMocks: func(st *mocks.IStorage, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUserData", mock.Anything, 1).Return(&models.DBUserData{
+					UserID:          userID,
+					Type:            constants.OTP,
+					ClientEncrypted: true,
+				}, nil)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, env, km)
+			srv := &ServerAdmin{
+				Storage:    st,
+				JWTConfig:  config.JWTConfig{},
+				Envelope:   env,
+				KeyManager: km,
+			}
+
+			resp, err := srv.GenerateOTP(ctx, &pbrpc.GenerateOTPRequest{Id: 1})
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			opts := totp.Opts{Digits: 8, Period: time.Minute}
+			_, valid := totp.Validate(resp.GetCode(), secret, time.Now(), 1, opts)
+			assert.True(t, valid)
+			assert.Len(t, resp.GetNextCode(), 8)
+			assert.Equal(t, int32(60), resp.GetPeriod())
+			assert.Positive(t, resp.GetExpiresIn())
+			assert.LessOrEqual(t, resp.GetExpiresIn(), int32(60))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// PreLogin tells a client how to authenticate the given user.
+//
+// For zero-knowledge accounts it returns the KDF parameters the client needs to derive its
+// authentication and encryption keys from the password before calling Login. Unknown users
+// and accounts using server-side encryption get an empty response, so they log in with the
+// plain password.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The PreLoginRequest message with the username.
+//
+// Returns:
+//   - *pbrpcu.PreLoginResponse: The encryption mode and KDF parameters.
+//   - error: An error if the lookup fails.
+func (s *ServerAdmin) PreLogin(ctx context.Context, in *pbrpcu.PreLoginRequest) (*pbrpcu.PreLoginResponse, error) {
+	user, err := s.Storage.GetUser(ctx, in.GetUsername())
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return &pbrpcu.PreLoginResponse{}, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.EncryptionMode != models.EncryptionModeClient {
+		return &pbrpcu.PreLoginResponse{}, nil
+	}
+
+	key, err := s.Storage.GetClientKey(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client key: %w", err)
+	}
+
+	return &pbrpcu.PreLoginResponse{
+		ZeroKnowledge: true,
+		Kdf:           kdfToProto(key.KDF),
+	}, nil
+}
+
+// kdfToProto converts KDF parameters to their protobuf representation.
+func kdfToProto(p models.KDFParams) *pbmodels.KdfParams {
+	return &pbmodels.KdfParams{
+		Algorithm: p.Algorithm,
+		Salt:      p.Salt,
+		Time:      p.Time,
+		Memory:    p.Memory,
+		Threads:   uint32(p.Threads),
+	}
+}
+
+// kdfFromProto converts protobuf KDF parameters to the model type.
+func kdfFromProto(p *pbmodels.KdfParams) models.KDFParams {
+	return models.KDFParams{
+		Algorithm: p.GetAlgorithm(),
+		Salt:      p.GetSalt(),
+		Time:      p.GetTime(),
+		Memory:    p.GetMemory(),
+		Threads:   uint8(min(p.GetThreads(), 255)),
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testKDF returns KDF parameters that pass validation.
+func testKDF() *pbmodels.KdfParams {
+	return &pbmodels.KdfParams{
+		Algorithm: "argon2id",
+		Salt:      []byte("0123456789abcdef"),
+		Time:      3,
+		Memory:    64 * 1024,
+		Threads:   2,
+	}
+}
+
+func TestServerAdmin_PreLogin(t *testing.T) {
+	const (
+		userID   = 42
+		username = "testuser"
+	)
+	kdf := kdfFromProto(testKDF())
+
+	tests := []struct {
+		name       string
+		setupMocks func(st *mocks.IStorage)
+		wantZK     bool
+		wantErr    bool
+	}{
+		{
+			name: "server mode",
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("GetUser", mock.Anything, username).Return(&models.UserEntry{
+					ID:             userID,
+					Username:       username,
+					EncryptionMode: models.EncryptionModeServer,
+				}, nil)
+			},
+		},
+		{
+			name: "unknown user",
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("GetUser", mock.Anything, username).Return(nil, models.ErrUserNotFound)
+			},
+		},
+		{
+			name: "zero knowledge",
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("GetUser", mock.Anything, username).Return(&models.UserEntry{
+					ID:             userID,
+					Username:       username,
+					EncryptionMode: models.EncryptionModeClient,
+				}, nil)
+				st.On("GetClientKey", mock.Anything, userID).Return(&models.ClientKey{UserID: userID, KDF: kdf}, nil)
+			},
+			wantZK: true,
+		},
+		{
+			name: "storage error",
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("GetUser", mock.Anything, username).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			tt.setupMocks(st)
+
+			srv := &ServerAdmin{Storage: st}
+
+			resp, err := srv.PreLogin(context.Background(), &pbrpcu.PreLoginRequest{Username: username})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantZK, resp.GetZeroKnowledge())
+			if tt.wantZK {
+				assert.Equal(t, kdf.Salt, resp.GetKdf().GetSalt())
+				assert.Equal(t, kdf.Memory, resp.GetKdf().GetMemory())
+			}
+		})
+	}
+}
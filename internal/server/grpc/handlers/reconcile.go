@@ -0,0 +1,63 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbrpca "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
+)
+
+// ReconcileObjects handles the administrative request to compare the S3 bucket with the records
+// that reference its objects.
+//
+// Objects no record or version references are reported as orphans and deleted when repair is
+// set; records whose object is missing are only reported.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ReconcileObjectsRequest message.
+//
+// Returns:
+//   - *pbrpca.ReconcileObjectsResponse: The orphaned and missing objects found.
+//   - error: A gRPC error if the bucket or the references cannot be listed.
+func (s *ServerAdmin) ReconcileObjects(
+	ctx context.Context,
+	in *pbrpca.ReconcileObjectsRequest,
+) (*pbrpca.ReconcileObjectsResponse, error) {
+	if s.Reconciler == nil {
+		return nil, status.Errorf(codes.Unavailable, "сверка объектов недоступна")
+	}
+
+	report, err := s.Reconciler.Reconcile(ctx, in.GetRepair())
+	if err != nil {
+		slog.Error("failed to reconcile objects", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка сверки объектов")
+	}
+
+	resp := &pbrpca.ReconcileObjectsResponse{
+		Objects:    int32(report.Objects),
+		References: int32(report.References),
+		Removed:    int32(report.Removed),
+	}
+	for _, obj := range report.Orphans {
+		resp.Orphans = append(resp.Orphans, &pbrpca.OrphanObject{
+			Name:         obj.Name,
+			Size:         obj.Size,
+			LastModified: obj.LastModified.Format("02.01.2006 15:04"),
+		})
+	}
+	for _, ref := range report.Missing {
+		resp.Missing = append(resp.Missing, &pbrpca.MissingObject{
+			RecordId:   int32(ref.UserDataID),
+			Version:    int32(ref.Version),
+			ObjectName: ref.ObjectName,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpca "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
+)
+
+func TestServerAdmin_ReconcileObjects(t *testing.T) {
+	ctx := context.Background()
+
+	rec := mocks.NewObjectReconciler(t)
+	rec.On("Reconcile", mock.Anything, true).Return(&models.ReconcileReport{
+		Objects:    3,
+		References: 2,
+		Orphans:    []models.S3Object{{Name: "orphan", Size: 10, LastModified: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)}},
+		Missing:    []models.ObjectReference{{UserDataID: 5, Version: 2, ObjectName: "lost"}},
+		Removed:    1,
+	}, nil)
+	srv := &ServerAdmin{Reconciler: rec}
+
+	resp, err := srv.ReconcileObjects(ctx, &pbrpca.ReconcileObjectsRequest{Repair: true})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), resp.GetObjects())
+	assert.Equal(t, int32(1), resp.GetRemoved())
+	require.Len(t, resp.GetOrphans(), 1)
+	assert.Equal(t, "01.06.2025 10:00", resp.GetOrphans()[0].GetLastModified())
+	require.Len(t, resp.GetMissing(), 1)
+	assert.Equal(t, int32(5), resp.GetMissing()[0].GetRecordId())
+	assert.Equal(t, int32(2), resp.GetMissing()[0].GetVersion())
+
+	failing := mocks.NewObjectReconciler(t)
+	failing.On("Reconcile", mock.Anything, false).Return(nil, errors.New("s3 error"))
+	_, err = (&ServerAdmin{Reconciler: failing}).ReconcileObjects(ctx, &pbrpca.ReconcileObjectsRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = (&ServerAdmin{}).ReconcileObjects(ctx, &pbrpca.ReconcileObjectsRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,234 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+const (
+	// DefaultSearchLimit is the number of Search results when the request sets no limit.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit is the largest number of Search results.
+	MaxSearchLimit = 100
+	// MaxSearchQueryLength is the longest query accepted by Search, in characters.
+	MaxSearchQueryLength = 256
+	// searchSimilarity is the word similarity at which a word is highlighted as a misspelling
+	// of a query term; it is the default threshold of the <% operator of pg_trgm.
+	searchSimilarity = 0.6
+)
+
+// Search handles the gRPC request to find the user's records by their metadata.
+//
+// Records match when their meta contains words starting with every word of the query, or
+// words similar to it, so misspellings are found as well. Only the caller's records outside
+// the trash are searched. Each result lists the meta fields that match, with the matched
+// words marked.
+//
+// Parameters:
+// - ctx: The gRPC context.
+// - in: The SearchRequest message with the query, types and number of results.
+//
+// Returns:
+// - *pbrpc.SearchResponse: The matched records, best match first.
+// - error: An error if the query is invalid or the search fails.
+func (s *ServerAdmin) Search(ctx context.Context, in *pbrpc.SearchRequest) (*pbrpc.SearchResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	text := strings.TrimSpace(in.GetQuery())
+	if utf8.RuneCountInString(text) > MaxSearchQueryLength {
+		return nil, status.Errorf(codes.InvalidArgument, "запрос длиннее %d символов", MaxSearchQueryLength)
+	}
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "запрос должен содержать хотя бы одно слово")
+	}
+
+	query := models.SearchQuery{Terms: terms, Text: text}
+	for _, t := range in.GetTypes() {
+		name := constants.MapDataTypeToString(t)
+		if name == "unknown" {
+			return nil, status.Errorf(codes.InvalidArgument, "неизвестный тип данных: %v", t)
+		}
+		query.Types = append(query.Types, name)
+	}
+	if in.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit не может быть отрицательным")
+	}
+	query.Limit = int(in.GetLimit())
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	query.Limit = min(query.Limit, MaxSearchLimit)
+
+	found, err := s.Storage.SearchUserData(ctx, userID, query)
+	if err != nil {
+		slog.Error("failed to search user data", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка поиска")
+	}
+
+	resp := &pbrpc.SearchResponse{}
+	for _, r := range found {
+		var meta pbmodels.Meta
+		if errUnmarshal := protojson.Unmarshal([]byte(r.Item.Meta), &meta); errUnmarshal != nil {
+			slog.Error("failed to unmarshal meta: " + errUnmarshal.Error())
+			continue
+		}
+
+		resp.Results = append(resp.Results, &pbrpc.SearchResult{
+			Record: &pbmodels.Record{
+				Id:        int32(r.Item.ID),
+				Type:      r.Item.Type,
+				Meta:      &meta,
+				CreatedAt: r.Item.CreatedAt.Format("02.01.2006 15:04"),
+				UpdatedAt: r.Item.UpdatedAt.Format("02.01.2006 15:04"),
+			},
+			Score:      r.Score,
+			Highlights: highlightMeta(r.Item.Meta, terms),
+		})
+	}
+
+	return resp, nil
+}
+
+// searchTerms splits a query into lower-case words of letters and digits.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// highlightMeta returns the searchable fields of the meta JSON that contain words matching terms.
+func highlightMeta(meta string, terms []string) []*pbrpc.SearchHighlight {
+	var doc any
+	if err := json.Unmarshal([]byte(meta), &doc); err != nil {
+		return nil
+	}
+
+	var highlights []*pbrpc.SearchHighlight
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(joinField(path, k), v[k])
+			}
+		case []any:
+			for i, item := range v {
+				walk(joinField(path, strconv.Itoa(i)), item)
+			}
+		case string:
+			if !searchableField(path) {
+				return
+			}
+			if ranges := highlightRanges(v, terms); len(ranges) > 0 {
+				highlights = append(highlights, &pbrpc.SearchHighlight{Field: path, Value: v, Ranges: ranges})
+			}
+		}
+	}
+	walk("", doc)
+
+	return highlights
+}
+
+// searchableField reports whether the meta field at path is one of those meta_search_text
+// indexes: the title, the content, the tags, the URLs and the names and values of custom fields.
+func searchableField(path string) bool {
+	parts := strings.Split(path, ".")
+	switch parts[0] {
+	case "title", "content":
+		return len(parts) == 1
+	case "tags", "urls":
+		return len(parts) == 2
+	case "fields":
+		return len(parts) == 3 && (parts[2] == "name" || parts[2] == "value")
+	}
+	return false
+}
+
+// joinField appends the name of a nested field to the path of its parent.
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// highlightRanges returns the words of value that start with a term or are similar to it,
+// as ranges of code points.
+func highlightRanges(value string, terms []string) []*pbrpc.SearchRange {
+	var ranges []*pbrpc.SearchRange
+
+	runes := []rune(value)
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) && !unicode.IsNumber(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsNumber(runes[end])) {
+			end++
+		}
+
+		word := strings.ToLower(string(runes[start:end]))
+		for _, term := range terms {
+			// Short terms are too ambiguous to be matched by similarity
+			if strings.HasPrefix(word, term) ||
+				(utf8.RuneCountInString(term) >= 3 && wordSimilarity(term, word) >= searchSimilarity) {
+				ranges = append(ranges, &pbrpc.SearchRange{Start: int32(start), End: int32(end)})
+				break
+			}
+		}
+		start = end
+	}
+
+	return ranges
+}
+
+// wordSimilarity returns the share of the trigrams of term that word contains, which is how
+// pg_trgm's word_similarity compares a term with a single word.
+func wordSimilarity(term, word string) float64 {
+	tt, tw := trigrams(term), trigrams(word)
+	common := 0
+	for t := range tt {
+		if _, ok := tw[t]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(tt))
+}
+
+// trigrams returns the set of trigrams of a word padded with two spaces in front and one
+// behind.
+func trigrams(word string) map[string]struct{} {
+	runes := []rune("  " + word + " ")
+	set := make(map[string]struct{}, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+func TestServerAdmin_Search(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	created := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	st := mocks.NewIStorage(t)
+	st.On("SearchUserData", mock.Anything, userID, models.SearchQuery{
+		Terms: []string{"рабочий", "vpn"},
+		Text:  "Рабочий VPN",
+		Types: []string{constants.Credentials},
+		Limit: DefaultSearchLimit,
+	}).Return([]models.SearchResult{
+		{Item: models.UserDataListItem{ID: 3, Type: constants.Credentials, Meta: `{"content":"VPN (рабочий), vpn.example.com"}`,
+			CreatedAt: created, UpdatedAt: created}, Score: 0.9},
+		{Item: models.UserDataListItem{ID: 4, Type: constants.Credentials, Meta: `invalid`}, Score: 0.5},
+	}, nil)
+	srv := &ServerAdmin{Storage: st}
+
+	resp, err := srv.Search(ctx, &pbrpc.SearchRequest{
+		Query: "  Рабочий VPN ",
+		Types: []pbc.DataType{pbc.DataType_DATA_TYPE_CREDENTIALS},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetResults(), 1)
+
+	result := resp.GetResults()[0]
+	assert.Equal(t, int32(3), result.GetRecord().GetId())
+	assert.Equal(t, "01.06.2025 10:00", result.GetRecord().GetCreatedAt())
+	assert.InDelta(t, 0.9, result.GetScore(), 1e-9)
+	require.Len(t, result.GetHighlights(), 1)
+	assert.Equal(t, "content", result.GetHighlights()[0].GetField())
+	assert.Equal(t, []*pbrpc.SearchRange{{Start: 0, End: 3}, {Start: 5, End: 12}, {Start: 15, End: 18}},
+		result.GetHighlights()[0].GetRanges())
+
+	failing := mocks.NewIStorage(t)
+	failing.On("SearchUserData", mock.Anything, userID, mock.Anything).Return(nil, errors.New("db error"))
+	_, err = (&ServerAdmin{Storage: failing}).Search(ctx, &pbrpc.SearchRequest{Query: "vpn", Limit: 1000})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, MaxSearchLimit, failing.Calls[0].Arguments.Get(2).(models.SearchQuery).Limit)
+
+	for name, req := range map[string]*pbrpc.SearchRequest{
+		"empty query":    {Query: " ,. "},
+		"query too long": {Query: string(make([]rune, MaxSearchQueryLength+1))},
+		"unknown type":   {Query: "vpn", Types: []pbc.DataType{pbc.DataType_DATA_TYPE_UNSPECIFIED}},
+		"negative limit": {Query: "vpn", Limit: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&ServerAdmin{Storage: mocks.NewIStorage(t)}).Search(ctx, req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+
+	_, err = srv.Search(context.Background(), &pbrpc.SearchRequest{Query: "vpn"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHighlightMeta(t *testing.T) {
+	meta := `{"content":"Пароль от роутера","tags":["home","wifi"],"extra":{"note":"router"},` +
+		`"fields":[{"name":"Model","type":"CUSTOM_FIELD_TYPE_TEXT","value":"netgear router"}]}`
+
+	highlights := highlightMeta(meta, []string{"routr", "wi"})
+	require.Len(t, highlights, 2)
+
+	assert.Equal(t, "fields.0.value", highlights[0].GetField())
+	assert.Equal(t, []*pbrpc.SearchRange{{Start: 8, End: 14}}, highlights[0].GetRanges())
+	assert.Equal(t, "tags.1", highlights[1].GetField())
+	assert.Equal(t, "wifi", highlights[1].GetValue())
+
+	assert.Empty(t, highlightMeta(meta, []string{"xyz"}))
+	assert.Empty(t, highlightMeta(meta, []string{"custom"}), "field types are not searchable")
+	assert.Empty(t, highlightMeta("invalid", []string{"vpn"}))
+}
+
+func TestWordSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, wordSimilarity("vpn", "vpn"), 1e-9)
+	assert.GreaterOrEqual(t, wordSimilarity("routr", "router"), searchSimilarity)
+	assert.Less(t, wordSimilarity("router", "vpn"), searchSimilarity)
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/apetsko/gophkeeper/config"
 	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/internal/jobs"
 	"github.com/apetsko/gophkeeper/internal/storage"
 )
 
@@ -12,6 +15,17 @@ type ServerAdmin struct {
 	JWTConfig  config.JWTConfig
 	Envelope   crypto.IEnvelope
 	KeyManager crypto.KeyManagerInterface
+	// KeyRotation runs server key rotations; administrative RPCs are unavailable when nil.
+	KeyRotation jobs.KeyRotator
+	// Reconciler compares the bucket with the stored records; ReconcileObjects is unavailable when nil.
+	Reconciler jobs.ObjectReconciler
+	// VersionRetention is the number of prior versions kept per record for users that have not
+	// chosen their own.
+	VersionRetention int
+	// TrashRetention is how long deleted records stay restorable before they are purged.
+	TrashRetention time.Duration
+	// UploadTTL is how long a resumable upload waits for its next chunk before it is abandoned.
+	UploadTTL time.Duration
 }
 
 func NewServerAdmin(
@@ -27,5 +41,9 @@ func NewServerAdmin(
 		JWTConfig:  jwtConfig,
 		Envelope:   envelope,
 		KeyManager: keyManager,
+
+		VersionRetention: config.DefaultVersionRetention,
+		TrashRetention:   config.DefaultTrashRetention,
+		UploadTTL:        config.DefaultUploadTTL,
 	}
 }
@@ -0,0 +1,128 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// ListSessions handles the gRPC request for the active sessions of the authenticated user.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListSessionsRequest message.
+//
+// Returns:
+//   - *pbrpcu.ListSessionsResponse: The sessions, most recently seen first, with the current one marked.
+//   - error: A gRPC error if the sessions cannot be read.
+func (s *ServerAdmin) ListSessions(ctx context.Context, _ *pbrpcu.ListSessionsRequest) (*pbrpcu.ListSessionsResponse, error) {
+	userID, sessionID, err := sessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.Storage.ListSessions(ctx, userID)
+	if err != nil {
+		slog.Error("failed to list sessions: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка получения списка сессий")
+	}
+
+	resp := &pbrpcu.ListSessionsResponse{Sessions: make([]*pbrpcu.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &pbrpcu.Session{
+			Id:            int32(session.ID),
+			DeviceName:    session.DeviceName,
+			ClientVersion: session.ClientVersion,
+			Ip:            session.IP,
+			CreatedAt:     session.CreatedAt.Format("02.01.2006 15:04"),
+			LastSeenAt:    session.LastUsedAt.Format("02.01.2006 15:04"),
+			Current:       session.ID == sessionID,
+		})
+	}
+
+	return resp, nil
+}
+
+// RevokeSession handles the gRPC request ending one session of the authenticated user.
+//
+// Its refresh token and access tokens stop working immediately. Revoking the current session
+// is the same as Logout.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RevokeSessionRequest message with the session ID.
+//
+// Returns:
+//   - *pbrpcu.RevokeSessionResponse: A response indicating success.
+//   - error: A gRPC error if the user has no such active session.
+func (s *ServerAdmin) RevokeSession(ctx context.Context, in *pbrpcu.RevokeSessionRequest) (*pbrpcu.RevokeSessionResponse, error) {
+	userID, _, err := sessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Storage.RevokeSession(ctx, userID, int(in.GetSessionId()), "revoked by user")
+	if err != nil {
+		if errors.Is(err, models.ErrSessionNotFound) {
+			return nil, status.Errorf(codes.NotFound, "сессия не найдена")
+		}
+		slog.Error("failed to revoke session: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка завершения сессии")
+	}
+
+	return &pbrpcu.RevokeSessionResponse{Message: "сессия завершена"}, nil
+}
+
+// RevokeAllOtherSessions handles the gRPC request ending every session of the authenticated
+// user except the current one.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RevokeAllOtherSessionsRequest message.
+//
+// Returns:
+//   - *pbrpcu.RevokeAllOtherSessionsResponse: The number of revoked sessions.
+//   - error: A gRPC error if the sessions cannot be revoked.
+func (s *ServerAdmin) RevokeAllOtherSessions(
+	ctx context.Context,
+	_ *pbrpcu.RevokeAllOtherSessionsRequest,
+) (*pbrpcu.RevokeAllOtherSessionsResponse, error) {
+	userID, sessionID, err := sessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.Storage.RevokeOtherSessions(ctx, userID, sessionID, "revoked by user")
+	if err != nil {
+		slog.Error("failed to revoke sessions: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка завершения сессий")
+	}
+
+	return &pbrpcu.RevokeAllOtherSessionsResponse{
+		Revoked: int32(revoked),
+		Message: fmt.Sprintf("завершено сессий: %d", revoked),
+	}, nil
+}
+
+// sessionFromContext returns the user ID and session ID put into ctx by the auth interceptor.
+func sessionFromContext(ctx context.Context) (userID, sessionID int, err error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+	sessionID, ok = ctx.Value(constants.SessionID).(int)
+	if !ok {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "не удалось получить SessionID")
+	}
+	return userID, sessionID, nil
+}
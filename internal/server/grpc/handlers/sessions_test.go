@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// sessionOf matches a session created for userID.
+func sessionOf(userID int) interface{} {
+	return mock.MatchedBy(func(s *models.Session) bool { return s.UserID == userID })
+}
+
+// sessionContext returns a context as set up by the auth interceptor.
+func sessionContext(userID, sessionID int) context.Context {
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	return context.WithValue(ctx, constants.SessionID, sessionID)
+}
+
+func TestServerAdmin_ListSessions(t *testing.T) {
+	seen := time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)
+	st := mocks.NewIStorage(t)
+	st.On("ListSessions", mock.Anything, 42).Return([]*models.Session{
+		{ID: 7, UserID: 42, DeviceName: "laptop", ClientVersion: "1.0.0", IP: "10.0.0.1", CreatedAt: seen, LastUsedAt: seen},
+		{ID: 8, UserID: 42, DeviceName: "phone", CreatedAt: seen, LastUsedAt: seen},
+	}, nil).Once()
+	srv := &ServerAdmin{Storage: st}
+
+	resp, err := srv.ListSessions(sessionContext(42, 7), &pbrpcu.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetSessions(), 2)
+	assert.Equal(t, &pbrpcu.Session{
+		Id: 7, DeviceName: "laptop", ClientVersion: "1.0.0", Ip: "10.0.0.1",
+		CreatedAt: "02.01.2025 10:30", LastSeenAt: "02.01.2025 10:30", Current: true,
+	}, resp.GetSessions()[0])
+	assert.False(t, resp.GetSessions()[1].GetCurrent())
+
+	st.On("ListSessions", mock.Anything, 42).Return(nil, errors.New("db error")).Once()
+	_, err = srv.ListSessions(sessionContext(42, 7), &pbrpcu.ListSessionsRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = srv.ListSessions(context.Background(), &pbrpcu.ListSessionsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServerAdmin_RevokeSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "success"},
+		{name: "not found", err: models.ErrSessionNotFound, wantCode: codes.NotFound},
+		{name: "storage error", err: errors.New("db error"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			st.On("RevokeSession", mock.Anything, 42, 8, "revoked by user").Return(tt.err)
+			srv := &ServerAdmin{Storage: st}
+
+			resp, err := srv.RevokeSession(sessionContext(42, 7), &pbrpcu.RevokeSessionRequest{SessionId: 8})
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, resp.GetMessage())
+		})
+	}
+}
+
+func TestServerAdmin_RevokeAllOtherSessions(t *testing.T) {
+	st := mocks.NewIStorage(t)
+	st.On("RevokeOtherSessions", mock.Anything, 42, 7, "revoked by user").Return(2, nil).Once()
+	srv := &ServerAdmin{Storage: st}
+
+	resp, err := srv.RevokeAllOtherSessions(sessionContext(42, 7), &pbrpcu.RevokeAllOtherSessionsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), resp.GetRevoked())
+
+	st.On("RevokeOtherSessions", mock.Anything, 42, 7, "revoked by user").Return(0, errors.New("db error")).Once()
+	_, err = srv.RevokeAllOtherSessions(sessionContext(42, 7), &pbrpcu.RevokeAllOtherSessionsRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestNewClientSession(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 51000}
+
+	tests := []struct {
+		name      string
+		md        metadata.MD
+		forwarded bool
+		want      *models.Session
+	}{
+		{
+			name: "cli client",
+			md:   metadata.Pairs("device-name", "laptop", "client-version", "1.0.0", "user-agent", "grpc-go/1.72.0"),
+			want: &models.Session{DeviceName: "laptop", ClientVersion: "1.0.0", IP: "192.0.2.10"},
+		},
+		{
+			name:      "browser through the gateway",
+			md:        metadata.Pairs("grpcgateway-user-agent", "Mozilla/5.0", "x-forwarded-for", "203.0.113.5, 10.0.0.1"),
+			forwarded: true,
+			want:      &models.Session{DeviceName: "Mozilla/5.0", IP: "203.0.113.5"},
+		},
+		{
+			name: "direct call with a forged x-forwarded-for",
+			md:   metadata.Pairs("device-name", "laptop", "x-forwarded-for", "203.0.113.5"),
+			want: &models.Session{DeviceName: "laptop", IP: "192.0.2.10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+			if tt.forwarded {
+				ctx = context.WithValue(ctx, constants.Forwarded, true)
+			}
+
+			assert.Equal(t, tt.want, newClientSession(ctx))
+		})
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"log/slog"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/crypto"
 	"github.com/apetsko/gophkeeper/models"
-	"github.com/apetsko/gophkeeper/pkg/jwt"
 	"github.com/apetsko/gophkeeper/pkg/password"
 	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
 )
@@ -17,7 +20,11 @@ import (
 // Signup handles the gRPC request for user registration.
 //
 // This method validates the username and password, hashes the password,
-// creates a new user record in the database, and generates a JWT token for the user.
+// creates a new user record in the database, and starts a session with access and refresh tokens.
+//
+// With zero_knowledge set the password field carries the client-derived authentication key,
+// and the request must include the client's KDF parameters and wrapped master key. The server
+// stores them as-is and never creates a server-side master key for the account.
 //
 // Parameters:
 //   - ctx: The gRPC context.
@@ -41,14 +48,13 @@ func (s *ServerAdmin) Signup(ctx context.Context, in *pbrpcu.SignupRequest) (*pb
 		PasswordHash: hash,
 	}
 
-	userID, err := s.Storage.AddUser(ctx, &user)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	if in.GetZeroKnowledge() {
+		return s.signupZeroKnowledge(ctx, in, &user)
 	}
 
-	token, err := jwt.GenerateJWT(userID, in.Username, s.JWTConfig.Secret)
+	userID, err := s.Storage.AddUser(ctx, &user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// TODO: нужно записать в потокобезопасную мапу в памяти
@@ -56,7 +62,6 @@ func (s *ServerAdmin) Signup(ctx context.Context, in *pbrpcu.SignupRequest) (*pb
 		ctx,
 		userID,
 		in.Password,
-		nil,
 	)
 
 	if errMasterKey != nil {
@@ -65,9 +70,54 @@ func (s *ServerAdmin) Signup(ctx context.Context, in *pbrpcu.SignupRequest) (*pb
 		return nil, errors.New("failed to generate encrypted master key")
 	}
 
+	tokens, err := s.startSession(ctx, userID, in.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pbrpcu.SignupResponse{
+		Id:           int32(user.ID),
+		Username:     user.Username,
+		Token:        tokens.access,
+		RefreshToken: tokens.refresh,
+		ExpiresIn:    tokens.expiresIn,
+	}, nil
+}
+
+// signupZeroKnowledge creates a zero-knowledge account from the client-supplied key material.
+func (s *ServerAdmin) signupZeroKnowledge(
+	ctx context.Context,
+	in *pbrpcu.SignupRequest,
+	user *models.UserEntry,
+) (*pbrpcu.SignupResponse, error) {
+	kdf := kdfFromProto(in.GetKdf())
+	if err := crypto.ValidateKDFParams(kdf); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if len(in.GetWrappedMasterKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "отсутствует зашифрованный мастер-ключ")
+	}
+
+	user.EncryptionMode = models.EncryptionModeClient
+	userID, err := s.Storage.AddClientUser(ctx, user, &models.ClientKey{
+		KDF:              kdf,
+		WrappedMasterKey: in.GetWrappedMasterKey(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	tokens, err := s.startSession(ctx, userID, user.Username)
+	if err != nil {
+		return nil, err
+	}
+
 	return &pbrpcu.SignupResponse{
-		Id:       int32(user.ID),
-		Username: user.Username,
-		Token:    token,
+		Id:            int32(userID),
+		Username:      user.Username,
+		Token:         tokens.access,
+		RefreshToken:  tokens.refresh,
+		ExpiresIn:     tokens.expiresIn,
+		ZeroKnowledge: true,
 	}, nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/apetsko/gophkeeper/config"
 	"github.com/apetsko/gophkeeper/internal/mocks"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,9 +32,10 @@ func TestServerAdmin_Signup(t *testing.T) {
 			req:  &pbrpcu.SignupRequest{Username: username, Password: passwordStr},
 			setupStorage: func(st *mocks.IStorage) {
 				st.On("AddUser", mock.Anything, mock.AnythingOfType("*models.UserEntry")).Return(userID, nil)
+				st.On("CreateSession", mock.Anything, sessionOf(userID), mock.Anything).Return(1, nil)
 			},
 			setupKeys: func(km *mocks.KeyManagerInterface) {
-				km.On("GetOrCreateMasterKey", mock.Anything, userID, passwordStr, mock.Anything).Return([]byte("key"), nil)
+				km.On("GetOrCreateMasterKey", mock.Anything, userID, passwordStr).Return([]byte("key"), nil)
 			},
 			wantErr: false,
 		},
@@ -67,10 +69,51 @@ func TestServerAdmin_Signup(t *testing.T) {
 				st.On("AddUser", mock.Anything, mock.AnythingOfType("*models.UserEntry")).Return(userID, nil)
 			},
 			setupKeys: func(km *mocks.KeyManagerInterface) {
-				km.On("GetOrCreateMasterKey", mock.Anything, userID, passwordStr, mock.Anything).Return(nil, errors.New("key error"))
+				km.On("GetOrCreateMasterKey", mock.Anything, userID, passwordStr).Return(nil, errors.New("key error"))
 			},
 			wantErr: true,
 		},
+		{
+			name: "zero knowledge",
+			req: &pbrpcu.SignupRequest{
+				Username:         username,
+				Password:         passwordStr,
+				ZeroKnowledge:    true,
+				Kdf:              testKDF(),
+				WrappedMasterKey: []byte("wrapped"),
+			},
+			setupStorage: func(st *mocks.IStorage) {
+				st.On("AddClientUser", mock.Anything, mock.AnythingOfType("*models.UserEntry"), mock.AnythingOfType("*models.ClientKey")).Return(userID, nil)
+				st.On("CreateSession", mock.Anything, sessionOf(userID), mock.Anything).Return(1, nil)
+			},
+			setupKeys: func(km *mocks.KeyManagerInterface) {},
+			wantErr:   false,
+		},
+		{
+			name: "zero knowledge weak kdf",
+			req: &pbrpcu.SignupRequest{
+				Username:         username,
+				Password:         passwordStr,
+				ZeroKnowledge:    true,
+				Kdf:              &pbmodels.KdfParams{Algorithm: "argon2id", Salt: []byte("short"), Time: 1, Memory: 64, Threads: 1},
+				WrappedMasterKey: []byte("wrapped"),
+			},
+			setupStorage: func(st *mocks.IStorage) {},
+			setupKeys:    func(km *mocks.KeyManagerInterface) {},
+			wantErr:      true,
+		},
+		{
+			name: "zero knowledge without master key",
+			req: &pbrpcu.SignupRequest{
+				Username:      username,
+				Password:      passwordStr,
+				ZeroKnowledge: true,
+				Kdf:           testKDF(),
+			},
+			setupStorage: func(st *mocks.IStorage) {},
+			setupKeys:    func(km *mocks.KeyManagerInterface) {},
+			wantErr:      true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -95,6 +138,7 @@ func TestServerAdmin_Signup(t *testing.T) {
 				assert.NotNil(t, resp)
 				assert.Equal(t, username, resp.Username)
 				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
 			}
 		})
 	}
@@ -0,0 +1,141 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/jwt"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+// sessionTokens are the tokens issued for a new or refreshed session.
+type sessionTokens struct {
+	access    string
+	refresh   string
+	expiresIn int64
+}
+
+// startSession creates a session for a user who passed all login checks and issues its
+// first access and refresh tokens.
+func (s *ServerAdmin) startSession(ctx context.Context, userID int, username string) (*sessionTokens, error) {
+	refresh, hash, err := jwt.NewRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	session := newClientSession(ctx)
+	session.UserID = userID
+	session.ExpiresAt = time.Now().Add(s.JWTConfig.RefreshTTL)
+
+	sessionID, err := s.Storage.CreateSession(ctx, session, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	access, err := jwt.GenerateJWT(userID, username, sessionID, s.JWTConfig.Secret, s.JWTConfig.AccessTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &sessionTokens{
+		access:    access,
+		refresh:   refresh,
+		expiresIn: int64(s.JWTConfig.AccessTTL / time.Second),
+	}, nil
+}
+
+// Refresh handles the gRPC request exchanging a refresh token for new access and refresh tokens.
+//
+// Every refresh token can be exchanged once. Presenting it again means it leaked, so the
+// session it belongs to is revoked, which logs out both the legitimate client and the attacker.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RefreshRequest message with the refresh token.
+//
+// Returns:
+//   - *pbrpcu.RefreshResponse: The new access and refresh tokens.
+//   - error: A gRPC error if the refresh token is invalid, expired, revoked or reused.
+func (s *ServerAdmin) Refresh(ctx context.Context, in *pbrpcu.RefreshRequest) (*pbrpcu.RefreshResponse, error) {
+	if in.GetRefreshToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "отсутствует refresh-токен")
+	}
+
+	refresh, hash, err := jwt.NewRefreshToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка генерации refresh-токена")
+	}
+
+	session, err := s.Storage.RotateRefreshToken(
+		ctx,
+		jwt.HashRefreshToken(in.GetRefreshToken()),
+		hash,
+		time.Now().Add(s.JWTConfig.RefreshTTL),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRefreshTokenReused):
+			slog.Warn("refresh token reuse detected, session revoked")
+			return nil, status.Errorf(codes.Unauthenticated, "refresh-токен уже использован, сессия отозвана")
+		case errors.Is(err, models.ErrRefreshTokenInvalid):
+			return nil, status.Errorf(codes.Unauthenticated, "недействительный refresh-токен")
+		default:
+			slog.Error("failed to rotate refresh token: " + err.Error())
+			return nil, status.Errorf(codes.Internal, "ошибка обновления токена")
+		}
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "пользователь не найден")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения пользователя")
+	}
+
+	access, err := jwt.GenerateJWT(user.ID, user.Username, session.ID, s.JWTConfig.Secret, s.JWTConfig.AccessTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка генерации токена")
+	}
+
+	return &pbrpcu.RefreshResponse{
+		Token:        access,
+		RefreshToken: refresh,
+		ExpiresIn:    int64(s.JWTConfig.AccessTTL / time.Second),
+	}, nil
+}
+
+// Logout handles the gRPC request ending the session of the presented access token.
+//
+// The session is revoked, so its refresh token and all access tokens issued for it stop working.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The LogoutRequest message.
+//
+// Returns:
+//   - *pbrpcu.LogoutResponse: A response indicating success.
+//   - error: A gRPC error if the session cannot be revoked.
+func (s *ServerAdmin) Logout(ctx context.Context, _ *pbrpcu.LogoutRequest) (*pbrpcu.LogoutResponse, error) {
+	userID, sessionID, err := sessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Storage.RevokeSession(ctx, userID, sessionID, "logout")
+	if err != nil && !errors.Is(err, models.ErrSessionNotFound) {
+		slog.Error("failed to revoke session: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка завершения сессии")
+	}
+
+	return &pbrpcu.LogoutResponse{Message: "сессия завершена"}, nil
+}
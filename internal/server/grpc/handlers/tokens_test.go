@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/jwt"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+func TestServerAdmin_Refresh(t *testing.T) {
+	const (
+		userID    = 42
+		sessionID = 7
+		secret    = "secret"
+		refresh   = "refresh-token"
+	)
+	jwtConfig := config.JWTConfig{Secret: secret, AccessTTL: time.Minute, RefreshTTL: time.Hour}
+	oldHash := jwt.HashRefreshToken(refresh)
+
+	tests := []struct {
+		name       string
+		req        *pbrpcu.RefreshRequest
+		setupMocks func(st *mocks.IStorage)
+		wantCode   codes.Code
+	}{
+		{
+			name: "success",
+			req:  &pbrpcu.RefreshRequest{RefreshToken: refresh},
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("RotateRefreshToken", mock.Anything, oldHash, mock.Anything, mock.Anything).
+					Return(&models.Session{ID: sessionID, UserID: userID}, nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID, Username: "testuser"}, nil)
+			},
+		},
+		{
+			name: "reused token",
+			req:  &pbrpcu.RefreshRequest{RefreshToken: refresh},
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("RotateRefreshToken", mock.Anything, oldHash, mock.Anything, mock.Anything).
+					Return(nil, models.ErrRefreshTokenReused)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "invalid token",
+			req:  &pbrpcu.RefreshRequest{RefreshToken: refresh},
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("RotateRefreshToken", mock.Anything, oldHash, mock.Anything, mock.Anything).
+					Return(nil, models.ErrRefreshTokenInvalid)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "storage error",
+			req:  &pbrpcu.RefreshRequest{RefreshToken: refresh},
+			setupMocks: func(st *mocks.IStorage) {
+				st.On("RotateRefreshToken", mock.Anything, oldHash, mock.Anything, mock.Anything).
+					Return(nil, errors.New("db error"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:       "missing token",
+			req:        &pbrpcu.RefreshRequest{},
+			setupMocks: func(st *mocks.IStorage) {},
+			wantCode:   codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			tt.setupMocks(st)
+
+			srv := &ServerAdmin{Storage: st, JWTConfig: jwtConfig}
+
+			resp, err := srv.Refresh(context.Background(), tt.req)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, refresh, resp.GetRefreshToken())
+			assert.Equal(t, int64(60), resp.GetExpiresIn())
+
+			claims, err := jwt.ParseJWT(resp.GetToken(), secret)
+			require.NoError(t, err)
+			assert.Equal(t, &jwt.Claims{UserID: userID, Username: "testuser", SessionID: sessionID}, claims)
+		})
+	}
+}
+
+func TestServerAdmin_Logout(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.UserID, 42)
+	ctx = context.WithValue(ctx, constants.SessionID, 7)
+
+	st := mocks.NewIStorage(t)
+	st.On("RevokeSession", mock.Anything, 42, 7, "logout").Return(nil).Once()
+	srv := &ServerAdmin{Storage: st}
+
+	resp, err := srv.Logout(ctx, &pbrpcu.LogoutRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetMessage())
+
+	st.On("RevokeSession", mock.Anything, 42, 7, "logout").Return(errors.New("db error")).Once()
+	_, err = srv.Logout(ctx, &pbrpcu.LogoutRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = srv.Logout(context.Background(), &pbrpcu.LogoutRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,128 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/jobs"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// ListTrash handles the gRPC request listing the records the authenticated user has deleted.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListTrashRequest message.
+//
+// Returns:
+//   - *pbrpc.ListTrashResponse: The trashed records with the time each one is purged at.
+//   - error: A gRPC error if the query fails.
+func (s *ServerAdmin) ListTrash(ctx context.Context, _ *pbrpc.ListTrashRequest) (*pbrpc.ListTrashResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	items, err := s.Storage.GetTrashList(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка получения корзины: %v", err)
+	}
+
+	var records []*pbrpc.TrashedRecord
+	for _, item := range items {
+		var meta pbmodels.Meta
+		if errUnmarshal := protojson.Unmarshal([]byte(item.Meta), &meta); errUnmarshal != nil {
+			slog.Error("failed to unmarshal meta: " + errUnmarshal.Error())
+			continue
+		}
+
+		records = append(records, &pbrpc.TrashedRecord{
+			Record: &pbmodels.Record{
+				Id:        int32(item.ID),
+				Type:      item.Type,
+				Meta:      &meta,
+				CreatedAt: item.CreatedAt.Format("02.01.2006 15:04"),
+			},
+			DeletedAt: item.DeletedAt.Format("02.01.2006 15:04"),
+			PurgeAt:   item.DeletedAt.Add(s.TrashRetention).Format("02.01.2006 15:04"),
+		})
+	}
+
+	return &pbrpc.ListTrashResponse{
+		Records: records,
+		Count:   int32(len(records)),
+	}, nil
+}
+
+// RestoreFromTrash handles the gRPC request to take a deleted record out of the trash.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RestoreFromTrashRequest message with the record ID.
+//
+// Returns:
+//   - *pbrpc.RestoreFromTrashResponse: A response indicating success.
+//   - error: A gRPC error if the user has no such record in the trash.
+func (s *ServerAdmin) RestoreFromTrash(
+	ctx context.Context,
+	in *pbrpc.RestoreFromTrashRequest,
+) (*pbrpc.RestoreFromTrashResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	if err := s.Storage.RestoreUserData(ctx, int(in.GetId()), userID); err != nil {
+		if errors.Is(err, models.ErrUserDataNotFound) {
+			return nil, status.Errorf(codes.NotFound, "запись не найдена в корзине")
+		}
+		slog.Error("failed to restore user data from trash: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка восстановления данных")
+	}
+
+	return &pbrpc.RestoreFromTrashResponse{
+		Message: fmt.Sprintf("запись %d восстановлена из корзины", in.GetId()),
+	}, nil
+}
+
+// EmptyTrash handles the gRPC request to permanently delete all records in the user's trash.
+//
+// The records are deleted together with their versions, and then their S3 objects are removed.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The EmptyTrashRequest message.
+//
+// Returns:
+//   - *pbrpc.EmptyTrashResponse: The number of deleted records.
+//   - error: A gRPC error if the deletion fails.
+func (s *ServerAdmin) EmptyTrash(ctx context.Context, _ *pbrpc.EmptyTrashRequest) (*pbrpc.EmptyTrashResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	purged, objects, err := s.Storage.EmptyTrash(ctx, userID)
+	if err != nil {
+		slog.Error("failed to empty trash: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка очистки корзины")
+	}
+
+	jobs.DeleteObjects(ctx, s.StorageS3, objects)
+
+	return &pbrpc.EmptyTrashResponse{
+		Message: fmt.Sprintf("удалено записей: %d", purged),
+		Purged:  int32(purged),
+	}, nil
+}
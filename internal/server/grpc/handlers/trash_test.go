@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+func TestServerAdmin_ListTrash(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	deletedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	st := mocks.NewIStorage(t)
+	st.On("GetTrashList", mock.Anything, userID).Return([]models.UserDataListItem{
+		{ID: 1, UserID: userID, Type: constants.Credentials, Meta: `{"content":"vpn"}`, DeletedAt: deletedAt},
+		{ID: 2, UserID: userID, Type: constants.Credentials, Meta: `invalid`, DeletedAt: deletedAt},
+	}, nil)
+	srv := &ServerAdmin{Storage: st, TrashRetention: 7 * 24 * time.Hour}
+
+	resp, err := srv.ListTrash(ctx, &pbrpc.ListTrashRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), resp.GetCount())
+	assert.Equal(t, "vpn", resp.GetRecords()[0].GetRecord().GetMeta().GetContent())
+	assert.Equal(t, "01.06.2025 10:00", resp.GetRecords()[0].GetDeletedAt())
+	assert.Equal(t, "08.06.2025 10:00", resp.GetRecords()[0].GetPurgeAt())
+
+	failing := mocks.NewIStorage(t)
+	failing.On("GetTrashList", mock.Anything, userID).Return(nil, errors.New("db error"))
+	_, err = (&ServerAdmin{Storage: failing}).ListTrash(ctx, &pbrpc.ListTrashRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestServerAdmin_RestoreFromTrash(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	tests := []struct {
+		name       string
+		restoreErr error
+		wantCode   codes.Code
+	}{
+		{name: "success"},
+		{name: "not in trash", restoreErr: models.ErrUserDataNotFound, wantCode: codes.NotFound},
+		{name: "db error", restoreErr: errors.New("db error"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			st.On("RestoreUserData", mock.Anything, 7, userID).Return(tt.restoreErr)
+			srv := &ServerAdmin{Storage: st}
+
+			resp, err := srv.RestoreFromTrash(ctx, &pbrpc.RestoreFromTrashRequest{Id: 7})
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, resp.GetMessage())
+		})
+	}
+}
+
+func TestServerAdmin_EmptyTrash(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	st := mocks.NewIStorage(t)
+	s3 := mocks.NewS3Client(t)
+	st.On("EmptyTrash", mock.Anything, userID).Return(2, []string{"obj-1", "obj-2"}, nil)
+	s3.On("Delete", mock.Anything, "obj-1").Return(nil)
+	s3.On("Delete", mock.Anything, "obj-2").Return(errors.New("s3 error"))
+	srv := &ServerAdmin{Storage: st, StorageS3: s3}
+
+	resp, err := srv.EmptyTrash(ctx, &pbrpc.EmptyTrashRequest{})
+	require.NoError(t, err, "objects that cannot be removed do not fail the request")
+	assert.Equal(t, int32(2), resp.GetPurged())
+
+	failing := mocks.NewIStorage(t)
+	failing.On("EmptyTrash", mock.Anything, userID).Return(0, nil, errors.New("db error"))
+	_, err = (&ServerAdmin{Storage: failing}).EmptyTrash(ctx, &pbrpc.EmptyTrashRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = srv.EmptyTrash(context.Background(), &pbrpc.EmptyTrashRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,316 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/jwt"
+	"github.com/apetsko/gophkeeper/pkg/password"
+	"github.com/apetsko/gophkeeper/pkg/totp"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+)
+
+const (
+	// totpIssuer is the issuer shown by authenticator apps.
+	totpIssuer = "GophKeeper"
+	// totpSkew is the number of time steps of clock drift accepted in each direction.
+	totpSkew = 1
+	// twoFactorTokenTTL is how long a login may wait for its second factor.
+	twoFactorTokenTTL = 5 * time.Minute
+	// recoveryCodeCount is the number of recovery codes generated at enrollment.
+	recoveryCodeCount = 10
+	// recoveryCodeBytes is the entropy of a recovery code; 10 bytes give 16 base32 characters.
+	recoveryCodeBytes = 10
+)
+
+// twoFactorLimits bounds the guesses at a second factor: a few per two-factor token, after
+// which the password has to be entered again, and a lockout of the user after repeated
+// failures across tokens.
+var twoFactorLimits = models.TwoFactorLimits{
+	TokenAttempts: 5,
+	UserAttempts:  10,
+	Lockout:       15 * time.Minute,
+}
+
+// LoginTOTP handles the gRPC request completing a login with a second factor.
+//
+// The two-factor token issued by Login is exchanged, together with a TOTP code or an unused
+// recovery code, for the regular authentication token. Each TOTP code is accepted only once,
+// and each two-factor token completes at most one login within twoFactorLimits.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The LoginTOTPRequest message with the two-factor token and the code.
+//
+// Returns:
+//   - *pbrpcu.LoginResponse: User details and authentication token.
+//   - error: A gRPC error if the token or the code is invalid.
+func (s *ServerAdmin) LoginTOTP(ctx context.Context, in *pbrpcu.LoginTOTPRequest) (*pbrpcu.LoginResponse, error) {
+	userID, jti, err := jwt.ParseTwoFactorJWT(in.GetTwoFactorToken(), s.JWTConfig.Secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "недействительный токен двухфакторной аутентификации")
+	}
+
+	if err = s.verifySecondFactor(ctx, userID, jti, in.GetCode()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "пользователь не найден")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения пользователя")
+	}
+
+	return s.loginResponse(ctx, user)
+}
+
+// EnrollTOTP handles the gRPC request to start TOTP enrollment for the authenticated user.
+//
+// A new secret is generated, sealed with the server key and stored unconfirmed; it replaces
+// any earlier unconfirmed secret. Two-factor authentication is enabled by ConfirmTOTP.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The EnrollTOTPRequest message.
+//
+// Returns:
+//   - *pbrpcu.EnrollTOTPResponse: The otpauth URI and the secret for the authenticator app.
+//   - error: A gRPC error if two-factor authentication is already enabled.
+func (s *ServerAdmin) EnrollTOTP(ctx context.Context, _ *pbrpcu.EnrollTOTPRequest) (*pbrpcu.EnrollTOTPResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "пользователь не найден")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения пользователя")
+	}
+	if user.TOTPEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "двухфакторная аутентификация уже включена")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка генерации секрета")
+	}
+
+	sealed, err := s.KeyManager.SealSecret(ctx, []byte(secret))
+	if err != nil {
+		slog.Error("failed to seal TOTP secret: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка шифрования секрета")
+	}
+
+	if err = s.Storage.SaveTOTPSecret(ctx, userID, sealed); err != nil {
+		slog.Error("failed to save TOTP secret: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка сохранения секрета")
+	}
+
+	return &pbrpcu.EnrollTOTPResponse{
+		OtpauthUri: totp.URI(totpIssuer, user.Username, secret, totp.Opts{}),
+		Secret:     secret,
+	}, nil
+}
+
+// ConfirmTOTP handles the gRPC request enabling TOTP with the first code from the authenticator app.
+//
+// On success it returns one-time recovery codes; only their hashes are stored, so they are
+// shown to the user exactly once.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ConfirmTOTPRequest message with the code.
+//
+// Returns:
+//   - *pbrpcu.ConfirmTOTPResponse: One-time recovery codes.
+//   - error: A gRPC error if there is no pending enrollment or the code is invalid.
+func (s *ServerAdmin) ConfirmTOTP(ctx context.Context, in *pbrpcu.ConfirmTOTPRequest) (*pbrpcu.ConfirmTOTPResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	state, err := s.Storage.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrTOTPNotEnrolled) {
+			return nil, status.Errorf(codes.FailedPrecondition, "сначала начните подключение двухфакторной аутентификации")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	if state.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "двухфакторная аутентификация уже включена")
+	}
+
+	secret, err := s.KeyManager.OpenSecret(ctx, state.Secret)
+	if err != nil {
+		slog.Error("failed to open TOTP secret: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка расшифровки секрета")
+	}
+
+	step, valid := totp.Validate(in.GetCode(), string(secret), time.Now(), totpSkew, totp.Opts{})
+	if !valid {
+		return nil, status.Errorf(codes.InvalidArgument, "неверный код")
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка генерации кодов восстановления")
+	}
+
+	if err = s.Storage.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		slog.Error("failed to enable TOTP: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка включения двухфакторной аутентификации")
+	}
+
+	return &pbrpcu.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP handles the gRPC request to turn off two-factor authentication.
+//
+// Both the password and a current TOTP code or an unused recovery code are required.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The DisableTOTPRequest message with the password and the code.
+//
+// Returns:
+//   - *pbrpcu.DisableTOTPResponse: A response indicating success.
+//   - error: A gRPC error if the password or the code is wrong.
+func (s *ServerAdmin) DisableTOTP(ctx context.Context, in *pbrpcu.DisableTOTPRequest) (*pbrpcu.DisableTOTPResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "пользователь не найден")
+		}
+		return nil, status.Errorf(codes.Internal, "ошибка получения пользователя")
+	}
+
+	if !password.CheckPasswordHash(in.GetPassword(), user.PasswordHash) {
+		return nil, status.Errorf(codes.Unauthenticated, "неверный пароль")
+	}
+
+	if err = s.verifySecondFactor(ctx, userID, "", in.GetCode()); err != nil {
+		return nil, err
+	}
+
+	if err = s.Storage.DisableTOTP(ctx, userID); err != nil {
+		slog.Error("failed to disable TOTP: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка отключения двухфакторной аутентификации")
+	}
+
+	return &pbrpcu.DisableTOTPResponse{Message: "двухфакторная аутентификация отключена"}, nil
+}
+
+// verifySecondFactor accepts a TOTP code that was not used before or an unused recovery code.
+//
+// Every attempt is counted against the user and, when jti is set, against the two-factor
+// token before the code is checked; an accepted code clears the count and uses up the token.
+func (s *ServerAdmin) verifySecondFactor(ctx context.Context, userID int, jti, code string) error {
+	state, err := s.Storage.GetTOTP(ctx, userID)
+	if err != nil && !errors.Is(err, models.ErrTOTPNotEnrolled) {
+		return status.Errorf(codes.Internal, "ошибка получения настроек двухфакторной аутентификации")
+	}
+	if err != nil || !state.Enabled {
+		return status.Errorf(codes.FailedPrecondition, "двухфакторная аутентификация не включена")
+	}
+
+	if err = s.Storage.StartTwoFactorAttempt(ctx, userID, jti, twoFactorLimits); err != nil {
+		return twoFactorAttemptError(err)
+	}
+
+	secret, err := s.KeyManager.OpenSecret(ctx, state.Secret)
+	if err != nil {
+		slog.Error("failed to open TOTP secret: " + err.Error())
+		return status.Errorf(codes.Internal, "ошибка расшифровки секрета")
+	}
+
+	if step, valid := totp.Validate(code, string(secret), time.Now(), totpSkew, totp.Opts{}); valid {
+		if err = s.Storage.UpdateTOTPStep(ctx, userID, step); err != nil {
+			if errors.Is(err, models.ErrTOTPCodeReused) {
+				return status.Errorf(codes.Unauthenticated, "код уже использован")
+			}
+			return status.Errorf(codes.Internal, "ошибка проверки кода")
+		}
+	} else if err = s.Storage.UseRecoveryCode(ctx, userID, hashRecoveryCode(code)); err != nil {
+		if errors.Is(err, models.ErrRecoveryCodeInvalid) {
+			return status.Errorf(codes.Unauthenticated, "неверный код")
+		}
+		return status.Errorf(codes.Internal, "ошибка проверки кода")
+	}
+
+	if err = s.Storage.FinishTwoFactorAttempt(ctx, userID, jti); err != nil {
+		return twoFactorAttemptError(err)
+	}
+
+	return nil
+}
+
+// twoFactorAttemptError maps an error of counting a second-factor attempt to a gRPC error.
+func twoFactorAttemptError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrTwoFactorLocked):
+		return status.Errorf(codes.ResourceExhausted, "слишком много неудачных попыток, повторите позже")
+	case errors.Is(err, models.ErrTwoFactorTokenUsed):
+		return status.Errorf(codes.Unauthenticated, "недействительный токен двухфакторной аутентификации")
+	default:
+		slog.Error("failed to count two-factor attempt: " + err.Error())
+		return status.Errorf(codes.Internal, "ошибка проверки кода")
+	}
+}
+
+// generateRecoveryCodes returns recoveryCodeCount random codes formatted as XXXX-XXXX-XXXX-XXXX
+// together with their hashes.
+func generateRecoveryCodes() ([]string, [][]byte, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	hashes := make([][]byte, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		plain := encoding.EncodeToString(raw)
+		groups := make([]string, 0, len(plain)/4)
+		for i := 0; i < len(plain); i += 4 {
+			groups = append(groups, plain[i:i+4])
+		}
+
+		code := strings.Join(groups, "-")
+		recoveryCodes = append(recoveryCodes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return recoveryCodes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code ignoring case, spaces and dashes.
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	"github.com/apetsko/gophkeeper/pkg/jwt"
+	"github.com/apetsko/gophkeeper/pkg/totp"
+	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
+	"github.com/apetsko/gophkeeper/utils"
+)
+
+func TestServerAdmin_Login_TwoFactor(t *testing.T) {
+	const (
+		userID   = 42
+		username = "testuser"
+		password = "password123"
+		secret   = "secret"
+	)
+	hash, _ := utils.HashPassword(password)
+
+	st := mocks.NewIStorage(t)
+	km := mocks.NewKeyManagerInterface(t)
+	st.On("GetUser", mock.Anything, username).Return(&models.UserEntry{
+		ID:           userID,
+		Username:     username,
+		PasswordHash: string(hash),
+		TOTPEnabled:  true,
+	}, nil)
+	km.On("GetOrCreateMasterKey", mock.Anything, userID, password).Return([]byte("mk"), nil)
+	var challenge string
+	st.On("CreateTwoFactorChallenge", mock.Anything, userID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { challenge = args.String(2) }).Return(nil)
+
+	srv := &ServerAdmin{Storage: st, KeyManager: km, JWTConfig: config.JWTConfig{Secret: secret}}
+
+	resp, err := srv.Login(context.Background(), &pbrpcu.LoginRequest{Username: username, Password: password})
+	require.NoError(t, err)
+	assert.True(t, resp.GetTwoFactorRequired())
+	assert.Empty(t, resp.GetToken())
+
+	pendingID, jti, err := jwt.ParseTwoFactorJWT(resp.GetTwoFactorToken(), secret)
+	require.NoError(t, err)
+	assert.Equal(t, userID, pendingID)
+	assert.Equal(t, challenge, jti, "the challenge is recorded under the token ID")
+}
+
+func TestServerAdmin_LoginTOTP(t *testing.T) {
+	const (
+		userID = 42
+		secret = "secret"
+	)
+	totpSecret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	code, err := totp.Code(totpSecret, time.Now(), totp.Opts{})
+	require.NoError(t, err)
+
+	sealed := &models.WrappedSecret{KeyID: "default", Ciphertext: []byte("sealed")}
+	enabled := &models.TOTP{UserID: userID, Secret: sealed, Enabled: true}
+	user := &models.UserEntry{ID: userID, Username: "testuser", TOTPEnabled: true}
+
+	pending, jti, err := jwt.GenerateTwoFactorJWT(userID, secret, time.Minute)
+	require.NoError(t, err)
+	full, err := jwt.GenerateJWT(userID, "testuser", 1, secret, time.Minute)
+	require.NoError(t, err)
+
+	recoveryCodes, _, err := generateRecoveryCodes()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		req        *pbrpcu.LoginTOTPRequest
+		setupMocks func(st *mocks.IStorage, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+	}{
+		{
+			name: "totp code",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(nil)
+				km.On("OpenSecret", mock.Anything, sealed).Return([]byte(totpSecret), nil)
+				st.On("UpdateTOTPStep", mock.Anything, userID, mock.AnythingOfType("int64")).Return(nil)
+				st.On("FinishTwoFactorAttempt", mock.Anything, userID, jti).Return(nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+				st.On("CreateSession", mock.Anything, sessionOf(userID), mock.Anything).Return(1, nil)
+			},
+		},
+		{
+			name: "recovery code",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: recoveryCodes[0]},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(nil)
+				km.On("OpenSecret", mock.Anything, sealed).Return([]byte(totpSecret), nil)
+				st.On("UseRecoveryCode", mock.Anything, userID, hashRecoveryCode(recoveryCodes[0])).Return(nil)
+				st.On("FinishTwoFactorAttempt", mock.Anything, userID, jti).Return(nil)
+				st.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+				st.On("CreateSession", mock.Anything, sessionOf(userID), mock.Anything).Return(1, nil)
+			},
+		},
+		{
+			name: "reused totp code",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(nil)
+				km.On("OpenSecret", mock.Anything, sealed).Return([]byte(totpSecret), nil)
+				st.On("UpdateTOTPStep", mock.Anything, userID, mock.AnythingOfType("int64")).Return(models.ErrTOTPCodeReused)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "wrong code",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: "000000"},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(nil)
+				km.On("OpenSecret", mock.Anything, sealed).Return([]byte(totpSecret), nil)
+				st.On("UseRecoveryCode", mock.Anything, userID, mock.Anything).Return(models.ErrRecoveryCodeInvalid)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "user locked out",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(models.ErrTwoFactorLocked)
+			},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name: "token out of attempts",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(models.ErrTwoFactorTokenUsed)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "token used by a concurrent login",
+			req:  &pbrpcu.LoginTOTPRequest{TwoFactorToken: pending, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetTOTP", mock.Anything, userID).Return(enabled, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, jti, twoFactorLimits).Return(nil)
+				km.On("OpenSecret", mock.Anything, sealed).Return([]byte(totpSecret), nil)
+				st.On("UpdateTOTPStep", mock.Anything, userID, mock.AnythingOfType("int64")).Return(nil)
+				st.On("FinishTwoFactorAttempt", mock.Anything, userID, jti).Return(models.ErrTwoFactorTokenUsed)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:       "regular token instead of two-factor token",
+			req:        &pbrpcu.LoginTOTPRequest{TwoFactorToken: full, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {},
+			wantCode:   codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, km)
+
+			srv := &ServerAdmin{Storage: st, KeyManager: km, JWTConfig: config.JWTConfig{Secret: secret}}
+
+			resp, err := srv.LoginTOTP(context.Background(), tt.req)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, resp.GetToken())
+				assert.False(t, resp.GetTwoFactorRequired())
+			}
+		})
+	}
+}
+
+func TestServerAdmin_EnrollAndConfirmTOTP(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	sealed := &models.WrappedSecret{KeyID: "default", Ciphertext: []byte("sealed")}
+
+	st := mocks.NewIStorage(t)
+	km := mocks.NewKeyManagerInterface(t)
+	srv := &ServerAdmin{Storage: st, KeyManager: km}
+
+	var plainSecret []byte
+	st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID, Username: "testuser"}, nil).Once()
+	km.On("SealSecret", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		plainSecret = args.Get(1).([]byte)
+	}).Return(sealed, nil).Once()
+	st.On("SaveTOTPSecret", mock.Anything, userID, sealed).Return(nil).Once()
+
+	enroll, err := srv.EnrollTOTP(ctx, &pbrpcu.EnrollTOTPRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, string(plainSecret), enroll.GetSecret())
+	assert.Contains(t, enroll.GetOtpauthUri(), "otpauth://totp/GophKeeper:testuser")
+
+	st.On("GetTOTP", mock.Anything, userID).Return(&models.TOTP{UserID: userID, Secret: sealed}, nil)
+	km.On("OpenSecret", mock.Anything, sealed).Return(plainSecret, nil)
+
+	_, err = srv.ConfirmTOTP(ctx, &pbrpcu.ConfirmTOTPRequest{Code: "000000"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	code, err := totp.Code(enroll.GetSecret(), time.Now(), totp.Opts{})
+	require.NoError(t, err)
+
+	var storedHashes [][]byte
+	st.On("EnableTOTP", mock.Anything, userID, mock.AnythingOfType("int64"), mock.Anything).Run(func(args mock.Arguments) {
+		storedHashes = args.Get(3).([][]byte)
+	}).Return(nil).Once()
+
+	confirm, err := srv.ConfirmTOTP(ctx, &pbrpcu.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+	require.Len(t, confirm.GetRecoveryCodes(), recoveryCodeCount)
+	require.Len(t, storedHashes, recoveryCodeCount)
+	for i, rc := range confirm.GetRecoveryCodes() {
+		assert.Len(t, rc, 19)
+		assert.Equal(t, storedHashes[i], hashRecoveryCode(rc))
+	}
+	// Recovery codes are accepted regardless of case, spaces and dashes.
+	typed := strings.ToLower(strings.ReplaceAll(confirm.GetRecoveryCodes()[0], "-", " "))
+	assert.Equal(t, storedHashes[0], hashRecoveryCode(typed))
+}
+
+func TestServerAdmin_EnrollTOTP_AlreadyEnabled(t *testing.T) {
+	const userID = 42
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	st := mocks.NewIStorage(t)
+	st.On("GetUserByID", mock.Anything, userID).Return(&models.UserEntry{ID: userID, TOTPEnabled: true}, nil)
+	srv := &ServerAdmin{Storage: st, KeyManager: mocks.NewKeyManagerInterface(t)}
+
+	_, err := srv.EnrollTOTP(ctx, &pbrpcu.EnrollTOTPRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServerAdmin_DisableTOTP(t *testing.T) {
+	const (
+		userID = 42
+		pass   = "password123"
+	)
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	hash, _ := utils.HashPassword(pass)
+	user := &models.UserEntry{ID: userID, Username: "testuser", PasswordHash: string(hash), TOTPEnabled: true}
+	sealed := &models.WrappedSecret{KeyID: "default", Ciphertext: []byte("sealed")}
+
+	totpSecret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	code, err := totp.Code(totpSecret, time.Now(), totp.Opts{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		req        *pbrpcu.DisableTOTPRequest
+		setupMocks func(st *mocks.IStorage, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+	}{
+		{
+			name: "success",
+			req:  &pbrpcu.DisableTOTPRequest{Password: pass, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+				st.On("GetTOTP", mock.Anything, userID).Return(&models.TOTP{UserID: userID, Secret: sealed, Enabled: true}, nil)
+				st.On("StartTwoFactorAttempt", mock.Anything, userID, "", twoFactorLimits).Return(nil)
+				km.On("OpenSecret", mock.Anything, sealed).Return([]byte(totpSecret), nil)
+				st.On("UpdateTOTPStep", mock.Anything, userID, mock.AnythingOfType("int64")).Return(nil)
+				st.On("FinishTwoFactorAttempt", mock.Anything, userID, "").Return(nil)
+				st.On("DisableTOTP", mock.Anything, userID).Return(nil)
+			},
+		},
+		{
+			name: "wrong password",
+			req:  &pbrpcu.DisableTOTPRequest{Password: "wrongpass", Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "not enrolled",
+			req:  &pbrpcu.DisableTOTPRequest{Password: pass, Code: code},
+			setupMocks: func(st *mocks.IStorage, km *mocks.KeyManagerInterface) {
+				st.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+				st.On("GetTOTP", mock.Anything, userID).Return(nil, models.ErrTOTPNotEnrolled)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			km := mocks.NewKeyManagerInterface(t)
+			tt.setupMocks(st, km)
+
+			srv := &ServerAdmin{Storage: st, KeyManager: km}
+
+			resp, err := srv.DisableTOTP(ctx, tt.req)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, resp.GetMessage())
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// Package handlers provides gRPC server handlers for managing user data operations,
+// including creation, retrieval, update, and deletion of user records.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// UploadChunkSize is the size of the chunks of resumable uploads. Every chunk but the last one
+// must have exactly this size. Each chunk becomes one part of a MinIO multipart upload, so it is
+// above the 5 MiB minimum part size, and a multiple of crypto.StreamChunkSize, so parts can be
+// encrypted independently.
+const UploadChunkSize = 8 << 20
+
+// MaxUploadChunkMessageSize is the size limit of messages carrying upload chunks: a chunk plus
+// room for the rest of the request.
+const MaxUploadChunkMessageSize = UploadChunkSize + 64<<10
+
+// MaxUploadSize is the largest file a resumable upload accepts: MinIO allows 10000 parts.
+const MaxUploadSize = 10000 * UploadChunkSize
+
+// CreateUpload starts a resumable upload of a file of known size.
+//
+// The file is sent with UploadChunk in chunks of the returned size, possibly over several
+// connections, and stored with CompleteUpload. An upload that receives no chunk for the upload
+// TTL is abandoned.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - in: The CreateUploadRequest message with the file name, type, size and meta.
+//
+// Returns:
+//   - *pbrpc.CreateUploadResponse: The upload ID, the chunk size and the expiry time.
+//   - error: A gRPC error if the request is invalid, the account encrypts on the client or the
+//     upload cannot be started.
+func (s *ServerAdmin) CreateUpload(ctx context.Context, in *pbrpc.CreateUploadRequest) (*pbrpc.CreateUploadResponse, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	if in.GetName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "не указано имя файла")
+	}
+	if in.GetSize() <= 0 || in.GetSize() > MaxUploadSize {
+		return nil, status.Errorf(codes.InvalidArgument, "размер файла должен быть от 1 до %d байт", int64(MaxUploadSize))
+	}
+	meta, err := normalizeMeta(in.GetMeta())
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrMasterKeyNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте")
+		}
+		return nil, fmt.Errorf("error get encryptedMK: %v", err)
+	}
+
+	sealer, encryptedData, err := s.Envelope.NewStreamSealer(ctx, encryptedMK)
+	if err != nil {
+		slog.Error("failed to create stream sealer", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка шифрования данных")
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ошибка создания загрузки")
+	}
+
+	// Чувствительные поля шифруются ключом загрузки, который станет ключом записи
+	sealed := &models.DBUserData{EncryptedDek: encryptedData.EncryptedDek, DekNonce: encryptedData.DekNonce}
+	if err = s.sealMeta(ctx, sealed, encryptedMK, meta); err != nil {
+		return nil, err
+	}
+
+	objectName := fmt.Sprintf("%d-%s", time.Now().UnixNano(), in.GetName())
+	multipartID, err := s.StorageS3.CreateMultipartUpload(ctx, &models.S3UploadData{
+		ObjectName:  objectName,
+		MetaContent: meta.GetContent(),
+		FileName:    in.GetName(),
+		FileType:    in.GetType(),
+	})
+	if err != nil {
+		slog.Error("failed to start multipart upload", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка создания загрузки")
+	}
+
+	upload := &models.Upload{
+		ID:               uploadID,
+		UserID:           userID,
+		ObjectName:       objectName,
+		MultipartID:      multipartID,
+		FileName:         in.GetName(),
+		FileType:         in.GetType(),
+		Meta:             sealed.Meta,
+		Size:             in.GetSize(),
+		EncryptedDek:     encryptedData.EncryptedDek,
+		DekNonce:         encryptedData.DekNonce,
+		StreamHeader:     sealer.Header(),
+		ExpiresAt:        time.Now().Add(s.UploadTTL),
+		MetaSecrets:      sealed.MetaSecrets,
+		MetaSecretsNonce: sealed.MetaSecretsNonce,
+	}
+	if err = s.Storage.CreateUpload(ctx, upload); err != nil {
+		s.abortMultipartUpload(ctx, upload)
+		slog.Error("failed to save upload", "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка создания загрузки")
+	}
+
+	return &pbrpc.CreateUploadResponse{
+		UploadId:  uploadID,
+		ChunkSize: UploadChunkSize,
+		ExpiresAt: upload.ExpiresAt.Format("02.01.2006 15:04"),
+	}, nil
+}
+
+// UploadChunk stores the chunk of a resumable upload that starts at the received offset.
+//
+// A chunk whose response was lost is detected by the offset check; the client asks GetUpload for
+// the received offset and carries on from there. Sending different data for a chunk that was
+// already encrypted is refused, since its nonces cannot be reused; such an upload must be started
+// over.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - in: The UploadChunkRequest message with the upload ID, the offset and the chunk.
+//
+// Returns:
+//   - *pbrpc.UploadChunkResponse: The new received offset and expiry time.
+//   - error: A gRPC error if the upload does not exist, the offset or the chunk size is wrong or
+//     the chunk cannot be stored.
+func (s *ServerAdmin) UploadChunk(ctx context.Context, in *pbrpc.UploadChunkRequest) (*pbrpc.UploadChunkResponse, error) {
+	upload, err := s.ownUpload(ctx, in.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+
+	offset, data := in.GetOffset(), in.GetData()
+	if offset != upload.Received {
+		return nil, status.Errorf(codes.Aborted, "неверное смещение %d, получено %d байт", offset, upload.Received)
+	}
+	end := offset + int64(len(data))
+	if len(data) == 0 || end > upload.Size || (end < upload.Size && len(data) != UploadChunkSize) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"размер части должен быть %d байт, кроме последней части", UploadChunkSize)
+	}
+
+	number := int(offset/UploadChunkSize) + 1
+	digest := sha256.Sum256(data)
+	stored, err := s.Storage.ReserveUploadPart(ctx, upload.ID, number, digest[:])
+	if err != nil {
+		slog.Error("failed to reserve upload part", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка сохранения части")
+	}
+	if !bytes.Equal(stored, digest[:]) {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"часть %d уже была получена с другим содержимым, начните загрузку заново", number)
+	}
+
+	encryptedMK, err := s.KeyManager.GetMasterKey(ctx, upload.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error get encryptedMK: %v", err)
+	}
+	sealer, err := s.Envelope.OpenStreamSealer(ctx, models.DBUserData{
+		EncryptedDek: upload.EncryptedDek,
+		DekNonce:     upload.DekNonce,
+	}, encryptedMK, upload.StreamHeader)
+	if err != nil {
+		slog.Error("failed to open stream sealer", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка шифрования данных")
+	}
+	part, err := sealer.SealPart(data, offset, end == upload.Size)
+	if err != nil {
+		slog.Error("failed to seal upload part", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка шифрования данных")
+	}
+
+	etag, err := s.StorageS3.UploadPart(ctx, upload.ObjectName, upload.MultipartID, number, part)
+	if err != nil {
+		slog.Error("failed to upload part", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка сохранения части")
+	}
+
+	expiresAt := time.Now().Add(s.UploadTTL)
+	err = s.Storage.CommitUploadPart(ctx, upload.ID, number, etag, offset, end, expiresAt)
+	if err != nil {
+		if errors.Is(err, models.ErrUploadOffsetMismatch) {
+			return nil, status.Errorf(codes.Aborted, "часть %d уже получена", number)
+		}
+		slog.Error("failed to commit upload part", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка сохранения части")
+	}
+
+	return &pbrpc.UploadChunkResponse{
+		Offset:    end,
+		ExpiresAt: expiresAt.Format("02.01.2006 15:04"),
+	}, nil
+}
+
+// GetUpload returns the state of a resumable upload, so an interrupted client knows the offset
+// to continue from.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - in: The GetUploadRequest message with the upload ID.
+//
+// Returns:
+//   - *pbrpc.GetUploadResponse: The file name and size, the received offset, the chunk size and
+//     the expiry time.
+//   - error: A gRPC error if the user has no such upload.
+func (s *ServerAdmin) GetUpload(ctx context.Context, in *pbrpc.GetUploadRequest) (*pbrpc.GetUploadResponse, error) {
+	upload, err := s.ownUpload(ctx, in.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pbrpc.GetUploadResponse{
+		Name:      upload.FileName,
+		Size:      upload.Size,
+		Offset:    upload.Received,
+		ChunkSize: UploadChunkSize,
+		ExpiresAt: upload.ExpiresAt.Format("02.01.2006 15:04"),
+	}, nil
+}
+
+// CompleteUpload assembles a fully received upload into a file record.
+//
+// The upload is claimed first, so concurrent calls cannot turn the same object into two records.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - in: The CompleteUploadRequest message with the upload ID.
+//
+// Returns:
+//   - *pbrpc.CompleteUploadResponse: The ID of the new record and the file size.
+//   - error: A gRPC error if the user has no such upload, not all of the file was received or
+//     the file cannot be stored.
+func (s *ServerAdmin) CompleteUpload(ctx context.Context, in *pbrpc.CompleteUploadRequest) (*pbrpc.CompleteUploadResponse, error) {
+	upload, err := s.ownUpload(ctx, in.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+	if upload.Received != upload.Size {
+		return nil, status.Errorf(codes.FailedPrecondition, "получено %d из %d байт", upload.Received, upload.Size)
+	}
+	if err = s.claimUpload(ctx, upload.ID); err != nil {
+		return nil, err
+	}
+
+	parts, err := s.Storage.ListUploadParts(ctx, upload.ID)
+	if err != nil {
+		s.releaseUpload(ctx, upload.ID)
+		slog.Error("failed to list upload parts", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка завершения загрузки")
+	}
+	if err = s.StorageS3.CompleteMultipartUpload(ctx, upload.ObjectName, upload.MultipartID, parts); err != nil {
+		s.releaseUpload(ctx, upload.ID)
+		slog.Error("failed to complete multipart upload", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка завершения загрузки")
+	}
+
+	id, err := s.Storage.SaveUserData(ctx, &models.DBUserData{
+		UserID:           upload.UserID,
+		Type:             constants.BinaryData,
+		MinioObjectID:    upload.ObjectName,
+		EncryptedDek:     upload.EncryptedDek,
+		DekNonce:         upload.DekNonce,
+		Meta:             upload.Meta,
+		MetaSecrets:      upload.MetaSecrets,
+		MetaSecretsNonce: upload.MetaSecretsNonce,
+	})
+	if err != nil {
+		s.discardObject(ctx, upload.ObjectName)
+		s.deleteUpload(ctx, upload.ID)
+		slog.Error("failed to save uploaded file: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "ошибка сохранения данных")
+	}
+	s.deleteUpload(ctx, upload.ID)
+
+	return &pbrpc.CompleteUploadResponse{
+		Id:      int32(id),
+		Size:    upload.Size,
+		Message: fmt.Sprintf("файл %s успешно сохранен", upload.FileName),
+	}, nil
+}
+
+// AbortUpload discards a resumable upload and the chunks received so far. Like CompleteUpload
+// it claims the upload first, so an upload being completed is not aborted under it.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - in: The AbortUploadRequest message with the upload ID.
+//
+// Returns:
+//   - *pbrpc.AbortUploadResponse: A confirmation message.
+//   - error: A gRPC error if the user has no such upload or it cannot be discarded.
+func (s *ServerAdmin) AbortUpload(ctx context.Context, in *pbrpc.AbortUploadRequest) (*pbrpc.AbortUploadResponse, error) {
+	upload, err := s.ownUpload(ctx, in.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.claimUpload(ctx, upload.ID); err != nil {
+		return nil, err
+	}
+
+	if err = s.StorageS3.AbortMultipartUpload(ctx, upload.ObjectName, upload.MultipartID); err != nil {
+		s.releaseUpload(ctx, upload.ID)
+		slog.Error("failed to abort multipart upload", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка отмены загрузки")
+	}
+	if err = s.Storage.DeleteUpload(ctx, upload.ID); err != nil && !errors.Is(err, models.ErrUploadNotFound) {
+		slog.Error("failed to delete upload", "upload", upload.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка отмены загрузки")
+	}
+
+	return &pbrpc.AbortUploadResponse{Message: "загрузка отменена"}, nil
+}
+
+// ownUpload returns the unexpired upload uploadID of the user making the request. Uploads of
+// other users are reported as missing.
+func (s *ServerAdmin) ownUpload(ctx context.Context, uploadID string) (*models.Upload, error) {
+	userID, ok := ctx.Value(constants.UserID).(int)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "не удалось получить UserID")
+	}
+
+	upload, err := s.Storage.GetUpload(ctx, uploadID)
+	if err != nil {
+		if errors.Is(err, models.ErrUploadNotFound) {
+			return nil, status.Errorf(codes.NotFound, "загрузка не найдена")
+		}
+		slog.Error("failed to get upload", "upload", uploadID, "error", err)
+		return nil, status.Errorf(codes.Internal, "ошибка получения загрузки")
+	}
+	if upload.UserID != userID || !time.Now().Before(upload.ExpiresAt) {
+		return nil, status.Errorf(codes.NotFound, "загрузка не найдена")
+	}
+
+	return upload, nil
+}
+
+// claimUpload claims uploadID for completion or abort.
+func (s *ServerAdmin) claimUpload(ctx context.Context, uploadID string) error {
+	if err := s.Storage.ClaimUpload(ctx, uploadID); err != nil {
+		if errors.Is(err, models.ErrUploadClaimed) {
+			return status.Errorf(codes.FailedPrecondition, "загрузка уже завершается")
+		}
+		slog.Error("failed to claim upload", "upload", uploadID, "error", err)
+		return status.Errorf(codes.Internal, "ошибка завершения загрузки")
+	}
+	return nil
+}
+
+// releaseUpload reopens a claimed upload after a failure, logging failures; the upload then
+// stays claimed until the cleanup job removes it once it expires.
+func (s *ServerAdmin) releaseUpload(ctx context.Context, uploadID string) {
+	if err := s.Storage.ReleaseUpload(ctx, uploadID); err != nil {
+		slog.Error("failed to release upload", "upload", uploadID, "error", err)
+	}
+}
+
+// abortMultipartUpload aborts the multipart upload of upload, logging failures; the cleanup job
+// does not know about uploads that were never saved.
+func (s *ServerAdmin) abortMultipartUpload(ctx context.Context, upload *models.Upload) {
+	if err := s.StorageS3.AbortMultipartUpload(ctx, upload.ObjectName, upload.MultipartID); err != nil {
+		slog.Error("failed to abort multipart upload", "object", upload.ObjectName, "error", err)
+	}
+}
+
+// deleteUpload deletes a finished upload, logging failures; the cleanup job removes it once it expires.
+func (s *ServerAdmin) deleteUpload(ctx context.Context, uploadID string) {
+	if err := s.Storage.DeleteUpload(ctx, uploadID); err != nil && !errors.Is(err, models.ErrUploadNotFound) {
+		slog.Error("failed to delete upload", "upload", uploadID, "error", err)
+	}
+}
+
+// newUploadID returns a random upload ID.
+func newUploadID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate upload ID: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
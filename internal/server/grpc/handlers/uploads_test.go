@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
+	"github.com/apetsko/gophkeeper/internal/crypto"
+	"github.com/apetsko/gophkeeper/internal/mocks"
+	"github.com/apetsko/gophkeeper/models"
+	pbmodels "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+func testUpload(userID int, size, received int64) *models.Upload {
+	return &models.Upload{
+		ID:           "upload-1",
+		UserID:       userID,
+		ObjectName:   "1-scan.pdf",
+		MultipartID:  "mp-1",
+		FileName:     "scan.pdf",
+		Meta:         "{}",
+		Size:         size,
+		Received:     received,
+		EncryptedDek: []byte("dek"),
+		DekNonce:     []byte("nonce"),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+}
+
+func TestServerAdmin_CreateUpload(t *testing.T) {
+	userID := 1
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	key := bytes.Repeat([]byte{1}, 32)
+
+	tests := []struct {
+		name       string
+		req        *pbrpc.CreateUploadRequest
+		setupMocks func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+	}{
+		{
+			name: "success",
+			req:  &pbrpc.CreateUploadRequest{Name: "scan.pdf", Type: "application/pdf", Size: 100, Meta: &pbmodels.Meta{Content: "passport"}},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				sealer, err := crypto.NewStreamSealer(key)
+				require.NoError(t, err)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("NewStreamSealer", mock.Anything, []byte("mk")).
+					Return(sealer, &models.EncryptedData{EncryptedDek: []byte("dek"), DekNonce: []byte("nonce")}, nil)
+				s3.On("CreateMultipartUpload", mock.Anything, mock.MatchedBy(func(d *models.S3UploadData) bool {
+					return d.FileName == "scan.pdf" && d.FileType == "application/pdf" && d.MetaContent == "passport"
+				})).Return("mp-1", nil)
+				st.On("CreateUpload", mock.Anything, mock.MatchedBy(func(u *models.Upload) bool {
+					return u.UserID == userID && u.MultipartID == "mp-1" && u.Size == 100 && len(u.ID) == 32 &&
+						bytes.Equal(u.StreamHeader, sealer.Header()) && string(u.EncryptedDek) == "dek"
+				})).Return(nil)
+			},
+		},
+		{
+			name:     "empty file",
+			req:      &pbrpc.CreateUploadRequest{Name: "scan.pdf"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "too large",
+			req:      &pbrpc.CreateUploadRequest{Name: "scan.pdf", Size: MaxUploadSize + 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "zero-knowledge account",
+			req:  &pbrpc.CreateUploadRequest{Name: "scan.pdf", Size: 100},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				km.On("GetMasterKey", mock.Anything, userID).Return(nil, models.ErrMasterKeyNotFound)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "save error aborts multipart upload",
+			req:  &pbrpc.CreateUploadRequest{Name: "scan.pdf", Size: 100},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				sealer, err := crypto.NewStreamSealer(key)
+				require.NoError(t, err)
+				km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+				env.On("NewStreamSealer", mock.Anything, []byte("mk")).Return(sealer, &models.EncryptedData{}, nil)
+				s3.On("CreateMultipartUpload", mock.Anything, mock.Anything).Return("mp-1", nil)
+				st.On("CreateUpload", mock.Anything, mock.Anything).Return(errors.New("db error"))
+				s3.On("AbortMultipartUpload", mock.Anything, mock.Anything, "mp-1").Return(nil)
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			s3 := mocks.NewS3Client(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(st, s3, env, km)
+			}
+			s := NewServerAdmin(st, s3, config.JWTConfig{}, env, km)
+
+			resp, err := s.CreateUpload(ctx, tt.req)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, resp.GetUploadId(), 32)
+			require.Equal(t, int64(UploadChunkSize), resp.GetChunkSize())
+			require.NotEmpty(t, resp.GetExpiresAt())
+		})
+	}
+}
+
+func TestServerAdmin_UploadChunk(t *testing.T) {
+	userID := 1
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	key := bytes.Repeat([]byte{1}, 32)
+	sealer, err := crypto.NewStreamSealer(key)
+	require.NoError(t, err)
+
+	size := int64(UploadChunkSize + 10)
+	plaintext := bytes.Repeat([]byte("s"), int(size))
+	first, last := plaintext[:UploadChunkSize], plaintext[UploadChunkSize:]
+
+	withSealer := func(upload *models.Upload) *models.Upload {
+		upload.StreamHeader = sealer.Header()
+		return upload
+	}
+	expectSealing := func(env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+		km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+		env.On("OpenStreamSealer", mock.Anything, mock.Anything, []byte("mk"), sealer.Header()).Return(sealer, nil)
+	}
+	digest := func(data []byte) []byte {
+		sum := sha256.Sum256(data)
+		return sum[:]
+	}
+
+	tests := []struct {
+		name       string
+		req        *pbrpc.UploadChunkRequest
+		setupMocks func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface)
+		wantCode   codes.Code
+		wantOffset int64
+	}{
+		{
+			name: "first chunk",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(withSealer(testUpload(userID, size, 0)), nil)
+				st.On("ReserveUploadPart", mock.Anything, "upload-1", 1, digest(first)).Return(digest(first), nil)
+				expectSealing(env, km)
+				s3.On("UploadPart", mock.Anything, "1-scan.pdf", "mp-1", 1, mock.Anything).Return("etag-1", nil)
+				st.On("CommitUploadPart", mock.Anything, "upload-1", 1, "etag-1", int64(0), int64(UploadChunkSize), mock.Anything).
+					Return(nil)
+			},
+			wantOffset: UploadChunkSize,
+		},
+		{
+			name: "last chunk",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Offset: UploadChunkSize, Data: last},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(withSealer(testUpload(userID, size, UploadChunkSize)), nil)
+				st.On("ReserveUploadPart", mock.Anything, "upload-1", 2, digest(last)).Return(digest(last), nil)
+				expectSealing(env, km)
+				s3.On("UploadPart", mock.Anything, "1-scan.pdf", "mp-1", 2, mock.Anything).Return("etag-2", nil)
+				st.On("CommitUploadPart", mock.Anything, "upload-1", 2, "etag-2", int64(UploadChunkSize), size, mock.Anything).
+					Return(nil)
+			},
+			wantOffset: size,
+		},
+		{
+			name: "offset already received",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, size, UploadChunkSize), nil)
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name: "partial chunk before the end",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Data: last},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, size, 0), nil)
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "chunk past the end",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Offset: UploadChunkSize, Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, size, UploadChunkSize), nil)
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "different data for a sealed chunk",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Offset: UploadChunkSize, Data: []byte("different!")},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, size, UploadChunkSize), nil)
+				st.On("ReserveUploadPart", mock.Anything, "upload-1", 2, mock.Anything).Return(digest(last), nil)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "concurrent chunk",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(withSealer(testUpload(userID, size, 0)), nil)
+				st.On("ReserveUploadPart", mock.Anything, "upload-1", 1, digest(first)).Return(digest(first), nil)
+				expectSealing(env, km)
+				s3.On("UploadPart", mock.Anything, "1-scan.pdf", "mp-1", 1, mock.Anything).Return("etag-1", nil)
+				st.On("CommitUploadPart", mock.Anything, "upload-1", 1, "etag-1", int64(0), int64(UploadChunkSize), mock.Anything).
+					Return(models.ErrUploadOffsetMismatch)
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name: "upload of another user",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID+1, size, 0), nil)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "expired upload",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "upload-1", Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				upload := testUpload(userID, size, 0)
+				upload.ExpiresAt = time.Now().Add(-time.Minute)
+				st.On("GetUpload", mock.Anything, "upload-1").Return(upload, nil)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "unknown upload",
+			req:  &pbrpc.UploadChunkRequest{UploadId: "missing", Data: first},
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client, env *mocks.IEnvelope, km *mocks.KeyManagerInterface) {
+				st.On("GetUpload", mock.Anything, "missing").Return(nil, models.ErrUploadNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			s3 := mocks.NewS3Client(t)
+			env := mocks.NewIEnvelope(t)
+			km := mocks.NewKeyManagerInterface(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(st, s3, env, km)
+			}
+			s := NewServerAdmin(st, s3, config.JWTConfig{}, env, km)
+
+			resp, err := s.UploadChunk(ctx, tt.req)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOffset, resp.GetOffset())
+		})
+	}
+}
+
+func TestServerAdmin_UploadChunk_PartsFormOneStream(t *testing.T) {
+	userID := 1
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	key := bytes.Repeat([]byte{1}, 32)
+	sealer, err := crypto.NewStreamSealer(key)
+	require.NoError(t, err)
+
+	plaintext := bytes.Repeat([]byte("field scan "), UploadChunkSize/10)
+	upload := testUpload(userID, int64(len(plaintext)), 0)
+	upload.StreamHeader = sealer.Header()
+
+	st := mocks.NewIStorage(t)
+	s3 := mocks.NewS3Client(t)
+	env := mocks.NewIEnvelope(t)
+	km := mocks.NewKeyManagerInterface(t)
+	st.On("GetUpload", mock.Anything, "upload-1").Return(func(context.Context, string) (*models.Upload, error) {
+		copied := *upload
+		return &copied, nil
+	})
+	st.On("ReserveUploadPart", mock.Anything, "upload-1", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ string, _ int, digest []byte) ([]byte, error) { return digest, nil })
+	st.On("CommitUploadPart", mock.Anything, "upload-1", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _ string, _ int, _ string, _, received int64, _ time.Time) error {
+			upload.Received = received
+			return nil
+		})
+	km.On("GetMasterKey", mock.Anything, userID).Return([]byte("mk"), nil)
+	env.On("OpenStreamSealer", mock.Anything, mock.Anything, []byte("mk"), sealer.Header()).Return(sealer, nil)
+	var stored bytes.Buffer
+	s3.On("UploadPart", mock.Anything, "1-scan.pdf", "mp-1", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, _, _ string, _ int, part []byte) (string, error) {
+			stored.Write(part)
+			return "etag", nil
+		})
+	s := NewServerAdmin(st, s3, config.JWTConfig{}, env, km)
+
+	for off := 0; off < len(plaintext); off += UploadChunkSize {
+		chunk := plaintext[off:min(off+UploadChunkSize, len(plaintext))]
+		_, err = s.UploadChunk(ctx, &pbrpc.UploadChunkRequest{UploadId: "upload-1", Offset: int64(off), Data: chunk})
+		require.NoError(t, err)
+	}
+
+	r, err := crypto.NewStreamReader(bytes.NewReader(stored.Bytes()), key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+}
+
+func TestServerAdmin_GetUpload(t *testing.T) {
+	userID := 1
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	st := mocks.NewIStorage(t)
+	st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 40), nil)
+	s := NewServerAdmin(st, nil, config.JWTConfig{}, nil, nil)
+
+	resp, err := s.GetUpload(ctx, &pbrpc.GetUploadRequest{UploadId: "upload-1"})
+	require.NoError(t, err)
+	require.Equal(t, "scan.pdf", resp.GetName())
+	require.Equal(t, int64(100), resp.GetSize())
+	require.Equal(t, int64(40), resp.GetOffset())
+	require.Equal(t, int64(UploadChunkSize), resp.GetChunkSize())
+}
+
+func TestServerAdmin_CompleteUpload(t *testing.T) {
+	userID := 1
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+	parts := []models.UploadPart{{Number: 1, ETag: "etag-1"}}
+
+	tests := []struct {
+		name       string
+		setupMocks func(st *mocks.IStorage, s3 *mocks.S3Client)
+		wantCode   codes.Code
+	}{
+		{
+			name: "success",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 100), nil)
+				st.On("ClaimUpload", mock.Anything, "upload-1").Return(nil)
+				st.On("ListUploadParts", mock.Anything, "upload-1").Return(parts, nil)
+				s3.On("CompleteMultipartUpload", mock.Anything, "1-scan.pdf", "mp-1", parts).Return(nil)
+				st.On("SaveUserData", mock.Anything, mock.MatchedBy(func(d *models.DBUserData) bool {
+					return d.UserID == userID && d.Type == constants.BinaryData && d.MinioObjectID == "1-scan.pdf" &&
+						len(d.DataNonce) == 0 && string(d.EncryptedDek) == "dek"
+				})).Return(7, nil)
+				st.On("DeleteUpload", mock.Anything, "upload-1").Return(nil)
+			},
+		},
+		{
+			name: "not all data received",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 40), nil)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "claimed by a concurrent call",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 100), nil)
+				st.On("ClaimUpload", mock.Anything, "upload-1").Return(models.ErrUploadClaimed)
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "complete error releases the upload",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 100), nil)
+				st.On("ClaimUpload", mock.Anything, "upload-1").Return(nil)
+				st.On("ListUploadParts", mock.Anything, "upload-1").Return(parts, nil)
+				s3.On("CompleteMultipartUpload", mock.Anything, "1-scan.pdf", "mp-1", parts).Return(errors.New("s3 error"))
+				st.On("ReleaseUpload", mock.Anything, "upload-1").Return(nil)
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "save error discards the object",
+			setupMocks: func(st *mocks.IStorage, s3 *mocks.S3Client) {
+				st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 100), nil)
+				st.On("ClaimUpload", mock.Anything, "upload-1").Return(nil)
+				st.On("ListUploadParts", mock.Anything, "upload-1").Return(parts, nil)
+				s3.On("CompleteMultipartUpload", mock.Anything, "1-scan.pdf", "mp-1", parts).Return(nil)
+				st.On("SaveUserData", mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+				s3.On("Delete", mock.Anything, "1-scan.pdf").Return(nil)
+				st.On("DeleteUpload", mock.Anything, "upload-1").Return(nil)
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			s3 := mocks.NewS3Client(t)
+			tt.setupMocks(st, s3)
+			s := NewServerAdmin(st, s3, config.JWTConfig{}, nil, nil)
+
+			resp, err := s.CompleteUpload(ctx, &pbrpc.CompleteUploadRequest{UploadId: "upload-1"})
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int32(7), resp.GetId())
+			require.Equal(t, int64(100), resp.GetSize())
+		})
+	}
+}
+
+func TestServerAdmin_AbortUpload(t *testing.T) {
+	userID := 1
+	ctx := context.WithValue(context.Background(), constants.UserID, userID)
+
+	st := mocks.NewIStorage(t)
+	s3 := mocks.NewS3Client(t)
+	st.On("GetUpload", mock.Anything, "upload-1").Return(testUpload(userID, 100, 40), nil)
+	st.On("ClaimUpload", mock.Anything, "upload-1").Return(nil)
+	s3.On("AbortMultipartUpload", mock.Anything, "1-scan.pdf", "mp-1").Return(nil)
+	st.On("DeleteUpload", mock.Anything, "upload-1").Return(nil)
+	s := NewServerAdmin(st, s3, config.JWTConfig{}, nil, nil)
+
+	_, err := s.AbortUpload(ctx, &pbrpc.AbortUploadRequest{UploadId: "upload-1"})
+	require.NoError(t, err)
+}
@@ -6,12 +6,11 @@ package grpc
 
 import (
 	"context"
-	"errors"
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
 	"net"
 
-	"github.com/golang-jwt/jwt/v5"
 	grpcLogging "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -24,9 +23,11 @@ import (
 	"github.com/apetsko/gophkeeper/config"
 	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
+	"github.com/apetsko/gophkeeper/pkg/jwt"
 	"github.com/apetsko/gophkeeper/pkg/logging"
 	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	pbrpca "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
 	pbrpcu "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
 )
 
@@ -69,6 +70,22 @@ func (s *GRPCHandler) Ping(ctx context.Context, in *pbrpc.PingRequest) (*pbrpc.P
 	return s.ServerAdmin.Ping(ctx, in)
 }
 
+// PreLogin handles the gRPC request for the authentication parameters of a user.
+//
+// Zero-knowledge clients call it before Login to fetch the KDF parameters needed to derive
+// their keys from the password.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The PreLoginRequest message with the username.
+//
+// Returns:
+//   - *pbrpcu.PreLoginResponse: The encryption mode and KDF parameters.
+//   - error: An error if the lookup fails.
+func (s *GRPCHandler) PreLogin(ctx context.Context, in *pbrpcu.PreLoginRequest) (*pbrpcu.PreLoginResponse, error) {
+	return s.ServerAdmin.PreLogin(ctx, in)
+}
+
 // Login handles the gRPC request for user authentication.
 //
 // This method validates the username and password, checks credentials against the database,
@@ -89,6 +106,151 @@ func (s *GRPCHandler) Signup(ctx context.Context, in *pbrpcu.SignupRequest) (*pb
 	return s.ServerAdmin.Signup(ctx, in)
 }
 
+// ChangePassword handles the gRPC request to change the authenticated user's password.
+//
+// This method verifies the old password and re-wraps the existing master key for the new one,
+// so stored records stay readable.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ChangePasswordRequest message with the old and new passwords.
+//
+// Returns:
+//   - *pbrpcu.ChangePasswordResponse: A response indicating success.
+//   - error: A gRPC error if the old password is wrong or the update fails.
+func (s *GRPCHandler) ChangePassword(
+	ctx context.Context,
+	in *pbrpcu.ChangePasswordRequest,
+) (*pbrpcu.ChangePasswordResponse, error) {
+	return s.ServerAdmin.ChangePassword(ctx, in)
+}
+
+// LoginTOTP handles the gRPC request completing a login with a second factor.
+//
+// This method exchanges the two-factor token issued by Login and a TOTP or recovery code
+// for the regular authentication token.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The LoginTOTPRequest message with the two-factor token and the code.
+//
+// Returns:
+//   - *pbrpcu.LoginResponse: User details and authentication token.
+//   - error: A gRPC error if the token or the code is invalid.
+func (s *GRPCHandler) LoginTOTP(ctx context.Context, in *pbrpcu.LoginTOTPRequest) (*pbrpcu.LoginResponse, error) {
+	return s.ServerAdmin.LoginTOTP(ctx, in)
+}
+
+// Refresh handles the gRPC request exchanging a refresh token for new access and refresh tokens.
+//
+// A refresh token that was already exchanged revokes its whole session.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RefreshRequest message with the refresh token.
+//
+// Returns:
+//   - *pbrpcu.RefreshResponse: The new access and refresh tokens.
+//   - error: A gRPC error if the refresh token is invalid, expired, revoked or reused.
+func (s *GRPCHandler) Refresh(ctx context.Context, in *pbrpcu.RefreshRequest) (*pbrpcu.RefreshResponse, error) {
+	return s.ServerAdmin.Refresh(ctx, in)
+}
+
+// Logout handles the gRPC request revoking the session of the presented access token.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The LogoutRequest message.
+//
+// Returns:
+//   - *pbrpcu.LogoutResponse: A response indicating success.
+//   - error: A gRPC error if the session cannot be revoked.
+func (s *GRPCHandler) Logout(ctx context.Context, in *pbrpcu.LogoutRequest) (*pbrpcu.LogoutResponse, error) {
+	return s.ServerAdmin.Logout(ctx, in)
+}
+
+// ListSessions handles the gRPC request for the active sessions of the authenticated user.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListSessionsRequest message.
+//
+// Returns:
+//   - *pbrpcu.ListSessionsResponse: The sessions with device, client version, IP and last-seen time.
+//   - error: A gRPC error if the sessions cannot be read.
+func (s *GRPCHandler) ListSessions(ctx context.Context, in *pbrpcu.ListSessionsRequest) (*pbrpcu.ListSessionsResponse, error) {
+	return s.ServerAdmin.ListSessions(ctx, in)
+}
+
+// RevokeSession handles the gRPC request ending one session of the authenticated user.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RevokeSessionRequest message with the session ID.
+//
+// Returns:
+//   - *pbrpcu.RevokeSessionResponse: A response indicating success.
+//   - error: A gRPC error if the user has no such active session.
+func (s *GRPCHandler) RevokeSession(ctx context.Context, in *pbrpcu.RevokeSessionRequest) (*pbrpcu.RevokeSessionResponse, error) {
+	return s.ServerAdmin.RevokeSession(ctx, in)
+}
+
+// RevokeAllOtherSessions handles the gRPC request ending all sessions of the authenticated
+// user except the current one.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RevokeAllOtherSessionsRequest message.
+//
+// Returns:
+//   - *pbrpcu.RevokeAllOtherSessionsResponse: The number of revoked sessions.
+//   - error: A gRPC error if the sessions cannot be revoked.
+func (s *GRPCHandler) RevokeAllOtherSessions(
+	ctx context.Context,
+	in *pbrpcu.RevokeAllOtherSessionsRequest,
+) (*pbrpcu.RevokeAllOtherSessionsResponse, error) {
+	return s.ServerAdmin.RevokeAllOtherSessions(ctx, in)
+}
+
+// EnrollTOTP handles the gRPC request to start TOTP enrollment for the authenticated user.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The EnrollTOTPRequest message.
+//
+// Returns:
+//   - *pbrpcu.EnrollTOTPResponse: The otpauth URI and the secret for the authenticator app.
+//   - error: A gRPC error if two-factor authentication is already enabled.
+func (s *GRPCHandler) EnrollTOTP(ctx context.Context, in *pbrpcu.EnrollTOTPRequest) (*pbrpcu.EnrollTOTPResponse, error) {
+	return s.ServerAdmin.EnrollTOTP(ctx, in)
+}
+
+// ConfirmTOTP handles the gRPC request enabling TOTP with the first code from the authenticator app.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ConfirmTOTPRequest message with the code.
+//
+// Returns:
+//   - *pbrpcu.ConfirmTOTPResponse: One-time recovery codes.
+//   - error: A gRPC error if the code is invalid.
+func (s *GRPCHandler) ConfirmTOTP(ctx context.Context, in *pbrpcu.ConfirmTOTPRequest) (*pbrpcu.ConfirmTOTPResponse, error) {
+	return s.ServerAdmin.ConfirmTOTP(ctx, in)
+}
+
+// DisableTOTP handles the gRPC request to turn off two-factor authentication.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The DisableTOTPRequest message with the password and a TOTP or recovery code.
+//
+// Returns:
+//   - *pbrpcu.DisableTOTPResponse: A response indicating success.
+//   - error: A gRPC error if the password or the code is wrong.
+func (s *GRPCHandler) DisableTOTP(ctx context.Context, in *pbrpcu.DisableTOTPRequest) (*pbrpcu.DisableTOTPResponse, error) {
+	return s.ServerAdmin.DisableTOTP(ctx, in)
+}
+
 // DataList handles the gRPC request to list all user data records.
 //
 // This method checks user authorization and retrieves a list of data records
@@ -121,6 +283,21 @@ func (s *GRPCHandler) DataSave(ctx context.Context, in *pbrpc.DataSaveRequest) (
 	return s.ServerAdmin.DataSave(ctx, in)
 }
 
+// DataUpdate handles the gRPC request to edit an existing user data record in place.
+//
+// The record keeps its ID, type and creation time; the new payload is encrypted with a fresh DEK.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The DataUpdateRequest message with the record ID and the new data.
+//
+// Returns:
+//   - *pbrpc.DataUpdateResponse: Confirmation of the update.
+//   - error: A gRPC error if the record belongs to another user or the update fails.
+func (s *GRPCHandler) DataUpdate(ctx context.Context, in *pbrpc.DataUpdateRequest) (*pbrpc.DataUpdateResponse, error) {
+	return s.ServerAdmin.DataUpdate(ctx, in)
+}
+
 // DataDelete handles the gRPC request to delete a user's data record.
 //
 // This method checks user authorization, verifies ownership of the data record,
@@ -153,6 +330,353 @@ func (s *GRPCHandler) DataView(ctx context.Context, in *pbrpc.DataViewRequest) (
 	return s.ServerAdmin.DataView(ctx, in)
 }
 
+// Search handles the gRPC request to find the user's records by their metadata.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The SearchRequest message with the query, types and number of results.
+//
+// Returns:
+//   - *pbrpc.SearchResponse: The matched records with their highlighted fields, best match first.
+//   - error: A gRPC error if the query is invalid or the search fails.
+func (s *GRPCHandler) Search(ctx context.Context, in *pbrpc.SearchRequest) (*pbrpc.SearchResponse, error) {
+	return s.ServerAdmin.Search(ctx, in)
+}
+
+// ListTrash handles the gRPC request listing the records the user has deleted.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListTrashRequest message.
+//
+// Returns:
+//   - *pbrpc.ListTrashResponse: The trashed records with the time each one is purged at.
+//   - error: A gRPC error if the query fails.
+func (s *GRPCHandler) ListTrash(ctx context.Context, in *pbrpc.ListTrashRequest) (*pbrpc.ListTrashResponse, error) {
+	return s.ServerAdmin.ListTrash(ctx, in)
+}
+
+// RestoreFromTrash handles the gRPC request to take a deleted record out of the trash.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RestoreFromTrashRequest message with the record ID.
+//
+// Returns:
+//   - *pbrpc.RestoreFromTrashResponse: Confirmation of the restore.
+//   - error: A gRPC error if the user has no such record in the trash.
+func (s *GRPCHandler) RestoreFromTrash(
+	ctx context.Context,
+	in *pbrpc.RestoreFromTrashRequest,
+) (*pbrpc.RestoreFromTrashResponse, error) {
+	return s.ServerAdmin.RestoreFromTrash(ctx, in)
+}
+
+// EmptyTrash handles the gRPC request to permanently delete all records in the user's trash.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The EmptyTrashRequest message.
+//
+// Returns:
+//   - *pbrpc.EmptyTrashResponse: The number of deleted records.
+//   - error: A gRPC error if the deletion fails.
+func (s *GRPCHandler) EmptyTrash(ctx context.Context, in *pbrpc.EmptyTrashRequest) (*pbrpc.EmptyTrashResponse, error) {
+	return s.ServerAdmin.EmptyTrash(ctx, in)
+}
+
+// CreateFolder handles the gRPC request to create a folder.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The CreateFolderRequest message with the parent folder and the name.
+//
+// Returns:
+//   - *pbrpc.CreateFolderResponse: The new folder.
+//   - error: A gRPC error if the name is invalid, the parent is not found or storage fails.
+func (s *GRPCHandler) CreateFolder(ctx context.Context, in *pbrpc.CreateFolderRequest) (*pbrpc.CreateFolderResponse, error) {
+	return s.ServerAdmin.CreateFolder(ctx, in)
+}
+
+// ListFolders handles the gRPC request listing the folders of the authenticated user.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListFoldersRequest message.
+//
+// Returns:
+//   - *pbrpc.ListFoldersResponse: The folders, oldest first.
+//   - error: A gRPC error if the query or decryption fails.
+func (s *GRPCHandler) ListFolders(ctx context.Context, in *pbrpc.ListFoldersRequest) (*pbrpc.ListFoldersResponse, error) {
+	return s.ServerAdmin.ListFolders(ctx, in)
+}
+
+// RenameFolder handles the gRPC request to rename a folder.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RenameFolderRequest message with the folder ID and the new name.
+//
+// Returns:
+//   - *pbrpc.RenameFolderResponse: A response indicating success.
+//   - error: A gRPC error if the name is invalid, the folder is not found or storage fails.
+func (s *GRPCHandler) RenameFolder(ctx context.Context, in *pbrpc.RenameFolderRequest) (*pbrpc.RenameFolderResponse, error) {
+	return s.ServerAdmin.RenameFolder(ctx, in)
+}
+
+// MoveFolder handles the gRPC request to move a folder into another folder or to the root.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The MoveFolderRequest message with the folder ID and the new parent.
+//
+// Returns:
+//   - *pbrpc.MoveFolderResponse: A response indicating success.
+//   - error: A gRPC error if a folder is not found or the move would nest the folder in itself.
+func (s *GRPCHandler) MoveFolder(ctx context.Context, in *pbrpc.MoveFolderRequest) (*pbrpc.MoveFolderResponse, error) {
+	return s.ServerAdmin.MoveFolder(ctx, in)
+}
+
+// DeleteFolder handles the gRPC request to delete a folder, moving its contents to its parent.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The DeleteFolderRequest message with the folder ID.
+//
+// Returns:
+//   - *pbrpc.DeleteFolderResponse: A response indicating success.
+//   - error: A gRPC error if the folder is not found or storage fails.
+func (s *GRPCHandler) DeleteFolder(ctx context.Context, in *pbrpc.DeleteFolderRequest) (*pbrpc.DeleteFolderResponse, error) {
+	return s.ServerAdmin.DeleteFolder(ctx, in)
+}
+
+// MoveRecords handles the gRPC request to move records into a folder or out of any folder.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The MoveRecordsRequest message with the record IDs and the target folder.
+//
+// Returns:
+//   - *pbrpc.MoveRecordsResponse: A response indicating success.
+//   - error: A gRPC error if the folder or a record is not found or storage fails.
+func (s *GRPCHandler) MoveRecords(ctx context.Context, in *pbrpc.MoveRecordsRequest) (*pbrpc.MoveRecordsResponse, error) {
+	return s.ServerAdmin.MoveRecords(ctx, in)
+}
+
+// GenerateOTP handles the gRPC request for the current and next codes of an OTP record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The GenerateOTPRequest message with the record ID.
+//
+// Returns:
+//   - *pbrpc.GenerateOTPResponse: The codes and how long the current one is valid.
+//   - error: A gRPC error if access is denied or the record is not a server-encrypted OTP.
+func (s *GRPCHandler) GenerateOTP(ctx context.Context, in *pbrpc.GenerateOTPRequest) (*pbrpc.GenerateOTPResponse, error) {
+	return s.ServerAdmin.GenerateOTP(ctx, in)
+}
+
+// ListRecordVersions handles the gRPC request listing the prior versions of a user data record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ListRecordVersionsRequest message with the record ID.
+//
+// Returns:
+//   - *pbrpc.ListRecordVersionsResponse: The prior versions, newest first.
+//   - error: A gRPC error if the record belongs to another user or the query fails.
+func (s *GRPCHandler) ListRecordVersions(
+	ctx context.Context,
+	in *pbrpc.ListRecordVersionsRequest,
+) (*pbrpc.ListRecordVersionsResponse, error) {
+	return s.ServerAdmin.ListRecordVersions(ctx, in)
+}
+
+// ViewRecordVersion handles the gRPC request to retrieve the contents of a prior version of a record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ViewRecordVersionRequest message with the record ID and the version number.
+//
+// Returns:
+//   - *pbrpc.DataViewResponse: The contents of the version.
+//   - error: A gRPC error if the record belongs to another user or the version does not exist.
+func (s *GRPCHandler) ViewRecordVersion(ctx context.Context, in *pbrpc.ViewRecordVersionRequest) (*pbrpc.DataViewResponse, error) {
+	return s.ServerAdmin.ViewRecordVersion(ctx, in)
+}
+
+// RestoreRecordVersion handles the gRPC request making a prior version the current contents of a record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The RestoreRecordVersionRequest message with the record ID and the version number.
+//
+// Returns:
+//   - *pbrpc.RestoreRecordVersionResponse: The version number of the restored contents.
+//   - error: A gRPC error if the record belongs to another user or the version does not exist.
+func (s *GRPCHandler) RestoreRecordVersion(
+	ctx context.Context,
+	in *pbrpc.RestoreRecordVersionRequest,
+) (*pbrpc.RestoreRecordVersionResponse, error) {
+	return s.ServerAdmin.RestoreRecordVersion(ctx, in)
+}
+
+// SetVersionRetention handles the gRPC request setting how many prior versions are kept per record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The SetVersionRetentionRequest message; 0 selects the server default.
+//
+// Returns:
+//   - *pbrpc.SetVersionRetentionResponse: The retention in effect.
+//   - error: A gRPC error if the value is out of range or the update fails.
+func (s *GRPCHandler) SetVersionRetention(
+	ctx context.Context,
+	in *pbrpc.SetVersionRetentionRequest,
+) (*pbrpc.SetVersionRetentionResponse, error) {
+	return s.ServerAdmin.SetVersionRetention(ctx, in)
+}
+
+// UploadFile handles the client-streaming request that stores a file of any size.
+//
+// The file is encrypted and written to storage as its chunks arrive, so it is never held in memory.
+//
+// Parameters:
+//   - stream: The gRPC stream with the file header followed by its chunks.
+//
+// Returns:
+//   - error: A gRPC error if the header is missing or the upload fails.
+func (s *GRPCHandler) UploadFile(stream grpc.ClientStreamingServer[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse]) error {
+	return s.ServerAdmin.UploadFile(stream)
+}
+
+// DownloadFile handles the server-streaming request that returns a stored file in chunks.
+//
+// Parameters:
+//   - in: The DownloadFileRequest message with the record ID.
+//   - stream: The gRPC stream the file header and chunks are sent to.
+//
+// Returns:
+//   - error: A gRPC error if the record is not the user's file or cannot be read.
+func (s *GRPCHandler) DownloadFile(in *pbrpc.DownloadFileRequest, stream grpc.ServerStreamingServer[pbrpc.DownloadFileResponse]) error {
+	return s.ServerAdmin.DownloadFile(in, stream)
+}
+
+// CreateUpload handles the request to start a resumable upload of a file of known size.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The CreateUploadRequest message with the file name, type, size and meta.
+//
+// Returns:
+//   - *pbrpc.CreateUploadResponse: The upload ID, the chunk size and the expiry time.
+//   - error: A gRPC error if the request is invalid or the upload cannot be started.
+func (s *GRPCHandler) CreateUpload(ctx context.Context, in *pbrpc.CreateUploadRequest) (*pbrpc.CreateUploadResponse, error) {
+	return s.ServerAdmin.CreateUpload(ctx, in)
+}
+
+// UploadChunk handles the request that stores the next chunk of a resumable upload.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The UploadChunkRequest message with the upload ID, the offset and the chunk.
+//
+// Returns:
+//   - *pbrpc.UploadChunkResponse: The new received offset.
+//   - error: A gRPC error if the offset or the chunk size is wrong or the chunk cannot be stored.
+func (s *GRPCHandler) UploadChunk(ctx context.Context, in *pbrpc.UploadChunkRequest) (*pbrpc.UploadChunkResponse, error) {
+	return s.ServerAdmin.UploadChunk(ctx, in)
+}
+
+// GetUpload handles the request for the state of a resumable upload.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The GetUploadRequest message with the upload ID.
+//
+// Returns:
+//   - *pbrpc.GetUploadResponse: The received offset and the expiry time.
+//   - error: A gRPC error if the user has no such upload.
+func (s *GRPCHandler) GetUpload(ctx context.Context, in *pbrpc.GetUploadRequest) (*pbrpc.GetUploadResponse, error) {
+	return s.ServerAdmin.GetUpload(ctx, in)
+}
+
+// CompleteUpload handles the request that stores a fully received upload as a file record.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The CompleteUploadRequest message with the upload ID.
+//
+// Returns:
+//   - *pbrpc.CompleteUploadResponse: The ID of the new record.
+//   - error: A gRPC error if not all of the file was received or it cannot be stored.
+func (s *GRPCHandler) CompleteUpload(ctx context.Context, in *pbrpc.CompleteUploadRequest) (*pbrpc.CompleteUploadResponse, error) {
+	return s.ServerAdmin.CompleteUpload(ctx, in)
+}
+
+// AbortUpload handles the request that discards a resumable upload.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The AbortUploadRequest message with the upload ID.
+//
+// Returns:
+//   - *pbrpc.AbortUploadResponse: A confirmation message.
+//   - error: A gRPC error if the user has no such upload.
+func (s *GRPCHandler) AbortUpload(ctx context.Context, in *pbrpc.AbortUploadRequest) (*pbrpc.AbortUploadResponse, error) {
+	return s.ServerAdmin.AbortUpload(ctx, in)
+}
+
+// StartKeyRotation handles the administrative request to start a server key rotation.
+//
+// This method starts re-wrapping all master keys with the active server key in the background.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The StartKeyRotationRequest message.
+//
+// Returns:
+//   - *pbrpca.StartKeyRotationResponse: The started job.
+//   - error: A gRPC error if a rotation is already running or cannot be started.
+func (s *GRPCHandler) StartKeyRotation(
+	ctx context.Context,
+	in *pbrpca.StartKeyRotationRequest,
+) (*pbrpca.StartKeyRotationResponse, error) {
+	return s.ServerAdmin.StartKeyRotation(ctx, in)
+}
+
+// GetKeyRotation handles the administrative request for the progress of the latest key rotation.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The GetKeyRotationRequest message.
+//
+// Returns:
+//   - *pbrpca.GetKeyRotationResponse: The latest job.
+//   - error: A gRPC error if no rotation was started or the lookup fails.
+func (s *GRPCHandler) GetKeyRotation(
+	ctx context.Context,
+	in *pbrpca.GetKeyRotationRequest,
+) (*pbrpca.GetKeyRotationResponse, error) {
+	return s.ServerAdmin.GetKeyRotation(ctx, in)
+}
+
+// ReconcileObjects handles the administrative request to find orphaned and missing S3 objects.
+//
+// Parameters:
+//   - ctx: The gRPC context.
+//   - in: The ReconcileObjectsRequest message.
+//
+// Returns:
+//   - *pbrpca.ReconcileObjectsResponse: The reconciliation report.
+//   - error: A gRPC error if the reconciliation fails.
+func (s *GRPCHandler) ReconcileObjects(
+	ctx context.Context,
+	in *pbrpca.ReconcileObjectsRequest,
+) (*pbrpca.ReconcileObjectsResponse, error) {
+	return s.ServerAdmin.ReconcileObjects(ctx, in)
+}
+
 // RunGRPC starts the gRPC server for the GophKeeper service.
 //
 // This function configures the gRPC server with optional TLS, authentication middleware,
@@ -183,18 +707,69 @@ func RunGRPC(cfg *config.Config, sa *handlers.ServerAdmin, log *logging.Logger)
 		opts = append(opts, grpc.Creds(creds))
 	}
 
+	// Chunks of resumable uploads exceed the default 4 MiB message limit
+	opts = append(opts, grpc.MaxRecvMsgSize(handlers.MaxUploadChunkMessageSize))
+
+	protected := map[string]bool{
+		"/api.proto.v1.GophKeeper/DataList":               true,
+		"/api.proto.v1.GophKeeper/DataView":               true,
+		"/api.proto.v1.GophKeeper/Search":                 true,
+		"/api.proto.v1.GophKeeper/DataSave":               true,
+		"/api.proto.v1.GophKeeper/DataUpdate":             true,
+		"/api.proto.v1.GophKeeper/DataDelete":             true,
+		"/api.proto.v1.GophKeeper/ListTrash":              true,
+		"/api.proto.v1.GophKeeper/RestoreFromTrash":       true,
+		"/api.proto.v1.GophKeeper/EmptyTrash":             true,
+		"/api.proto.v1.GophKeeper/CreateFolder":           true,
+		"/api.proto.v1.GophKeeper/ListFolders":            true,
+		"/api.proto.v1.GophKeeper/RenameFolder":           true,
+		"/api.proto.v1.GophKeeper/MoveFolder":             true,
+		"/api.proto.v1.GophKeeper/DeleteFolder":           true,
+		"/api.proto.v1.GophKeeper/MoveRecords":            true,
+		"/api.proto.v1.GophKeeper/ListRecordVersions":     true,
+		"/api.proto.v1.GophKeeper/GenerateOTP":            true,
+		"/api.proto.v1.GophKeeper/ViewRecordVersion":      true,
+		"/api.proto.v1.GophKeeper/RestoreRecordVersion":   true,
+		"/api.proto.v1.GophKeeper/SetVersionRetention":    true,
+		"/api.proto.v1.GophKeeper/ChangePassword":         true,
+		"/api.proto.v1.GophKeeper/EnrollTOTP":             true,
+		"/api.proto.v1.GophKeeper/ConfirmTOTP":            true,
+		"/api.proto.v1.GophKeeper/DisableTOTP":            true,
+		"/api.proto.v1.GophKeeper/Logout":                 true,
+		"/api.proto.v1.GophKeeper/ListSessions":           true,
+		"/api.proto.v1.GophKeeper/RevokeSession":          true,
+		"/api.proto.v1.GophKeeper/RevokeAllOtherSessions": true,
+		"/api.proto.v1.GophKeeper/UploadFile":             true,
+		"/api.proto.v1.GophKeeper/DownloadFile":           true,
+		"/api.proto.v1.GophKeeper/CreateUpload":           true,
+		"/api.proto.v1.GophKeeper/UploadChunk":            true,
+		"/api.proto.v1.GophKeeper/GetUpload":              true,
+		"/api.proto.v1.GophKeeper/CompleteUpload":         true,
+		"/api.proto.v1.GophKeeper/AbortUpload":            true,
+	}
+
 	opts = append(opts, grpc.ChainUnaryInterceptor(
+		gatewayUnaryInterceptor(cfg.GatewayToken),
 		authUnaryInterceptor(
+			protected,
+			cfg.JWT.Secret,
+			sa.Storage,
+		),
+		adminUnaryInterceptor(
 			map[string]bool{
-				"/api.proto.v1.GophKeeper/DataList":   true,
-				"/api.proto.v1.GophKeeper/DataView":   true,
-				"/api.proto.v1.GophKeeper/DataSave":   true,
-				"/api.proto.v1.GophKeeper/DataDelete": true,
+				"/api.proto.v1.GophKeeper/StartKeyRotation": true,
+				"/api.proto.v1.GophKeeper/GetKeyRotation":   true,
+				"/api.proto.v1.GophKeeper/ReconcileObjects": true,
 			},
-			[]byte(cfg.JWT.Secret),
+			cfg.AdminToken,
 		),
 		grpcLogging.UnaryServerInterceptor(logging.InterceptorLogger(log)),
 	))
+	opts = append(opts, grpc.ChainStreamInterceptor(
+		gatewayStreamInterceptor(cfg.GatewayToken),
+		authStreamInterceptor(protected, cfg.JWT.Secret, sa.Storage),
+		grpcLogging.StreamServerInterceptor(logging.InterceptorLogger(log)),
+	))
 
 	srv := grpc.NewServer(opts...)
 
@@ -225,19 +800,27 @@ func RunGRPC(cfg *config.Config, sa *handlers.ServerAdmin, log *logging.Logger)
 	return srv, nil
 }
 
+// sessionChecker reports whether the session of an access token is still active and
+// records when it was last seen.
+type sessionChecker interface {
+	TouchSession(ctx context.Context, sessionID int, ip string) (bool, error)
+}
+
 // authUnaryInterceptor returns a gRPC unary server interceptor for JWT authentication.
 //
 // This interceptor checks if the called method requires authentication. For protected methods,
-// it extracts and validates the JWT from the request metadata, verifies the signing method and claims,
-// and injects the user ID and JWT into the context for downstream handlers.
+// it extracts and validates the access token from the request metadata, including its signing
+// method and expiry, checks that its session was not revoked, and injects the user ID, session ID
+// and JWT into the context for downstream handlers.
 //
 // Parameters:
 //   - protected: Map of gRPC method names that require authentication.
 //   - jwtSecret: Secret key used to validate JWT tokens.
+//   - sessions: Source of session revocation state and last-seen times.
 //
 // Returns:
 //   - grpc.UnaryServerInterceptor: The configured authentication interceptor.
-func authUnaryInterceptor(protected map[string]bool, jwtSecret []byte) grpc.UnaryServerInterceptor {
+func authUnaryInterceptor(protected map[string]bool, jwtSecret string, sessions sessionChecker) grpc.UnaryServerInterceptor {
 	slog.Info("Auth interceptor enabled")
 
 	return func(
@@ -250,39 +833,172 @@ func authUnaryInterceptor(protected map[string]bool, jwtSecret []byte) grpc.Unar
 			return handler(ctx, req)
 		}
 
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		ctx, err := authenticate(ctx, jwtSecret, sessions)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor returns a gRPC stream server interceptor for JWT authentication.
+//
+// It applies the same checks as authUnaryInterceptor to the protected streaming methods.
+//
+// Parameters:
+//   - protected: Map of gRPC method names that require authentication.
+//   - jwtSecret: Secret key used to validate JWT tokens.
+//   - sessions: Source of session revocation state and last-seen times.
+//
+// Returns:
+//   - grpc.StreamServerInterceptor: The configured authentication interceptor.
+func authStreamInterceptor(protected map[string]bool, jwtSecret string, sessions sessionChecker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !protected[info.FullMethod] {
+			return handler(srv, ss)
 		}
 
-		jwtHeader := md.Get(string(constants.JWT))
-		if len(jwtHeader) == 0 || jwtHeader[0] == "" {
-			return nil, status.Error(codes.Unauthenticated, "missing jwt")
+		ctx, err := authenticate(ss.Context(), jwtSecret, sessions)
+		if err != nil {
+			return err
 		}
 
-		tokenStr := jwtHeader[0]
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a server stream whose context carries the authenticated user or the
+// gateway mark.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the authenticated user.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate validates the access token in the metadata of ctx and checks that its session
+// is active. It returns ctx with the user ID, session ID and JWT of the token.
+func authenticate(ctx context.Context, jwtSecret string, sessions sessionChecker) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	jwtHeader := md.Get(string(constants.JWT))
+	if len(jwtHeader) == 0 || jwtHeader[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing jwt")
+	}
+
+	tokenStr := jwtHeader[0]
+
+	claims, err := jwt.ParseJWT(tokenStr, jwtSecret)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid jwt")
+	}
 
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return jwtSecret, nil
-		})
+	active, err := sessions.TouchSession(ctx, claims.SessionID, handlers.ClientIP(ctx))
+	if err != nil {
+		slog.Error("failed to check session: " + err.Error())
+		return nil, status.Error(codes.Internal, "failed to check session")
+	}
+	if !active {
+		return nil, status.Error(codes.Unauthenticated, "session revoked")
+	}
+
+	ctx = context.WithValue(ctx, constants.UserID, claims.UserID)
+	ctx = context.WithValue(ctx, constants.SessionID, claims.SessionID)
+	ctx = context.WithValue(ctx, constants.JWT, tokenStr)
+	return ctx, nil
+}
 
-		if err != nil || !token.Valid {
-			return nil, status.Error(codes.Unauthenticated, "invalid jwt")
+// gatewayUnaryInterceptor returns a gRPC unary server interceptor marking the requests of the
+// HTTP gateway.
+//
+// Requests whose "gateway-token" metadata matches gatewayToken get constants.Forwarded in their
+// context, so the client address the gateway forwards is trusted for them only.
+//
+// Parameters:
+//   - gatewayToken: The configured gateway token.
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The configured gateway interceptor.
+func gatewayUnaryInterceptor(gatewayToken string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(markForwarded(ctx, gatewayToken), req)
+	}
+}
+
+// gatewayStreamInterceptor returns a gRPC stream server interceptor marking the requests of the
+// HTTP gateway, like gatewayUnaryInterceptor.
+//
+// Parameters:
+//   - gatewayToken: The configured gateway token.
+//
+// Returns:
+//   - grpc.StreamServerInterceptor: The configured gateway interceptor.
+func gatewayStreamInterceptor(gatewayToken string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: markForwarded(ss.Context(), gatewayToken)})
+	}
+}
+
+// markForwarded returns ctx with constants.Forwarded set when its metadata carries gatewayToken.
+func markForwarded(ctx context.Context, gatewayToken string) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || gatewayToken == "" {
+		return ctx
+	}
+	token := md.Get(string(constants.GatewayToken))
+	if len(token) == 0 || subtle.ConstantTimeCompare([]byte(token[0]), []byte(gatewayToken)) != 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, constants.Forwarded, true)
+}
+
+// adminUnaryInterceptor returns a gRPC unary server interceptor guarding administrative methods.
+//
+// Administrative methods require the "admin-token" metadata to match the configured token.
+// When no token is configured, they are always denied.
+//
+// Parameters:
+//   - admin: Map of gRPC method names that require the admin token.
+//   - adminToken: The configured admin token.
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The configured admin interceptor.
+func adminUnaryInterceptor(admin map[string]bool, adminToken string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !admin[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		if adminToken == "" {
+			return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+		}
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing metadata")
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			if uidFloat, ok := claims["user_id"].(float64); ok {
-				userID := int(uidFloat)
-				ctx = context.WithValue(ctx, constants.UserID, userID)
-			} else {
-				return nil, status.Error(codes.InvalidArgument, "user_id not found or not a number")
-			}
+		token := md.Get(string(constants.AdminToken))
+		if len(token) == 0 || subtle.ConstantTimeCompare([]byte(token[0]), []byte(adminToken)) != 1 {
+			return nil, status.Error(codes.PermissionDenied, "invalid admin token")
 		}
 
-		ctx = context.WithValue(ctx, constants.JWT, tokenStr)
 		return handler(ctx, req)
 	}
 }
@@ -4,15 +4,22 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/internal/mocks"
 	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
+	"github.com/apetsko/gophkeeper/pkg/jwt"
 	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
 	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -72,3 +79,165 @@ func TestGRPCHandler_Protected_NoJWT(t *testing.T) {
 	_, err = client.DataList(ctx, &pbrpc.DataListRequest{})
 	require.Error(t, err)
 }
+
+func TestAdminUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.proto.v1.GophKeeper/StartKeyRotation"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	admin := map[string]bool{info.FullMethod: true}
+
+	tests := []struct {
+		name       string
+		adminToken string
+		md         metadata.MD
+		method     string
+		wantCode   codes.Code
+	}{
+		{name: "valid token", adminToken: "secret", md: metadata.Pairs("admin-token", "secret")},
+		{name: "wrong token", adminToken: "secret", md: metadata.Pairs("admin-token", "nope"), wantCode: codes.PermissionDenied},
+		{name: "missing token", adminToken: "secret", md: metadata.MD{}, wantCode: codes.PermissionDenied},
+		{name: "admin API disabled", md: metadata.Pairs("admin-token", ""), wantCode: codes.PermissionDenied},
+		{name: "not an admin method", method: "/api.proto.v1.GophKeeper/Ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := *info
+			if tt.method != "" {
+				i.FullMethod = tt.method
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			resp, err := adminUnaryInterceptor(admin, tt.adminToken)(ctx, nil, &i, handler)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "ok", resp)
+		})
+	}
+}
+
+func TestGatewayUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.proto.v1.GophKeeper/Login"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		forwarded, _ := ctx.Value(constants.Forwarded).(bool)
+		return forwarded, nil
+	}
+
+	tests := []struct {
+		name         string
+		gatewayToken string
+		md           metadata.MD
+		want         bool
+	}{
+		{name: "gateway", gatewayToken: "secret", md: metadata.Pairs("gateway-token", "secret"), want: true},
+		{name: "wrong token", gatewayToken: "secret", md: metadata.Pairs("gateway-token", "nope")},
+		{name: "direct call", gatewayToken: "secret", md: metadata.Pairs("x-forwarded-for", "203.0.113.5")},
+		{name: "no gateway token configured", md: metadata.Pairs("gateway-token", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			resp, err := gatewayUnaryInterceptor(tt.gatewayToken)(ctx, nil, info, handler)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	const secret = "testsecret"
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.proto.v1.GophKeeper/DataList"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if ctx.Value(constants.SessionID) != 7 {
+			return nil, status.Error(codes.Internal, "session ID not in context")
+		}
+		return "ok", nil
+	}
+
+	valid, err := jwt.GenerateJWT(42, "user", 7, secret, time.Minute)
+	require.NoError(t, err)
+	expired, err := jwt.GenerateJWT(42, "user", 7, secret, -time.Minute)
+	require.NoError(t, err)
+	pending, _, err := jwt.GenerateTwoFactorJWT(42, secret, time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		token    string
+		active   *bool
+		wantCode codes.Code
+	}{
+		{name: "valid token", token: valid, active: ptr(true)},
+		{name: "revoked session", token: valid, active: ptr(false), wantCode: codes.Unauthenticated},
+		{name: "expired token", token: expired, wantCode: codes.Unauthenticated},
+		{name: "two-factor token", token: pending, wantCode: codes.Unauthenticated},
+		{name: "missing token", wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := mocks.NewIStorage(t)
+			if tt.active != nil {
+				st.On("TouchSession", mock.Anything, 7, mock.Anything).Return(*tt.active, nil)
+			}
+			interceptor := authUnaryInterceptor(map[string]bool{info.FullMethod: true}, secret, st)
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("jwt", tt.token))
+
+			resp, err := interceptor(ctx, nil, info, handler)
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "ok", resp)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// fakeServerStream is a server stream carrying only a context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	const secret = "testsecret"
+	info := &grpc.StreamServerInfo{FullMethod: "/api.proto.v1.GophKeeper/UploadFile"}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		if ss.Context().Value(constants.UserID) != 42 {
+			return status.Error(codes.Internal, "user ID not in context")
+		}
+		return nil
+	}
+
+	valid, err := jwt.GenerateJWT(42, "user", 7, secret, time.Minute)
+	require.NoError(t, err)
+
+	st := mocks.NewIStorage(t)
+	st.On("TouchSession", mock.Anything, 7, mock.Anything).Return(true, nil).Once()
+	interceptor := authStreamInterceptor(map[string]bool{info.FullMethod: true}, secret, st)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("jwt", valid))
+	require.NoError(t, interceptor(nil, &fakeServerStream{ctx: ctx}, info, handler))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.MD{})
+	err = interceptor(nil, &fakeServerStream{ctx: ctx}, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Unprotected methods pass the stream through unchanged
+	unprotected := &grpc.StreamServerInfo{FullMethod: "/api.proto.v1.GophKeeper/Ping"}
+	err = interceptor(nil, &fakeServerStream{ctx: ctx}, unprotected, handler)
+	require.Equal(t, codes.Internal, status.Code(err))
+}
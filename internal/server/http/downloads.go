@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// downloadPattern is the path files are downloaded from.
+const downloadPattern = "/v1/files/{id}"
+
+// downloadHandler returns the handler of GET /v1/files/{id}, which sends the contents of a file
+// record as the response body instead of base64 inside JSON.
+//
+// The file is streamed from DownloadFile as it is decrypted. A single Range of bytes is served
+// as a partial response; other Range headers are ignored and the whole file is sent. Files of
+// zero-knowledge accounts cannot be decrypted by the server and are refused.
+//
+// Parameters:
+//   - mux: The gateway mux, whose metadata and error handling are reused.
+//   - client: The client of the gRPC backend.
+//
+// Returns:
+//   - runtime.HandlerFunc: The handler to register on mux.
+func downloadHandler(mux *runtime.ServeMux, client pb.GophKeeperClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pb.GophKeeper_DownloadFile_FullMethodName,
+			runtime.WithHTTPPathPattern(downloadPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		id, err := strconv.ParseInt(pathParams["id"], 10, 32)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Errorf(codes.InvalidArgument, "неверный ID записи"))
+			return
+		}
+		req := &pbrpc.DownloadFileRequest{Id: int32(id)}
+		var ranged bool
+		req.Offset, req.Length, ranged = parseRange(r.Header.Get("Range"))
+
+		stream, err := client.DownloadFile(ctx, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		first, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) == codes.OutOfRange {
+				err = &runtime.HTTPStatusError{HTTPStatus: http.StatusRequestedRangeNotSatisfiable, Err: err}
+			}
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		header := first.GetHeader()
+		switch {
+		case header == nil:
+			err = status.Errorf(codes.Internal, "не получен заголовок файла")
+		case header.GetEncrypted() != nil:
+			err = status.Errorf(codes.FailedPrecondition, "файл зашифрован на клиенте, скачайте его через клиент")
+		case ranged && header.GetLength() == 0:
+			err = &runtime.HTTPStatusError{
+				HTTPStatus: http.StatusRequestedRangeNotSatisfiable,
+				Err:        status.Errorf(codes.OutOfRange, "файл пуст"),
+			}
+		}
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		setFileHeaders(w, header)
+		if ranged {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d",
+				header.GetOffset(), header.GetOffset()+header.GetLength()-1, header.GetSize()))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		for {
+			msg, errRecv := stream.Recv()
+			if errors.Is(errRecv, io.EOF) {
+				return
+			}
+			if errRecv != nil {
+				// The status is already sent; the short body tells the client the download failed
+				slog.Error("failed to download file", "id", id, "error", errRecv)
+				return
+			}
+			if _, errWrite := w.Write(msg.GetChunk()); errWrite != nil {
+				return
+			}
+		}
+	}
+}
+
+// setFileHeaders sets the type, name and length of the downloaded file on the response. The type
+// is the one the user stored, so browsers are told not to sniff the content instead.
+func setFileHeaders(w http.ResponseWriter, header *pbrpc.DownloadFileHeader) {
+	contentType := header.GetType()
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": header.GetName()})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("Content-Length", strconv.FormatInt(header.GetLength(), 10))
+	w.Header().Set("Accept-Ranges", "bytes")
+}
+
+// parseRange converts a Range header with a single byte range into the offset and length of a
+// DownloadFileRequest; a suffix range becomes a negative offset. ok is false for a missing or
+// malformed header and for several ranges, which are answered with the whole file.
+func parseRange(h string) (offset, length int64, ok bool) {
+	spec, found := strings.CutPrefix(h, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		return -n, 0, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	if last == "" {
+		return start, 0, true
+	}
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end - start + 1, true
+}
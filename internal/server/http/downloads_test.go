@@ -0,0 +1,211 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	"github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// fakeDownloadStream returns the queued messages and then io.EOF.
+type fakeDownloadStream struct {
+	grpc.ClientStream
+	msgs []*pbrpc.DownloadFileResponse
+	err  error
+}
+
+func (s *fakeDownloadStream) Recv() (*pbrpc.DownloadFileResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if len(s.msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return msg, nil
+}
+
+// fakeDownloadClient serves a range of data the way DownloadFile does.
+type fakeDownloadClient struct {
+	pb.GophKeeperClient
+	data      []byte
+	encrypted bool
+	req       *pbrpc.DownloadFileRequest
+	jwt       []string
+}
+
+func (c *fakeDownloadClient) DownloadFile(
+	ctx context.Context,
+	in *pbrpc.DownloadFileRequest,
+	_ ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pbrpc.DownloadFileResponse], error) {
+	c.req = in
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.jwt = md.Get("jwt")
+
+	size := int64(len(c.data))
+	offset := in.GetOffset()
+	if offset < 0 {
+		offset = max(size+offset, 0)
+	} else if offset > 0 && offset >= size {
+		return &fakeDownloadStream{err: status.Error(codes.OutOfRange, "смещение за концом файла")}, nil
+	}
+	end := size
+	if in.GetLength() > 0 {
+		end = min(offset+in.GetLength(), size)
+	}
+
+	header := &pbrpc.DownloadFileHeader{
+		Name:   "отчёт 2024.pdf",
+		Type:   "application/pdf",
+		Size:   size,
+		Offset: offset,
+		Length: end - offset,
+	}
+	if c.encrypted {
+		header.Encrypted = &models.EncryptedPayload{}
+	}
+	msgs := []*pbrpc.DownloadFileResponse{
+		{Payload: &pbrpc.DownloadFileResponse_Header{Header: header}},
+	}
+	for i := offset; i < end; i += 4 {
+		msgs = append(msgs, &pbrpc.DownloadFileResponse{
+			Payload: &pbrpc.DownloadFileResponse_Chunk{Chunk: c.data[i:min(i+4, end)]},
+		})
+	}
+	return &fakeDownloadStream{msgs: msgs}, nil
+}
+
+func TestDownloadHandler(t *testing.T) {
+	newServer := func(client pb.GophKeeperClient) *httptest.Server {
+		mux := runtime.NewServeMux(runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+			return metadata.Pairs("jwt", req.Header.Get("jwt"))
+		}))
+		require.NoError(t, mux.HandlePath(http.MethodGet, downloadPattern, downloadHandler(mux, client)))
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	get := func(t *testing.T, url, rangeHeader string) (*http.Response, string) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("jwt", "token")
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+	const data = "0123456789abcdef"
+
+	t.Run("whole file", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data)}
+		resp, body := get(t, newServer(client).URL+"/v1/files/7", "")
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, data, body)
+		require.Equal(t, int32(7), client.req.GetId())
+		require.Equal(t, []string{"token"}, client.jwt)
+		require.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+		require.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		require.Equal(t, "attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D1%91%D1%82%202024.pdf",
+			resp.Header.Get("Content-Disposition"))
+		require.Equal(t, "16", resp.Header.Get("Content-Length"))
+		require.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+		require.Empty(t, resp.Header.Get("Content-Range"))
+	})
+
+	t.Run("range", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data)}
+		resp, body := get(t, newServer(client).URL+"/v1/files/7", "bytes=3-9")
+
+		require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		require.Equal(t, data[3:10], body)
+		require.Equal(t, "bytes 3-9/16", resp.Header.Get("Content-Range"))
+		require.Equal(t, "7", resp.Header.Get("Content-Length"))
+		require.Equal(t, int64(3), client.req.GetOffset())
+		require.Equal(t, int64(7), client.req.GetLength())
+	})
+
+	t.Run("suffix range", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data)}
+		resp, body := get(t, newServer(client).URL+"/v1/files/7", "bytes=-5")
+
+		require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		require.Equal(t, data[11:], body)
+		require.Equal(t, "bytes 11-15/16", resp.Header.Get("Content-Range"))
+	})
+
+	t.Run("range past the end", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data)}
+		resp, _ := get(t, newServer(client).URL+"/v1/files/7", "bytes=16-")
+		require.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+	})
+
+	t.Run("several ranges send the whole file", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data)}
+		resp, body := get(t, newServer(client).URL+"/v1/files/7", "bytes=0-1,4-5")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, data, body)
+	})
+
+	t.Run("client-side encrypted file", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data), encrypted: true}
+		resp, body := get(t, newServer(client).URL+"/v1/files/7", "")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.NotContains(t, body, data)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		client := &fakeDownloadClient{data: []byte(data)}
+		resp, _ := get(t, newServer(client).URL+"/v1/files/abc", "")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Nil(t, client.req)
+	})
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header string
+		offset int64
+		length int64
+		ok     bool
+	}{
+		{header: "bytes=0-99", offset: 0, length: 100, ok: true},
+		{header: "bytes=100-", offset: 100, length: 0, ok: true},
+		{header: "bytes=-500", offset: -500, length: 0, ok: true},
+		{header: "bytes=5-5", offset: 5, length: 1, ok: true},
+		{header: ""},
+		{header: "bytes=9-3"},
+		{header: "bytes=-0"},
+		{header: "bytes=a-b"},
+		{header: "bytes=0-1,3-4"},
+		{header: "items=0-1"},
+		{header: "bytes=5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			offset, length, ok := parseRange(tt.header)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.offset, offset)
+			require.Equal(t, tt.length, length)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	"github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// fileUploadPattern is the path files are uploaded to as multipart forms.
+const fileUploadPattern = "/v1/files"
+
+// maxFormFieldSize limits the text fields of an upload form.
+const maxFormFieldSize = 64 << 10
+
+// fileUploadHandler returns the handler of POST /v1/files, which saves the file of a
+// multipart/form-data body as a new binary record and responds with its ID.
+//
+// The form has a "file" field and the optional text fields "meta", "name" and "type"; name and
+// type default to the file name and Content-Type of the file part. The text fields must precede
+// the file, which is read part by part and passed to UploadFile without being buffered, so its
+// size is limited only by the storage.
+//
+// Parameters:
+//   - mux: The gateway mux, whose metadata and error handling are reused.
+//   - client: The client of the gRPC backend.
+//
+// Returns:
+//   - runtime.HandlerFunc: The handler to register on mux.
+func fileUploadHandler(mux *runtime.ServeMux, client pb.GophKeeperClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pb.GophKeeper_UploadFile_FullMethodName,
+			runtime.WithHTTPPathPattern(fileUploadPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		// Cancelling the stream makes the server discard a partly uploaded file
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		form, err := r.MultipartReader()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r,
+				status.Errorf(codes.InvalidArgument, "ожидается тело multipart/form-data: %v", err))
+			return
+		}
+
+		resp, err := uploadForm(ctx, client, form)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, resp)
+	}
+}
+
+// uploadForm reads the text fields of the form up to the file and streams the file to UploadFile.
+func uploadForm(
+	ctx context.Context,
+	client pb.GophKeeperClient,
+	form *multipart.Reader,
+) (*pbrpc.UploadFileResponse, error) {
+	header := &pbrpc.UploadFileHeader{Meta: &models.Meta{}}
+	for {
+		part, err := form.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, status.Errorf(codes.InvalidArgument, "форма не содержит поле file")
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "ошибка чтения формы: %v", err)
+		}
+
+		if part.FormName() == "file" {
+			if header.GetName() == "" {
+				header.Name = part.FileName()
+			}
+			if header.GetType() == "" {
+				header.Type = part.Header.Get("Content-Type")
+			}
+			if header.GetName() == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "не указано имя файла")
+			}
+			return uploadPart(ctx, client, header, part)
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize+1))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "ошибка чтения поля %s: %v", part.FormName(), err)
+		}
+		if len(value) > maxFormFieldSize {
+			return nil, status.Errorf(codes.InvalidArgument, "поле %s больше %d байт", part.FormName(), maxFormFieldSize)
+		}
+		switch part.FormName() {
+		case "meta":
+			header.Meta.Content = string(value)
+		case "name":
+			header.Name = string(value)
+		case "type":
+			header.Type = string(value)
+		}
+	}
+}
+
+// uploadPart sends the header and then the contents of the file part to UploadFile.
+func uploadPart(
+	ctx context.Context,
+	client pb.GophKeeperClient,
+	header *pbrpc.UploadFileHeader,
+	file io.Reader,
+) (*pbrpc.UploadFileResponse, error) {
+	stream, err := client.UploadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.Send(&pbrpc.UploadFileRequest{
+		Payload: &pbrpc.UploadFileRequest_Header{Header: header},
+	}); err != nil {
+		return nil, sendError(stream, err)
+	}
+
+	buf := make([]byte, handlers.FileChunkSize)
+	for {
+		n, errRead := io.ReadFull(file, buf)
+		if n > 0 {
+			if err = stream.Send(&pbrpc.UploadFileRequest{
+				Payload: &pbrpc.UploadFileRequest_Chunk{Chunk: buf[:n]},
+			}); err != nil {
+				return nil, sendError(stream, err)
+			}
+		}
+		if errors.Is(errRead, io.EOF) || errors.Is(errRead, io.ErrUnexpectedEOF) {
+			break
+		}
+		if errRead != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "ошибка чтения файла: %v", errRead)
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// sendError returns the status the server ended the stream with when Send reports io.EOF.
+func sendError(stream grpc.ClientStreamingClient[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse], err error) error {
+	if errors.Is(err, io.EOF) {
+		_, err = stream.CloseAndRecv()
+	}
+	return err
+}
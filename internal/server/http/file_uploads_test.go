@@ -0,0 +1,199 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// fakeUploadStream collects the messages sent to UploadFile.
+type fakeUploadStream struct {
+	grpc.ClientStream
+	header *pbrpc.UploadFileHeader
+	data   bytes.Buffer
+	chunks int
+	err    error
+}
+
+func (s *fakeUploadStream) Send(m *pbrpc.UploadFileRequest) error {
+	if s.err != nil {
+		return io.EOF
+	}
+	if h := m.GetHeader(); h != nil {
+		s.header = h
+		return nil
+	}
+	s.chunks++
+	s.data.Write(m.GetChunk())
+	return nil
+}
+
+func (s *fakeUploadStream) CloseAndRecv() (*pbrpc.UploadFileResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &pbrpc.UploadFileResponse{Id: 42, Size: int64(s.data.Len())}, nil
+}
+
+// fakeUploadClient opens a fakeUploadStream.
+type fakeUploadClient struct {
+	pb.GophKeeperClient
+	stream *fakeUploadStream
+	jwt    []string
+}
+
+func (c *fakeUploadClient) UploadFile(
+	ctx context.Context,
+	_ ...grpc.CallOption,
+) (grpc.ClientStreamingClient[pbrpc.UploadFileRequest, pbrpc.UploadFileResponse], error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.jwt = md.Get("jwt")
+	return c.stream, nil
+}
+
+// formField is a part of a multipart form; a field with a file name is a file part.
+type formField struct {
+	name, fileName, contentType, value string
+}
+
+func TestFileUploadHandler(t *testing.T) {
+	newServer := func(client pb.GophKeeperClient) *httptest.Server {
+		mux := runtime.NewServeMux(runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+			return metadata.Pairs("jwt", req.Header.Get("jwt"))
+		}))
+		require.NoError(t, mux.HandlePath(http.MethodPost, fileUploadPattern, fileUploadHandler(mux, client)))
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	post := func(t *testing.T, url string, fields ...formField) (*http.Response, string) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		for _, f := range fields {
+			var (
+				w   io.Writer
+				err error
+			)
+			if f.fileName != "" {
+				h := textproto.MIMEHeader{}
+				h.Set("Content-Disposition", `form-data; name="`+f.name+`"; filename="`+f.fileName+`"`)
+				h.Set("Content-Type", f.contentType)
+				w, err = form.CreatePart(h)
+			} else {
+				w, err = form.CreateFormField(f.name)
+			}
+			require.NoError(t, err)
+			_, err = io.WriteString(w, f.value)
+			require.NoError(t, err)
+		}
+		require.NoError(t, form.Close())
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, &body)
+		require.NoError(t, err)
+		req.Header.Set("jwt", "token")
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(respBody)
+	}
+	contents := strings.Repeat("0123456789", handlers.FileChunkSize/4)
+
+	t.Run("file is streamed in chunks", func(t *testing.T) {
+		client := &fakeUploadClient{stream: &fakeUploadStream{}}
+		resp, body := post(t, newServer(client).URL+"/v1/files",
+			formField{name: "meta", value: "паспорт"},
+			formField{name: "file", fileName: "scan.pdf", contentType: "application/pdf", value: contents},
+		)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var got struct {
+			ID   int32  `json:"id"`
+			Size string `json:"size"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &got))
+		require.Equal(t, int32(42), got.ID)
+		require.Equal(t, strconv.Itoa(len(contents)), got.Size)
+
+		require.Equal(t, "scan.pdf", client.stream.header.GetName())
+		require.Equal(t, "application/pdf", client.stream.header.GetType())
+		require.Equal(t, "паспорт", client.stream.header.GetMeta().GetContent())
+		require.Equal(t, contents, client.stream.data.String())
+		require.Equal(t, 3, client.stream.chunks)
+		require.Equal(t, []string{"token"}, client.jwt)
+	})
+
+	t.Run("name and type fields override the file part", func(t *testing.T) {
+		client := &fakeUploadClient{stream: &fakeUploadStream{}}
+		resp, _ := post(t, newServer(client).URL+"/v1/files",
+			formField{name: "name", value: "отчёт.txt"},
+			formField{name: "type", value: "text/plain"},
+			formField{name: "file", fileName: "blob", contentType: "application/octet-stream", value: "text"},
+		)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "отчёт.txt", client.stream.header.GetName())
+		require.Equal(t, "text/plain", client.stream.header.GetType())
+	})
+
+	t.Run("form without a file", func(t *testing.T) {
+		client := &fakeUploadClient{stream: &fakeUploadStream{}}
+		resp, _ := post(t, newServer(client).URL+"/v1/files", formField{name: "meta", value: "x"})
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Nil(t, client.stream.header)
+	})
+
+	t.Run("field too large", func(t *testing.T) {
+		client := &fakeUploadClient{stream: &fakeUploadStream{}}
+		resp, _ := post(t, newServer(client).URL+"/v1/files",
+			formField{name: "meta", value: strings.Repeat("x", maxFormFieldSize+1)},
+			formField{name: "file", fileName: "a.txt", contentType: "text/plain", value: "a"},
+		)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Nil(t, client.stream.header)
+	})
+
+	t.Run("not a multipart body", func(t *testing.T) {
+		client := &fakeUploadClient{stream: &fakeUploadStream{}}
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+			newServer(client).URL+"/v1/files", strings.NewReader(`{"name": "a"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("server error ends the upload", func(t *testing.T) {
+		client := &fakeUploadClient{stream: &fakeUploadStream{
+			err: status.Error(codes.FailedPrecondition, "данные этого аккаунта шифруются на клиенте"),
+		}}
+		resp, body := post(t, newServer(client).URL+"/v1/files",
+			formField{name: "file", fileName: "a.txt", contentType: "text/plain", value: "a"},
+		)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Contains(t, body, "шифруются на клиенте")
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/apetsko/gophkeeper/config"
+	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/pkg/logging"
 	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -44,9 +45,13 @@ func RunHTTP(ctx context.Context, cfg *config.Config, log *logging.Logger) (*htt
 	mux := runtime.NewServeMux(
 		runtime.WithMetadata(func(ctx context.Context, req *http.Request) metadata.MD {
 			md := metadata.New(nil)
+			md.Set(string(constants.GatewayToken), cfg.GatewayToken)
 			if auth := req.Header.Get("jwt"); auth != "" {
 				md.Set("jwt", auth)
 			}
+			if token := req.Header.Get("admin-token"); token != "" {
+				md.Set("admin-token", token)
+			}
 			return md
 		}),
 	)
@@ -82,6 +87,23 @@ func RunHTTP(ctx context.Context, cfg *config.Config, log *logging.Logger) (*htt
 		log.Fatalf("failed to register gRPC-Gateway: %v", err)
 	}
 
+	// Uploaded and downloaded files are raw bytes or forms rather than JSON, so their handlers
+	// call the backend themselves
+	conn, err := grpc.NewClient(cfg.GRPCAddress, opts...)
+	if err != nil {
+		log.Fatalf("failed to create gRPC client: %v", err)
+	}
+	client := pb.NewGophKeeperClient(conn)
+	if err := mux.HandlePath(http.MethodPut, uploadChunkPattern, uploadChunkHandler(mux, client)); err != nil {
+		log.Fatalf("failed to register upload handler: %v", err)
+	}
+	if err := mux.HandlePath(http.MethodGet, downloadPattern, downloadHandler(mux, client)); err != nil {
+		log.Fatalf("failed to register download handler: %v", err)
+	}
+	if err := mux.HandlePath(http.MethodPost, fileUploadPattern, fileUploadHandler(mux, client)); err != nil {
+		log.Fatalf("failed to register file upload handler: %v", err)
+	}
+
 	srv := &http.Server{
 		Addr:              cfg.HTTPAddress,
 		Handler:           handler,
@@ -95,6 +117,7 @@ func RunHTTP(ctx context.Context, cfg *config.Config, log *logging.Logger) (*htt
 		five := 5 * time.Second
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), five)
 		defer cancel()
+		defer func() { _ = conn.Close() }()
 		return srv.Shutdown(shutdownCtx)
 	})
 
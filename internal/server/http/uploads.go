@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// UploadOffsetHeader carries the offset of a chunk of a resumable upload in requests and the
+// received offset in responses.
+const UploadOffsetHeader = "Upload-Offset"
+
+// uploadChunkPattern is the path chunks of resumable uploads are sent to.
+const uploadChunkPattern = "/v1/uploads/{upload_id}"
+
+// uploadChunkHandler returns the handler of PUT /v1/uploads/{upload_id}, which passes the raw
+// request body to UploadChunk as the chunk that starts at the Upload-Offset header.
+//
+// The gateway would expect the chunk base64-encoded inside a JSON body, which adds a third to
+// every upload and keeps the whole body in memory twice.
+//
+// Parameters:
+//   - mux: The gateway mux, whose metadata and error handling are reused.
+//   - client: The client of the gRPC backend.
+//
+// Returns:
+//   - runtime.HandlerFunc: The handler to register on mux.
+func uploadChunkHandler(mux *runtime.ServeMux, client pb.GophKeeperClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pb.GophKeeper_UploadChunk_FullMethodName,
+			runtime.WithHTTPPathPattern(uploadChunkPattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		offset, err := strconv.ParseInt(r.Header.Get(UploadOffsetHeader), 10, 64)
+		if err != nil || offset < 0 {
+			runtime.HTTPError(ctx, mux, outbound, w, r,
+				status.Errorf(codes.InvalidArgument, "заголовок %s должен содержать смещение части", UploadOffsetHeader))
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, handlers.UploadChunkSize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				err = status.Errorf(codes.InvalidArgument, "размер части больше %d байт", handlers.UploadChunkSize)
+			} else {
+				err = status.Errorf(codes.InvalidArgument, "ошибка чтения части: %v", err)
+			}
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		resp, err := client.UploadChunk(ctx, &pbrpc.UploadChunkRequest{
+			UploadId: pathParams["upload_id"],
+			Offset:   offset,
+			Data:     data,
+		}, grpc.MaxCallSendMsgSize(handlers.MaxUploadChunkMessageSize))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set(UploadOffsetHeader, strconv.FormatInt(resp.GetOffset(), 10))
+		runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, resp)
+	}
+}
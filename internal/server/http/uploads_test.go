@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/apetsko/gophkeeper/internal/server/grpc/handlers"
+	pb "github.com/apetsko/gophkeeper/protogen/api/proto/v1"
+	pbrpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+)
+
+// fakeChunkClient records the UploadChunk call and answers it.
+type fakeChunkClient struct {
+	pb.GophKeeperClient
+	req *pbrpc.UploadChunkRequest
+	jwt []string
+	err error
+}
+
+func (c *fakeChunkClient) UploadChunk(
+	ctx context.Context,
+	in *pbrpc.UploadChunkRequest,
+	_ ...grpc.CallOption,
+) (*pbrpc.UploadChunkResponse, error) {
+	c.req = in
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.jwt = md.Get("jwt")
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pbrpc.UploadChunkResponse{Offset: in.GetOffset() + int64(len(in.GetData()))}, nil
+}
+
+func TestUploadChunkHandler(t *testing.T) {
+	newServer := func(client pb.GophKeeperClient) *httptest.Server {
+		mux := runtime.NewServeMux(runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+			return metadata.Pairs("jwt", req.Header.Get("jwt"))
+		}))
+		require.NoError(t, mux.HandlePath(http.MethodPut, uploadChunkPattern, uploadChunkHandler(mux, client)))
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	put := func(t *testing.T, url, offset string, body string) *http.Response {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, url, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("jwt", "token")
+		if offset != "" {
+			req.Header.Set(UploadOffsetHeader, offset)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("raw body is passed as the chunk", func(t *testing.T) {
+		client := &fakeChunkClient{}
+		srv := newServer(client)
+
+		resp := put(t, srv.URL+"/v1/uploads/abc", "16", "chunk")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "21", resp.Header.Get(UploadOffsetHeader))
+		require.Equal(t, "abc", client.req.GetUploadId())
+		require.Equal(t, int64(16), client.req.GetOffset())
+		require.Equal(t, []byte("chunk"), client.req.GetData())
+		require.Equal(t, []string{"token"}, client.jwt)
+	})
+
+	t.Run("missing offset", func(t *testing.T) {
+		client := &fakeChunkClient{}
+		resp := put(t, newServer(client).URL+"/v1/uploads/abc", "", "chunk")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Nil(t, client.req)
+	})
+
+	t.Run("chunk too large", func(t *testing.T) {
+		client := &fakeChunkClient{}
+		resp := put(t, newServer(client).URL+"/v1/uploads/abc", "0", strings.Repeat("x", handlers.UploadChunkSize+1))
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Nil(t, client.req)
+	})
+
+	t.Run("gRPC errors are mapped", func(t *testing.T) {
+		client := &fakeChunkClient{err: status.Error(codes.Aborted, "неверное смещение")}
+		resp := put(t, newServer(client).URL+"/v1/uploads/abc", "8", "chunk")
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
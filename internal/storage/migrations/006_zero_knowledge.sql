@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN encryption_mode TEXT NOT NULL DEFAULT 'server';
+
+CREATE TABLE user_client_keys
+(
+    user_id            INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    kdf_algorithm      TEXT  NOT NULL,
+    kdf_salt           BYTEA NOT NULL,
+    kdf_time           INT   NOT NULL,
+    kdf_memory         INT   NOT NULL,
+    kdf_threads        INT   NOT NULL,
+    wrapped_master_key BYTEA NOT NULL,
+    created_at         TIMESTAMPTZ DEFAULT now(),
+    updated_at         TIMESTAMPTZ DEFAULT now()
+);
+
+ALTER TABLE user_data
+    ADD COLUMN client_encrypted BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS client_encrypted;
+
+DROP TABLE IF EXISTS user_client_keys;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS encryption_mode;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE user_keys
+    ADD COLUMN kdf_version          INT NOT NULL DEFAULT 0,
+    ADD COLUMN kdf_algorithm        TEXT,
+    ADD COLUMN kdf_salt             BYTEA,
+    ADD COLUMN kdf_time             INT,
+    ADD COLUMN kdf_memory           INT,
+    ADD COLUMN kdf_threads          INT,
+    ADD COLUMN password_wrapped_key BYTEA;
+
+-- +goose Down
+ALTER TABLE user_keys
+    DROP COLUMN IF EXISTS password_wrapped_key,
+    DROP COLUMN IF EXISTS kdf_threads,
+    DROP COLUMN IF EXISTS kdf_memory,
+    DROP COLUMN IF EXISTS kdf_time,
+    DROP COLUMN IF EXISTS kdf_salt,
+    DROP COLUMN IF EXISTS kdf_algorithm,
+    DROP COLUMN IF EXISTS kdf_version;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE user_keys
+    ADD COLUMN server_key_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_user_keys_server_key_id ON user_keys (server_key_id);
+
+CREATE TABLE key_rotation_jobs
+(
+    id            INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    target_key_id TEXT NOT NULL,
+    status        TEXT NOT NULL DEFAULT 'running',
+    last_user_id  INT  NOT NULL DEFAULT 0,
+    rewrapped     INT  NOT NULL DEFAULT 0,
+    error         TEXT NOT NULL DEFAULT '',
+    started_at    TIMESTAMPTZ   DEFAULT now(),
+    updated_at    TIMESTAMPTZ   DEFAULT now()
+);
+
+-- Only one rotation may run at a time.
+CREATE UNIQUE INDEX idx_key_rotation_jobs_running ON key_rotation_jobs ((TRUE)) WHERE status = 'running';
+
+-- +goose Down
+DROP TABLE IF EXISTS key_rotation_jobs;
+
+DROP INDEX IF EXISTS idx_user_keys_server_key_id;
+
+ALTER TABLE user_keys
+    DROP COLUMN IF EXISTS server_key_id;
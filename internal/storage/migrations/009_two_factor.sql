@@ -0,0 +1,24 @@
+-- +goose Up
+-- totp_secret holds the base64 ciphertext of the secret, sealed with the server key totp_secret_key_id.
+ALTER TABLE users
+    ADD COLUMN totp_secret_key_id TEXT,
+    ADD COLUMN totp_secret_nonce  BYTEA,
+    ADD COLUMN totp_last_step     BIGINT;
+
+CREATE TABLE user_recovery_codes
+(
+    id         INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id    INT   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  BYTEA NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    UNIQUE (user_id, code_hash)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_last_step,
+    DROP COLUMN IF EXISTS totp_secret_nonce,
+    DROP COLUMN IF EXISTS totp_secret_key_id;
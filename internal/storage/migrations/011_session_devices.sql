@@ -0,0 +1,13 @@
+-- +goose Up
+-- Client details shown in the list of active sessions. The IP address is the one the
+-- session was last seen from.
+ALTER TABLE sessions
+    ADD COLUMN device_name    TEXT NOT NULL DEFAULT '',
+    ADD COLUMN client_version TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip             TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS device_name,
+    DROP COLUMN IF EXISTS client_version,
+    DROP COLUMN IF EXISTS ip;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Every update of a user_data row first copies its current contents into user_data_versions.
+-- A version keeps its own wrapped DEK and MinIO object, so it can be decrypted and restored
+-- after the record has moved on.
+ALTER TABLE user_data
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- version_retention overrides the server default number of versions kept per record.
+ALTER TABLE users
+    ADD COLUMN version_retention INT;
+
+CREATE TABLE user_data_versions
+(
+    id              INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_data_id    INT         NOT NULL REFERENCES user_data (id) ON DELETE CASCADE,
+    version         INT         NOT NULL,
+    minio_object_id TEXT,
+    encrypted_data  BYTEA,
+    data_nonce      BYTEA,
+    encrypted_dek   BYTEA       NOT NULL,
+    dek_nonce       BYTEA       NOT NULL,
+    meta            JSONB       NOT NULL DEFAULT '{}'::JSONB,
+    created_at      TIMESTAMPTZ NOT NULL,
+    archived_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_data_id, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_data_versions;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS version_retention;
+
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS version;
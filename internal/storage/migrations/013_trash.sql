@@ -0,0 +1,13 @@
+-- +goose Up
+-- A deleted record stays in the trash until it is restored, the trash is emptied or the
+-- purger removes it after the trash retention period.
+ALTER TABLE user_data
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_user_data_deleted_at ON user_data (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_data_deleted_at;
+
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS deleted_at;
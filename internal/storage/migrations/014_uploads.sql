@@ -0,0 +1,39 @@
+-- +goose Up
+-- A resumable upload is a MinIO multipart upload of a file encrypted in the segmented stream
+-- format. Its parts are sent by separate requests, so the stream header and the wrapped DEK are
+-- kept here until the upload is completed, aborted or expires.
+CREATE TABLE uploads
+(
+    id            TEXT PRIMARY KEY,
+    user_id       INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    object_name   TEXT        NOT NULL,
+    multipart_id  TEXT        NOT NULL,
+    file_name     TEXT        NOT NULL,
+    file_type     TEXT        NOT NULL,
+    meta          TEXT        NOT NULL,
+    size          BIGINT      NOT NULL,
+    received      BIGINT      NOT NULL DEFAULT 0,
+    encrypted_dek BYTEA       NOT NULL,
+    dek_nonce     BYTEA       NOT NULL,
+    stream_header BYTEA       NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX uploads_expires_at_idx ON uploads (expires_at);
+
+-- A part is reserved with the digest of its plaintext before it is encrypted, so a retry with
+-- different data can be refused instead of reusing the chunk nonces; etag is set once the part
+-- is stored.
+CREATE TABLE upload_parts
+(
+    upload_id   TEXT  NOT NULL REFERENCES uploads (id) ON DELETE CASCADE,
+    part_number INT   NOT NULL,
+    digest      BYTEA NOT NULL,
+    etag        TEXT,
+    PRIMARY KEY (upload_id, part_number)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS upload_parts;
+DROP TABLE IF EXISTS uploads;
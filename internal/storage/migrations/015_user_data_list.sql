@@ -0,0 +1,20 @@
+-- +goose Up
+-- DataList pages through a user's records with a cursor on (created_at, id) or (updated_at, id),
+-- which needs both timestamps to be set and an index for each order.
+UPDATE user_data SET created_at = now() WHERE created_at IS NULL;
+UPDATE user_data SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE user_data
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX idx_user_data_list_created ON user_data (user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_user_data_list_updated ON user_data (user_id, updated_at, id) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_data_list_updated;
+DROP INDEX IF EXISTS idx_user_data_list_created;
+
+ALTER TABLE user_data
+    ALTER COLUMN created_at DROP NOT NULL,
+    ALTER COLUMN updated_at DROP NOT NULL;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Search matches the string values of the record meta by full-text search, for whole words and
+-- prefixes, and by trigram similarity, for misspelled words. Both are indexed on the text
+-- returned by meta_search_text.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- +goose StatementBegin
+CREATE FUNCTION meta_search_text(meta JSONB) RETURNS TEXT
+    LANGUAGE SQL
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT coalesce(string_agg(value #>> '{}', ' '), '')
+FROM jsonb_path_query(meta, 'strict $.** ? (@.type() == "string")') AS value
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX idx_user_data_meta_fts ON user_data USING GIN (to_tsvector('simple', meta_search_text(meta)));
+CREATE INDEX idx_user_data_meta_trgm ON user_data USING GIN (meta_search_text(meta) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_data_meta_trgm;
+DROP INDEX IF EXISTS idx_user_data_meta_fts;
+
+DROP FUNCTION IF EXISTS meta_search_text(JSONB);
@@ -0,0 +1,98 @@
+-- +goose Up
+-- Meta gains a title, tags, a favorite flag, URLs and custom fields. The values of sensitive
+-- custom fields are encrypted with the DEK of the record and kept in meta_secrets rather than
+-- in the meta JSONB.
+ALTER TABLE user_data
+    ADD COLUMN meta_secrets       BYTEA,
+    ADD COLUMN meta_secrets_nonce BYTEA;
+
+ALTER TABLE user_data_versions
+    ADD COLUMN meta_secrets       BYTEA,
+    ADD COLUMN meta_secrets_nonce BYTEA;
+
+ALTER TABLE uploads
+    ADD COLUMN meta_secrets       BYTEA,
+    ADD COLUMN meta_secrets_nonce BYTEA;
+
+-- Records described by a content string alone get its first line as their title; the content
+-- stays as the note of the record.
+UPDATE user_data SET meta = '{}'::JSONB WHERE meta IS NULL;
+
+ALTER TABLE user_data
+    ALTER COLUMN meta SET NOT NULL;
+
+UPDATE user_data
+SET meta = meta || jsonb_build_object('title', left(btrim(split_part(meta ->> 'content', E'\n', 1)), 100))
+WHERE btrim(COALESCE(meta ->> 'content', '')) <> '' AND NOT meta ? 'title';
+
+UPDATE user_data_versions
+SET meta = meta || jsonb_build_object('title', left(btrim(split_part(meta ->> 'content', E'\n', 1)), 100))
+WHERE btrim(COALESCE(meta ->> 'content', '')) <> '' AND NOT meta ? 'title';
+
+CREATE INDEX idx_user_data_meta_tags ON user_data USING GIN ((meta -> 'tags') jsonb_path_ops);
+
+-- Search covers the values users see rather than every string of the JSON, which now includes
+-- the names of custom field types.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION meta_search_text(meta JSONB) RETURNS TEXT
+    LANGUAGE SQL
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT coalesce(string_agg(value #>> '{}', ' '), '')
+FROM (SELECT jsonb_path_query(meta, 'lax $.title') AS value
+      UNION ALL
+      SELECT jsonb_path_query(meta, 'lax $.content')
+      UNION ALL
+      SELECT jsonb_path_query(meta, 'lax $.tags[*]')
+      UNION ALL
+      SELECT jsonb_path_query(meta, 'lax $.urls[*]')
+      UNION ALL
+      SELECT jsonb_path_query(meta, 'lax $.fields[*].name')
+      UNION ALL
+      SELECT jsonb_path_query(meta, 'lax $.fields[*].value')) AS matched
+WHERE jsonb_typeof(value) = 'string'
+$$;
+-- +goose StatementEnd
+
+REINDEX INDEX idx_user_data_meta_fts;
+REINDEX INDEX idx_user_data_meta_trgm;
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION meta_search_text(meta JSONB) RETURNS TEXT
+    LANGUAGE SQL
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT coalesce(string_agg(value #>> '{}', ' '), '')
+FROM jsonb_path_query(meta, 'strict $.** ? (@.type() == "string")') AS value
+$$;
+-- +goose StatementEnd
+
+REINDEX INDEX idx_user_data_meta_fts;
+REINDEX INDEX idx_user_data_meta_trgm;
+
+DROP INDEX IF EXISTS idx_user_data_meta_tags;
+
+-- The previous Meta has a content string only and rejects the other keys
+UPDATE user_data SET meta = meta - 'title' - 'tags' - 'favorite' - 'urls' - 'fields';
+UPDATE user_data_versions SET meta = meta - 'title' - 'tags' - 'favorite' - 'urls' - 'fields';
+UPDATE uploads SET meta = (meta::JSONB - 'title' - 'tags' - 'favorite' - 'urls' - 'fields')::TEXT;
+
+ALTER TABLE user_data
+    ALTER COLUMN meta DROP NOT NULL;
+
+ALTER TABLE uploads
+    DROP COLUMN IF EXISTS meta_secrets_nonce,
+    DROP COLUMN IF EXISTS meta_secrets;
+
+ALTER TABLE user_data_versions
+    DROP COLUMN IF EXISTS meta_secrets_nonce,
+    DROP COLUMN IF EXISTS meta_secrets;
+
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS meta_secrets_nonce,
+    DROP COLUMN IF EXISTS meta_secrets;
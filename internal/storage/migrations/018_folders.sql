@@ -0,0 +1,34 @@
+-- +goose Up
+-- Folders nest through parent_id, NULL at the root. Folder names often say what a record is
+-- for, so they are encrypted with their own DEK like record payloads, by the server or by a
+-- zero-knowledge client.
+CREATE TABLE folders
+(
+    id               SERIAL PRIMARY KEY,
+    user_id          INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    parent_id        INT REFERENCES folders (id) ON DELETE SET NULL,
+    encrypted_name   BYTEA       NOT NULL,
+    name_nonce       BYTEA       NOT NULL,
+    encrypted_dek    BYTEA       NOT NULL,
+    dek_nonce        BYTEA       NOT NULL,
+    client_encrypted BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_folders_user ON folders (user_id);
+CREATE INDEX idx_folders_parent ON folders (parent_id);
+
+-- Records outside any folder have no folder_id; a deleted folder hands its records to its parent.
+ALTER TABLE user_data
+    ADD COLUMN folder_id INT REFERENCES folders (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_user_data_folder ON user_data (folder_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_data_folder;
+
+ALTER TABLE user_data
+    DROP COLUMN IF EXISTS folder_id;
+
+DROP TABLE IF EXISTS folders;
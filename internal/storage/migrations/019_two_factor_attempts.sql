@@ -0,0 +1,27 @@
+-- +goose Up
+-- Failed second-factor attempts are counted per user, and a user is locked out until
+-- totp_locked_until once too many fail in a row.
+ALTER TABLE users
+    ADD COLUMN totp_failed_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN totp_locked_until    TIMESTAMPTZ;
+
+-- A challenge is the pending login of a two-factor token, keyed by the token's jti. It counts
+-- the failed attempts made with the token and is marked used by the login it completes.
+CREATE TABLE two_factor_challenges
+(
+    jti             TEXT PRIMARY KEY,
+    user_id         INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    failed_attempts INT         NOT NULL DEFAULT 0,
+    used_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX two_factor_challenges_expires_at_idx ON two_factor_challenges (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS two_factor_challenges;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_locked_until,
+    DROP COLUMN IF EXISTS totp_failed_attempts;
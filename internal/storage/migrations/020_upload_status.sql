@@ -0,0 +1,9 @@
+-- +goose Up
+-- An upload is claimed by setting its status before it is completed or aborted, so concurrent
+-- requests cannot both turn the same object into a record or abort it under a completion.
+ALTER TABLE uploads
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'open';
+
+-- +goose Down
+ALTER TABLE uploads
+    DROP COLUMN IF EXISTS status;
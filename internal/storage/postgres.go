@@ -9,9 +9,13 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -21,6 +25,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 
+	"github.com/apetsko/gophkeeper/internal/constants"
 	"github.com/apetsko/gophkeeper/models"
 )
 
@@ -41,6 +46,14 @@ type PgxPoolIface interface {
 //go:embed migrations/*.sql
 var migrations embed.FS
 
+// insertUserSQL inserts a user and returns its ID, or no rows if the username is taken.
+const insertUserSQL = `
+    INSERT INTO users (username, password_hash, encryption_mode, created_at, updated_at)
+    VALUES ($1, $2, $3, NOW(), NOW())
+    ON CONFLICT (username) DO NOTHING
+    RETURNING id;
+`
+
 // Storage implements the IStorage interface using a PostgreSQL backend.
 //
 // It provides methods for user management, master key storage, and user data operations.
@@ -126,15 +139,8 @@ func (p *Storage) Close() error {
 //   - int: The new user's ID.
 //   - error: An error if the user exists or insertion fails.
 func (p *Storage) AddUser(ctx context.Context, u *models.UserEntry) (int, error) {
-	const insertUser = `
-        INSERT INTO users (username, password_hash, created_at, updated_at)
-        VALUES ($1, $2, NOW(), NOW())
-        ON CONFLICT (username) DO NOTHING
-        RETURNING id;
-    `
-
 	var id int
-	err := p.DB.QueryRow(ctx, insertUser, u.Username, u.PasswordHash).Scan(&id)
+	err := p.DB.QueryRow(ctx, insertUserSQL, u.Username, u.PasswordHash, encryptionMode(u)).Scan(&id)
 
 	switch {
 	case err == nil:
@@ -146,244 +152,3065 @@ func (p *Storage) AddUser(ctx context.Context, u *models.UserEntry) (int, error)
 	}
 }
 
-// GetUser retrieves a user by username.
+// AddClientUser inserts a new zero-knowledge user together with its client key material.
+//
+// Both rows are written in a single transaction, so an account never exists without its wrapped master key.
 //
 // Parameters:
 //   - ctx: Context for the operation.
-//   - username: Username to search for.
+//   - u: Pointer to the UserEntry to add.
+//   - key: Client key material; its UserID is ignored.
 //
 // Returns:
-//   - *models.UserEntry: The found user entry.
-//   - error: An error if not found or query fails.
-func (p *Storage) GetUser(ctx context.Context, username string) (*models.UserEntry, error) {
-	const getUser = `
-		SELECT id, username, password_hash FROM users
-		WHERE username = $1;
-	`
+//   - int: The new user's ID.
+//   - error: An error if the user exists or insertion fails.
+func (p *Storage) AddClientUser(ctx context.Context, u *models.UserEntry, key *models.ClientKey) (int, error) {
+	const insertKey = `
+        INSERT INTO user_client_keys (user_id, kdf_algorithm, kdf_salt, kdf_time, kdf_memory, kdf_threads, wrapped_master_key)
+        VALUES ($1, $2, $3, $4, $5, $6, $7);
+    `
 
-	var u models.UserEntry
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id int
+	err = tx.QueryRow(ctx, insertUserSQL, u.Username, u.PasswordHash, models.EncryptionModeClient).Scan(&id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return 0, models.ErrUserExists
+	case err != nil:
+		return 0, fmt.Errorf("failed to insert user: %w", err)
+	}
 
-	err := p.DB.QueryRow(ctx, getUser, username).Scan(&u.ID, &u.Username, &u.PasswordHash)
+	_, err = tx.Exec(
+		ctx,
+		insertKey,
+		id,
+		key.KDF.Algorithm,
+		key.KDF.Salt,
+		int(key.KDF.Time),
+		int(key.KDF.Memory),
+		int(key.KDF.Threads),
+		key.WrappedMasterKey,
+	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, models.ErrUserNotFound
-		}
-		return nil, err
+		return 0, fmt.Errorf("failed to save client key: %w", err)
 	}
-	return &u, nil
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
 }
 
-// SaveMasterKey stores an encrypted master key for a user.
+// GetClientKey retrieves the client key material of a zero-knowledge user.
 //
 // Parameters:
 //   - ctx: Context for the operation.
 //   - userID: User ID.
-//   - encryptedMK: Encrypted master key bytes.
-//   - nonce: Nonce used for encryption.
 //
 // Returns:
-//   - int: The new record's ID.
-//   - error: An error if the operation fails.
-func (p *Storage) SaveMasterKey(
-	ctx context.Context,
-	userID int,
-	encryptedMK []byte,
-	nonce []byte,
-) (int, error) {
-	const insertSQL = `
-        INSERT INTO user_keys (user_id, encrypted_master_key, nonce) 
-        VALUES ($1, $2, $3)
-        RETURNING id;
+//   - *models.ClientKey: The KDF parameters and wrapped master key.
+//   - error: models.ErrClientKeyNotFound if the user is not a zero-knowledge user, or a query error.
+func (p *Storage) GetClientKey(ctx context.Context, userID int) (*models.ClientKey, error) {
+	const selectSQL = `
+        SELECT kdf_algorithm, kdf_salt, kdf_time, kdf_memory, kdf_threads, wrapped_master_key
+        FROM user_client_keys
+        WHERE user_id = $1;
     `
 
-	var id int
-
-	err := p.DB.QueryRow(ctx, insertSQL, userID, encryptedMK, nonce).Scan(&id)
+	var (
+		key                  = models.ClientKey{UserID: userID}
+		kdfTime, mem, thread int
+	)
+	err := p.DB.QueryRow(ctx, selectSQL, userID).Scan(
+		&key.KDF.Algorithm,
+		&key.KDF.Salt,
+		&kdfTime,
+		&mem,
+		&thread,
+		&key.WrappedMasterKey,
+	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to save master key: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrClientKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get client key: %w", err)
 	}
 
-	return id, err
+	key.KDF.Time = uint32(kdfTime)
+	key.KDF.Memory = uint32(mem)
+	key.KDF.Threads = uint8(thread)
+
+	return &key, nil
 }
 
-// GetMasterKey retrieves the encrypted master key for a user.
+// GetUser retrieves a user by username.
 //
 // Parameters:
 //   - ctx: Context for the operation.
-//   - userID: User ID.
+//   - username: Username to search for.
 //
 // Returns:
-//   - *models.EncryptedMK: The encrypted master key and nonce.
+//   - *models.UserEntry: The found user entry.
 //   - error: An error if not found or query fails.
-func (p *Storage) GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error) {
-	const selectSQL = `
-        SELECT encrypted_master_key, nonce FROM user_keys 
-        WHERE user_id = $1;
-    `
+func (p *Storage) GetUser(ctx context.Context, username string) (*models.UserEntry, error) {
+	const getUser = `
+		SELECT id, username, password_hash, encryption_mode, COALESCE(totp_enabled, FALSE),
+		       COALESCE(version_retention, 0) FROM users
+		WHERE username = $1;
+	`
 
-	var encryptedMK models.EncryptedMK
-	err := p.DB.QueryRow(ctx, selectSQL, userID).Scan(&encryptedMK.EncryptedMK, &encryptedMK.Nonce)
+	var u models.UserEntry
+
+	err := p.DB.QueryRow(ctx, getUser, username).Scan(
+		&u.ID, &u.Username, &u.PasswordHash, &u.EncryptionMode, &u.TOTPEnabled, &u.VersionRetention,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, models.ErrMasterKeyNotFound
+			return nil, models.ErrUserNotFound
 		}
 		return nil, err
 	}
+	return &u, nil
+}
+
+// GetUserByID retrieves a user by ID.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - id: User ID.
+//
+// Returns:
+//   - *models.UserEntry: The found user entry.
+//   - error: models.ErrUserNotFound if there is no such user, or a query error.
+func (p *Storage) GetUserByID(ctx context.Context, id int) (*models.UserEntry, error) {
+	const getUser = `
+		SELECT id, username, password_hash, encryption_mode, COALESCE(totp_enabled, FALSE),
+		       COALESCE(version_retention, 0) FROM users
+		WHERE id = $1;
+	`
 
-	return &encryptedMK, err
+	var u models.UserEntry
+
+	err := p.DB.QueryRow(ctx, getUser, id).Scan(
+		&u.ID, &u.Username, &u.PasswordHash, &u.EncryptionMode, &u.TOTPEnabled, &u.VersionRetention,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
 }
 
-// SaveUserData stores encrypted user data in the database.
+// SaveMasterKey stores an encrypted master key for a user.
 //
 // Parameters:
 //   - ctx: Context for the operation.
-//   - userData: Pointer to the DBUserData to store.
+//   - key: The encrypted master key with its KDF parameters; key.UserID identifies the owner.
 //
 // Returns:
 //   - int: The new record's ID.
 //   - error: An error if the operation fails.
-func (p *Storage) SaveUserData(ctx context.Context, userData *models.DBUserData) (int, error) {
+func (p *Storage) SaveMasterKey(ctx context.Context, key *models.EncryptedMK) (int, error) {
 	const insertSQL = `
-        INSERT INTO user_data (user_id, type, minio_object_id, encrypted_data, data_nonce, encrypted_dek, dek_nonce, meta) 
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        INSERT INTO user_keys (user_id, encrypted_master_key, nonce, kdf_version, kdf_algorithm,
+                               kdf_salt, kdf_time, kdf_memory, kdf_threads, password_wrapped_key, server_key_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
         RETURNING id;
     `
 
+	algorithm, salt, kdfTime, memory, threads := kdfColumns(key.KDF)
+
 	var id int
 
 	err := p.DB.QueryRow(
 		ctx,
 		insertSQL,
-		userData.UserID,
-		userData.Type,
-		userData.MinioObjectID,
-		userData.EncryptedData,
-		userData.DataNonce,
-		userData.EncryptedDek,
-		userData.DekNonce,
-		userData.Meta,
+		key.UserID,
+		key.EncryptedMK,
+		key.Nonce,
+		key.KDFVersion,
+		algorithm,
+		salt,
+		kdfTime,
+		memory,
+		threads,
+		key.PasswordWrappedMK,
+		serverKeyID(key),
 	).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to save user data: %w", err)
+		return 0, fmt.Errorf("failed to save master key: %w", err)
 	}
 
 	return id, err
 }
 
-// GetUserData retrieves a user data record by its ID.
+// UpdateMasterKey replaces the stored master key of a user.
+//
+// When rewrapDEK is not nil, the data encryption keys of all server-encrypted records of the
+// user, of their prior versions, folders and unfinished uploads are re-wrapped with it. The records and the key are updated
+// in a single transaction, so a failure leaves the old master key and the old DEKs in place.
 //
 // Parameters:
 //   - ctx: Context for the operation.
-//   - userDataID: ID of the user data record.
+//   - key: The new encrypted master key with its KDF parameters; key.UserID identifies the owner.
+//   - rewrapDEK: Optional function re-wrapping an encrypted DEK and its nonce.
 //
 // Returns:
-//   - *models.DBUserData: The user data record.
-//   - error: An error if not found or query fails.
-func (p *Storage) GetUserData(ctx context.Context, userDataID int) (*models.DBUserData, error) {
-	const selectSQL = `
-        SELECT user_id, 
-               type,
-               minio_object_id,
-               encrypted_data,
-               data_nonce,
-               encrypted_dek,
-               dek_nonce,
-               meta FROM user_data 
-        WHERE id = $1;
+//   - error: models.ErrMasterKeyNotFound if the user has no key, or an error if the operation fails.
+func (p *Storage) UpdateMasterKey(
+	ctx context.Context,
+	key *models.EncryptedMK,
+	rewrapDEK func(encryptedDEK, nonce []byte) ([]byte, []byte, error),
+) error {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The key row is locked before the DEKs, in the same order as UpdatePassword
+	if _, err := scanMasterKey(tx.QueryRow(ctx, selectMasterKeySQL+" FOR UPDATE", key.UserID), key.UserID); err != nil {
+		return err
+	}
+
+	if rewrapDEK != nil {
+		if err := rewrapUserDEKs(ctx, tx, key.UserID, rewrapDEK); err != nil {
+			return err
+		}
+	}
+
+	if err := updateMasterKey(ctx, tx, key); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// rewrapUserDEKs re-wraps within tx the data keys of all server-encrypted records of userID, of
+// their prior versions, folders and unfinished uploads.
+func rewrapUserDEKs(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID int,
+	rewrapDEK func(encryptedDEK, nonce []byte) ([]byte, []byte, error),
+) error {
+	const (
+		selectDEKs = `
+            SELECT id, encrypted_dek, dek_nonce FROM user_data
+            WHERE user_id = $1 AND NOT client_encrypted
+            FOR UPDATE;
+        `
+		updateDEK = `
+            UPDATE user_data SET encrypted_dek = $1, dek_nonce = $2
+            WHERE id = $3;
+        `
+		selectVersionDEKs = `
+            SELECT v.id, v.encrypted_dek, v.dek_nonce FROM user_data_versions v
+            JOIN user_data d ON d.id = v.user_data_id
+            WHERE d.user_id = $1 AND NOT d.client_encrypted
+            FOR UPDATE OF v;
+        `
+		updateVersionDEK = `
+            UPDATE user_data_versions SET encrypted_dek = $1, dek_nonce = $2
+            WHERE id = $3;
+        `
+		selectFolderDEKs = `
+            SELECT id, encrypted_dek, dek_nonce FROM folders
+            WHERE user_id = $1 AND NOT client_encrypted
+            FOR UPDATE;
+        `
+		updateFolderDEK = `
+            UPDATE folders SET encrypted_dek = $1, dek_nonce = $2
+            WHERE id = $3;
+        `
+		selectUploadDEKs = `
+            SELECT id, encrypted_dek, dek_nonce FROM uploads
+            WHERE user_id = $1
+            FOR UPDATE;
+        `
+		updateUploadDEK = `
+            UPDATE uploads SET encrypted_dek = $1, dek_nonce = $2
+            WHERE id = $3;
+        `
+	)
+
+	if err := rewrapDEKs(ctx, tx, selectDEKs, updateDEK, userID, rewrapDEK); err != nil {
+		return err
+	}
+	if err := rewrapDEKs(ctx, tx, selectVersionDEKs, updateVersionDEK, userID, rewrapDEK); err != nil {
+		return err
+	}
+	if err := rewrapDEKs(ctx, tx, selectFolderDEKs, updateFolderDEK, userID, rewrapDEK); err != nil {
+		return err
+	}
+
+	return rewrapDEKs(ctx, tx, selectUploadDEKs, updateUploadDEK, userID, rewrapDEK)
+}
+
+// rewrapDEKs re-wraps within tx the data keys of userID that selectSQL returns as (id, dek, nonce)
+// rows, writing each one back with updateSQL. The id is passed back as scanned, so tables with
+// text keys such as uploads are handled as well.
+func rewrapDEKs(
+	ctx context.Context,
+	tx pgx.Tx,
+	selectSQL, updateSQL string,
+	userID int,
+	rewrapDEK func(encryptedDEK, nonce []byte) ([]byte, []byte, error),
+) error {
+	type wrappedDEK struct {
+		id         any
+		dek, nonce []byte
+	}
+
+	rows, err := tx.Query(ctx, selectSQL, userID)
+	if err != nil {
+		return fmt.Errorf("failed to select data keys: %w", err)
+	}
+	deks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (wrappedDEK, error) {
+		var d wrappedDEK
+		return d, row.Scan(&d.id, &d.dek, &d.nonce)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read data keys: %w", err)
+	}
+
+	for _, d := range deks {
+		dek, nonce, errWrap := rewrapDEK(d.dek, d.nonce)
+		if errWrap != nil {
+			return fmt.Errorf("failed to rewrap data key %v: %w", d.id, errWrap)
+		}
+		if _, err := tx.Exec(ctx, updateSQL, dek, nonce, d.id); err != nil {
+			return fmt.Errorf("failed to update data key %v: %w", d.id, err)
+		}
+	}
+
+	return nil
+}
+
+// updateMasterKey overwrites the user_keys row of key.UserID within tx.
+func updateMasterKey(ctx context.Context, tx pgx.Tx, key *models.EncryptedMK) error {
+	const updateSQL = `
+        UPDATE user_keys
+        SET encrypted_master_key = $2, nonce = $3, kdf_version = $4, kdf_algorithm = $5, kdf_salt = $6,
+            kdf_time = $7, kdf_memory = $8, kdf_threads = $9, password_wrapped_key = $10, server_key_id = $11,
+            updated_at = now()
+        WHERE user_id = $1;
     `
 
-	var userData models.DBUserData
+	algorithm, salt, kdfTime, memory, threads := kdfColumns(key.KDF)
 
-	err := p.DB.QueryRow(ctx, selectSQL, userDataID).Scan(
-		&userData.UserID,
-		&userData.Type,
-		&userData.MinioObjectID,
-		&userData.EncryptedData,
-		&userData.DataNonce,
-		&userData.EncryptedDek,
-		&userData.DekNonce,
-		&userData.Meta,
+	tag, err := tx.Exec(
+		ctx,
+		updateSQL,
+		key.UserID,
+		key.EncryptedMK,
+		key.Nonce,
+		key.KDFVersion,
+		algorithm,
+		salt,
+		kdfTime,
+		memory,
+		threads,
+		key.PasswordWrappedMK,
+		serverKeyID(key),
 	)
 	if err != nil {
-		return &userData, fmt.Errorf("failed to get user data: %w", err)
+		return fmt.Errorf("failed to update master key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrMasterKeyNotFound
 	}
 
-	return &userData, err
+	return nil
 }
 
-// GetUserDataList returns a list of user data items for a given user.
+// UpdatePassword sets a new password hash and re-wraps the master key for the new password.
+//
+// The user's key row is locked for the transaction and handed to rewrap, which replaces it in
+// place, so a concurrent change of the key cannot be overwritten with a stale copy. When rewrap
+// replaces the master key itself, it returns a function re-wrapping the DEKs for the new key,
+// and all data keys of the user are re-wrapped as by UpdateMasterKey. Everything is written in
+// the same transaction, so the password, the key that it opens and the DEKs never get out of
+// sync.
 //
 // Parameters:
 //   - ctx: Context for the operation.
 //   - userID: User ID.
+//   - passwordHash: The bcrypt hash of the new password.
+//   - rewrap: Function verifying the old password against the key and re-wrapping it in place;
+//     it returns the DEK re-wrapping function when the master key was replaced, or nil.
 //
 // Returns:
-//   - []models.UserDataListItem: List of user data items.
-//   - error: An error if the query fails.
-func (p *Storage) GetUserDataList(ctx context.Context, userID int) ([]models.UserDataListItem, error) {
-	const selectSQL = `
-        SELECT id,
-               user_id, 
-               type,
-               meta,
-               created_at
-        FROM user_data 
-        WHERE user_id = $1
-        ORDER BY id DESC;
-    `
-
-	rows, err := p.DB.Query(ctx, selectSQL, userID)
+//   - error: models.ErrUserNotFound or models.ErrMasterKeyNotFound if a row is missing, the error
+//     of rewrap, or an error if the operation fails.
+func (p *Storage) UpdatePassword(
+	ctx context.Context,
+	userID int,
+	passwordHash string,
+	rewrap func(key *models.EncryptedMK) (func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error),
+) error {
+	tx, err := p.DB.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query user data list: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows.Close()
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	var result []models.UserDataListItem
-	for rows.Next() {
-		var data models.UserDataListItem
-		err := rows.Scan(
-			&data.ID,
-			&data.UserID,
-			&data.Type,
-			&data.Meta,
-			&data.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+	key, err := scanMasterKey(tx.QueryRow(ctx, selectMasterKeySQL+" FOR UPDATE", userID), userID)
+	if err != nil {
+		return err
+	}
+	rewrapDEK, err := rewrap(key)
+	if err != nil {
+		return fmt.Errorf("failed to rewrap master key: %w", err)
+	}
+	if rewrapDEK != nil {
+		if err := rewrapUserDEKs(ctx, tx, userID, rewrapDEK); err != nil {
+			return err
 		}
-		result = append(result, data)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+	if err := updatePasswordHash(ctx, tx, userID, passwordHash); err != nil {
+		return err
 	}
 
-	return result, nil
+	key.UserID = userID
+	if err := updateMasterKey(ctx, tx, key); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
 
-// DeleteUserData deletes a user data record by its ID.
+// UpdateClientPassword sets a new password hash and client key material for a zero-knowledge user.
+//
+// Both rows are written in a single transaction.
 //
 // Parameters:
 //   - ctx: Context for the operation.
-//   - userDataID: ID of the user data record to delete.
+//   - userID: User ID.
+//   - passwordHash: The bcrypt hash of the new authentication key.
+//   - key: The new KDF parameters and the master key re-wrapped by the client.
 //
 // Returns:
-//   - error: An error if not found or deletion fails.
-func (p *Storage) DeleteUserData(ctx context.Context, userDataID int) error {
-	const deleteSQL = `
-        DELETE FROM user_data 
-        WHERE id = $1
-        RETURNING id;
+//   - error: models.ErrUserNotFound or models.ErrClientKeyNotFound if a row is missing, or an error if the operation fails.
+func (p *Storage) UpdateClientPassword(ctx context.Context, userID int, passwordHash string, key *models.ClientKey) error {
+	const updateKey = `
+        UPDATE user_client_keys
+        SET kdf_algorithm = $2, kdf_salt = $3, kdf_time = $4, kdf_memory = $5, kdf_threads = $6,
+            wrapped_master_key = $7, updated_at = now()
+        WHERE user_id = $1;
     `
 
-	var deletedID int
-	err := p.DB.QueryRow(ctx, deleteSQL, userDataID).Scan(&deletedID)
+	tx, err := p.DB.Begin(ctx)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("user data with ID %d not found", userDataID)
-		}
-		return fmt.Errorf("failed to delete user data: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := updatePasswordHash(ctx, tx, userID, passwordHash); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(
+		ctx,
+		updateKey,
+		userID,
+		key.KDF.Algorithm,
+		key.KDF.Salt,
+		int(key.KDF.Time),
+		int(key.KDF.Memory),
+		int(key.KDF.Threads),
+		key.WrappedMasterKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update client key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrClientKeyNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updatePasswordHash sets the password hash of a user within tx.
+func updatePasswordHash(ctx context.Context, tx pgx.Tx, userID int, passwordHash string) error {
+	const updateSQL = `
+        UPDATE users SET password_hash = $2, updated_at = now()
+        WHERE id = $1;
+    `
+
+	tag, err := tx.Exec(ctx, updateSQL, userID, passwordHash)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
 	}
 
 	return nil
 }
+
+// GetMasterKey retrieves the encrypted master key for a user.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - *models.EncryptedMK: The encrypted master key, nonce and KDF parameters.
+//   - error: An error if not found or query fails.
+func (p *Storage) GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error) {
+	return scanMasterKey(p.DB.QueryRow(ctx, selectMasterKeySQL, userID), userID)
+}
+
+// selectMasterKeySQL selects the columns scanned by scanMasterKey for the user $1.
+const selectMasterKeySQL = `
+        SELECT server_key_id, encrypted_master_key, nonce, kdf_version, kdf_algorithm, kdf_salt,
+               kdf_time, kdf_memory, kdf_threads, password_wrapped_key
+        FROM user_keys
+        WHERE user_id = $1`
+
+// scanMasterKey scans a row of selectMasterKeySQL.
+func scanMasterKey(row pgx.Row, userID int) (*models.EncryptedMK, error) {
+	var (
+		encryptedMK              = models.EncryptedMK{UserID: userID}
+		algorithm                *string
+		salt                     []byte
+		kdfTime, memory, threads *int
+	)
+
+	err := row.Scan(
+		&encryptedMK.ServerKeyID,
+		&encryptedMK.EncryptedMK,
+		&encryptedMK.Nonce,
+		&encryptedMK.KDFVersion,
+		&algorithm,
+		&salt,
+		&kdfTime,
+		&memory,
+		&threads,
+		&encryptedMK.PasswordWrappedMK,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrMasterKeyNotFound
+		}
+		return nil, err
+	}
+
+	if algorithm != nil && kdfTime != nil && memory != nil && threads != nil {
+		encryptedMK.KDF = &models.KDFParams{
+			Algorithm: *algorithm,
+			Salt:      salt,
+			Time:      uint32(*kdfTime),
+			Memory:    uint32(*memory),
+			Threads:   uint8(*threads),
+		}
+	}
+
+	return &encryptedMK, nil
+}
+
+// serverKeyID returns the server key ID of key, defaulting to the ID used before key IDs existed.
+func serverKeyID(key *models.EncryptedMK) string {
+	if key.ServerKeyID == "" {
+		return constants.DefaultServerKeyID
+	}
+	return key.ServerKeyID
+}
+
+// kdfColumns splits optional KDF parameters into nullable column values.
+func kdfColumns(p *models.KDFParams) (algorithm *string, salt []byte, kdfTime, memory, threads *int) {
+	if p == nil {
+		return nil, nil, nil, nil, nil
+	}
+
+	t, m, th := int(p.Time), int(p.Memory), int(p.Threads)
+	return &p.Algorithm, p.Salt, &t, &m, &th
+}
+
+// CreateKeyRotationJob starts a job re-wrapping all master keys with the server key targetKeyID.
+//
+// A partial unique index allows a single running job, so concurrent starts are rejected.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - targetKeyID: The ID of the server key master keys are re-wrapped with.
+//
+// Returns:
+//   - *models.KeyRotationJob: The new job.
+//   - error: models.ErrKeyRotationInProgress if a job is already running, or an error if the operation fails.
+func (p *Storage) CreateKeyRotationJob(ctx context.Context, targetKeyID string) (*models.KeyRotationJob, error) {
+	const insertSQL = `
+        INSERT INTO key_rotation_jobs (target_key_id, status)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+        RETURNING id, started_at, updated_at;
+    `
+
+	job := models.KeyRotationJob{TargetKeyID: targetKeyID, Status: models.JobStatusRunning}
+
+	err := p.DB.QueryRow(ctx, insertSQL, targetKeyID, models.JobStatusRunning).
+		Scan(&job.ID, &job.StartedAt, &job.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrKeyRotationInProgress
+		}
+		return nil, fmt.Errorf("failed to create key rotation job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetKeyRotationJob returns the most recent key rotation job.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//
+// Returns:
+//   - *models.KeyRotationJob: The job with the number of master keys and TOTP secrets not yet
+//     wrapped with its target key.
+//   - error: models.ErrKeyRotationNotFound if no rotation was started, or an error if the query fails.
+func (p *Storage) GetKeyRotationJob(ctx context.Context) (*models.KeyRotationJob, error) {
+	const selectSQL = `
+        SELECT j.id, j.target_key_id, j.status, j.last_user_id, j.rewrapped, j.error, j.started_at, j.updated_at,
+               (SELECT count(*) FROM user_keys k WHERE k.server_key_id <> j.target_key_id) +
+               (SELECT count(*) FROM users u WHERE u.totp_secret IS NOT NULL AND u.totp_secret_key_id <> j.target_key_id)
+        FROM key_rotation_jobs j
+        ORDER BY j.id DESC
+        LIMIT 1;
+    `
+
+	var job models.KeyRotationJob
+
+	err := p.DB.QueryRow(ctx, selectSQL).Scan(
+		&job.ID,
+		&job.TargetKeyID,
+		&job.Status,
+		&job.LastUserID,
+		&job.Rewrapped,
+		&job.Error,
+		&job.StartedAt,
+		&job.UpdatedAt,
+		&job.Remaining,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrKeyRotationNotFound
+		}
+		return nil, fmt.Errorf("failed to get key rotation job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// RewrapMasterKeysBatch re-wraps the next batch of master keys of a running key rotation job.
+//
+// Keys are processed in user ID order, starting after the job's last processed user. The keys
+// and the job's progress are updated in a single transaction, so an interrupted job resumes
+// exactly where the last committed batch ended.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - jobID: The key rotation job ID.
+//   - limit: The maximum number of keys to re-wrap.
+//   - rewrap: Function re-wrapping a master key in place with the target server key.
+//
+// Returns:
+//   - int: The number of re-wrapped keys; zero when no keys are left.
+//   - error: models.ErrKeyRotationNotFound if the job is not running, or an error if the operation fails.
+func (p *Storage) RewrapMasterKeysBatch(
+	ctx context.Context,
+	jobID, limit int,
+	rewrap func(key *models.EncryptedMK) error,
+) (int, error) {
+	const (
+		selectJob = `
+            SELECT target_key_id, last_user_id FROM key_rotation_jobs
+            WHERE id = $1 AND status = $2
+            FOR UPDATE;
+        `
+		selectKeys = `
+            SELECT user_id, server_key_id, encrypted_master_key, nonce FROM user_keys
+            WHERE user_id > $1 AND server_key_id <> $2
+            ORDER BY user_id
+            LIMIT $3
+            FOR UPDATE;
+        `
+		updateKey = `
+            UPDATE user_keys SET server_key_id = $2, encrypted_master_key = $3, nonce = $4, updated_at = now()
+            WHERE user_id = $1;
+        `
+		updateJob = `
+            UPDATE key_rotation_jobs SET last_user_id = $2, rewrapped = rewrapped + $3, updated_at = now()
+            WHERE id = $1;
+        `
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		targetKeyID string
+		lastUserID  int
+	)
+	if err := tx.QueryRow(ctx, selectJob, jobID, models.JobStatusRunning).Scan(&targetKeyID, &lastUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.ErrKeyRotationNotFound
+		}
+		return 0, fmt.Errorf("failed to lock key rotation job: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, selectKeys, lastUserID, targetKeyID, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select master keys: %w", err)
+	}
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.EncryptedMK, error) {
+		var k models.EncryptedMK
+		return &k, row.Scan(&k.UserID, &k.ServerKeyID, &k.EncryptedMK, &k.Nonce)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read master keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	for _, k := range keys {
+		if err := rewrap(k); err != nil {
+			return 0, fmt.Errorf("failed to rewrap master key of user %d: %w", k.UserID, err)
+		}
+		if _, err := tx.Exec(ctx, updateKey, k.UserID, serverKeyID(k), k.EncryptedMK, k.Nonce); err != nil {
+			return 0, fmt.Errorf("failed to update master key of user %d: %w", k.UserID, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, updateJob, jobID, keys[len(keys)-1].UserID, len(keys)); err != nil {
+		return 0, fmt.Errorf("failed to update key rotation job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// RewrapTOTPSecretsBatch re-wraps the next batch of TOTP secrets of a running key rotation job.
+//
+// Re-wrapped secrets no longer match the query, so no cursor is needed; the secrets and the
+// job's progress are updated in a single transaction.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - jobID: The key rotation job ID.
+//   - limit: The maximum number of secrets to re-wrap.
+//   - rewrap: Function re-wrapping a secret in place with the target server key.
+//
+// Returns:
+//   - int: The number of re-wrapped secrets; zero when none are left.
+//   - error: models.ErrKeyRotationNotFound if the job is not running, or an error if the operation fails.
+func (p *Storage) RewrapTOTPSecretsBatch(
+	ctx context.Context,
+	jobID, limit int,
+	rewrap func(secret *models.WrappedSecret) error,
+) (int, error) {
+	const (
+		selectJob = `
+            SELECT target_key_id FROM key_rotation_jobs
+            WHERE id = $1 AND status = $2
+            FOR UPDATE;
+        `
+		selectSecrets = `
+            SELECT id, totp_secret_key_id, totp_secret, totp_secret_nonce FROM users
+            WHERE totp_secret IS NOT NULL AND totp_secret_key_id <> $1
+            ORDER BY id
+            LIMIT $2
+            FOR UPDATE;
+        `
+		updateSecret = `
+            UPDATE users SET totp_secret_key_id = $2, totp_secret = $3, totp_secret_nonce = $4, updated_at = now()
+            WHERE id = $1;
+        `
+		updateJob = `
+            UPDATE key_rotation_jobs SET rewrapped = rewrapped + $2, updated_at = now()
+            WHERE id = $1;
+        `
+	)
+
+	type userSecret struct {
+		userID int
+		secret *models.WrappedSecret
+	}
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var targetKeyID string
+	if err := tx.QueryRow(ctx, selectJob, jobID, models.JobStatusRunning).Scan(&targetKeyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.ErrKeyRotationNotFound
+		}
+		return 0, fmt.Errorf("failed to lock key rotation job: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, selectSecrets, targetKeyID, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select TOTP secrets: %w", err)
+	}
+	secrets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (userSecret, error) {
+		var (
+			us         userSecret
+			ciphertext string
+		)
+		us.secret = &models.WrappedSecret{}
+		if err := row.Scan(&us.userID, &us.secret.KeyID, &ciphertext, &us.secret.Nonce); err != nil {
+			return us, err
+		}
+		us.secret.Ciphertext, err = base64.StdEncoding.DecodeString(ciphertext)
+		return us, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read TOTP secrets: %w", err)
+	}
+	if len(secrets) == 0 {
+		return 0, nil
+	}
+
+	for _, us := range secrets {
+		if err := rewrap(us.secret); err != nil {
+			return 0, fmt.Errorf("failed to rewrap TOTP secret of user %d: %w", us.userID, err)
+		}
+		ciphertext := base64.StdEncoding.EncodeToString(us.secret.Ciphertext)
+		if _, err := tx.Exec(ctx, updateSecret, us.userID, us.secret.KeyID, ciphertext, us.secret.Nonce); err != nil {
+			return 0, fmt.Errorf("failed to update TOTP secret of user %d: %w", us.userID, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, updateJob, jobID, len(secrets)); err != nil {
+		return 0, fmt.Errorf("failed to update key rotation job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(secrets), nil
+}
+
+// FinishKeyRotationJob sets the final status of a key rotation job.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - jobID: The key rotation job ID.
+//   - status: models.JobStatusDone or models.JobStatusFailed.
+//   - reason: The failure reason; empty for finished jobs.
+//
+// Returns:
+//   - error: models.ErrKeyRotationNotFound if the job does not exist, or an error if the operation fails.
+func (p *Storage) FinishKeyRotationJob(ctx context.Context, jobID int, status, reason string) error {
+	const updateSQL = `
+        UPDATE key_rotation_jobs SET status = $2, error = $3, updated_at = now()
+        WHERE id = $1;
+    `
+
+	tag, err := p.DB.Exec(ctx, updateSQL, jobID, status, reason)
+	if err != nil {
+		return fmt.Errorf("failed to finish key rotation job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrKeyRotationNotFound
+	}
+
+	return nil
+}
+
+// GetTOTP retrieves the two-factor authentication state of a user.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - *models.TOTP: The sealed secret, whether it is enabled and the last accepted time step.
+//   - error: models.ErrTOTPNotEnrolled if the user has no secret, or an error if the query fails.
+func (p *Storage) GetTOTP(ctx context.Context, userID int) (*models.TOTP, error) {
+	const selectSQL = `
+        SELECT totp_secret, totp_secret_key_id, totp_secret_nonce, COALESCE(totp_enabled, FALSE),
+               COALESCE(totp_last_step, 0)
+        FROM users
+        WHERE id = $1;
+    `
+
+	var (
+		totp       = models.TOTP{UserID: userID, Secret: &models.WrappedSecret{}}
+		ciphertext *string
+		keyID      *string
+	)
+
+	err := p.DB.QueryRow(ctx, selectSQL, userID).Scan(&ciphertext, &keyID, &totp.Secret.Nonce, &totp.Enabled, &totp.LastStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get TOTP: %w", err)
+	}
+	if ciphertext == nil || keyID == nil {
+		return nil, models.ErrTOTPNotEnrolled
+	}
+
+	totp.Secret.KeyID = *keyID
+	totp.Secret.Ciphertext, err = base64.StdEncoding.DecodeString(*ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode TOTP secret: %w", err)
+	}
+
+	return &totp, nil
+}
+
+// SaveTOTPSecret stores a new, not yet confirmed TOTP secret, replacing any pending one.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - secret: The TOTP secret sealed with the server key.
+//
+// Returns:
+//   - error: models.ErrUserNotFound if there is no such user, or an error if the operation fails.
+func (p *Storage) SaveTOTPSecret(ctx context.Context, userID int, secret *models.WrappedSecret) error {
+	const updateSQL = `
+        UPDATE users
+        SET totp_secret = $2, totp_secret_key_id = $3, totp_secret_nonce = $4, totp_enabled = FALSE,
+            totp_last_step = NULL, updated_at = now()
+        WHERE id = $1;
+    `
+
+	ciphertext := base64.StdEncoding.EncodeToString(secret.Ciphertext)
+	tag, err := p.DB.Exec(ctx, updateSQL, userID, ciphertext, secret.KeyID, secret.Nonce)
+	if err != nil {
+		return fmt.Errorf("failed to save TOTP secret: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// EnableTOTP confirms the pending TOTP secret and replaces the user's recovery codes.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - step: The time step of the confirming code.
+//   - recoveryCodeHashes: Hashes of the new one-time recovery codes.
+//
+// Returns:
+//   - error: models.ErrTOTPNotEnrolled if no secret is pending, or an error if the operation fails.
+func (p *Storage) EnableTOTP(ctx context.Context, userID int, step int64, recoveryCodeHashes [][]byte) error {
+	const (
+		enableSQL = `
+            UPDATE users SET totp_enabled = TRUE, totp_last_step = $2, updated_at = now()
+            WHERE id = $1 AND totp_secret IS NOT NULL;
+        `
+		deleteCodes = `DELETE FROM user_recovery_codes WHERE user_id = $1;`
+		insertCode  = `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2);`
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, enableSQL, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrTOTPNotEnrolled
+	}
+
+	if _, err := tx.Exec(ctx, deleteCodes, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.Exec(ctx, insertCode, userID, hash); err != nil {
+			return fmt.Errorf("failed to save recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DisableTOTP removes the TOTP secret and the recovery codes of a user.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - error: models.ErrUserNotFound if there is no such user, or an error if the operation fails.
+func (p *Storage) DisableTOTP(ctx context.Context, userID int) error {
+	const (
+		disableSQL = `
+            UPDATE users
+            SET totp_secret = NULL, totp_secret_key_id = NULL, totp_secret_nonce = NULL, totp_enabled = FALSE,
+                totp_last_step = NULL, updated_at = now()
+            WHERE id = $1;
+        `
+		deleteCodes = `DELETE FROM user_recovery_codes WHERE user_id = $1;`
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, disableSQL, userID)
+	if err != nil {
+		return fmt.Errorf("failed to disable TOTP: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+	if _, err := tx.Exec(ctx, deleteCodes, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTOTPStep records the time step of an accepted TOTP code.
+//
+// The update only succeeds for steps after the last accepted one, so concurrent logins cannot
+// use the same code twice.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - step: The time step of the accepted code.
+//
+// Returns:
+//   - error: models.ErrTOTPCodeReused if the step was already used, or an error if the operation fails.
+func (p *Storage) UpdateTOTPStep(ctx context.Context, userID int, step int64) error {
+	const updateSQL = `
+        UPDATE users SET totp_last_step = $2
+        WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2);
+    `
+
+	tag, err := p.DB.Exec(ctx, updateSQL, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to update TOTP step: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrTOTPCodeReused
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - codeHash: The hash of the recovery code.
+//
+// Returns:
+//   - error: models.ErrRecoveryCodeInvalid if no unused code matches, or an error if the operation fails.
+func (p *Storage) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) error {
+	const updateSQL = `
+        UPDATE user_recovery_codes SET used_at = now()
+        WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+    `
+
+	tag, err := p.DB.Exec(ctx, updateSQL, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrRecoveryCodeInvalid
+	}
+
+	return nil
+}
+
+// CreateTwoFactorChallenge records the pending login of a two-factor token.
+//
+// Expired challenges of the user are removed at the same time.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - jti: The ID of the two-factor token.
+//   - expiresAt: When the token expires.
+//
+// Returns:
+//   - error: An error if the operation fails.
+func (p *Storage) CreateTwoFactorChallenge(ctx context.Context, userID int, jti string, expiresAt time.Time) error {
+	const (
+		deleteExpired = `DELETE FROM two_factor_challenges WHERE user_id = $1 AND expires_at <= now();`
+		insertSQL     = `INSERT INTO two_factor_challenges (jti, user_id, expires_at) VALUES ($1, $2, $3);`
+	)
+
+	if _, err := p.DB.Exec(ctx, deleteExpired, userID); err != nil {
+		return fmt.Errorf("failed to delete expired two-factor challenges: %w", err)
+	}
+	if _, err := p.DB.Exec(ctx, insertSQL, jti, userID, expiresAt); err != nil {
+		return fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+
+	return nil
+}
+
+// StartTwoFactorAttempt counts an attempt at the second factor before its code is checked.
+//
+// The attempt is counted as failed against the user and, when jti is set, against the
+// challenge of the two-factor token; FinishTwoFactorAttempt clears it once the code is
+// accepted. Counting first means that concurrent guesses cannot exceed the limits. The user is
+// locked out for limits.Lockout by the attempt that reaches limits.UserAttempts.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - jti: The ID of the two-factor token, or empty for a second factor given outside a login.
+//   - limits: The allowed attempts per token and per user.
+//
+// Returns:
+//   - error: models.ErrTwoFactorLocked if the user is locked out, models.ErrTwoFactorTokenUsed
+//     if the token is used, expired or out of attempts, or an error if the operation fails.
+func (p *Storage) StartTwoFactorAttempt(ctx context.Context, userID int, jti string, limits models.TwoFactorLimits) error {
+	const (
+		selectUser = `
+            SELECT totp_locked_until IS NOT NULL AND totp_locked_until > now() FROM users
+            WHERE id = $1
+            FOR UPDATE;
+        `
+		updateChallenge = `
+            UPDATE two_factor_challenges SET failed_attempts = failed_attempts + 1
+            WHERE jti = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > now() AND failed_attempts < $3;
+        `
+		updateUser = `
+            UPDATE users
+            SET totp_failed_attempts = CASE WHEN totp_failed_attempts + 1 >= $2 THEN 0 ELSE totp_failed_attempts + 1 END,
+                totp_locked_until    = CASE WHEN totp_failed_attempts + 1 >= $2 THEN $3 ELSE totp_locked_until END
+            WHERE id = $1;
+        `
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var locked bool
+	if err := tx.QueryRow(ctx, selectUser, userID).Scan(&locked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	if locked {
+		return models.ErrTwoFactorLocked
+	}
+
+	if jti != "" {
+		tag, errChallenge := tx.Exec(ctx, updateChallenge, jti, userID, limits.TokenAttempts)
+		if errChallenge != nil {
+			return fmt.Errorf("failed to count two-factor attempt: %w", errChallenge)
+		}
+		if tag.RowsAffected() == 0 {
+			return models.ErrTwoFactorTokenUsed
+		}
+	}
+
+	if _, err := tx.Exec(ctx, updateUser, userID, limits.UserAttempts, time.Now().Add(limits.Lockout)); err != nil {
+		return fmt.Errorf("failed to count two-factor attempt: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FinishTwoFactorAttempt records an accepted second factor.
+//
+// The failed attempts and any lockout of the user are cleared, and the challenge of the
+// two-factor token, when jti is set, is marked used so the token cannot complete another login.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - jti: The ID of the two-factor token, or empty for a second factor given outside a login.
+//
+// Returns:
+//   - error: models.ErrTwoFactorTokenUsed if the token was used concurrently, or an error if the operation fails.
+func (p *Storage) FinishTwoFactorAttempt(ctx context.Context, userID int, jti string) error {
+	const (
+		useChallenge = `
+            UPDATE two_factor_challenges SET used_at = now()
+            WHERE jti = $1 AND user_id = $2 AND used_at IS NULL;
+        `
+		resetUser = `UPDATE users SET totp_failed_attempts = 0, totp_locked_until = NULL WHERE id = $1;`
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if jti != "" {
+		tag, errChallenge := tx.Exec(ctx, useChallenge, jti, userID)
+		if errChallenge != nil {
+			return fmt.Errorf("failed to use two-factor challenge: %w", errChallenge)
+		}
+		if tag.RowsAffected() == 0 {
+			return models.ErrTwoFactorTokenUsed
+		}
+	}
+
+	if _, err := tx.Exec(ctx, resetUser, userID); err != nil {
+		return fmt.Errorf("failed to reset two-factor attempts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreateSession starts a session for a user with its first refresh token.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - session: The user ID, the client details and when the refresh token expires.
+//   - tokenHash: The hash of the refresh token.
+//
+// Returns:
+//   - int: The new session ID.
+//   - error: An error if the operation fails.
+func (p *Storage) CreateSession(ctx context.Context, session *models.Session, tokenHash []byte) (int, error) {
+	const (
+		insertSession = `
+            INSERT INTO sessions (user_id, device_name, client_version, ip, expires_at)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id;
+        `
+		insertToken = `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3);`
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var sessionID int
+	err = tx.QueryRow(
+		ctx,
+		insertSession,
+		session.UserID,
+		session.DeviceName,
+		session.ClientVersion,
+		session.IP,
+		session.ExpiresAt,
+	).Scan(&sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create session: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertToken, sessionID, tokenHash, session.ExpiresAt); err != nil {
+		return 0, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return sessionID, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one in the same session.
+//
+// The presented token is marked as used. Presenting a used token again means it was stolen
+// from one of the parties holding it, so the whole session is revoked.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - tokenHash: The hash of the presented refresh token.
+//   - newHash: The hash of the replacement refresh token.
+//   - expiresAt: When the replacement expires.
+//
+// Returns:
+//   - *models.Session: The session the tokens belong to.
+//   - error: models.ErrRefreshTokenInvalid for unknown, expired or revoked tokens,
+//     models.ErrRefreshTokenReused for a token that was already exchanged, or an error if the operation fails.
+func (p *Storage) RotateRefreshToken(
+	ctx context.Context,
+	tokenHash, newHash []byte,
+	expiresAt time.Time,
+) (*models.Session, error) {
+	const (
+		selectSQL = `
+            SELECT rt.id, rt.used_at IS NOT NULL, rt.expires_at <= now(), s.revoked_at IS NOT NULL,
+                   s.id, s.user_id, s.created_at
+            FROM refresh_tokens rt
+            JOIN sessions s ON s.id = rt.session_id
+            WHERE rt.token_hash = $1
+            FOR UPDATE OF rt, s;
+        `
+		useSQL    = `UPDATE refresh_tokens SET used_at = now() WHERE id = $1;`
+		insertSQL = `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3);`
+		touchSQL  = `
+            UPDATE sessions SET last_used_at = now(), expires_at = $2
+            WHERE id = $1
+            RETURNING last_used_at, expires_at;
+        `
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		tokenID                int
+		used, expired, revoked bool
+		session                models.Session
+	)
+	err = tx.QueryRow(ctx, selectSQL, tokenHash).
+		Scan(&tokenID, &used, &expired, &revoked, &session.ID, &session.UserID, &session.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrRefreshTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	switch {
+	case revoked || expired:
+		return nil, models.ErrRefreshTokenInvalid
+	case used:
+		if err := revokeSession(ctx, tx, session.ID, "refresh token reuse"); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil, models.ErrRefreshTokenReused
+	}
+
+	if _, err := tx.Exec(ctx, useSQL, tokenID); err != nil {
+		return nil, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertSQL, session.ID, newHash, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	if err := tx.QueryRow(ctx, touchSQL, session.ID, expiresAt).Scan(&session.LastUsedAt, &session.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RevokeSession revokes an active session of a user, invalidating its refresh and access tokens.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - sessionID: The session ID.
+//   - reason: Why the session was revoked, kept for auditing.
+//
+// Returns:
+//   - error: models.ErrSessionNotFound if the user has no such active session, or an error if the operation fails.
+func (p *Storage) RevokeSession(ctx context.Context, userID, sessionID int, reason string) error {
+	const revokeSQL = `
+        UPDATE sessions SET revoked_at = now(), revoke_reason = $3
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+    `
+
+	tag, err := p.DB.Exec(ctx, revokeSQL, sessionID, userID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions revokes all active sessions of a user except one.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - keepSessionID: The session to keep, usually the one making the request.
+//   - reason: Why the sessions were revoked, kept for auditing.
+//
+// Returns:
+//   - int: The number of revoked sessions.
+//   - error: An error if the operation fails.
+func (p *Storage) RevokeOtherSessions(ctx context.Context, userID, keepSessionID int, reason string) (int, error) {
+	const revokeSQL = `
+        UPDATE sessions SET revoked_at = now(), revoke_reason = $3
+        WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL AND expires_at > now();
+    `
+
+	tag, err := p.DB.Exec(ctx, revokeSQL, userID, keepSessionID, reason)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ListSessions returns the active sessions of a user, most recently seen first.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - []*models.Session: The sessions that are neither revoked nor expired.
+//   - error: An error if the query fails.
+func (p *Storage) ListSessions(ctx context.Context, userID int) ([]*models.Session, error) {
+	const selectSQL = `
+        SELECT id, user_id, device_name, client_version, ip, created_at, last_used_at, expires_at
+        FROM sessions
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+        ORDER BY last_used_at DESC, id DESC;
+    `
+
+	rows, err := p.DB.Query(ctx, selectSQL, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		var s models.Session
+		err := rows.Scan(&s.ID, &s.UserID, &s.DeviceName, &s.ClientVersion, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// TouchSession reports whether a session exists, is not revoked and has not expired, and
+// records that it was seen from ip.
+//
+// The last-seen time is written at most once a minute, so busy clients do not turn every
+// request into an update.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - sessionID: The session ID.
+//   - ip: The address of the request, left unchanged when empty.
+//
+// Returns:
+//   - bool: Whether tokens of the session are still accepted.
+//   - error: An error if the query fails.
+func (p *Storage) TouchSession(ctx context.Context, sessionID int, ip string) (bool, error) {
+	const touchSQL = `
+        WITH active AS (
+            SELECT id, last_used_at, ip
+            FROM sessions
+            WHERE id = $1 AND revoked_at IS NULL AND expires_at > now()
+        ), touched AS (
+            UPDATE sessions s SET last_used_at = now(), ip = COALESCE(NULLIF($2, ''), s.ip)
+            FROM active a
+            WHERE s.id = a.id
+              AND (a.last_used_at < now() - interval '1 minute' OR ($2 <> '' AND a.ip <> $2))
+        )
+        SELECT EXISTS (SELECT 1 FROM active);
+    `
+
+	var active bool
+	if err := p.DB.QueryRow(ctx, touchSQL, sessionID, ip).Scan(&active); err != nil {
+		return false, fmt.Errorf("failed to touch session: %w", err)
+	}
+
+	return active, nil
+}
+
+// revokeSession revokes a session inside tx.
+func revokeSession(ctx context.Context, tx pgx.Tx, sessionID int, reason string) error {
+	const revokeSQL = `
+        UPDATE sessions SET revoked_at = now(), revoke_reason = $2
+        WHERE id = $1 AND revoked_at IS NULL;
+    `
+
+	if _, err := tx.Exec(ctx, revokeSQL, sessionID, reason); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// SaveUserData stores encrypted user data in the database.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userData: Pointer to the DBUserData to store.
+//
+// Returns:
+//   - int: The new record's ID.
+//   - error: An error if the operation fails.
+func (p *Storage) SaveUserData(ctx context.Context, userData *models.DBUserData) (int, error) {
+	const insertSQL = `
+        INSERT INTO user_data (user_id, type, minio_object_id, encrypted_data, data_nonce, encrypted_dek, dek_nonce, meta, client_encrypted,
+                               meta_secrets, meta_secrets_nonce)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id;
+    `
+
+	var id int
+
+	err := p.DB.QueryRow(
+		ctx,
+		insertSQL,
+		userData.UserID,
+		userData.Type,
+		userData.MinioObjectID,
+		userData.EncryptedData,
+		userData.DataNonce,
+		userData.EncryptedDek,
+		userData.DekNonce,
+		userData.Meta,
+		userData.ClientEncrypted,
+		userData.MetaSecrets,
+		userData.MetaSecretsNonce,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save user data: %w", err)
+	}
+
+	return id, err
+}
+
+// GetUserData retrieves a user data record by its ID.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//
+// Returns:
+//   - *models.DBUserData: The user data record.
+//   - error: An error if not found or query fails.
+func (p *Storage) GetUserData(ctx context.Context, userDataID int) (*models.DBUserData, error) {
+	const selectSQL = `
+        SELECT user_id, 
+               type,
+               minio_object_id,
+               encrypted_data,
+               data_nonce,
+               encrypted_dek,
+               dek_nonce,
+               meta,
+               meta_secrets,
+               meta_secrets_nonce,
+               client_encrypted,
+               version FROM user_data 
+        WHERE id = $1 AND deleted_at IS NULL;
+    `
+
+	var userData models.DBUserData
+
+	err := p.DB.QueryRow(ctx, selectSQL, userDataID).Scan(
+		&userData.UserID,
+		&userData.Type,
+		&userData.MinioObjectID,
+		&userData.EncryptedData,
+		&userData.DataNonce,
+		&userData.EncryptedDek,
+		&userData.DekNonce,
+		&userData.Meta,
+		&userData.MetaSecrets,
+		&userData.MetaSecretsNonce,
+		&userData.ClientEncrypted,
+		&userData.Version,
+	)
+	if err != nil {
+		return &userData, fmt.Errorf("failed to get user data: %w", err)
+	}
+
+	return &userData, err
+}
+
+// UpdateUserData replaces the contents of a user data record in place.
+//
+// The current contents are first archived as a version, and only the keep newest versions of
+// the record are retained. The payload, its nonces, the wrapped DEK, the MinIO object and the
+// meta are then overwritten, the version number is incremented and updated_at is set; the ID,
+// type and created_at are kept.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//   - userData: The new contents; UserID must be the owner of the record.
+//   - keep: The number of prior versions retained for the record.
+//
+// Returns:
+//   - []string: The MinIO objects no longer referenced once the versions are pruned, which the caller removes.
+//   - error: models.ErrUserDataNotFound if the user has no such record, or an error if the update fails.
+func (p *Storage) UpdateUserData(
+	ctx context.Context,
+	userDataID int,
+	userData *models.DBUserData,
+	keep int,
+) ([]string, error) {
+	const updateSQL = `
+        UPDATE user_data
+        SET minio_object_id = $2,
+            encrypted_data  = $3,
+            data_nonce      = $4,
+            encrypted_dek   = $5,
+            dek_nonce       = $6,
+            meta            = $7,
+            meta_secrets       = $8,
+            meta_secrets_nonce = $9,
+            version         = version + 1,
+            updated_at      = now()
+        WHERE id = $1;
+    `
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = archiveUserData(ctx, tx, userDataID, userData.UserID); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		updateSQL,
+		userDataID,
+		userData.MinioObjectID,
+		userData.EncryptedData,
+		userData.DataNonce,
+		userData.EncryptedDek,
+		userData.DekNonce,
+		userData.Meta,
+		userData.MetaSecrets,
+		userData.MetaSecretsNonce,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user data: %w", err)
+	}
+
+	objects, err := pruneUserDataVersions(ctx, tx, userDataID, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return objects, nil
+}
+
+// ListUserDataVersions returns the prior versions of a user data record without their payloads.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//
+// Returns:
+//   - []models.UserDataVersion: The versions, newest first; only Data.Meta is set.
+//   - error: An error if the query fails.
+func (p *Storage) ListUserDataVersions(ctx context.Context, userDataID int) ([]models.UserDataVersion, error) {
+	const selectSQL = `
+        SELECT version, meta, created_at, archived_at FROM user_data_versions
+        WHERE user_data_id = $1
+        ORDER BY version DESC;
+    `
+
+	rows, err := p.DB.Query(ctx, selectSQL, userDataID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user data versions: %w", err)
+	}
+
+	versions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.UserDataVersion, error) {
+		var v models.UserDataVersion
+		return v, row.Scan(&v.Version, &v.Data.Meta, &v.CreatedAt, &v.ArchivedAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user data versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetUserDataVersion retrieves a prior version of a user data record.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//   - version: The version number.
+//
+// Returns:
+//   - *models.UserDataVersion: The version with its payload, wrapped DEK, MinIO object and meta.
+//   - error: models.ErrVersionNotFound if the record has no such version, or a query error.
+func (p *Storage) GetUserDataVersion(ctx context.Context, userDataID, version int) (*models.UserDataVersion, error) {
+	const selectSQL = `
+        SELECT version,
+               COALESCE(minio_object_id, ''),
+               encrypted_data,
+               data_nonce,
+               encrypted_dek,
+               dek_nonce,
+               meta,
+               meta_secrets,
+               meta_secrets_nonce,
+               created_at,
+               archived_at FROM user_data_versions
+        WHERE user_data_id = $1 AND version = $2;
+    `
+
+	var v models.UserDataVersion
+
+	err := p.DB.QueryRow(ctx, selectSQL, userDataID, version).Scan(
+		&v.Version,
+		&v.Data.MinioObjectID,
+		&v.Data.EncryptedData,
+		&v.Data.DataNonce,
+		&v.Data.EncryptedDek,
+		&v.Data.DekNonce,
+		&v.Data.Meta,
+		&v.Data.MetaSecrets,
+		&v.Data.MetaSecretsNonce,
+		&v.CreatedAt,
+		&v.ArchivedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrVersionNotFound
+		}
+		return nil, fmt.Errorf("failed to get user data version: %w", err)
+	}
+
+	return &v, nil
+}
+
+// RestoreUserDataVersion makes a prior version the current contents of a user data record.
+//
+// The contents being replaced are archived as a new version first, so a restore can itself be
+// undone; only the keep newest versions of the record are retained afterwards.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//   - userID: ID of the owner of the record.
+//   - version: The version number to restore.
+//   - keep: The number of prior versions retained for the record.
+//
+// Returns:
+//   - int: The version number of the restored contents.
+//   - []string: The MinIO objects no longer referenced once the versions are pruned, which the caller removes.
+//   - error: models.ErrUserDataNotFound or models.ErrVersionNotFound if a row is missing, or an error if the restore fails.
+func (p *Storage) RestoreUserDataVersion(
+	ctx context.Context,
+	userDataID, userID, version, keep int,
+) (int, []string, error) {
+	const restoreSQL = `
+        UPDATE user_data d
+        SET minio_object_id = v.minio_object_id,
+            encrypted_data  = v.encrypted_data,
+            data_nonce      = v.data_nonce,
+            encrypted_dek   = v.encrypted_dek,
+            dek_nonce       = v.dek_nonce,
+            meta            = v.meta,
+            meta_secrets       = v.meta_secrets,
+            meta_secrets_nonce = v.meta_secrets_nonce,
+            version         = d.version + 1,
+            updated_at      = now()
+        FROM user_data_versions v
+        WHERE d.id = $1 AND v.user_data_id = d.id AND v.version = $2
+        RETURNING d.version;
+    `
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = archiveUserData(ctx, tx, userDataID, userID); err != nil {
+		return 0, nil, err
+	}
+
+	var current int
+	if err = tx.QueryRow(ctx, restoreSQL, userDataID, version).Scan(&current); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil, models.ErrVersionNotFound
+		}
+		return 0, nil, fmt.Errorf("failed to restore user data version: %w", err)
+	}
+
+	objects, err := pruneUserDataVersions(ctx, tx, userDataID, keep)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return current, objects, nil
+}
+
+// SetVersionRetention sets the number of prior versions kept for every record of a user.
+//
+// Versions above the new limit are removed on the next update of a record.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - retention: The number of versions; 0 selects the server default.
+//
+// Returns:
+//   - error: models.ErrUserNotFound if there is no such user, or an error if the update fails.
+func (p *Storage) SetVersionRetention(ctx context.Context, userID, retention int) error {
+	const updateSQL = `
+        UPDATE users SET version_retention = NULLIF($2, 0)
+        WHERE id = $1;
+    `
+
+	tag, err := p.DB.Exec(ctx, updateSQL, userID, retention)
+	if err != nil {
+		return fmt.Errorf("failed to set version retention: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// archiveUserData locks a user data record of userID that is not in the trash within tx and copies its current contents
+// into user_data_versions.
+func archiveUserData(ctx context.Context, tx pgx.Tx, userDataID, userID int) error {
+	const (
+		lockSQL = `
+            SELECT id FROM user_data
+            WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+            FOR UPDATE;
+        `
+		archiveSQL = `
+            INSERT INTO user_data_versions (user_data_id, version, minio_object_id, encrypted_data, data_nonce,
+                                            encrypted_dek, dek_nonce, meta, meta_secrets, meta_secrets_nonce, created_at)
+            SELECT id, version, minio_object_id, encrypted_data, data_nonce, encrypted_dek, dek_nonce,
+                   COALESCE(meta, '{}'::JSONB), meta_secrets, meta_secrets_nonce, COALESCE(updated_at, created_at, now())
+            FROM user_data
+            WHERE id = $1;
+        `
+	)
+
+	var id int
+	if err := tx.QueryRow(ctx, lockSQL, userDataID, userID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrUserDataNotFound
+		}
+		return fmt.Errorf("failed to lock user data: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, archiveSQL, userDataID); err != nil {
+		return fmt.Errorf("failed to archive user data: %w", err)
+	}
+
+	return nil
+}
+
+// pruneUserDataVersions removes all but the keep newest versions of a user data record within tx.
+//
+// Returns the MinIO objects of the removed versions that neither the record nor one of its
+// remaining versions references any more; the caller removes them once tx is committed.
+func pruneUserDataVersions(ctx context.Context, tx pgx.Tx, userDataID, keep int) ([]string, error) {
+	const (
+		deleteSQL = `
+            DELETE FROM user_data_versions
+            WHERE user_data_id = $1
+              AND version NOT IN (
+                  SELECT version FROM user_data_versions
+                  WHERE user_data_id = $1
+                  ORDER BY version DESC
+                  LIMIT $2
+              )
+            RETURNING COALESCE(minio_object_id, '');
+        `
+		referencedSQL = `
+            SELECT minio_object_id FROM user_data
+            WHERE id = $1 AND minio_object_id = ANY($2)
+            UNION
+            SELECT minio_object_id FROM user_data_versions
+            WHERE user_data_id = $1 AND minio_object_id = ANY($2);
+        `
+	)
+
+	rows, err := tx.Query(ctx, deleteSQL, userDataID, max(keep, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune user data versions: %w", err)
+	}
+	pruned, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pruned user data versions: %w", err)
+	}
+	pruned = slices.DeleteFunc(pruned, func(object string) bool { return object == "" })
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	// A restored version shares its object with the record, so only unreferenced ones are removed
+	rows, err = tx.Query(ctx, referencedSQL, userDataID, pruned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select referenced objects: %w", err)
+	}
+	referenced, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referenced objects: %w", err)
+	}
+
+	slices.Sort(pruned)
+	pruned = slices.Compact(pruned)
+	return slices.DeleteFunc(pruned, func(object string) bool {
+		return slices.Contains(referenced, object)
+	}), nil
+}
+
+// GetUserDataList returns a page of the records of a user outside the trash.
+//
+// Pages are selected with a cursor on the sorted time and the record ID, so following pages
+// are read from the index without skipping the records before them. The total is counted
+// with the same filter, independently of the page.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - filter: The filter, order and position of the page.
+//
+// Returns:
+//   - *models.UserDataPage: The records of the page, the total and the next cursor.
+//   - error: An error if the query fails.
+func (p *Storage) GetUserDataList(
+	ctx context.Context,
+	userID int,
+	filter models.UserDataListFilter,
+) (*models.UserDataPage, error) {
+	column, direction, comparison := "created_at", "DESC", "<"
+	switch filter.Sort {
+	case models.SortCreatedAsc:
+		direction, comparison = "ASC", ">"
+	case models.SortUpdatedDesc:
+		column = "updated_at"
+	case models.SortUpdatedAsc:
+		column, direction, comparison = "updated_at", "ASC", ">"
+	}
+
+	where := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []any{userID}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+	if len(filter.Types) > 0 {
+		addCondition("type = ANY($%d)", filter.Types)
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tags: %w", err)
+		}
+		addCondition("meta -> 'tags' @> $%d::JSONB", string(tags))
+	}
+	if filter.FavoritesOnly {
+		where = append(where, `meta @> '{"favorite": true}'::JSONB`)
+	}
+	if filter.FolderID != 0 && filter.Subfolders {
+		addCondition(`folder_id IN (
+            WITH RECURSIVE tree AS (
+                SELECT id FROM folders WHERE id = $%d
+                UNION ALL
+                SELECT f.id FROM folders f JOIN tree t ON f.parent_id = t.id
+            )
+            SELECT id FROM tree)`, filter.FolderID)
+	} else if filter.FolderID != 0 {
+		addCondition("folder_id = $%d", filter.FolderID)
+	}
+	for _, bound := range []struct {
+		condition string
+		value     time.Time
+	}{
+		{"created_at >= $%d", filter.CreatedFrom},
+		{"created_at < $%d", filter.CreatedTo},
+		{"updated_at >= $%d", filter.UpdatedFrom},
+		{"updated_at < $%d", filter.UpdatedTo},
+	} {
+		if !bound.value.IsZero() {
+			addCondition(bound.condition, bound.value)
+		}
+	}
+
+	countSQL := "SELECT count(*) FROM user_data WHERE " + strings.Join(where, " AND ")
+	page := &models.UserDataPage{}
+	if err := p.DB.QueryRow(ctx, countSQL, args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count user data: %w", err)
+	}
+
+	if filter.After != nil {
+		args = append(args, filter.After.Time, filter.After.ID)
+		where = append(where, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+	selectSQL := fmt.Sprintf(`
+        SELECT id, user_id, type, meta, COALESCE(folder_id, 0), version, created_at, updated_at
+        FROM user_data
+        WHERE %s
+        ORDER BY %s %s, id %s`, strings.Join(where, " AND "), column, direction, direction)
+	if filter.Limit > 0 {
+		// One more row than requested tells whether there is a next page
+		args = append(args, filter.Limit+1)
+		selectSQL += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.After == nil && filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		selectSQL += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := p.DB.Query(ctx, selectSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user data list: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data models.UserDataListItem
+		err := rows.Scan(
+			&data.ID,
+			&data.UserID,
+			&data.Type,
+			&data.Meta,
+			&data.FolderID,
+			&data.Version,
+			&data.CreatedAt,
+			&data.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		page.Items = append(page.Items, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	if filter.Limit > 0 && len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.Next = &models.UserDataCursor{Time: last.CreatedAt, ID: last.ID}
+		if column == "updated_at" {
+			page.Next.Time = last.UpdatedAt
+		}
+	}
+
+	return page, nil
+}
+
+// SearchUserData returns the records of a user outside the trash whose metadata matches query.
+//
+// A record matches when its meta contains words starting with every term of the query, or
+// when a word of its meta is similar enough to the query text; the latter finds misspelled
+// words. Results are ordered by the sum of the full-text rank and the trigram similarity.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - query: The terms, text, types and number of results of the search.
+//
+// Returns:
+//   - []models.SearchResult: The matched records, best match first.
+//   - error: An error if the query fails.
+func (p *Storage) SearchUserData(
+	ctx context.Context,
+	userID int,
+	query models.SearchQuery,
+) ([]models.SearchResult, error) {
+	// Every term matches words it is a prefix of; terms hold only letters and digits
+	prefixes := make([]string, 0, len(query.Terms))
+	for _, term := range query.Terms {
+		prefixes = append(prefixes, term+":*")
+	}
+
+	selectSQL := `
+        SELECT id, user_id, type, meta, created_at, updated_at,
+               (ts_rank(to_tsvector('simple', meta_search_text(meta)), to_tsquery('simple', $2))
+                   + word_similarity($3, meta_search_text(meta)))::FLOAT8 AS score
+        FROM user_data
+        WHERE user_id = $1 AND deleted_at IS NULL
+          AND (to_tsvector('simple', meta_search_text(meta)) @@ to_tsquery('simple', $2)
+              OR $3 <% meta_search_text(meta))`
+	args := []any{userID, strings.Join(prefixes, " & "), query.Text}
+	if len(query.Types) > 0 {
+		args = append(args, query.Types)
+		selectSQL += fmt.Sprintf(" AND type = ANY($%d)", len(args))
+	}
+	selectSQL += " ORDER BY score DESC, id DESC"
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		selectSQL += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := p.DB.Query(ctx, selectSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user data: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.SearchResult
+	for rows.Next() {
+		var r models.SearchResult
+		err := rows.Scan(
+			&r.Item.ID,
+			&r.Item.UserID,
+			&r.Item.Type,
+			&r.Item.Meta,
+			&r.Item.CreatedAt,
+			&r.Item.UpdatedAt,
+			&r.Score,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result = append(result, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return result, nil
+}
+
+// TrashUserData moves a user data record into the trash.
+//
+// A trashed record is hidden from GetUserData and GetUserDataList until it is restored with
+// RestoreUserData or purged.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//   - userID: ID of the owner of the record.
+//
+// Returns:
+//   - error: models.ErrUserDataNotFound if the user has no such record outside the trash, or an error if the update fails.
+func (p *Storage) TrashUserData(ctx context.Context, userDataID, userID int) error {
+	const updateSQL = `
+        UPDATE user_data SET deleted_at = now()
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
+    `
+
+	tag, err := p.DB.Exec(ctx, updateSQL, userDataID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to move user data to trash: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserDataNotFound
+	}
+
+	return nil
+}
+
+// GetTrashList returns the trashed records of a user, most recently deleted first.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - []models.UserDataListItem: The trashed records with DeletedAt set.
+//   - error: An error if the query fails.
+func (p *Storage) GetTrashList(ctx context.Context, userID int) ([]models.UserDataListItem, error) {
+	const selectSQL = `
+        SELECT id, user_id, type, meta, created_at, deleted_at FROM user_data
+        WHERE user_id = $1 AND deleted_at IS NOT NULL
+        ORDER BY deleted_at DESC;
+    `
+
+	rows, err := p.DB.Query(ctx, selectSQL, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.UserDataListItem, error) {
+		var item models.UserDataListItem
+		return item, row.Scan(&item.ID, &item.UserID, &item.Type, &item.Meta, &item.CreatedAt, &item.DeletedAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreUserData takes a user data record out of the trash.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record.
+//   - userID: ID of the owner of the record.
+//
+// Returns:
+//   - error: models.ErrUserDataNotFound if the user has no such record in the trash, or an error if the update fails.
+func (p *Storage) RestoreUserData(ctx context.Context, userDataID, userID int) error {
+	const updateSQL = `
+        UPDATE user_data SET deleted_at = NULL
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL;
+    `
+
+	tag, err := p.DB.Exec(ctx, updateSQL, userDataID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to restore user data from trash: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserDataNotFound
+	}
+
+	return nil
+}
+
+// EmptyTrash permanently deletes all trashed records of a user together with their versions.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - int: The number of deleted records.
+//   - []string: The MinIO objects of the deleted records and versions, which the caller removes.
+//   - error: An error if the deletion fails.
+func (p *Storage) EmptyTrash(ctx context.Context, userID int) (int, []string, error) {
+	const selectSQL = `
+        SELECT id FROM user_data
+        WHERE user_id = $1 AND deleted_at IS NOT NULL
+        FOR UPDATE;
+    `
+
+	return p.purgeUserData(ctx, selectSQL, userID)
+}
+
+// PurgeTrash permanently deletes up to limit records of all users that were trashed before
+// deletedBefore, together with their versions.
+//
+// Rows locked by a concurrent purge are skipped, so several servers may purge at once.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - deletedBefore: Records trashed before this time are deleted.
+//   - limit: The maximum number of records to delete.
+//
+// Returns:
+//   - int: The number of deleted records.
+//   - []string: The MinIO objects of the deleted records and versions, which the caller removes.
+//   - error: An error if the deletion fails.
+func (p *Storage) PurgeTrash(ctx context.Context, deletedBefore time.Time, limit int) (int, []string, error) {
+	const selectSQL = `
+        SELECT id FROM user_data
+        WHERE deleted_at < $1
+        ORDER BY deleted_at
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED;
+    `
+
+	return p.purgeUserData(ctx, selectSQL, deletedBefore, limit)
+}
+
+// purgeUserData deletes the user data records whose IDs selectSQL returns and collects the
+// MinIO objects referenced by them and by their versions.
+func (p *Storage) purgeUserData(ctx context.Context, selectSQL string, args ...any) (int, []string, error) {
+	const (
+		objectsSQL = `
+            SELECT minio_object_id FROM user_data
+            WHERE id = ANY($1) AND COALESCE(minio_object_id, '') <> ''
+            UNION
+            SELECT minio_object_id FROM user_data_versions
+            WHERE user_data_id = ANY($1) AND COALESCE(minio_object_id, '') <> '';
+        `
+		deleteSQL = `
+            DELETE FROM user_data
+            WHERE id = ANY($1);
+        `
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, selectSQL, args...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to select trashed user data: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read trashed user data: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	rows, err = tx.Query(ctx, objectsSQL, ids)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to select objects of trashed user data: %w", err)
+	}
+	objects, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read objects of trashed user data: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, deleteSQL, ids); err != nil {
+		return 0, nil, fmt.Errorf("failed to delete trashed user data: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(ids), objects, nil
+}
+
+// ListObjectReferences returns every MinIO object referenced by a user data record, including
+// trashed records, or by one of their versions.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//
+// Returns:
+//   - []models.ObjectReference: The references; Version is zero for the current contents of a record.
+//   - error: An error if the query fails.
+func (p *Storage) ListObjectReferences(ctx context.Context) ([]models.ObjectReference, error) {
+	const selectSQL = `
+        SELECT id, 0, minio_object_id, COALESCE(updated_at, created_at, now()) FROM user_data
+        WHERE COALESCE(minio_object_id, '') <> ''
+        UNION ALL
+        SELECT user_data_id, version, minio_object_id, archived_at FROM user_data_versions
+        WHERE COALESCE(minio_object_id, '') <> '';
+    `
+
+	rows, err := p.DB.Query(ctx, selectSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query object references: %w", err)
+	}
+
+	refs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ObjectReference, error) {
+		var ref models.ObjectReference
+		return ref, row.Scan(&ref.UserDataID, &ref.Version, &ref.ObjectName, &ref.ReferencedAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object references: %w", err)
+	}
+
+	return refs, nil
+}
+
+// CreateFolder stores a new folder of a user.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - folder: The folder; UserID, ParentID, the encrypted name and the wrapped DEK are stored.
+//
+// Returns:
+//   - int: The ID of the new folder.
+//   - error: models.ErrFolderNotFound if the parent is not a folder of the user, or an error if
+//     the operation fails.
+func (p *Storage) CreateFolder(ctx context.Context, folder *models.Folder) (int, error) {
+	const insertSQL = `
+        INSERT INTO folders (user_id, parent_id, encrypted_name, name_nonce, encrypted_dek, dek_nonce, client_encrypted)
+        SELECT $1, NULLIF($2, 0), $3, $4, $5, $6, $7
+        WHERE $2 = 0 OR EXISTS (SELECT 1 FROM folders WHERE id = $2 AND user_id = $1)
+        RETURNING id;
+    `
+
+	var id int
+	err := p.DB.QueryRow(
+		ctx,
+		insertSQL,
+		folder.UserID,
+		folder.ParentID,
+		folder.EncryptedName,
+		folder.NameNonce,
+		folder.EncryptedDek,
+		folder.DekNonce,
+		folder.ClientEncrypted,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.ErrFolderNotFound
+		}
+		return 0, fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	return id, nil
+}
+
+// ListFolders returns all folders of a user, oldest first.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//
+// Returns:
+//   - []models.Folder: The folders with their encrypted names.
+//   - error: An error if the query fails.
+func (p *Storage) ListFolders(ctx context.Context, userID int) ([]models.Folder, error) {
+	const selectSQL = `
+        SELECT id, user_id, COALESCE(parent_id, 0), encrypted_name, name_nonce, encrypted_dek, dek_nonce,
+               client_encrypted, created_at, updated_at
+        FROM folders
+        WHERE user_id = $1
+        ORDER BY id;
+    `
+
+	rows, err := p.DB.Query(ctx, selectSQL, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query folders: %w", err)
+	}
+	folders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Folder, error) {
+		var f models.Folder
+		return f, row.Scan(
+			&f.ID,
+			&f.UserID,
+			&f.ParentID,
+			&f.EncryptedName,
+			&f.NameNonce,
+			&f.EncryptedDek,
+			&f.DekNonce,
+			&f.ClientEncrypted,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read folders: %w", err)
+	}
+
+	return folders, nil
+}
+
+// RenameFolder replaces the encrypted name of a user's folder.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - folder: The folder; ID and UserID select it, the encrypted name and the wrapped DEK are stored.
+//
+// Returns:
+//   - error: models.ErrFolderNotFound if the user has no such folder, or an error if the
+//     operation fails.
+func (p *Storage) RenameFolder(ctx context.Context, folder *models.Folder) error {
+	const updateSQL = `
+        UPDATE folders
+        SET encrypted_name = $3, name_nonce = $4, encrypted_dek = $5, dek_nonce = $6, updated_at = now()
+        WHERE id = $1 AND user_id = $2;
+    `
+
+	tag, err := p.DB.Exec(
+		ctx,
+		updateSQL,
+		folder.ID,
+		folder.UserID,
+		folder.EncryptedName,
+		folder.NameNonce,
+		folder.EncryptedDek,
+		folder.DekNonce,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rename folder: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+// MoveFolder moves a user's folder, with its records and subfolders, into another folder.
+//
+// The folders of the user are locked while the new parent is checked, so concurrent moves
+// cannot make two folders contain each other.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - folderID: The folder to move.
+//   - userID: User ID.
+//   - parentID: The new parent folder; 0 moves the folder to the root.
+//
+// Returns:
+//   - error: models.ErrFolderNotFound if either folder is not the user's, models.ErrFolderCycle
+//     if the parent is the folder itself or one of its subfolders, or an error if the operation fails.
+func (p *Storage) MoveFolder(ctx context.Context, folderID, userID, parentID int) error {
+	const (
+		lockSQL  = `SELECT id FROM folders WHERE user_id = $1 ORDER BY id FOR UPDATE;`
+		cycleSQL = `
+            WITH RECURSIVE ancestors AS (
+                SELECT id, parent_id FROM folders WHERE id = $1
+                UNION ALL
+                SELECT f.id, f.parent_id FROM folders f JOIN ancestors a ON f.id = a.parent_id
+            )
+            SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2);
+        `
+		updateSQL = `
+            UPDATE folders SET parent_id = NULLIF($3, 0), updated_at = now()
+            WHERE id = $1 AND user_id = $2;
+        `
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, lockSQL, userID)
+	if err != nil {
+		return fmt.Errorf("failed to lock folders: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return fmt.Errorf("failed to lock folders: %w", err)
+	}
+	owned := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		owned[id] = true
+	}
+	if !owned[folderID] || (parentID != 0 && !owned[parentID]) {
+		return models.ErrFolderNotFound
+	}
+
+	if parentID != 0 {
+		var cycle bool
+		if err = tx.QueryRow(ctx, cycleSQL, parentID, folderID).Scan(&cycle); err != nil {
+			return fmt.Errorf("failed to check folder ancestors: %w", err)
+		}
+		if cycle {
+			return models.ErrFolderCycle
+		}
+	}
+
+	if _, err = tx.Exec(ctx, updateSQL, folderID, userID, parentID); err != nil {
+		return fmt.Errorf("failed to move folder: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFolder deletes a user's folder. Its records and subfolders, including records in the
+// trash, are moved to the folder that contained it.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - folderID: The folder to delete.
+//   - userID: User ID.
+//
+// Returns:
+//   - error: models.ErrFolderNotFound if the user has no such folder, or an error if the
+//     operation fails.
+func (p *Storage) DeleteFolder(ctx context.Context, folderID, userID int) error {
+	const (
+		selectSQL       = `SELECT parent_id FROM folders WHERE id = $1 AND user_id = $2 FOR UPDATE;`
+		moveRecordsSQL  = `UPDATE user_data SET folder_id = $2 WHERE folder_id = $1;`
+		moveChildrenSQL = `UPDATE folders SET parent_id = $2, updated_at = now() WHERE parent_id = $1;`
+		deleteSQL       = `DELETE FROM folders WHERE id = $1;`
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var parentID *int
+	if err = tx.QueryRow(ctx, selectSQL, folderID, userID).Scan(&parentID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrFolderNotFound
+		}
+		return fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, moveRecordsSQL, folderID, parentID); err != nil {
+		return fmt.Errorf("failed to move folder records: %w", err)
+	}
+	if _, err = tx.Exec(ctx, moveChildrenSQL, folderID, parentID); err != nil {
+		return fmt.Errorf("failed to move subfolders: %w", err)
+	}
+	if _, err = tx.Exec(ctx, deleteSQL, folderID); err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MoveUserData moves records of a user into a folder, all of them or none.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userID: User ID.
+//   - userDataIDs: The records to move; records in the trash can be moved too.
+//   - folderID: The target folder; 0 moves the records out of any folder.
+//
+// Returns:
+//   - error: models.ErrFolderNotFound if the folder is not the user's, models.ErrUserDataNotFound
+//     if one of the records is not the user's, or an error if the operation fails.
+func (p *Storage) MoveUserData(ctx context.Context, userID int, userDataIDs []int, folderID int) error {
+	const (
+		folderSQL = `SELECT EXISTS (SELECT 1 FROM folders WHERE id = $1 AND user_id = $2);`
+		updateSQL = `
+            UPDATE user_data SET folder_id = NULLIF($3, 0)
+            WHERE id = ANY($1) AND user_id = $2;
+        `
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if folderID != 0 {
+		var exists bool
+		if err = tx.QueryRow(ctx, folderSQL, folderID, userID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to get folder: %w", err)
+		}
+		if !exists {
+			return models.ErrFolderNotFound
+		}
+	}
+
+	// Повторяющиеся ID обновляются один раз
+	unique := make(map[int]struct{}, len(userDataIDs))
+	for _, id := range userDataIDs {
+		unique[id] = struct{}{}
+	}
+
+	tag, err := tx.Exec(ctx, updateSQL, userDataIDs, userID, folderID)
+	if err != nil {
+		return fmt.Errorf("failed to move user data: %w", err)
+	}
+	if int(tag.RowsAffected()) != len(unique) {
+		return models.ErrUserDataNotFound
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreateUpload stores a new resumable upload.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - upload: The upload; ID, UserID, the object and multipart IDs, the file details, the DEK,
+//     the stream header, the sealed metadata secrets and ExpiresAt are stored.
+//
+// Returns:
+//   - error: An error if the operation fails.
+func (p *Storage) CreateUpload(ctx context.Context, upload *models.Upload) error {
+	const insertSQL = `
+        INSERT INTO uploads (id, user_id, object_name, multipart_id, file_name, file_type, meta, size,
+                             encrypted_dek, dek_nonce, stream_header, meta_secrets, meta_secrets_nonce, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+    `
+
+	_, err := p.DB.Exec(
+		ctx,
+		insertSQL,
+		upload.ID,
+		upload.UserID,
+		upload.ObjectName,
+		upload.MultipartID,
+		upload.FileName,
+		upload.FileType,
+		upload.Meta,
+		upload.Size,
+		upload.EncryptedDek,
+		upload.DekNonce,
+		upload.StreamHeader,
+		upload.MetaSecrets,
+		upload.MetaSecretsNonce,
+		upload.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	return nil
+}
+
+// uploadColumns are the columns scanned by scanUpload.
+const uploadColumns = `id, user_id, object_name, multipart_id, file_name, file_type, meta, size, received,
+               encrypted_dek, dek_nonce, stream_header, meta_secrets, meta_secrets_nonce, created_at, expires_at`
+
+// scanUpload scans a row of uploadColumns.
+func scanUpload(row pgx.Row) (*models.Upload, error) {
+	var u models.Upload
+	err := row.Scan(
+		&u.ID,
+		&u.UserID,
+		&u.ObjectName,
+		&u.MultipartID,
+		&u.FileName,
+		&u.FileType,
+		&u.Meta,
+		&u.Size,
+		&u.Received,
+		&u.EncryptedDek,
+		&u.DekNonce,
+		&u.StreamHeader,
+		&u.MetaSecrets,
+		&u.MetaSecretsNonce,
+		&u.CreatedAt,
+		&u.ExpiresAt,
+	)
+	return &u, err
+}
+
+// GetUpload retrieves a resumable upload by its ID.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//
+// Returns:
+//   - *models.Upload: The upload, including expired ones that were not cleaned up yet.
+//   - error: models.ErrUploadNotFound if there is no such upload, or an error if the query fails.
+func (p *Storage) GetUpload(ctx context.Context, uploadID string) (*models.Upload, error) {
+	selectSQL := `SELECT ` + uploadColumns + ` FROM uploads WHERE id = $1;`
+
+	upload, err := scanUpload(p.DB.QueryRow(ctx, selectSQL, uploadID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	return upload, nil
+}
+
+// ReserveUploadPart records the digest of the plaintext of a part unless the part already has one.
+//
+// A part is encrypted with nonces derived from its position, so once a part was sealed, only the
+// same plaintext may be sealed at that position again.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//   - number: The part number, starting at 1.
+//   - digest: The digest of the plaintext of the part.
+//
+// Returns:
+//   - []byte: The digest on record, which differs from digest if other data was sent for the part before.
+//   - error: An error if the operation fails.
+func (p *Storage) ReserveUploadPart(ctx context.Context, uploadID string, number int, digest []byte) ([]byte, error) {
+	const reserveSQL = `
+        WITH inserted AS (
+            INSERT INTO upload_parts (upload_id, part_number, digest)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (upload_id, part_number) DO NOTHING
+            RETURNING digest
+        )
+        SELECT digest FROM inserted
+        UNION ALL
+        SELECT digest FROM upload_parts WHERE upload_id = $1 AND part_number = $2;
+    `
+
+	var stored []byte
+	if err := p.DB.QueryRow(ctx, reserveSQL, uploadID, number, digest).Scan(&stored); err != nil {
+		return nil, fmt.Errorf("failed to reserve upload part: %w", err)
+	}
+
+	return stored, nil
+}
+
+// CommitUploadPart records the ETag of a stored part and advances the received offset of the upload.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//   - number: The part number.
+//   - etag: The ETag MinIO returned for the part.
+//   - offset: The offset the part starts at, which must be the received offset of the upload.
+//   - received: The offset the part ends at.
+//   - expiresAt: The new expiry time of the upload.
+//
+// Returns:
+//   - error: models.ErrUploadOffsetMismatch if the upload is gone or has moved on from offset,
+//     or an error if the operation fails.
+func (p *Storage) CommitUploadPart(
+	ctx context.Context,
+	uploadID string,
+	number int,
+	etag string,
+	offset, received int64,
+	expiresAt time.Time,
+) error {
+	const (
+		advanceSQL = `
+            UPDATE uploads SET received = $3, expires_at = $4
+            WHERE id = $1 AND received = $2;
+        `
+		partSQL = `UPDATE upload_parts SET etag = $3 WHERE upload_id = $1 AND part_number = $2;`
+	)
+
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, advanceSQL, uploadID, offset, received, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to advance upload: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUploadOffsetMismatch
+	}
+	if _, err := tx.Exec(ctx, partSQL, uploadID, number, etag); err != nil {
+		return fmt.Errorf("failed to save upload part: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListUploadParts returns the stored parts of an upload.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//
+// Returns:
+//   - []models.UploadPart: The parts that have an ETag, ordered by number.
+//   - error: An error if the query fails.
+func (p *Storage) ListUploadParts(ctx context.Context, uploadID string) ([]models.UploadPart, error) {
+	const selectSQL = `
+        SELECT part_number, etag FROM upload_parts
+        WHERE upload_id = $1 AND etag IS NOT NULL
+        ORDER BY part_number;
+    `
+
+	rows, err := p.DB.Query(ctx, selectSQL, uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upload parts: %w", err)
+	}
+
+	parts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.UploadPart, error) {
+		var part models.UploadPart
+		return part, row.Scan(&part.Number, &part.ETag)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload parts: %w", err)
+	}
+
+	return parts, nil
+}
+
+// ClaimUpload marks an open upload as being completed or aborted. Only one request can claim
+// an upload, so it is turned into a record or aborted at most once.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//
+// Returns:
+//   - error: models.ErrUploadClaimed if the upload is gone or already claimed, or an error if
+//     the operation fails.
+func (p *Storage) ClaimUpload(ctx context.Context, uploadID string) error {
+	const claimSQL = `UPDATE uploads SET status = 'finishing' WHERE id = $1 AND status = 'open';`
+
+	tag, err := p.DB.Exec(ctx, claimSQL, uploadID)
+	if err != nil {
+		return fmt.Errorf("failed to claim upload: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUploadClaimed
+	}
+
+	return nil
+}
+
+// ReleaseUpload reopens a claimed upload, so a completion or abort that failed can be retried.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//
+// Returns:
+//   - error: An error if the operation fails.
+func (p *Storage) ReleaseUpload(ctx context.Context, uploadID string) error {
+	const releaseSQL = `UPDATE uploads SET status = 'open' WHERE id = $1;`
+
+	if _, err := p.DB.Exec(ctx, releaseSQL, uploadID); err != nil {
+		return fmt.Errorf("failed to release upload: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUpload deletes a resumable upload and its parts.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - uploadID: The upload ID.
+//
+// Returns:
+//   - error: models.ErrUploadNotFound if there is no such upload, or an error if the operation fails.
+func (p *Storage) DeleteUpload(ctx context.Context, uploadID string) error {
+	const deleteSQL = `DELETE FROM uploads WHERE id = $1;`
+
+	tag, err := p.DB.Exec(ctx, deleteSQL, uploadID)
+	if err != nil {
+		return fmt.Errorf("failed to delete upload: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUploadNotFound
+	}
+
+	return nil
+}
+
+// ListExpiredUploads returns uploads of all users that expired before the given time, oldest first.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - before: Uploads that expired before this time are returned.
+//   - limit: The maximum number of uploads to return.
+//
+// Returns:
+//   - []*models.Upload: The expired uploads.
+//   - error: An error if the query fails.
+func (p *Storage) ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]*models.Upload, error) {
+	selectSQL := `SELECT ` + uploadColumns + ` FROM uploads WHERE expires_at < $1 ORDER BY expires_at LIMIT $2;`
+
+	rows, err := p.DB.Query(ctx, selectSQL, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired uploads: %w", err)
+	}
+	defer rows.Close()
+
+	var uploads []*models.Upload
+	for rows.Next() {
+		upload, err := scanUpload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan upload: %w", err)
+		}
+		uploads = append(uploads, upload)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list expired uploads: %w", err)
+	}
+
+	return uploads, nil
+}
+
+// DeleteUserData deletes a user data record by its ID.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - userDataID: ID of the user data record to delete.
+//
+// Returns:
+//   - error: An error if not found or deletion fails.
+func (p *Storage) DeleteUserData(ctx context.Context, userDataID int) error {
+	const deleteSQL = `
+        DELETE FROM user_data 
+        WHERE id = $1
+        RETURNING id;
+    `
+
+	var deletedID int
+	err := p.DB.QueryRow(ctx, deleteSQL, userDataID).Scan(&deletedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("user data with ID %d not found", userDataID)
+		}
+		return fmt.Errorf("failed to delete user data: %w", err)
+	}
+
+	return nil
+}
+
+// encryptionMode returns the user's encryption mode, defaulting to server-side encryption.
+func encryptionMode(u *models.UserEntry) string {
+	if u.EncryptionMode == "" {
+		return models.EncryptionModeServer
+	}
+	return u.EncryptionMode
+}
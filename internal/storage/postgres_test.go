@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
@@ -131,7 +132,7 @@ func TestStorage_SaveMasterKey_and_GetMasterKey(t *testing.T) {
 
 	encMK := []byte("encrypted-mk")
 	nonce := []byte("nonce")
-	mkID, err := st.SaveMasterKey(ctx, uid, encMK, nonce)
+	mkID, err := st.SaveMasterKey(ctx, &models.EncryptedMK{UserID: uid, EncryptedMK: encMK, Nonce: nonce})
 	require.NoError(t, err)
 	require.NotZero(t, mkID)
 
@@ -139,6 +140,1044 @@ func TestStorage_SaveMasterKey_and_GetMasterKey(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, encMK, got.EncryptedMK)
 	require.Equal(t, nonce, got.Nonce)
+	require.Zero(t, got.KDFVersion)
+	require.Nil(t, got.KDF)
+}
+
+// recordUpdatedAt returns the updated_at of the user data record id.
+func recordUpdatedAt(t *testing.T, st IStorage, id int) time.Time {
+	t.Helper()
+	var updatedAt time.Time
+	require.NoError(t, st.(*Storage).DB.QueryRow(context.Background(), "SELECT updated_at FROM user_data WHERE id = $1", id).Scan(&updatedAt))
+	return updatedAt
+}
+
+func TestStorage_UpdateMasterKey(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "upgradeuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	_, err = st.SaveMasterKey(ctx, &models.EncryptedMK{UserID: uid, EncryptedMK: []byte("old"), Nonce: []byte("n")})
+	require.NoError(t, err)
+	dataID, err := st.SaveUserData(ctx, &models.DBUserData{
+		UserID:        uid,
+		Type:          "credentials",
+		EncryptedData: []byte("data"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	})
+	require.NoError(t, err)
+	_, err = st.UpdateUserData(ctx, dataID, &models.DBUserData{
+		UserID:        uid,
+		EncryptedData: []byte("data2"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek2"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	}, 10)
+	require.NoError(t, err)
+	updatedAt := recordUpdatedAt(t, st, dataID)
+	require.NoError(t, st.CreateUpload(ctx, &models.Upload{
+		ID:           "upgrade-upload",
+		UserID:       uid,
+		ObjectName:   "obj-upgrade",
+		MultipartID:  "mp-upgrade",
+		FileName:     "scan.pdf",
+		FileType:     "application/pdf",
+		Meta:         "{}",
+		Size:         30,
+		EncryptedDek: []byte("dek3"),
+		DekNonce:     []byte("kn"),
+		StreamHeader: []byte("header"),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}))
+
+	kdf := &models.KDFParams{Algorithm: "argon2id", Salt: []byte("0123456789abcdef"), Time: 3, Memory: 65536, Threads: 4}
+	err = st.UpdateMasterKey(ctx, &models.EncryptedMK{
+		UserID:            uid,
+		EncryptedMK:       []byte("new"),
+		Nonce:             []byte("n2"),
+		KDFVersion:        1,
+		KDF:               kdf,
+		PasswordWrappedMK: []byte("wrapped"),
+	}, func(dek, nonce []byte) ([]byte, []byte, error) {
+		return append([]byte("re-"), dek...), nonce, nil
+	})
+	require.NoError(t, err)
+
+	got, err := st.GetMasterKey(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), got.EncryptedMK)
+	require.Equal(t, 1, got.KDFVersion)
+	require.Equal(t, kdf, got.KDF)
+	require.Equal(t, []byte("wrapped"), got.PasswordWrappedMK)
+
+	data, err := st.GetUserData(ctx, dataID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("re-dek2"), data.EncryptedDek)
+	require.Equal(t, updatedAt, recordUpdatedAt(t, st, dataID), "re-wrapping a DEK must not mark the record as edited")
+
+	version, err := st.GetUserDataVersion(ctx, dataID, 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("re-dek"), version.Data.EncryptedDek)
+
+	upload, err := st.GetUpload(ctx, "upgrade-upload")
+	require.NoError(t, err)
+	require.Equal(t, []byte("re-dek3"), upload.EncryptedDek)
+}
+
+func TestStorage_UpdatePassword(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "passwduser", PasswordHash: "old-hash"})
+	require.NoError(t, err)
+	_, err = st.SaveMasterKey(ctx, &models.EncryptedMK{UserID: uid, EncryptedMK: []byte("mk"), Nonce: []byte("n")})
+	require.NoError(t, err)
+	dataID, err := st.SaveUserData(ctx, &models.DBUserData{
+		UserID:        uid,
+		Type:          "credentials",
+		EncryptedData: []byte("data"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	})
+	require.NoError(t, err)
+
+	// A legacy key is replaced, so the DEKs are re-wrapped with the new password
+	err = st.UpdatePassword(ctx, uid, "new-hash", func(key *models.EncryptedMK) (func([]byte, []byte) ([]byte, []byte, error), error) {
+		require.Equal(t, []byte("mk"), key.EncryptedMK)
+		*key = models.EncryptedMK{
+			EncryptedMK:       []byte("mk2"),
+			Nonce:             []byte("n"),
+			KDFVersion:        1,
+			KDF:               &models.KDFParams{Algorithm: "argon2id", Salt: []byte("0123456789abcdef"), Time: 3, Memory: 65536, Threads: 4},
+			PasswordWrappedMK: []byte("rewrapped"),
+		}
+		return func(dek, nonce []byte) ([]byte, []byte, error) {
+			return append([]byte("re-"), dek...), nonce, nil
+		}, nil
+	})
+	require.NoError(t, err)
+
+	u, err := st.GetUserByID(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, "new-hash", u.PasswordHash)
+
+	got, err := st.GetMasterKey(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, []byte("mk2"), got.EncryptedMK)
+	require.Equal(t, []byte("rewrapped"), got.PasswordWrappedMK)
+
+	data, err := st.GetUserData(ctx, dataID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("re-dek"), data.EncryptedDek)
+
+	// A failed rewrap leaves both the password and the key unchanged
+	errRewrap := errors.New("wrong password")
+	err = st.UpdatePassword(ctx, uid, "other-hash", func(*models.EncryptedMK) (func([]byte, []byte) ([]byte, []byte, error), error) {
+		return nil, errRewrap
+	})
+	require.ErrorIs(t, err, errRewrap)
+	u, err = st.GetUserByID(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, "new-hash", u.PasswordHash)
+
+	// A missing key rolls the password change back
+	other, err := st.AddUser(ctx, &models.UserEntry{Username: "nokeyuser", PasswordHash: "old-hash"})
+	require.NoError(t, err)
+	err = st.UpdatePassword(ctx, other, "new-hash", func(*models.EncryptedMK) (func([]byte, []byte) ([]byte, []byte, error), error) {
+		return nil, nil
+	})
+	require.ErrorIs(t, err, models.ErrMasterKeyNotFound)
+	u, err = st.GetUserByID(ctx, other)
+	require.NoError(t, err)
+	require.Equal(t, "old-hash", u.PasswordHash)
+}
+
+func TestStorage_KeyRotation(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	var uids []int
+	for _, name := range []string{"rot1", "rot2", "rot3"} {
+		uid, err := st.AddUser(ctx, &models.UserEntry{Username: name, PasswordHash: "hash"})
+		require.NoError(t, err)
+		_, err = st.SaveMasterKey(ctx, &models.EncryptedMK{UserID: uid, EncryptedMK: []byte("mk"), Nonce: []byte("n")})
+		require.NoError(t, err)
+		uids = append(uids, uid)
+	}
+
+	job, err := st.CreateKeyRotationJob(ctx, "k2")
+	require.NoError(t, err)
+	_, err = st.CreateKeyRotationJob(ctx, "k2")
+	require.ErrorIs(t, err, models.ErrKeyRotationInProgress)
+
+	rewrap := func(key *models.EncryptedMK) error {
+		key.ServerKeyID, key.EncryptedMK = "k2", append([]byte("k2-"), key.EncryptedMK...)
+		return nil
+	}
+
+	n, err := st.RewrapMasterKeysBatch(ctx, job.ID, 2, rewrap)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	got, err := st.GetKeyRotationJob(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uids[1], got.LastUserID)
+	require.Equal(t, 2, got.Rewrapped)
+	require.Equal(t, 1, got.Remaining)
+
+	n, err = st.RewrapMasterKeysBatch(ctx, job.ID, 2, rewrap)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = st.RewrapMasterKeysBatch(ctx, job.ID, 2, rewrap)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	mk, err := st.GetMasterKey(ctx, uids[2])
+	require.NoError(t, err)
+	require.Equal(t, "k2", mk.ServerKeyID)
+	require.Equal(t, []byte("k2-mk"), mk.EncryptedMK)
+
+	require.NoError(t, st.FinishKeyRotationJob(ctx, job.ID, models.JobStatusDone, ""))
+	got, err = st.GetKeyRotationJob(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.JobStatusDone, got.Status)
+	require.Zero(t, got.Remaining)
+
+	_, err = st.RewrapMasterKeysBatch(ctx, job.ID, 2, rewrap)
+	require.ErrorIs(t, err, models.ErrKeyRotationNotFound)
+}
+
+func TestStorage_TOTP(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "totpuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	_, err = st.GetTOTP(ctx, uid)
+	require.ErrorIs(t, err, models.ErrTOTPNotEnrolled)
+
+	secret := &models.WrappedSecret{KeyID: "default", Ciphertext: []byte("sealed"), Nonce: []byte("nonce")}
+	require.NoError(t, st.SaveTOTPSecret(ctx, uid, secret))
+
+	got, err := st.GetTOTP(ctx, uid)
+	require.NoError(t, err)
+	require.False(t, got.Enabled)
+	require.Equal(t, secret, got.Secret)
+
+	hashes := [][]byte{[]byte("hash-1"), []byte("hash-2")}
+	require.NoError(t, st.EnableTOTP(ctx, uid, 100, hashes))
+
+	u, err := st.GetUserByID(ctx, uid)
+	require.NoError(t, err)
+	require.True(t, u.TOTPEnabled)
+
+	require.ErrorIs(t, st.UpdateTOTPStep(ctx, uid, 100), models.ErrTOTPCodeReused)
+	require.NoError(t, st.UpdateTOTPStep(ctx, uid, 101))
+
+	require.NoError(t, st.UseRecoveryCode(ctx, uid, []byte("hash-1")))
+	require.ErrorIs(t, st.UseRecoveryCode(ctx, uid, []byte("hash-1")), models.ErrRecoveryCodeInvalid)
+	require.ErrorIs(t, st.UseRecoveryCode(ctx, uid, []byte("unknown")), models.ErrRecoveryCodeInvalid)
+
+	require.NoError(t, st.DisableTOTP(ctx, uid))
+	_, err = st.GetTOTP(ctx, uid)
+	require.ErrorIs(t, err, models.ErrTOTPNotEnrolled)
+	require.ErrorIs(t, st.UseRecoveryCode(ctx, uid, []byte("hash-2")), models.ErrRecoveryCodeInvalid)
+}
+
+func TestStorage_TwoFactorAttempts(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "attemptsuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	limits := models.TwoFactorLimits{TokenAttempts: 2, UserAttempts: 3, Lockout: time.Hour}
+
+	require.NoError(t, st.CreateTwoFactorChallenge(ctx, uid, "jti-1", time.Now().Add(time.Minute)))
+	require.NoError(t, st.StartTwoFactorAttempt(ctx, uid, "jti-1", limits))
+	require.NoError(t, st.StartTwoFactorAttempt(ctx, uid, "jti-1", limits))
+	require.ErrorIs(t, st.StartTwoFactorAttempt(ctx, uid, "jti-1", limits), models.ErrTwoFactorTokenUsed)
+	require.ErrorIs(t, st.StartTwoFactorAttempt(ctx, uid, "unknown", limits), models.ErrTwoFactorTokenUsed)
+
+	// An accepted code uses up the token and clears the failed attempts
+	require.NoError(t, st.CreateTwoFactorChallenge(ctx, uid, "jti-2", time.Now().Add(time.Minute)))
+	require.NoError(t, st.StartTwoFactorAttempt(ctx, uid, "jti-2", limits))
+	require.NoError(t, st.FinishTwoFactorAttempt(ctx, uid, "jti-2"))
+	require.ErrorIs(t, st.FinishTwoFactorAttempt(ctx, uid, "jti-2"), models.ErrTwoFactorTokenUsed)
+	require.ErrorIs(t, st.StartTwoFactorAttempt(ctx, uid, "jti-2", limits), models.ErrTwoFactorTokenUsed)
+
+	// Failures across tokens lock the user out
+	require.NoError(t, st.StartTwoFactorAttempt(ctx, uid, "", limits))
+	require.NoError(t, st.StartTwoFactorAttempt(ctx, uid, "", limits))
+	require.NoError(t, st.StartTwoFactorAttempt(ctx, uid, "", limits))
+	require.NoError(t, st.CreateTwoFactorChallenge(ctx, uid, "jti-3", time.Now().Add(time.Minute)))
+	require.ErrorIs(t, st.StartTwoFactorAttempt(ctx, uid, "jti-3", limits), models.ErrTwoFactorLocked)
+
+	// Expired tokens are refused
+	require.NoError(t, st.FinishTwoFactorAttempt(ctx, uid, ""))
+	require.NoError(t, st.CreateTwoFactorChallenge(ctx, uid, "jti-4", time.Now().Add(-time.Minute)))
+	require.ErrorIs(t, st.StartTwoFactorAttempt(ctx, uid, "jti-4", limits), models.ErrTwoFactorTokenUsed)
+}
+
+func TestStorage_Sessions(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "sessionuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	sid, err := st.CreateSession(ctx, &models.Session{UserID: uid, ExpiresAt: expires}, []byte("token-1"))
+	require.NoError(t, err)
+	active, err := st.TouchSession(ctx, sid, "")
+	require.NoError(t, err)
+	require.True(t, active)
+
+	session, err := st.RotateRefreshToken(ctx, []byte("token-1"), []byte("token-2"), expires)
+	require.NoError(t, err)
+	require.Equal(t, sid, session.ID)
+	require.Equal(t, uid, session.UserID)
+
+	_, err = st.RotateRefreshToken(ctx, []byte("unknown"), []byte("token-x"), expires)
+	require.ErrorIs(t, err, models.ErrRefreshTokenInvalid)
+
+	// Presenting the replaced token again revokes the whole family.
+	_, err = st.RotateRefreshToken(ctx, []byte("token-1"), []byte("token-3"), expires)
+	require.ErrorIs(t, err, models.ErrRefreshTokenReused)
+	active, err = st.TouchSession(ctx, sid, "")
+	require.NoError(t, err)
+	require.False(t, active)
+	_, err = st.RotateRefreshToken(ctx, []byte("token-2"), []byte("token-4"), expires)
+	require.ErrorIs(t, err, models.ErrRefreshTokenInvalid)
+
+	other, err := st.CreateSession(ctx, &models.Session{UserID: uid, ExpiresAt: expires}, []byte("token-5"))
+	require.NoError(t, err)
+	require.ErrorIs(t, st.RevokeSession(ctx, uid+1, other, "logout"), models.ErrSessionNotFound)
+	require.NoError(t, st.RevokeSession(ctx, uid, other, "logout"))
+	require.ErrorIs(t, st.RevokeSession(ctx, uid, other, "logout"), models.ErrSessionNotFound)
+
+	active, err = st.TouchSession(ctx, 999999, "")
+	require.NoError(t, err)
+	require.False(t, active)
+}
+
+func TestStorage_ListAndRevokeOtherSessions(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "devicesuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	laptop, err := st.CreateSession(ctx, &models.Session{
+		UserID: uid, DeviceName: "laptop", ClientVersion: "1.0.0", IP: "10.0.0.1", ExpiresAt: expires,
+	}, []byte("laptop-token"))
+	require.NoError(t, err)
+	phone, err := st.CreateSession(ctx, &models.Session{UserID: uid, DeviceName: "phone", ExpiresAt: expires}, []byte("phone-token"))
+	require.NoError(t, err)
+	_, err = st.CreateSession(ctx, &models.Session{UserID: uid, DeviceName: "tablet", ExpiresAt: expires}, []byte("tablet-token"))
+	require.NoError(t, err)
+
+	// A new address is recorded right away.
+	active, err := st.TouchSession(ctx, laptop, "10.0.0.2")
+	require.NoError(t, err)
+	require.True(t, active)
+
+	sessions, err := st.ListSessions(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	for _, s := range sessions {
+		if s.ID == laptop {
+			require.Equal(t, "laptop", s.DeviceName)
+			require.Equal(t, "1.0.0", s.ClientVersion)
+			require.Equal(t, "10.0.0.2", s.IP)
+		}
+	}
+
+	require.NoError(t, st.RevokeSession(ctx, uid, phone, "revoked by user"))
+	revoked, err := st.RevokeOtherSessions(ctx, uid, laptop, "revoked by user")
+	require.NoError(t, err)
+	require.Equal(t, 1, revoked)
+
+	sessions, err = st.ListSessions(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, laptop, sessions[0].ID)
+}
+
+func TestStorage_UpdateUserData(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "updateuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	dataID, err := st.SaveUserData(ctx, &models.DBUserData{
+		UserID:        uid,
+		Type:          "credentials",
+		EncryptedData: []byte("data"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          `{"content": "old"}`,
+	})
+	require.NoError(t, err)
+
+	update := &models.DBUserData{
+		UserID:        uid,
+		EncryptedData: []byte("data2"),
+		DataNonce:     []byte("dn2"),
+		EncryptedDek:  []byte("dek2"),
+		DekNonce:      []byte("kn2"),
+		Meta:          `{"content": "new"}`,
+	}
+	_, err = st.UpdateUserData(ctx, dataID, update, 10)
+	require.NoError(t, err)
+
+	got, err := st.GetUserData(ctx, dataID)
+	require.NoError(t, err)
+	require.Equal(t, "credentials", got.Type)
+	require.Equal(t, []byte("data2"), got.EncryptedData)
+	require.Equal(t, []byte("dek2"), got.EncryptedDek)
+	require.JSONEq(t, `{"content": "new"}`, got.Meta)
+	require.Equal(t, 2, got.Version)
+
+	update.UserID = uid + 1
+	_, err = st.UpdateUserData(ctx, dataID, update, 10)
+	require.ErrorIs(t, err, models.ErrUserDataNotFound)
+}
+
+func TestStorage_UpdateUserData_PrunedObjects(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "pruneuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	file := func(object string) *models.DBUserData {
+		return &models.DBUserData{
+			UserID:        uid,
+			Type:          "binary_data",
+			MinioObjectID: object,
+			DataNonce:     []byte("dn"),
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+			Meta:          "{}",
+		}
+	}
+	dataID, err := st.SaveUserData(ctx, file("obj-1"))
+	require.NoError(t, err)
+
+	// The superseded object is kept while its version is
+	objects, err := st.UpdateUserData(ctx, dataID, file("obj-2"), 1)
+	require.NoError(t, err)
+	require.Empty(t, objects)
+
+	objects, err = st.UpdateUserData(ctx, dataID, file("obj-3"), 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"obj-1"}, objects)
+
+	// Without versions the superseded object goes at once
+	objects, err = st.UpdateUserData(ctx, dataID, file("obj-4"), 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"obj-2", "obj-3"}, objects)
+}
+
+func TestStorage_UserDataVersions(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "versionuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	dataID, err := st.SaveUserData(ctx, &models.DBUserData{
+		UserID:        uid,
+		Type:          "credentials",
+		EncryptedData: []byte("v1"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek1"),
+		DekNonce:      []byte("kn"),
+		Meta:          `{"content": "v1"}`,
+	})
+	require.NoError(t, err)
+
+	for _, v := range []string{"v2", "v3", "v4"} {
+		_, err = st.UpdateUserData(ctx, dataID, &models.DBUserData{
+			UserID:        uid,
+			EncryptedData: []byte(v),
+			DataNonce:     []byte("dn"),
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+			Meta:          `{"content": "` + v + `"}`,
+		}, 2)
+		require.NoError(t, err)
+	}
+
+	// Only the two newest prior versions are kept.
+	versions, err := st.ListUserDataVersions(ctx, dataID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 3, versions[0].Version)
+	require.Equal(t, 2, versions[1].Version)
+	require.JSONEq(t, `{"content": "v3"}`, versions[0].Data.Meta)
+
+	_, err = st.GetUserDataVersion(ctx, dataID, 1)
+	require.ErrorIs(t, err, models.ErrVersionNotFound)
+
+	version, err := st.GetUserDataVersion(ctx, dataID, 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), version.Data.EncryptedData)
+
+	current, _, err := st.RestoreUserDataVersion(ctx, dataID, uid, 2, 2)
+	require.NoError(t, err)
+	require.Equal(t, 5, current)
+
+	got, err := st.GetUserData(ctx, dataID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("v2"), got.EncryptedData)
+	require.Equal(t, 5, got.Version)
+	list, err := st.GetUserDataList(ctx, uid, models.UserDataListFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, 5, list.Items[0].Version)
+
+	versions, err = st.ListUserDataVersions(ctx, dataID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 4, versions[0].Version)
+
+	_, _, err = st.RestoreUserDataVersion(ctx, dataID, uid, 1, 2)
+	require.ErrorIs(t, err, models.ErrVersionNotFound)
+	_, _, err = st.RestoreUserDataVersion(ctx, dataID, uid+1, 4, 2)
+	require.ErrorIs(t, err, models.ErrUserDataNotFound)
+
+	require.NoError(t, st.SetVersionRetention(ctx, uid, 5))
+	user, err := st.GetUserByID(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, 5, user.VersionRetention)
+
+	require.NoError(t, st.SetVersionRetention(ctx, uid, 0))
+	user, err = st.GetUserByID(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, 0, user.VersionRetention)
+}
+
+func TestStorage_Trash(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "trashuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	save := func(object string) int {
+		id, errSave := st.SaveUserData(ctx, &models.DBUserData{
+			UserID:        uid,
+			Type:          "binary_data",
+			MinioObjectID: object,
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+			Meta:          "{}",
+		})
+		require.NoError(t, errSave)
+		return id
+	}
+	kept, trashed, purged := save("obj-kept"), save("obj-trashed"), save("obj-purged")
+	_, err = st.UpdateUserData(ctx, purged, &models.DBUserData{
+		UserID:        uid,
+		MinioObjectID: "obj-purged-2",
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	}, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, st.TrashUserData(ctx, trashed, uid))
+	require.NoError(t, st.TrashUserData(ctx, purged, uid))
+	require.ErrorIs(t, st.TrashUserData(ctx, trashed, uid), models.ErrUserDataNotFound)
+	require.ErrorIs(t, st.TrashUserData(ctx, kept, uid+1), models.ErrUserDataNotFound)
+
+	list, err := st.GetUserDataList(ctx, uid, models.UserDataListFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, kept, list.Items[0].ID)
+	_, err = st.GetUserData(ctx, trashed)
+	require.Error(t, err)
+
+	trash, err := st.GetTrashList(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, trash, 2)
+	require.False(t, trash[0].DeletedAt.IsZero())
+
+	require.NoError(t, st.RestoreUserData(ctx, trashed, uid))
+	require.ErrorIs(t, st.RestoreUserData(ctx, trashed, uid), models.ErrUserDataNotFound)
+
+	n, objects, err := st.PurgeTrash(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Zero(t, n, "records trashed within the retention period are kept")
+	require.Empty(t, objects)
+
+	n, objects, err = st.PurgeTrash(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.ElementsMatch(t, []string{"obj-purged", "obj-purged-2"}, objects)
+
+	require.NoError(t, st.TrashUserData(ctx, kept, uid))
+	n, objects, err = st.EmptyTrash(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"obj-kept"}, objects)
+
+	list, err = st.GetUserDataList(ctx, uid, models.UserDataListFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, trashed, list.Items[0].ID)
+}
+
+func TestStorage_GetUserDataList(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "listuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	var ids []int
+	for _, dataType := range []string{"credentials", "bank_card", "credentials", "binary_data", "credentials"} {
+		id, errSave := st.SaveUserData(ctx, &models.DBUserData{
+			UserID:        uid,
+			Type:          dataType,
+			EncryptedData: []byte("data"),
+			DataNonce:     []byte("dn"),
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+			Meta:          "{}",
+		})
+		require.NoError(t, errSave)
+		ids = append(ids, id)
+	}
+	// The first record becomes the most recently updated one
+	_, err = st.UpdateUserData(ctx, ids[0], &models.DBUserData{
+		UserID:        uid,
+		EncryptedData: []byte("data2"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	}, 10)
+	require.NoError(t, err)
+
+	collect := func(filter models.UserDataListFilter) ([]int, int) {
+		var (
+			got   []int
+			total int
+		)
+		for {
+			page, errList := st.GetUserDataList(ctx, uid, filter)
+			require.NoError(t, errList)
+			require.LessOrEqual(t, len(page.Items), filter.Limit)
+			total = page.Total
+			for _, item := range page.Items {
+				got = append(got, item.ID)
+			}
+			if page.Next == nil {
+				return got, total
+			}
+			filter.After = page.Next
+		}
+	}
+
+	got, total := collect(models.UserDataListFilter{Limit: 2})
+	require.Equal(t, []int{ids[4], ids[3], ids[2], ids[1], ids[0]}, got)
+	require.Equal(t, 5, total)
+
+	got, total = collect(models.UserDataListFilter{Limit: 2, Sort: models.SortCreatedAsc, Types: []string{"credentials"}})
+	require.Equal(t, []int{ids[0], ids[2], ids[4]}, got)
+	require.Equal(t, 3, total)
+
+	got, _ = collect(models.UserDataListFilter{Limit: 3, Sort: models.SortUpdatedDesc})
+	require.Equal(t, ids[0], got[0])
+	require.Len(t, got, 5)
+
+	page, err := st.GetUserDataList(ctx, uid, models.UserDataListFilter{Limit: 2, Offset: 4})
+	require.NoError(t, err)
+	require.Equal(t, 5, page.Total)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, ids[0], page.Items[0].ID)
+	require.Nil(t, page.Next)
+
+	page, err = st.GetUserDataList(ctx, uid, models.UserDataListFilter{CreatedFrom: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Zero(t, page.Total)
+	require.Empty(t, page.Items)
+
+	page, err = st.GetUserDataList(ctx, uid, models.UserDataListFilter{UpdatedTo: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, 5, page.Total)
+}
+
+func TestStorage_StructuredMeta(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "metauser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	save := func(meta string, secrets []byte) int {
+		id, errSave := st.SaveUserData(ctx, &models.DBUserData{
+			UserID:           uid,
+			Type:             "credentials",
+			EncryptedData:    []byte("data"),
+			DataNonce:        []byte("dn"),
+			EncryptedDek:     []byte("dek"),
+			DekNonce:         []byte("kn"),
+			Meta:             meta,
+			MetaSecrets:      secrets,
+			MetaSecretsNonce: []byte("sn"),
+		})
+		require.NoError(t, errSave)
+		return id
+	}
+	home := save(`{"title":"Router","tags":["home","wifi"],"favorite":true}`, []byte("secrets"))
+	work := save(`{"title":"VPN","tags":["work"]}`, nil)
+	both := save(`{"title":"Printer","tags":["work","home"]}`, nil)
+
+	list := func(filter models.UserDataListFilter) []int {
+		page, errList := st.GetUserDataList(ctx, uid, filter)
+		require.NoError(t, errList)
+		var ids []int
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+	require.ElementsMatch(t, []int{home, both}, list(models.UserDataListFilter{Tags: []string{"home"}}))
+	require.Equal(t, []int{both}, list(models.UserDataListFilter{Tags: []string{"home", "work"}}))
+	require.Equal(t, []int{home}, list(models.UserDataListFilter{FavoritesOnly: true}))
+	require.Empty(t, list(models.UserDataListFilter{Tags: []string{"office"}}))
+	require.ElementsMatch(t, []int{home, work, both}, list(models.UserDataListFilter{}))
+
+	got, err := st.GetUserData(ctx, home)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secrets"), got.MetaSecrets)
+	require.Equal(t, []byte("sn"), got.MetaSecretsNonce)
+
+	// The secrets of the replaced contents are kept with the version
+	_, err = st.UpdateUserData(ctx, home, &models.DBUserData{
+		UserID:        uid,
+		EncryptedData: []byte("data2"),
+		DataNonce:     []byte("dn"),
+		EncryptedDek:  []byte("dek2"),
+		DekNonce:      []byte("kn"),
+		Meta:          `{"title":"Router"}`,
+	}, 10)
+	require.NoError(t, err)
+	got, err = st.GetUserData(ctx, home)
+	require.NoError(t, err)
+	require.Nil(t, got.MetaSecrets)
+
+	version, err := st.GetUserDataVersion(ctx, home, 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secrets"), version.Data.MetaSecrets)
+
+	_, _, err = st.RestoreUserDataVersion(ctx, home, uid, 1, 10)
+	require.NoError(t, err)
+	got, err = st.GetUserData(ctx, home)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secrets"), got.MetaSecrets)
+	require.Equal(t, []byte("sn"), got.MetaSecretsNonce)
+}
+
+func TestStorage_Folders(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "folderuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	other, err := st.AddUser(ctx, &models.UserEntry{Username: "folderother", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	create := func(userID, parentID int, name string) int {
+		id, errCreate := st.CreateFolder(ctx, &models.Folder{
+			UserID:        userID,
+			ParentID:      parentID,
+			EncryptedName: []byte(name),
+			NameNonce:     []byte("nn"),
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+		})
+		require.NoError(t, errCreate)
+		return id
+	}
+	work := create(uid, 0, "work")
+	projects := create(uid, work, "projects")
+	archive := create(uid, projects, "archive")
+	foreign := create(other, 0, "foreign")
+
+	_, err = st.CreateFolder(ctx, &models.Folder{UserID: uid, ParentID: foreign, EncryptedName: []byte("x"),
+		NameNonce: []byte("nn"), EncryptedDek: []byte("dek"), DekNonce: []byte("kn")})
+	require.ErrorIs(t, err, models.ErrFolderNotFound)
+
+	folders, err := st.ListFolders(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, folders, 3)
+	require.Equal(t, work, folders[1].ParentID)
+	require.Equal(t, []byte("projects"), folders[1].EncryptedName)
+
+	require.NoError(t, st.RenameFolder(ctx, &models.Folder{ID: work, UserID: uid, EncryptedName: []byte("job"),
+		NameNonce: []byte("nn2"), EncryptedDek: []byte("dek2"), DekNonce: []byte("kn2")}))
+	require.ErrorIs(t, st.RenameFolder(ctx, &models.Folder{ID: foreign, UserID: uid}), models.ErrFolderNotFound)
+
+	// A folder cannot end up inside itself
+	require.ErrorIs(t, st.MoveFolder(ctx, work, uid, archive), models.ErrFolderCycle)
+	require.ErrorIs(t, st.MoveFolder(ctx, work, uid, work), models.ErrFolderCycle)
+	require.ErrorIs(t, st.MoveFolder(ctx, work, uid, foreign), models.ErrFolderNotFound)
+	require.NoError(t, st.MoveFolder(ctx, archive, uid, work))
+
+	save := func(title string) int {
+		id, errSave := st.SaveUserData(ctx, &models.DBUserData{
+			UserID:        uid,
+			Type:          "credentials",
+			EncryptedData: []byte("data"),
+			DataNonce:     []byte("dn"),
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+			Meta:          `{"title":"` + title + `"}`,
+		})
+		require.NoError(t, errSave)
+		return id
+	}
+	vpn := save("VPN")
+	repo := save("Repo")
+	loose := save("Loose")
+
+	require.NoError(t, st.MoveUserData(ctx, uid, []int{vpn}, work))
+	require.NoError(t, st.MoveUserData(ctx, uid, []int{repo, repo}, projects))
+	require.ErrorIs(t, st.MoveUserData(ctx, uid, []int{loose}, foreign), models.ErrFolderNotFound)
+
+	otherRecord, err := st.SaveUserData(ctx, &models.DBUserData{UserID: other, Type: "credentials",
+		EncryptedData: []byte("data"), DataNonce: []byte("dn"), EncryptedDek: []byte("dek"), DekNonce: []byte("kn"), Meta: "{}"})
+	require.NoError(t, err)
+	require.ErrorIs(t, st.MoveUserData(ctx, uid, []int{loose, otherRecord}, work), models.ErrUserDataNotFound)
+
+	list := func(filter models.UserDataListFilter) []int {
+		page, errList := st.GetUserDataList(ctx, uid, filter)
+		require.NoError(t, errList)
+		var ids []int
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []int{vpn}, list(models.UserDataListFilter{FolderID: work}))
+	require.ElementsMatch(t, []int{vpn, repo}, list(models.UserDataListFilter{FolderID: work, Subfolders: true}))
+	require.ElementsMatch(t, []int{vpn, repo, loose}, list(models.UserDataListFilter{}))
+
+	// Deleting a folder hands its records and subfolders to its parent
+	require.NoError(t, st.DeleteFolder(ctx, projects, uid))
+	require.ErrorIs(t, st.DeleteFolder(ctx, projects, uid), models.ErrFolderNotFound)
+	require.ElementsMatch(t, []int{vpn, repo}, list(models.UserDataListFilter{FolderID: work}))
+
+	folders, err = st.ListFolders(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, folders, 2)
+	require.Equal(t, archive, folders[1].ID)
+	require.Equal(t, work, folders[1].ParentID)
+
+	require.NoError(t, st.MoveUserData(ctx, uid, []int{vpn}, 0))
+	got, err := st.GetUserDataList(ctx, uid, models.UserDataListFilter{})
+	require.NoError(t, err)
+	for _, item := range got.Items {
+		if item.ID == vpn {
+			require.Zero(t, item.FolderID)
+		}
+	}
+}
+
+func TestStorage_SearchUserData(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "searchuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	other, err := st.AddUser(ctx, &models.UserEntry{Username: "searchother", PasswordHash: "hash"})
+	require.NoError(t, err)
+	save := func(userID int, dataType, meta string) int {
+		id, errSave := st.SaveUserData(ctx, &models.DBUserData{
+			UserID:        userID,
+			Type:          dataType,
+			EncryptedData: []byte("data"),
+			DataNonce:     []byte("dn"),
+			EncryptedDek:  []byte("dek"),
+			DekNonce:      []byte("kn"),
+			Meta:          meta,
+		})
+		require.NoError(t, errSave)
+		return id
+	}
+	vpn := save(uid, "credentials", `{"content":"Рабочий VPN"}`)
+	router := save(uid, "credentials", `{"content":"Домашний роутер netgear"}`)
+	card := save(uid, "bank_card", `{"content":"Карта для VPN подписки"}`)
+	trashed := save(uid, "credentials", `{"content":"Старый VPN"}`)
+	save(other, "credentials", `{"content":"Чужой VPN"}`)
+	require.NoError(t, st.TrashUserData(ctx, trashed, uid))
+
+	ids := func(results []models.SearchResult) []int {
+		var got []int
+		for _, r := range results {
+			got = append(got, r.Item.ID)
+		}
+		return got
+	}
+
+	found, err := st.SearchUserData(ctx, uid, models.SearchQuery{Terms: []string{"vpn"}, Text: "vpn", Limit: 10})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int{vpn, card}, ids(found))
+	require.Positive(t, found[0].Score)
+
+	found, err = st.SearchUserData(ctx, uid, models.SearchQuery{
+		Terms: []string{"vpn"}, Text: "vpn", Types: []string{"bank_card"}, Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{card}, ids(found))
+
+	found, err = st.SearchUserData(ctx, uid, models.SearchQuery{Terms: []string{"раб"}, Text: "раб", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []int{vpn}, ids(found), "terms match word prefixes")
+
+	found, err = st.SearchUserData(ctx, uid, models.SearchQuery{Terms: []string{"netgar"}, Text: "netgar", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []int{router}, ids(found), "misspelled words are matched by similarity")
+
+	found, err = st.SearchUserData(ctx, uid, models.SearchQuery{Terms: []string{"vpn"}, Text: "vpn", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+}
+
+func TestStorage_ListObjectReferences(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "refsuser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	file, err := st.SaveUserData(ctx, &models.DBUserData{
+		UserID:        uid,
+		Type:          "binary_data",
+		MinioObjectID: "refs-v1",
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	})
+	require.NoError(t, err)
+	creds, err := st.SaveUserData(ctx, &models.DBUserData{
+		UserID:        uid,
+		Type:          "credentials",
+		EncryptedData: []byte("enc"),
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	})
+	require.NoError(t, err)
+	_, err = st.UpdateUserData(ctx, file, &models.DBUserData{
+		UserID:        uid,
+		MinioObjectID: "refs-v2",
+		EncryptedDek:  []byte("dek"),
+		DekNonce:      []byte("kn"),
+		Meta:          "{}",
+	}, 10)
+	require.NoError(t, err)
+	require.NoError(t, st.TrashUserData(ctx, file, uid))
+
+	refs, err := st.ListObjectReferences(ctx)
+	require.NoError(t, err)
+
+	var own []models.ObjectReference
+	for _, ref := range refs {
+		require.NotEqual(t, creds, ref.UserDataID, "records without an object are not listed")
+		if ref.UserDataID == file {
+			require.False(t, ref.ReferencedAt.IsZero())
+			ref.ReferencedAt = time.Time{}
+			own = append(own, ref)
+		}
+	}
+	require.ElementsMatch(t, []models.ObjectReference{
+		{UserDataID: file, ObjectName: "refs-v2"},
+		{UserDataID: file, Version: 1, ObjectName: "refs-v1"},
+	}, own, "trashed records and versions keep their objects referenced")
+}
+
+func TestStorage_Uploads(t *testing.T) {
+	st := setupTestStorage(t)
+	ctx := context.Background()
+
+	uid, err := st.AddUser(ctx, &models.UserEntry{Username: "uploaduser", PasswordHash: "hash"})
+	require.NoError(t, err)
+	upload := &models.Upload{
+		ID:           "upload-1",
+		UserID:       uid,
+		ObjectName:   "obj-upload",
+		MultipartID:  "mp-1",
+		FileName:     "scan.pdf",
+		FileType:     "application/pdf",
+		Meta:         "{}",
+		Size:         30,
+		EncryptedDek: []byte("dek"),
+		DekNonce:     []byte("kn"),
+		StreamHeader: []byte("header"),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	require.NoError(t, st.CreateUpload(ctx, upload))
+
+	got, err := st.GetUpload(ctx, upload.ID)
+	require.NoError(t, err)
+	require.Equal(t, upload.MultipartID, got.MultipartID)
+	require.Equal(t, upload.StreamHeader, got.StreamHeader)
+	require.Zero(t, got.Received)
+	_, err = st.GetUpload(ctx, "missing")
+	require.ErrorIs(t, err, models.ErrUploadNotFound)
+
+	digest, err := st.ReserveUploadPart(ctx, upload.ID, 1, []byte("first"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), digest)
+	digest, err = st.ReserveUploadPart(ctx, upload.ID, 1, []byte("other"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), digest, "the first digest of a part is kept")
+
+	parts, err := st.ListUploadParts(ctx, upload.ID)
+	require.NoError(t, err)
+	require.Empty(t, parts, "reserved parts are not listed until stored")
+
+	expiresAt := time.Now().Add(2 * time.Hour)
+	require.NoError(t, st.CommitUploadPart(ctx, upload.ID, 1, "etag-1", 0, 20, expiresAt))
+	require.ErrorIs(t, st.CommitUploadPart(ctx, upload.ID, 1, "etag-1", 0, 20, expiresAt), models.ErrUploadOffsetMismatch)
+
+	got, err = st.GetUpload(ctx, upload.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), got.Received)
+	require.WithinDuration(t, expiresAt, got.ExpiresAt, time.Second)
+
+	parts, err = st.ListUploadParts(ctx, upload.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.UploadPart{{Number: 1, ETag: "etag-1"}}, parts)
+
+	expired, err := st.ListExpiredUploads(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Empty(t, expired)
+	expired, err = st.ListExpiredUploads(ctx, time.Now().Add(3*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, upload.ID, expired[0].ID)
+
+	require.NoError(t, st.ClaimUpload(ctx, upload.ID))
+	require.ErrorIs(t, st.ClaimUpload(ctx, upload.ID), models.ErrUploadClaimed, "an upload is claimed once")
+	require.NoError(t, st.ReleaseUpload(ctx, upload.ID))
+	require.NoError(t, st.ClaimUpload(ctx, upload.ID), "a released upload can be claimed again")
+	require.ErrorIs(t, st.ClaimUpload(ctx, "missing"), models.ErrUploadClaimed)
+
+	require.NoError(t, st.DeleteUpload(ctx, upload.ID))
+	require.ErrorIs(t, st.DeleteUpload(ctx, upload.ID), models.ErrUploadNotFound)
+	parts, err = st.ListUploadParts(ctx, upload.ID)
+	require.NoError(t, err)
+	require.Empty(t, parts)
 }
 
 func TestStorage_GetMasterKey_NotFound(t *testing.T) {
@@ -196,7 +1235,7 @@ func TestStorage_AddUser_DBError(t *testing.T) {
 func TestStorage_SaveMasterKey_DBError(t *testing.T) {
 	st := setupTestStorage(t)
 	st.(*Storage).DB.Close()
-	_, err := st.SaveMasterKey(context.Background(), 1, []byte("a"), []byte("b"))
+	_, err := st.SaveMasterKey(context.Background(), &models.EncryptedMK{UserID: 1, EncryptedMK: []byte("a"), Nonce: []byte("b")})
 	require.Error(t, err)
 }
 
@@ -224,7 +1263,7 @@ func TestStorage_GetUserData_DBError(t *testing.T) {
 func TestStorage_GetUserDataList_DBError(t *testing.T) {
 	st := setupTestStorage(t)
 	st.(*Storage).DB.Close()
-	_, err := st.GetUserDataList(context.Background(), 1)
+	_, err := st.GetUserDataList(context.Background(), 1, models.UserDataListFilter{})
 	require.Error(t, err)
 }
 
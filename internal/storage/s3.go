@@ -20,15 +20,28 @@ import (
 
 // S3Client defines the interface for S3-compatible object storage operations.
 //
-// It abstracts file upload and retrieval for easier testing and mocking.
+// It abstracts file upload, retrieval, listing and removal, whole, streamed or in separately
+// uploaded parts, for easier testing and mocking.
 type S3Client interface {
 	Upload(ctx context.Context, data []byte, s3UploadData *models.S3UploadData) (*minio.UploadInfo, error)
 	GetObject(ctx context.Context, objectName string) ([]byte, *minio.ObjectInfo, error)
+	UploadStream(ctx context.Context, r io.Reader, s3UploadData *models.S3UploadData) (*minio.UploadInfo, error)
+	GetObjectStream(ctx context.Context, objectName string) (io.ReadSeekCloser, *minio.ObjectInfo, error)
+	Delete(ctx context.Context, objectName string) error
+	List(ctx context.Context) ([]models.S3Object, error)
+	CreateMultipartUpload(ctx context.Context, s3UploadData *models.S3UploadData) (string, error)
+	UploadPart(ctx context.Context, objectName, uploadID string, number int, data []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, objectName, uploadID string, parts []models.UploadPart) error
+	AbortMultipartUpload(ctx context.Context, objectName, uploadID string) error
 }
 
+// StreamPartSize is the part size of multipart uploads of streams of unknown length; it bounds
+// the memory an upload buffers.
+const StreamPartSize = 16 * 1024 * 1024
+
 // S3 implements the S3Client interface using a MinIO client.
 //
-// It provides methods to upload and retrieve objects from the configured bucket.
+// It provides methods to upload, retrieve, list and remove objects in the configured bucket.
 type S3 struct {
 	MinioClient *minio.Client
 	MinioBucket string
@@ -97,15 +110,42 @@ func (s *S3) Upload(
 		s3UploadData.ObjectName,
 		bytes.NewReader(data),
 		int64(len(data)),
-		minio.PutObjectOptions{
-			ContentType: s3UploadData.FileType,
-			UserMetadata: map[string]string{
-				"original-name": s3UploadData.FileName,
-				"meta-content":  s3UploadData.MetaContent,
-				"upload-time":   time.Now().Format(time.RFC3339),
-				"is-encrypted":  "true",
-			},
-		},
+		putObjectOptions(s3UploadData),
+	)
+
+	if errPutObject != nil {
+		return nil, fmt.Errorf("failed to upload file to MinIO: %v", errPutObject)
+	}
+
+	return &info, nil
+}
+
+// UploadStream uploads the contents of r, whose length is not known in advance, to the S3 bucket
+// as a multipart upload with parts of StreamPartSize.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - r: The object contents; read until EOF.
+//   - s3UploadData: Metadata and object information.
+//
+// Returns:
+//   - *minio.UploadInfo: Information about the uploaded object.
+//   - error: An error if reading r or the upload fails; no object is created then.
+func (s *S3) UploadStream(
+	ctx context.Context,
+	r io.Reader,
+	s3UploadData *models.S3UploadData,
+) (*minio.UploadInfo, error) {
+	opts := putObjectOptions(s3UploadData)
+	opts.PartSize = StreamPartSize
+
+	info, errPutObject := s.MinioClient.PutObject(
+		ctx,
+		s.MinioBucket,
+		s3UploadData.ObjectName,
+		r,
+		-1,
+		opts,
 	)
 
 	if errPutObject != nil {
@@ -153,3 +193,186 @@ func (s *S3) GetObject(
 
 	return data, &objectInfo, nil
 }
+
+// GetObjectStream opens an object in the S3 bucket for reading without loading it into memory.
+//
+// Reads continue a single request to MinIO until the object is seeked, so a range read in
+// order is fetched at once.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - objectName: Name of the object to retrieve.
+//
+// Returns:
+//   - io.ReadSeekCloser: The object contents; the caller closes it.
+//   - *minio.ObjectInfo: Metadata about the object.
+//   - error: An error if the object cannot be opened.
+func (s *S3) GetObjectStream(
+	ctx context.Context,
+	objectName string,
+) (io.ReadSeekCloser, *minio.ObjectInfo, error) {
+	object, err := s.MinioClient.GetObject(
+		ctx,
+		s.MinioBucket,
+		objectName,
+		minio.GetObjectOptions{},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get object from MinIO: %v", err)
+	}
+
+	objectInfo, err := object.Stat()
+	if err != nil {
+		_ = object.Close()
+		return nil, nil, fmt.Errorf("failed to get object info: %v", err)
+	}
+
+	return object, &objectInfo, nil
+}
+
+// Delete removes an object from the S3 bucket by its name.
+//
+// Removing an object that does not exist is not an error.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - objectName: Name of the object to remove.
+//
+// Returns:
+//   - error: An error if the removal fails.
+func (s *S3) Delete(ctx context.Context, objectName string) error {
+	if err := s.MinioClient.RemoveObject(ctx, s.MinioBucket, objectName, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to remove object from MinIO: %v", err)
+	}
+
+	return nil
+}
+
+// List returns all objects in the S3 bucket.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//
+// Returns:
+//   - []models.S3Object: The name, size and modification time of every object.
+//   - error: An error if the listing fails.
+func (s *S3) List(ctx context.Context) ([]models.S3Object, error) {
+	var objects []models.S3Object
+
+	for info := range s.MinioClient.ListObjects(ctx, s.MinioBucket, minio.ListObjectsOptions{Recursive: true}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list objects in MinIO: %v", info.Err)
+		}
+
+		objects = append(objects, models.S3Object{
+			Name:         info.Key,
+			Size:         info.Size,
+			LastModified: info.LastModified,
+		})
+	}
+
+	return objects, nil
+}
+
+// CreateMultipartUpload starts a multipart upload whose parts are uploaded separately, possibly
+// by different requests.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - s3UploadData: Metadata and object information.
+//
+// Returns:
+//   - string: The ID of the multipart upload.
+//   - error: An error if the upload cannot be started.
+func (s *S3) CreateMultipartUpload(ctx context.Context, s3UploadData *models.S3UploadData) (string, error) {
+	uploadID, err := s.core().NewMultipartUpload(ctx, s.MinioBucket, s3UploadData.ObjectName, putObjectOptions(s3UploadData))
+	if err != nil {
+		return "", fmt.Errorf("failed to start multipart upload in MinIO: %v", err)
+	}
+
+	return uploadID, nil
+}
+
+// UploadPart uploads one part of a multipart upload. Uploading a part number again replaces it.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - objectName: Name of the object being uploaded.
+//   - uploadID: The ID of the multipart upload.
+//   - number: The part number, starting at 1.
+//   - data: The part contents; all parts but the last must be at least 5 MiB.
+//
+// Returns:
+//   - string: The ETag of the part, needed to complete the upload.
+//   - error: An error if the upload fails.
+func (s *S3) UploadPart(ctx context.Context, objectName, uploadID string, number int, data []byte) (string, error) {
+	part, err := s.core().PutObjectPart(ctx, s.MinioBucket, objectName, uploadID, number,
+		bytes.NewReader(data), int64(len(data)), minio.PutObjectPartOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d to MinIO: %v", number, err)
+	}
+
+	return part.ETag, nil
+}
+
+// CompleteMultipartUpload joins the uploaded parts into the object.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - objectName: Name of the object being uploaded.
+//   - uploadID: The ID of the multipart upload.
+//   - parts: The uploaded parts in order.
+//
+// Returns:
+//   - error: An error if the upload cannot be completed.
+func (s *S3) CompleteMultipartUpload(ctx context.Context, objectName, uploadID string, parts []models.UploadPart) error {
+	completeParts := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		completeParts = append(completeParts, minio.CompletePart{PartNumber: p.Number, ETag: p.ETag})
+	}
+
+	if _, err := s.core().CompleteMultipartUpload(ctx, s.MinioBucket, objectName, uploadID,
+		completeParts, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to complete multipart upload in MinIO: %v", err)
+	}
+
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its parts.
+//
+// Aborting an upload that no longer exists is not an error.
+//
+// Parameters:
+//   - ctx: Context for the operation.
+//   - objectName: Name of the object being uploaded.
+//   - uploadID: The ID of the multipart upload.
+//
+// Returns:
+//   - error: An error if the upload cannot be aborted.
+func (s *S3) AbortMultipartUpload(ctx context.Context, objectName, uploadID string) error {
+	err := s.core().AbortMultipartUpload(ctx, s.MinioBucket, objectName, uploadID)
+	if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+		return fmt.Errorf("failed to abort multipart upload in MinIO: %v", err)
+	}
+
+	return nil
+}
+
+// core returns the low-level MinIO API used for multipart uploads.
+func (s *S3) core() *minio.Core {
+	return &minio.Core{Client: s.MinioClient}
+}
+
+// putObjectOptions returns the content type and user metadata stored with an object.
+func putObjectOptions(s3UploadData *models.S3UploadData) minio.PutObjectOptions {
+	return minio.PutObjectOptions{
+		ContentType: s3UploadData.FileType,
+		UserMetadata: map[string]string{
+			"original-name": s3UploadData.FileName,
+			"meta-content":  s3UploadData.MetaContent,
+			"upload-time":   time.Now().Format(time.RFC3339),
+			"is-encrypted":  "true",
+		},
+	}
+}
@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -111,11 +113,65 @@ func TestS3_FullFlow(t *testing.T) {
 	require.Equal(t, "test-meta", info.UserMetadata["Meta-Content"])
 	require.Equal(t, "true", info.UserMetadata["Is-Encrypted"])
 
+	// Stream
+	streamed := bytes.Repeat([]byte("stream "), 1024)
+	_, err = s3.UploadStream(ctx, bytes.NewReader(streamed), &models.S3UploadData{ObjectName: "streamed", FileName: "s.bin"})
+	require.NoError(t, err)
+	rc, info, err := s3.GetObjectStream(ctx, "streamed")
+	require.NoError(t, err)
+	got, err = io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, streamed, got)
+	require.Equal(t, "s.bin", info.UserMetadata["Original-Name"])
+	_, err = rc.Seek(7, io.SeekStart)
+	require.NoError(t, err)
+	tail, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, streamed[7:], tail)
+	require.NoError(t, rc.Close())
+	require.NoError(t, s3.Delete(ctx, "streamed"))
+
+	// Multipart
+	uploadID, err := s3.CreateMultipartUpload(ctx, &models.S3UploadData{ObjectName: "parts", FileName: "p.bin"})
+	require.NoError(t, err)
+	first := bytes.Repeat([]byte{1}, 5*1024*1024)
+	etag1, err := s3.UploadPart(ctx, "parts", uploadID, 1, first)
+	require.NoError(t, err)
+	etag2, err := s3.UploadPart(ctx, "parts", uploadID, 2, []byte("tail"))
+	require.NoError(t, err)
+	require.NoError(t, s3.CompleteMultipartUpload(ctx, "parts", uploadID,
+		[]models.UploadPart{{Number: 1, ETag: etag1}, {Number: 2, ETag: etag2}}))
+	got, info, err = s3.GetObject(ctx, "parts")
+	require.NoError(t, err)
+	require.Equal(t, append(first, "tail"...), got)
+	require.Equal(t, "p.bin", info.UserMetadata["Original-Name"])
+	require.NoError(t, s3.Delete(ctx, "parts"))
+
+	abortedID, err := s3.CreateMultipartUpload(ctx, &models.S3UploadData{ObjectName: "aborted"})
+	require.NoError(t, err)
+	_, err = s3.UploadPart(ctx, "aborted", abortedID, 1, []byte("partial"))
+	require.NoError(t, err)
+	require.NoError(t, s3.AbortMultipartUpload(ctx, "aborted", abortedID))
+	require.NoError(t, s3.AbortMultipartUpload(ctx, "aborted", abortedID), "aborting twice succeeds")
+
+	// List
+	objects, err := s3.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.Equal(t, objectName, objects[0].Name)
+	require.Equal(t, int64(len(content)), objects[0].Size)
+
 	// Delete
-	err = s3.MinioClient.RemoveObject(ctx, cfg.Bucket, objectName, minio.RemoveObjectOptions{})
+	require.NoError(t, s3.Delete(ctx, objectName))
+
+	objects, err = s3.List(ctx)
 	require.NoError(t, err)
+	require.Empty(t, objects)
 
 	// Ensure deleted
 	_, _, err = s3.GetObject(ctx, objectName)
 	require.Error(t, err)
+
+	// Deleting a missing object succeeds
+	require.NoError(t, s3.Delete(ctx, objectName))
 }
@@ -7,6 +7,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/apetsko/gophkeeper/models"
 )
@@ -25,18 +26,129 @@ type IStorage interface {
 	// Returns the new user's ID or an error if the user already exists or the operation fails.
 	AddUser(ctx context.Context, u *models.UserEntry) (int, error)
 
+	// AddClientUser adds a new zero-knowledge user and its client key material in one transaction.
+	// Returns the new user's ID or an error if the user already exists or the operation fails.
+	AddClientUser(ctx context.Context, u *models.UserEntry, key *models.ClientKey) (int, error)
+
+	// GetClientKey retrieves the client key material of a zero-knowledge user.
+	// Returns models.ErrClientKeyNotFound for accounts using server-side encryption.
+	GetClientKey(ctx context.Context, userID int) (*models.ClientKey, error)
+
 	// GetUser retrieves a user by username.
 	// Returns the user entry or an error if not found.
 	GetUser(ctx context.Context, username string) (*models.UserEntry, error)
 
-	// SaveMasterKey stores an encrypted master key for a user.
+	// GetUserByID retrieves a user by ID.
+	// Returns the user entry or models.ErrUserNotFound.
+	GetUserByID(ctx context.Context, id int) (*models.UserEntry, error)
+
+	// UpdatePassword sets a new password hash and the master key re-wrapped in place by rewrap,
+	// which is given the key locked for the transaction. When rewrap replaces the master key, the
+	// DEK re-wrapping function it returns is applied to every data key of the user.
+	UpdatePassword(
+		ctx context.Context,
+		userID int,
+		passwordHash string,
+		rewrap func(key *models.EncryptedMK) (func(encryptedDEK, nonce []byte) ([]byte, []byte, error), error),
+	) error
+
+	// UpdateClientPassword sets a new password hash and client key material of a zero-knowledge
+	// user in one transaction.
+	UpdateClientPassword(ctx context.Context, userID int, passwordHash string, key *models.ClientKey) error
+
+	// SaveMasterKey stores an encrypted master key and its KDF parameters for key.UserID.
 	// Returns the new record's ID or an error if the operation fails.
-	SaveMasterKey(ctx context.Context, userID int, encryptedMK []byte, nonce []byte) (int, error)
+	SaveMasterKey(ctx context.Context, key *models.EncryptedMK) (int, error)
+
+	// UpdateMasterKey replaces the stored master key of key.UserID. When rewrapDEK is not nil,
+	// every data encryption key of the user, including those of unfinished uploads, is re-wrapped
+	// with it in the same transaction.
+	UpdateMasterKey(
+		ctx context.Context,
+		key *models.EncryptedMK,
+		rewrapDEK func(encryptedDEK, nonce []byte) ([]byte, []byte, error),
+	) error
 
 	// GetMasterKey retrieves the encrypted master key for a user.
 	// Returns the encrypted master key or an error if not found.
 	GetMasterKey(ctx context.Context, userID int) (*models.EncryptedMK, error)
 
+	// CreateKeyRotationJob starts a job re-wrapping master keys with the server key targetKeyID.
+	// Returns models.ErrKeyRotationInProgress if another job is running.
+	CreateKeyRotationJob(ctx context.Context, targetKeyID string) (*models.KeyRotationJob, error)
+
+	// GetKeyRotationJob returns the most recent key rotation job and the number of master keys
+	// not yet wrapped with its target key. Returns models.ErrKeyRotationNotFound if there is none.
+	GetKeyRotationJob(ctx context.Context) (*models.KeyRotationJob, error)
+
+	// RewrapMasterKeysBatch re-wraps up to limit master keys not yet wrapped with the target key
+	// of job jobID and records the progress in the same transaction.
+	// Returns the number of re-wrapped keys; zero means the job has nothing left to do.
+	RewrapMasterKeysBatch(ctx context.Context, jobID, limit int, rewrap func(key *models.EncryptedMK) error) (int, error)
+
+	// RewrapTOTPSecretsBatch re-wraps up to limit TOTP secrets not yet wrapped with the target key
+	// of job jobID. Returns the number of re-wrapped secrets; zero means none are left.
+	RewrapTOTPSecretsBatch(ctx context.Context, jobID, limit int, rewrap func(secret *models.WrappedSecret) error) (int, error)
+
+	// FinishKeyRotationJob sets the final status of a key rotation job and the failure reason, if any.
+	FinishKeyRotationJob(ctx context.Context, jobID int, status, reason string) error
+
+	// GetTOTP retrieves the two-factor authentication state of a user.
+	// Returns models.ErrTOTPNotEnrolled if the user has no TOTP secret.
+	GetTOTP(ctx context.Context, userID int) (*models.TOTP, error)
+
+	// SaveTOTPSecret stores a new, not yet confirmed TOTP secret.
+	SaveTOTPSecret(ctx context.Context, userID int, secret *models.WrappedSecret) error
+
+	// EnableTOTP confirms the pending TOTP secret and replaces the recovery codes in one transaction.
+	EnableTOTP(ctx context.Context, userID int, step int64, recoveryCodeHashes [][]byte) error
+
+	// DisableTOTP removes the TOTP secret and the recovery codes of a user.
+	DisableTOTP(ctx context.Context, userID int) error
+
+	// UpdateTOTPStep records the time step of an accepted code.
+	// Returns models.ErrTOTPCodeReused if the step is not after the last accepted one.
+	UpdateTOTPStep(ctx context.Context, userID int, step int64) error
+
+	// UseRecoveryCode marks a recovery code as used.
+	// Returns models.ErrRecoveryCodeInvalid if no unused code has that hash.
+	UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) error
+
+	// CreateTwoFactorChallenge records the pending login of the two-factor token jti.
+	CreateTwoFactorChallenge(ctx context.Context, userID int, jti string, expiresAt time.Time) error
+
+	// StartTwoFactorAttempt counts an attempt at the second factor, with the token jti if set,
+	// before the code is checked. Returns models.ErrTwoFactorLocked if the user is locked out or
+	// models.ErrTwoFactorTokenUsed if the token is used, expired or out of attempts.
+	StartTwoFactorAttempt(ctx context.Context, userID int, jti string, limits models.TwoFactorLimits) error
+
+	// FinishTwoFactorAttempt clears the failed attempts of the user and marks the token jti, if
+	// set, used. Returns models.ErrTwoFactorTokenUsed if the token was already used.
+	FinishTwoFactorAttempt(ctx context.Context, userID int, jti string) error
+
+	// CreateSession starts a session with its first refresh token and returns the session ID.
+	CreateSession(ctx context.Context, session *models.Session, tokenHash []byte) (int, error)
+
+	// RotateRefreshToken replaces a refresh token with a new one in the same session.
+	// Returns models.ErrRefreshTokenInvalid for unknown, expired or revoked tokens, and
+	// models.ErrRefreshTokenReused, after revoking the session, for a token used before.
+	RotateRefreshToken(ctx context.Context, tokenHash, newHash []byte, expiresAt time.Time) (*models.Session, error)
+
+	// RevokeSession revokes an active session of a user.
+	// Returns models.ErrSessionNotFound if there is no such active session.
+	RevokeSession(ctx context.Context, userID, sessionID int, reason string) error
+
+	// RevokeOtherSessions revokes all active sessions of a user except keepSessionID
+	// and returns how many were revoked.
+	RevokeOtherSessions(ctx context.Context, userID, keepSessionID int, reason string) (int, error)
+
+	// ListSessions returns the active sessions of a user, most recently seen first.
+	ListSessions(ctx context.Context, userID int) ([]*models.Session, error)
+
+	// TouchSession reports whether tokens of a session are still accepted and records
+	// that the session was seen from ip.
+	TouchSession(ctx context.Context, sessionID int, ip string) (bool, error)
+
 	// SaveUserData stores encrypted user data in the storage.
 	// Returns the new record's ID or an error if the operation fails.
 	SaveUserData(ctx context.Context, userData *models.DBUserData) (int, error)
@@ -45,9 +157,124 @@ type IStorage interface {
 	// Returns the user data or an error if not found.
 	GetUserData(ctx context.Context, userDataID int) (*models.DBUserData, error)
 
-	// GetUserDataList returns a list of user data items for a given user.
-	// Returns the list or an error if the query fails.
-	GetUserDataList(ctx context.Context, userID int) ([]models.UserDataListItem, error)
+	// UpdateUserData archives the current contents of a user's record as a version, keeping the
+	// keep newest versions, and replaces its payload, wrapped DEK, object and meta.
+	// Returns the MinIO objects left unreferenced by the pruned versions, for the caller to
+	// remove, or models.ErrUserDataNotFound if the user has no record with that ID.
+	UpdateUserData(ctx context.Context, userDataID int, userData *models.DBUserData, keep int) ([]string, error)
+
+	// ListUserDataVersions returns the prior versions of a record, newest first, without payloads.
+	ListUserDataVersions(ctx context.Context, userDataID int) ([]models.UserDataVersion, error)
+
+	// GetUserDataVersion retrieves a prior version of a record.
+	// Returns models.ErrVersionNotFound if the record has no such version.
+	GetUserDataVersion(ctx context.Context, userDataID, version int) (*models.UserDataVersion, error)
+
+	// RestoreUserDataVersion archives the current contents of a user's record and replaces them
+	// with a prior version, keeping the keep newest versions. Returns the new version number and
+	// the MinIO objects left unreferenced by the pruned versions, for the caller to remove, or
+	// models.ErrUserDataNotFound or models.ErrVersionNotFound.
+	RestoreUserDataVersion(ctx context.Context, userDataID, userID, version, keep int) (int, []string, error)
+
+	// SetVersionRetention sets the number of versions kept per record of a user; 0 selects the
+	// server default. Returns models.ErrUserNotFound if there is no such user.
+	SetVersionRetention(ctx context.Context, userID, retention int) error
+
+	// GetUserDataList returns the page of a user's records selected by filter, together with the
+	// number of records matching the filter and the cursor of the next page.
+	GetUserDataList(ctx context.Context, userID int, filter models.UserDataListFilter) (*models.UserDataPage, error)
+
+	// SearchUserData returns the records of a user outside the trash whose metadata matches
+	// query, best match first.
+	SearchUserData(ctx context.Context, userID int, query models.SearchQuery) ([]models.SearchResult, error)
+
+	// TrashUserData moves a user's record into the trash, hiding it from GetUserData and GetUserDataList.
+	// Returns models.ErrUserDataNotFound if the user has no such record outside the trash.
+	TrashUserData(ctx context.Context, userDataID, userID int) error
+
+	// GetTrashList returns the trashed records of a user, most recently deleted first.
+	GetTrashList(ctx context.Context, userID int) ([]models.UserDataListItem, error)
+
+	// RestoreUserData takes a user's record out of the trash.
+	// Returns models.ErrUserDataNotFound if the user has no such record in the trash.
+	RestoreUserData(ctx context.Context, userDataID, userID int) error
+
+	// EmptyTrash permanently deletes the trashed records of a user and returns their number
+	// and the MinIO objects they and their versions referenced.
+	EmptyTrash(ctx context.Context, userID int) (int, []string, error)
+
+	// PurgeTrash permanently deletes up to limit records trashed before deletedBefore and returns
+	// their number and the MinIO objects they and their versions referenced.
+	PurgeTrash(ctx context.Context, deletedBefore time.Time, limit int) (int, []string, error)
+
+	// ListObjectReferences returns every MinIO object referenced by a record, trashed or not,
+	// or by one of its versions.
+	ListObjectReferences(ctx context.Context) ([]models.ObjectReference, error)
+
+	// CreateFolder stores a new folder of a user and returns its ID.
+	// Returns models.ErrFolderNotFound if the parent is not a folder of the user.
+	CreateFolder(ctx context.Context, folder *models.Folder) (int, error)
+
+	// ListFolders returns all folders of a user, oldest first.
+	ListFolders(ctx context.Context, userID int) ([]models.Folder, error)
+
+	// RenameFolder replaces the encrypted name of a user's folder.
+	// Returns models.ErrFolderNotFound if the user has no such folder.
+	RenameFolder(ctx context.Context, folder *models.Folder) error
+
+	// MoveFolder moves a user's folder into another folder, or to the root if parentID is 0.
+	// Returns models.ErrFolderNotFound if either folder is not the user's and models.ErrFolderCycle
+	// if parentID is the folder itself or one of its subfolders.
+	MoveFolder(ctx context.Context, folderID, userID, parentID int) error
+
+	// DeleteFolder deletes a user's folder and hands its records and subfolders to its parent.
+	// Returns models.ErrFolderNotFound if the user has no such folder.
+	DeleteFolder(ctx context.Context, folderID, userID int) error
+
+	// MoveUserData moves records of a user into a folder, or out of any folder if folderID is 0.
+	// Returns models.ErrFolderNotFound if the folder is not the user's and models.ErrUserDataNotFound
+	// if one of the records is not the user's; no record is moved then.
+	MoveUserData(ctx context.Context, userID int, userDataIDs []int, folderID int) error
+
+	// CreateUpload stores a new resumable upload.
+	CreateUpload(ctx context.Context, upload *models.Upload) error
+
+	// GetUpload retrieves a resumable upload by its ID.
+	// Returns models.ErrUploadNotFound if there is no such upload.
+	GetUpload(ctx context.Context, uploadID string) (*models.Upload, error)
+
+	// ReserveUploadPart records the digest of the plaintext of a part unless the part already
+	// has one, and returns the digest on record.
+	ReserveUploadPart(ctx context.Context, uploadID string, number int, digest []byte) ([]byte, error)
+
+	// CommitUploadPart records the ETag of a stored part and advances the received offset of the
+	// upload from offset to received, extending it until expiresAt.
+	// Returns models.ErrUploadOffsetMismatch if the upload has moved on from offset.
+	CommitUploadPart(
+		ctx context.Context,
+		uploadID string,
+		number int,
+		etag string,
+		offset, received int64,
+		expiresAt time.Time,
+	) error
+
+	// ListUploadParts returns the stored parts of an upload in order.
+	ListUploadParts(ctx context.Context, uploadID string) ([]models.UploadPart, error)
+
+	// ClaimUpload marks an open upload as being completed or aborted.
+	// Returns models.ErrUploadClaimed if it is gone or another request has claimed it.
+	ClaimUpload(ctx context.Context, uploadID string) error
+
+	// ReleaseUpload reopens a claimed upload whose completion or abort failed.
+	ReleaseUpload(ctx context.Context, uploadID string) error
+
+	// DeleteUpload deletes a resumable upload and its parts.
+	// Returns models.ErrUploadNotFound if there is no such upload.
+	DeleteUpload(ctx context.Context, uploadID string) error
+
+	// ListExpiredUploads returns up to limit uploads that expired before the given time.
+	ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]*models.Upload, error)
 
 	// DeleteUserData deletes a user data record by its ID.
 	// Returns an error if not found or deletion fails.
@@ -1,13 +1,71 @@
 package models
 
+import "time"
+
 // EncryptedMK holds an encrypted master key and its nonce.
 //
+// The master key is stored twice: sealed with the server key for data access, and wrapped with
+// a key derived from the user's password, which is how the password is verified against it.
+//
 // Fields:
-//   - EncryptedMK: The encrypted master key bytes.
+//   - UserID: The ID of the user who owns the key.
+//   - ServerKeyID: The ID of the server key EncryptedMK is sealed with.
+//   - EncryptedMK: The master key encrypted with the server key.
 //   - Nonce: The nonce used for encryption.
+//   - KDFVersion: The version of the KDF parameters; 0 marks legacy keys derived without a salt.
+//   - KDF: Parameters used to derive the password key, nil for legacy keys.
+//   - PasswordWrappedMK: The master key wrapped with the password key (nonce || ciphertext).
 type EncryptedMK struct {
-	EncryptedMK []byte `json:"encrypted_mk"`
-	Nonce       []byte `json:"nonce"`
+	UserID            int        `json:"user_id"`
+	ServerKeyID       string     `json:"server_key_id"`
+	EncryptedMK       []byte     `json:"encrypted_mk"`
+	Nonce             []byte     `json:"nonce"`
+	KDFVersion        int        `json:"kdf_version"`
+	KDF               *KDFParams `json:"kdf,omitempty"`
+	PasswordWrappedMK []byte     `json:"password_wrapped_mk,omitempty"`
+}
+
+// WrappedSecret is a small secret sealed with a server key, such as a TOTP secret.
+//
+// Fields:
+//   - KeyID: The ID of the server key the secret is sealed with.
+//   - Ciphertext: The sealed secret.
+//   - Nonce: The nonce used for sealing; empty for backends that embed it in the ciphertext.
+type WrappedSecret struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext []byte `json:"ciphertext"`
+	Nonce      []byte `json:"nonce"`
+}
+
+// KDFParams describes how a key is derived from a password.
+//
+// Fields:
+//   - Algorithm: The key derivation function name (e.g. "argon2id").
+//   - Salt: The random salt.
+//   - Time: The number of passes over memory.
+//   - Memory: The memory cost in KiB.
+//   - Threads: The degree of parallelism.
+type KDFParams struct {
+	Algorithm string `json:"algorithm"`
+	Salt      []byte `json:"salt"`
+	Time      uint32 `json:"time"`
+	Memory    uint32 `json:"memory"`
+	Threads   uint8  `json:"threads"`
+}
+
+// ClientKey holds the key material of a zero-knowledge account.
+//
+// The server only stores it on behalf of the client: the master key is wrapped with a key
+// derived on the client from the user's password and cannot be opened server-side.
+//
+// Fields:
+//   - UserID: The ID of the user who owns the key.
+//   - KDF: Parameters the client uses to derive its keys from the password.
+//   - WrappedMasterKey: The master key sealed by the client (nonce || ciphertext).
+type ClientKey struct {
+	UserID           int       `json:"user_id"`
+	KDF              KDFParams `json:"kdf"`
+	WrappedMasterKey []byte    `json:"wrapped_master_key"`
 }
 
 // EncryptedData contains encrypted user data and associated nonces.
@@ -23,3 +81,37 @@ type EncryptedData struct {
 	EncryptedDek  []byte
 	DekNonce      []byte
 }
+
+// Key rotation job statuses.
+const (
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// KeyRotationJob tracks the re-wrapping of all master keys under a new server key.
+//
+// Progress is committed together with every batch, so a job interrupted by a restart resumes
+// after the last processed user.
+//
+// Fields:
+//   - ID: The job ID.
+//   - TargetKeyID: The server key ID master keys are re-wrapped with.
+//   - Status: JobStatusRunning, JobStatusDone or JobStatusFailed.
+//   - LastUserID: The user ID of the last processed master key.
+//   - Rewrapped: The number of master keys re-wrapped so far.
+//   - Remaining: The number of master keys still wrapped with other keys.
+//   - Error: The failure reason of a failed job.
+//   - StartedAt: When the job was created.
+//   - UpdatedAt: When the job last made progress.
+type KeyRotationJob struct {
+	ID          int       `json:"id"`
+	TargetKeyID string    `json:"target_key_id"`
+	Status      string    `json:"status"`
+	LastUserID  int       `json:"last_user_id"`
+	Rewrapped   int       `json:"rewrapped"`
+	Remaining   int       `json:"remaining"`
+	Error       string    `json:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
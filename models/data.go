@@ -8,11 +8,15 @@ import "time"
 //   - UserID: The ID of the user who owns the data.
 //   - Type: The type/category of the data.
 //   - MinioObjectID: The S3/MinIO object identifier.
-//   - Meta: Metadata associated with the data.
+//   - Meta: Metadata associated with the data, without the values of sensitive fields.
+//   - MetaSecrets: The sensitive meta fields, encrypted with the DEK; nil when there are none.
+//   - MetaSecretsNonce: Nonce for MetaSecrets.
 //   - EncryptedData: The encrypted data bytes.
 //   - DataNonce: Nonce for the encrypted data.
 //   - EncryptedDek: The encrypted data encryption key.
 //   - DekNonce: Nonce for the encrypted DEK.
+//   - ClientEncrypted: Whether the payload was encrypted by a zero-knowledge client.
+//   - Version: The version number of the contents; it grows with every update.
 type DBUserData struct {
 	UserID        int    `json:"user_id"`
 	Type          string `json:"type"`
@@ -22,6 +26,27 @@ type DBUserData struct {
 	DataNonce     []byte `json:"data_nonce"`
 	EncryptedDek  []byte `json:"encrypted_dek"`
 	DekNonce      []byte `json:"dek_nonce"`
+
+	MetaSecrets      []byte `json:"meta_secrets"`
+	MetaSecretsNonce []byte `json:"meta_secrets_nonce"`
+
+	ClientEncrypted bool `json:"client_encrypted"`
+	Version         int  `json:"version"`
+}
+
+// UserDataVersion represents a prior revision of a user data record.
+//
+// Fields:
+//   - Version: The version number of the revision.
+//   - Data: The archived payload, wrapped DEK, MinIO object and meta; UserID, Type and
+//     ClientEncrypted are those of the record and are not stored with the revision.
+//   - CreatedAt: Timestamp when the revision was written.
+//   - ArchivedAt: Timestamp when the revision was replaced by a newer one.
+type UserDataVersion struct {
+	Version    int        `json:"version"`
+	Data       DBUserData `json:"data"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ArchivedAt time.Time  `json:"archived_at"`
 }
 
 // UserDataListItem represents a summary of a user data record for listing purposes.
@@ -31,11 +56,85 @@ type DBUserData struct {
 //   - UserID: The ID of the user who owns the data.
 //   - Type: The type/category of the data.
 //   - Meta: Metadata associated with the data.
+//   - FolderID: The folder of the record; 0 outside any folder.
+//   - Version: The version number of the current contents.
 //   - CreatedAt: Timestamp when the data was created.
+//   - UpdatedAt: Timestamp when the data was last changed.
+//   - DeletedAt: Timestamp when the data was moved to the trash; zero outside the trash.
 type UserDataListItem struct {
 	ID        int       `json:"id"`
 	UserID    int       `json:"user_id"`
 	Type      string    `json:"type"`
 	Meta      string    `json:"meta"`
+	FolderID  int       `json:"folder_id"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// UserDataSort is the order of the records returned by GetUserDataList.
+type UserDataSort int
+
+const (
+	// SortCreatedDesc lists the newest records first.
+	SortCreatedDesc UserDataSort = iota
+	// SortCreatedAsc lists the oldest records first.
+	SortCreatedAsc
+	// SortUpdatedDesc lists the most recently changed records first.
+	SortUpdatedDesc
+	// SortUpdatedAsc lists the least recently changed records first.
+	SortUpdatedAsc
+)
+
+// UserDataCursor is the position in a list after which the next page starts.
+//
+// Fields:
+//   - Time: The creation or update time of the last record, depending on the sort.
+//   - ID: The ID of the last record, which orders records with equal times.
+type UserDataCursor struct {
+	Time time.Time
+	ID   int
+}
+
+// UserDataListFilter selects a page of the records returned by GetUserDataList.
+//
+// Fields:
+//   - Types: Only records of these types are listed; all types when empty.
+//   - Tags: Only records with all of these tags are listed.
+//   - FavoritesOnly: Only records marked as favorite are listed.
+//   - FolderID: Only records in this folder are listed; records of all folders when zero.
+//   - Subfolders: With FolderID, the records of its subfolders are listed too.
+//   - CreatedFrom, CreatedTo: The range of creation times; CreatedTo is exclusive, zero bounds are open.
+//   - UpdatedFrom, UpdatedTo: The range of update times; UpdatedTo is exclusive, zero bounds are open.
+//   - Sort: The order of the records.
+//   - After: The cursor of the previous page; Offset is ignored when it is set.
+//   - Offset: The number of records to skip.
+//   - Limit: The maximum number of records returned; no limit when zero.
+type UserDataListFilter struct {
+	Types         []string
+	Tags          []string
+	FavoritesOnly bool
+	FolderID      int
+	Subfolders    bool
+	CreatedFrom   time.Time
+	CreatedTo     time.Time
+	UpdatedFrom   time.Time
+	UpdatedTo     time.Time
+	Sort          UserDataSort
+	After         *UserDataCursor
+	Offset        int
+	Limit         int
+}
+
+// UserDataPage is a page of the records returned by GetUserDataList.
+//
+// Fields:
+//   - Items: The records of the page.
+//   - Total: The number of records that match the filter, on all pages.
+//   - Next: The cursor of the next page; nil on the last page.
+type UserDataPage struct {
+	Items []UserDataListItem
+	Total int
+	Next  *UserDataCursor
 }
@@ -6,4 +6,27 @@ var (
 	ErrUserExists        = errors.New("user already exists")
 	ErrUserNotFound      = errors.New("user not found")
 	ErrMasterKeyNotFound = errors.New("master key not found")
+	ErrClientKeyNotFound = errors.New("client key not found")
+	ErrUserDataNotFound  = errors.New("user data not found")
+	ErrVersionNotFound   = errors.New("user data version not found")
+
+	ErrKeyRotationInProgress = errors.New("key rotation is already in progress")
+	ErrKeyRotationNotFound   = errors.New("key rotation job not found")
+
+	ErrTOTPNotEnrolled     = errors.New("two-factor authentication is not enrolled")
+	ErrTOTPCodeReused      = errors.New("TOTP code was already used")
+	ErrRecoveryCodeInvalid = errors.New("recovery code is invalid or used")
+	ErrTwoFactorTokenUsed  = errors.New("two-factor token is used, expired or out of attempts")
+	ErrTwoFactorLocked     = errors.New("too many failed two-factor attempts")
+
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+
+	ErrFolderNotFound = errors.New("folder not found")
+	ErrFolderCycle    = errors.New("folder cannot be moved into itself or its subfolders")
+
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadOffsetMismatch = errors.New("upload offset does not match the received data")
+	ErrUploadClaimed        = errors.New("upload is already being completed or aborted")
 )
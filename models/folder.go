@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Folder is a folder organising the records of a user.
+//
+// Fields:
+//   - ID: The folder ID.
+//   - UserID: The ID of the user who owns the folder.
+//   - ParentID: The ID of the folder containing this one; 0 at the root.
+//   - EncryptedName: The name encrypted with the DEK.
+//   - NameNonce: Nonce for EncryptedName.
+//   - EncryptedDek: The DEK, wrapped with the master key of the user.
+//   - DekNonce: Nonce for EncryptedDek.
+//   - ClientEncrypted: Whether the name was encrypted by a zero-knowledge client.
+//   - CreatedAt: When the folder was created.
+//   - UpdatedAt: When the folder was last renamed or moved.
+type Folder struct {
+	ID              int
+	UserID          int
+	ParentID        int
+	EncryptedName   []byte
+	NameNonce       []byte
+	EncryptedDek    []byte
+	DekNonce        []byte
+	ClientEncrypted bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
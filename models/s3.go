@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // S3UploadData contains metadata for uploading an object to S3/MinIO.
 //
 // Fields:
@@ -13,3 +15,55 @@ type S3UploadData struct {
 	FileName    string
 	FileType    string
 }
+
+// S3Object describes an object stored in the S3/MinIO bucket.
+//
+// Fields:
+//   - Name: The name of the object in the bucket.
+//   - Size: The size of the object in bytes.
+//   - LastModified: Timestamp when the object was written.
+type S3Object struct {
+	Name         string
+	Size         int64
+	LastModified time.Time
+}
+
+// ObjectReference is an S3/MinIO object referenced by a user data record or one of its versions.
+//
+// Fields:
+//   - UserDataID: The ID of the user data record.
+//   - Version: The version of the record that references the object; zero for the current contents.
+//   - ObjectName: The name of the object in the bucket.
+//   - ReferencedAt: Timestamp when the row started referencing the object.
+type ObjectReference struct {
+	UserDataID   int
+	Version      int
+	ObjectName   string
+	ReferencedAt time.Time
+}
+
+// ReconcileReport is the result of comparing the bucket with the records that reference it.
+//
+// Fields:
+//   - Objects: The number of objects in the bucket.
+//   - References: The number of rows referencing an object.
+//   - Orphans: Objects that no record or version references.
+//   - Missing: References whose object is not in the bucket.
+//   - Removed: The number of orphans deleted by a repairing run.
+type ReconcileReport struct {
+	Objects    int
+	References int
+	Orphans    []S3Object
+	Missing    []ObjectReference
+	Removed    int
+}
+
+// UploadPart is a part of a multipart upload to S3/MinIO.
+//
+// Fields:
+//   - Number: The part number, starting at 1.
+//   - ETag: The ETag MinIO returned for the part.
+type UploadPart struct {
+	Number int
+	ETag   string
+}
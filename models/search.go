@@ -0,0 +1,26 @@
+package models
+
+// SearchQuery selects the records returned by SearchUserData.
+//
+// Fields:
+//   - Terms: The words of the query; records whose metadata contains words starting with all
+//     of them are matched by full-text search.
+//   - Text: The query as typed, matched by trigram similarity so misspelled words are found.
+//   - Types: Only records of these types are searched; all types when empty.
+//   - Limit: The maximum number of results.
+type SearchQuery struct {
+	Terms []string
+	Text  string
+	Types []string
+	Limit int
+}
+
+// SearchResult is a record matched by SearchUserData.
+//
+// Fields:
+//   - Item: The matched record.
+//   - Score: The full-text rank plus the trigram similarity; a higher score is a better match.
+type SearchResult struct {
+	Item  UserDataListItem
+	Score float64
+}
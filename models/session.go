@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Session is a login of a user on one client: the family of refresh tokens issued since
+// the login, each replacing the previous one.
+//
+// Access tokens carry the session ID, so revoking the session invalidates them together
+// with its refresh tokens.
+//
+// Fields:
+//   - ID: The session ID.
+//   - UserID: The ID of the user.
+//   - DeviceName: The device or browser the user logged in from.
+//   - ClientVersion: The version of the client application.
+//   - IP: The address the session was last seen from.
+//   - CreatedAt: When the user logged in.
+//   - LastUsedAt: When the session was last seen.
+//   - ExpiresAt: When the current refresh token expires.
+//   - RevokedAt: When the session was revoked, nil while it is active.
+type Session struct {
+	ID            int        `json:"id"`
+	UserID        int        `json:"user_id"`
+	DeviceName    string     `json:"device_name"`
+	ClientVersion string     `json:"client_version"`
+	IP            string     `json:"ip"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastUsedAt    time.Time  `json:"last_used_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Upload is a resumable file upload: a MinIO multipart upload whose parts arrive in separate
+// requests and are encrypted as parts of one segmented stream.
+//
+// Fields:
+//   - ID: The random upload ID given to the client.
+//   - UserID: The ID of the user uploading the file.
+//   - ObjectName: The name of the object being uploaded.
+//   - MultipartID: The ID of the MinIO multipart upload.
+//   - FileName: The original file name.
+//   - FileType: The MIME type of the file.
+//   - Meta: The record meta, as protojson, without the values of sensitive fields.
+//   - MetaSecrets: The sensitive meta fields, encrypted with the DEK; nil when there are none.
+//   - MetaSecretsNonce: Nonce for MetaSecrets.
+//   - Size: The size of the file in bytes, declared when the upload is created.
+//   - Received: The number of bytes received so far.
+//   - EncryptedDek: The DEK of the file, encrypted with the user's master key.
+//   - DekNonce: The nonce used to encrypt the DEK.
+//   - StreamHeader: The header of the encrypted stream, needed to seal the remaining parts.
+//   - CreatedAt: When the upload was created.
+//   - ExpiresAt: When the upload is abandoned unless more data arrives.
+type Upload struct {
+	ID               string
+	UserID           int
+	ObjectName       string
+	MultipartID      string
+	FileName         string
+	FileType         string
+	Meta             string
+	MetaSecrets      []byte
+	MetaSecretsNonce []byte
+	Size             int64
+	Received         int64
+	EncryptedDek     []byte
+	DekNonce         []byte
+	StreamHeader     []byte
+	CreatedAt        time.Time
+	ExpiresAt        time.Time
+}
@@ -1,6 +1,15 @@
 // Package models defines data structures used throughout the GophKeeper application.
 package models
 
+import "time"
+
+const (
+	// EncryptionModeServer marks legacy accounts whose data is encrypted by the server.
+	EncryptionModeServer = "server"
+	// EncryptionModeClient marks zero-knowledge accounts whose data is encrypted by the client.
+	EncryptionModeClient = "client"
+)
+
 // User represents a user registration or login request.
 //
 // Fields:
@@ -17,8 +26,41 @@ type User struct {
 //   - ID: Unique user identifier.
 //   - Username: The user's login name.
 //   - PasswordHash: The hashed password.
+//   - EncryptionMode: Where user data is encrypted: EncryptionModeServer or EncryptionModeClient.
+//   - TOTPEnabled: Whether login requires a TOTP code.
+//   - VersionRetention: The number of prior versions kept per record; 0 selects the server default.
 type UserEntry struct {
-	ID           int    `json:"id"`
-	Username     string `json:"username"`
-	PasswordHash string `json:"password_hash"`
+	ID             int    `json:"id"`
+	Username       string `json:"username"`
+	PasswordHash   string `json:"password_hash"`
+	EncryptionMode string `json:"encryption_mode"`
+	TOTPEnabled    bool   `json:"totp_enabled"`
+
+	VersionRetention int `json:"version_retention"`
+}
+
+// TOTP holds the two-factor authentication state of a user.
+//
+// Fields:
+//   - UserID: The ID of the user.
+//   - Secret: The TOTP secret, sealed with the server key.
+//   - Enabled: Whether enrollment was confirmed with a first code.
+//   - LastStep: The time step of the last accepted code, so a code cannot be used twice.
+type TOTP struct {
+	UserID   int            `json:"user_id"`
+	Secret   *WrappedSecret `json:"secret"`
+	Enabled  bool           `json:"enabled"`
+	LastStep int64          `json:"last_step"`
+}
+
+// TwoFactorLimits bounds the guesses at a second factor.
+//
+// Fields:
+//   - TokenAttempts: The number of attempts allowed with one two-factor token.
+//   - UserAttempts: The number of failed attempts in a row after which a user is locked out.
+//   - Lockout: How long a user stays locked out.
+type TwoFactorLimits struct {
+	TokenAttempts int           `json:"token_attempts"`
+	UserAttempts  int           `json:"user_attempts"`
+	Lockout       time.Duration `json:"lockout"`
 }
@@ -3,24 +3,140 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// GenerateJWT creates a signed JWT token for the given user ID and username.
+// TwoFactorPendingClaim marks tokens issued after the password check of an account with
+// two-factor authentication. They only authorize the second factor, not the API.
+const TwoFactorPendingClaim = "2fa_pending"
+
+// twoFactorIDLength is the number of random bytes in the ID of a two-factor token.
+const twoFactorIDLength = 16
+
+// ErrInvalidToken is returned for tokens that fail validation.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the validated claims of an access token.
+type Claims struct {
+	// UserID is the authenticated user's ID.
+	UserID int
+	// Username is the authenticated user's login name.
+	Username string
+	// SessionID identifies the refresh-token family the token was issued for.
+	SessionID int
+}
+
+// GenerateJWT creates a signed access token for the given user and session.
 //
-// The token uses HS256 signing and includes user ID, username, and issued-at claims.
+// The token uses HS256 signing and includes user ID, username, session ID, issued-at and
+// expiration claims; it expires after ttl.
 //
 // Returns the signed JWT string or an error if signing fails.
-func GenerateJWT(userID int, username, jwtSecret string) (string, error) {
+func GenerateJWT(userID int, username string, sessionID int, jwtSecret string, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"name":    username,
-		"iat":     time.Now().Unix(),
+		"sid":     sessionID,
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	return token.SignedString([]byte(jwtSecret))
 }
+
+// ParseJWT validates an access token created by GenerateJWT.
+//
+// Returns the token claims or ErrInvalidToken if the token is malformed, expired, has no
+// session, or is a two-factor token.
+func ParseJWT(tokenStr, jwtSecret string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if pending, _ := claims[TwoFactorPendingClaim].(bool); pending {
+		return nil, ErrInvalidToken
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	sessionID, ok := claims["sid"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	username, _ := claims["name"].(string)
+
+	return &Claims{UserID: int(userID), Username: username, SessionID: int(sessionID)}, nil
+}
+
+// GenerateTwoFactorJWT creates a short-lived token proving that userID passed the password
+// check and may complete the login with a second factor.
+//
+// Each token gets a random ID, the jti claim, under which the server counts the attempts made
+// with it and marks it used, so a token completes at most one login.
+//
+// Returns the signed JWT string and its ID, or an error if signing fails.
+func GenerateTwoFactorJWT(userID int, jwtSecret string, ttl time.Duration) (string, string, error) {
+	raw := make([]byte, twoFactorIDLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	jti := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id":             userID,
+		"jti":                 jti,
+		TwoFactorPendingClaim: true,
+		"iat":                 now.Unix(),
+		"exp":                 now.Add(ttl).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	signed, err := token.SignedString([]byte(jwtSecret))
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
+}
+
+// ParseTwoFactorJWT validates a token created by GenerateTwoFactorJWT.
+//
+// Returns the user ID and the token ID, or ErrInvalidToken if the token is malformed, expired
+// or not a two-factor token.
+func ParseTwoFactorJWT(tokenStr, jwtSecret string) (int, string, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return 0, "", ErrInvalidToken
+	}
+
+	if pending, _ := claims[TwoFactorPendingClaim].(bool); !pending {
+		return 0, "", ErrInvalidToken
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", ErrInvalidToken
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return 0, "", ErrInvalidToken
+	}
+
+	return int(userID), jti, nil
+}
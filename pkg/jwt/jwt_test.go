@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
@@ -12,7 +13,7 @@ func TestGenerateJWT(t *testing.T) {
 	username := "testuser"
 	secret := "mysecret"
 
-	tokenStr, err := GenerateJWT(userID, username, secret)
+	tokenStr, err := GenerateJWT(userID, username, 7, secret, time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, tokenStr)
 
@@ -27,5 +28,78 @@ func TestGenerateJWT(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, float64(userID), claims["user_id"])
 	require.Equal(t, username, claims["name"])
+	require.Equal(t, float64(7), claims["sid"])
 	require.NotZero(t, claims["iat"])
+	require.NotZero(t, claims["exp"])
+}
+
+func TestParseJWT(t *testing.T) {
+	secret := "mysecret"
+
+	tokenStr, err := GenerateJWT(42, "testuser", 7, secret, time.Minute)
+	require.NoError(t, err)
+	claims, err := ParseJWT(tokenStr, secret)
+	require.NoError(t, err)
+	require.Equal(t, &Claims{UserID: 42, Username: "testuser", SessionID: 7}, claims)
+
+	_, err = ParseJWT(tokenStr, "othersecret")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expired, err := GenerateJWT(42, "testuser", 7, secret, -time.Minute)
+	require.NoError(t, err)
+	_, err = ParseJWT(expired, secret)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Tokens without an expiry, as issued by earlier versions, are no longer accepted.
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 42, "name": "testuser"}).
+		SignedString([]byte(secret))
+	require.NoError(t, err)
+	_, err = ParseJWT(legacy, secret)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	pending, _, err := GenerateTwoFactorJWT(42, secret, time.Minute)
+	require.NoError(t, err)
+	_, err = ParseJWT(pending, secret)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	token, hash, err := NewRefreshToken()
+	require.NoError(t, err)
+	require.Len(t, token, 43)
+	require.Equal(t, HashRefreshToken(token), hash)
+
+	other, _, err := NewRefreshToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+}
+
+func TestTwoFactorJWT(t *testing.T) {
+	secret := "mysecret"
+
+	tokenStr, jti, err := GenerateTwoFactorJWT(42, secret, time.Minute)
+	require.NoError(t, err)
+
+	userID, parsedJTI, err := ParseTwoFactorJWT(tokenStr, secret)
+	require.NoError(t, err)
+	require.Equal(t, 42, userID)
+	require.Equal(t, jti, parsedJTI)
+
+	_, other, err := GenerateTwoFactorJWT(42, secret, time.Minute)
+	require.NoError(t, err)
+	require.NotEqual(t, jti, other, "every token gets its own ID")
+
+	_, _, err = ParseTwoFactorJWT(tokenStr, "othersecret")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expired, _, err := GenerateTwoFactorJWT(42, secret, -time.Minute)
+	require.NoError(t, err)
+	_, _, err = ParseTwoFactorJWT(expired, secret)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// A regular session token is not a second-factor token.
+	session, err := GenerateJWT(42, "testuser", 7, secret, time.Minute)
+	require.NoError(t, err)
+	_, _, err = ParseTwoFactorJWT(session, secret)
+	require.ErrorIs(t, err, ErrInvalidToken)
 }
@@ -0,0 +1,29 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// refreshTokenLength is the number of random bytes in a refresh token.
+const refreshTokenLength = 32
+
+// NewRefreshToken creates a random opaque refresh token.
+//
+// Returns the token handed to the client and its hash, which is what the server stores.
+func NewRefreshToken() (string, []byte, error) {
+	raw := make([]byte, refreshTokenLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the SHA-256 hash under which a refresh token is stored.
+func HashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,87 @@
+// Package sshkey parses SSH private keys as stored in the vault and describes their public halves.
+//
+// Keys are accepted in the OpenSSH format written by ssh-keygen and in the PEM formats (PKCS#1,
+// PKCS#8, SEC 1) of older tools, optionally encrypted with a passphrase.
+package sshkey
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// ErrInvalidKey is returned for data that is not an SSH private key.
+	ErrInvalidKey = errors.New("invalid SSH private key")
+	// ErrPassphraseRequired is returned for an encrypted private key given without a passphrase.
+	ErrPassphraseRequired = errors.New("SSH private key is encrypted: passphrase required")
+	// ErrWrongPassphrase is returned when the passphrase does not decrypt the private key.
+	ErrWrongPassphrase = errors.New("wrong SSH key passphrase")
+	// ErrKeyMismatch is returned when a public key does not belong to the private key.
+	ErrKeyMismatch = errors.New("public key does not match the private key")
+)
+
+// Key describes the public half of a private key.
+type Key struct {
+	// PublicKey is the public key in the authorized_keys format, without a comment.
+	PublicKey string
+	// Comment is the comment of the public key given to Inspect, if any.
+	Comment string
+	// Fingerprint is the SHA256 fingerprint, as printed by ssh-keygen -l.
+	Fingerprint string
+}
+
+// Signer parses privateKey, decrypting it with passphrase when it is set.
+func Signer(privateKey, passphrase string) (ssh.Signer, error) {
+	var (
+		signer ssh.Signer
+		err    error
+	)
+	if passphrase == "" {
+		signer, err = ssh.ParsePrivateKey([]byte(privateKey))
+	} else {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	}
+
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case err == nil:
+		return signer, nil
+	case errors.As(err, &missing):
+		return nil, ErrPassphraseRequired
+	case errors.Is(err, x509.IncorrectPasswordError):
+		return nil, ErrWrongPassphrase
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+}
+
+// Inspect parses privateKey and returns its public key and fingerprint. When publicKey is set,
+// in the authorized_keys format, it must belong to privateKey; its comment is returned.
+func Inspect(privateKey, publicKey, passphrase string) (*Key, error) {
+	signer, err := Signer(privateKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	pub := signer.PublicKey()
+
+	key := &Key{
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+		Fingerprint: ssh.FingerprintSHA256(pub),
+	}
+	if strings.TrimSpace(publicKey) != "" {
+		given, comment, _, _, errParse := ssh.ParseAuthorizedKey([]byte(publicKey))
+		if errParse != nil {
+			return nil, fmt.Errorf("invalid SSH public key: %v", errParse)
+		}
+		if !bytes.Equal(given.Marshal(), pub.Marshal()) {
+			return nil, ErrKeyMismatch
+		}
+		key.Comment = comment
+	}
+	return key, nil
+}
@@ -0,0 +1,68 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newKey(t *testing.T, passphrase string) (string, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "deploy")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "deploy", []byte(passphrase))
+	}
+	require.NoError(t, err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(block)), sshPub
+}
+
+func TestInspect(t *testing.T) {
+	private, pub := newKey(t, "")
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+
+	key, err := Inspect(private, authorized+" ci@example.com\n", "")
+	require.NoError(t, err)
+	require.Equal(t, authorized, key.PublicKey)
+	require.Equal(t, "ci@example.com", key.Comment)
+	require.Equal(t, ssh.FingerprintSHA256(pub), key.Fingerprint)
+	require.True(t, strings.HasPrefix(key.Fingerprint, "SHA256:"))
+
+	other, _ := newKey(t, "")
+	otherKey, err := Inspect(other, "", "")
+	require.NoError(t, err)
+	_, err = Inspect(private, otherKey.PublicKey, "")
+	require.ErrorIs(t, err, ErrKeyMismatch)
+
+	_, err = Inspect("not a key", "", "")
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestSigner_Passphrase(t *testing.T) {
+	private, pub := newKey(t, "correct horse")
+
+	_, err := Signer(private, "")
+	require.ErrorIs(t, err, ErrPassphraseRequired)
+	_, err = Signer(private, "wrong")
+	require.ErrorIs(t, err, ErrWrongPassphrase)
+
+	signer, err := Signer(private, "correct horse")
+	require.NoError(t, err)
+	require.Equal(t, pub.Marshal(), signer.PublicKey().Marshal())
+
+	sig, err := signer.Sign(rand.Reader, []byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, pub.Verify([]byte("data"), sig))
+}
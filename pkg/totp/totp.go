@@ -0,0 +1,262 @@
+// Package totp implements time-based one-time passwords (RFC 6238) on top of HOTP (RFC 4226).
+//
+// Codes are compatible with common authenticator apps: secrets are base32 encoded without
+// padding and otpauth:// URIs describe them for QR enrollment.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is the RFC 4226 default and what authenticator apps expect.
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported HMAC algorithms.
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+)
+
+// secretLength is the length in bytes of generated secrets (160 bits, as recommended by RFC 4226).
+const secretLength = 20
+
+// ErrInvalidSecret is returned for secrets that are not valid base32.
+var ErrInvalidSecret = errors.New("invalid TOTP secret")
+
+// ErrInvalidURI is returned for strings that are not otpauth://totp/ URIs.
+var ErrInvalidURI = errors.New("invalid otpauth URI")
+
+// Opts are the code parameters. The zero value selects the defaults used by authenticator
+// apps: SHA1, 6 digits and a 30 second period.
+type Opts struct {
+	Algorithm string
+	Digits    int
+	Period    time.Duration
+}
+
+// withDefaults fills unset options.
+func (o Opts) withDefaults() Opts {
+	if o.Algorithm == "" {
+		o.Algorithm = AlgorithmSHA1
+	}
+	if o.Digits == 0 {
+		o.Digits = 6
+	}
+	if o.Period == 0 {
+		o.Period = 30 * time.Second
+	}
+	return o
+}
+
+// Check reports whether codes can be generated for secret with opts.
+func Check(secret string, opts Opts) error {
+	opts = opts.withDefaults()
+	if _, err := decodeSecret(secret); err != nil {
+		return err
+	}
+	switch opts.Algorithm {
+	case AlgorithmSHA1, AlgorithmSHA256, AlgorithmSHA512:
+	default:
+		return fmt.Errorf("unsupported TOTP algorithm %q", opts.Algorithm)
+	}
+	if opts.Digits < 6 || opts.Digits > 8 {
+		return fmt.Errorf("unsupported TOTP digits %d", opts.Digits)
+	}
+	if opts.Period < time.Second || opts.Period%time.Second != 0 {
+		return fmt.Errorf("unsupported TOTP period %s", opts.Period)
+	}
+	return nil
+}
+
+// GenerateSecret returns a new random base32-encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// Step returns the time step that t falls into.
+func Step(t time.Time, opts Opts) int64 {
+	return t.Unix() / int64(opts.withDefaults().Period/time.Second)
+}
+
+// Remaining returns how long the code of time t stays valid.
+func Remaining(t time.Time, opts Opts) time.Duration {
+	period := opts.withDefaults().Period
+	next := time.Unix((Step(t, opts)+1)*int64(period/time.Second), 0)
+	return next.Sub(t)
+}
+
+// Code returns the code for secret at time t.
+func Code(secret string, t time.Time, opts Opts) (string, error) {
+	return HOTP(secret, Step(t, opts), opts)
+}
+
+// HOTP returns the counter-based code for secret and counter (RFC 4226).
+func HOTP(secret string, counter int64, opts Opts) (string, error) {
+	opts = opts.withDefaults()
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	var newHash func() hash.Hash
+	switch opts.Algorithm {
+	case AlgorithmSHA1:
+		newHash = sha1.New
+	case AlgorithmSHA256:
+		newHash = sha256.New
+	case AlgorithmSHA512:
+		newHash = sha512.New
+	default:
+		return "", fmt.Errorf("unsupported TOTP algorithm %q", opts.Algorithm)
+	}
+	if opts.Digits < 6 || opts.Digits > 8 {
+		return "", fmt.Errorf("unsupported TOTP digits %d", opts.Digits)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range opts.Digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", opts.Digits, value%mod), nil
+}
+
+// Validate checks code against secret at time t, accepting skew steps of clock drift in each
+// direction. It returns the matching time step, so callers can reject a code used twice.
+func Validate(code, secret string, t time.Time, skew int, opts Opts) (int64, bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	step := Step(t, opts)
+
+	for i := -skew; i <= skew; i++ {
+		expected, err := HOTP(secret, step+int64(i), opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + int64(i), true
+		}
+	}
+
+	return 0, false
+}
+
+// URI returns the otpauth:// URI that enrolls secret in an authenticator app.
+func URI(issuer, account, secret string, opts Opts) string {
+	opts = opts.withDefaults()
+
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", opts.Algorithm)
+	q.Set("digits", strconv.Itoa(opts.Digits))
+	q.Set("period", strconv.Itoa(int(opts.Period/time.Second)))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// Key is a secret with its code parameters and labels, as described by an otpauth:// URI.
+type Key struct {
+	Issuer  string
+	Account string
+	Secret  string
+	Opts    Opts
+}
+
+// ParseURI parses an otpauth://totp/ URI, as encoded in the QR codes shown by services that
+// enroll an authenticator app. The issuer is taken from the issuer parameter or, failing that,
+// from the label prefix; missing code parameters keep their defaults.
+func ParseURI(uri string) (*Key, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil || !strings.EqualFold(u.Scheme, "otpauth") {
+		return nil, ErrInvalidURI
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("%w: only time-based codes are supported", ErrInvalidURI)
+	}
+
+	q := u.Query()
+	key := &Key{
+		Secret: strings.ToUpper(strings.ReplaceAll(q.Get("secret"), " ", "")),
+		Opts:   Opts{Algorithm: strings.ToUpper(q.Get("algorithm"))},
+	}
+	if key.Secret == "" {
+		return nil, fmt.Errorf("%w: no secret", ErrInvalidURI)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		key.Issuer, key.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+
+	if v := q.Get("digits"); v != "" {
+		if key.Opts.Digits, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("%w: invalid digits %q", ErrInvalidURI, v)
+		}
+	}
+	if v := q.Get("period"); v != "" {
+		seconds, errPeriod := strconv.Atoi(v)
+		if errPeriod != nil {
+			return nil, fmt.Errorf("%w: invalid period %q", ErrInvalidURI, v)
+		}
+		key.Opts.Period = time.Duration(seconds) * time.Second
+	}
+
+	key.Opts = key.Opts.withDefaults()
+	if err = Check(key.Secret, key.Opts); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// encoding is base32 without padding, as used in otpauth URIs.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// decodeSecret decodes a base32 secret, ignoring case, spaces and padding.
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
@@ -0,0 +1,114 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B test vectors use the ASCII seeds below with 8 digits.
+func TestCode_RFC6238(t *testing.T) {
+	enc := func(s string) string { return base32.StdEncoding.EncodeToString([]byte(s)) }
+	sha1Seed := enc("12345678901234567890")
+	sha256Seed := enc("12345678901234567890123456789012")
+	sha512Seed := enc("1234567890123456789012345678901234567890123456789012345678901234")
+
+	tests := []struct {
+		unix   int64
+		secret string
+		alg    string
+		want   string
+	}{
+		{59, sha1Seed, AlgorithmSHA1, "94287082"},
+		{59, sha256Seed, AlgorithmSHA256, "46119246"},
+		{59, sha512Seed, AlgorithmSHA512, "90693936"},
+		{1111111109, sha1Seed, AlgorithmSHA1, "07081804"},
+		{1234567890, sha256Seed, AlgorithmSHA256, "91819424"},
+		{20000000000, sha512Seed, AlgorithmSHA512, "47863826"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg+"/"+tt.want, func(t *testing.T) {
+			got, err := Code(tt.secret, time.Unix(tt.unix, 0), Opts{Algorithm: tt.alg, Digits: 8})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := Code(secret, now, Opts{})
+	require.NoError(t, err)
+	require.Len(t, code, 6)
+
+	step, ok := Validate(code, secret, now, 1, Opts{})
+	require.True(t, ok)
+	require.Equal(t, Step(now, Opts{}), step)
+
+	_, ok = Validate(code, secret, now.Add(30*time.Second), 1, Opts{})
+	require.True(t, ok, "previous step is accepted within skew")
+
+	_, ok = Validate(code, secret, now.Add(90*time.Second), 1, Opts{})
+	require.False(t, ok, "codes outside the skew window are rejected")
+
+	_, ok = Validate("000000", "not base32!", now, 1, Opts{})
+	require.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("GophKeeper", "alice", "JBSWY3DPEHPK3PXP", Opts{})
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/GophKeeper:alice", u.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+	require.Equal(t, "30", u.Query().Get("period"))
+}
+
+func TestParseURI(t *testing.T) {
+	key, err := ParseURI("otpauth://totp/ACME%20Co:john@example.com?secret=jbswy3dpehpk3pxp&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60")
+	require.NoError(t, err)
+	require.Equal(t, &Key{
+		Issuer:  "ACME Co",
+		Account: "john@example.com",
+		Secret:  "JBSWY3DPEHPK3PXP",
+		Opts:    Opts{Algorithm: AlgorithmSHA256, Digits: 8, Period: time.Minute},
+	}, key)
+
+	key, err = ParseURI("otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	require.Equal(t, "GitHub", key.Issuer)
+	require.Equal(t, "octocat", key.Account)
+	require.Equal(t, Opts{Algorithm: AlgorithmSHA1, Digits: 6, Period: 30 * time.Second}, key.Opts)
+
+	for _, uri := range []string{
+		"JBSWY3DPEHPK3PXP",
+		"https://example.com/?secret=JBSWY3DPEHPK3PXP",
+		"otpauth://hotp/x?secret=JBSWY3DPEHPK3PXP&counter=1",
+		"otpauth://totp/x",
+		"otpauth://totp/x?secret=not-base32!",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=4",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&period=-30",
+	} {
+		_, err = ParseURI(uri)
+		require.Error(t, err, uri)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	require.Equal(t, 10*time.Second, Remaining(time.Unix(1_700_000_000, 0), Opts{}))
+	require.Equal(t, 30*time.Second, Remaining(time.Unix(1_700_000_010, 0), Opts{}), "a step starts at 1700000010")
+	require.Equal(t, 9500*time.Millisecond, Remaining(time.Unix(1_700_000_000, 500_000_000), Opts{}))
+	require.Equal(t, 40*time.Second, Remaining(time.Unix(1_700_000_000, 0), Opts{Period: time.Minute}))
+}
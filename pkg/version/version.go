@@ -14,6 +14,14 @@ var buildTime = ""
 // commitHash is the git commit hash of the build.
 var commitHash = ""
 
+// Version returns the application version, or "dev" for builds without version information.
+func Version() string {
+	if version == "" {
+		return "dev"
+	}
+	return version
+}
+
 func PrintVersion() {
 	fmt.Printf("Version: %s\nBuild time: %s\nCommitHash: %s\n", version, buildTime, commitHash)
 }
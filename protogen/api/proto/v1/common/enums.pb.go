@@ -28,6 +28,9 @@ const (
 	DataType_DATA_TYPE_BANK_CARD   DataType = 1
 	DataType_DATA_TYPE_CREDENTIALS DataType = 2
 	DataType_DATA_TYPE_BINARY_DATA DataType = 3
+	DataType_DATA_TYPE_TEXT        DataType = 4
+	DataType_DATA_TYPE_OTP         DataType = 5
+	DataType_DATA_TYPE_SSH_KEY     DataType = 6
 )
 
 // Enum value maps for DataType.
@@ -37,12 +40,18 @@ var (
 		1: "DATA_TYPE_BANK_CARD",
 		2: "DATA_TYPE_CREDENTIALS",
 		3: "DATA_TYPE_BINARY_DATA",
+		4: "DATA_TYPE_TEXT",
+		5: "DATA_TYPE_OTP",
+		6: "DATA_TYPE_SSH_KEY",
 	}
 	DataType_value = map[string]int32{
 		"DATA_TYPE_UNSPECIFIED": 0,
 		"DATA_TYPE_BANK_CARD":   1,
 		"DATA_TYPE_CREDENTIALS": 2,
 		"DATA_TYPE_BINARY_DATA": 3,
+		"DATA_TYPE_TEXT":        4,
+		"DATA_TYPE_OTP":         5,
+		"DATA_TYPE_SSH_KEY":     6,
 	}
 )
 
@@ -73,16 +82,144 @@ func (DataType) EnumDescriptor() ([]byte, []int) {
 	return file_api_proto_v1_common_enums_proto_rawDescGZIP(), []int{0}
 }
 
+// CustomFieldType is the kind of value of a custom meta field; the value is always sent as a
+// string and validated against its type.
+type CustomFieldType int32
+
+const (
+	CustomFieldType_CUSTOM_FIELD_TYPE_TEXT CustomFieldType = 0
+	// A decimal number.
+	CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER CustomFieldType = 1
+	// "true" or "false".
+	CustomFieldType_CUSTOM_FIELD_TYPE_BOOLEAN CustomFieldType = 2
+	// A date as YYYY-MM-DD.
+	CustomFieldType_CUSTOM_FIELD_TYPE_DATE CustomFieldType = 3
+	// An absolute http or https URL.
+	CustomFieldType_CUSTOM_FIELD_TYPE_URL   CustomFieldType = 4
+	CustomFieldType_CUSTOM_FIELD_TYPE_EMAIL CustomFieldType = 5
+)
+
+// Enum value maps for CustomFieldType.
+var (
+	CustomFieldType_name = map[int32]string{
+		0: "CUSTOM_FIELD_TYPE_TEXT",
+		1: "CUSTOM_FIELD_TYPE_NUMBER",
+		2: "CUSTOM_FIELD_TYPE_BOOLEAN",
+		3: "CUSTOM_FIELD_TYPE_DATE",
+		4: "CUSTOM_FIELD_TYPE_URL",
+		5: "CUSTOM_FIELD_TYPE_EMAIL",
+	}
+	CustomFieldType_value = map[string]int32{
+		"CUSTOM_FIELD_TYPE_TEXT":    0,
+		"CUSTOM_FIELD_TYPE_NUMBER":  1,
+		"CUSTOM_FIELD_TYPE_BOOLEAN": 2,
+		"CUSTOM_FIELD_TYPE_DATE":    3,
+		"CUSTOM_FIELD_TYPE_URL":     4,
+		"CUSTOM_FIELD_TYPE_EMAIL":   5,
+	}
+)
+
+func (x CustomFieldType) Enum() *CustomFieldType {
+	p := new(CustomFieldType)
+	*p = x
+	return p
+}
+
+func (x CustomFieldType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CustomFieldType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_common_enums_proto_enumTypes[1].Descriptor()
+}
+
+func (CustomFieldType) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_common_enums_proto_enumTypes[1]
+}
+
+func (x CustomFieldType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CustomFieldType.Descriptor instead.
+func (CustomFieldType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_common_enums_proto_rawDescGZIP(), []int{1}
+}
+
+// OtpAlgorithm is the HMAC algorithm of one-time passwords; SHA1 is what most services use.
+type OtpAlgorithm int32
+
+const (
+	OtpAlgorithm_OTP_ALGORITHM_SHA1   OtpAlgorithm = 0
+	OtpAlgorithm_OTP_ALGORITHM_SHA256 OtpAlgorithm = 1
+	OtpAlgorithm_OTP_ALGORITHM_SHA512 OtpAlgorithm = 2
+)
+
+// Enum value maps for OtpAlgorithm.
+var (
+	OtpAlgorithm_name = map[int32]string{
+		0: "OTP_ALGORITHM_SHA1",
+		1: "OTP_ALGORITHM_SHA256",
+		2: "OTP_ALGORITHM_SHA512",
+	}
+	OtpAlgorithm_value = map[string]int32{
+		"OTP_ALGORITHM_SHA1":   0,
+		"OTP_ALGORITHM_SHA256": 1,
+		"OTP_ALGORITHM_SHA512": 2,
+	}
+)
+
+func (x OtpAlgorithm) Enum() *OtpAlgorithm {
+	p := new(OtpAlgorithm)
+	*p = x
+	return p
+}
+
+func (x OtpAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OtpAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_common_enums_proto_enumTypes[2].Descriptor()
+}
+
+func (OtpAlgorithm) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_common_enums_proto_enumTypes[2]
+}
+
+func (x OtpAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OtpAlgorithm.Descriptor instead.
+func (OtpAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_common_enums_proto_rawDescGZIP(), []int{2}
+}
+
 var File_api_proto_v1_common_enums_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_common_enums_proto_rawDesc = "" +
 	"\n" +
-	"\x1fapi/proto/v1/common/enums.proto\x12\x13api.proto.v1.common*t\n" +
+	"\x1fapi/proto/v1/common/enums.proto\x12\x13api.proto.v1.common*\xb2\x01\n" +
 	"\bDataType\x12\x19\n" +
 	"\x15DATA_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
 	"\x13DATA_TYPE_BANK_CARD\x10\x01\x12\x19\n" +
 	"\x15DATA_TYPE_CREDENTIALS\x10\x02\x12\x19\n" +
-	"\x15DATA_TYPE_BINARY_DATA\x10\x03B<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/commonb\x06proto3"
+	"\x15DATA_TYPE_BINARY_DATA\x10\x03\x12\x12\n" +
+	"\x0eDATA_TYPE_TEXT\x10\x04\x12\x11\n" +
+	"\rDATA_TYPE_OTP\x10\x05\x12\x15\n" +
+	"\x11DATA_TYPE_SSH_KEY\x10\x06*\xbe\x01\n" +
+	"\x0fCustomFieldType\x12\x1a\n" +
+	"\x16CUSTOM_FIELD_TYPE_TEXT\x10\x00\x12\x1c\n" +
+	"\x18CUSTOM_FIELD_TYPE_NUMBER\x10\x01\x12\x1d\n" +
+	"\x19CUSTOM_FIELD_TYPE_BOOLEAN\x10\x02\x12\x1a\n" +
+	"\x16CUSTOM_FIELD_TYPE_DATE\x10\x03\x12\x19\n" +
+	"\x15CUSTOM_FIELD_TYPE_URL\x10\x04\x12\x1b\n" +
+	"\x17CUSTOM_FIELD_TYPE_EMAIL\x10\x05*Z\n" +
+	"\fOtpAlgorithm\x12\x16\n" +
+	"\x12OTP_ALGORITHM_SHA1\x10\x00\x12\x18\n" +
+	"\x14OTP_ALGORITHM_SHA256\x10\x01\x12\x18\n" +
+	"\x14OTP_ALGORITHM_SHA512\x10\x02B<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/commonb\x06proto3"
 
 var (
 	file_api_proto_v1_common_enums_proto_rawDescOnce sync.Once
@@ -96,9 +233,11 @@ func file_api_proto_v1_common_enums_proto_rawDescGZIP() []byte {
 	return file_api_proto_v1_common_enums_proto_rawDescData
 }
 
-var file_api_proto_v1_common_enums_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_proto_v1_common_enums_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
 var file_api_proto_v1_common_enums_proto_goTypes = []any{
-	(DataType)(0), // 0: api.proto.v1.common.DataType
+	(DataType)(0),        // 0: api.proto.v1.common.DataType
+	(CustomFieldType)(0), // 1: api.proto.v1.common.CustomFieldType
+	(OtpAlgorithm)(0),    // 2: api.proto.v1.common.OtpAlgorithm
 }
 var file_api_proto_v1_common_enums_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -118,7 +257,7 @@ func file_api_proto_v1_common_enums_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_common_enums_proto_rawDesc), len(file_api_proto_v1_common_enums_proto_rawDesc)),
-			NumEnums:      1,
+			NumEnums:      3,
 			NumMessages:   0,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/models/encrypted_payload.proto
+
+package models
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EncryptedPayload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EncryptedData []byte                 `protobuf:"bytes,1,opt,name=encrypted_data,json=encryptedData,proto3" json:"encrypted_data,omitempty"`
+	DataNonce     []byte                 `protobuf:"bytes,2,opt,name=data_nonce,json=dataNonce,proto3" json:"data_nonce,omitempty"`
+	EncryptedDek  []byte                 `protobuf:"bytes,3,opt,name=encrypted_dek,json=encryptedDek,proto3" json:"encrypted_dek,omitempty"`
+	DekNonce      []byte                 `protobuf:"bytes,4,opt,name=dek_nonce,json=dekNonce,proto3" json:"dek_nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptedPayload) Reset() {
+	*x = EncryptedPayload{}
+	mi := &file_api_proto_v1_models_encrypted_payload_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptedPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptedPayload) ProtoMessage() {}
+
+func (x *EncryptedPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_models_encrypted_payload_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptedPayload.ProtoReflect.Descriptor instead.
+func (*EncryptedPayload) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_models_encrypted_payload_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EncryptedPayload) GetEncryptedData() []byte {
+	if x != nil {
+		return x.EncryptedData
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetDataNonce() []byte {
+	if x != nil {
+		return x.DataNonce
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetEncryptedDek() []byte {
+	if x != nil {
+		return x.EncryptedDek
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetDekNonce() []byte {
+	if x != nil {
+		return x.DekNonce
+	}
+	return nil
+}
+
+var File_api_proto_v1_models_encrypted_payload_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_models_encrypted_payload_proto_rawDesc = "" +
+	"\n" +
+	"+api/proto/v1/models/encrypted_payload.proto\x12\x13api.proto.v1.models\"\x9a\x01\n" +
+	"\x10EncryptedPayload\x12%\n" +
+	"\x0eencrypted_data\x18\x01 \x01(\fR\rencryptedData\x12\x1d\n" +
+	"\n" +
+	"data_nonce\x18\x02 \x01(\fR\tdataNonce\x12#\n" +
+	"\rencrypted_dek\x18\x03 \x01(\fR\fencryptedDek\x12\x1b\n" +
+	"\tdek_nonce\x18\x04 \x01(\fR\bdekNonceB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+
+var (
+	file_api_proto_v1_models_encrypted_payload_proto_rawDescOnce sync.Once
+	file_api_proto_v1_models_encrypted_payload_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_models_encrypted_payload_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_models_encrypted_payload_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_models_encrypted_payload_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_encrypted_payload_proto_rawDesc), len(file_api_proto_v1_models_encrypted_payload_proto_rawDesc)))
+	})
+	return file_api_proto_v1_models_encrypted_payload_proto_rawDescData
+}
+
+var file_api_proto_v1_models_encrypted_payload_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_proto_v1_models_encrypted_payload_proto_goTypes = []any{
+	(*EncryptedPayload)(nil), // 0: api.proto.v1.models.EncryptedPayload
+}
+var file_api_proto_v1_models_encrypted_payload_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_models_encrypted_payload_proto_init() }
+func file_api_proto_v1_models_encrypted_payload_proto_init() {
+	if File_api_proto_v1_models_encrypted_payload_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_encrypted_payload_proto_rawDesc), len(file_api_proto_v1_models_encrypted_payload_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_models_encrypted_payload_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_models_encrypted_payload_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_models_encrypted_payload_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_models_encrypted_payload_proto = out.File
+	file_api_proto_v1_models_encrypted_payload_proto_goTypes = nil
+	file_api_proto_v1_models_encrypted_payload_proto_depIdxs = nil
+}
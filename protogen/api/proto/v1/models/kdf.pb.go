@@ -0,0 +1,158 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/models/kdf.proto
+
+package models
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type KdfParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Salt          []byte                 `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Time          uint32                 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Memory        uint32                 `protobuf:"varint,4,opt,name=memory,proto3" json:"memory,omitempty"`
+	Threads       uint32                 `protobuf:"varint,5,opt,name=threads,proto3" json:"threads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KdfParams) Reset() {
+	*x = KdfParams{}
+	mi := &file_api_proto_v1_models_kdf_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KdfParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KdfParams) ProtoMessage() {}
+
+func (x *KdfParams) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_models_kdf_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KdfParams.ProtoReflect.Descriptor instead.
+func (*KdfParams) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_models_kdf_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KdfParams) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *KdfParams) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *KdfParams) GetTime() uint32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *KdfParams) GetMemory() uint32 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *KdfParams) GetThreads() uint32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+var File_api_proto_v1_models_kdf_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_models_kdf_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapi/proto/v1/models/kdf.proto\x12\x13api.proto.v1.models\"\x83\x01\n" +
+	"\tKdfParams\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\fR\x04salt\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\rR\x04time\x12\x16\n" +
+	"\x06memory\x18\x04 \x01(\rR\x06memory\x12\x18\n" +
+	"\athreads\x18\x05 \x01(\rR\athreadsB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+
+var (
+	file_api_proto_v1_models_kdf_proto_rawDescOnce sync.Once
+	file_api_proto_v1_models_kdf_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_models_kdf_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_models_kdf_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_models_kdf_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_kdf_proto_rawDesc), len(file_api_proto_v1_models_kdf_proto_rawDesc)))
+	})
+	return file_api_proto_v1_models_kdf_proto_rawDescData
+}
+
+var file_api_proto_v1_models_kdf_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_proto_v1_models_kdf_proto_goTypes = []any{
+	(*KdfParams)(nil), // 0: api.proto.v1.models.KdfParams
+}
+var file_api_proto_v1_models_kdf_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_models_kdf_proto_init() }
+func file_api_proto_v1_models_kdf_proto_init() {
+	if File_api_proto_v1_models_kdf_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_kdf_proto_rawDesc), len(file_api_proto_v1_models_kdf_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_models_kdf_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_models_kdf_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_models_kdf_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_models_kdf_proto = out.File
+	file_api_proto_v1_models_kdf_proto_goTypes = nil
+	file_api_proto_v1_models_kdf_proto_depIdxs = nil
+}
@@ -7,6 +7,7 @@
 package models
 
 import (
+	common "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,16 +22,98 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// CustomField is a named value attached to a record.
+type CustomField struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type  common.CustomFieldType `protobuf:"varint,2,opt,name=type,proto3,enum=api.proto.v1.common.CustomFieldType" json:"type,omitempty"`
+	Value string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// The value of a sensitive field is encrypted with the record instead of being stored in
+	// the searchable meta. It is returned by DataView and ViewRecordVersion only; elsewhere the
+	// field is listed with an empty value.
+	Sensitive     bool `protobuf:"varint,4,opt,name=sensitive,proto3" json:"sensitive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomField) Reset() {
+	*x = CustomField{}
+	mi := &file_api_proto_v1_models_meta_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomField) ProtoMessage() {}
+
+func (x *CustomField) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_models_meta_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomField.ProtoReflect.Descriptor instead.
+func (*CustomField) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_models_meta_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CustomField) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CustomField) GetType() common.CustomFieldType {
+	if x != nil {
+		return x.Type
+	}
+	return common.CustomFieldType(0)
+}
+
+func (x *CustomField) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *CustomField) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+// Meta describes a record. It is stored unencrypted, apart from sensitive custom fields, so
+// records can be listed and searched without decrypting them.
 type Meta struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// content is a free-form note.
+	Content string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Title   string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// tags are stored in lower case without duplicates.
+	Tags     []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	Favorite bool     `protobuf:"varint,4,opt,name=favorite,proto3" json:"favorite,omitempty"`
+	// urls are absolute http or https URLs the record belongs to.
+	Urls          []string       `protobuf:"bytes,5,rep,name=urls,proto3" json:"urls,omitempty"`
+	Fields        []*CustomField `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Meta) Reset() {
 	*x = Meta{}
-	mi := &file_api_proto_v1_models_meta_proto_msgTypes[0]
+	mi := &file_api_proto_v1_models_meta_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -42,7 +125,7 @@ func (x *Meta) String() string {
 func (*Meta) ProtoMessage() {}
 
 func (x *Meta) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_models_meta_proto_msgTypes[0]
+	mi := &file_api_proto_v1_models_meta_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,7 +138,7 @@ func (x *Meta) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Meta.ProtoReflect.Descriptor instead.
 func (*Meta) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_models_meta_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_v1_models_meta_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Meta) GetContent() string {
@@ -65,13 +148,58 @@ func (x *Meta) GetContent() string {
 	return ""
 }
 
+func (x *Meta) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Meta) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Meta) GetFavorite() bool {
+	if x != nil {
+		return x.Favorite
+	}
+	return false
+}
+
+func (x *Meta) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *Meta) GetFields() []*CustomField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
 var File_api_proto_v1_models_meta_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_models_meta_proto_rawDesc = "" +
 	"\n" +
-	"\x1eapi/proto/v1/models/meta.proto\x12\x13api.proto.v1.models\" \n" +
+	"\x1eapi/proto/v1/models/meta.proto\x12\x13api.proto.v1.models\x1a\x1fapi/proto/v1/common/enums.proto\"\x8f\x01\n" +
+	"\vCustomField\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\x04type\x18\x02 \x01(\x0e2$.api.proto.v1.common.CustomFieldTypeR\x04type\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1c\n" +
+	"\tsensitive\x18\x04 \x01(\bR\tsensitive\"\xb4\x01\n" +
 	"\x04Meta\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\tR\acontentB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+	"\acontent\x18\x01 \x01(\tR\acontent\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x1a\n" +
+	"\bfavorite\x18\x04 \x01(\bR\bfavorite\x12\x12\n" +
+	"\x04urls\x18\x05 \x03(\tR\x04urls\x128\n" +
+	"\x06fields\x18\x06 \x03(\v2 .api.proto.v1.models.CustomFieldR\x06fieldsB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
 
 var (
 	file_api_proto_v1_models_meta_proto_rawDescOnce sync.Once
@@ -85,16 +213,20 @@ func file_api_proto_v1_models_meta_proto_rawDescGZIP() []byte {
 	return file_api_proto_v1_models_meta_proto_rawDescData
 }
 
-var file_api_proto_v1_models_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_proto_v1_models_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_proto_v1_models_meta_proto_goTypes = []any{
-	(*Meta)(nil), // 0: api.proto.v1.models.Meta
+	(*CustomField)(nil),         // 0: api.proto.v1.models.CustomField
+	(*Meta)(nil),                // 1: api.proto.v1.models.Meta
+	(common.CustomFieldType)(0), // 2: api.proto.v1.common.CustomFieldType
 }
 var file_api_proto_v1_models_meta_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: api.proto.v1.models.CustomField.type:type_name -> api.proto.v1.common.CustomFieldType
+	0, // 1: api.proto.v1.models.Meta.fields:type_name -> api.proto.v1.models.CustomField
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_models_meta_proto_init() }
@@ -108,7 +240,7 @@ func file_api_proto_v1_models_meta_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_meta_proto_rawDesc), len(file_api_proto_v1_models_meta_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
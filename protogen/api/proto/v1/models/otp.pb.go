@@ -0,0 +1,185 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/models/otp.proto
+
+package models
+
+import (
+	common "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Otp is the seed of a time-based one-time password (RFC 6238) with its code parameters.
+type Otp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// secret is the base32-encoded seed.
+	Secret    string              `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	Algorithm common.OtpAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=api.proto.v1.common.OtpAlgorithm" json:"algorithm,omitempty"`
+	// digits is the length of the codes, 6 when not set; 6 to 8 digits are supported.
+	Digits int32 `protobuf:"varint,3,opt,name=digits,proto3" json:"digits,omitempty"`
+	// period is how long a code is valid in seconds, 30 when not set.
+	Period  int32  `protobuf:"varint,4,opt,name=period,proto3" json:"period,omitempty"`
+	Issuer  string `protobuf:"bytes,5,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Account string `protobuf:"bytes,6,opt,name=account,proto3" json:"account,omitempty"`
+	// uri is an otpauth://totp/ URI, such as the one read from an enrollment QR code. When it is
+	// set on save, the other fields are taken from it.
+	Uri           string `protobuf:"bytes,7,opt,name=uri,proto3" json:"uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Otp) Reset() {
+	*x = Otp{}
+	mi := &file_api_proto_v1_models_otp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otp) ProtoMessage() {}
+
+func (x *Otp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_models_otp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Otp.ProtoReflect.Descriptor instead.
+func (*Otp) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_models_otp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Otp) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Otp) GetAlgorithm() common.OtpAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return common.OtpAlgorithm(0)
+}
+
+func (x *Otp) GetDigits() int32 {
+	if x != nil {
+		return x.Digits
+	}
+	return 0
+}
+
+func (x *Otp) GetPeriod() int32 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *Otp) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Otp) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Otp) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+var File_api_proto_v1_models_otp_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_models_otp_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapi/proto/v1/models/otp.proto\x12\x13api.proto.v1.models\x1a\x1fapi/proto/v1/common/enums.proto\"\xd2\x01\n" +
+	"\x03Otp\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12?\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2!.api.proto.v1.common.OtpAlgorithmR\talgorithm\x12\x16\n" +
+	"\x06digits\x18\x03 \x01(\x05R\x06digits\x12\x16\n" +
+	"\x06period\x18\x04 \x01(\x05R\x06period\x12\x16\n" +
+	"\x06issuer\x18\x05 \x01(\tR\x06issuer\x12\x18\n" +
+	"\aaccount\x18\x06 \x01(\tR\aaccount\x12\x10\n" +
+	"\x03uri\x18\a \x01(\tR\x03uriB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+
+var (
+	file_api_proto_v1_models_otp_proto_rawDescOnce sync.Once
+	file_api_proto_v1_models_otp_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_models_otp_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_models_otp_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_models_otp_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_otp_proto_rawDesc), len(file_api_proto_v1_models_otp_proto_rawDesc)))
+	})
+	return file_api_proto_v1_models_otp_proto_rawDescData
+}
+
+var file_api_proto_v1_models_otp_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_proto_v1_models_otp_proto_goTypes = []any{
+	(*Otp)(nil),              // 0: api.proto.v1.models.Otp
+	(common.OtpAlgorithm)(0), // 1: api.proto.v1.common.OtpAlgorithm
+}
+var file_api_proto_v1_models_otp_proto_depIdxs = []int32{
+	1, // 0: api.proto.v1.models.Otp.algorithm:type_name -> api.proto.v1.common.OtpAlgorithm
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_models_otp_proto_init() }
+func file_api_proto_v1_models_otp_proto_init() {
+	if File_api_proto_v1_models_otp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_otp_proto_rawDesc), len(file_api_proto_v1_models_otp_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_models_otp_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_models_otp_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_models_otp_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_models_otp_proto = out.File
+	file_api_proto_v1_models_otp_proto_goTypes = nil
+	file_api_proto_v1_models_otp_proto_depIdxs = nil
+}
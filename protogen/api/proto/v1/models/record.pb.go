@@ -22,11 +22,17 @@ const (
 )
 
 type Record struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	Meta          *Meta                  `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type      string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Meta      *Meta                  `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// folder_id is the folder the record is in, 0 outside any folder.
+	FolderId int32 `protobuf:"varint,6,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	// version is the version number of the current contents, raised by every update of the
+	// record. Unlike updated_at, which is shown to the minute, it tells every change apart.
+	Version       int32 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -89,17 +95,42 @@ func (x *Record) GetCreatedAt() string {
 	return ""
 }
 
+func (x *Record) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Record) GetFolderId() int32 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *Record) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 var File_api_proto_v1_models_record_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_models_record_proto_rawDesc = "" +
 	"\n" +
-	" api/proto/v1/models/record.proto\x12\x13api.proto.v1.models\x1a\x1eapi/proto/v1/models/meta.proto\"z\n" +
+	" api/proto/v1/models/record.proto\x12\x13api.proto.v1.models\x1a\x1eapi/proto/v1/models/meta.proto\"\xd0\x01\n" +
 	"\x06Record\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12-\n" +
 	"\x04meta\x18\x03 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\tR\tcreatedAtB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x12\x1b\n" +
+	"\tfolder_id\x18\x06 \x01(\x05R\bfolderId\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversionB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
 
 var (
 	file_api_proto_v1_models_record_proto_rawDescOnce sync.Once
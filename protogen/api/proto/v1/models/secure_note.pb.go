@@ -0,0 +1,124 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/models/secure_note.proto
+
+package models
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SecureNote is encrypted free text, such as a recovery phrase or a Markdown runbook.
+type SecureNote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecureNote) Reset() {
+	*x = SecureNote{}
+	mi := &file_api_proto_v1_models_secure_note_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecureNote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecureNote) ProtoMessage() {}
+
+func (x *SecureNote) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_models_secure_note_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecureNote.ProtoReflect.Descriptor instead.
+func (*SecureNote) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_models_secure_note_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SecureNote) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+var File_api_proto_v1_models_secure_note_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_models_secure_note_proto_rawDesc = "" +
+	"\n" +
+	"%api/proto/v1/models/secure_note.proto\x12\x13api.proto.v1.models\" \n" +
+	"\n" +
+	"SecureNote\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04textB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+
+var (
+	file_api_proto_v1_models_secure_note_proto_rawDescOnce sync.Once
+	file_api_proto_v1_models_secure_note_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_models_secure_note_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_models_secure_note_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_models_secure_note_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_secure_note_proto_rawDesc), len(file_api_proto_v1_models_secure_note_proto_rawDesc)))
+	})
+	return file_api_proto_v1_models_secure_note_proto_rawDescData
+}
+
+var file_api_proto_v1_models_secure_note_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_proto_v1_models_secure_note_proto_goTypes = []any{
+	(*SecureNote)(nil), // 0: api.proto.v1.models.SecureNote
+}
+var file_api_proto_v1_models_secure_note_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_models_secure_note_proto_init() }
+func file_api_proto_v1_models_secure_note_proto_init() {
+	if File_api_proto_v1_models_secure_note_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_secure_note_proto_rawDesc), len(file_api_proto_v1_models_secure_note_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_models_secure_note_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_models_secure_note_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_models_secure_note_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_models_secure_note_proto = out.File
+	file_api_proto_v1_models_secure_note_proto_goTypes = nil
+	file_api_proto_v1_models_secure_note_proto_depIdxs = nil
+}
@@ -0,0 +1,168 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/models/ssh_key.proto
+
+package models
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SshKey is an SSH key pair, such as a deploy key.
+type SshKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// private_key is the private key in the OpenSSH or PEM format, as written by ssh-keygen.
+	PrivateKey string `protobuf:"bytes,1,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	// public_key is the public key in the authorized_keys format; it is derived from the private
+	// key on save when not set.
+	PublicKey string `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Comment   string `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	// passphrase decrypts private_key when it is encrypted.
+	Passphrase string `protobuf:"bytes,4,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	// fingerprint is the SHA256 fingerprint of the key, computed on save.
+	Fingerprint   string `protobuf:"bytes,5,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SshKey) Reset() {
+	*x = SshKey{}
+	mi := &file_api_proto_v1_models_ssh_key_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SshKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SshKey) ProtoMessage() {}
+
+func (x *SshKey) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_models_ssh_key_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SshKey.ProtoReflect.Descriptor instead.
+func (*SshKey) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_models_ssh_key_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SshKey) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+func (x *SshKey) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SshKey) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *SshKey) GetPassphrase() string {
+	if x != nil {
+		return x.Passphrase
+	}
+	return ""
+}
+
+func (x *SshKey) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+var File_api_proto_v1_models_ssh_key_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_models_ssh_key_proto_rawDesc = "" +
+	"\n" +
+	"!api/proto/v1/models/ssh_key.proto\x12\x13api.proto.v1.models\"\xa4\x01\n" +
+	"\x06SshKey\x12\x1f\n" +
+	"\vprivate_key\x18\x01 \x01(\tR\n" +
+	"privateKey\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\tR\tpublicKey\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x12\x1e\n" +
+	"\n" +
+	"passphrase\x18\x04 \x01(\tR\n" +
+	"passphrase\x12 \n" +
+	"\vfingerprint\x18\x05 \x01(\tR\vfingerprintB<Z:github.com/apetsko/gophkeeper/protogen/api/proto/v1/modelsb\x06proto3"
+
+var (
+	file_api_proto_v1_models_ssh_key_proto_rawDescOnce sync.Once
+	file_api_proto_v1_models_ssh_key_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_models_ssh_key_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_models_ssh_key_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_models_ssh_key_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_ssh_key_proto_rawDesc), len(file_api_proto_v1_models_ssh_key_proto_rawDesc)))
+	})
+	return file_api_proto_v1_models_ssh_key_proto_rawDescData
+}
+
+var file_api_proto_v1_models_ssh_key_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_proto_v1_models_ssh_key_proto_goTypes = []any{
+	(*SshKey)(nil), // 0: api.proto.v1.models.SshKey
+}
+var file_api_proto_v1_models_ssh_key_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_models_ssh_key_proto_init() }
+func file_api_proto_v1_models_ssh_key_proto_init() {
+	if File_api_proto_v1_models_ssh_key_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_models_ssh_key_proto_rawDesc), len(file_api_proto_v1_models_ssh_key_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_models_ssh_key_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_models_ssh_key_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_models_ssh_key_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_models_ssh_key_proto = out.File
+	file_api_proto_v1_models_ssh_key_proto_goTypes = nil
+	file_api_proto_v1_models_ssh_key_proto_depIdxs = nil
+}
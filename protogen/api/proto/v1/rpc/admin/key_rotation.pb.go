@@ -0,0 +1,359 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/admin/key_rotation.proto
+
+package admin
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type KeyRotationJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TargetKeyId   string                 `protobuf:"bytes,2,opt,name=target_key_id,json=targetKeyId,proto3" json:"target_key_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Rewrapped     int32                  `protobuf:"varint,4,opt,name=rewrapped,proto3" json:"rewrapped,omitempty"`
+	Remaining     int32                  `protobuf:"varint,5,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAt     string                 `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyRotationJob) Reset() {
+	*x = KeyRotationJob{}
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyRotationJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRotationJob) ProtoMessage() {}
+
+func (x *KeyRotationJob) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRotationJob.ProtoReflect.Descriptor instead.
+func (*KeyRotationJob) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KeyRotationJob) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *KeyRotationJob) GetTargetKeyId() string {
+	if x != nil {
+		return x.TargetKeyId
+	}
+	return ""
+}
+
+func (x *KeyRotationJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *KeyRotationJob) GetRewrapped() int32 {
+	if x != nil {
+		return x.Rewrapped
+	}
+	return 0
+}
+
+func (x *KeyRotationJob) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *KeyRotationJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *KeyRotationJob) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *KeyRotationJob) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type StartKeyRotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartKeyRotationRequest) Reset() {
+	*x = StartKeyRotationRequest{}
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartKeyRotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartKeyRotationRequest) ProtoMessage() {}
+
+func (x *StartKeyRotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartKeyRotationRequest.ProtoReflect.Descriptor instead.
+func (*StartKeyRotationRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescGZIP(), []int{1}
+}
+
+type StartKeyRotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *KeyRotationJob        `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartKeyRotationResponse) Reset() {
+	*x = StartKeyRotationResponse{}
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartKeyRotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartKeyRotationResponse) ProtoMessage() {}
+
+func (x *StartKeyRotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartKeyRotationResponse.ProtoReflect.Descriptor instead.
+func (*StartKeyRotationResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StartKeyRotationResponse) GetJob() *KeyRotationJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetKeyRotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyRotationRequest) Reset() {
+	*x = GetKeyRotationRequest{}
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyRotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyRotationRequest) ProtoMessage() {}
+
+func (x *GetKeyRotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyRotationRequest.ProtoReflect.Descriptor instead.
+func (*GetKeyRotationRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescGZIP(), []int{3}
+}
+
+type GetKeyRotationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *KeyRotationJob        `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyRotationResponse) Reset() {
+	*x = GetKeyRotationResponse{}
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyRotationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyRotationResponse) ProtoMessage() {}
+
+func (x *GetKeyRotationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyRotationResponse.ProtoReflect.Descriptor instead.
+func (*GetKeyRotationResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetKeyRotationResponse) GetJob() *KeyRotationJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+var File_api_proto_v1_rpc_admin_key_rotation_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_admin_key_rotation_proto_rawDesc = "" +
+	"\n" +
+	")api/proto/v1/rpc/admin/key_rotation.proto\x12\x16api.proto.v1.rpc.admin\"\xec\x01\n" +
+	"\x0eKeyRotationJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\"\n" +
+	"\rtarget_key_id\x18\x02 \x01(\tR\vtargetKeyId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1c\n" +
+	"\trewrapped\x18\x04 \x01(\x05R\trewrapped\x12\x1c\n" +
+	"\tremaining\x18\x05 \x01(\x05R\tremaining\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\a \x01(\tR\tstartedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\"\x19\n" +
+	"\x17StartKeyRotationRequest\"T\n" +
+	"\x18StartKeyRotationResponse\x128\n" +
+	"\x03job\x18\x01 \x01(\v2&.api.proto.v1.rpc.admin.KeyRotationJobR\x03job\"\x17\n" +
+	"\x15GetKeyRotationRequest\"R\n" +
+	"\x16GetKeyRotationResponse\x128\n" +
+	"\x03job\x18\x01 \x01(\v2&.api.proto.v1.rpc.admin.KeyRotationJobR\x03jobB?Z=github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/adminb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_admin_key_rotation_proto_rawDesc), len(file_api_proto_v1_rpc_admin_key_rotation_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_admin_key_rotation_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_proto_v1_rpc_admin_key_rotation_proto_goTypes = []any{
+	(*KeyRotationJob)(nil),           // 0: api.proto.v1.rpc.admin.KeyRotationJob
+	(*StartKeyRotationRequest)(nil),  // 1: api.proto.v1.rpc.admin.StartKeyRotationRequest
+	(*StartKeyRotationResponse)(nil), // 2: api.proto.v1.rpc.admin.StartKeyRotationResponse
+	(*GetKeyRotationRequest)(nil),    // 3: api.proto.v1.rpc.admin.GetKeyRotationRequest
+	(*GetKeyRotationResponse)(nil),   // 4: api.proto.v1.rpc.admin.GetKeyRotationResponse
+}
+var file_api_proto_v1_rpc_admin_key_rotation_proto_depIdxs = []int32{
+	0, // 0: api.proto.v1.rpc.admin.StartKeyRotationResponse.job:type_name -> api.proto.v1.rpc.admin.KeyRotationJob
+	0, // 1: api.proto.v1.rpc.admin.GetKeyRotationResponse.job:type_name -> api.proto.v1.rpc.admin.KeyRotationJob
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_admin_key_rotation_proto_init() }
+func file_api_proto_v1_rpc_admin_key_rotation_proto_init() {
+	if File_api_proto_v1_rpc_admin_key_rotation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_admin_key_rotation_proto_rawDesc), len(file_api_proto_v1_rpc_admin_key_rotation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_admin_key_rotation_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_admin_key_rotation_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_admin_key_rotation_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_admin_key_rotation_proto = out.File
+	file_api_proto_v1_rpc_admin_key_rotation_proto_goTypes = nil
+	file_api_proto_v1_rpc_admin_key_rotation_proto_depIdxs = nil
+}
@@ -0,0 +1,340 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/admin/reconcile.proto
+
+package admin
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OrphanObject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	LastModified  string                 `protobuf:"bytes,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrphanObject) Reset() {
+	*x = OrphanObject{}
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrphanObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrphanObject) ProtoMessage() {}
+
+func (x *OrphanObject) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrphanObject.ProtoReflect.Descriptor instead.
+func (*OrphanObject) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_reconcile_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrphanObject) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrphanObject) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *OrphanObject) GetLastModified() string {
+	if x != nil {
+		return x.LastModified
+	}
+	return ""
+}
+
+type MissingObject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordId      int32                  `protobuf:"varint,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	ObjectName    string                 `protobuf:"bytes,3,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MissingObject) Reset() {
+	*x = MissingObject{}
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MissingObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MissingObject) ProtoMessage() {}
+
+func (x *MissingObject) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MissingObject.ProtoReflect.Descriptor instead.
+func (*MissingObject) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_reconcile_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MissingObject) GetRecordId() int32 {
+	if x != nil {
+		return x.RecordId
+	}
+	return 0
+}
+
+func (x *MissingObject) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *MissingObject) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+type ReconcileObjectsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Repair        bool                   `protobuf:"varint,1,opt,name=repair,proto3" json:"repair,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileObjectsRequest) Reset() {
+	*x = ReconcileObjectsRequest{}
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileObjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileObjectsRequest) ProtoMessage() {}
+
+func (x *ReconcileObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileObjectsRequest.ProtoReflect.Descriptor instead.
+func (*ReconcileObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_reconcile_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReconcileObjectsRequest) GetRepair() bool {
+	if x != nil {
+		return x.Repair
+	}
+	return false
+}
+
+type ReconcileObjectsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Objects       int32                  `protobuf:"varint,1,opt,name=objects,proto3" json:"objects,omitempty"`
+	References    int32                  `protobuf:"varint,2,opt,name=references,proto3" json:"references,omitempty"`
+	Orphans       []*OrphanObject        `protobuf:"bytes,3,rep,name=orphans,proto3" json:"orphans,omitempty"`
+	Missing       []*MissingObject       `protobuf:"bytes,4,rep,name=missing,proto3" json:"missing,omitempty"`
+	Removed       int32                  `protobuf:"varint,5,opt,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileObjectsResponse) Reset() {
+	*x = ReconcileObjectsResponse{}
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileObjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileObjectsResponse) ProtoMessage() {}
+
+func (x *ReconcileObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileObjectsResponse.ProtoReflect.Descriptor instead.
+func (*ReconcileObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_admin_reconcile_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReconcileObjectsResponse) GetObjects() int32 {
+	if x != nil {
+		return x.Objects
+	}
+	return 0
+}
+
+func (x *ReconcileObjectsResponse) GetReferences() int32 {
+	if x != nil {
+		return x.References
+	}
+	return 0
+}
+
+func (x *ReconcileObjectsResponse) GetOrphans() []*OrphanObject {
+	if x != nil {
+		return x.Orphans
+	}
+	return nil
+}
+
+func (x *ReconcileObjectsResponse) GetMissing() []*MissingObject {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+func (x *ReconcileObjectsResponse) GetRemoved() int32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+var File_api_proto_v1_rpc_admin_reconcile_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_admin_reconcile_proto_rawDesc = "" +
+	"\n" +
+	"&api/proto/v1/rpc/admin/reconcile.proto\x12\x16api.proto.v1.rpc.admin\"[\n" +
+	"\fOrphanObject\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12#\n" +
+	"\rlast_modified\x18\x03 \x01(\tR\flastModified\"g\n" +
+	"\rMissingObject\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\x05R\brecordId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x1f\n" +
+	"\vobject_name\x18\x03 \x01(\tR\n" +
+	"objectName\"1\n" +
+	"\x17ReconcileObjectsRequest\x12\x16\n" +
+	"\x06repair\x18\x01 \x01(\bR\x06repair\"\xef\x01\n" +
+	"\x18ReconcileObjectsResponse\x12\x18\n" +
+	"\aobjects\x18\x01 \x01(\x05R\aobjects\x12\x1e\n" +
+	"\n" +
+	"references\x18\x02 \x01(\x05R\n" +
+	"references\x12>\n" +
+	"\aorphans\x18\x03 \x03(\v2$.api.proto.v1.rpc.admin.OrphanObjectR\aorphans\x12?\n" +
+	"\amissing\x18\x04 \x03(\v2%.api.proto.v1.rpc.admin.MissingObjectR\amissing\x12\x18\n" +
+	"\aremoved\x18\x05 \x01(\x05R\aremovedB?Z=github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/adminb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_admin_reconcile_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_admin_reconcile_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_admin_reconcile_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_admin_reconcile_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_admin_reconcile_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_admin_reconcile_proto_rawDesc), len(file_api_proto_v1_rpc_admin_reconcile_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_admin_reconcile_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_api_proto_v1_rpc_admin_reconcile_proto_goTypes = []any{
+	(*OrphanObject)(nil),             // 0: api.proto.v1.rpc.admin.OrphanObject
+	(*MissingObject)(nil),            // 1: api.proto.v1.rpc.admin.MissingObject
+	(*ReconcileObjectsRequest)(nil),  // 2: api.proto.v1.rpc.admin.ReconcileObjectsRequest
+	(*ReconcileObjectsResponse)(nil), // 3: api.proto.v1.rpc.admin.ReconcileObjectsResponse
+}
+var file_api_proto_v1_rpc_admin_reconcile_proto_depIdxs = []int32{
+	0, // 0: api.proto.v1.rpc.admin.ReconcileObjectsResponse.orphans:type_name -> api.proto.v1.rpc.admin.OrphanObject
+	1, // 1: api.proto.v1.rpc.admin.ReconcileObjectsResponse.missing:type_name -> api.proto.v1.rpc.admin.MissingObject
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_admin_reconcile_proto_init() }
+func file_api_proto_v1_rpc_admin_reconcile_proto_init() {
+	if File_api_proto_v1_rpc_admin_reconcile_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_admin_reconcile_proto_rawDesc), len(file_api_proto_v1_rpc_admin_reconcile_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_admin_reconcile_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_admin_reconcile_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_admin_reconcile_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_admin_reconcile_proto = out.File
+	file_api_proto_v1_rpc_admin_reconcile_proto_goTypes = nil
+	file_api_proto_v1_rpc_admin_reconcile_proto_depIdxs = nil
+}
@@ -7,6 +7,7 @@
 package rpc
 
 import (
+	common "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
 	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -22,10 +23,86 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// DataListSort orders the records returned by DataList; newer records come first by default.
+type DataListSort int32
+
+const (
+	DataListSort_DATA_LIST_SORT_UNSPECIFIED  DataListSort = 0
+	DataListSort_DATA_LIST_SORT_CREATED_DESC DataListSort = 1
+	DataListSort_DATA_LIST_SORT_CREATED_ASC  DataListSort = 2
+	DataListSort_DATA_LIST_SORT_UPDATED_DESC DataListSort = 3
+	DataListSort_DATA_LIST_SORT_UPDATED_ASC  DataListSort = 4
+)
+
+// Enum value maps for DataListSort.
+var (
+	DataListSort_name = map[int32]string{
+		0: "DATA_LIST_SORT_UNSPECIFIED",
+		1: "DATA_LIST_SORT_CREATED_DESC",
+		2: "DATA_LIST_SORT_CREATED_ASC",
+		3: "DATA_LIST_SORT_UPDATED_DESC",
+		4: "DATA_LIST_SORT_UPDATED_ASC",
+	}
+	DataListSort_value = map[string]int32{
+		"DATA_LIST_SORT_UNSPECIFIED":  0,
+		"DATA_LIST_SORT_CREATED_DESC": 1,
+		"DATA_LIST_SORT_CREATED_ASC":  2,
+		"DATA_LIST_SORT_UPDATED_DESC": 3,
+		"DATA_LIST_SORT_UPDATED_ASC":  4,
+	}
+)
+
+func (x DataListSort) Enum() *DataListSort {
+	p := new(DataListSort)
+	*p = x
+	return p
+}
+
+func (x DataListSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataListSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_rpc_data_list_proto_enumTypes[0].Descriptor()
+}
+
+func (DataListSort) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_rpc_data_list_proto_enumTypes[0]
+}
+
+func (x DataListSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataListSort.Descriptor instead.
+func (DataListSort) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_list_proto_rawDescGZIP(), []int{0}
+}
+
 type DataListRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page selects a page of limit records when no cursor is given; pages start at 1.
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// limit is the number of records per page, 100 by default and at most 1000.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor is the next_cursor of the previous page; it must be used with the same sort and filters.
+	Cursor string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// types keeps only records of the given types.
+	Types []common.DataType `protobuf:"varint,4,rep,packed,name=types,proto3,enum=api.proto.v1.common.DataType" json:"types,omitempty"`
+	// The time ranges are RFC 3339 timestamps; "from" is inclusive and "to" is exclusive.
+	CreatedFrom string       `protobuf:"bytes,5,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   string       `protobuf:"bytes,6,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	UpdatedFrom string       `protobuf:"bytes,7,opt,name=updated_from,json=updatedFrom,proto3" json:"updated_from,omitempty"`
+	UpdatedTo   string       `protobuf:"bytes,8,opt,name=updated_to,json=updatedTo,proto3" json:"updated_to,omitempty"`
+	Sort        DataListSort `protobuf:"varint,9,opt,name=sort,proto3,enum=api.proto.v1.rpc.DataListSort" json:"sort,omitempty"`
+	// tags keeps only records that have all of the given tags; tags are compared in lower case.
+	Tags []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// favorite keeps only records marked as favorite.
+	Favorite bool `protobuf:"varint,11,opt,name=favorite,proto3" json:"favorite,omitempty"`
+	// folder_id keeps only records in the given folder; 0 lists records in any folder.
+	FolderId int32 `protobuf:"varint,12,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	// subfolders also keeps records in the subfolders of folder_id, at any depth.
+	Subfolders    bool `protobuf:"varint,13,opt,name=subfolders,proto3" json:"subfolders,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,10 +151,90 @@ func (x *DataListRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *DataListRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *DataListRequest) GetTypes() []common.DataType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *DataListRequest) GetCreatedFrom() string {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return ""
+}
+
+func (x *DataListRequest) GetCreatedTo() string {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return ""
+}
+
+func (x *DataListRequest) GetUpdatedFrom() string {
+	if x != nil {
+		return x.UpdatedFrom
+	}
+	return ""
+}
+
+func (x *DataListRequest) GetUpdatedTo() string {
+	if x != nil {
+		return x.UpdatedTo
+	}
+	return ""
+}
+
+func (x *DataListRequest) GetSort() DataListSort {
+	if x != nil {
+		return x.Sort
+	}
+	return DataListSort_DATA_LIST_SORT_UNSPECIFIED
+}
+
+func (x *DataListRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *DataListRequest) GetFavorite() bool {
+	if x != nil {
+		return x.Favorite
+	}
+	return false
+}
+
+func (x *DataListRequest) GetFolderId() int32 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *DataListRequest) GetSubfolders() bool {
+	if x != nil {
+		return x.Subfolders
+	}
+	return false
+}
+
 type DataListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Records       []*models.Record       `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
-	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Records []*models.Record       `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// count is the number of records that match the filters, on all pages.
+	Count int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// next_cursor continues the list after this page; it is empty on the last page.
+	NextCursor    string `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -126,17 +283,48 @@ func (x *DataListResponse) GetCount() int32 {
 	return 0
 }
 
+func (x *DataListResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 var File_api_proto_v1_rpc_data_list_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_rpc_data_list_proto_rawDesc = "" +
 	"\n" +
-	" api/proto/v1/rpc/data_list.proto\x12\x10api.proto.v1.rpc\x1a api/proto/v1/models/record.proto\";\n" +
+	" api/proto/v1/rpc/data_list.proto\x12\x10api.proto.v1.rpc\x1a\x1fapi/proto/v1/common/enums.proto\x1a api/proto/v1/models/record.proto\"\xad\x03\n" +
 	"\x0fDataListRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"_\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x123\n" +
+	"\x05types\x18\x04 \x03(\x0e2\x1d.api.proto.v1.common.DataTypeR\x05types\x12!\n" +
+	"\fcreated_from\x18\x05 \x01(\tR\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\x06 \x01(\tR\tcreatedTo\x12!\n" +
+	"\fupdated_from\x18\a \x01(\tR\vupdatedFrom\x12\x1d\n" +
+	"\n" +
+	"updated_to\x18\b \x01(\tR\tupdatedTo\x122\n" +
+	"\x04sort\x18\t \x01(\x0e2\x1e.api.proto.v1.rpc.DataListSortR\x04sort\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x12\x1a\n" +
+	"\bfavorite\x18\v \x01(\bR\bfavorite\x12\x1b\n" +
+	"\tfolder_id\x18\f \x01(\x05R\bfolderId\x12\x1e\n" +
+	"\n" +
+	"subfolders\x18\r \x01(\bR\n" +
+	"subfolders\"\x80\x01\n" +
 	"\x10DataListResponse\x125\n" +
 	"\arecords\x18\x01 \x03(\v2\x1b.api.proto.v1.models.RecordR\arecords\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\x05R\x05countB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor*\xb0\x01\n" +
+	"\fDataListSort\x12\x1e\n" +
+	"\x1aDATA_LIST_SORT_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bDATA_LIST_SORT_CREATED_DESC\x10\x01\x12\x1e\n" +
+	"\x1aDATA_LIST_SORT_CREATED_ASC\x10\x02\x12\x1f\n" +
+	"\x1bDATA_LIST_SORT_UPDATED_DESC\x10\x03\x12\x1e\n" +
+	"\x1aDATA_LIST_SORT_UPDATED_ASC\x10\x04B9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
 
 var (
 	file_api_proto_v1_rpc_data_list_proto_rawDescOnce sync.Once
@@ -150,19 +338,24 @@ func file_api_proto_v1_rpc_data_list_proto_rawDescGZIP() []byte {
 	return file_api_proto_v1_rpc_data_list_proto_rawDescData
 }
 
+var file_api_proto_v1_rpc_data_list_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_api_proto_v1_rpc_data_list_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_proto_v1_rpc_data_list_proto_goTypes = []any{
-	(*DataListRequest)(nil),  // 0: api.proto.v1.rpc.DataListRequest
-	(*DataListResponse)(nil), // 1: api.proto.v1.rpc.DataListResponse
-	(*models.Record)(nil),    // 2: api.proto.v1.models.Record
+	(DataListSort)(0),        // 0: api.proto.v1.rpc.DataListSort
+	(*DataListRequest)(nil),  // 1: api.proto.v1.rpc.DataListRequest
+	(*DataListResponse)(nil), // 2: api.proto.v1.rpc.DataListResponse
+	(common.DataType)(0),     // 3: api.proto.v1.common.DataType
+	(*models.Record)(nil),    // 4: api.proto.v1.models.Record
 }
 var file_api_proto_v1_rpc_data_list_proto_depIdxs = []int32{
-	2, // 0: api.proto.v1.rpc.DataListResponse.records:type_name -> api.proto.v1.models.Record
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: api.proto.v1.rpc.DataListRequest.types:type_name -> api.proto.v1.common.DataType
+	0, // 1: api.proto.v1.rpc.DataListRequest.sort:type_name -> api.proto.v1.rpc.DataListSort
+	4, // 2: api.proto.v1.rpc.DataListResponse.records:type_name -> api.proto.v1.models.Record
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_rpc_data_list_proto_init() }
@@ -175,13 +368,14 @@ func file_api_proto_v1_rpc_data_list_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_data_list_proto_rawDesc), len(file_api_proto_v1_rpc_data_list_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_api_proto_v1_rpc_data_list_proto_goTypes,
 		DependencyIndexes: file_api_proto_v1_rpc_data_list_proto_depIdxs,
+		EnumInfos:         file_api_proto_v1_rpc_data_list_proto_enumTypes,
 		MessageInfos:      file_api_proto_v1_rpc_data_list_proto_msgTypes,
 	}.Build()
 	File_api_proto_v1_rpc_data_list_proto = out.File
@@ -32,6 +32,10 @@ type DataSaveRequest struct {
 	//	*DataSaveRequest_BankCard
 	//	*DataSaveRequest_Credentials
 	//	*DataSaveRequest_BinaryData
+	//	*DataSaveRequest_Encrypted
+	//	*DataSaveRequest_SecureNote
+	//	*DataSaveRequest_Otp
+	//	*DataSaveRequest_SshKey
 	Data          isDataSaveRequest_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -115,6 +119,42 @@ func (x *DataSaveRequest) GetBinaryData() *models.File {
 	return nil
 }
 
+func (x *DataSaveRequest) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		if x, ok := x.Data.(*DataSaveRequest_Encrypted); ok {
+			return x.Encrypted
+		}
+	}
+	return nil
+}
+
+func (x *DataSaveRequest) GetSecureNote() *models.SecureNote {
+	if x != nil {
+		if x, ok := x.Data.(*DataSaveRequest_SecureNote); ok {
+			return x.SecureNote
+		}
+	}
+	return nil
+}
+
+func (x *DataSaveRequest) GetOtp() *models.Otp {
+	if x != nil {
+		if x, ok := x.Data.(*DataSaveRequest_Otp); ok {
+			return x.Otp
+		}
+	}
+	return nil
+}
+
+func (x *DataSaveRequest) GetSshKey() *models.SshKey {
+	if x != nil {
+		if x, ok := x.Data.(*DataSaveRequest_SshKey); ok {
+			return x.SshKey
+		}
+	}
+	return nil
+}
+
 type isDataSaveRequest_Data interface {
 	isDataSaveRequest_Data()
 }
@@ -131,12 +171,36 @@ type DataSaveRequest_BinaryData struct {
 	BinaryData *models.File `protobuf:"bytes,5,opt,name=binary_data,json=binaryData,proto3,oneof"`
 }
 
+type DataSaveRequest_Encrypted struct {
+	Encrypted *models.EncryptedPayload `protobuf:"bytes,6,opt,name=encrypted,proto3,oneof"`
+}
+
+type DataSaveRequest_SecureNote struct {
+	SecureNote *models.SecureNote `protobuf:"bytes,7,opt,name=secure_note,json=secureNote,proto3,oneof"`
+}
+
+type DataSaveRequest_Otp struct {
+	Otp *models.Otp `protobuf:"bytes,8,opt,name=otp,proto3,oneof"`
+}
+
+type DataSaveRequest_SshKey struct {
+	SshKey *models.SshKey `protobuf:"bytes,9,opt,name=ssh_key,json=sshKey,proto3,oneof"`
+}
+
 func (*DataSaveRequest_BankCard) isDataSaveRequest_Data() {}
 
 func (*DataSaveRequest_Credentials) isDataSaveRequest_Data() {}
 
 func (*DataSaveRequest_BinaryData) isDataSaveRequest_Data() {}
 
+func (*DataSaveRequest_Encrypted) isDataSaveRequest_Data() {}
+
+func (*DataSaveRequest_SecureNote) isDataSaveRequest_Data() {}
+
+func (*DataSaveRequest_Otp) isDataSaveRequest_Data() {}
+
+func (*DataSaveRequest_SshKey) isDataSaveRequest_Data() {}
+
 type DataSaveResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -185,14 +249,19 @@ var File_api_proto_v1_rpc_data_save_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_rpc_data_save_proto_rawDesc = "" +
 	"\n" +
-	" api/proto/v1/rpc/data_save.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\x1a\x1eapi/proto/v1/models/file.proto\x1a#api/proto/v1/models/bank_card.proto\x1a%api/proto/v1/models/credentials.proto\x1a\x1fapi/proto/v1/common/enums.proto\"\xbd\x02\n" +
+	" api/proto/v1/rpc/data_save.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\x1a\x1eapi/proto/v1/models/file.proto\x1a#api/proto/v1/models/bank_card.proto\x1a%api/proto/v1/models/credentials.proto\x1a%api/proto/v1/models/secure_note.proto\x1a\x1dapi/proto/v1/models/otp.proto\x1a!api/proto/v1/models/ssh_key.proto\x1a+api/proto/v1/models/encrypted_payload.proto\x1a\x1fapi/proto/v1/common/enums.proto\"\xae\x04\n" +
 	"\x0fDataSaveRequest\x121\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x1d.api.proto.v1.common.DataTypeR\x04type\x12-\n" +
 	"\x04meta\x18\x02 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12<\n" +
 	"\tbank_card\x18\x03 \x01(\v2\x1d.api.proto.v1.models.BankCardH\x00R\bbankCard\x12D\n" +
 	"\vcredentials\x18\x04 \x01(\v2 .api.proto.v1.models.CredentialsH\x00R\vcredentials\x12<\n" +
 	"\vbinary_data\x18\x05 \x01(\v2\x19.api.proto.v1.models.FileH\x00R\n" +
-	"binaryDataB\x06\n" +
+	"binaryData\x12E\n" +
+	"\tencrypted\x18\x06 \x01(\v2%.api.proto.v1.models.EncryptedPayloadH\x00R\tencrypted\x12B\n" +
+	"\vsecure_note\x18\a \x01(\v2\x1f.api.proto.v1.models.SecureNoteH\x00R\n" +
+	"secureNote\x12,\n" +
+	"\x03otp\x18\b \x01(\v2\x18.api.proto.v1.models.OtpH\x00R\x03otp\x126\n" +
+	"\assh_key\x18\t \x01(\v2\x1b.api.proto.v1.models.SshKeyH\x00R\x06sshKeyB\x06\n" +
 	"\x04data\",\n" +
 	"\x10DataSaveResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessageB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
@@ -211,25 +280,33 @@ func file_api_proto_v1_rpc_data_save_proto_rawDescGZIP() []byte {
 
 var file_api_proto_v1_rpc_data_save_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_proto_v1_rpc_data_save_proto_goTypes = []any{
-	(*DataSaveRequest)(nil),    // 0: api.proto.v1.rpc.DataSaveRequest
-	(*DataSaveResponse)(nil),   // 1: api.proto.v1.rpc.DataSaveResponse
-	(common.DataType)(0),       // 2: api.proto.v1.common.DataType
-	(*models.Meta)(nil),        // 3: api.proto.v1.models.Meta
-	(*models.BankCard)(nil),    // 4: api.proto.v1.models.BankCard
-	(*models.Credentials)(nil), // 5: api.proto.v1.models.Credentials
-	(*models.File)(nil),        // 6: api.proto.v1.models.File
+	(*DataSaveRequest)(nil),         // 0: api.proto.v1.rpc.DataSaveRequest
+	(*DataSaveResponse)(nil),        // 1: api.proto.v1.rpc.DataSaveResponse
+	(common.DataType)(0),            // 2: api.proto.v1.common.DataType
+	(*models.Meta)(nil),             // 3: api.proto.v1.models.Meta
+	(*models.BankCard)(nil),         // 4: api.proto.v1.models.BankCard
+	(*models.Credentials)(nil),      // 5: api.proto.v1.models.Credentials
+	(*models.File)(nil),             // 6: api.proto.v1.models.File
+	(*models.EncryptedPayload)(nil), // 7: api.proto.v1.models.EncryptedPayload
+	(*models.SecureNote)(nil),       // 8: api.proto.v1.models.SecureNote
+	(*models.Otp)(nil),              // 9: api.proto.v1.models.Otp
+	(*models.SshKey)(nil),           // 10: api.proto.v1.models.SshKey
 }
 var file_api_proto_v1_rpc_data_save_proto_depIdxs = []int32{
-	2, // 0: api.proto.v1.rpc.DataSaveRequest.type:type_name -> api.proto.v1.common.DataType
-	3, // 1: api.proto.v1.rpc.DataSaveRequest.meta:type_name -> api.proto.v1.models.Meta
-	4, // 2: api.proto.v1.rpc.DataSaveRequest.bank_card:type_name -> api.proto.v1.models.BankCard
-	5, // 3: api.proto.v1.rpc.DataSaveRequest.credentials:type_name -> api.proto.v1.models.Credentials
-	6, // 4: api.proto.v1.rpc.DataSaveRequest.binary_data:type_name -> api.proto.v1.models.File
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	2,  // 0: api.proto.v1.rpc.DataSaveRequest.type:type_name -> api.proto.v1.common.DataType
+	3,  // 1: api.proto.v1.rpc.DataSaveRequest.meta:type_name -> api.proto.v1.models.Meta
+	4,  // 2: api.proto.v1.rpc.DataSaveRequest.bank_card:type_name -> api.proto.v1.models.BankCard
+	5,  // 3: api.proto.v1.rpc.DataSaveRequest.credentials:type_name -> api.proto.v1.models.Credentials
+	6,  // 4: api.proto.v1.rpc.DataSaveRequest.binary_data:type_name -> api.proto.v1.models.File
+	7,  // 5: api.proto.v1.rpc.DataSaveRequest.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	8,  // 6: api.proto.v1.rpc.DataSaveRequest.secure_note:type_name -> api.proto.v1.models.SecureNote
+	9,  // 7: api.proto.v1.rpc.DataSaveRequest.otp:type_name -> api.proto.v1.models.Otp
+	10, // 8: api.proto.v1.rpc.DataSaveRequest.ssh_key:type_name -> api.proto.v1.models.SshKey
+	9,  // [9:9] is the sub-list for method output_type
+	9,  // [9:9] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_rpc_data_save_proto_init() }
@@ -241,6 +318,10 @@ func file_api_proto_v1_rpc_data_save_proto_init() {
 		(*DataSaveRequest_BankCard)(nil),
 		(*DataSaveRequest_Credentials)(nil),
 		(*DataSaveRequest_BinaryData)(nil),
+		(*DataSaveRequest_Encrypted)(nil),
+		(*DataSaveRequest_SecureNote)(nil),
+		(*DataSaveRequest_Otp)(nil),
+		(*DataSaveRequest_SshKey)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -0,0 +1,343 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/data_update.proto
+
+package rpc
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DataUpdateRequest replaces the contents of an existing record. The record keeps its ID,
+// type and creation time; the new data must be of the same type.
+type DataUpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The new metadata; the current one is kept when it is not set.
+	Meta *models.Meta `protobuf:"bytes,2,opt,name=meta,proto3" json:"meta,omitempty"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*DataUpdateRequest_BankCard
+	//	*DataUpdateRequest_Credentials
+	//	*DataUpdateRequest_BinaryData
+	//	*DataUpdateRequest_Encrypted
+	//	*DataUpdateRequest_SecureNote
+	//	*DataUpdateRequest_Otp
+	//	*DataUpdateRequest_SshKey
+	Data          isDataUpdateRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataUpdateRequest) Reset() {
+	*x = DataUpdateRequest{}
+	mi := &file_api_proto_v1_rpc_data_update_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataUpdateRequest) ProtoMessage() {}
+
+func (x *DataUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_update_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataUpdateRequest.ProtoReflect.Descriptor instead.
+func (*DataUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_update_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DataUpdateRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DataUpdateRequest) GetMeta() *models.Meta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetData() isDataUpdateRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetBankCard() *models.BankCard {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_BankCard); ok {
+			return x.BankCard
+		}
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetCredentials() *models.Credentials {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_Credentials); ok {
+			return x.Credentials
+		}
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetBinaryData() *models.File {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_BinaryData); ok {
+			return x.BinaryData
+		}
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_Encrypted); ok {
+			return x.Encrypted
+		}
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetSecureNote() *models.SecureNote {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_SecureNote); ok {
+			return x.SecureNote
+		}
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetOtp() *models.Otp {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_Otp); ok {
+			return x.Otp
+		}
+	}
+	return nil
+}
+
+func (x *DataUpdateRequest) GetSshKey() *models.SshKey {
+	if x != nil {
+		if x, ok := x.Data.(*DataUpdateRequest_SshKey); ok {
+			return x.SshKey
+		}
+	}
+	return nil
+}
+
+type isDataUpdateRequest_Data interface {
+	isDataUpdateRequest_Data()
+}
+
+type DataUpdateRequest_BankCard struct {
+	BankCard *models.BankCard `protobuf:"bytes,3,opt,name=bank_card,json=bankCard,proto3,oneof"`
+}
+
+type DataUpdateRequest_Credentials struct {
+	Credentials *models.Credentials `protobuf:"bytes,4,opt,name=credentials,proto3,oneof"`
+}
+
+type DataUpdateRequest_BinaryData struct {
+	BinaryData *models.File `protobuf:"bytes,5,opt,name=binary_data,json=binaryData,proto3,oneof"`
+}
+
+type DataUpdateRequest_Encrypted struct {
+	Encrypted *models.EncryptedPayload `protobuf:"bytes,6,opt,name=encrypted,proto3,oneof"`
+}
+
+type DataUpdateRequest_SecureNote struct {
+	SecureNote *models.SecureNote `protobuf:"bytes,7,opt,name=secure_note,json=secureNote,proto3,oneof"`
+}
+
+type DataUpdateRequest_Otp struct {
+	Otp *models.Otp `protobuf:"bytes,8,opt,name=otp,proto3,oneof"`
+}
+
+type DataUpdateRequest_SshKey struct {
+	SshKey *models.SshKey `protobuf:"bytes,9,opt,name=ssh_key,json=sshKey,proto3,oneof"`
+}
+
+func (*DataUpdateRequest_BankCard) isDataUpdateRequest_Data() {}
+
+func (*DataUpdateRequest_Credentials) isDataUpdateRequest_Data() {}
+
+func (*DataUpdateRequest_BinaryData) isDataUpdateRequest_Data() {}
+
+func (*DataUpdateRequest_Encrypted) isDataUpdateRequest_Data() {}
+
+func (*DataUpdateRequest_SecureNote) isDataUpdateRequest_Data() {}
+
+func (*DataUpdateRequest_Otp) isDataUpdateRequest_Data() {}
+
+func (*DataUpdateRequest_SshKey) isDataUpdateRequest_Data() {}
+
+type DataUpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataUpdateResponse) Reset() {
+	*x = DataUpdateResponse{}
+	mi := &file_api_proto_v1_rpc_data_update_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataUpdateResponse) ProtoMessage() {}
+
+func (x *DataUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_update_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataUpdateResponse.ProtoReflect.Descriptor instead.
+func (*DataUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_update_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DataUpdateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_api_proto_v1_rpc_data_update_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_data_update_proto_rawDesc = "" +
+	"\n" +
+	"\"api/proto/v1/rpc/data_update.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\x1a\x1eapi/proto/v1/models/file.proto\x1a#api/proto/v1/models/bank_card.proto\x1a%api/proto/v1/models/credentials.proto\x1a%api/proto/v1/models/secure_note.proto\x1a\x1dapi/proto/v1/models/otp.proto\x1a!api/proto/v1/models/ssh_key.proto\x1a+api/proto/v1/models/encrypted_payload.proto\"\x8d\x04\n" +
+	"\x11DataUpdateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12-\n" +
+	"\x04meta\x18\x02 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12<\n" +
+	"\tbank_card\x18\x03 \x01(\v2\x1d.api.proto.v1.models.BankCardH\x00R\bbankCard\x12D\n" +
+	"\vcredentials\x18\x04 \x01(\v2 .api.proto.v1.models.CredentialsH\x00R\vcredentials\x12<\n" +
+	"\vbinary_data\x18\x05 \x01(\v2\x19.api.proto.v1.models.FileH\x00R\n" +
+	"binaryData\x12E\n" +
+	"\tencrypted\x18\x06 \x01(\v2%.api.proto.v1.models.EncryptedPayloadH\x00R\tencrypted\x12B\n" +
+	"\vsecure_note\x18\a \x01(\v2\x1f.api.proto.v1.models.SecureNoteH\x00R\n" +
+	"secureNote\x12,\n" +
+	"\x03otp\x18\b \x01(\v2\x18.api.proto.v1.models.OtpH\x00R\x03otp\x126\n" +
+	"\assh_key\x18\t \x01(\v2\x1b.api.proto.v1.models.SshKeyH\x00R\x06sshKeyB\x06\n" +
+	"\x04data\".\n" +
+	"\x12DataUpdateResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessageB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_data_update_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_data_update_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_data_update_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_data_update_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_data_update_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_data_update_proto_rawDesc), len(file_api_proto_v1_rpc_data_update_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_data_update_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_data_update_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_proto_v1_rpc_data_update_proto_goTypes = []any{
+	(*DataUpdateRequest)(nil),       // 0: api.proto.v1.rpc.DataUpdateRequest
+	(*DataUpdateResponse)(nil),      // 1: api.proto.v1.rpc.DataUpdateResponse
+	(*models.Meta)(nil),             // 2: api.proto.v1.models.Meta
+	(*models.BankCard)(nil),         // 3: api.proto.v1.models.BankCard
+	(*models.Credentials)(nil),      // 4: api.proto.v1.models.Credentials
+	(*models.File)(nil),             // 5: api.proto.v1.models.File
+	(*models.EncryptedPayload)(nil), // 6: api.proto.v1.models.EncryptedPayload
+	(*models.SecureNote)(nil),       // 7: api.proto.v1.models.SecureNote
+	(*models.Otp)(nil),              // 8: api.proto.v1.models.Otp
+	(*models.SshKey)(nil),           // 9: api.proto.v1.models.SshKey
+}
+var file_api_proto_v1_rpc_data_update_proto_depIdxs = []int32{
+	2, // 0: api.proto.v1.rpc.DataUpdateRequest.meta:type_name -> api.proto.v1.models.Meta
+	3, // 1: api.proto.v1.rpc.DataUpdateRequest.bank_card:type_name -> api.proto.v1.models.BankCard
+	4, // 2: api.proto.v1.rpc.DataUpdateRequest.credentials:type_name -> api.proto.v1.models.Credentials
+	5, // 3: api.proto.v1.rpc.DataUpdateRequest.binary_data:type_name -> api.proto.v1.models.File
+	6, // 4: api.proto.v1.rpc.DataUpdateRequest.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	7, // 5: api.proto.v1.rpc.DataUpdateRequest.secure_note:type_name -> api.proto.v1.models.SecureNote
+	8, // 6: api.proto.v1.rpc.DataUpdateRequest.otp:type_name -> api.proto.v1.models.Otp
+	9, // 7: api.proto.v1.rpc.DataUpdateRequest.ssh_key:type_name -> api.proto.v1.models.SshKey
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_data_update_proto_init() }
+func file_api_proto_v1_rpc_data_update_proto_init() {
+	if File_api_proto_v1_rpc_data_update_proto != nil {
+		return
+	}
+	file_api_proto_v1_rpc_data_update_proto_msgTypes[0].OneofWrappers = []any{
+		(*DataUpdateRequest_BankCard)(nil),
+		(*DataUpdateRequest_Credentials)(nil),
+		(*DataUpdateRequest_BinaryData)(nil),
+		(*DataUpdateRequest_Encrypted)(nil),
+		(*DataUpdateRequest_SecureNote)(nil),
+		(*DataUpdateRequest_Otp)(nil),
+		(*DataUpdateRequest_SshKey)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_data_update_proto_rawDesc), len(file_api_proto_v1_rpc_data_update_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_data_update_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_data_update_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_data_update_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_data_update_proto = out.File
+	file_api_proto_v1_rpc_data_update_proto_goTypes = nil
+	file_api_proto_v1_rpc_data_update_proto_depIdxs = nil
+}
@@ -0,0 +1,553 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/data_versions.proto
+
+package rpc
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RecordVersion describes a prior revision of a record. Version numbers grow with every
+// update; the contents of a version are returned by ViewRecordVersion.
+type RecordVersion struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Meta    *models.Meta           `protobuf:"bytes,2,opt,name=meta,proto3" json:"meta,omitempty"`
+	// When the revision was written.
+	CreatedAt string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When the revision was replaced by a newer one.
+	ArchivedAt    string `protobuf:"bytes,4,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordVersion) Reset() {
+	*x = RecordVersion{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordVersion) ProtoMessage() {}
+
+func (x *RecordVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordVersion.ProtoReflect.Descriptor instead.
+func (*RecordVersion) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RecordVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RecordVersion) GetMeta() *models.Meta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *RecordVersion) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *RecordVersion) GetArchivedAt() string {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return ""
+}
+
+type ListRecordVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecordVersionsRequest) Reset() {
+	*x = ListRecordVersionsRequest{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecordVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecordVersionsRequest) ProtoMessage() {}
+
+func (x *ListRecordVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecordVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListRecordVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListRecordVersionsRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListRecordVersionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The version number of the current contents of the record.
+	CurrentVersion int32 `protobuf:"varint,1,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	// The number of prior versions kept for every record of the user.
+	Retention int32 `protobuf:"varint,2,opt,name=retention,proto3" json:"retention,omitempty"`
+	// Prior versions, newest first.
+	Versions      []*RecordVersion `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecordVersionsResponse) Reset() {
+	*x = ListRecordVersionsResponse{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecordVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecordVersionsResponse) ProtoMessage() {}
+
+func (x *ListRecordVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecordVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListRecordVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListRecordVersionsResponse) GetCurrentVersion() int32 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+func (x *ListRecordVersionsResponse) GetRetention() int32 {
+	if x != nil {
+		return x.Retention
+	}
+	return 0
+}
+
+func (x *ListRecordVersionsResponse) GetVersions() []*RecordVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// ViewRecordVersionRequest selects a prior version of a record; the response is a
+// DataViewResponse with the contents of that version.
+type ViewRecordVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ViewRecordVersionRequest) Reset() {
+	*x = ViewRecordVersionRequest{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ViewRecordVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ViewRecordVersionRequest) ProtoMessage() {}
+
+func (x *ViewRecordVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ViewRecordVersionRequest.ProtoReflect.Descriptor instead.
+func (*ViewRecordVersionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ViewRecordVersionRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ViewRecordVersionRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// RestoreRecordVersionRequest makes a prior version the current contents of a record.
+// The contents it replaces are kept as a new version.
+type RestoreRecordVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreRecordVersionRequest) Reset() {
+	*x = RestoreRecordVersionRequest{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreRecordVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRecordVersionRequest) ProtoMessage() {}
+
+func (x *RestoreRecordVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRecordVersionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreRecordVersionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RestoreRecordVersionRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RestoreRecordVersionRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type RestoreRecordVersionResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// The version number of the restored contents.
+	CurrentVersion int32 `protobuf:"varint,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RestoreRecordVersionResponse) Reset() {
+	*x = RestoreRecordVersionResponse{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreRecordVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRecordVersionResponse) ProtoMessage() {}
+
+func (x *RestoreRecordVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRecordVersionResponse.ProtoReflect.Descriptor instead.
+func (*RestoreRecordVersionResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RestoreRecordVersionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RestoreRecordVersionResponse) GetCurrentVersion() int32 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+// SetVersionRetentionRequest sets how many prior versions are kept for every record of the
+// user; 0 selects the server default.
+type SetVersionRetentionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Retention     int32                  `protobuf:"varint,1,opt,name=retention,proto3" json:"retention,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVersionRetentionRequest) Reset() {
+	*x = SetVersionRetentionRequest{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVersionRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVersionRetentionRequest) ProtoMessage() {}
+
+func (x *SetVersionRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVersionRetentionRequest.ProtoReflect.Descriptor instead.
+func (*SetVersionRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetVersionRetentionRequest) GetRetention() int32 {
+	if x != nil {
+		return x.Retention
+	}
+	return 0
+}
+
+type SetVersionRetentionResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// The retention in effect.
+	Retention     int32 `protobuf:"varint,2,opt,name=retention,proto3" json:"retention,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVersionRetentionResponse) Reset() {
+	*x = SetVersionRetentionResponse{}
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVersionRetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVersionRetentionResponse) ProtoMessage() {}
+
+func (x *SetVersionRetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_data_versions_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVersionRetentionResponse.ProtoReflect.Descriptor instead.
+func (*SetVersionRetentionResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetVersionRetentionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SetVersionRetentionResponse) GetRetention() int32 {
+	if x != nil {
+		return x.Retention
+	}
+	return 0
+}
+
+var File_api_proto_v1_rpc_data_versions_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_data_versions_proto_rawDesc = "" +
+	"\n" +
+	"$api/proto/v1/rpc/data_versions.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\"\x98\x01\n" +
+	"\rRecordVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12-\n" +
+	"\x04meta\x18\x02 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\x1f\n" +
+	"\varchived_at\x18\x04 \x01(\tR\n" +
+	"archivedAt\"+\n" +
+	"\x19ListRecordVersionsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\xa0\x01\n" +
+	"\x1aListRecordVersionsResponse\x12'\n" +
+	"\x0fcurrent_version\x18\x01 \x01(\x05R\x0ecurrentVersion\x12\x1c\n" +
+	"\tretention\x18\x02 \x01(\x05R\tretention\x12;\n" +
+	"\bversions\x18\x03 \x03(\v2\x1f.api.proto.v1.rpc.RecordVersionR\bversions\"D\n" +
+	"\x18ViewRecordVersionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"G\n" +
+	"\x1bRestoreRecordVersionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"a\n" +
+	"\x1cRestoreRecordVersionResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12'\n" +
+	"\x0fcurrent_version\x18\x02 \x01(\x05R\x0ecurrentVersion\":\n" +
+	"\x1aSetVersionRetentionRequest\x12\x1c\n" +
+	"\tretention\x18\x01 \x01(\x05R\tretention\"U\n" +
+	"\x1bSetVersionRetentionResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1c\n" +
+	"\tretention\x18\x02 \x01(\x05R\tretentionB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_data_versions_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_data_versions_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_data_versions_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_data_versions_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_data_versions_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_data_versions_proto_rawDesc), len(file_api_proto_v1_rpc_data_versions_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_data_versions_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_data_versions_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_api_proto_v1_rpc_data_versions_proto_goTypes = []any{
+	(*RecordVersion)(nil),                // 0: api.proto.v1.rpc.RecordVersion
+	(*ListRecordVersionsRequest)(nil),    // 1: api.proto.v1.rpc.ListRecordVersionsRequest
+	(*ListRecordVersionsResponse)(nil),   // 2: api.proto.v1.rpc.ListRecordVersionsResponse
+	(*ViewRecordVersionRequest)(nil),     // 3: api.proto.v1.rpc.ViewRecordVersionRequest
+	(*RestoreRecordVersionRequest)(nil),  // 4: api.proto.v1.rpc.RestoreRecordVersionRequest
+	(*RestoreRecordVersionResponse)(nil), // 5: api.proto.v1.rpc.RestoreRecordVersionResponse
+	(*SetVersionRetentionRequest)(nil),   // 6: api.proto.v1.rpc.SetVersionRetentionRequest
+	(*SetVersionRetentionResponse)(nil),  // 7: api.proto.v1.rpc.SetVersionRetentionResponse
+	(*models.Meta)(nil),                  // 8: api.proto.v1.models.Meta
+}
+var file_api_proto_v1_rpc_data_versions_proto_depIdxs = []int32{
+	8, // 0: api.proto.v1.rpc.RecordVersion.meta:type_name -> api.proto.v1.models.Meta
+	0, // 1: api.proto.v1.rpc.ListRecordVersionsResponse.versions:type_name -> api.proto.v1.rpc.RecordVersion
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_data_versions_proto_init() }
+func file_api_proto_v1_rpc_data_versions_proto_init() {
+	if File_api_proto_v1_rpc_data_versions_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_data_versions_proto_rawDesc), len(file_api_proto_v1_rpc_data_versions_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_data_versions_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_data_versions_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_data_versions_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_data_versions_proto = out.File
+	file_api_proto_v1_rpc_data_versions_proto_goTypes = nil
+	file_api_proto_v1_rpc_data_versions_proto_depIdxs = nil
+}
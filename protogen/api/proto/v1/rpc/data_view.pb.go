@@ -76,6 +76,10 @@ type DataViewResponse struct {
 	//	*DataViewResponse_BankCard
 	//	*DataViewResponse_Credentials
 	//	*DataViewResponse_BinaryData
+	//	*DataViewResponse_Encrypted
+	//	*DataViewResponse_SecureNote
+	//	*DataViewResponse_Otp
+	//	*DataViewResponse_SshKey
 	Data          isDataViewResponse_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -159,6 +163,42 @@ func (x *DataViewResponse) GetBinaryData() *models.File {
 	return nil
 }
 
+func (x *DataViewResponse) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		if x, ok := x.Data.(*DataViewResponse_Encrypted); ok {
+			return x.Encrypted
+		}
+	}
+	return nil
+}
+
+func (x *DataViewResponse) GetSecureNote() *models.SecureNote {
+	if x != nil {
+		if x, ok := x.Data.(*DataViewResponse_SecureNote); ok {
+			return x.SecureNote
+		}
+	}
+	return nil
+}
+
+func (x *DataViewResponse) GetOtp() *models.Otp {
+	if x != nil {
+		if x, ok := x.Data.(*DataViewResponse_Otp); ok {
+			return x.Otp
+		}
+	}
+	return nil
+}
+
+func (x *DataViewResponse) GetSshKey() *models.SshKey {
+	if x != nil {
+		if x, ok := x.Data.(*DataViewResponse_SshKey); ok {
+			return x.SshKey
+		}
+	}
+	return nil
+}
+
 type isDataViewResponse_Data interface {
 	isDataViewResponse_Data()
 }
@@ -175,26 +215,55 @@ type DataViewResponse_BinaryData struct {
 	BinaryData *models.File `protobuf:"bytes,5,opt,name=binary_data,json=binaryData,proto3,oneof"`
 }
 
+type DataViewResponse_Encrypted struct {
+	Encrypted *models.EncryptedPayload `protobuf:"bytes,6,opt,name=encrypted,proto3,oneof"`
+}
+
+type DataViewResponse_SecureNote struct {
+	SecureNote *models.SecureNote `protobuf:"bytes,7,opt,name=secure_note,json=secureNote,proto3,oneof"`
+}
+
+type DataViewResponse_Otp struct {
+	Otp *models.Otp `protobuf:"bytes,8,opt,name=otp,proto3,oneof"`
+}
+
+type DataViewResponse_SshKey struct {
+	SshKey *models.SshKey `protobuf:"bytes,9,opt,name=ssh_key,json=sshKey,proto3,oneof"`
+}
+
 func (*DataViewResponse_BankCard) isDataViewResponse_Data() {}
 
 func (*DataViewResponse_Credentials) isDataViewResponse_Data() {}
 
 func (*DataViewResponse_BinaryData) isDataViewResponse_Data() {}
 
+func (*DataViewResponse_Encrypted) isDataViewResponse_Data() {}
+
+func (*DataViewResponse_SecureNote) isDataViewResponse_Data() {}
+
+func (*DataViewResponse_Otp) isDataViewResponse_Data() {}
+
+func (*DataViewResponse_SshKey) isDataViewResponse_Data() {}
+
 var File_api_proto_v1_rpc_data_view_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_rpc_data_view_proto_rawDesc = "" +
 	"\n" +
-	" api/proto/v1/rpc/data_view.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\x1a\x1eapi/proto/v1/models/file.proto\x1a#api/proto/v1/models/bank_card.proto\x1a%api/proto/v1/models/credentials.proto\x1a\x1fapi/proto/v1/common/enums.proto\"!\n" +
+	" api/proto/v1/rpc/data_view.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\x1a\x1eapi/proto/v1/models/file.proto\x1a#api/proto/v1/models/bank_card.proto\x1a%api/proto/v1/models/credentials.proto\x1a%api/proto/v1/models/secure_note.proto\x1a\x1dapi/proto/v1/models/otp.proto\x1a!api/proto/v1/models/ssh_key.proto\x1a+api/proto/v1/models/encrypted_payload.proto\x1a\x1fapi/proto/v1/common/enums.proto\"!\n" +
 	"\x0fDataViewRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"\xbe\x02\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\xaf\x04\n" +
 	"\x10DataViewResponse\x121\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x1d.api.proto.v1.common.DataTypeR\x04type\x12-\n" +
 	"\x04meta\x18\x02 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12<\n" +
 	"\tbank_card\x18\x03 \x01(\v2\x1d.api.proto.v1.models.BankCardH\x00R\bbankCard\x12D\n" +
 	"\vcredentials\x18\x04 \x01(\v2 .api.proto.v1.models.CredentialsH\x00R\vcredentials\x12<\n" +
 	"\vbinary_data\x18\x05 \x01(\v2\x19.api.proto.v1.models.FileH\x00R\n" +
-	"binaryDataB\x06\n" +
+	"binaryData\x12E\n" +
+	"\tencrypted\x18\x06 \x01(\v2%.api.proto.v1.models.EncryptedPayloadH\x00R\tencrypted\x12B\n" +
+	"\vsecure_note\x18\a \x01(\v2\x1f.api.proto.v1.models.SecureNoteH\x00R\n" +
+	"secureNote\x12,\n" +
+	"\x03otp\x18\b \x01(\v2\x18.api.proto.v1.models.OtpH\x00R\x03otp\x126\n" +
+	"\assh_key\x18\t \x01(\v2\x1b.api.proto.v1.models.SshKeyH\x00R\x06sshKeyB\x06\n" +
 	"\x04dataB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
 
 var (
@@ -211,25 +280,33 @@ func file_api_proto_v1_rpc_data_view_proto_rawDescGZIP() []byte {
 
 var file_api_proto_v1_rpc_data_view_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_proto_v1_rpc_data_view_proto_goTypes = []any{
-	(*DataViewRequest)(nil),    // 0: api.proto.v1.rpc.DataViewRequest
-	(*DataViewResponse)(nil),   // 1: api.proto.v1.rpc.DataViewResponse
-	(common.DataType)(0),       // 2: api.proto.v1.common.DataType
-	(*models.Meta)(nil),        // 3: api.proto.v1.models.Meta
-	(*models.BankCard)(nil),    // 4: api.proto.v1.models.BankCard
-	(*models.Credentials)(nil), // 5: api.proto.v1.models.Credentials
-	(*models.File)(nil),        // 6: api.proto.v1.models.File
+	(*DataViewRequest)(nil),         // 0: api.proto.v1.rpc.DataViewRequest
+	(*DataViewResponse)(nil),        // 1: api.proto.v1.rpc.DataViewResponse
+	(common.DataType)(0),            // 2: api.proto.v1.common.DataType
+	(*models.Meta)(nil),             // 3: api.proto.v1.models.Meta
+	(*models.BankCard)(nil),         // 4: api.proto.v1.models.BankCard
+	(*models.Credentials)(nil),      // 5: api.proto.v1.models.Credentials
+	(*models.File)(nil),             // 6: api.proto.v1.models.File
+	(*models.EncryptedPayload)(nil), // 7: api.proto.v1.models.EncryptedPayload
+	(*models.SecureNote)(nil),       // 8: api.proto.v1.models.SecureNote
+	(*models.Otp)(nil),              // 9: api.proto.v1.models.Otp
+	(*models.SshKey)(nil),           // 10: api.proto.v1.models.SshKey
 }
 var file_api_proto_v1_rpc_data_view_proto_depIdxs = []int32{
-	2, // 0: api.proto.v1.rpc.DataViewResponse.type:type_name -> api.proto.v1.common.DataType
-	3, // 1: api.proto.v1.rpc.DataViewResponse.meta:type_name -> api.proto.v1.models.Meta
-	4, // 2: api.proto.v1.rpc.DataViewResponse.bank_card:type_name -> api.proto.v1.models.BankCard
-	5, // 3: api.proto.v1.rpc.DataViewResponse.credentials:type_name -> api.proto.v1.models.Credentials
-	6, // 4: api.proto.v1.rpc.DataViewResponse.binary_data:type_name -> api.proto.v1.models.File
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	2,  // 0: api.proto.v1.rpc.DataViewResponse.type:type_name -> api.proto.v1.common.DataType
+	3,  // 1: api.proto.v1.rpc.DataViewResponse.meta:type_name -> api.proto.v1.models.Meta
+	4,  // 2: api.proto.v1.rpc.DataViewResponse.bank_card:type_name -> api.proto.v1.models.BankCard
+	5,  // 3: api.proto.v1.rpc.DataViewResponse.credentials:type_name -> api.proto.v1.models.Credentials
+	6,  // 4: api.proto.v1.rpc.DataViewResponse.binary_data:type_name -> api.proto.v1.models.File
+	7,  // 5: api.proto.v1.rpc.DataViewResponse.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	8,  // 6: api.proto.v1.rpc.DataViewResponse.secure_note:type_name -> api.proto.v1.models.SecureNote
+	9,  // 7: api.proto.v1.rpc.DataViewResponse.otp:type_name -> api.proto.v1.models.Otp
+	10, // 8: api.proto.v1.rpc.DataViewResponse.ssh_key:type_name -> api.proto.v1.models.SshKey
+	9,  // [9:9] is the sub-list for method output_type
+	9,  // [9:9] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_rpc_data_view_proto_init() }
@@ -241,6 +318,10 @@ func file_api_proto_v1_rpc_data_view_proto_init() {
 		(*DataViewResponse_BankCard)(nil),
 		(*DataViewResponse_Credentials)(nil),
 		(*DataViewResponse_BinaryData)(nil),
+		(*DataViewResponse_Encrypted)(nil),
+		(*DataViewResponse_SecureNote)(nil),
+		(*DataViewResponse_Otp)(nil),
+		(*DataViewResponse_SshKey)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
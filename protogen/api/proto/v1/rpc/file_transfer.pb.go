@@ -0,0 +1,564 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/file_transfer.proto
+
+package rpc
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UploadFileHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Meta          *models.Meta           `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadFileHeader) Reset() {
+	*x = UploadFileHeader{}
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileHeader) ProtoMessage() {}
+
+func (x *UploadFileHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileHeader.ProtoReflect.Descriptor instead.
+func (*UploadFileHeader) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UploadFileHeader) GetMeta() *models.Meta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *UploadFileHeader) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UploadFileHeader) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type UploadFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*UploadFileRequest_Header
+	//	*UploadFileRequest_Chunk
+	Payload       isUploadFileRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadFileRequest) Reset() {
+	*x = UploadFileRequest{}
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileRequest) ProtoMessage() {}
+
+func (x *UploadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileRequest.ProtoReflect.Descriptor instead.
+func (*UploadFileRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UploadFileRequest) GetPayload() isUploadFileRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *UploadFileRequest) GetHeader() *UploadFileHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadFileRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *UploadFileRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadFileRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadFileRequest_Payload interface {
+	isUploadFileRequest_Payload()
+}
+
+type UploadFileRequest_Header struct {
+	Header *UploadFileHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type UploadFileRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadFileRequest_Header) isUploadFileRequest_Payload() {}
+
+func (*UploadFileRequest_Chunk) isUploadFileRequest_Payload() {}
+
+type UploadFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadFileResponse) Reset() {
+	*x = UploadFileResponse{}
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadFileResponse) ProtoMessage() {}
+
+func (x *UploadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadFileResponse.ProtoReflect.Descriptor instead.
+func (*UploadFileResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UploadFileResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UploadFileResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadFileResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DownloadFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// offset and length select a range of the file; a negative offset selects the last -offset
+	// bytes and a zero length runs to the end. Without them the whole file is sent.
+	Offset        int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int64 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadFileRequest) Reset() {
+	*x = DownloadFileRequest{}
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadFileRequest) ProtoMessage() {}
+
+func (x *DownloadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadFileRequest.ProtoReflect.Descriptor instead.
+func (*DownloadFileRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DownloadFileRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DownloadFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadFileRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type DownloadFileHeader struct {
+	state     protoimpl.MessageState   `protogen:"open.v1"`
+	Meta      *models.Meta             `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name      string                   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type      string                   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Size      int64                    `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Encrypted *models.EncryptedPayload `protobuf:"bytes,5,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	// offset and length describe the range of the file sent in the following chunks.
+	Offset        int64 `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int64 `protobuf:"varint,7,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadFileHeader) Reset() {
+	*x = DownloadFileHeader{}
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadFileHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadFileHeader) ProtoMessage() {}
+
+func (x *DownloadFileHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadFileHeader.ProtoReflect.Descriptor instead.
+func (*DownloadFileHeader) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DownloadFileHeader) GetMeta() *models.Meta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *DownloadFileHeader) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DownloadFileHeader) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DownloadFileHeader) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DownloadFileHeader) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		return x.Encrypted
+	}
+	return nil
+}
+
+func (x *DownloadFileHeader) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadFileHeader) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type DownloadFileResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*DownloadFileResponse_Header
+	//	*DownloadFileResponse_Chunk
+	Payload       isDownloadFileResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadFileResponse) Reset() {
+	*x = DownloadFileResponse{}
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadFileResponse) ProtoMessage() {}
+
+func (x *DownloadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_file_transfer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadFileResponse.ProtoReflect.Descriptor instead.
+func (*DownloadFileResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DownloadFileResponse) GetPayload() isDownloadFileResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DownloadFileResponse) GetHeader() *DownloadFileHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadFileResponse_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *DownloadFileResponse) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadFileResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isDownloadFileResponse_Payload interface {
+	isDownloadFileResponse_Payload()
+}
+
+type DownloadFileResponse_Header struct {
+	Header *DownloadFileHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type DownloadFileResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*DownloadFileResponse_Header) isDownloadFileResponse_Payload() {}
+
+func (*DownloadFileResponse_Chunk) isDownloadFileResponse_Payload() {}
+
+var File_api_proto_v1_rpc_file_transfer_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_file_transfer_proto_rawDesc = "" +
+	"\n" +
+	"$api/proto/v1/rpc/file_transfer.proto\x12\x10api.proto.v1.rpc\x1a\x1eapi/proto/v1/models/meta.proto\x1a+api/proto/v1/models/encrypted_payload.proto\"i\n" +
+	"\x10UploadFileHeader\x12-\n" +
+	"\x04meta\x18\x01 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\"t\n" +
+	"\x11UploadFileRequest\x12<\n" +
+	"\x06header\x18\x01 \x01(\v2\".api.proto.v1.rpc.UploadFileHeaderH\x00R\x06header\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"R\n" +
+	"\x12UploadFileResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"U\n" +
+	"\x13DownloadFileRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x03R\x06length\"\xf4\x01\n" +
+	"\x12DownloadFileHeader\x12-\n" +
+	"\x04meta\x18\x01 \x01(\v2\x19.api.proto.v1.models.MetaR\x04meta\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12C\n" +
+	"\tencrypted\x18\x05 \x01(\v2%.api.proto.v1.models.EncryptedPayloadR\tencrypted\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\a \x01(\x03R\x06length\"y\n" +
+	"\x14DownloadFileResponse\x12>\n" +
+	"\x06header\x18\x01 \x01(\v2$.api.proto.v1.rpc.DownloadFileHeaderH\x00R\x06header\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayloadB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_file_transfer_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_file_transfer_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_file_transfer_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_file_transfer_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_file_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_file_transfer_proto_rawDesc), len(file_api_proto_v1_rpc_file_transfer_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_file_transfer_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_file_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_proto_v1_rpc_file_transfer_proto_goTypes = []any{
+	(*UploadFileHeader)(nil),        // 0: api.proto.v1.rpc.UploadFileHeader
+	(*UploadFileRequest)(nil),       // 1: api.proto.v1.rpc.UploadFileRequest
+	(*UploadFileResponse)(nil),      // 2: api.proto.v1.rpc.UploadFileResponse
+	(*DownloadFileRequest)(nil),     // 3: api.proto.v1.rpc.DownloadFileRequest
+	(*DownloadFileHeader)(nil),      // 4: api.proto.v1.rpc.DownloadFileHeader
+	(*DownloadFileResponse)(nil),    // 5: api.proto.v1.rpc.DownloadFileResponse
+	(*models.Meta)(nil),             // 6: api.proto.v1.models.Meta
+	(*models.EncryptedPayload)(nil), // 7: api.proto.v1.models.EncryptedPayload
+}
+var file_api_proto_v1_rpc_file_transfer_proto_depIdxs = []int32{
+	6, // 0: api.proto.v1.rpc.UploadFileHeader.meta:type_name -> api.proto.v1.models.Meta
+	0, // 1: api.proto.v1.rpc.UploadFileRequest.header:type_name -> api.proto.v1.rpc.UploadFileHeader
+	6, // 2: api.proto.v1.rpc.DownloadFileHeader.meta:type_name -> api.proto.v1.models.Meta
+	7, // 3: api.proto.v1.rpc.DownloadFileHeader.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	4, // 4: api.proto.v1.rpc.DownloadFileResponse.header:type_name -> api.proto.v1.rpc.DownloadFileHeader
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_file_transfer_proto_init() }
+func file_api_proto_v1_rpc_file_transfer_proto_init() {
+	if File_api_proto_v1_rpc_file_transfer_proto != nil {
+		return
+	}
+	file_api_proto_v1_rpc_file_transfer_proto_msgTypes[1].OneofWrappers = []any{
+		(*UploadFileRequest_Header)(nil),
+		(*UploadFileRequest_Chunk)(nil),
+	}
+	file_api_proto_v1_rpc_file_transfer_proto_msgTypes[5].OneofWrappers = []any{
+		(*DownloadFileResponse_Header)(nil),
+		(*DownloadFileResponse_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_file_transfer_proto_rawDesc), len(file_api_proto_v1_rpc_file_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_file_transfer_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_file_transfer_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_file_transfer_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_file_transfer_proto = out.File
+	file_api_proto_v1_rpc_file_transfer_proto_goTypes = nil
+	file_api_proto_v1_rpc_file_transfer_proto_depIdxs = nil
+}
@@ -0,0 +1,863 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/folders.proto
+
+package rpc
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Folder groups records of the user. Folders nest: parent_id is the containing folder, 0 at the root.
+type Folder struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId int32                  `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// name is set for accounts whose data the server encrypts.
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// encrypted is the name of a folder of a zero-knowledge account, encrypted by the client.
+	Encrypted     *models.EncryptedPayload `protobuf:"bytes,4,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	CreatedAt     string                   `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                   `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Folder) Reset() {
+	*x = Folder{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Folder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Folder) ProtoMessage() {}
+
+func (x *Folder) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Folder.ProtoReflect.Descriptor instead.
+func (*Folder) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Folder) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Folder) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *Folder) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Folder) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		return x.Encrypted
+	}
+	return nil
+}
+
+func (x *Folder) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Folder) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CreateFolderRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ParentId int32                  `protobuf:"varint,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Types that are valid to be assigned to NameData:
+	//
+	//	*CreateFolderRequest_Name
+	//	*CreateFolderRequest_Encrypted
+	NameData      isCreateFolderRequest_NameData `protobuf_oneof:"name_data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderRequest) Reset() {
+	*x = CreateFolderRequest{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderRequest) ProtoMessage() {}
+
+func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderRequest.ProtoReflect.Descriptor instead.
+func (*CreateFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateFolderRequest) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CreateFolderRequest) GetNameData() isCreateFolderRequest_NameData {
+	if x != nil {
+		return x.NameData
+	}
+	return nil
+}
+
+func (x *CreateFolderRequest) GetName() string {
+	if x != nil {
+		if x, ok := x.NameData.(*CreateFolderRequest_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *CreateFolderRequest) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		if x, ok := x.NameData.(*CreateFolderRequest_Encrypted); ok {
+			return x.Encrypted
+		}
+	}
+	return nil
+}
+
+type isCreateFolderRequest_NameData interface {
+	isCreateFolderRequest_NameData()
+}
+
+type CreateFolderRequest_Name struct {
+	Name string `protobuf:"bytes,2,opt,name=name,proto3,oneof"`
+}
+
+type CreateFolderRequest_Encrypted struct {
+	Encrypted *models.EncryptedPayload `protobuf:"bytes,3,opt,name=encrypted,proto3,oneof"`
+}
+
+func (*CreateFolderRequest_Name) isCreateFolderRequest_NameData() {}
+
+func (*CreateFolderRequest_Encrypted) isCreateFolderRequest_NameData() {}
+
+type CreateFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *Folder                `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderResponse) Reset() {
+	*x = CreateFolderResponse{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderResponse) ProtoMessage() {}
+
+func (x *CreateFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderResponse.ProtoReflect.Descriptor instead.
+func (*CreateFolderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateFolderResponse) GetFolder() *Folder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+type ListFoldersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFoldersRequest) Reset() {
+	*x = ListFoldersRequest{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFoldersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFoldersRequest) ProtoMessage() {}
+
+func (x *ListFoldersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFoldersRequest.ProtoReflect.Descriptor instead.
+func (*ListFoldersRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{3}
+}
+
+type ListFoldersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Folders of the user, oldest first.
+	Folders       []*Folder `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFoldersResponse) Reset() {
+	*x = ListFoldersResponse{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFoldersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFoldersResponse) ProtoMessage() {}
+
+func (x *ListFoldersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFoldersResponse.ProtoReflect.Descriptor instead.
+func (*ListFoldersResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListFoldersResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+type RenameFolderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to NameData:
+	//
+	//	*RenameFolderRequest_Name
+	//	*RenameFolderRequest_Encrypted
+	NameData      isRenameFolderRequest_NameData `protobuf_oneof:"name_data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderRequest) Reset() {
+	*x = RenameFolderRequest{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderRequest) ProtoMessage() {}
+
+func (x *RenameFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderRequest.ProtoReflect.Descriptor instead.
+func (*RenameFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RenameFolderRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RenameFolderRequest) GetNameData() isRenameFolderRequest_NameData {
+	if x != nil {
+		return x.NameData
+	}
+	return nil
+}
+
+func (x *RenameFolderRequest) GetName() string {
+	if x != nil {
+		if x, ok := x.NameData.(*RenameFolderRequest_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *RenameFolderRequest) GetEncrypted() *models.EncryptedPayload {
+	if x != nil {
+		if x, ok := x.NameData.(*RenameFolderRequest_Encrypted); ok {
+			return x.Encrypted
+		}
+	}
+	return nil
+}
+
+type isRenameFolderRequest_NameData interface {
+	isRenameFolderRequest_NameData()
+}
+
+type RenameFolderRequest_Name struct {
+	Name string `protobuf:"bytes,2,opt,name=name,proto3,oneof"`
+}
+
+type RenameFolderRequest_Encrypted struct {
+	Encrypted *models.EncryptedPayload `protobuf:"bytes,3,opt,name=encrypted,proto3,oneof"`
+}
+
+func (*RenameFolderRequest_Name) isRenameFolderRequest_NameData() {}
+
+func (*RenameFolderRequest_Encrypted) isRenameFolderRequest_NameData() {}
+
+type RenameFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderResponse) Reset() {
+	*x = RenameFolderResponse{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderResponse) ProtoMessage() {}
+
+func (x *RenameFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderResponse.ProtoReflect.Descriptor instead.
+func (*RenameFolderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RenameFolderResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// MoveFolderRequest moves a folder with its contents into parent_id, or to the root if it is 0.
+type MoveFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId      int32                  `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderRequest) Reset() {
+	*x = MoveFolderRequest{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderRequest) ProtoMessage() {}
+
+func (x *MoveFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderRequest.ProtoReflect.Descriptor instead.
+func (*MoveFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MoveFolderRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MoveFolderRequest) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+type MoveFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderResponse) Reset() {
+	*x = MoveFolderResponse{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderResponse) ProtoMessage() {}
+
+func (x *MoveFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderResponse.ProtoReflect.Descriptor instead.
+func (*MoveFolderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MoveFolderResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// DeleteFolderRequest deletes a folder; its records and subfolders move to its parent.
+type DeleteFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderRequest) Reset() {
+	*x = DeleteFolderRequest{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderRequest) ProtoMessage() {}
+
+func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteFolderRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderResponse) Reset() {
+	*x = DeleteFolderResponse{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderResponse) ProtoMessage() {}
+
+func (x *DeleteFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFolderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteFolderResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// MoveRecordsRequest moves records into folder_id, or out of any folder if it is 0.
+// Either all records are moved or none.
+type MoveRecordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int32                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	FolderId      int32                  `protobuf:"varint,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveRecordsRequest) Reset() {
+	*x = MoveRecordsRequest{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRecordsRequest) ProtoMessage() {}
+
+func (x *MoveRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRecordsRequest.ProtoReflect.Descriptor instead.
+func (*MoveRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MoveRecordsRequest) GetIds() []int32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *MoveRecordsRequest) GetFolderId() int32 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+type MoveRecordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveRecordsResponse) Reset() {
+	*x = MoveRecordsResponse{}
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRecordsResponse) ProtoMessage() {}
+
+func (x *MoveRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_folders_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRecordsResponse.ProtoReflect.Descriptor instead.
+func (*MoveRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_folders_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MoveRecordsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_api_proto_v1_rpc_folders_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_folders_proto_rawDesc = "" +
+	"\n" +
+	"\x1eapi/proto/v1/rpc/folders.proto\x12\x10api.proto.v1.rpc\x1a+api/proto/v1/models/encrypted_payload.proto\"\xcc\x01\n" +
+	"\x06Folder\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x05R\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12C\n" +
+	"\tencrypted\x18\x04 \x01(\v2%.api.proto.v1.models.EncryptedPayloadR\tencrypted\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"\x9c\x01\n" +
+	"\x13CreateFolderRequest\x12\x1b\n" +
+	"\tparent_id\x18\x01 \x01(\x05R\bparentId\x12\x14\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x12E\n" +
+	"\tencrypted\x18\x03 \x01(\v2%.api.proto.v1.models.EncryptedPayloadH\x00R\tencryptedB\v\n" +
+	"\tname_data\"H\n" +
+	"\x14CreateFolderResponse\x120\n" +
+	"\x06folder\x18\x01 \x01(\v2\x18.api.proto.v1.rpc.FolderR\x06folder\"\x14\n" +
+	"\x12ListFoldersRequest\"I\n" +
+	"\x13ListFoldersResponse\x122\n" +
+	"\afolders\x18\x01 \x03(\v2\x18.api.proto.v1.rpc.FolderR\afolders\"\x8f\x01\n" +
+	"\x13RenameFolderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x12E\n" +
+	"\tencrypted\x18\x03 \x01(\v2%.api.proto.v1.models.EncryptedPayloadH\x00R\tencryptedB\v\n" +
+	"\tname_data\"0\n" +
+	"\x14RenameFolderResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"@\n" +
+	"\x11MoveFolderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x05R\bparentId\".\n" +
+	"\x12MoveFolderResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"%\n" +
+	"\x13DeleteFolderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"0\n" +
+	"\x14DeleteFolderResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"C\n" +
+	"\x12MoveRecordsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x05R\x03ids\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\x05R\bfolderId\"/\n" +
+	"\x13MoveRecordsResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessageB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_folders_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_folders_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_folders_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_folders_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_folders_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_folders_proto_rawDesc), len(file_api_proto_v1_rpc_folders_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_folders_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_folders_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_api_proto_v1_rpc_folders_proto_goTypes = []any{
+	(*Folder)(nil),                  // 0: api.proto.v1.rpc.Folder
+	(*CreateFolderRequest)(nil),     // 1: api.proto.v1.rpc.CreateFolderRequest
+	(*CreateFolderResponse)(nil),    // 2: api.proto.v1.rpc.CreateFolderResponse
+	(*ListFoldersRequest)(nil),      // 3: api.proto.v1.rpc.ListFoldersRequest
+	(*ListFoldersResponse)(nil),     // 4: api.proto.v1.rpc.ListFoldersResponse
+	(*RenameFolderRequest)(nil),     // 5: api.proto.v1.rpc.RenameFolderRequest
+	(*RenameFolderResponse)(nil),    // 6: api.proto.v1.rpc.RenameFolderResponse
+	(*MoveFolderRequest)(nil),       // 7: api.proto.v1.rpc.MoveFolderRequest
+	(*MoveFolderResponse)(nil),      // 8: api.proto.v1.rpc.MoveFolderResponse
+	(*DeleteFolderRequest)(nil),     // 9: api.proto.v1.rpc.DeleteFolderRequest
+	(*DeleteFolderResponse)(nil),    // 10: api.proto.v1.rpc.DeleteFolderResponse
+	(*MoveRecordsRequest)(nil),      // 11: api.proto.v1.rpc.MoveRecordsRequest
+	(*MoveRecordsResponse)(nil),     // 12: api.proto.v1.rpc.MoveRecordsResponse
+	(*models.EncryptedPayload)(nil), // 13: api.proto.v1.models.EncryptedPayload
+}
+var file_api_proto_v1_rpc_folders_proto_depIdxs = []int32{
+	13, // 0: api.proto.v1.rpc.Folder.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	13, // 1: api.proto.v1.rpc.CreateFolderRequest.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	0,  // 2: api.proto.v1.rpc.CreateFolderResponse.folder:type_name -> api.proto.v1.rpc.Folder
+	0,  // 3: api.proto.v1.rpc.ListFoldersResponse.folders:type_name -> api.proto.v1.rpc.Folder
+	13, // 4: api.proto.v1.rpc.RenameFolderRequest.encrypted:type_name -> api.proto.v1.models.EncryptedPayload
+	5,  // [5:5] is the sub-list for method output_type
+	5,  // [5:5] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_folders_proto_init() }
+func file_api_proto_v1_rpc_folders_proto_init() {
+	if File_api_proto_v1_rpc_folders_proto != nil {
+		return
+	}
+	file_api_proto_v1_rpc_folders_proto_msgTypes[1].OneofWrappers = []any{
+		(*CreateFolderRequest_Name)(nil),
+		(*CreateFolderRequest_Encrypted)(nil),
+	}
+	file_api_proto_v1_rpc_folders_proto_msgTypes[5].OneofWrappers = []any{
+		(*RenameFolderRequest_Name)(nil),
+		(*RenameFolderRequest_Encrypted)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_folders_proto_rawDesc), len(file_api_proto_v1_rpc_folders_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_folders_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_folders_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_folders_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_folders_proto = out.File
+	file_api_proto_v1_rpc_folders_proto_goTypes = nil
+	file_api_proto_v1_rpc_folders_proto_depIdxs = nil
+}
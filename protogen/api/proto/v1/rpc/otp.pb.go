@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/otp.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GenerateOTPRequest asks for the codes of an OTP record.
+type GenerateOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateOTPRequest) Reset() {
+	*x = GenerateOTPRequest{}
+	mi := &file_api_proto_v1_rpc_otp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateOTPRequest) ProtoMessage() {}
+
+func (x *GenerateOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_otp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateOTPRequest.ProtoReflect.Descriptor instead.
+func (*GenerateOTPRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_otp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateOTPRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GenerateOTPResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code is the current code.
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// next_code is the code that follows when code expires.
+	NextCode string `protobuf:"bytes,2,opt,name=next_code,json=nextCode,proto3" json:"next_code,omitempty"`
+	// expires_in is the number of seconds code stays valid.
+	ExpiresIn int32 `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// period is how long every code is valid in seconds.
+	Period        int32 `protobuf:"varint,4,opt,name=period,proto3" json:"period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateOTPResponse) Reset() {
+	*x = GenerateOTPResponse{}
+	mi := &file_api_proto_v1_rpc_otp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateOTPResponse) ProtoMessage() {}
+
+func (x *GenerateOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_otp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateOTPResponse.ProtoReflect.Descriptor instead.
+func (*GenerateOTPResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_otp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateOTPResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *GenerateOTPResponse) GetNextCode() string {
+	if x != nil {
+		return x.NextCode
+	}
+	return ""
+}
+
+func (x *GenerateOTPResponse) GetExpiresIn() int32 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *GenerateOTPResponse) GetPeriod() int32 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+var File_api_proto_v1_rpc_otp_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_otp_proto_rawDesc = "" +
+	"\n" +
+	"\x1aapi/proto/v1/rpc/otp.proto\x12\x10api.proto.v1.rpc\"$\n" +
+	"\x12GenerateOTPRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"}\n" +
+	"\x13GenerateOTPResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1b\n" +
+	"\tnext_code\x18\x02 \x01(\tR\bnextCode\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x05R\texpiresIn\x12\x16\n" +
+	"\x06period\x18\x04 \x01(\x05R\x06periodB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_otp_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_otp_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_otp_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_otp_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_otp_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_otp_proto_rawDesc), len(file_api_proto_v1_rpc_otp_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_otp_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_otp_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_proto_v1_rpc_otp_proto_goTypes = []any{
+	(*GenerateOTPRequest)(nil),  // 0: api.proto.v1.rpc.GenerateOTPRequest
+	(*GenerateOTPResponse)(nil), // 1: api.proto.v1.rpc.GenerateOTPResponse
+}
+var file_api_proto_v1_rpc_otp_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_otp_proto_init() }
+func file_api_proto_v1_rpc_otp_proto_init() {
+	if File_api_proto_v1_rpc_otp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_otp_proto_rawDesc), len(file_api_proto_v1_rpc_otp_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_otp_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_otp_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_otp_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_otp_proto = out.File
+	file_api_proto_v1_rpc_otp_proto_goTypes = nil
+	file_api_proto_v1_rpc_otp_proto_depIdxs = nil
+}
@@ -0,0 +1,393 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/search.proto
+
+package rpc
+
+import (
+	common "github.com/apetsko/gophkeeper/protogen/api/proto/v1/common"
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// query is matched against the metadata of the records, as words and by similarity, so
+	// misspelled words are found too.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// types keeps only records of the given types.
+	Types []common.DataType `protobuf:"varint,2,rep,packed,name=types,proto3,enum=api.proto.v1.common.DataType" json:"types,omitempty"`
+	// limit is the number of results, 20 by default and at most 100.
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_search_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetTypes() []common.DataType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// SearchRange is a matched part of a field value, in Unicode code points; end is exclusive.
+type SearchRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int32                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRange) Reset() {
+	*x = SearchRange{}
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRange) ProtoMessage() {}
+
+func (x *SearchRange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRange.ProtoReflect.Descriptor instead.
+func (*SearchRange) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_search_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchRange) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *SearchRange) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+// SearchHighlight is a metadata field of a result that matches the query.
+type SearchHighlight struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// field is the JSON name of the field, with nested fields separated by dots.
+	Field         string         `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Value         string         `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Ranges        []*SearchRange `protobuf:"bytes,3,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHighlight) Reset() {
+	*x = SearchHighlight{}
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHighlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHighlight) ProtoMessage() {}
+
+func (x *SearchHighlight) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHighlight.ProtoReflect.Descriptor instead.
+func (*SearchHighlight) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_search_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchHighlight) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SearchHighlight) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *SearchHighlight) GetRanges() []*SearchRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+type SearchResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Record *models.Record         `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// score ranks the results; a higher score is a better match.
+	Score         float64            `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Highlights    []*SearchHighlight `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_search_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchResult) GetRecord() *models.Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchResult) GetHighlights() []*SearchHighlight {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+type SearchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Results, best match first.
+	Results       []*SearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_search_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_search_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SearchResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_api_proto_v1_rpc_search_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_search_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapi/proto/v1/rpc/search.proto\x12\x10api.proto.v1.rpc\x1a\x1fapi/proto/v1/common/enums.proto\x1a api/proto/v1/models/record.proto\"p\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x123\n" +
+	"\x05types\x18\x02 \x03(\x0e2\x1d.api.proto.v1.common.DataTypeR\x05types\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"5\n" +
+	"\vSearchRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x05R\x03end\"t\n" +
+	"\x0fSearchHighlight\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x125\n" +
+	"\x06ranges\x18\x03 \x03(\v2\x1d.api.proto.v1.rpc.SearchRangeR\x06ranges\"\x9c\x01\n" +
+	"\fSearchResult\x123\n" +
+	"\x06record\x18\x01 \x01(\v2\x1b.api.proto.v1.models.RecordR\x06record\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12A\n" +
+	"\n" +
+	"highlights\x18\x03 \x03(\v2!.api.proto.v1.rpc.SearchHighlightR\n" +
+	"highlights\"J\n" +
+	"\x0eSearchResponse\x128\n" +
+	"\aresults\x18\x01 \x03(\v2\x1e.api.proto.v1.rpc.SearchResultR\aresultsB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_search_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_search_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_search_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_search_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_search_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_search_proto_rawDesc), len(file_api_proto_v1_rpc_search_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_search_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_search_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_proto_v1_rpc_search_proto_goTypes = []any{
+	(*SearchRequest)(nil),   // 0: api.proto.v1.rpc.SearchRequest
+	(*SearchRange)(nil),     // 1: api.proto.v1.rpc.SearchRange
+	(*SearchHighlight)(nil), // 2: api.proto.v1.rpc.SearchHighlight
+	(*SearchResult)(nil),    // 3: api.proto.v1.rpc.SearchResult
+	(*SearchResponse)(nil),  // 4: api.proto.v1.rpc.SearchResponse
+	(common.DataType)(0),    // 5: api.proto.v1.common.DataType
+	(*models.Record)(nil),   // 6: api.proto.v1.models.Record
+}
+var file_api_proto_v1_rpc_search_proto_depIdxs = []int32{
+	5, // 0: api.proto.v1.rpc.SearchRequest.types:type_name -> api.proto.v1.common.DataType
+	1, // 1: api.proto.v1.rpc.SearchHighlight.ranges:type_name -> api.proto.v1.rpc.SearchRange
+	6, // 2: api.proto.v1.rpc.SearchResult.record:type_name -> api.proto.v1.models.Record
+	2, // 3: api.proto.v1.rpc.SearchResult.highlights:type_name -> api.proto.v1.rpc.SearchHighlight
+	3, // 4: api.proto.v1.rpc.SearchResponse.results:type_name -> api.proto.v1.rpc.SearchResult
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_search_proto_init() }
+func file_api_proto_v1_rpc_search_proto_init() {
+	if File_api_proto_v1_rpc_search_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_search_proto_rawDesc), len(file_api_proto_v1_rpc_search_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_search_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_search_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_search_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_search_proto = out.File
+	file_api_proto_v1_rpc_search_proto_goTypes = nil
+	file_api_proto_v1_rpc_search_proto_depIdxs = nil
+}
@@ -0,0 +1,432 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/trash.proto
+
+package rpc
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TrashedRecord is a record deleted by DataDelete. It stays restorable until purge_at,
+// when it is deleted permanently together with its versions and files.
+type TrashedRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Record        *models.Record         `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	DeletedAt     string                 `protobuf:"bytes,2,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	PurgeAt       string                 `protobuf:"bytes,3,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashedRecord) Reset() {
+	*x = TrashedRecord{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashedRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashedRecord) ProtoMessage() {}
+
+func (x *TrashedRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashedRecord.ProtoReflect.Descriptor instead.
+func (*TrashedRecord) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TrashedRecord) GetRecord() *models.Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *TrashedRecord) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+func (x *TrashedRecord) GetPurgeAt() string {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return ""
+}
+
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{1}
+}
+
+type ListTrashResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Trashed records, most recently deleted first.
+	Records       []*TrashedRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	Count         int32            `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListTrashResponse) GetRecords() []*TrashedRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListTrashResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type RestoreFromTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromTrashRequest) Reset() {
+	*x = RestoreFromTrashRequest{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromTrashRequest) ProtoMessage() {}
+
+func (x *RestoreFromTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromTrashRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFromTrashRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RestoreFromTrashRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreFromTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromTrashResponse) Reset() {
+	*x = RestoreFromTrashResponse{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromTrashResponse) ProtoMessage() {}
+
+func (x *RestoreFromTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromTrashResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFromTrashResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RestoreFromTrashResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// EmptyTrashRequest permanently deletes all trashed records of the user.
+type EmptyTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyTrashRequest) Reset() {
+	*x = EmptyTrashRequest{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyTrashRequest) ProtoMessage() {}
+
+func (x *EmptyTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyTrashRequest.ProtoReflect.Descriptor instead.
+func (*EmptyTrashRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{5}
+}
+
+type EmptyTrashResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// The number of deleted records.
+	Purged        int32 `protobuf:"varint,2,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyTrashResponse) Reset() {
+	*x = EmptyTrashResponse{}
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyTrashResponse) ProtoMessage() {}
+
+func (x *EmptyTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_trash_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyTrashResponse.ProtoReflect.Descriptor instead.
+func (*EmptyTrashResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_trash_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EmptyTrashResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EmptyTrashResponse) GetPurged() int32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+var File_api_proto_v1_rpc_trash_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_trash_proto_rawDesc = "" +
+	"\n" +
+	"\x1capi/proto/v1/rpc/trash.proto\x12\x10api.proto.v1.rpc\x1a api/proto/v1/models/record.proto\"~\n" +
+	"\rTrashedRecord\x123\n" +
+	"\x06record\x18\x01 \x01(\v2\x1b.api.proto.v1.models.RecordR\x06record\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\x02 \x01(\tR\tdeletedAt\x12\x19\n" +
+	"\bpurge_at\x18\x03 \x01(\tR\apurgeAt\"\x12\n" +
+	"\x10ListTrashRequest\"d\n" +
+	"\x11ListTrashResponse\x129\n" +
+	"\arecords\x18\x01 \x03(\v2\x1f.api.proto.v1.rpc.TrashedRecordR\arecords\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\")\n" +
+	"\x17RestoreFromTrashRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"4\n" +
+	"\x18RestoreFromTrashResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x13\n" +
+	"\x11EmptyTrashRequest\"F\n" +
+	"\x12EmptyTrashResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x16\n" +
+	"\x06purged\x18\x02 \x01(\x05R\x06purgedB9Z7github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpcb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_trash_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_trash_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_trash_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_trash_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_trash_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_trash_proto_rawDesc), len(file_api_proto_v1_rpc_trash_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_trash_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_trash_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_api_proto_v1_rpc_trash_proto_goTypes = []any{
+	(*TrashedRecord)(nil),            // 0: api.proto.v1.rpc.TrashedRecord
+	(*ListTrashRequest)(nil),         // 1: api.proto.v1.rpc.ListTrashRequest
+	(*ListTrashResponse)(nil),        // 2: api.proto.v1.rpc.ListTrashResponse
+	(*RestoreFromTrashRequest)(nil),  // 3: api.proto.v1.rpc.RestoreFromTrashRequest
+	(*RestoreFromTrashResponse)(nil), // 4: api.proto.v1.rpc.RestoreFromTrashResponse
+	(*EmptyTrashRequest)(nil),        // 5: api.proto.v1.rpc.EmptyTrashRequest
+	(*EmptyTrashResponse)(nil),       // 6: api.proto.v1.rpc.EmptyTrashResponse
+	(*models.Record)(nil),            // 7: api.proto.v1.models.Record
+}
+var file_api_proto_v1_rpc_trash_proto_depIdxs = []int32{
+	7, // 0: api.proto.v1.rpc.TrashedRecord.record:type_name -> api.proto.v1.models.Record
+	0, // 1: api.proto.v1.rpc.ListTrashResponse.records:type_name -> api.proto.v1.rpc.TrashedRecord
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_trash_proto_init() }
+func file_api_proto_v1_rpc_trash_proto_init() {
+	if File_api_proto_v1_rpc_trash_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_trash_proto_rawDesc), len(file_api_proto_v1_rpc_trash_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_trash_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_trash_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_trash_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_trash_proto = out.File
+	file_api_proto_v1_rpc_trash_proto_goTypes = nil
+	file_api_proto_v1_rpc_trash_proto_depIdxs = nil
+}
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/user/change_password.proto
+
+package user
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChangePasswordRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OldPassword string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	// Zero-knowledge accounts only: the client's new KDF parameters and the master key
+	// re-wrapped with the key derived from the new password.
+	Kdf              *models.KdfParams `protobuf:"bytes,3,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	WrappedMasterKey []byte            `protobuf:"bytes,4,opt,name=wrapped_master_key,json=wrappedMasterKey,proto3" json:"wrapped_master_key,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_api_proto_v1_rpc_user_change_password_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_user_change_password_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_user_change_password_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetKdf() *models.KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) GetWrappedMasterKey() []byte {
+	if x != nil {
+		return x.WrappedMasterKey
+	}
+	return nil
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_api_proto_v1_rpc_user_change_password_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_user_change_password_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_user_change_password_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ChangePasswordResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_api_proto_v1_rpc_user_change_password_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_user_change_password_proto_rawDesc = "" +
+	"\n" +
+	"+api/proto/v1/rpc/user/change_password.proto\x12\x15api.proto.v1.rpc.user\x1a\x1dapi/proto/v1/models/kdf.proto\"\xbd\x01\n" +
+	"\x15ChangePasswordRequest\x12!\n" +
+	"\fold_password\x18\x01 \x01(\tR\voldPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\x120\n" +
+	"\x03kdf\x18\x03 \x01(\v2\x1e.api.proto.v1.models.KdfParamsR\x03kdf\x12,\n" +
+	"\x12wrapped_master_key\x18\x04 \x01(\fR\x10wrappedMasterKey\"2\n" +
+	"\x16ChangePasswordResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessageB>Z<github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/userb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_user_change_password_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_user_change_password_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_user_change_password_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_user_change_password_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_user_change_password_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_user_change_password_proto_rawDesc), len(file_api_proto_v1_rpc_user_change_password_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_user_change_password_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_user_change_password_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_proto_v1_rpc_user_change_password_proto_goTypes = []any{
+	(*ChangePasswordRequest)(nil),  // 0: api.proto.v1.rpc.user.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil), // 1: api.proto.v1.rpc.user.ChangePasswordResponse
+	(*models.KdfParams)(nil),       // 2: api.proto.v1.models.KdfParams
+}
+var file_api_proto_v1_rpc_user_change_password_proto_depIdxs = []int32{
+	2, // 0: api.proto.v1.rpc.user.ChangePasswordRequest.kdf:type_name -> api.proto.v1.models.KdfParams
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_user_change_password_proto_init() }
+func file_api_proto_v1_rpc_user_change_password_proto_init() {
+	if File_api_proto_v1_rpc_user_change_password_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_user_change_password_proto_rawDesc), len(file_api_proto_v1_rpc_user_change_password_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_user_change_password_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_user_change_password_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_user_change_password_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_user_change_password_proto = out.File
+	file_api_proto_v1_rpc_user_change_password_proto_goTypes = nil
+	file_api_proto_v1_rpc_user_change_password_proto_depIdxs = nil
+}
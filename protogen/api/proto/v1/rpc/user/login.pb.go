@@ -7,6 +7,7 @@
 package user
 
 import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -74,10 +75,21 @@ func (x *LoginRequest) GetPassword() string {
 }
 
 type LoginResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username         string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Token            string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	ZeroKnowledge    bool                   `protobuf:"varint,4,opt,name=zero_knowledge,json=zeroKnowledge,proto3" json:"zero_knowledge,omitempty"`
+	Kdf              *models.KdfParams      `protobuf:"bytes,5,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	WrappedMasterKey []byte                 `protobuf:"bytes,6,opt,name=wrapped_master_key,json=wrappedMasterKey,proto3" json:"wrapped_master_key,omitempty"`
+	// Set when the account has two-factor authentication enabled: token is empty, and
+	// two_factor_token must be sent with a TOTP code to LoginTOTP.
+	TwoFactorRequired bool   `protobuf:"varint,7,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	TwoFactorToken    string `protobuf:"bytes,8,opt,name=two_factor_token,json=twoFactorToken,proto3" json:"two_factor_token,omitempty"`
+	// Exchanged for a new access token by Refresh once token expires.
+	RefreshToken string `protobuf:"bytes,9,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Lifetime of the access token in seconds.
+	ExpiresIn     int64 `protobuf:"varint,10,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,18 +145,76 @@ func (x *LoginResponse) GetToken() string {
 	return ""
 }
 
+func (x *LoginResponse) GetZeroKnowledge() bool {
+	if x != nil {
+		return x.ZeroKnowledge
+	}
+	return false
+}
+
+func (x *LoginResponse) GetKdf() *models.KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *LoginResponse) GetWrappedMasterKey() []byte {
+	if x != nil {
+		return x.WrappedMasterKey
+	}
+	return nil
+}
+
+func (x *LoginResponse) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *LoginResponse) GetTwoFactorToken() string {
+	if x != nil {
+		return x.TwoFactorToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
 var File_api_proto_v1_rpc_user_login_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_rpc_user_login_proto_rawDesc = "" +
 	"\n" +
-	"!api/proto/v1/rpc/user/login.proto\x12\x15api.proto.v1.rpc.user\"F\n" +
+	"!api/proto/v1/rpc/user/login.proto\x12\x15api.proto.v1.rpc.user\x1a\x1dapi/proto/v1/models/kdf.proto\"F\n" +
 	"\fLoginRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"Q\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xf6\x02\n" +
 	"\rLoginResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
-	"\x05token\x18\x03 \x01(\tR\x05tokenB>Z<github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/userb\x06proto3"
+	"\x05token\x18\x03 \x01(\tR\x05token\x12%\n" +
+	"\x0ezero_knowledge\x18\x04 \x01(\bR\rzeroKnowledge\x120\n" +
+	"\x03kdf\x18\x05 \x01(\v2\x1e.api.proto.v1.models.KdfParamsR\x03kdf\x12,\n" +
+	"\x12wrapped_master_key\x18\x06 \x01(\fR\x10wrappedMasterKey\x12.\n" +
+	"\x13two_factor_required\x18\a \x01(\bR\x11twoFactorRequired\x12(\n" +
+	"\x10two_factor_token\x18\b \x01(\tR\x0etwoFactorToken\x12#\n" +
+	"\rrefresh_token\x18\t \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\n" +
+	" \x01(\x03R\texpiresInB>Z<github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/userb\x06proto3"
 
 var (
 	file_api_proto_v1_rpc_user_login_proto_rawDescOnce sync.Once
@@ -160,15 +230,17 @@ func file_api_proto_v1_rpc_user_login_proto_rawDescGZIP() []byte {
 
 var file_api_proto_v1_rpc_user_login_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_proto_v1_rpc_user_login_proto_goTypes = []any{
-	(*LoginRequest)(nil),  // 0: api.proto.v1.rpc.user.LoginRequest
-	(*LoginResponse)(nil), // 1: api.proto.v1.rpc.user.LoginResponse
+	(*LoginRequest)(nil),     // 0: api.proto.v1.rpc.user.LoginRequest
+	(*LoginResponse)(nil),    // 1: api.proto.v1.rpc.user.LoginResponse
+	(*models.KdfParams)(nil), // 2: api.proto.v1.models.KdfParams
 }
 var file_api_proto_v1_rpc_user_login_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: api.proto.v1.rpc.user.LoginResponse.kdf:type_name -> api.proto.v1.models.KdfParams
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_rpc_user_login_proto_init() }
@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: api/proto/v1/rpc/user/prelogin.proto
+
+package user
+
+import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PreLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreLoginRequest) Reset() {
+	*x = PreLoginRequest{}
+	mi := &file_api_proto_v1_rpc_user_prelogin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreLoginRequest) ProtoMessage() {}
+
+func (x *PreLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_user_prelogin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreLoginRequest.ProtoReflect.Descriptor instead.
+func (*PreLoginRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_user_prelogin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PreLoginRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type PreLoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ZeroKnowledge bool                   `protobuf:"varint,1,opt,name=zero_knowledge,json=zeroKnowledge,proto3" json:"zero_knowledge,omitempty"`
+	Kdf           *models.KdfParams      `protobuf:"bytes,2,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreLoginResponse) Reset() {
+	*x = PreLoginResponse{}
+	mi := &file_api_proto_v1_rpc_user_prelogin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreLoginResponse) ProtoMessage() {}
+
+func (x *PreLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rpc_user_prelogin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreLoginResponse.ProtoReflect.Descriptor instead.
+func (*PreLoginResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rpc_user_prelogin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PreLoginResponse) GetZeroKnowledge() bool {
+	if x != nil {
+		return x.ZeroKnowledge
+	}
+	return false
+}
+
+func (x *PreLoginResponse) GetKdf() *models.KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+var File_api_proto_v1_rpc_user_prelogin_proto protoreflect.FileDescriptor
+
+const file_api_proto_v1_rpc_user_prelogin_proto_rawDesc = "" +
+	"\n" +
+	"$api/proto/v1/rpc/user/prelogin.proto\x12\x15api.proto.v1.rpc.user\x1a\x1dapi/proto/v1/models/kdf.proto\"-\n" +
+	"\x0fPreLoginRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"k\n" +
+	"\x10PreLoginResponse\x12%\n" +
+	"\x0ezero_knowledge\x18\x01 \x01(\bR\rzeroKnowledge\x120\n" +
+	"\x03kdf\x18\x02 \x01(\v2\x1e.api.proto.v1.models.KdfParamsR\x03kdfB>Z<github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/userb\x06proto3"
+
+var (
+	file_api_proto_v1_rpc_user_prelogin_proto_rawDescOnce sync.Once
+	file_api_proto_v1_rpc_user_prelogin_proto_rawDescData []byte
+)
+
+func file_api_proto_v1_rpc_user_prelogin_proto_rawDescGZIP() []byte {
+	file_api_proto_v1_rpc_user_prelogin_proto_rawDescOnce.Do(func() {
+		file_api_proto_v1_rpc_user_prelogin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_user_prelogin_proto_rawDesc), len(file_api_proto_v1_rpc_user_prelogin_proto_rawDesc)))
+	})
+	return file_api_proto_v1_rpc_user_prelogin_proto_rawDescData
+}
+
+var file_api_proto_v1_rpc_user_prelogin_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_proto_v1_rpc_user_prelogin_proto_goTypes = []any{
+	(*PreLoginRequest)(nil),  // 0: api.proto.v1.rpc.user.PreLoginRequest
+	(*PreLoginResponse)(nil), // 1: api.proto.v1.rpc.user.PreLoginResponse
+	(*models.KdfParams)(nil), // 2: api.proto.v1.models.KdfParams
+}
+var file_api_proto_v1_rpc_user_prelogin_proto_depIdxs = []int32{
+	2, // 0: api.proto.v1.rpc.user.PreLoginResponse.kdf:type_name -> api.proto.v1.models.KdfParams
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_v1_rpc_user_prelogin_proto_init() }
+func file_api_proto_v1_rpc_user_prelogin_proto_init() {
+	if File_api_proto_v1_rpc_user_prelogin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rpc_user_prelogin_proto_rawDesc), len(file_api_proto_v1_rpc_user_prelogin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_v1_rpc_user_prelogin_proto_goTypes,
+		DependencyIndexes: file_api_proto_v1_rpc_user_prelogin_proto_depIdxs,
+		MessageInfos:      file_api_proto_v1_rpc_user_prelogin_proto_msgTypes,
+	}.Build()
+	File_api_proto_v1_rpc_user_prelogin_proto = out.File
+	file_api_proto_v1_rpc_user_prelogin_proto_goTypes = nil
+	file_api_proto_v1_rpc_user_prelogin_proto_depIdxs = nil
+}
@@ -7,6 +7,7 @@
 package user
 
 import (
+	models "github.com/apetsko/gophkeeper/protogen/api/proto/v1/models"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -22,11 +23,14 @@ const (
 )
 
 type SignupRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Username         string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password         string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	ZeroKnowledge    bool                   `protobuf:"varint,3,opt,name=zero_knowledge,json=zeroKnowledge,proto3" json:"zero_knowledge,omitempty"`
+	Kdf              *models.KdfParams      `protobuf:"bytes,4,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	WrappedMasterKey []byte                 `protobuf:"bytes,5,opt,name=wrapped_master_key,json=wrappedMasterKey,proto3" json:"wrapped_master_key,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *SignupRequest) Reset() {
@@ -73,11 +77,37 @@ func (x *SignupRequest) GetPassword() string {
 	return ""
 }
 
+func (x *SignupRequest) GetZeroKnowledge() bool {
+	if x != nil {
+		return x.ZeroKnowledge
+	}
+	return false
+}
+
+func (x *SignupRequest) GetKdf() *models.KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *SignupRequest) GetWrappedMasterKey() []byte {
+	if x != nil {
+		return x.WrappedMasterKey
+	}
+	return nil
+}
+
 type SignupResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	ZeroKnowledge bool                   `protobuf:"varint,4,opt,name=zero_knowledge,json=zeroKnowledge,proto3" json:"zero_knowledge,omitempty"`
+	// Exchanged for a new access token by Refresh once token expires.
+	RefreshToken string `protobuf:"bytes,5,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Lifetime of the access token in seconds.
+	ExpiresIn     int64 `protobuf:"varint,6,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,18 +163,46 @@ func (x *SignupResponse) GetToken() string {
 	return ""
 }
 
+func (x *SignupResponse) GetZeroKnowledge() bool {
+	if x != nil {
+		return x.ZeroKnowledge
+	}
+	return false
+}
+
+func (x *SignupResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *SignupResponse) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
 var File_api_proto_v1_rpc_user_signup_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_rpc_user_signup_proto_rawDesc = "" +
 	"\n" +
-	"\"api/proto/v1/rpc/user/signup.proto\x12\x15api.proto.v1.rpc.user\"G\n" +
+	"\"api/proto/v1/rpc/user/signup.proto\x12\x15api.proto.v1.rpc.user\x1a\x1dapi/proto/v1/models/kdf.proto\"\xce\x01\n" +
 	"\rSignupRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"R\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12%\n" +
+	"\x0ezero_knowledge\x18\x03 \x01(\bR\rzeroKnowledge\x120\n" +
+	"\x03kdf\x18\x04 \x01(\v2\x1e.api.proto.v1.models.KdfParamsR\x03kdf\x12,\n" +
+	"\x12wrapped_master_key\x18\x05 \x01(\fR\x10wrappedMasterKey\"\xbd\x01\n" +
 	"\x0eSignupResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
-	"\x05token\x18\x03 \x01(\tR\x05tokenB>Z<github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/userb\x06proto3"
+	"\x05token\x18\x03 \x01(\tR\x05token\x12%\n" +
+	"\x0ezero_knowledge\x18\x04 \x01(\bR\rzeroKnowledge\x12#\n" +
+	"\rrefresh_token\x18\x05 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x06 \x01(\x03R\texpiresInB>Z<github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/userb\x06proto3"
 
 var (
 	file_api_proto_v1_rpc_user_signup_proto_rawDescOnce sync.Once
@@ -160,15 +218,17 @@ func file_api_proto_v1_rpc_user_signup_proto_rawDescGZIP() []byte {
 
 var file_api_proto_v1_rpc_user_signup_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_api_proto_v1_rpc_user_signup_proto_goTypes = []any{
-	(*SignupRequest)(nil),  // 0: api.proto.v1.rpc.user.SignupRequest
-	(*SignupResponse)(nil), // 1: api.proto.v1.rpc.user.SignupResponse
+	(*SignupRequest)(nil),    // 0: api.proto.v1.rpc.user.SignupRequest
+	(*SignupResponse)(nil),   // 1: api.proto.v1.rpc.user.SignupResponse
+	(*models.KdfParams)(nil), // 2: api.proto.v1.models.KdfParams
 }
 var file_api_proto_v1_rpc_user_signup_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: api.proto.v1.rpc.user.SignupRequest.kdf:type_name -> api.proto.v1.models.KdfParams
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_rpc_user_signup_proto_init() }
@@ -8,6 +8,7 @@ package v1
 
 import (
 	rpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	admin "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
 	user "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
@@ -27,53 +28,242 @@ var File_api_proto_v1_service_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_service_proto_rawDesc = "" +
 	"\n" +
-	"\x1aapi/proto/v1/service.proto\x12\fapi.proto.v1\x1a\x1bapi/proto/v1/rpc/ping.proto\x1a api/proto/v1/rpc/data_save.proto\x1a api/proto/v1/rpc/data_list.proto\x1a\"api/proto/v1/rpc/data_delete.proto\x1a api/proto/v1/rpc/data_view.proto\x1a!api/proto/v1/rpc/user/login.proto\x1a\"api/proto/v1/rpc/user/signup.proto\x1a\x1cgoogle/api/annotations.proto2\xf0\x05\n" +
+	"\x1aapi/proto/v1/service.proto\x12\fapi.proto.v1\x1a\x1bapi/proto/v1/rpc/ping.proto\x1a api/proto/v1/rpc/data_save.proto\x1a api/proto/v1/rpc/data_list.proto\x1a\x1dapi/proto/v1/rpc/search.proto\x1a\"api/proto/v1/rpc/data_delete.proto\x1a api/proto/v1/rpc/data_view.proto\x1a\x1aapi/proto/v1/rpc/otp.proto\x1a\"api/proto/v1/rpc/data_update.proto\x1a$api/proto/v1/rpc/data_versions.proto\x1a\x1capi/proto/v1/rpc/trash.proto\x1a\x1eapi/proto/v1/rpc/folders.proto\x1a$api/proto/v1/rpc/file_transfer.proto\x1a\x1eapi/proto/v1/rpc/uploads.proto\x1a!api/proto/v1/rpc/user/login.proto\x1a\"api/proto/v1/rpc/user/signup.proto\x1a$api/proto/v1/rpc/user/prelogin.proto\x1a+api/proto/v1/rpc/user/change_password.proto\x1a&api/proto/v1/rpc/user/two_factor.proto\x1a!api/proto/v1/rpc/user/token.proto\x1a#api/proto/v1/rpc/user/session.proto\x1a)api/proto/v1/rpc/admin/key_rotation.proto\x1a&api/proto/v1/rpc/admin/reconcile.proto\x1a\x1cgoogle/api/annotations.proto2\x81+\n" +
 	"\n" +
-	"GophKeeper\x12h\n" +
-	"\x05Login\x12#.api.proto.v1.rpc.user.LoginRequest\x1a$.api.proto.v1.rpc.user.LoginResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/login\x12l\n" +
+	"GophKeeper\x12t\n" +
+	"\bPreLogin\x12&.api.proto.v1.rpc.user.PreLoginRequest\x1a'.api.proto.v1.rpc.user.PreLoginResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/prelogin\x12h\n" +
+	"\x05Login\x12#.api.proto.v1.rpc.user.LoginRequest\x1a$.api.proto.v1.rpc.user.LoginResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/login\x12u\n" +
+	"\tLoginTOTP\x12'.api.proto.v1.rpc.user.LoginTOTPRequest\x1a$.api.proto.v1.rpc.user.LoginResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login/totp\x12v\n" +
+	"\aRefresh\x12%.api.proto.v1.rpc.user.RefreshRequest\x1a&.api.proto.v1.rpc.user.RefreshResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/token/refresh\x12l\n" +
+	"\x06Logout\x12$.api.proto.v1.rpc.user.LogoutRequest\x1a%.api.proto.v1.rpc.user.LogoutResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/logout\x12\x82\x01\n" +
+	"\fListSessions\x12*.api.proto.v1.rpc.user.ListSessionsRequest\x1a+.api.proto.v1.rpc.user.ListSessionsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/user/sessions\x12\x92\x01\n" +
+	"\rRevokeSession\x12+.api.proto.v1.rpc.user.RevokeSessionRequest\x1a,.api.proto.v1.rpc.user.RevokeSessionResponse\"&\x82\xd3\xe4\x93\x02 *\x1e/v1/user/sessions/{session_id}\x12\xb1\x01\n" +
+	"\x16RevokeAllOtherSessions\x124.api.proto.v1.rpc.user.RevokeAllOtherSessionsRequest\x1a5.api.proto.v1.rpc.user.RevokeAllOtherSessionsResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/user/sessions/revoke-others\x12l\n" +
 	"\x06Signup\x12$.api.proto.v1.rpc.user.SignupRequest\x1a%.api.proto.v1.rpc.user.SignupResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
-	"/v1/signup\x12W\n" +
+	"/v1/signup\x12\x8b\x01\n" +
+	"\x0eChangePassword\x12,.api.proto.v1.rpc.user.ChangePasswordRequest\x1a-.api.proto.v1.rpc.user.ChangePasswordResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/user/password\x12\x82\x01\n" +
+	"\n" +
+	"EnrollTOTP\x12(.api.proto.v1.rpc.user.EnrollTOTPRequest\x1a).api.proto.v1.rpc.user.EnrollTOTPResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/user/totp/enroll\x12\x86\x01\n" +
+	"\vConfirmTOTP\x12).api.proto.v1.rpc.user.ConfirmTOTPRequest\x1a*.api.proto.v1.rpc.user.ConfirmTOTPResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/user/totp/confirm\x12\x86\x01\n" +
+	"\vDisableTOTP\x12).api.proto.v1.rpc.user.DisableTOTPRequest\x1a*.api.proto.v1.rpc.user.DisableTOTPResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/user/totp/disable\x12W\n" +
 	"\x04Ping\x12\x1d.api.proto.v1.rpc.PingRequest\x1a\x1e.api.proto.v1.rpc.PingResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
 	"\x12\b/v1/ping\x12k\n" +
-	"\bDataSave\x12!.api.proto.v1.rpc.DataSaveRequest\x1a\".api.proto.v1.rpc.DataSaveResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/data/save\x12p\n" +
+	"\bDataSave\x12!.api.proto.v1.rpc.DataSaveRequest\x1a\".api.proto.v1.rpc.DataSaveResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/data/save\x12s\n" +
+	"\n" +
+	"DataUpdate\x12#.api.proto.v1.rpc.DataUpdateRequest\x1a$.api.proto.v1.rpc.DataUpdateResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\x1a\x0f/v1/data/update\x12p\n" +
 	"\n" +
 	"DataDelete\x12#.api.proto.v1.rpc.DataDeleteRequest\x1a$.api.proto.v1.rpc.DataDeleteResponse\"\x17\x82\xd3\xe4\x93\x02\x11*\x0f/v1/data/delete\x12h\n" +
-	"\bDataList\x12!.api.proto.v1.rpc.DataListRequest\x1a\".api.proto.v1.rpc.DataListResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/data/list\x12h\n" +
-	"\bDataView\x12!.api.proto.v1.rpc.DataViewRequest\x1a\".api.proto.v1.rpc.DataViewResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/data/viewB5Z3github.com/apetsko/gophkeeper/protogen/api/proto/v1b\x06proto3"
+	"\bDataList\x12!.api.proto.v1.rpc.DataListRequest\x1a\".api.proto.v1.rpc.DataListResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/data/list\x12d\n" +
+	"\x06Search\x12\x1f.api.proto.v1.rpc.SearchRequest\x1a .api.proto.v1.rpc.SearchResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/data/search\x12h\n" +
+	"\bDataView\x12!.api.proto.v1.rpc.DataViewRequest\x1a\".api.proto.v1.rpc.DataViewResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/data/view\x12p\n" +
+	"\vGenerateOTP\x12$.api.proto.v1.rpc.GenerateOTPRequest\x1a%.api.proto.v1.rpc.GenerateOTPResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/data/otp\x12Y\n" +
+	"\n" +
+	"UploadFile\x12#.api.proto.v1.rpc.UploadFileRequest\x1a$.api.proto.v1.rpc.UploadFileResponse(\x01\x12_\n" +
+	"\fDownloadFile\x12%.api.proto.v1.rpc.DownloadFileRequest\x1a&.api.proto.v1.rpc.DownloadFileResponse0\x01\x12u\n" +
+	"\fCreateUpload\x12%.api.proto.v1.rpc.CreateUploadRequest\x1a&.api.proto.v1.rpc.CreateUploadResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/uploads\x12Z\n" +
+	"\vUploadChunk\x12$.api.proto.v1.rpc.UploadChunkRequest\x1a%.api.proto.v1.rpc.UploadChunkResponse\x12u\n" +
+	"\tGetUpload\x12\".api.proto.v1.rpc.GetUploadRequest\x1a#.api.proto.v1.rpc.GetUploadResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/uploads/{upload_id}\x12\x90\x01\n" +
+	"\x0eCompleteUpload\x12'.api.proto.v1.rpc.CompleteUploadRequest\x1a(.api.proto.v1.rpc.CompleteUploadResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /v1/uploads/{upload_id}/complete\x12{\n" +
+	"\vAbortUpload\x12$.api.proto.v1.rpc.AbortUploadRequest\x1a%.api.proto.v1.rpc.AbortUploadResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/v1/uploads/{upload_id}\x12l\n" +
+	"\tListTrash\x12\".api.proto.v1.rpc.ListTrashRequest\x1a#.api.proto.v1.rpc.ListTrashResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/data/trash\x12\x8c\x01\n" +
+	"\x10RestoreFromTrash\x12).api.proto.v1.rpc.RestoreFromTrashRequest\x1a*.api.proto.v1.rpc.RestoreFromTrashResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/data/trash/restore\x12o\n" +
+	"\n" +
+	"EmptyTrash\x12#.api.proto.v1.rpc.EmptyTrashRequest\x1a$.api.proto.v1.rpc.EmptyTrashResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/data/trash\x12u\n" +
+	"\fCreateFolder\x12%.api.proto.v1.rpc.CreateFolderRequest\x1a&.api.proto.v1.rpc.CreateFolderResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/folders\x12o\n" +
+	"\vListFolders\x12$.api.proto.v1.rpc.ListFoldersRequest\x1a%.api.proto.v1.rpc.ListFoldersResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/folders\x12\x7f\n" +
+	"\fRenameFolder\x12%.api.proto.v1.rpc.RenameFolderRequest\x1a&.api.proto.v1.rpc.RenameFolderResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\x1a\x15/v1/folders/{id}/name\x12{\n" +
+	"\n" +
+	"MoveFolder\x12#.api.proto.v1.rpc.MoveFolderRequest\x1a$.api.proto.v1.rpc.MoveFolderResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\x1a\x17/v1/folders/{id}/parent\x12w\n" +
+	"\fDeleteFolder\x12%.api.proto.v1.rpc.DeleteFolderRequest\x1a&.api.proto.v1.rpc.DeleteFolderResponse\"\x18\x82\xd3\xe4\x93\x02\x12*\x10/v1/folders/{id}\x12t\n" +
+	"\vMoveRecords\x12$.api.proto.v1.rpc.MoveRecordsRequest\x1a%.api.proto.v1.rpc.MoveRecordsResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/data/move\x12\x8a\x01\n" +
+	"\x12ListRecordVersions\x12+.api.proto.v1.rpc.ListRecordVersionsRequest\x1a,.api.proto.v1.rpc.ListRecordVersionsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/data/versions\x12\x83\x01\n" +
+	"\x11ViewRecordVersion\x12*.api.proto.v1.rpc.ViewRecordVersionRequest\x1a\".api.proto.v1.rpc.DataViewResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/data/versions/view\x12\x9b\x01\n" +
+	"\x14RestoreRecordVersion\x12-.api.proto.v1.rpc.RestoreRecordVersionRequest\x1a..api.proto.v1.rpc.RestoreRecordVersionResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/data/versions/restore\x12\xa2\x01\n" +
+	"\x13SetVersionRetention\x12,.api.proto.v1.rpc.SetVersionRetentionRequest\x1a-.api.proto.v1.rpc.SetVersionRetentionResponse\".\x82\xd3\xe4\x93\x02(:\x01*\x1a#/v1/user/settings/version-retention\x12\x99\x01\n" +
+	"\x10StartKeyRotation\x12/.api.proto.v1.rpc.admin.StartKeyRotationRequest\x1a0.api.proto.v1.rpc.admin.StartKeyRotationResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/admin/keys/rotation\x12\x90\x01\n" +
+	"\x0eGetKeyRotation\x12-.api.proto.v1.rpc.admin.GetKeyRotationRequest\x1a..api.proto.v1.rpc.admin.GetKeyRotationResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/admin/keys/rotation\x12\x9d\x01\n" +
+	"\x10ReconcileObjects\x12/.api.proto.v1.rpc.admin.ReconcileObjectsRequest\x1a0.api.proto.v1.rpc.admin.ReconcileObjectsResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/admin/objects/reconcileB5Z3github.com/apetsko/gophkeeper/protogen/api/proto/v1b\x06proto3"
 
 var file_api_proto_v1_service_proto_goTypes = []any{
-	(*user.LoginRequest)(nil),      // 0: api.proto.v1.rpc.user.LoginRequest
-	(*user.SignupRequest)(nil),     // 1: api.proto.v1.rpc.user.SignupRequest
-	(*rpc.PingRequest)(nil),        // 2: api.proto.v1.rpc.PingRequest
-	(*rpc.DataSaveRequest)(nil),    // 3: api.proto.v1.rpc.DataSaveRequest
-	(*rpc.DataDeleteRequest)(nil),  // 4: api.proto.v1.rpc.DataDeleteRequest
-	(*rpc.DataListRequest)(nil),    // 5: api.proto.v1.rpc.DataListRequest
-	(*rpc.DataViewRequest)(nil),    // 6: api.proto.v1.rpc.DataViewRequest
-	(*user.LoginResponse)(nil),     // 7: api.proto.v1.rpc.user.LoginResponse
-	(*user.SignupResponse)(nil),    // 8: api.proto.v1.rpc.user.SignupResponse
-	(*rpc.PingResponse)(nil),       // 9: api.proto.v1.rpc.PingResponse
-	(*rpc.DataSaveResponse)(nil),   // 10: api.proto.v1.rpc.DataSaveResponse
-	(*rpc.DataDeleteResponse)(nil), // 11: api.proto.v1.rpc.DataDeleteResponse
-	(*rpc.DataListResponse)(nil),   // 12: api.proto.v1.rpc.DataListResponse
-	(*rpc.DataViewResponse)(nil),   // 13: api.proto.v1.rpc.DataViewResponse
+	(*user.PreLoginRequest)(nil),                // 0: api.proto.v1.rpc.user.PreLoginRequest
+	(*user.LoginRequest)(nil),                   // 1: api.proto.v1.rpc.user.LoginRequest
+	(*user.LoginTOTPRequest)(nil),               // 2: api.proto.v1.rpc.user.LoginTOTPRequest
+	(*user.RefreshRequest)(nil),                 // 3: api.proto.v1.rpc.user.RefreshRequest
+	(*user.LogoutRequest)(nil),                  // 4: api.proto.v1.rpc.user.LogoutRequest
+	(*user.ListSessionsRequest)(nil),            // 5: api.proto.v1.rpc.user.ListSessionsRequest
+	(*user.RevokeSessionRequest)(nil),           // 6: api.proto.v1.rpc.user.RevokeSessionRequest
+	(*user.RevokeAllOtherSessionsRequest)(nil),  // 7: api.proto.v1.rpc.user.RevokeAllOtherSessionsRequest
+	(*user.SignupRequest)(nil),                  // 8: api.proto.v1.rpc.user.SignupRequest
+	(*user.ChangePasswordRequest)(nil),          // 9: api.proto.v1.rpc.user.ChangePasswordRequest
+	(*user.EnrollTOTPRequest)(nil),              // 10: api.proto.v1.rpc.user.EnrollTOTPRequest
+	(*user.ConfirmTOTPRequest)(nil),             // 11: api.proto.v1.rpc.user.ConfirmTOTPRequest
+	(*user.DisableTOTPRequest)(nil),             // 12: api.proto.v1.rpc.user.DisableTOTPRequest
+	(*rpc.PingRequest)(nil),                     // 13: api.proto.v1.rpc.PingRequest
+	(*rpc.DataSaveRequest)(nil),                 // 14: api.proto.v1.rpc.DataSaveRequest
+	(*rpc.DataUpdateRequest)(nil),               // 15: api.proto.v1.rpc.DataUpdateRequest
+	(*rpc.DataDeleteRequest)(nil),               // 16: api.proto.v1.rpc.DataDeleteRequest
+	(*rpc.DataListRequest)(nil),                 // 17: api.proto.v1.rpc.DataListRequest
+	(*rpc.SearchRequest)(nil),                   // 18: api.proto.v1.rpc.SearchRequest
+	(*rpc.DataViewRequest)(nil),                 // 19: api.proto.v1.rpc.DataViewRequest
+	(*rpc.GenerateOTPRequest)(nil),              // 20: api.proto.v1.rpc.GenerateOTPRequest
+	(*rpc.UploadFileRequest)(nil),               // 21: api.proto.v1.rpc.UploadFileRequest
+	(*rpc.DownloadFileRequest)(nil),             // 22: api.proto.v1.rpc.DownloadFileRequest
+	(*rpc.CreateUploadRequest)(nil),             // 23: api.proto.v1.rpc.CreateUploadRequest
+	(*rpc.UploadChunkRequest)(nil),              // 24: api.proto.v1.rpc.UploadChunkRequest
+	(*rpc.GetUploadRequest)(nil),                // 25: api.proto.v1.rpc.GetUploadRequest
+	(*rpc.CompleteUploadRequest)(nil),           // 26: api.proto.v1.rpc.CompleteUploadRequest
+	(*rpc.AbortUploadRequest)(nil),              // 27: api.proto.v1.rpc.AbortUploadRequest
+	(*rpc.ListTrashRequest)(nil),                // 28: api.proto.v1.rpc.ListTrashRequest
+	(*rpc.RestoreFromTrashRequest)(nil),         // 29: api.proto.v1.rpc.RestoreFromTrashRequest
+	(*rpc.EmptyTrashRequest)(nil),               // 30: api.proto.v1.rpc.EmptyTrashRequest
+	(*rpc.CreateFolderRequest)(nil),             // 31: api.proto.v1.rpc.CreateFolderRequest
+	(*rpc.ListFoldersRequest)(nil),              // 32: api.proto.v1.rpc.ListFoldersRequest
+	(*rpc.RenameFolderRequest)(nil),             // 33: api.proto.v1.rpc.RenameFolderRequest
+	(*rpc.MoveFolderRequest)(nil),               // 34: api.proto.v1.rpc.MoveFolderRequest
+	(*rpc.DeleteFolderRequest)(nil),             // 35: api.proto.v1.rpc.DeleteFolderRequest
+	(*rpc.MoveRecordsRequest)(nil),              // 36: api.proto.v1.rpc.MoveRecordsRequest
+	(*rpc.ListRecordVersionsRequest)(nil),       // 37: api.proto.v1.rpc.ListRecordVersionsRequest
+	(*rpc.ViewRecordVersionRequest)(nil),        // 38: api.proto.v1.rpc.ViewRecordVersionRequest
+	(*rpc.RestoreRecordVersionRequest)(nil),     // 39: api.proto.v1.rpc.RestoreRecordVersionRequest
+	(*rpc.SetVersionRetentionRequest)(nil),      // 40: api.proto.v1.rpc.SetVersionRetentionRequest
+	(*admin.StartKeyRotationRequest)(nil),       // 41: api.proto.v1.rpc.admin.StartKeyRotationRequest
+	(*admin.GetKeyRotationRequest)(nil),         // 42: api.proto.v1.rpc.admin.GetKeyRotationRequest
+	(*admin.ReconcileObjectsRequest)(nil),       // 43: api.proto.v1.rpc.admin.ReconcileObjectsRequest
+	(*user.PreLoginResponse)(nil),               // 44: api.proto.v1.rpc.user.PreLoginResponse
+	(*user.LoginResponse)(nil),                  // 45: api.proto.v1.rpc.user.LoginResponse
+	(*user.RefreshResponse)(nil),                // 46: api.proto.v1.rpc.user.RefreshResponse
+	(*user.LogoutResponse)(nil),                 // 47: api.proto.v1.rpc.user.LogoutResponse
+	(*user.ListSessionsResponse)(nil),           // 48: api.proto.v1.rpc.user.ListSessionsResponse
+	(*user.RevokeSessionResponse)(nil),          // 49: api.proto.v1.rpc.user.RevokeSessionResponse
+	(*user.RevokeAllOtherSessionsResponse)(nil), // 50: api.proto.v1.rpc.user.RevokeAllOtherSessionsResponse
+	(*user.SignupResponse)(nil),                 // 51: api.proto.v1.rpc.user.SignupResponse
+	(*user.ChangePasswordResponse)(nil),         // 52: api.proto.v1.rpc.user.ChangePasswordResponse
+	(*user.EnrollTOTPResponse)(nil),             // 53: api.proto.v1.rpc.user.EnrollTOTPResponse
+	(*user.ConfirmTOTPResponse)(nil),            // 54: api.proto.v1.rpc.user.ConfirmTOTPResponse
+	(*user.DisableTOTPResponse)(nil),            // 55: api.proto.v1.rpc.user.DisableTOTPResponse
+	(*rpc.PingResponse)(nil),                    // 56: api.proto.v1.rpc.PingResponse
+	(*rpc.DataSaveResponse)(nil),                // 57: api.proto.v1.rpc.DataSaveResponse
+	(*rpc.DataUpdateResponse)(nil),              // 58: api.proto.v1.rpc.DataUpdateResponse
+	(*rpc.DataDeleteResponse)(nil),              // 59: api.proto.v1.rpc.DataDeleteResponse
+	(*rpc.DataListResponse)(nil),                // 60: api.proto.v1.rpc.DataListResponse
+	(*rpc.SearchResponse)(nil),                  // 61: api.proto.v1.rpc.SearchResponse
+	(*rpc.DataViewResponse)(nil),                // 62: api.proto.v1.rpc.DataViewResponse
+	(*rpc.GenerateOTPResponse)(nil),             // 63: api.proto.v1.rpc.GenerateOTPResponse
+	(*rpc.UploadFileResponse)(nil),              // 64: api.proto.v1.rpc.UploadFileResponse
+	(*rpc.DownloadFileResponse)(nil),            // 65: api.proto.v1.rpc.DownloadFileResponse
+	(*rpc.CreateUploadResponse)(nil),            // 66: api.proto.v1.rpc.CreateUploadResponse
+	(*rpc.UploadChunkResponse)(nil),             // 67: api.proto.v1.rpc.UploadChunkResponse
+	(*rpc.GetUploadResponse)(nil),               // 68: api.proto.v1.rpc.GetUploadResponse
+	(*rpc.CompleteUploadResponse)(nil),          // 69: api.proto.v1.rpc.CompleteUploadResponse
+	(*rpc.AbortUploadResponse)(nil),             // 70: api.proto.v1.rpc.AbortUploadResponse
+	(*rpc.ListTrashResponse)(nil),               // 71: api.proto.v1.rpc.ListTrashResponse
+	(*rpc.RestoreFromTrashResponse)(nil),        // 72: api.proto.v1.rpc.RestoreFromTrashResponse
+	(*rpc.EmptyTrashResponse)(nil),              // 73: api.proto.v1.rpc.EmptyTrashResponse
+	(*rpc.CreateFolderResponse)(nil),            // 74: api.proto.v1.rpc.CreateFolderResponse
+	(*rpc.ListFoldersResponse)(nil),             // 75: api.proto.v1.rpc.ListFoldersResponse
+	(*rpc.RenameFolderResponse)(nil),            // 76: api.proto.v1.rpc.RenameFolderResponse
+	(*rpc.MoveFolderResponse)(nil),              // 77: api.proto.v1.rpc.MoveFolderResponse
+	(*rpc.DeleteFolderResponse)(nil),            // 78: api.proto.v1.rpc.DeleteFolderResponse
+	(*rpc.MoveRecordsResponse)(nil),             // 79: api.proto.v1.rpc.MoveRecordsResponse
+	(*rpc.ListRecordVersionsResponse)(nil),      // 80: api.proto.v1.rpc.ListRecordVersionsResponse
+	(*rpc.RestoreRecordVersionResponse)(nil),    // 81: api.proto.v1.rpc.RestoreRecordVersionResponse
+	(*rpc.SetVersionRetentionResponse)(nil),     // 82: api.proto.v1.rpc.SetVersionRetentionResponse
+	(*admin.StartKeyRotationResponse)(nil),      // 83: api.proto.v1.rpc.admin.StartKeyRotationResponse
+	(*admin.GetKeyRotationResponse)(nil),        // 84: api.proto.v1.rpc.admin.GetKeyRotationResponse
+	(*admin.ReconcileObjectsResponse)(nil),      // 85: api.proto.v1.rpc.admin.ReconcileObjectsResponse
 }
 var file_api_proto_v1_service_proto_depIdxs = []int32{
-	0,  // 0: api.proto.v1.GophKeeper.Login:input_type -> api.proto.v1.rpc.user.LoginRequest
-	1,  // 1: api.proto.v1.GophKeeper.Signup:input_type -> api.proto.v1.rpc.user.SignupRequest
-	2,  // 2: api.proto.v1.GophKeeper.Ping:input_type -> api.proto.v1.rpc.PingRequest
-	3,  // 3: api.proto.v1.GophKeeper.DataSave:input_type -> api.proto.v1.rpc.DataSaveRequest
-	4,  // 4: api.proto.v1.GophKeeper.DataDelete:input_type -> api.proto.v1.rpc.DataDeleteRequest
-	5,  // 5: api.proto.v1.GophKeeper.DataList:input_type -> api.proto.v1.rpc.DataListRequest
-	6,  // 6: api.proto.v1.GophKeeper.DataView:input_type -> api.proto.v1.rpc.DataViewRequest
-	7,  // 7: api.proto.v1.GophKeeper.Login:output_type -> api.proto.v1.rpc.user.LoginResponse
-	8,  // 8: api.proto.v1.GophKeeper.Signup:output_type -> api.proto.v1.rpc.user.SignupResponse
-	9,  // 9: api.proto.v1.GophKeeper.Ping:output_type -> api.proto.v1.rpc.PingResponse
-	10, // 10: api.proto.v1.GophKeeper.DataSave:output_type -> api.proto.v1.rpc.DataSaveResponse
-	11, // 11: api.proto.v1.GophKeeper.DataDelete:output_type -> api.proto.v1.rpc.DataDeleteResponse
-	12, // 12: api.proto.v1.GophKeeper.DataList:output_type -> api.proto.v1.rpc.DataListResponse
-	13, // 13: api.proto.v1.GophKeeper.DataView:output_type -> api.proto.v1.rpc.DataViewResponse
-	7,  // [7:14] is the sub-list for method output_type
-	0,  // [0:7] is the sub-list for method input_type
+	0,  // 0: api.proto.v1.GophKeeper.PreLogin:input_type -> api.proto.v1.rpc.user.PreLoginRequest
+	1,  // 1: api.proto.v1.GophKeeper.Login:input_type -> api.proto.v1.rpc.user.LoginRequest
+	2,  // 2: api.proto.v1.GophKeeper.LoginTOTP:input_type -> api.proto.v1.rpc.user.LoginTOTPRequest
+	3,  // 3: api.proto.v1.GophKeeper.Refresh:input_type -> api.proto.v1.rpc.user.RefreshRequest
+	4,  // 4: api.proto.v1.GophKeeper.Logout:input_type -> api.proto.v1.rpc.user.LogoutRequest
+	5,  // 5: api.proto.v1.GophKeeper.ListSessions:input_type -> api.proto.v1.rpc.user.ListSessionsRequest
+	6,  // 6: api.proto.v1.GophKeeper.RevokeSession:input_type -> api.proto.v1.rpc.user.RevokeSessionRequest
+	7,  // 7: api.proto.v1.GophKeeper.RevokeAllOtherSessions:input_type -> api.proto.v1.rpc.user.RevokeAllOtherSessionsRequest
+	8,  // 8: api.proto.v1.GophKeeper.Signup:input_type -> api.proto.v1.rpc.user.SignupRequest
+	9,  // 9: api.proto.v1.GophKeeper.ChangePassword:input_type -> api.proto.v1.rpc.user.ChangePasswordRequest
+	10, // 10: api.proto.v1.GophKeeper.EnrollTOTP:input_type -> api.proto.v1.rpc.user.EnrollTOTPRequest
+	11, // 11: api.proto.v1.GophKeeper.ConfirmTOTP:input_type -> api.proto.v1.rpc.user.ConfirmTOTPRequest
+	12, // 12: api.proto.v1.GophKeeper.DisableTOTP:input_type -> api.proto.v1.rpc.user.DisableTOTPRequest
+	13, // 13: api.proto.v1.GophKeeper.Ping:input_type -> api.proto.v1.rpc.PingRequest
+	14, // 14: api.proto.v1.GophKeeper.DataSave:input_type -> api.proto.v1.rpc.DataSaveRequest
+	15, // 15: api.proto.v1.GophKeeper.DataUpdate:input_type -> api.proto.v1.rpc.DataUpdateRequest
+	16, // 16: api.proto.v1.GophKeeper.DataDelete:input_type -> api.proto.v1.rpc.DataDeleteRequest
+	17, // 17: api.proto.v1.GophKeeper.DataList:input_type -> api.proto.v1.rpc.DataListRequest
+	18, // 18: api.proto.v1.GophKeeper.Search:input_type -> api.proto.v1.rpc.SearchRequest
+	19, // 19: api.proto.v1.GophKeeper.DataView:input_type -> api.proto.v1.rpc.DataViewRequest
+	20, // 20: api.proto.v1.GophKeeper.GenerateOTP:input_type -> api.proto.v1.rpc.GenerateOTPRequest
+	21, // 21: api.proto.v1.GophKeeper.UploadFile:input_type -> api.proto.v1.rpc.UploadFileRequest
+	22, // 22: api.proto.v1.GophKeeper.DownloadFile:input_type -> api.proto.v1.rpc.DownloadFileRequest
+	23, // 23: api.proto.v1.GophKeeper.CreateUpload:input_type -> api.proto.v1.rpc.CreateUploadRequest
+	24, // 24: api.proto.v1.GophKeeper.UploadChunk:input_type -> api.proto.v1.rpc.UploadChunkRequest
+	25, // 25: api.proto.v1.GophKeeper.GetUpload:input_type -> api.proto.v1.rpc.GetUploadRequest
+	26, // 26: api.proto.v1.GophKeeper.CompleteUpload:input_type -> api.proto.v1.rpc.CompleteUploadRequest
+	27, // 27: api.proto.v1.GophKeeper.AbortUpload:input_type -> api.proto.v1.rpc.AbortUploadRequest
+	28, // 28: api.proto.v1.GophKeeper.ListTrash:input_type -> api.proto.v1.rpc.ListTrashRequest
+	29, // 29: api.proto.v1.GophKeeper.RestoreFromTrash:input_type -> api.proto.v1.rpc.RestoreFromTrashRequest
+	30, // 30: api.proto.v1.GophKeeper.EmptyTrash:input_type -> api.proto.v1.rpc.EmptyTrashRequest
+	31, // 31: api.proto.v1.GophKeeper.CreateFolder:input_type -> api.proto.v1.rpc.CreateFolderRequest
+	32, // 32: api.proto.v1.GophKeeper.ListFolders:input_type -> api.proto.v1.rpc.ListFoldersRequest
+	33, // 33: api.proto.v1.GophKeeper.RenameFolder:input_type -> api.proto.v1.rpc.RenameFolderRequest
+	34, // 34: api.proto.v1.GophKeeper.MoveFolder:input_type -> api.proto.v1.rpc.MoveFolderRequest
+	35, // 35: api.proto.v1.GophKeeper.DeleteFolder:input_type -> api.proto.v1.rpc.DeleteFolderRequest
+	36, // 36: api.proto.v1.GophKeeper.MoveRecords:input_type -> api.proto.v1.rpc.MoveRecordsRequest
+	37, // 37: api.proto.v1.GophKeeper.ListRecordVersions:input_type -> api.proto.v1.rpc.ListRecordVersionsRequest
+	38, // 38: api.proto.v1.GophKeeper.ViewRecordVersion:input_type -> api.proto.v1.rpc.ViewRecordVersionRequest
+	39, // 39: api.proto.v1.GophKeeper.RestoreRecordVersion:input_type -> api.proto.v1.rpc.RestoreRecordVersionRequest
+	40, // 40: api.proto.v1.GophKeeper.SetVersionRetention:input_type -> api.proto.v1.rpc.SetVersionRetentionRequest
+	41, // 41: api.proto.v1.GophKeeper.StartKeyRotation:input_type -> api.proto.v1.rpc.admin.StartKeyRotationRequest
+	42, // 42: api.proto.v1.GophKeeper.GetKeyRotation:input_type -> api.proto.v1.rpc.admin.GetKeyRotationRequest
+	43, // 43: api.proto.v1.GophKeeper.ReconcileObjects:input_type -> api.proto.v1.rpc.admin.ReconcileObjectsRequest
+	44, // 44: api.proto.v1.GophKeeper.PreLogin:output_type -> api.proto.v1.rpc.user.PreLoginResponse
+	45, // 45: api.proto.v1.GophKeeper.Login:output_type -> api.proto.v1.rpc.user.LoginResponse
+	45, // 46: api.proto.v1.GophKeeper.LoginTOTP:output_type -> api.proto.v1.rpc.user.LoginResponse
+	46, // 47: api.proto.v1.GophKeeper.Refresh:output_type -> api.proto.v1.rpc.user.RefreshResponse
+	47, // 48: api.proto.v1.GophKeeper.Logout:output_type -> api.proto.v1.rpc.user.LogoutResponse
+	48, // 49: api.proto.v1.GophKeeper.ListSessions:output_type -> api.proto.v1.rpc.user.ListSessionsResponse
+	49, // 50: api.proto.v1.GophKeeper.RevokeSession:output_type -> api.proto.v1.rpc.user.RevokeSessionResponse
+	50, // 51: api.proto.v1.GophKeeper.RevokeAllOtherSessions:output_type -> api.proto.v1.rpc.user.RevokeAllOtherSessionsResponse
+	51, // 52: api.proto.v1.GophKeeper.Signup:output_type -> api.proto.v1.rpc.user.SignupResponse
+	52, // 53: api.proto.v1.GophKeeper.ChangePassword:output_type -> api.proto.v1.rpc.user.ChangePasswordResponse
+	53, // 54: api.proto.v1.GophKeeper.EnrollTOTP:output_type -> api.proto.v1.rpc.user.EnrollTOTPResponse
+	54, // 55: api.proto.v1.GophKeeper.ConfirmTOTP:output_type -> api.proto.v1.rpc.user.ConfirmTOTPResponse
+	55, // 56: api.proto.v1.GophKeeper.DisableTOTP:output_type -> api.proto.v1.rpc.user.DisableTOTPResponse
+	56, // 57: api.proto.v1.GophKeeper.Ping:output_type -> api.proto.v1.rpc.PingResponse
+	57, // 58: api.proto.v1.GophKeeper.DataSave:output_type -> api.proto.v1.rpc.DataSaveResponse
+	58, // 59: api.proto.v1.GophKeeper.DataUpdate:output_type -> api.proto.v1.rpc.DataUpdateResponse
+	59, // 60: api.proto.v1.GophKeeper.DataDelete:output_type -> api.proto.v1.rpc.DataDeleteResponse
+	60, // 61: api.proto.v1.GophKeeper.DataList:output_type -> api.proto.v1.rpc.DataListResponse
+	61, // 62: api.proto.v1.GophKeeper.Search:output_type -> api.proto.v1.rpc.SearchResponse
+	62, // 63: api.proto.v1.GophKeeper.DataView:output_type -> api.proto.v1.rpc.DataViewResponse
+	63, // 64: api.proto.v1.GophKeeper.GenerateOTP:output_type -> api.proto.v1.rpc.GenerateOTPResponse
+	64, // 65: api.proto.v1.GophKeeper.UploadFile:output_type -> api.proto.v1.rpc.UploadFileResponse
+	65, // 66: api.proto.v1.GophKeeper.DownloadFile:output_type -> api.proto.v1.rpc.DownloadFileResponse
+	66, // 67: api.proto.v1.GophKeeper.CreateUpload:output_type -> api.proto.v1.rpc.CreateUploadResponse
+	67, // 68: api.proto.v1.GophKeeper.UploadChunk:output_type -> api.proto.v1.rpc.UploadChunkResponse
+	68, // 69: api.proto.v1.GophKeeper.GetUpload:output_type -> api.proto.v1.rpc.GetUploadResponse
+	69, // 70: api.proto.v1.GophKeeper.CompleteUpload:output_type -> api.proto.v1.rpc.CompleteUploadResponse
+	70, // 71: api.proto.v1.GophKeeper.AbortUpload:output_type -> api.proto.v1.rpc.AbortUploadResponse
+	71, // 72: api.proto.v1.GophKeeper.ListTrash:output_type -> api.proto.v1.rpc.ListTrashResponse
+	72, // 73: api.proto.v1.GophKeeper.RestoreFromTrash:output_type -> api.proto.v1.rpc.RestoreFromTrashResponse
+	73, // 74: api.proto.v1.GophKeeper.EmptyTrash:output_type -> api.proto.v1.rpc.EmptyTrashResponse
+	74, // 75: api.proto.v1.GophKeeper.CreateFolder:output_type -> api.proto.v1.rpc.CreateFolderResponse
+	75, // 76: api.proto.v1.GophKeeper.ListFolders:output_type -> api.proto.v1.rpc.ListFoldersResponse
+	76, // 77: api.proto.v1.GophKeeper.RenameFolder:output_type -> api.proto.v1.rpc.RenameFolderResponse
+	77, // 78: api.proto.v1.GophKeeper.MoveFolder:output_type -> api.proto.v1.rpc.MoveFolderResponse
+	78, // 79: api.proto.v1.GophKeeper.DeleteFolder:output_type -> api.proto.v1.rpc.DeleteFolderResponse
+	79, // 80: api.proto.v1.GophKeeper.MoveRecords:output_type -> api.proto.v1.rpc.MoveRecordsResponse
+	80, // 81: api.proto.v1.GophKeeper.ListRecordVersions:output_type -> api.proto.v1.rpc.ListRecordVersionsResponse
+	62, // 82: api.proto.v1.GophKeeper.ViewRecordVersion:output_type -> api.proto.v1.rpc.DataViewResponse
+	81, // 83: api.proto.v1.GophKeeper.RestoreRecordVersion:output_type -> api.proto.v1.rpc.RestoreRecordVersionResponse
+	82, // 84: api.proto.v1.GophKeeper.SetVersionRetention:output_type -> api.proto.v1.rpc.SetVersionRetentionResponse
+	83, // 85: api.proto.v1.GophKeeper.StartKeyRotation:output_type -> api.proto.v1.rpc.admin.StartKeyRotationResponse
+	84, // 86: api.proto.v1.GophKeeper.GetKeyRotation:output_type -> api.proto.v1.rpc.admin.GetKeyRotationResponse
+	85, // 87: api.proto.v1.GophKeeper.ReconcileObjects:output_type -> api.proto.v1.rpc.admin.ReconcileObjectsResponse
+	44, // [44:88] is the sub-list for method output_type
+	0,  // [0:44] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
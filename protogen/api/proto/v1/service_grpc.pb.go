@@ -9,6 +9,7 @@ package v1
 import (
 	context "context"
 	rpc "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc"
+	admin "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/admin"
 	user "github.com/apetsko/gophkeeper/protogen/api/proto/v1/rpc/user"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -21,26 +22,101 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GophKeeper_Login_FullMethodName      = "/api.proto.v1.GophKeeper/Login"
-	GophKeeper_Signup_FullMethodName     = "/api.proto.v1.GophKeeper/Signup"
-	GophKeeper_Ping_FullMethodName       = "/api.proto.v1.GophKeeper/Ping"
-	GophKeeper_DataSave_FullMethodName   = "/api.proto.v1.GophKeeper/DataSave"
-	GophKeeper_DataDelete_FullMethodName = "/api.proto.v1.GophKeeper/DataDelete"
-	GophKeeper_DataList_FullMethodName   = "/api.proto.v1.GophKeeper/DataList"
-	GophKeeper_DataView_FullMethodName   = "/api.proto.v1.GophKeeper/DataView"
+	GophKeeper_PreLogin_FullMethodName               = "/api.proto.v1.GophKeeper/PreLogin"
+	GophKeeper_Login_FullMethodName                  = "/api.proto.v1.GophKeeper/Login"
+	GophKeeper_LoginTOTP_FullMethodName              = "/api.proto.v1.GophKeeper/LoginTOTP"
+	GophKeeper_Refresh_FullMethodName                = "/api.proto.v1.GophKeeper/Refresh"
+	GophKeeper_Logout_FullMethodName                 = "/api.proto.v1.GophKeeper/Logout"
+	GophKeeper_ListSessions_FullMethodName           = "/api.proto.v1.GophKeeper/ListSessions"
+	GophKeeper_RevokeSession_FullMethodName          = "/api.proto.v1.GophKeeper/RevokeSession"
+	GophKeeper_RevokeAllOtherSessions_FullMethodName = "/api.proto.v1.GophKeeper/RevokeAllOtherSessions"
+	GophKeeper_Signup_FullMethodName                 = "/api.proto.v1.GophKeeper/Signup"
+	GophKeeper_ChangePassword_FullMethodName         = "/api.proto.v1.GophKeeper/ChangePassword"
+	GophKeeper_EnrollTOTP_FullMethodName             = "/api.proto.v1.GophKeeper/EnrollTOTP"
+	GophKeeper_ConfirmTOTP_FullMethodName            = "/api.proto.v1.GophKeeper/ConfirmTOTP"
+	GophKeeper_DisableTOTP_FullMethodName            = "/api.proto.v1.GophKeeper/DisableTOTP"
+	GophKeeper_Ping_FullMethodName                   = "/api.proto.v1.GophKeeper/Ping"
+	GophKeeper_DataSave_FullMethodName               = "/api.proto.v1.GophKeeper/DataSave"
+	GophKeeper_DataUpdate_FullMethodName             = "/api.proto.v1.GophKeeper/DataUpdate"
+	GophKeeper_DataDelete_FullMethodName             = "/api.proto.v1.GophKeeper/DataDelete"
+	GophKeeper_DataList_FullMethodName               = "/api.proto.v1.GophKeeper/DataList"
+	GophKeeper_Search_FullMethodName                 = "/api.proto.v1.GophKeeper/Search"
+	GophKeeper_DataView_FullMethodName               = "/api.proto.v1.GophKeeper/DataView"
+	GophKeeper_GenerateOTP_FullMethodName            = "/api.proto.v1.GophKeeper/GenerateOTP"
+	GophKeeper_UploadFile_FullMethodName             = "/api.proto.v1.GophKeeper/UploadFile"
+	GophKeeper_DownloadFile_FullMethodName           = "/api.proto.v1.GophKeeper/DownloadFile"
+	GophKeeper_CreateUpload_FullMethodName           = "/api.proto.v1.GophKeeper/CreateUpload"
+	GophKeeper_UploadChunk_FullMethodName            = "/api.proto.v1.GophKeeper/UploadChunk"
+	GophKeeper_GetUpload_FullMethodName              = "/api.proto.v1.GophKeeper/GetUpload"
+	GophKeeper_CompleteUpload_FullMethodName         = "/api.proto.v1.GophKeeper/CompleteUpload"
+	GophKeeper_AbortUpload_FullMethodName            = "/api.proto.v1.GophKeeper/AbortUpload"
+	GophKeeper_ListTrash_FullMethodName              = "/api.proto.v1.GophKeeper/ListTrash"
+	GophKeeper_RestoreFromTrash_FullMethodName       = "/api.proto.v1.GophKeeper/RestoreFromTrash"
+	GophKeeper_EmptyTrash_FullMethodName             = "/api.proto.v1.GophKeeper/EmptyTrash"
+	GophKeeper_CreateFolder_FullMethodName           = "/api.proto.v1.GophKeeper/CreateFolder"
+	GophKeeper_ListFolders_FullMethodName            = "/api.proto.v1.GophKeeper/ListFolders"
+	GophKeeper_RenameFolder_FullMethodName           = "/api.proto.v1.GophKeeper/RenameFolder"
+	GophKeeper_MoveFolder_FullMethodName             = "/api.proto.v1.GophKeeper/MoveFolder"
+	GophKeeper_DeleteFolder_FullMethodName           = "/api.proto.v1.GophKeeper/DeleteFolder"
+	GophKeeper_MoveRecords_FullMethodName            = "/api.proto.v1.GophKeeper/MoveRecords"
+	GophKeeper_ListRecordVersions_FullMethodName     = "/api.proto.v1.GophKeeper/ListRecordVersions"
+	GophKeeper_ViewRecordVersion_FullMethodName      = "/api.proto.v1.GophKeeper/ViewRecordVersion"
+	GophKeeper_RestoreRecordVersion_FullMethodName   = "/api.proto.v1.GophKeeper/RestoreRecordVersion"
+	GophKeeper_SetVersionRetention_FullMethodName    = "/api.proto.v1.GophKeeper/SetVersionRetention"
+	GophKeeper_StartKeyRotation_FullMethodName       = "/api.proto.v1.GophKeeper/StartKeyRotation"
+	GophKeeper_GetKeyRotation_FullMethodName         = "/api.proto.v1.GophKeeper/GetKeyRotation"
+	GophKeeper_ReconcileObjects_FullMethodName       = "/api.proto.v1.GophKeeper/ReconcileObjects"
 )
 
 // GophKeeperClient is the client API for GophKeeper service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GophKeeperClient interface {
+	PreLogin(ctx context.Context, in *user.PreLoginRequest, opts ...grpc.CallOption) (*user.PreLoginResponse, error)
 	Login(ctx context.Context, in *user.LoginRequest, opts ...grpc.CallOption) (*user.LoginResponse, error)
+	LoginTOTP(ctx context.Context, in *user.LoginTOTPRequest, opts ...grpc.CallOption) (*user.LoginResponse, error)
+	Refresh(ctx context.Context, in *user.RefreshRequest, opts ...grpc.CallOption) (*user.RefreshResponse, error)
+	Logout(ctx context.Context, in *user.LogoutRequest, opts ...grpc.CallOption) (*user.LogoutResponse, error)
+	ListSessions(ctx context.Context, in *user.ListSessionsRequest, opts ...grpc.CallOption) (*user.ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, in *user.RevokeSessionRequest, opts ...grpc.CallOption) (*user.RevokeSessionResponse, error)
+	RevokeAllOtherSessions(ctx context.Context, in *user.RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*user.RevokeAllOtherSessionsResponse, error)
 	Signup(ctx context.Context, in *user.SignupRequest, opts ...grpc.CallOption) (*user.SignupResponse, error)
+	ChangePassword(ctx context.Context, in *user.ChangePasswordRequest, opts ...grpc.CallOption) (*user.ChangePasswordResponse, error)
+	EnrollTOTP(ctx context.Context, in *user.EnrollTOTPRequest, opts ...grpc.CallOption) (*user.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, in *user.ConfirmTOTPRequest, opts ...grpc.CallOption) (*user.ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, in *user.DisableTOTPRequest, opts ...grpc.CallOption) (*user.DisableTOTPResponse, error)
 	Ping(ctx context.Context, in *rpc.PingRequest, opts ...grpc.CallOption) (*rpc.PingResponse, error)
 	DataSave(ctx context.Context, in *rpc.DataSaveRequest, opts ...grpc.CallOption) (*rpc.DataSaveResponse, error)
+	DataUpdate(ctx context.Context, in *rpc.DataUpdateRequest, opts ...grpc.CallOption) (*rpc.DataUpdateResponse, error)
 	DataDelete(ctx context.Context, in *rpc.DataDeleteRequest, opts ...grpc.CallOption) (*rpc.DataDeleteResponse, error)
 	DataList(ctx context.Context, in *rpc.DataListRequest, opts ...grpc.CallOption) (*rpc.DataListResponse, error)
+	Search(ctx context.Context, in *rpc.SearchRequest, opts ...grpc.CallOption) (*rpc.SearchResponse, error)
 	DataView(ctx context.Context, in *rpc.DataViewRequest, opts ...grpc.CallOption) (*rpc.DataViewResponse, error)
+	GenerateOTP(ctx context.Context, in *rpc.GenerateOTPRequest, opts ...grpc.CallOption) (*rpc.GenerateOTPResponse, error)
+	UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[rpc.UploadFileRequest, rpc.UploadFileResponse], error)
+	DownloadFile(ctx context.Context, in *rpc.DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[rpc.DownloadFileResponse], error)
+	CreateUpload(ctx context.Context, in *rpc.CreateUploadRequest, opts ...grpc.CallOption) (*rpc.CreateUploadResponse, error)
+	// Over HTTP chunks are sent as raw bodies to PUT /v1/uploads/{upload_id}, see internal/server/http.
+	UploadChunk(ctx context.Context, in *rpc.UploadChunkRequest, opts ...grpc.CallOption) (*rpc.UploadChunkResponse, error)
+	GetUpload(ctx context.Context, in *rpc.GetUploadRequest, opts ...grpc.CallOption) (*rpc.GetUploadResponse, error)
+	CompleteUpload(ctx context.Context, in *rpc.CompleteUploadRequest, opts ...grpc.CallOption) (*rpc.CompleteUploadResponse, error)
+	AbortUpload(ctx context.Context, in *rpc.AbortUploadRequest, opts ...grpc.CallOption) (*rpc.AbortUploadResponse, error)
+	ListTrash(ctx context.Context, in *rpc.ListTrashRequest, opts ...grpc.CallOption) (*rpc.ListTrashResponse, error)
+	RestoreFromTrash(ctx context.Context, in *rpc.RestoreFromTrashRequest, opts ...grpc.CallOption) (*rpc.RestoreFromTrashResponse, error)
+	EmptyTrash(ctx context.Context, in *rpc.EmptyTrashRequest, opts ...grpc.CallOption) (*rpc.EmptyTrashResponse, error)
+	CreateFolder(ctx context.Context, in *rpc.CreateFolderRequest, opts ...grpc.CallOption) (*rpc.CreateFolderResponse, error)
+	ListFolders(ctx context.Context, in *rpc.ListFoldersRequest, opts ...grpc.CallOption) (*rpc.ListFoldersResponse, error)
+	RenameFolder(ctx context.Context, in *rpc.RenameFolderRequest, opts ...grpc.CallOption) (*rpc.RenameFolderResponse, error)
+	MoveFolder(ctx context.Context, in *rpc.MoveFolderRequest, opts ...grpc.CallOption) (*rpc.MoveFolderResponse, error)
+	DeleteFolder(ctx context.Context, in *rpc.DeleteFolderRequest, opts ...grpc.CallOption) (*rpc.DeleteFolderResponse, error)
+	MoveRecords(ctx context.Context, in *rpc.MoveRecordsRequest, opts ...grpc.CallOption) (*rpc.MoveRecordsResponse, error)
+	ListRecordVersions(ctx context.Context, in *rpc.ListRecordVersionsRequest, opts ...grpc.CallOption) (*rpc.ListRecordVersionsResponse, error)
+	ViewRecordVersion(ctx context.Context, in *rpc.ViewRecordVersionRequest, opts ...grpc.CallOption) (*rpc.DataViewResponse, error)
+	RestoreRecordVersion(ctx context.Context, in *rpc.RestoreRecordVersionRequest, opts ...grpc.CallOption) (*rpc.RestoreRecordVersionResponse, error)
+	SetVersionRetention(ctx context.Context, in *rpc.SetVersionRetentionRequest, opts ...grpc.CallOption) (*rpc.SetVersionRetentionResponse, error)
+	StartKeyRotation(ctx context.Context, in *admin.StartKeyRotationRequest, opts ...grpc.CallOption) (*admin.StartKeyRotationResponse, error)
+	GetKeyRotation(ctx context.Context, in *admin.GetKeyRotationRequest, opts ...grpc.CallOption) (*admin.GetKeyRotationResponse, error)
+	ReconcileObjects(ctx context.Context, in *admin.ReconcileObjectsRequest, opts ...grpc.CallOption) (*admin.ReconcileObjectsResponse, error)
 }
 
 type gophKeeperClient struct {
@@ -51,6 +127,16 @@ func NewGophKeeperClient(cc grpc.ClientConnInterface) GophKeeperClient {
 	return &gophKeeperClient{cc}
 }
 
+func (c *gophKeeperClient) PreLogin(ctx context.Context, in *user.PreLoginRequest, opts ...grpc.CallOption) (*user.PreLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.PreLoginResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_PreLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophKeeperClient) Login(ctx context.Context, in *user.LoginRequest, opts ...grpc.CallOption) (*user.LoginResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(user.LoginResponse)
@@ -61,6 +147,66 @@ func (c *gophKeeperClient) Login(ctx context.Context, in *user.LoginRequest, opt
 	return out, nil
 }
 
+func (c *gophKeeperClient) LoginTOTP(ctx context.Context, in *user.LoginTOTPRequest, opts ...grpc.CallOption) (*user.LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.LoginResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_LoginTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) Refresh(ctx context.Context, in *user.RefreshRequest, opts ...grpc.CallOption) (*user.RefreshResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.RefreshResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_Refresh_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) Logout(ctx context.Context, in *user.LogoutRequest, opts ...grpc.CallOption) (*user.LogoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.LogoutResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ListSessions(ctx context.Context, in *user.ListSessionsRequest, opts ...grpc.CallOption) (*user.ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.ListSessionsResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) RevokeSession(ctx context.Context, in *user.RevokeSessionRequest, opts ...grpc.CallOption) (*user.RevokeSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) RevokeAllOtherSessions(ctx context.Context, in *user.RevokeAllOtherSessionsRequest, opts ...grpc.CallOption) (*user.RevokeAllOtherSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.RevokeAllOtherSessionsResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_RevokeAllOtherSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gophKeeperClient) Signup(ctx context.Context, in *user.SignupRequest, opts ...grpc.CallOption) (*user.SignupResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(user.SignupResponse)
@@ -71,241 +217,1350 @@ func (c *gophKeeperClient) Signup(ctx context.Context, in *user.SignupRequest, o
 	return out, nil
 }
 
-func (c *gophKeeperClient) Ping(ctx context.Context, in *rpc.PingRequest, opts ...grpc.CallOption) (*rpc.PingResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(rpc.PingResponse)
-	err := c.cc.Invoke(ctx, GophKeeper_Ping_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func (c *gophKeeperClient) ChangePassword(ctx context.Context, in *user.ChangePasswordRequest, opts ...grpc.CallOption) (*user.ChangePasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.ChangePasswordResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ChangePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) EnrollTOTP(ctx context.Context, in *user.EnrollTOTPRequest, opts ...grpc.CallOption) (*user.EnrollTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.EnrollTOTPResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_EnrollTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ConfirmTOTP(ctx context.Context, in *user.ConfirmTOTPRequest, opts ...grpc.CallOption) (*user.ConfirmTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.ConfirmTOTPResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ConfirmTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DisableTOTP(ctx context.Context, in *user.DisableTOTPRequest, opts ...grpc.CallOption) (*user.DisableTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(user.DisableTOTPResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DisableTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) Ping(ctx context.Context, in *rpc.PingRequest, opts ...grpc.CallOption) (*rpc.PingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.PingResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DataSave(ctx context.Context, in *rpc.DataSaveRequest, opts ...grpc.CallOption) (*rpc.DataSaveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DataSaveResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DataSave_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DataUpdate(ctx context.Context, in *rpc.DataUpdateRequest, opts ...grpc.CallOption) (*rpc.DataUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DataUpdateResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DataUpdate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DataDelete(ctx context.Context, in *rpc.DataDeleteRequest, opts ...grpc.CallOption) (*rpc.DataDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DataDeleteResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DataDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DataList(ctx context.Context, in *rpc.DataListRequest, opts ...grpc.CallOption) (*rpc.DataListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DataListResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DataList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) Search(ctx context.Context, in *rpc.SearchRequest, opts ...grpc.CallOption) (*rpc.SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.SearchResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DataView(ctx context.Context, in *rpc.DataViewRequest, opts ...grpc.CallOption) (*rpc.DataViewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DataViewResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DataView_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) GenerateOTP(ctx context.Context, in *rpc.GenerateOTPRequest, opts ...grpc.CallOption) (*rpc.GenerateOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.GenerateOTPResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_GenerateOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[rpc.UploadFileRequest, rpc.UploadFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GophKeeper_ServiceDesc.Streams[0], GophKeeper_UploadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[rpc.UploadFileRequest, rpc.UploadFileResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GophKeeper_UploadFileClient = grpc.ClientStreamingClient[rpc.UploadFileRequest, rpc.UploadFileResponse]
+
+func (c *gophKeeperClient) DownloadFile(ctx context.Context, in *rpc.DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[rpc.DownloadFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GophKeeper_ServiceDesc.Streams[1], GophKeeper_DownloadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[rpc.DownloadFileRequest, rpc.DownloadFileResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GophKeeper_DownloadFileClient = grpc.ServerStreamingClient[rpc.DownloadFileResponse]
+
+func (c *gophKeeperClient) CreateUpload(ctx context.Context, in *rpc.CreateUploadRequest, opts ...grpc.CallOption) (*rpc.CreateUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.CreateUploadResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_CreateUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) UploadChunk(ctx context.Context, in *rpc.UploadChunkRequest, opts ...grpc.CallOption) (*rpc.UploadChunkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.UploadChunkResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_UploadChunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) GetUpload(ctx context.Context, in *rpc.GetUploadRequest, opts ...grpc.CallOption) (*rpc.GetUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.GetUploadResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_GetUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) CompleteUpload(ctx context.Context, in *rpc.CompleteUploadRequest, opts ...grpc.CallOption) (*rpc.CompleteUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.CompleteUploadResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_CompleteUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) AbortUpload(ctx context.Context, in *rpc.AbortUploadRequest, opts ...grpc.CallOption) (*rpc.AbortUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.AbortUploadResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_AbortUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ListTrash(ctx context.Context, in *rpc.ListTrashRequest, opts ...grpc.CallOption) (*rpc.ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.ListTrashResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) RestoreFromTrash(ctx context.Context, in *rpc.RestoreFromTrashRequest, opts ...grpc.CallOption) (*rpc.RestoreFromTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.RestoreFromTrashResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_RestoreFromTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) EmptyTrash(ctx context.Context, in *rpc.EmptyTrashRequest, opts ...grpc.CallOption) (*rpc.EmptyTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.EmptyTrashResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_EmptyTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) CreateFolder(ctx context.Context, in *rpc.CreateFolderRequest, opts ...grpc.CallOption) (*rpc.CreateFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.CreateFolderResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_CreateFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ListFolders(ctx context.Context, in *rpc.ListFoldersRequest, opts ...grpc.CallOption) (*rpc.ListFoldersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.ListFoldersResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ListFolders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) RenameFolder(ctx context.Context, in *rpc.RenameFolderRequest, opts ...grpc.CallOption) (*rpc.RenameFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.RenameFolderResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_RenameFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) MoveFolder(ctx context.Context, in *rpc.MoveFolderRequest, opts ...grpc.CallOption) (*rpc.MoveFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.MoveFolderResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_MoveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) DeleteFolder(ctx context.Context, in *rpc.DeleteFolderRequest, opts ...grpc.CallOption) (*rpc.DeleteFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DeleteFolderResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_DeleteFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) MoveRecords(ctx context.Context, in *rpc.MoveRecordsRequest, opts ...grpc.CallOption) (*rpc.MoveRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.MoveRecordsResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_MoveRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ListRecordVersions(ctx context.Context, in *rpc.ListRecordVersionsRequest, opts ...grpc.CallOption) (*rpc.ListRecordVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.ListRecordVersionsResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ListRecordVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ViewRecordVersion(ctx context.Context, in *rpc.ViewRecordVersionRequest, opts ...grpc.CallOption) (*rpc.DataViewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.DataViewResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ViewRecordVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) RestoreRecordVersion(ctx context.Context, in *rpc.RestoreRecordVersionRequest, opts ...grpc.CallOption) (*rpc.RestoreRecordVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.RestoreRecordVersionResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_RestoreRecordVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) SetVersionRetention(ctx context.Context, in *rpc.SetVersionRetentionRequest, opts ...grpc.CallOption) (*rpc.SetVersionRetentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(rpc.SetVersionRetentionResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_SetVersionRetention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) StartKeyRotation(ctx context.Context, in *admin.StartKeyRotationRequest, opts ...grpc.CallOption) (*admin.StartKeyRotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(admin.StartKeyRotationResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_StartKeyRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) GetKeyRotation(ctx context.Context, in *admin.GetKeyRotationRequest, opts ...grpc.CallOption) (*admin.GetKeyRotationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(admin.GetKeyRotationResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_GetKeyRotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gophKeeperClient) ReconcileObjects(ctx context.Context, in *admin.ReconcileObjectsRequest, opts ...grpc.CallOption) (*admin.ReconcileObjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(admin.ReconcileObjectsResponse)
+	err := c.cc.Invoke(ctx, GophKeeper_ReconcileObjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GophKeeperServer is the server API for GophKeeper service.
+// All implementations must embed UnimplementedGophKeeperServer
+// for forward compatibility.
+type GophKeeperServer interface {
+	PreLogin(context.Context, *user.PreLoginRequest) (*user.PreLoginResponse, error)
+	Login(context.Context, *user.LoginRequest) (*user.LoginResponse, error)
+	LoginTOTP(context.Context, *user.LoginTOTPRequest) (*user.LoginResponse, error)
+	Refresh(context.Context, *user.RefreshRequest) (*user.RefreshResponse, error)
+	Logout(context.Context, *user.LogoutRequest) (*user.LogoutResponse, error)
+	ListSessions(context.Context, *user.ListSessionsRequest) (*user.ListSessionsResponse, error)
+	RevokeSession(context.Context, *user.RevokeSessionRequest) (*user.RevokeSessionResponse, error)
+	RevokeAllOtherSessions(context.Context, *user.RevokeAllOtherSessionsRequest) (*user.RevokeAllOtherSessionsResponse, error)
+	Signup(context.Context, *user.SignupRequest) (*user.SignupResponse, error)
+	ChangePassword(context.Context, *user.ChangePasswordRequest) (*user.ChangePasswordResponse, error)
+	EnrollTOTP(context.Context, *user.EnrollTOTPRequest) (*user.EnrollTOTPResponse, error)
+	ConfirmTOTP(context.Context, *user.ConfirmTOTPRequest) (*user.ConfirmTOTPResponse, error)
+	DisableTOTP(context.Context, *user.DisableTOTPRequest) (*user.DisableTOTPResponse, error)
+	Ping(context.Context, *rpc.PingRequest) (*rpc.PingResponse, error)
+	DataSave(context.Context, *rpc.DataSaveRequest) (*rpc.DataSaveResponse, error)
+	DataUpdate(context.Context, *rpc.DataUpdateRequest) (*rpc.DataUpdateResponse, error)
+	DataDelete(context.Context, *rpc.DataDeleteRequest) (*rpc.DataDeleteResponse, error)
+	DataList(context.Context, *rpc.DataListRequest) (*rpc.DataListResponse, error)
+	Search(context.Context, *rpc.SearchRequest) (*rpc.SearchResponse, error)
+	DataView(context.Context, *rpc.DataViewRequest) (*rpc.DataViewResponse, error)
+	GenerateOTP(context.Context, *rpc.GenerateOTPRequest) (*rpc.GenerateOTPResponse, error)
+	UploadFile(grpc.ClientStreamingServer[rpc.UploadFileRequest, rpc.UploadFileResponse]) error
+	DownloadFile(*rpc.DownloadFileRequest, grpc.ServerStreamingServer[rpc.DownloadFileResponse]) error
+	CreateUpload(context.Context, *rpc.CreateUploadRequest) (*rpc.CreateUploadResponse, error)
+	// Over HTTP chunks are sent as raw bodies to PUT /v1/uploads/{upload_id}, see internal/server/http.
+	UploadChunk(context.Context, *rpc.UploadChunkRequest) (*rpc.UploadChunkResponse, error)
+	GetUpload(context.Context, *rpc.GetUploadRequest) (*rpc.GetUploadResponse, error)
+	CompleteUpload(context.Context, *rpc.CompleteUploadRequest) (*rpc.CompleteUploadResponse, error)
+	AbortUpload(context.Context, *rpc.AbortUploadRequest) (*rpc.AbortUploadResponse, error)
+	ListTrash(context.Context, *rpc.ListTrashRequest) (*rpc.ListTrashResponse, error)
+	RestoreFromTrash(context.Context, *rpc.RestoreFromTrashRequest) (*rpc.RestoreFromTrashResponse, error)
+	EmptyTrash(context.Context, *rpc.EmptyTrashRequest) (*rpc.EmptyTrashResponse, error)
+	CreateFolder(context.Context, *rpc.CreateFolderRequest) (*rpc.CreateFolderResponse, error)
+	ListFolders(context.Context, *rpc.ListFoldersRequest) (*rpc.ListFoldersResponse, error)
+	RenameFolder(context.Context, *rpc.RenameFolderRequest) (*rpc.RenameFolderResponse, error)
+	MoveFolder(context.Context, *rpc.MoveFolderRequest) (*rpc.MoveFolderResponse, error)
+	DeleteFolder(context.Context, *rpc.DeleteFolderRequest) (*rpc.DeleteFolderResponse, error)
+	MoveRecords(context.Context, *rpc.MoveRecordsRequest) (*rpc.MoveRecordsResponse, error)
+	ListRecordVersions(context.Context, *rpc.ListRecordVersionsRequest) (*rpc.ListRecordVersionsResponse, error)
+	ViewRecordVersion(context.Context, *rpc.ViewRecordVersionRequest) (*rpc.DataViewResponse, error)
+	RestoreRecordVersion(context.Context, *rpc.RestoreRecordVersionRequest) (*rpc.RestoreRecordVersionResponse, error)
+	SetVersionRetention(context.Context, *rpc.SetVersionRetentionRequest) (*rpc.SetVersionRetentionResponse, error)
+	StartKeyRotation(context.Context, *admin.StartKeyRotationRequest) (*admin.StartKeyRotationResponse, error)
+	GetKeyRotation(context.Context, *admin.GetKeyRotationRequest) (*admin.GetKeyRotationResponse, error)
+	ReconcileObjects(context.Context, *admin.ReconcileObjectsRequest) (*admin.ReconcileObjectsResponse, error)
+	mustEmbedUnimplementedGophKeeperServer()
+}
+
+// UnimplementedGophKeeperServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGophKeeperServer struct{}
+
+func (UnimplementedGophKeeperServer) PreLogin(context.Context, *user.PreLoginRequest) (*user.PreLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreLogin not implemented")
+}
+func (UnimplementedGophKeeperServer) Login(context.Context, *user.LoginRequest) (*user.LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedGophKeeperServer) LoginTOTP(context.Context, *user.LoginTOTPRequest) (*user.LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginTOTP not implemented")
+}
+func (UnimplementedGophKeeperServer) Refresh(context.Context, *user.RefreshRequest) (*user.RefreshResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (UnimplementedGophKeeperServer) Logout(context.Context, *user.LogoutRequest) (*user.LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedGophKeeperServer) ListSessions(context.Context, *user.ListSessionsRequest) (*user.ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedGophKeeperServer) RevokeSession(context.Context, *user.RevokeSessionRequest) (*user.RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedGophKeeperServer) RevokeAllOtherSessions(context.Context, *user.RevokeAllOtherSessionsRequest) (*user.RevokeAllOtherSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAllOtherSessions not implemented")
+}
+func (UnimplementedGophKeeperServer) Signup(context.Context, *user.SignupRequest) (*user.SignupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Signup not implemented")
+}
+func (UnimplementedGophKeeperServer) ChangePassword(context.Context, *user.ChangePasswordRequest) (*user.ChangePasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedGophKeeperServer) EnrollTOTP(context.Context, *user.EnrollTOTPRequest) (*user.EnrollTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedGophKeeperServer) ConfirmTOTP(context.Context, *user.ConfirmTOTPRequest) (*user.ConfirmTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedGophKeeperServer) DisableTOTP(context.Context, *user.DisableTOTPRequest) (*user.DisableTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedGophKeeperServer) Ping(context.Context, *rpc.PingRequest) (*rpc.PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedGophKeeperServer) DataSave(context.Context, *rpc.DataSaveRequest) (*rpc.DataSaveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DataSave not implemented")
+}
+func (UnimplementedGophKeeperServer) DataUpdate(context.Context, *rpc.DataUpdateRequest) (*rpc.DataUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DataUpdate not implemented")
+}
+func (UnimplementedGophKeeperServer) DataDelete(context.Context, *rpc.DataDeleteRequest) (*rpc.DataDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DataDelete not implemented")
+}
+func (UnimplementedGophKeeperServer) DataList(context.Context, *rpc.DataListRequest) (*rpc.DataListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DataList not implemented")
+}
+func (UnimplementedGophKeeperServer) Search(context.Context, *rpc.SearchRequest) (*rpc.SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedGophKeeperServer) DataView(context.Context, *rpc.DataViewRequest) (*rpc.DataViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DataView not implemented")
+}
+func (UnimplementedGophKeeperServer) GenerateOTP(context.Context, *rpc.GenerateOTPRequest) (*rpc.GenerateOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateOTP not implemented")
+}
+func (UnimplementedGophKeeperServer) UploadFile(grpc.ClientStreamingServer[rpc.UploadFileRequest, rpc.UploadFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+}
+func (UnimplementedGophKeeperServer) DownloadFile(*rpc.DownloadFileRequest, grpc.ServerStreamingServer[rpc.DownloadFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
+}
+func (UnimplementedGophKeeperServer) CreateUpload(context.Context, *rpc.CreateUploadRequest) (*rpc.CreateUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUpload not implemented")
+}
+func (UnimplementedGophKeeperServer) UploadChunk(context.Context, *rpc.UploadChunkRequest) (*rpc.UploadChunkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadChunk not implemented")
+}
+func (UnimplementedGophKeeperServer) GetUpload(context.Context, *rpc.GetUploadRequest) (*rpc.GetUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpload not implemented")
+}
+func (UnimplementedGophKeeperServer) CompleteUpload(context.Context, *rpc.CompleteUploadRequest) (*rpc.CompleteUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteUpload not implemented")
+}
+func (UnimplementedGophKeeperServer) AbortUpload(context.Context, *rpc.AbortUploadRequest) (*rpc.AbortUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortUpload not implemented")
+}
+func (UnimplementedGophKeeperServer) ListTrash(context.Context, *rpc.ListTrashRequest) (*rpc.ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedGophKeeperServer) RestoreFromTrash(context.Context, *rpc.RestoreFromTrashRequest) (*rpc.RestoreFromTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFromTrash not implemented")
+}
+func (UnimplementedGophKeeperServer) EmptyTrash(context.Context, *rpc.EmptyTrashRequest) (*rpc.EmptyTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmptyTrash not implemented")
+}
+func (UnimplementedGophKeeperServer) CreateFolder(context.Context, *rpc.CreateFolderRequest) (*rpc.CreateFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
+}
+func (UnimplementedGophKeeperServer) ListFolders(context.Context, *rpc.ListFoldersRequest) (*rpc.ListFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFolders not implemented")
+}
+func (UnimplementedGophKeeperServer) RenameFolder(context.Context, *rpc.RenameFolderRequest) (*rpc.RenameFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFolder not implemented")
+}
+func (UnimplementedGophKeeperServer) MoveFolder(context.Context, *rpc.MoveFolderRequest) (*rpc.MoveFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFolder not implemented")
+}
+func (UnimplementedGophKeeperServer) DeleteFolder(context.Context, *rpc.DeleteFolderRequest) (*rpc.DeleteFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedGophKeeperServer) MoveRecords(context.Context, *rpc.MoveRecordsRequest) (*rpc.MoveRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveRecords not implemented")
+}
+func (UnimplementedGophKeeperServer) ListRecordVersions(context.Context, *rpc.ListRecordVersionsRequest) (*rpc.ListRecordVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecordVersions not implemented")
+}
+func (UnimplementedGophKeeperServer) ViewRecordVersion(context.Context, *rpc.ViewRecordVersionRequest) (*rpc.DataViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ViewRecordVersion not implemented")
+}
+func (UnimplementedGophKeeperServer) RestoreRecordVersion(context.Context, *rpc.RestoreRecordVersionRequest) (*rpc.RestoreRecordVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreRecordVersion not implemented")
+}
+func (UnimplementedGophKeeperServer) SetVersionRetention(context.Context, *rpc.SetVersionRetentionRequest) (*rpc.SetVersionRetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVersionRetention not implemented")
+}
+func (UnimplementedGophKeeperServer) StartKeyRotation(context.Context, *admin.StartKeyRotationRequest) (*admin.StartKeyRotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartKeyRotation not implemented")
+}
+func (UnimplementedGophKeeperServer) GetKeyRotation(context.Context, *admin.GetKeyRotationRequest) (*admin.GetKeyRotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyRotation not implemented")
+}
+func (UnimplementedGophKeeperServer) ReconcileObjects(context.Context, *admin.ReconcileObjectsRequest) (*admin.ReconcileObjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconcileObjects not implemented")
+}
+func (UnimplementedGophKeeperServer) mustEmbedUnimplementedGophKeeperServer() {}
+func (UnimplementedGophKeeperServer) testEmbeddedByValue()                    {}
+
+// UnsafeGophKeeperServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GophKeeperServer will
+// result in compilation errors.
+type UnsafeGophKeeperServer interface {
+	mustEmbedUnimplementedGophKeeperServer()
+}
+
+func RegisterGophKeeperServer(s grpc.ServiceRegistrar, srv GophKeeperServer) {
+	// If the following call pancis, it indicates UnimplementedGophKeeperServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GophKeeper_ServiceDesc, srv)
+}
+
+func _GophKeeper_PreLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.PreLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).PreLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_PreLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).PreLogin(ctx, req.(*user.PreLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).Login(ctx, req.(*user.LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_LoginTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.LoginTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).LoginTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_LoginTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).LoginTOTP(ctx, req.(*user.LoginTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_Refresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).Refresh(ctx, req.(*user.RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).Logout(ctx, req.(*user.LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).ListSessions(ctx, req.(*user.ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).RevokeSession(ctx, req.(*user.RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_RevokeAllOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.RevokeAllOtherSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).RevokeAllOtherSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_RevokeAllOtherSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).RevokeAllOtherSessions(ctx, req.(*user.RevokeAllOtherSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_Signup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.SignupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).Signup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_Signup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).Signup(ctx, req.(*user.SignupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).ChangePassword(ctx, req.(*user.ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.EnrollTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_EnrollTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).EnrollTOTP(ctx, req.(*user.EnrollTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.ConfirmTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_ConfirmTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).ConfirmTOTP(ctx, req.(*user.ConfirmTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(user.DisableTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DisableTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DisableTOTP(ctx, req.(*user.DisableTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).Ping(ctx, req.(*rpc.PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_DataSave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.DataSaveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DataSave(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DataSave_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DataSave(ctx, req.(*rpc.DataSaveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_DataUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.DataUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DataUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DataUpdate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DataUpdate(ctx, req.(*rpc.DataUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_DataDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.DataDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DataDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DataDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DataDelete(ctx, req.(*rpc.DataDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_DataList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.DataListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DataList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DataList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DataList(ctx, req.(*rpc.DataListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).Search(ctx, req.(*rpc.SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_DataView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.DataViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DataView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DataView_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DataView(ctx, req.(*rpc.DataViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_GenerateOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.GenerateOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).GenerateOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_GenerateOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).GenerateOTP(ctx, req.(*rpc.GenerateOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GophKeeperServer).UploadFile(&grpc.GenericServerStream[rpc.UploadFileRequest, rpc.UploadFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GophKeeper_UploadFileServer = grpc.ClientStreamingServer[rpc.UploadFileRequest, rpc.UploadFileResponse]
+
+func _GophKeeper_DownloadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(rpc.DownloadFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GophKeeperServer).DownloadFile(m, &grpc.GenericServerStream[rpc.DownloadFileRequest, rpc.DownloadFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GophKeeper_DownloadFileServer = grpc.ServerStreamingServer[rpc.DownloadFileResponse]
+
+func _GophKeeper_CreateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.CreateUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).CreateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_CreateUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).CreateUpload(ctx, req.(*rpc.CreateUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_UploadChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.UploadChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).UploadChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_UploadChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).UploadChunk(ctx, req.(*rpc.UploadChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_GetUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.GetUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).GetUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_GetUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).GetUpload(ctx, req.(*rpc.GetUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_CompleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.CompleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).CompleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_CompleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).CompleteUpload(ctx, req.(*rpc.CompleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_AbortUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.AbortUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).AbortUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_AbortUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).AbortUpload(ctx, req.(*rpc.AbortUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).ListTrash(ctx, req.(*rpc.ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_RestoreFromTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.RestoreFromTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).RestoreFromTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_RestoreFromTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).RestoreFromTrash(ctx, req.(*rpc.RestoreFromTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_EmptyTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.EmptyTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GophKeeperServer).EmptyTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_EmptyTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).EmptyTrash(ctx, req.(*rpc.EmptyTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GophKeeper_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.CreateFolderRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GophKeeperServer).CreateFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_CreateFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).CreateFolder(ctx, req.(*rpc.CreateFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gophKeeperClient) DataSave(ctx context.Context, in *rpc.DataSaveRequest, opts ...grpc.CallOption) (*rpc.DataSaveResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(rpc.DataSaveResponse)
-	err := c.cc.Invoke(ctx, GophKeeper_DataSave_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _GophKeeper_ListFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.ListFoldersRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GophKeeperServer).ListFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_ListFolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).ListFolders(ctx, req.(*rpc.ListFoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gophKeeperClient) DataDelete(ctx context.Context, in *rpc.DataDeleteRequest, opts ...grpc.CallOption) (*rpc.DataDeleteResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(rpc.DataDeleteResponse)
-	err := c.cc.Invoke(ctx, GophKeeper_DataDelete_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _GophKeeper_RenameFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.RenameFolderRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GophKeeperServer).RenameFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_RenameFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).RenameFolder(ctx, req.(*rpc.RenameFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gophKeeperClient) DataList(ctx context.Context, in *rpc.DataListRequest, opts ...grpc.CallOption) (*rpc.DataListResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(rpc.DataListResponse)
-	err := c.cc.Invoke(ctx, GophKeeper_DataList_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _GophKeeper_MoveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.MoveFolderRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(GophKeeperServer).MoveFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_MoveFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).MoveFolder(ctx, req.(*rpc.MoveFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *gophKeeperClient) DataView(ctx context.Context, in *rpc.DataViewRequest, opts ...grpc.CallOption) (*rpc.DataViewResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(rpc.DataViewResponse)
-	err := c.cc.Invoke(ctx, GophKeeper_DataView_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _GophKeeper_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.DeleteFolderRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// GophKeeperServer is the server API for GophKeeper service.
-// All implementations must embed UnimplementedGophKeeperServer
-// for forward compatibility.
-type GophKeeperServer interface {
-	Login(context.Context, *user.LoginRequest) (*user.LoginResponse, error)
-	Signup(context.Context, *user.SignupRequest) (*user.SignupResponse, error)
-	Ping(context.Context, *rpc.PingRequest) (*rpc.PingResponse, error)
-	DataSave(context.Context, *rpc.DataSaveRequest) (*rpc.DataSaveResponse, error)
-	DataDelete(context.Context, *rpc.DataDeleteRequest) (*rpc.DataDeleteResponse, error)
-	DataList(context.Context, *rpc.DataListRequest) (*rpc.DataListResponse, error)
-	DataView(context.Context, *rpc.DataViewRequest) (*rpc.DataViewResponse, error)
-	mustEmbedUnimplementedGophKeeperServer()
-}
-
-// UnimplementedGophKeeperServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedGophKeeperServer struct{}
-
-func (UnimplementedGophKeeperServer) Login(context.Context, *user.LoginRequest) (*user.LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
-}
-func (UnimplementedGophKeeperServer) Signup(context.Context, *user.SignupRequest) (*user.SignupResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Signup not implemented")
-}
-func (UnimplementedGophKeeperServer) Ping(context.Context, *rpc.PingRequest) (*rpc.PingResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
-}
-func (UnimplementedGophKeeperServer) DataSave(context.Context, *rpc.DataSaveRequest) (*rpc.DataSaveResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DataSave not implemented")
-}
-func (UnimplementedGophKeeperServer) DataDelete(context.Context, *rpc.DataDeleteRequest) (*rpc.DataDeleteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DataDelete not implemented")
-}
-func (UnimplementedGophKeeperServer) DataList(context.Context, *rpc.DataListRequest) (*rpc.DataListResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DataList not implemented")
-}
-func (UnimplementedGophKeeperServer) DataView(context.Context, *rpc.DataViewRequest) (*rpc.DataViewResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DataView not implemented")
-}
-func (UnimplementedGophKeeperServer) mustEmbedUnimplementedGophKeeperServer() {}
-func (UnimplementedGophKeeperServer) testEmbeddedByValue()                    {}
-
-// UnsafeGophKeeperServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to GophKeeperServer will
-// result in compilation errors.
-type UnsafeGophKeeperServer interface {
-	mustEmbedUnimplementedGophKeeperServer()
+	if interceptor == nil {
+		return srv.(GophKeeperServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_DeleteFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).DeleteFolder(ctx, req.(*rpc.DeleteFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterGophKeeperServer(s grpc.ServiceRegistrar, srv GophKeeperServer) {
-	// If the following call pancis, it indicates UnimplementedGophKeeperServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _GophKeeper_MoveRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.MoveRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&GophKeeper_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(GophKeeperServer).MoveRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GophKeeper_MoveRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GophKeeperServer).MoveRecords(ctx, req.(*rpc.MoveRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(user.LoginRequest)
+func _GophKeeper_ListRecordVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.ListRecordVersionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).Login(ctx, in)
+		return srv.(GophKeeperServer).ListRecordVersions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_Login_FullMethodName,
+		FullMethod: GophKeeper_ListRecordVersions_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).Login(ctx, req.(*user.LoginRequest))
+		return srv.(GophKeeperServer).ListRecordVersions(ctx, req.(*rpc.ListRecordVersionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_Signup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(user.SignupRequest)
+func _GophKeeper_ViewRecordVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.ViewRecordVersionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).Signup(ctx, in)
+		return srv.(GophKeeperServer).ViewRecordVersion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_Signup_FullMethodName,
+		FullMethod: GophKeeper_ViewRecordVersion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).Signup(ctx, req.(*user.SignupRequest))
+		return srv.(GophKeeperServer).ViewRecordVersion(ctx, req.(*rpc.ViewRecordVersionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(rpc.PingRequest)
+func _GophKeeper_RestoreRecordVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.RestoreRecordVersionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).Ping(ctx, in)
+		return srv.(GophKeeperServer).RestoreRecordVersion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_Ping_FullMethodName,
+		FullMethod: GophKeeper_RestoreRecordVersion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).Ping(ctx, req.(*rpc.PingRequest))
+		return srv.(GophKeeperServer).RestoreRecordVersion(ctx, req.(*rpc.RestoreRecordVersionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_DataSave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(rpc.DataSaveRequest)
+func _GophKeeper_SetVersionRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(rpc.SetVersionRetentionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).DataSave(ctx, in)
+		return srv.(GophKeeperServer).SetVersionRetention(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_DataSave_FullMethodName,
+		FullMethod: GophKeeper_SetVersionRetention_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).DataSave(ctx, req.(*rpc.DataSaveRequest))
+		return srv.(GophKeeperServer).SetVersionRetention(ctx, req.(*rpc.SetVersionRetentionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_DataDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(rpc.DataDeleteRequest)
+func _GophKeeper_StartKeyRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(admin.StartKeyRotationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).DataDelete(ctx, in)
+		return srv.(GophKeeperServer).StartKeyRotation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_DataDelete_FullMethodName,
+		FullMethod: GophKeeper_StartKeyRotation_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).DataDelete(ctx, req.(*rpc.DataDeleteRequest))
+		return srv.(GophKeeperServer).StartKeyRotation(ctx, req.(*admin.StartKeyRotationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_DataList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(rpc.DataListRequest)
+func _GophKeeper_GetKeyRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(admin.GetKeyRotationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).DataList(ctx, in)
+		return srv.(GophKeeperServer).GetKeyRotation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_DataList_FullMethodName,
+		FullMethod: GophKeeper_GetKeyRotation_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).DataList(ctx, req.(*rpc.DataListRequest))
+		return srv.(GophKeeperServer).GetKeyRotation(ctx, req.(*admin.GetKeyRotationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _GophKeeper_DataView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(rpc.DataViewRequest)
+func _GophKeeper_ReconcileObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(admin.ReconcileObjectsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(GophKeeperServer).DataView(ctx, in)
+		return srv.(GophKeeperServer).ReconcileObjects(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: GophKeeper_DataView_FullMethodName,
+		FullMethod: GophKeeper_ReconcileObjects_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(GophKeeperServer).DataView(ctx, req.(*rpc.DataViewRequest))
+		return srv.(GophKeeperServer).ReconcileObjects(ctx, req.(*admin.ReconcileObjectsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -317,14 +1572,58 @@ var GophKeeper_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "api.proto.v1.GophKeeper",
 	HandlerType: (*GophKeeperServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PreLogin",
+			Handler:    _GophKeeper_PreLogin_Handler,
+		},
 		{
 			MethodName: "Login",
 			Handler:    _GophKeeper_Login_Handler,
 		},
+		{
+			MethodName: "LoginTOTP",
+			Handler:    _GophKeeper_LoginTOTP_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _GophKeeper_Refresh_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _GophKeeper_Logout_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _GophKeeper_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _GophKeeper_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllOtherSessions",
+			Handler:    _GophKeeper_RevokeAllOtherSessions_Handler,
+		},
 		{
 			MethodName: "Signup",
 			Handler:    _GophKeeper_Signup_Handler,
 		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _GophKeeper_ChangePassword_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _GophKeeper_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _GophKeeper_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _GophKeeper_DisableTOTP_Handler,
+		},
 		{
 			MethodName: "Ping",
 			Handler:    _GophKeeper_Ping_Handler,
@@ -333,6 +1632,10 @@ var GophKeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DataSave",
 			Handler:    _GophKeeper_DataSave_Handler,
 		},
+		{
+			MethodName: "DataUpdate",
+			Handler:    _GophKeeper_DataUpdate_Handler,
+		},
 		{
 			MethodName: "DataDelete",
 			Handler:    _GophKeeper_DataDelete_Handler,
@@ -341,11 +1644,114 @@ var GophKeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DataList",
 			Handler:    _GophKeeper_DataList_Handler,
 		},
+		{
+			MethodName: "Search",
+			Handler:    _GophKeeper_Search_Handler,
+		},
 		{
 			MethodName: "DataView",
 			Handler:    _GophKeeper_DataView_Handler,
 		},
+		{
+			MethodName: "GenerateOTP",
+			Handler:    _GophKeeper_GenerateOTP_Handler,
+		},
+		{
+			MethodName: "CreateUpload",
+			Handler:    _GophKeeper_CreateUpload_Handler,
+		},
+		{
+			MethodName: "UploadChunk",
+			Handler:    _GophKeeper_UploadChunk_Handler,
+		},
+		{
+			MethodName: "GetUpload",
+			Handler:    _GophKeeper_GetUpload_Handler,
+		},
+		{
+			MethodName: "CompleteUpload",
+			Handler:    _GophKeeper_CompleteUpload_Handler,
+		},
+		{
+			MethodName: "AbortUpload",
+			Handler:    _GophKeeper_AbortUpload_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _GophKeeper_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreFromTrash",
+			Handler:    _GophKeeper_RestoreFromTrash_Handler,
+		},
+		{
+			MethodName: "EmptyTrash",
+			Handler:    _GophKeeper_EmptyTrash_Handler,
+		},
+		{
+			MethodName: "CreateFolder",
+			Handler:    _GophKeeper_CreateFolder_Handler,
+		},
+		{
+			MethodName: "ListFolders",
+			Handler:    _GophKeeper_ListFolders_Handler,
+		},
+		{
+			MethodName: "RenameFolder",
+			Handler:    _GophKeeper_RenameFolder_Handler,
+		},
+		{
+			MethodName: "MoveFolder",
+			Handler:    _GophKeeper_MoveFolder_Handler,
+		},
+		{
+			MethodName: "DeleteFolder",
+			Handler:    _GophKeeper_DeleteFolder_Handler,
+		},
+		{
+			MethodName: "MoveRecords",
+			Handler:    _GophKeeper_MoveRecords_Handler,
+		},
+		{
+			MethodName: "ListRecordVersions",
+			Handler:    _GophKeeper_ListRecordVersions_Handler,
+		},
+		{
+			MethodName: "ViewRecordVersion",
+			Handler:    _GophKeeper_ViewRecordVersion_Handler,
+		},
+		{
+			MethodName: "RestoreRecordVersion",
+			Handler:    _GophKeeper_RestoreRecordVersion_Handler,
+		},
+		{
+			MethodName: "SetVersionRetention",
+			Handler:    _GophKeeper_SetVersionRetention_Handler,
+		},
+		{
+			MethodName: "StartKeyRotation",
+			Handler:    _GophKeeper_StartKeyRotation_Handler,
+		},
+		{
+			MethodName: "GetKeyRotation",
+			Handler:    _GophKeeper_GetKeyRotation_Handler,
+		},
+		{
+			MethodName: "ReconcileObjects",
+			Handler:    _GophKeeper_ReconcileObjects_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadFile",
+			Handler:       _GophKeeper_UploadFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadFile",
+			Handler:       _GophKeeper_DownloadFile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/v1/service.proto",
 }